@@ -7,16 +7,28 @@ import (
 
 // API baetyl api server
 type API struct {
-	NS      service.NamespaceService
-	Node    service.NodeService
-	Index   service.IndexService
-	Func    service.FunctionService
-	Obj     service.ObjectService
-	PKI     service.PKIService
-	Auth    service.AuthService
-	Prop    service.PropertyService
-	Init    service.InitService
-	License service.LicenseService
+	NS          service.NamespaceService
+	Node        service.NodeService
+	Index       service.IndexService
+	Func        service.FunctionService
+	Obj         service.ObjectService
+	PKI         service.PKIService
+	Auth        service.AuthService
+	Prop        service.PropertyService
+	Init        service.InitService
+	License     service.LicenseService
+	Batch       service.BatchService
+	Callback    service.CallbackService
+	Rollout     service.RolloutService
+	APIKey      service.APIKeyService
+	RBAC        service.RBACService
+	Audit       service.AuditService
+	Command     service.CommandService
+	OTA         service.OTAService
+	NodeGroup   service.NodeGroupService
+	Bundle      service.BundleService
+	Event       service.EventService
+	CertManager service.CertManagerService
 	*service.AppCombinedService
 }
 
@@ -66,6 +78,54 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 	if err != nil {
 		return nil, err
 	}
+	batchService, err := service.NewBatchService(config)
+	if err != nil {
+		return nil, err
+	}
+	callbackService, err := service.NewCallbackService(config)
+	if err != nil {
+		return nil, err
+	}
+	rolloutService, err := service.NewRolloutService(config)
+	if err != nil {
+		return nil, err
+	}
+	eventService, err := service.NewEventService(config)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyService, err := service.NewAPIKeyService(config)
+	if err != nil {
+		return nil, err
+	}
+	rbacService, err := service.NewRBACService(config)
+	if err != nil {
+		return nil, err
+	}
+	auditService, err := service.NewAuditService(config)
+	if err != nil {
+		return nil, err
+	}
+	commandService, err := service.NewCommandService(config)
+	if err != nil {
+		return nil, err
+	}
+	otaService, err := service.NewOTAService(config)
+	if err != nil {
+		return nil, err
+	}
+	nodeGroupService, err := service.NewNodeGroupService(config)
+	if err != nil {
+		return nil, err
+	}
+	bundleService, err := service.NewBundleService(config)
+	if err != nil {
+		return nil, err
+	}
+	certManagerService, err := service.NewCertManagerService(config)
+	if err != nil {
+		return nil, err
+	}
 	return &API{
 		NS:                 namespaceService,
 		Node:               nodeService,
@@ -77,6 +137,18 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 		Prop:               propertyService,
 		Init:               initService,
 		License:            licenseService,
+		Batch:              batchService,
+		Callback:           callbackService,
+		Rollout:            rolloutService,
+		Event:              eventService,
+		APIKey:             apiKeyService,
+		RBAC:               rbacService,
+		Audit:              auditService,
+		Command:            commandService,
+		OTA:                otaService,
+		NodeGroup:          nodeGroupService,
+		Bundle:             bundleService,
+		CertManager:        certManagerService,
 		AppCombinedService: acs,
 	}, nil
 }
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetAPIKey get an api key
+func (api *API) GetAPIKey(c *common.Context) (interface{}, error) {
+	return api.APIKey.Get(c.GetNameFromParam())
+}
+
+// ListAPIKey list the api keys, filtered by the user in query if present
+func (api *API) ListAPIKey(c *common.Context) (interface{}, error) {
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	res, err := api.APIKey.List(c.Query("user"), params)
+	if err != nil {
+		return nil, err
+	}
+	return models.MisData{
+		Count: res.Total,
+		Rows:  res.Items,
+	}, nil
+}
+
+// CreateAPIKey create an api key, the key is only returned in the response
+func (api *API) CreateAPIKey(c *common.Context) (interface{}, error) {
+	key := new(models.APIKey)
+	if err := c.LoadBody(key); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if key.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+	if err := checkNamespaces(key.Namespaces); err != nil {
+		return nil, err
+	}
+	if key.ExpireTime != nil && !key.ExpireTime.After(time.Now()) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "expireTime should be in the future"))
+	}
+	key.Key, key.KeyPrefix, key.KeyHash = "", "", ""
+	return api.APIKey.Create(key)
+}
+
+// DeleteAPIKey delete an api key
+func (api *API) DeleteAPIKey(c *common.Context) (interface{}, error) {
+	n := c.GetNameFromParam()
+	if _, err := api.APIKey.Get(n); err != nil {
+		return nil, err
+	}
+	return nil, api.APIKey.Delete(n)
+}
+
+// IssueToken issue a bearer token for the user
+func (api *API) IssueToken(c *common.Context) (interface{}, error) {
+	req := new(models.TokenRequest)
+	if err := c.LoadBody(req); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err := checkNamespaces(req.Namespaces); err != nil {
+		return nil, err
+	}
+	if req.Expiration < 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "expiration should not be negative"))
+	}
+	return api.APIKey.IssueToken(req)
+}
+
+func checkNamespaces(namespaces []string) error {
+	if len(namespaces) == 0 {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "namespaces is required"))
+	}
+	for _, ns := range namespaces {
+		if ns == "" {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", "namespace should not be empty"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initAPIKeyAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	v1 := router.Group("v1")
+	{
+		apikeys := v1.Group("/apikeys")
+		apikeys.POST("", common.WrapperMis(api.CreateAPIKey))
+		apikeys.GET("", common.WrapperMis(api.ListAPIKey))
+		apikeys.GET("/:name", common.WrapperMis(api.GetAPIKey))
+		apikeys.DELETE("/:name", common.WrapperMis(api.DeleteAPIKey))
+
+		tokens := v1.Group("/tokens")
+		tokens.POST("", common.WrapperMis(api.IssueToken))
+	}
+	return api, router, mockCtl
+}
+
+type misResponse struct {
+	Status int             `json:"status"`
+	Msg    string          `json:"msg"`
+	Data   json.RawMessage `json:"data"`
+}
+
+func doMisRequest(t *testing.T, router *gin.Engine, method, url string, body interface{}) misResponse {
+	var req *http.Request
+	if body != nil {
+		b, err := json.Marshal(body)
+		assert.NoError(t, err)
+		req, _ = http.NewRequest(method, url, bytes.NewReader(b))
+	} else {
+		req, _ = http.NewRequest(method, url, nil)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var res misResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	api, router, mockCtl := initAPIKeyAPI(t)
+	defer mockCtl.Finish()
+	sKey := ms.NewMockAPIKeyService(mockCtl)
+	api.APIKey = sKey
+
+	key := &models.APIKey{Name: "key01", User: "user01", Namespaces: []string{"default"}}
+	sKey.EXPECT().Create(gomock.Any()).DoAndReturn(func(k *models.APIKey) (*models.APIKey, error) {
+		assert.Empty(t, k.KeyHash)
+		res := *k
+		res.Key = "bk_123"
+		return &res, nil
+	})
+	res := doMisRequest(t, router, http.MethodPost, "/v1/apikeys", map[string]interface{}{
+		"name": "key01", "user": "user01", "namespaces": []string{"default"}, "keyHash": "injected",
+	})
+	assert.Equal(t, 0, res.Status)
+	var created models.APIKey
+	assert.NoError(t, json.Unmarshal(res.Data, &created))
+	assert.Equal(t, "bk_123", created.Key)
+
+	past := time.Now().Add(-time.Hour)
+	invalids := []*models.APIKey{
+		{User: "user01", Namespaces: []string{"default"}},
+		{Name: "key01", Namespaces: []string{"default"}},
+		{Name: "key01", User: "user01"},
+		{Name: "key01", User: "user01", Namespaces: []string{""}},
+		{Name: "key01", User: "user01", Namespaces: []string{"default"}, ExpireTime: &past},
+	}
+	for _, v := range invalids {
+		res = doMisRequest(t, router, http.MethodPost, "/v1/apikeys", v)
+		assert.Equal(t, 1, res.Status, v)
+	}
+
+	sKey.EXPECT().Create(gomock.Any()).Return(nil, fmt.Errorf("error"))
+	res = doMisRequest(t, router, http.MethodPost, "/v1/apikeys", key)
+	assert.Equal(t, 1, res.Status)
+}
+
+func TestGetAndListAPIKey(t *testing.T) {
+	api, router, mockCtl := initAPIKeyAPI(t)
+	defer mockCtl.Finish()
+	sKey := ms.NewMockAPIKeyService(mockCtl)
+	api.APIKey = sKey
+
+	sKey.EXPECT().Get("key01").Return(&models.APIKey{Name: "key01"}, nil)
+	res := doMisRequest(t, router, http.MethodGet, "/v1/apikeys/key01", nil)
+	assert.Equal(t, 0, res.Status)
+
+	sKey.EXPECT().List("user01", gomock.Any()).Return(&models.ListView{
+		Total: 1,
+		Items: []models.APIKey{{Name: "key01"}},
+	}, nil)
+	res = doMisRequest(t, router, http.MethodGet, "/v1/apikeys?user=user01", nil)
+	assert.Equal(t, 0, res.Status)
+	var data models.MisData
+	assert.NoError(t, json.Unmarshal(res.Data, &data))
+	assert.Equal(t, 1, data.Count)
+
+	sKey.EXPECT().List("", gomock.Any()).Return(nil, fmt.Errorf("error"))
+	res = doMisRequest(t, router, http.MethodGet, "/v1/apikeys", nil)
+	assert.Equal(t, 1, res.Status)
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	api, router, mockCtl := initAPIKeyAPI(t)
+	defer mockCtl.Finish()
+	sKey := ms.NewMockAPIKeyService(mockCtl)
+	api.APIKey = sKey
+
+	sKey.EXPECT().Get("key01").Return(&models.APIKey{Name: "key01"}, nil)
+	sKey.EXPECT().Delete("key01").Return(nil)
+	res := doMisRequest(t, router, http.MethodDelete, "/v1/apikeys/key01", nil)
+	assert.Equal(t, 0, res.Status)
+
+	sKey.EXPECT().Get("key02").Return(nil, common.Error(common.ErrResourceNotFound))
+	res = doMisRequest(t, router, http.MethodDelete, "/v1/apikeys/key02", nil)
+	assert.Equal(t, 1, res.Status)
+}
+
+func TestIssueToken(t *testing.T) {
+	api, router, mockCtl := initAPIKeyAPI(t)
+	defer mockCtl.Finish()
+	sKey := ms.NewMockAPIKeyService(mockCtl)
+	api.APIKey = sKey
+
+	req := &models.TokenRequest{User: "user01", Namespaces: []string{"default"}, Expiration: 60}
+	sKey.EXPECT().IssueToken(req).Return(&models.TokenView{Token: "token"}, nil)
+	res := doMisRequest(t, router, http.MethodPost, "/v1/tokens", req)
+	assert.Equal(t, 0, res.Status)
+	var token models.TokenView
+	assert.NoError(t, json.Unmarshal(res.Data, &token))
+	assert.Equal(t, "token", token.Token)
+
+	invalids := []*models.TokenRequest{
+		{Namespaces: []string{"default"}},
+		{User: "user01"},
+		{User: "user01", Namespaces: []string{"default"}, Expiration: -1},
+	}
+	for _, v := range invalids {
+		res = doMisRequest(t, router, http.MethodPost, "/v1/tokens", v)
+		assert.Equal(t, 1, res.Status, v)
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 const (
@@ -51,7 +52,9 @@ func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
 	}
 	ns, name := c.GetNamespace(), appView.Name
 
-	err = api.validApplication(ns, appView)
+	// the missing references are reported instead if dry run
+	dryRun := c.IsDryRun()
+	missing, err := api.validApplication(ns, appView, dryRun)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +78,10 @@ func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
 	if baseApp != nil && baseApp.Type != appView.Type {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the type of baseApp is conflicted"))
 	}
+	if dryRun {
+		c.SetDryRunHandled()
+		return api.dryRunApplication(ns, appView, nil, missing)
+	}
 
 	app, configs, err := api.toApplication(appView, nil)
 	if err != nil {
@@ -108,17 +115,38 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 
 	ns, name := c.GetNamespace(), c.GetNameFromParam()
 
-	err = api.validApplication(ns, appView)
+	dryRun := c.IsDryRun()
+	missing, err := api.validApplication(ns, appView, dryRun)
 	if err != nil {
 		return nil, err
 	}
 
+	if appView.Rollout != nil {
+		if err = service.CheckRolloutStrategy(appView.Rollout); err != nil {
+			return nil, err
+		}
+	}
+
 	oldApp, err := api.App.Get(ns, name, "")
 	if err != nil {
 		return nil, err
 	}
 
 	appView.Version = oldApp.Version
+	if dryRun {
+		c.SetDryRunHandled()
+		return api.dryRunApplication(ns, appView, oldApp, missing)
+	}
+	app, err := api.updateApplication(ns, appView, oldApp)
+	if err != nil {
+		return nil, err
+	}
+	return api.toApplicationView(app)
+}
+
+// updateApplication converts and stores the application with the generated configs of the function app,
+// then updates the nodes, the nodes only matched by the old selector are removed
+func (api *API) updateApplication(ns string, appView *models.ApplicationView, oldApp *specV1.Application) (*specV1.Application, error) {
 	app, configs, err := api.toApplication(appView, oldApp)
 	if err != nil {
 		return nil, err
@@ -134,21 +162,24 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	if oldApp != nil && oldApp.Selector != app.Selector {
+	if oldApp.Selector != app.Selector {
 		// delete old nodes
 		if err := api.DeleteNodeAndAppIndex(ns, oldApp); err != nil {
 			return nil, err
 		}
 	}
 
-	// update nodes
-	if err := api.UpdateNodeAndAppIndex(ns, app); err != nil {
+	// update nodes, in waves if rollout is set
+	if appView.Rollout != nil && app.Version != oldApp.Version {
+		if _, err := api.Rollout.Create(ns, app, appView.Rollout); err != nil {
+			return nil, err
+		}
+	} else if err := api.UpdateNodeAndAppIndex(ns, app); err != nil {
 		return nil, err
 	}
 
 	api.cleanGeneratedConfigsOfFunctionApp(configs, oldApp)
-
-	return api.toApplicationView(app)
+	return app, nil
 }
 
 // DeleteApplication delete the application
@@ -181,6 +212,59 @@ func (api *API) DeleteApplication(c *common.Context) (interface{}, error) {
 	return nil, nil
 }
 
+// ListApplicationVersion list the historical versions of the application
+func (api *API) ListApplicationVersion(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.App.Get(ns, name, ""); err != nil {
+		return nil, err
+	}
+	return api.App.ListVersions(ns, name, params)
+}
+
+// RollbackApplication restore the spec of a historical version as the new version of the application,
+// the historical version is checked and stored the same as the update
+func (api *API) RollbackApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	version := c.Query("version")
+	if version == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "version is required"))
+	}
+
+	oldApp, err := api.App.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if oldApp.Version == version {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the version is the current version"))
+	}
+
+	app, err := api.App.GetVersion(ns, name, version)
+	if err != nil {
+		return nil, err
+	}
+	app.Namespace = ns
+	app.Name = name
+	app.Version = oldApp.Version
+	app.CreationTimestamp = oldApp.CreationTimestamp
+
+	appView, err := api.toApplicationView(app)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = api.validApplication(ns, appView, false); err != nil {
+		return nil, err
+	}
+	app, err = api.updateApplication(ns, appView, oldApp)
+	if err != nil {
+		return nil, err
+	}
+	return api.toApplicationView(app)
+}
+
 func (api *API) parseApplication(c *common.Context) (*models.ApplicationView, error) {
 	app := new(models.ApplicationView)
 	app.Name = c.GetNameFromParam()
@@ -194,26 +278,33 @@ func (api *API) parseApplication(c *common.Context) (*models.ApplicationView, er
 	if app.Name == "" {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
 	}
+	if err = validApplicationType(app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
 
+// validApplicationType checks the services and the registries match the type of the application
+func validApplicationType(app *models.ApplicationView) error {
 	if app.Type == common.ContainerApp {
 		for _, v := range app.Services {
 			if v.FunctionConfig != nil || v.Functions != nil {
-				return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "add function info in container app"))
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", "add function info in container app"))
 			}
 		}
 	} else if app.Type == common.FunctionApp {
 		for _, v := range app.Services {
 			if v.FunctionConfig == nil {
-				return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "function config can't be empty in function app"))
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", "function config can't be empty in function app"))
 			}
 		}
 		if len(app.Registries) != 0 {
-			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "registries should be be empty in function app"))
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", "registries should be be empty in function app"))
 		}
 	} else {
-		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "type is invalid"))
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "type is invalid"))
 	}
-	return app, nil
+	return nil
 }
 
 func (api *API) getBaseAppIfSet(c *common.Context) (*specV1.Application, error) {
@@ -255,7 +346,7 @@ func (api *API) parseListOptionsAppendSystemLabel(c *common.Context) *models.Lis
 }
 
 func (api *API) UpdateNodeAndAppIndex(namespace string, app *specV1.Application) error {
-	nodes, err := api.Node.UpdateNodeAppVersion(namespace, app)
+	nodes, err := api.Node.UpdateNodeAppVersion(namespace, app, models.DeployTriggerApp)
 	if err != nil {
 		return err
 	}
@@ -423,35 +514,48 @@ func (api *API) translateSecretsToSecretLikedModels(appView *models.ApplicationV
 	return nil
 }
 
-func (api *API) validApplication(namesapce string, app *models.ApplicationView) error {
+// validApplication checks the configs, secrets, certificates and registries referenced by the application,
+// the references not found are returned instead of the error if dry run, so that all of them are reported
+func (api *API) validApplication(namesapce string, app *models.ApplicationView, dryRun bool) ([]models.DryRunReference, error) {
+	var missing []models.DryRunReference
+	check := func(kind, name string, err error) error {
+		if err == nil {
+			return nil
+		}
+		if e, ok := err.(errors.Coder); dryRun && ok && e.Code() == common.ErrResourceNotFound {
+			missing = append(missing, models.DryRunReference{Kind: kind, Name: name})
+			return nil
+		}
+		return err
+	}
 	for _, v := range app.Volumes {
 		if v.Config != nil {
 			_, err := api.Config.Get(namesapce, v.Config.Name, "")
-			if err != nil {
-				return err
+			if err = check(models.BundleKindConfig, v.Config.Name, err); err != nil {
+				return nil, err
 			}
 		}
 		if v.Secret != nil {
 			_, err := api.Secret.Get(namesapce, v.Secret.Name, "")
-			if err != nil {
-				return err
+			if err = check(models.BundleKindSecret, v.Secret.Name, err); err != nil {
+				return nil, err
 			}
 		}
 		if v.Certificate != nil {
 			_, err := api.Secret.Get(namesapce, v.Certificate.Name, "")
-			if err != nil {
-				return err
+			if err = check(models.BundleKindCertificate, v.Certificate.Name, err); err != nil {
+				return nil, err
 			}
 		}
 	}
 
 	for _, r := range app.Registries {
 		_, err := api.Secret.Get(namesapce, r.Name, "")
-		if err != nil {
-			return err
+		if err = check(models.BundleKindRegistry, r.Name, err); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	return missing, nil
 }
 
 func (api *API) isAppCanDelete(namesapce, name string) (bool, error) {
@@ -153,6 +153,8 @@ func initApplicationAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		configs.DELETE("/:name", mockIM, common.Wrapper(api.DeleteApplication))
 		configs.POST("", mockIM, common.Wrapper(api.CreateApplication))
 		configs.GET("", mockIM, common.Wrapper(api.ListApplication))
+		configs.GET("/:name/versions", mockIM, common.Wrapper(api.ListApplicationVersion))
+		configs.POST("/:name/rollback", mockIM, common.Wrapper(api.RollbackApplication))
 	}
 	return api, router, mockCtl
 }
@@ -496,7 +498,7 @@ func TestCreateContainerApplication(t *testing.T) {
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "eden2", "").Return(eden2, nil).Return(eden2, nil).Times(1)
 	sApp.EXPECT().CreateWithBase(appView.Namespace, gomock.Any(), eden2).Return(mApp, nil).Times(1)
-	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any(), models.DeployTriggerApp).Return(nil, fmt.Errorf("error")).Times(1)
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView)
 	req, _ = http.NewRequest(http.MethodPost, "/v1/apps?base=eden2", bytes.NewReader(body))
@@ -509,7 +511,7 @@ func TestCreateContainerApplication(t *testing.T) {
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "eden2", "").Return(eden2, nil).Return(eden2, nil).Times(1)
 	sApp.EXPECT().CreateWithBase(appView.Namespace, gomock.Any(), eden2).Return(eden2, nil)
-	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any()).Return([]string{}, nil).Times(1)
+	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any(), models.DeployTriggerApp).Return([]string{}, nil).Times(1)
 	sIndex.EXPECT().RefreshNodesIndexByApp(appView.Namespace, gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	sSecret.EXPECT().Get(appView.Namespace, "secret01", "").Return(secret, nil).Times(1)
 	w = httptest.NewRecorder()
@@ -698,7 +700,7 @@ func TestCreateApplicationHasCertificates(t *testing.T) {
 	sSecret.EXPECT().Get(appView.Namespace, "certificate01", "").Return(secret, nil).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
 	sApp.EXPECT().CreateWithBase(appView.Namespace, app, nil).Return(app, nil)
-	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any()).Return([]string{}, nil).Times(1)
+	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any(), models.DeployTriggerApp).Return([]string{}, nil).Times(1)
 	sIndex.EXPECT().RefreshNodesIndexByApp(appView.Namespace, gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	sSecret.EXPECT().Get(appView.Namespace, "secret01", "").Return(secret, nil).Times(1)
 	sSecret.EXPECT().Get(appView.Namespace, "registry01", "").Return(secretRegistry, nil).Times(1)
@@ -762,7 +764,7 @@ func TestUpdateContainerApplication(t *testing.T) {
 	sApp.EXPECT().Update(mApp.Namespace, gomock.Any()).Return(mApp2, nil)
 	sIndex.EXPECT().RefreshNodesIndexByApp(mApp.Namespace, mApp.Name, gomock.Any()).Return(nil).Times(2)
 	sNode.EXPECT().DeleteNodeAppVersion(gomock.Any(), gomock.Any()).Return(nil, nil)
-	sNode.EXPECT().UpdateNodeAppVersion(gomock.Any(), gomock.Any()).Return([]string{}, nil)
+	sNode.EXPECT().UpdateNodeAppVersion(gomock.Any(), gomock.Any(), models.DeployTriggerApp).Return([]string{}, nil)
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mApp)
 	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc", bytes.NewReader(body))
@@ -787,7 +789,7 @@ func TestUpdateContainerApplication(t *testing.T) {
 	// 500
 	sApp.EXPECT().Get(gomock.Any(), "abc", gomock.Any()).Return(nil, nil).AnyTimes()
 	sApp.EXPECT().Update(mApp.Namespace, gomock.Any()).Return(mApp, nil)
-	sNode.EXPECT().UpdateNodeAppVersion(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+	sNode.EXPECT().UpdateNodeAppVersion(gomock.Any(), gomock.Any(), models.DeployTriggerApp).Return(nil, fmt.Errorf("error"))
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mApp)
 	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc", bytes.NewReader(body))
@@ -795,6 +797,197 @@ func TestUpdateContainerApplication(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
 
+func TestUpdateApplicationWithRollout(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	sRollout := ms.NewMockRolloutService(mockCtl)
+	api.Rollout = sRollout
+
+	mApp := getMockContainerApp()
+	mApp.Selector = "label = test"
+	mApp.Version = "1"
+	mApp2 := getMockContainerApp()
+	mApp2.Selector = "label = test"
+	mApp2.Version = "2"
+
+	config := &specV1.Configuration{Name: "agent-conf", Version: "123"}
+	secret := &specV1.Secret{Name: "secret01", Version: "123"}
+	sConfig.EXPECT().Get(gomock.Any(), gomock.Any(), "").Return(config, nil).AnyTimes()
+	sSecret.EXPECT().Get(gomock.Any(), secret.Name, gomock.Any()).Return(secret, nil).AnyTimes()
+
+	appView := map[string]interface{}{}
+	data, _ := json.Marshal(mApp)
+	assert.NoError(t, json.Unmarshal(data, &appView))
+
+	// invalid strategy
+	appView["rollout"] = map[string]interface{}{"percent": 200}
+	body, _ := json.Marshal(appView)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/v1/apps/abc", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the nodes are updated by the rollout instead of all at once
+	appView["rollout"] = map[string]interface{}{"percent": 50, "nodes": []string{"n1"}}
+	sApp.EXPECT().Get(mApp.Namespace, "abc", "").Return(mApp, nil)
+	sApp.EXPECT().Update(mApp.Namespace, gomock.Any()).Return(mApp2, nil)
+	sRollout.EXPECT().Create(mApp.Namespace, mApp2, gomock.Any()).DoAndReturn(
+		func(_ string, _ *specV1.Application, strategy *models.RolloutStrategy) (*models.Rollout, error) {
+			assert.Equal(t, 50, strategy.Percent)
+			assert.Equal(t, []string{"n1"}, strategy.Nodes)
+			assert.Equal(t, models.RolloutPolicyPause, strategy.FailurePolicy)
+			return &models.Rollout{Name: "abc-2"}, nil
+		})
+	body, _ = json.Marshal(appView)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sApp.EXPECT().Get(mApp.Namespace, "abc", "").Return(mApp, nil)
+	sApp.EXPECT().Update(mApp.Namespace, gomock.Any()).Return(mApp2, nil)
+	sRollout.EXPECT().Create(mApp.Namespace, mApp2, gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListApplicationVersion(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App: sApp,
+	}
+
+	mApp := getMockContainerApp()
+	versions := &models.ListView{
+		Total:    1,
+		PageNo:   1,
+		PageSize: 10,
+		Items: []models.ApplicationVersion{{
+			Version: "2",
+			Diffs:   []models.ApplicationDiff{{Path: "selector", Old: "a=a", New: "a=b"}},
+		}},
+	}
+	filter := &models.Filter{PageNo: 1, PageSize: 10}
+	sApp.EXPECT().Get(mApp.Namespace, mApp.Name, "").Return(mApp, nil)
+	sApp.EXPECT().ListVersions(mApp.Namespace, mApp.Name, filter).Return(versions, nil)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/abc/versions?pageNo=1&pageSize=10", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"diffs":[{"path":"selector","old":"a=a","new":"a=b"}]`)
+
+	sApp.EXPECT().Get(mApp.Namespace, mApp.Name, "").Return(nil, common.Error(common.ErrResourceNotFound))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/abc/versions", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRollbackApplication(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	sIndex := ms.NewMockIndexService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Index = sIndex
+	api.Node = sNode
+
+	secret := &specV1.Secret{Name: "registry01", Version: "123", Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}}
+	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(secret, nil).AnyTimes()
+
+	mApp := getMockContainerApp()
+	mApp.Version = "3"
+	mApp.Selector = "a=b"
+	history := getMockContainerApp()
+	history.Version = "1"
+	history.Selector = "a=a"
+
+	// version is required
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// current version
+	sApp.EXPECT().Get(mApp.Namespace, mApp.Name, "").Return(mApp, nil)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?version=3", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// version not found
+	sApp.EXPECT().Get(mApp.Namespace, mApp.Name, "").Return(mApp, nil)
+	sApp.EXPECT().GetVersion(mApp.Namespace, mApp.Name, "2").Return(nil, common.Error(common.ErrResourceNotFound))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?version=2", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// config of the version deleted
+	sApp.EXPECT().Get(mApp.Namespace, mApp.Name, "").Return(mApp, nil)
+	sApp.EXPECT().GetVersion(mApp.Namespace, mApp.Name, "1").Return(history, nil)
+	sConfig.EXPECT().Get(mApp.Namespace, "agent-conf", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?version=1", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	config := &specV1.Configuration{Name: "agent-conf", Version: "2"}
+	sConfig.EXPECT().Get(mApp.Namespace, "agent-conf", "").Return(config, nil).AnyTimes()
+
+	rollbacked := getMockContainerApp()
+	rollbacked.Version = "4"
+	rollbacked.Selector = history.Selector
+	sApp.EXPECT().Get(mApp.Namespace, mApp.Name, "").Return(mApp, nil)
+	sApp.EXPECT().GetVersion(mApp.Namespace, mApp.Name, "1").Return(history, nil)
+	sApp.EXPECT().Update(mApp.Namespace, gomock.Any()).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "3", app.Version)
+		assert.Equal(t, "a=a", app.Selector)
+		return rollbacked, nil
+	})
+	sNode.EXPECT().DeleteNodeAppVersion(mApp.Namespace, mApp).Return(nil, nil)
+	sIndex.EXPECT().RefreshNodesIndexByApp(mApp.Namespace, mApp.Name, []string{}).Return(nil)
+	sNode.EXPECT().UpdateNodeAppVersion(mApp.Namespace, rollbacked, models.DeployTriggerApp).Return([]string{"node01"}, nil)
+	sIndex.EXPECT().RefreshNodesIndexByApp(mApp.Namespace, mApp.Name, []string{"node01"}).Return(nil)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?version=1", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"version":"4"`)
+
+	// update nodes failed
+	sApp.EXPECT().Get(mApp.Namespace, mApp.Name, "").Return(mApp, nil)
+	sApp.EXPECT().GetVersion(mApp.Namespace, mApp.Name, "2").Return(getMockContainerApp(), nil)
+	sApp.EXPECT().Update(mApp.Namespace, gomock.Any()).Return(mApp, nil)
+	sNode.EXPECT().UpdateNodeAppVersion(mApp.Namespace, mApp, models.DeployTriggerApp).Return(nil, fmt.Errorf("error"))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?version=2", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
 func TestCreateFunctionApplication(t *testing.T) {
 	api, router, mockCtl := initApplicationAPI(t)
 	defer mockCtl.Finish()
@@ -1066,7 +1259,7 @@ func TestCreateFunctionApplication(t *testing.T) {
 		},
 	}
 	sApp.EXPECT().CreateWithBase(appView.Namespace, gomock.Any(), gomock.Any()).Return(mApp, nil).Times(1)
-	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any(), models.DeployTriggerApp).Return(nil, fmt.Errorf("error")).Times(1)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView)
@@ -1080,7 +1273,7 @@ func TestCreateFunctionApplication(t *testing.T) {
 	sFunc.EXPECT().ListRuntimes().Return(funcs, nil).Times(1)
 	sConfig.EXPECT().Upsert(appView.Namespace, gomock.Any()).Return(config, nil).Times(1)
 	sApp.EXPECT().CreateWithBase(appView.Namespace, gomock.Any(), gomock.Any()).Return(mApp, nil).Times(1)
-	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any()).Return([]string{}, nil).Times(1)
+	sNode.EXPECT().UpdateNodeAppVersion(appView.Namespace, gomock.Any(), models.DeployTriggerApp).Return([]string{}, nil).Times(1)
 	sIndex.EXPECT().RefreshNodesIndexByApp(appView.Namespace, gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	sConfig.EXPECT().Get(appView.Namespace, gomock.Any(), "").Return(config, nil).AnyTimes()
 
@@ -1482,7 +1675,7 @@ func TestUpdateFunctionApplication(t *testing.T) {
 	sConfig.EXPECT().Upsert(namespace, gomock.Any()).Return(config2extra, nil).Times(1)
 	sConfig.EXPECT().Upsert(namespace, gomock.Any()).Return(config3, nil).Times(1)
 	sApp.EXPECT().Update(namespace, gomock.Any()).Return(newApp, nil).Times(1)
-	sNode.EXPECT().UpdateNodeAppVersion(namespace, gomock.Any()).Return([]string{}, nil).Times(1)
+	sNode.EXPECT().UpdateNodeAppVersion(namespace, gomock.Any(), models.DeployTriggerApp).Return([]string{}, nil).Times(1)
 	sIndex.EXPECT().RefreshNodesIndexByApp(namespace, gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	sConfig.EXPECT().Delete(namespace, gomock.Any()).Return(nil).Times(1)
 	sConfig.EXPECT().Get(namespace, "baetyl-function-config-app-service-2", "").Return(config2, nil).Times(1)
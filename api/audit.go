@@ -0,0 +1,32 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ListAudit list the audit logs of the namespace
+func (api *API) ListAudit(c *common.Context) (interface{}, error) {
+	params := &models.AuditFilter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	params.Namespace = c.GetNamespace()
+	return api.Audit.List(params)
+}
+
+// ListAuditMis list the audit logs of all namespaces, filtered by the namespace in query if present
+func (api *API) ListAuditMis(c *common.Context) (interface{}, error) {
+	params := &models.AuditFilter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	res, err := api.Audit.List(params)
+	if err != nil {
+		return nil, err
+	}
+	return models.MisData{
+		Count: res.Total,
+		Rows:  res.Items,
+	}, nil
+}
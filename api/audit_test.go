@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initAuditAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		audits := v1.Group("/audits")
+		audits.GET("", mockIM, common.Wrapper(api.ListAudit))
+	}
+	mis := router.Group("mis")
+	{
+		mis.GET("/audits", common.WrapperMis(api.ListAuditMis))
+	}
+	return api, router, mockCtl
+}
+
+func TestListAudit(t *testing.T) {
+	api, router, mockCtl := initAuditAPI(t)
+	defer mockCtl.Finish()
+	sAudit := ms.NewMockAuditService(mockCtl)
+	api.Audit = sAudit
+
+	// the namespace in query is ignored
+	sAudit.EXPECT().List(gomock.Any()).DoAndReturn(func(f *models.AuditFilter) (*models.ListView, error) {
+		assert.Equal(t, "default", f.Namespace)
+		assert.Equal(t, "user01", f.User)
+		assert.Equal(t, models.AuditDelete, f.Operation)
+		assert.Equal(t, int64(100), f.Start)
+		return &models.ListView{Total: 1, Items: []models.AuditLog{{Id: 1}}}, nil
+	})
+	req, _ := http.NewRequest(http.MethodGet, "/v1/audits?namespace=other&user=user01&operation=delete&start=100", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/audits?start=abc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListAuditMis(t *testing.T) {
+	api, router, mockCtl := initAuditAPI(t)
+	defer mockCtl.Finish()
+	sAudit := ms.NewMockAuditService(mockCtl)
+	api.Audit = sAudit
+
+	sAudit.EXPECT().List(gomock.Any()).DoAndReturn(func(f *models.AuditFilter) (*models.ListView, error) {
+		assert.Equal(t, "other", f.Namespace)
+		return &models.ListView{Total: 2, Items: []models.AuditLog{{Id: 1}, {Id: 2}}}, nil
+	})
+	res := doMisRequest(t, router, http.MethodGet, "/mis/audits?namespace=other", nil)
+	assert.Equal(t, 0, res.Status)
+	assert.Contains(t, string(res.Data), `"count":2`)
+
+	sAudit.EXPECT().List(gomock.Any()).Return(nil, common.Error(common.ErrDatabase))
+	res = doMisRequest(t, router, http.MethodGet, "/mis/audits", nil)
+	assert.Equal(t, 1, res.Status)
+}
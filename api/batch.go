@@ -0,0 +1,202 @@
+package api
+
+import (
+	"path"
+	"strconv"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	fingerprintAll = common.FingerprintSN | common.FingerprintInput | common.FingerprintHostName |
+		common.FingerprintBootID | common.FingerprintSystemUUID | common.FingerprintMachineID
+	securityKeyLength = 16
+)
+
+// GetBatch get a batch
+func (api *API) GetBatch(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Batch.Get(ns, n)
+}
+
+// ListBatch list batches
+func (api *API) ListBatch(c *common.Context) (interface{}, error) {
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	return api.Batch.List(c.GetNamespace(), params)
+}
+
+// CreateBatch create a batch, only the security types supported by activation are allowed
+func (api *API) CreateBatch(c *common.Context) (interface{}, error) {
+	batch, err := api.parseAndCheckBatch(c)
+	if err != nil {
+		return nil, err
+	}
+	if batch.SecurityType != common.None && batch.SecurityType != common.Token {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "security type ("+string(batch.SecurityType)+") is not supported by activation"))
+	}
+	if batch.SecurityType == common.Token && batch.SecurityKey == "" {
+		batch.SecurityKey = common.RandString(securityKeyLength)
+	}
+	return api.Batch.Create(batch)
+}
+
+// UpdateBatch update the batch, security type and key can't be changed
+func (api *API) UpdateBatch(c *common.Context) (interface{}, error) {
+	batch, err := api.parseAndCheckBatch(c)
+	if err != nil {
+		return nil, err
+	}
+	old, err := api.Batch.Get(batch.Namespace, batch.Name)
+	if err != nil {
+		return nil, err
+	}
+	batch.SecurityType = old.SecurityType
+	batch.SecurityKey = old.SecurityKey
+	batch.EnableWhitelist = old.EnableWhitelist
+	return api.Batch.Update(batch)
+}
+
+// DeleteBatch delete the batch
+func (api *API) DeleteBatch(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	if _, err := api.Batch.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return nil, api.Batch.Delete(ns, n)
+}
+
+// ListRecord list records of the batch
+func (api *API) ListRecord(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Batch.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return api.Batch.ListRecord(ns, n, params)
+}
+
+// GetRecord get a record of the batch
+func (api *API) GetRecord(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Batch.GetRecord(ns, n, c.Param("record"))
+}
+
+// CreateRecord create records of the batch with the given fingerprint values
+func (api *API) CreateRecord(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	req := &models.RecordsRequest{}
+	if err := c.LoadBody(req); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if len(req.FingerprintValues) == 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "fingerprintValues is required"))
+	}
+	return api.Batch.CreateRecords(ns, n, req.FingerprintValues)
+}
+
+// GenRecordRandom create records of the batch with random fingerprint values
+func (api *API) GenRecordRandom(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	num, err := strconv.Atoi(c.Query("num"))
+	if err != nil || num <= 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "num should be a positive integer"))
+	}
+	values := make([]string, 0, num)
+	for i := 0; i < num; i++ {
+		values = append(values, common.RandString(securityKeyLength))
+	}
+	return api.Batch.CreateRecords(ns, n, values)
+}
+
+// UpdateRecord update the node name bound to an inactivated record
+func (api *API) UpdateRecord(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	req := &models.Record{}
+	if err := c.LoadBody(req); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	record, err := api.Batch.GetRecord(ns, n, c.Param("record"))
+	if err != nil {
+		return nil, err
+	}
+	if record.Active == common.Activated {
+		return nil, common.Error(common.ErrRegisterRecordActivated)
+	}
+	record.NodeName = req.NodeName
+	return api.Batch.UpdateRecord(record)
+}
+
+// DeleteRecord delete a record of the batch
+func (api *API) DeleteRecord(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return nil, api.Batch.DeleteRecord(ns, n, c.Param("record"))
+}
+
+// BatchNumberCollector collect the number of batches for quota check
+func (api *API) BatchNumberCollector(namespace string) (map[string]int, error) {
+	count, err := api.Batch.Count(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{
+		plugin.QuotaBatch: count,
+	}, nil
+}
+
+func (api *API) parseAndCheckBatch(c *common.Context) (*models.Batch, error) {
+	batch := new(models.Batch)
+	batch.Name = c.GetNameFromParam()
+	err := c.LoadBody(batch)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if name := c.GetNameFromParam(); name != "" {
+		batch.Name = name
+	}
+	if batch.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+	batch.Namespace = c.GetNamespace()
+	batch.Labels = common.AddSystemLabel(batch.Labels, map[string]string{
+		common.LabelBatch: batch.Name,
+	})
+	if batch.QuotaNum <= 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "quotaNum should be positive"))
+	}
+	if batch.EnableWhitelist != common.DisableWhitelist && batch.EnableWhitelist != common.EnableWhitelist {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "enableWhitelist should be 0 or 1"))
+	}
+	switch batch.SecurityType {
+	case "":
+		batch.SecurityType = common.None
+	case common.None, common.Token, common.Cert, common.Dongle:
+	default:
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "securityType is invalid"))
+	}
+	return batch, checkFingerprint(&batch.Fingerprint)
+}
+
+func checkFingerprint(fp *models.Fingerprint) error {
+	if fp.Type == 0 {
+		fp.Type = common.FingerprintSN
+	}
+	if fp.Type&^fingerprintAll != 0 {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "fingerprint type is invalid"))
+	}
+	if fp.Type&common.FingerprintSN != 0 && fp.SnPath == "" {
+		fp.SnPath = path.Join(common.DefaultSNPath, common.DefaultSNFile)
+	}
+	if fp.Type&common.FingerprintInput != 0 && fp.InputField == "" {
+		fp.InputField = common.DefaultInputField
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func initBatchAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		batches := v1.Group("/batches")
+		batches.GET("/:name", mockIM, common.Wrapper(api.GetBatch))
+		batches.PUT("/:name", mockIM, common.Wrapper(api.UpdateBatch))
+		batches.DELETE("/:name", mockIM, common.Wrapper(api.DeleteBatch))
+		batches.POST("", mockIM, common.Wrapper(api.CreateBatch))
+		batches.GET("", mockIM, common.Wrapper(api.ListBatch))
+		batches.GET("/:name/records", mockIM, common.Wrapper(api.ListRecord))
+		batches.POST("/:name/records", mockIM, common.Wrapper(api.CreateRecord))
+		batches.POST("/:name/generate", mockIM, common.Wrapper(api.GenRecordRandom))
+		batches.GET("/:name/records/:record", mockIM, common.Wrapper(api.GetRecord))
+		batches.PUT("/:name/records/:record", mockIM, common.Wrapper(api.UpdateRecord))
+		batches.DELETE("/:name/records/:record", mockIM, common.Wrapper(api.DeleteRecord))
+	}
+	return api, router, mockCtl
+}
+
+func TestGetBatch(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	api.Batch = sBatch
+
+	batch := &models.Batch{Name: "b1", Namespace: "default"}
+	sBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	sBatch.EXPECT().Get("default", "b2").Return(nil, common.Error(common.ErrResourceNotFound))
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/batches/b1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/batches/b2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListBatch(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	api.Batch = sBatch
+
+	sBatch.EXPECT().List("default", gomock.Any()).Return(&models.ListView{Total: 0, Items: []models.Batch{}}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/batches?pageNo=1&pageSize=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCreateBatch(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	api.Batch = sBatch
+
+	sBatch.EXPECT().Create(gomock.Any()).DoAndReturn(func(batch *models.Batch) (*models.Batch, error) {
+		assert.Equal(t, "default", batch.Namespace)
+		assert.Equal(t, 200, batch.QuotaNum)
+		assert.Equal(t, common.Token, batch.SecurityType)
+		assert.Len(t, batch.SecurityKey, securityKeyLength)
+		assert.Equal(t, common.FingerprintSN, batch.Fingerprint.Type)
+		assert.Equal(t, "/var/lib/baetyl/sn/fingerprint.txt", batch.Fingerprint.SnPath)
+		assert.Equal(t, "b1", batch.Labels[common.LabelBatch])
+		return batch, nil
+	})
+	body, _ := json.Marshal(&models.Batch{Name: "b1", SecurityType: common.Token})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// invalid security type
+	body, _ = json.Marshal(&models.Batch{Name: "b1", SecurityType: "abc"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the security types not supported by activation
+	for _, typ := range []common.Security{common.Cert, common.Dongle} {
+		body, _ = json.Marshal(&models.Batch{Name: "b1", SecurityType: typ})
+		req, _ = http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "not supported by activation")
+	}
+
+	// invalid fingerprint type
+	body, _ = json.Marshal(&models.Batch{Name: "b1", Fingerprint: models.Fingerprint{Type: 0x1000}})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// invalid whitelist
+	body, _ = json.Marshal(&models.Batch{Name: "b1", EnableWhitelist: 3})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateBatch(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	api.Batch = sBatch
+
+	old := &models.Batch{
+		Name:            "b1",
+		Namespace:       "default",
+		SecurityType:    common.Token,
+		SecurityKey:     "key",
+		EnableWhitelist: common.EnableWhitelist,
+	}
+	sBatch.EXPECT().Get("default", "b1").Return(old, nil)
+	sBatch.EXPECT().Update(gomock.Any()).DoAndReturn(func(batch *models.Batch) (*models.Batch, error) {
+		assert.Equal(t, common.Token, batch.SecurityType)
+		assert.Equal(t, "key", batch.SecurityKey)
+		assert.Equal(t, common.EnableWhitelist, batch.EnableWhitelist)
+		assert.Equal(t, "desc", batch.Description)
+		return batch, nil
+	})
+	body, _ := json.Marshal(&models.Batch{Description: "desc"})
+	req, _ := http.NewRequest(http.MethodPut, "/v1/batches/b1", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestDeleteBatch(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	api.Batch = sBatch
+
+	sBatch.EXPECT().Get("default", "b1").Return(&models.Batch{}, nil)
+	sBatch.EXPECT().Delete("default", "b1").Return(common.Error(common.ErrRegisterDeleteRecord))
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/batches/b1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sBatch.EXPECT().Get("default", "b1").Return(&models.Batch{}, nil)
+	sBatch.EXPECT().Delete("default", "b1").Return(nil)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/batches/b1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRecord(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	api.Batch = sBatch
+	record := models.NewRecord("default", "b1", "sn")
+
+	// list
+	sBatch.EXPECT().Get("default", "b1").Return(&models.Batch{}, nil)
+	sBatch.EXPECT().ListRecord("default", "b1", gomock.Any()).Return(&models.ListView{Items: []models.Record{record}}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/batches/b1/records", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// get
+	sBatch.EXPECT().GetRecord("default", "b1", record.Name).Return(&record, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/batches/b1/records/"+record.Name, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// create
+	sBatch.EXPECT().CreateRecords("default", "b1", []string{"a", "b"}).Return([]models.Record{}, nil)
+	body, _ := json.Marshal(&models.RecordsRequest{FingerprintValues: []string{"a", "b"}})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches/b1/records", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body, _ = json.Marshal(&models.RecordsRequest{})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches/b1/records", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// generate
+	sBatch.EXPECT().CreateRecords("default", "b1", gomock.Any()).DoAndReturn(func(_, _ string, values []string) ([]models.Record, error) {
+		assert.Len(t, values, 3)
+		return []models.Record{}, nil
+	})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches/b1/generate?num=3", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches/b1/generate?num=x", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// update
+	sBatch.EXPECT().GetRecord("default", "b1", record.Name).Return(&record, nil)
+	sBatch.EXPECT().UpdateRecord(gomock.Any()).DoAndReturn(func(r *models.Record) (*models.Record, error) {
+		assert.Equal(t, "node01", r.NodeName)
+		return r, nil
+	})
+	body, _ = json.Marshal(&models.Record{NodeName: "node01"})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/batches/b1/records/"+record.Name, bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	activated := record
+	activated.Active = common.Activated
+	sBatch.EXPECT().GetRecord("default", "b1", record.Name).Return(&activated, nil)
+	req, _ = http.NewRequest(http.MethodPut, "/v1/batches/b1/records/"+record.Name, bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// delete
+	sBatch.EXPECT().DeleteRecord("default", "b1", record.Name).Return(nil)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/batches/b1/records/"+record.Name, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestBatchNumberCollector(t *testing.T) {
+	api, _, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	api.Batch = sBatch
+
+	sBatch.EXPECT().Count("default").Return(3, nil)
+	res, err := api.BatchNumberCollector("default")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res[plugin.QuotaBatch])
+
+	sBatch.EXPECT().Count("default").Return(0, fmt.Errorf("error"))
+	_, err = api.BatchNumberCollector("default")
+	assert.Error(t, err)
+}
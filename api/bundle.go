@@ -0,0 +1,298 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/jinzhu/copier"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// the header of the passphrase encrypting the secrets of the bundle
+const headerBundlePassphrase = "X-Bundle-Passphrase"
+
+// the max size of the bundle applied, in bytes
+const maxBundleSize = 16 << 20
+
+// ExportBundle export the resources of the namespace as a yaml bundle,
+// the data of the secrets are encrypted if the passphrase header is set
+func (api *API) ExportBundle(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	bundle, err := api.Bundle.Export(ns, c.GetHeader(headerBundlePassphrase))
+	if err != nil {
+		return nil, err
+	}
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-bundle.yml", ns))
+	return data, nil
+}
+
+// ApplyBundle diff the yaml bundle against the namespace and apply the changes in the dependency order,
+// the changes are returned without being applied if dry run, the applying stops at the first failed change
+func (api *API) ApplyBundle(c *common.Context) (interface{}, error) {
+	opts := new(models.BundleApplyOptions)
+	if err := c.Bind(opts); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, maxBundleSize+1))
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if len(data) > maxBundleSize {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the bundle exceeds the max size ("+strconv.Itoa(maxBundleSize)+" bytes)"))
+	}
+	bundle := new(models.Bundle)
+	if err = yaml.Unmarshal(data, bundle); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	ns := c.GetNamespace()
+	plan, err := api.Bundle.Plan(ns, c.GetHeader(headerBundlePassphrase), bundle, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = api.validBundleApps(ns, plan); err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		c.SetDryRunHandled()
+		return plan, nil
+	}
+	// the pruned nodes are deleted after the others are created, so all nodes created count against the quota
+	if created := countBundleCreated(plan, models.BundleKindNode); created > 0 {
+		if err = api.License.CheckQuota(ns, api.bulkNodeNumberCollector(created)); err != nil {
+			return nil, err
+		}
+	}
+	for i := range plan.Changes {
+		change := &plan.Changes[i]
+		if err = api.applyBundleChange(ns, change); err != nil {
+			change.Error = err.Error()
+			break
+		}
+		change.Applied = true
+	}
+	return plan, nil
+}
+
+// validBundleApps validates the applications created or updated by the plan as the application api does, the references
+// are valid if they exist in the namespace or are created or updated by the plan, and are not deleted by the plan
+func (api *API) validBundleApps(ns string, plan *models.BundlePlan) error {
+	refs := map[string]string{}
+	for _, change := range plan.Changes {
+		if change.Kind != models.BundleKindApp {
+			refs[bundleRefKey(change.Kind, change.Name)] = change.Action
+		}
+	}
+	for _, change := range plan.Changes {
+		if change.Kind != models.BundleKindApp || change.Action == models.BundleActionDelete {
+			continue
+		}
+		app := change.Resource.(*specV1.Application)
+		appView := new(models.ApplicationView)
+		if err := copier.Copy(appView, app); err != nil {
+			return err
+		}
+		if err := validApplicationType(appView); err != nil {
+			return err
+		}
+		if err := service.CheckApplicationNames(app); err != nil {
+			return err
+		}
+		missing, err := api.validApplication(ns, appView, true)
+		if err != nil {
+			return err
+		}
+		for _, ref := range missing {
+			if action := refs[bundleRefKey(ref.Kind, ref.Name)]; action == "" || action == models.BundleActionDelete {
+				return common.Error(common.ErrResourceNotFound, common.Field("type", ref.Kind), common.Field("name", ref.Name))
+			}
+		}
+		for _, v := range app.Volumes {
+			var kind, name string
+			if v.Config != nil {
+				kind, name = models.BundleKindConfig, v.Config.Name
+			} else if v.Secret != nil {
+				kind, name = models.BundleKindSecret, v.Secret.Name
+			} else {
+				continue
+			}
+			if refs[bundleRefKey(kind, name)] == models.BundleActionDelete {
+				return common.Error(common.ErrResourceHasBeenUsed, common.Field("type", kind), common.Field("name", name))
+			}
+		}
+	}
+	return nil
+}
+
+// bundleRefKey returns the key of the resource referenced by the applications, the secrets, the registries
+// and the certificates are all referenced as secrets
+func bundleRefKey(kind, name string) string {
+	if kind != models.BundleKindConfig {
+		kind = models.BundleKindSecret
+	}
+	return kind + "/" + name
+}
+
+func countBundleCreated(plan *models.BundlePlan, kind string) int {
+	count := 0
+	for _, change := range plan.Changes {
+		if change.Kind == kind && change.Action == models.BundleActionCreate {
+			count++
+		}
+	}
+	return count
+}
+
+func (api *API) applyBundleChange(ns string, change *models.BundleChange) error {
+	switch change.Kind {
+	case models.BundleKindConfig:
+		return api.applyBundleConfig(ns, change)
+	case models.BundleKindSecret, models.BundleKindRegistry, models.BundleKindCertificate:
+		return api.applyBundleSecret(ns, change)
+	case models.BundleKindNode:
+		return api.applyBundleNode(ns, change)
+	case models.BundleKindApp:
+		return api.applyBundleApp(ns, change)
+	}
+	return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the kind ("+change.Kind+") is not supported"))
+}
+
+func (api *API) applyBundleConfig(ns string, change *models.BundleChange) error {
+	if change.Action == models.BundleActionDelete {
+		appNames, err := api.Index.ListAppIndexByConfig(ns, change.Name)
+		if err != nil {
+			return err
+		}
+		if len(appNames) > 0 {
+			return common.Error(common.ErrResourceHasBeenUsed,
+				common.Field("type", "config"),
+				common.Field("name", change.Name))
+		}
+		return api.Config.Delete(ns, change.Name)
+	}
+	cfg := change.Resource.(*specV1.Configuration)
+	cfg.Namespace = ns
+	if change.Action == models.BundleActionCreate {
+		_, err := api.Config.Create(ns, cfg)
+		return err
+	}
+	old, err := api.Config.Get(ns, cfg.Name, "")
+	if err != nil {
+		return err
+	}
+	cfg.Version = old.Version
+	cfg.UpdateTimestamp = time.Now()
+	res, err := api.Config.Update(ns, cfg)
+	if err != nil {
+		return err
+	}
+	appNames, err := api.Index.ListAppIndexByConfig(ns, res.Name)
+	if err != nil {
+		return err
+	}
+	return api.updateNodeAndApp(ns, res, appNames)
+}
+
+func (api *API) applyBundleSecret(ns string, change *models.BundleChange) error {
+	if change.Action == models.BundleActionDelete {
+		_, err := api.deleteSecret(ns, change.Name, change.Kind)
+		return err
+	}
+	secret := change.Resource.(*specV1.Secret)
+	secret.Namespace = ns
+	if change.Action == models.BundleActionCreate {
+		_, err := api.Secret.Create(ns, secret)
+		return err
+	}
+	old, err := api.Secret.Get(ns, secret.Name, "")
+	if err != nil {
+		return err
+	}
+	secret.Version = old.Version
+	secret.UpdateTimestamp = time.Now()
+	res, err := api.Secret.Update(ns, secret)
+	if err != nil {
+		return err
+	}
+	return api.updateAppSecret(ns, res)
+}
+
+func (api *API) applyBundleNode(ns string, change *models.BundleChange) error {
+	if change.Action == models.BundleActionDelete {
+		return api.deleteNode(ns, change.Name)
+	}
+	node := change.Resource.(*specV1.Node)
+	node.Namespace = ns
+	if change.Action == models.BundleActionCreate {
+		_, err := api.createNode(node)
+		return err
+	}
+	old, err := api.Node.Get(ns, node.Name)
+	if err != nil {
+		return err
+	}
+	node.Labels = common.AddSystemLabel(node.Labels, map[string]string{
+		common.LabelNodeName: node.Name,
+	})
+	node.Version = old.Version
+	_, err = api.Node.Update(ns, node)
+	return err
+}
+
+func (api *API) applyBundleApp(ns string, change *models.BundleChange) error {
+	if change.Action == models.BundleActionDelete {
+		app, err := api.App.Get(ns, change.Name, "")
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				return nil
+			}
+			return err
+		}
+		if canDelete, err := api.isAppCanDelete(ns, change.Name); err != nil {
+			return err
+		} else if !canDelete {
+			return common.Error(common.ErrAppReferencedByNode, common.Field("name", change.Name))
+		}
+		if err = api.App.Delete(ns, change.Name, ""); err != nil {
+			return err
+		}
+		return api.DeleteNodeAndAppIndex(ns, app)
+	}
+	app := change.Resource.(*specV1.Application)
+	app.Namespace = ns
+	if change.Action == models.BundleActionCreate {
+		res, err := api.App.Create(ns, app)
+		if err != nil {
+			return err
+		}
+		return api.UpdateNodeAndAppIndex(ns, res)
+	}
+	old, err := api.App.Get(ns, app.Name, "")
+	if err != nil {
+		return err
+	}
+	app.Version = old.Version
+	res, err := api.App.Update(ns, app)
+	if err != nil {
+		return err
+	}
+	if old.Selector != res.Selector {
+		if err = api.DeleteNodeAndAppIndex(ns, old); err != nil {
+			return err
+		}
+	}
+	return api.UpdateNodeAndAppIndex(ns, res)
+}
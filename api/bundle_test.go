@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initBundleAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		bundle := v1.Group("/bundle")
+		bundle.GET("", mockIM, common.WrapperRaw(api.ExportBundle))
+		bundle.POST("/apply", mockIM, common.Wrapper(api.ApplyBundle))
+	}
+	return api, router, mockCtl
+}
+
+func TestExportBundle(t *testing.T) {
+	api, router, mockCtl := initBundleAPI(t)
+	defer mockCtl.Finish()
+	sBundle := ms.NewMockBundleService(mockCtl)
+	api.Bundle = sBundle
+
+	sBundle.EXPECT().Export("default", "passphrase").Return(&models.Bundle{
+		Version:   models.BundleVersion,
+		Namespace: "default",
+		Configs:   []specV1.Configuration{{Name: "c1", Data: map[string]string{"a": "a"}}},
+	}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/bundle", nil)
+	req.Header.Set(headerBundlePassphrase, "passphrase")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "attachment; filename=default-bundle.yml", w.Header().Get("Content-Disposition"))
+	var bundle models.Bundle
+	assert.NoError(t, yaml.Unmarshal(w.Body.Bytes(), &bundle))
+	assert.Equal(t, "c1", bundle.Configs[0].Name)
+
+	sBundle.EXPECT().Export("default", "").Return(nil, fmt.Errorf("error"))
+	req, _ = http.NewRequest(http.MethodGet, "/v1/bundle", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestApplyBundle(t *testing.T) {
+	api, router, mockCtl := initBundleAPI(t)
+	defer mockCtl.Finish()
+	sBundle, sNode, sIndex, sInit := ms.NewMockBundleService(mockCtl), ms.NewMockNodeService(mockCtl), ms.NewMockIndexService(mockCtl), ms.NewMockInitService(mockCtl)
+	sApp, sConfig, sSecret := ms.NewMockApplicationService(mockCtl), ms.NewMockConfigService(mockCtl), ms.NewMockSecretService(mockCtl)
+	api.Bundle, api.Node, api.Index, api.Init = sBundle, sNode, sIndex, sInit
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}
+	sRollout := ms.NewMockRolloutService(mockCtl)
+	api.Rollout = sRollout
+
+	data, _ := yaml.Marshal(&models.Bundle{Version: models.BundleVersion})
+	apply := func(query string) (int, *models.BundlePlan) {
+		req, _ := http.NewRequest(http.MethodPost, "/v1/bundle/apply"+query, bytes.NewReader(data))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		plan := new(models.BundlePlan)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), plan))
+		return w.Code, plan
+	}
+
+	changes := func() []models.BundleChange {
+		return []models.BundleChange{
+			{Kind: models.BundleKindConfig, Name: "c1", Action: models.BundleActionUpdate, Resource: &specV1.Configuration{Name: "c1", Data: map[string]string{"a": "b"}}},
+			{Kind: models.BundleKindSecret, Name: "s1", Action: models.BundleActionCreate, Resource: &specV1.Secret{Name: "s1"}},
+			{Kind: models.BundleKindNode, Name: "n1", Action: models.BundleActionUpdate, Resource: &specV1.Node{Name: "n1", Labels: map[string]string{"a": "a"}}},
+			{Kind: models.BundleKindApp, Name: "app1", Action: models.BundleActionCreate, Resource: &specV1.Application{Name: "app1", Type: common.ContainerApp, Selector: "a=a",
+				Volumes: []specV1.Volume{{Name: "v1", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s1"}}}}}},
+			{Kind: models.BundleKindApp, Name: "app2", Action: models.BundleActionDelete},
+			{Kind: models.BundleKindRegistry, Name: "r1", Action: models.BundleActionDelete},
+		}
+	}
+
+	// the secret referenced by app1 is created by the bundle
+	sSecret.EXPECT().Get("default", "s1", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(2)
+
+	// dry run
+	sBundle.EXPECT().Plan("default", "", gomock.Any(), &models.BundleApplyOptions{DryRun: true, Prune: true}).
+		Return(&models.BundlePlan{DryRun: true, Prune: true, Changes: changes()}, nil)
+	code, plan := apply("?dryRun=true&prune=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, plan.Changes, 6)
+	assert.False(t, plan.Changes[0].Applied)
+
+	// apply
+	sBundle.EXPECT().Plan("default", "", gomock.Any(), &models.BundleApplyOptions{Prune: true}).
+		Return(&models.BundlePlan{Prune: true, Changes: changes()}, nil)
+	sConfig.EXPECT().Get("default", "c1", "").Return(&specV1.Configuration{Name: "c1", Version: "1"}, nil)
+	sConfig.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "1", cfg.Version)
+		cfg.Version = "2"
+		return cfg, nil
+	})
+	sIndex.EXPECT().ListAppIndexByConfig("default", "c1").Return([]string{"app3"}, nil)
+	app3 := &specV1.Application{Name: "app3", Selector: "a=a", Volumes: []specV1.Volume{{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c1", Version: "1"}}}}}
+	sApp.EXPECT().Get("default", "app3", "").Return(app3, nil)
+	sApp.EXPECT().Update("default", app3).Return(app3, nil)
+	sRollout.EXPECT().UpdateNodeAppVersion("default", app3, models.DeployTriggerConfig).Return(nil, nil)
+	sSecret.EXPECT().Create("default", &specV1.Secret{Name: "s1", Namespace: "default"}).Return(&specV1.Secret{Name: "s1"}, nil)
+	sNode.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1", Version: "3"}, nil)
+	sNode.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "3", n.Version)
+		assert.Equal(t, "n1", n.Labels[common.LabelNodeName])
+		return n, nil
+	})
+	app1 := &specV1.Application{Name: "app1", Namespace: "default", Type: common.ContainerApp, Selector: "a=a",
+		Volumes: []specV1.Volume{{Name: "v1", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s1"}}}}}
+	sApp.EXPECT().Create("default", app1).Return(app1, nil)
+	sNode.EXPECT().UpdateNodeAppVersion("default", app1, models.DeployTriggerApp).Return([]string{"n1"}, nil)
+	sIndex.EXPECT().RefreshNodesIndexByApp("default", "app1", []string{"n1"}).Return(nil)
+	app2 := &specV1.Application{Name: "app2", Selector: "b=b"}
+	sApp.EXPECT().Get("default", "app2", "").Return(app2, nil)
+	sApp.EXPECT().Delete("default", "app2", "").Return(nil)
+	sNode.EXPECT().DeleteNodeAppVersion("default", app2).Return(nil, nil)
+	sIndex.EXPECT().RefreshNodesIndexByApp("default", "app2", []string{}).Return(nil)
+	sIndex.EXPECT().ListAppIndexBySecret("default", "r1").Return([]string{"app1"}, nil)
+	code, plan = apply("?prune=true")
+	assert.Equal(t, http.StatusOK, code)
+	for i := 0; i < 5; i++ {
+		assert.True(t, plan.Changes[i].Applied, plan.Changes[i].Name)
+	}
+	assert.False(t, plan.Changes[5].Applied)
+	assert.NotEmpty(t, plan.Changes[5].Error)
+
+	// the nodes created by the bundle exceed the quota, nothing is applied
+	sLicense := ms.NewMockLicenseService(mockCtl)
+	api.License = sLicense
+	sBundle.EXPECT().Plan("default", "", gomock.Any(), &models.BundleApplyOptions{}).Return(&models.BundlePlan{Changes: []models.BundleChange{
+		{Kind: models.BundleKindNode, Name: "n2", Action: models.BundleActionCreate, Resource: &specV1.Node{Name: "n2"}},
+		{Kind: models.BundleKindNode, Name: "n3", Action: models.BundleActionCreate, Resource: &specV1.Node{Name: "n3"}},
+		{Kind: models.BundleKindNode, Name: "n0", Action: models.BundleActionDelete},
+	}}, nil)
+	sLicense.EXPECT().CheckQuota("default", gomock.Any()).DoAndReturn(func(ns string, collector plugin.QuotaCollector) error {
+		sNode.EXPECT().List(ns, gomock.Any()).Return(&models.NodeList{Items: []specV1.Node{{Name: "n0"}}}, nil)
+		counts, err := collector(ns)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, counts[plugin.QuotaNode])
+		return common.Error(common.ErrLicenseQuota, common.Field("name", plugin.QuotaNode), common.Field("limit", 2))
+	})
+	code, _ = apply("")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// the applications are validated before applied
+	invalidApps := []*specV1.Application{
+		{Name: "app4", Type: "unknown"},
+		{Name: "app4", Type: common.ContainerApp, Services: []specV1.Service{{Name: "s"}, {Name: "s"}}},
+		{Name: "app4", Type: common.ContainerApp, Volumes: []specV1.Volume{{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c4"}}}}},
+		{Name: "app4", Type: common.ContainerApp, Volumes: []specV1.Volume{{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c5"}}}}},
+	}
+	sConfig.EXPECT().Get("default", "c4", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	sConfig.EXPECT().Get("default", "c5", "").Return(&specV1.Configuration{Name: "c5"}, nil)
+	for _, app := range invalidApps {
+		sBundle.EXPECT().Plan("default", "", gomock.Any(), &models.BundleApplyOptions{DryRun: true, Prune: true}).Return(&models.BundlePlan{Changes: []models.BundleChange{
+			{Kind: models.BundleKindApp, Name: app.Name, Action: models.BundleActionCreate, Resource: app},
+			{Kind: models.BundleKindConfig, Name: "c5", Action: models.BundleActionDelete},
+		}}, nil)
+		code, _ = apply("?dryRun=true&prune=true")
+		assert.NotEqual(t, http.StatusOK, code, app)
+	}
+
+	// invalid bundles
+	sBundle.EXPECT().Plan("default", "", gomock.Any(), gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid))
+	code, _ = apply("")
+	assert.Equal(t, http.StatusBadRequest, code)
+	data = []byte("version: [")
+	code, _ = apply("")
+	assert.Equal(t, http.StatusBadRequest, code)
+	data = make([]byte, maxBundleSize+1)
+	code, _ = apply("")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
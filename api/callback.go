@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// callbackTemplate matches the template segments of the callback url, which are rendered by the event args
+var callbackTemplate = regexp.MustCompile(`{{[^}]*}}`)
+
+// GetCallback get a callback
+func (api *API) GetCallback(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Callback.Get(ns, n)
+}
+
+// ListCallback list callbacks
+func (api *API) ListCallback(c *common.Context) (interface{}, error) {
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	return api.Callback.List(c.GetNamespace(), params)
+}
+
+// CreateCallback create a callback
+func (api *API) CreateCallback(c *common.Context) (interface{}, error) {
+	callback, err := parseAndCheckCallback(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.Callback.Create(callback)
+}
+
+// UpdateCallback update the callback
+func (api *API) UpdateCallback(c *common.Context) (interface{}, error) {
+	callback, err := parseAndCheckCallback(c)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = api.Callback.Get(callback.Namespace, callback.Name); err != nil {
+		return nil, err
+	}
+	return api.Callback.Update(callback)
+}
+
+// DeleteCallback delete the callback
+func (api *API) DeleteCallback(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	if _, err := api.Callback.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return nil, api.Callback.Delete(ns, n)
+}
+
+// ListCallbackLog list the delivery history of the callback
+func (api *API) ListCallbackLog(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Callback.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return api.Callback.ListLog(ns, n, params)
+}
+
+func parseAndCheckCallback(c *common.Context) (*models.Callback, error) {
+	callback := new(models.Callback)
+	callback.Name = c.GetNameFromParam()
+	err := c.LoadBody(callback)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if name := c.GetNameFromParam(); name != "" {
+		callback.Name = name
+	}
+	if callback.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+	callback.Namespace = c.GetNamespace()
+	callback.Method = strings.ToUpper(callback.Method)
+	switch callback.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "method should be one of GET/POST/PUT/DELETE"))
+	}
+	if _, err = template.New(callback.Name).Parse(callback.Url); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	// the template segments are replaced, so that the url rendered by the event args is validated
+	u, err := url.ParseRequestURI(callbackTemplate.ReplaceAllString(callback.Url, "x"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "url should be an absolute http(s) url"))
+	}
+	return callback, nil
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initCallbackAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		callbacks := v1.Group("/callbacks")
+		callbacks.GET("/:name", mockIM, common.Wrapper(api.GetCallback))
+		callbacks.PUT("/:name", mockIM, common.Wrapper(api.UpdateCallback))
+		callbacks.DELETE("/:name", mockIM, common.Wrapper(api.DeleteCallback))
+		callbacks.POST("", mockIM, common.Wrapper(api.CreateCallback))
+		callbacks.GET("", mockIM, common.Wrapper(api.ListCallback))
+		callbacks.GET("/:name/logs", mockIM, common.Wrapper(api.ListCallbackLog))
+	}
+	return api, router, mockCtl
+}
+
+func TestGetCallback(t *testing.T) {
+	api, router, mockCtl := initCallbackAPI(t)
+	defer mockCtl.Finish()
+	sCallback := ms.NewMockCallbackService(mockCtl)
+	api.Callback = sCallback
+
+	sCallback.EXPECT().Get("default", "cb").Return(&models.Callback{Name: "cb"}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/callbacks/cb", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sCallback.EXPECT().List("default", gomock.Any()).Return(&models.ListView{Items: []models.Callback{}}, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/callbacks", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCreateCallback(t *testing.T) {
+	api, router, mockCtl := initCallbackAPI(t)
+	defer mockCtl.Finish()
+	sCallback := ms.NewMockCallbackService(mockCtl)
+	api.Callback = sCallback
+
+	sCallback.EXPECT().Create(gomock.Any()).DoAndReturn(func(cb *models.Callback) (*models.Callback, error) {
+		assert.Equal(t, "default", cb.Namespace)
+		assert.Equal(t, http.MethodPost, cb.Method)
+		return cb, nil
+	})
+	body, _ := json.Marshal(&models.Callback{Name: "cb", Method: "post", Url: "http://localhost:8080/cb"})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body, _ = json.Marshal(&models.Callback{Name: "cb", Method: "PATCH", Url: "http://localhost:8080/cb"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(&models.Callback{Name: "cb", Method: "GET", Url: "ftp://localhost/cb"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the url is validated with the template segments replaced
+	sCallback.EXPECT().Create(gomock.Any()).Return(&models.Callback{Name: "cb"}, nil)
+	body, _ = json.Marshal(&models.Callback{Name: "cb", Method: "POST", Url: "http://{{.ip}}:8080/cb/{{ .nodeName }}"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body, _ = json.Marshal(&models.Callback{Name: "cb", Method: "POST", Url: "{{.url}}"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(&models.Callback{Name: "cb", Method: "POST", Url: "http://localhost/{{.nodeName"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateAndDeleteCallback(t *testing.T) {
+	api, router, mockCtl := initCallbackAPI(t)
+	defer mockCtl.Finish()
+	sCallback := ms.NewMockCallbackService(mockCtl)
+	api.Callback = sCallback
+
+	sCallback.EXPECT().Get("default", "cb").Return(&models.Callback{Name: "cb"}, nil)
+	sCallback.EXPECT().Update(gomock.Any()).DoAndReturn(func(cb *models.Callback) (*models.Callback, error) {
+		assert.Equal(t, "cb", cb.Name)
+		return cb, nil
+	})
+	body, _ := json.Marshal(&models.Callback{Method: "PUT", Url: "https://localhost/cb"})
+	req, _ := http.NewRequest(http.MethodPut, "/v1/callbacks/cb", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sCallback.EXPECT().Get("default", "cb").Return(&models.Callback{Name: "cb"}, nil)
+	sCallback.EXPECT().Delete("default", "cb").Return(common.Error(common.ErrRegisterDeleteCallback))
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/callbacks/cb", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListCallbackLog(t *testing.T) {
+	api, router, mockCtl := initCallbackAPI(t)
+	defer mockCtl.Finish()
+	sCallback := ms.NewMockCallbackService(mockCtl)
+	api.Callback = sCallback
+
+	sCallback.EXPECT().Get("default", "cb").Return(&models.Callback{Name: "cb"}, nil)
+	sCallback.EXPECT().ListLog("default", "cb", &models.Filter{PageNo: 1, PageSize: 10}).Return(&models.ListView{
+		Total: 1,
+		Items: []models.CallbackLog{{Id: 1, CallbackName: "cb"}},
+	}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/callbacks/cb/logs?pageNo=1&pageSize=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sCallback.EXPECT().Get("default", "cx").Return(nil, common.Error(common.ErrResourceNotFound))
+	req, _ = http.NewRequest(http.MethodGet, "/v1/callbacks/cx/logs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -171,3 +171,24 @@ func wrapCertificateListOption(lo *models.ListOptions) *models.ListOptions {
 	lo.LabelSelector = fmt.Sprintf("%s=%s", specV1.SecretLabel, specV1.SecretCustomCertificate)
 	return lo
 }
+
+// ListExpiringCertificates list the certificates issued by the pki which expire within the duration in query,
+// e.g. within=720h, the renewal window of the certificate manager is used if absent
+func (api *API) ListExpiringCertificates(c *common.Context) (interface{}, error) {
+	var within time.Duration
+	if v := c.Query("within"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "within is an invalid duration"))
+		}
+		within = d
+	}
+	res, err := api.CertManager.ListExpiringCerts(within)
+	if err != nil {
+		return nil, err
+	}
+	return models.MisData{
+		Count: len(res),
+		Rows:  res,
+	}, nil
+}
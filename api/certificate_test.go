@@ -35,6 +35,10 @@ func initCertificateAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		certificate.GET("", mockIM, common.Wrapper(api.ListCertificate))
 		certificate.GET("/:name/apps", mockIM, common.Wrapper(api.GetAppByCertificate))
 	}
+	mis := router.Group("mis")
+	{
+		mis.GET("/certificates/expiring", common.WrapperMis(api.ListExpiringCertificates))
+	}
 	return api, router, mockCtl
 }
 
@@ -701,3 +705,28 @@ func TestGetAppByCertificate(t *testing.T) {
 	router.ServeHTTP(w4, req4)
 	assert.Equal(t, http.StatusOK, w4.Code)
 }
+
+func TestListExpiringCertificates(t *testing.T) {
+	api, router, mockCtl := initCertificateAPI(t)
+	defer mockCtl.Finish()
+	sCertManager := ms.NewMockCertManagerService(mockCtl)
+	api.CertManager = sCertManager
+
+	certs := []models.ExpiringCert{
+		{CertID: "c1", Type: models.CertTypeNode, CommonName: "default.n1", Namespace: "default", NodeName: "n1"},
+		{CertID: "c2", Type: models.CertTypeRoot, CommonName: "root.ca", Expired: true},
+	}
+	sCertManager.EXPECT().ListExpiringCerts(720*time.Hour).Return(certs, nil)
+	res := doMisRequest(t, router, http.MethodGet, "/mis/certificates/expiring?within=720h", nil)
+	assert.Equal(t, 0, res.Status)
+	assert.Contains(t, string(res.Data), `"count":2`)
+	assert.Contains(t, string(res.Data), `"nodeName":"n1"`)
+
+	// the renewal window is used by default
+	sCertManager.EXPECT().ListExpiringCerts(time.Duration(0)).Return(nil, common.Error(common.ErrDatabase))
+	res = doMisRequest(t, router, http.MethodGet, "/mis/certificates/expiring", nil)
+	assert.Equal(t, 1, res.Status)
+
+	res = doMisRequest(t, router, http.MethodGet, "/mis/certificates/expiring?within=a", nil)
+	assert.Equal(t, 1, res.Status)
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// CreateCommand queue the command of the node, which is delivered once the node is connected to a sync link
+func (api *API) CreateCommand(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	cmd := new(models.Command)
+	if err := c.LoadBody(cmd); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Node.Get(ns, n); err != nil {
+		return nil, err
+	}
+	cmd.Namespace, cmd.Node = ns, n
+	return api.Command.Create(cmd)
+}
+
+// GetCommand get the command of the node with its result
+func (api *API) GetCommand(c *common.Context) (interface{}, error) {
+	return api.Command.Get(c.GetNamespace(), c.GetNameFromParam(), c.Param("id"))
+}
+
+// ListCommand list the command history of the node, filtered by the state in query if present
+func (api *API) ListCommand(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.TaskFilter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Node.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return api.Command.List(ns, n, params)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initCommandAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		commands := v1.Group("/nodes/:name/commands")
+		commands.POST("", mockIM, common.Wrapper(api.CreateCommand))
+		commands.GET("", mockIM, common.Wrapper(api.ListCommand))
+		commands.GET("/:id", mockIM, common.Wrapper(api.GetCommand))
+	}
+	return api, router, mockCtl
+}
+
+func TestCreateCommand(t *testing.T) {
+	api, router, mockCtl := initCommandAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sCommand := ms.NewMockCommandService(mockCtl)
+	api.Node, api.Command = sNode, sCommand
+
+	sNode.EXPECT().Get("default", "n0").Return(&specV1.Node{Name: "n0"}, nil)
+	sCommand.EXPECT().Create(gomock.Any()).DoAndReturn(func(cmd *models.Command) (*models.Command, error) {
+		assert.Equal(t, "default", cmd.Namespace)
+		assert.Equal(t, "n0", cmd.Node)
+		assert.Equal(t, models.CommandRestartService, cmd.Type)
+		assert.Equal(t, "app", cmd.Args["service"])
+		cmd.ID, cmd.State = "c0", models.CommandPending
+		return cmd, nil
+	})
+	body, _ := json.Marshal(&models.Command{Type: models.CommandRestartService, Args: map[string]string{"service": "app"}})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/nodes/n0/commands", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.Command
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "c0", res.ID)
+
+	// the node is not found
+	sNode.EXPECT().Get("default", "n1").Return(nil, common.Error(common.ErrResourceNotFound))
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/n1/commands", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/n0/commands", bytes.NewReader([]byte("{")))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetAndListCommand(t *testing.T) {
+	api, router, mockCtl := initCommandAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sCommand := ms.NewMockCommandService(mockCtl)
+	api.Node, api.Command = sNode, sCommand
+
+	sCommand.EXPECT().Get("default", "n0", "c0").Return(&models.Command{ID: "c0", State: models.CommandSucceeded}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/n0/commands/c0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sNode.EXPECT().Get("default", "n0").Return(&specV1.Node{Name: "n0"}, nil)
+	sCommand.EXPECT().List("default", "n0", gomock.Any()).DoAndReturn(func(_, _ string, f *models.TaskFilter) (*models.ListView, error) {
+		assert.Equal(t, models.CommandPending, f.State)
+		assert.Equal(t, 2, f.PageNo)
+		return &models.ListView{Total: 1, Items: []models.Command{{ID: "c0"}}}, nil
+	})
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/n0/commands?state=pending&pageNo=2&pageSize=10", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/n0/commands?pageNo=x", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
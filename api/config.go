@@ -69,6 +69,15 @@ func (api *API) CreateConfig(c *common.Context) (interface{}, error) {
 			common.Field("error", "this name is already in use"))
 	}
 
+	if c.IsDryRun() {
+		c.SetDryRunHandled()
+		view, err := api.toConfigurationView(config)
+		if err != nil {
+			return nil, err
+		}
+		return &models.DryRunResult{Valid: true, Resource: view}, nil
+	}
+
 	config, err = api.Config.Create(ns, config)
 	if err != nil {
 		return nil, err
@@ -90,8 +99,28 @@ func (api *API) UpdateConfig(c *common.Context) (interface{}, error) {
 		return nil, err
 	}
 
+	dryRun := c.IsDryRun()
+	if dryRun {
+		c.SetDryRunHandled()
+	}
 	if models.EqualConfig(res, config) {
-		return api.toConfigurationView(res)
+		view, err := api.toConfigurationView(res)
+		if err != nil || !dryRun {
+			return view, err
+		}
+		return &models.DryRunResult{Valid: true, Resource: view}, nil
+	}
+
+	if dryRun {
+		view, err := api.toConfigurationView(config)
+		if err != nil {
+			return nil, err
+		}
+		appNames, err := api.Index.ListAppIndexByConfig(ns, config.Name)
+		if err != nil {
+			return nil, err
+		}
+		return api.dryRunReferencedBy(ns, view, appNames)
 	}
 
 	config.Version = res.Version
@@ -228,7 +257,7 @@ func (api *API) updateNodeAndApp(namespace string, config *specV1.Configuration,
 		if err != nil {
 			return err
 		}
-		_, err = api.Node.UpdateNodeAppVersion(namespace, app)
+		_, err = api.Rollout.UpdateNodeAppVersion(namespace, app, models.DeployTriggerConfig)
 		if err != nil {
 			return err
 		}
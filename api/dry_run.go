@@ -0,0 +1,148 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// dryRunApplication converts the application without persisting anything,
+// reports the missing references and the changes to the desires of the nodes,
+// the nodes are grouped into the waves of the rollout if the update is rolled out
+func (api *API) dryRunApplication(namespace string, appView *models.ApplicationView, oldApp *specV1.Application, missing []models.DryRunReference) (*models.DryRunResult, error) {
+	app, configs, err := api.toApplication(appView, oldApp)
+	if err != nil {
+		return nil, err
+	}
+	app.Namespace = namespace
+	nodes, desires, err := api.dryRunDesires(namespace, app, oldApp)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.DryRunResult{
+		Valid:    len(missing) == 0,
+		Resource: app,
+		Configs:  configs,
+		Missing:  missing,
+		Nodes:    nodes,
+		Desires:  desires,
+	}
+	if oldApp != nil && appView.Rollout != nil {
+		if res.Waves, err = service.PlanRolloutWaves(nodes, appView.Rollout); err != nil {
+			return nil, err
+		}
+		waveOf := map[string]int{}
+		for i, wave := range res.Waves {
+			for _, node := range wave {
+				waveOf[node] = i + 1
+			}
+		}
+		for i := range res.Desires {
+			if res.Desires[i].Action != models.DesireActionRemove {
+				res.Desires[i].Wave = waveOf[res.Desires[i].Node]
+			}
+		}
+	}
+	return res, nil
+}
+
+// dryRunReferencedBy reports the applications referencing the updated config or secret
+// and the changes to the desires of the nodes which the applications are deployed to
+func (api *API) dryRunReferencedBy(namespace string, resource interface{}, appNames []string) (*models.DryRunResult, error) {
+	res := &models.DryRunResult{Valid: true, Resource: resource}
+	for _, appName := range appNames {
+		app, err := api.App.Get(namespace, appName, "")
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				continue
+			}
+			return nil, err
+		}
+		res.Apps = append(res.Apps, app.Name)
+		nodes, desires, err := api.dryRunDesires(namespace, app, app)
+		if err != nil {
+			return nil, err
+		}
+		res.Nodes = appendUnique(res.Nodes, nodes...)
+		res.Desires = append(res.Desires, desires...)
+	}
+	return res, nil
+}
+
+// dryRunDesires returns the nodes matched by the selector of the application and the changes to their desires,
+// the application is removed from the desires of the nodes only matched by the old selector
+func (api *API) dryRunDesires(namespace string, app, oldApp *specV1.Application) ([]string, []models.DesireChange, error) {
+	var nodes []string
+	var desires []models.DesireChange
+	matched := map[string]bool{}
+	if app.Selector != "" {
+		list, err := api.Node.List(namespace, &models.ListOptions{LabelSelector: app.Selector})
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range list.Items {
+			node := &list.Items[i]
+			matched[node.Name] = true
+			nodes = append(nodes, node.Name)
+			change := models.DesireChange{Node: node.Name, App: app.Name, Action: models.DesireActionAdd}
+			if info, ok := desireAppInfo(node, app); ok {
+				change.Action = models.DesireActionUpdate
+				change.Version = info.Version
+			}
+			desires = append(desires, change)
+		}
+	}
+	if oldApp == nil || oldApp.Selector == "" || oldApp.Selector == app.Selector {
+		return nodes, desires, nil
+	}
+	list, err := api.Node.List(namespace, &models.ListOptions{LabelSelector: oldApp.Selector})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range list.Items {
+		node := &list.Items[i]
+		if matched[node.Name] {
+			continue
+		}
+		if info, ok := desireAppInfo(node, oldApp); ok {
+			desires = append(desires, models.DesireChange{
+				Node:    node.Name,
+				App:     oldApp.Name,
+				Action:  models.DesireActionRemove,
+				Version: info.Version,
+			})
+		}
+	}
+	return nodes, desires, nil
+}
+
+func desireAppInfo(node *specV1.Node, app *specV1.Application) (specV1.AppInfo, bool) {
+	if node.Desire == nil {
+		return specV1.AppInfo{}, false
+	}
+	for _, info := range node.Desire.AppInfos(app.System) {
+		if info.Name == app.Name {
+			return info, true
+		}
+	}
+	return specV1.AppInfo{}, false
+}
+
+func appendUnique(items []string, adds ...string) []string {
+	for _, a := range adds {
+		found := false
+		for _, item := range items {
+			if item == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			items = append(items, a)
+		}
+	}
+	return items
+}
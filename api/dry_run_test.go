@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func genDryRunNodes() *models.NodeList {
+	return &models.NodeList{Items: []specV1.Node{
+		{Name: "n1", Desire: specV1.Desire{"apps": []specV1.AppInfo{{Name: "abc", Version: "1"}}}},
+		{Name: "n2"},
+	}}
+}
+
+func TestDryRunApplication(t *testing.T) {
+	api, router, mockCtl := initApplicationAPI(t)
+	defer mockCtl.Finish()
+	sApp, sConfig, sSecret := ms.NewMockApplicationService(mockCtl), ms.NewMockConfigService(mockCtl), ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+
+	ns := "baetyl-cloud"
+	appView := &models.ApplicationView{
+		Name:     "abc",
+		Type:     common.ContainerApp,
+		Selector: "a=a",
+		Services: []specV1.Service{{Name: "s1", Image: "image"}},
+		Volumes: []models.VolumeView{
+			{Name: "v1", Config: &specV1.ObjectReference{Name: "c1"}},
+			{Name: "v2", Secret: &specV1.ObjectReference{Name: "s1"}},
+		},
+		Registries: []models.RegistryView{{Name: "r1"}},
+	}
+	body, _ := json.Marshal(appView)
+	dryRun := func(method, path string) (int, *models.DryRunResult) {
+		req, _ := http.NewRequest(method, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		res := new(models.DryRunResult)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		return w.Code, res
+	}
+
+	// create, nothing is persisted
+	sApp.EXPECT().Get(ns, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	sConfig.EXPECT().Get(ns, "c1", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	sSecret.EXPECT().Get(ns, "s1", "").Return(&specV1.Secret{Name: "s1"}, nil)
+	sSecret.EXPECT().Get(ns, "r1", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	sNode.EXPECT().List(ns, &models.ListOptions{LabelSelector: "a=a"}).Return(genDryRunNodes(), nil)
+	code, res := dryRun(http.MethodPost, "/v1/apps?dryRun=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, res.Valid)
+	assert.Equal(t, []models.DryRunReference{
+		{Kind: models.BundleKindConfig, Name: "c1"},
+		{Kind: models.BundleKindRegistry, Name: "r1"},
+	}, res.Missing)
+	assert.Equal(t, []string{"n1", "n2"}, res.Nodes)
+	assert.Equal(t, []models.DesireChange{
+		{Node: "n1", App: "abc", Action: models.DesireActionUpdate, Version: "1"},
+		{Node: "n2", App: "abc", Action: models.DesireActionAdd},
+	}, res.Desires)
+
+	// update with a new selector
+	appView.Selector = "b=b"
+	body, _ = json.Marshal(appView)
+	sConfig.EXPECT().Get(ns, "c1", "").Return(&specV1.Configuration{Name: "c1"}, nil)
+	sSecret.EXPECT().Get(ns, gomock.Any(), "").Return(&specV1.Secret{}, nil).Times(2)
+	sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Name: "abc", Version: "1", Selector: "a=a"}, nil)
+	sNode.EXPECT().List(ns, &models.ListOptions{LabelSelector: "b=b"}).Return(&models.NodeList{Items: []specV1.Node{{Name: "n2"}}}, nil)
+	sNode.EXPECT().List(ns, &models.ListOptions{LabelSelector: "a=a"}).Return(genDryRunNodes(), nil)
+	code, res = dryRun(http.MethodPut, "/v1/apps/abc?dryRun=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, res.Valid)
+	assert.Empty(t, res.Missing)
+	assert.Equal(t, []models.DesireChange{
+		{Node: "n2", App: "abc", Action: models.DesireActionAdd},
+		{Node: "n1", App: "abc", Action: models.DesireActionRemove, Version: "1"},
+	}, res.Desires)
+
+	// the update rolled out is previewed in waves
+	appView.Rollout = &models.RolloutStrategy{Percent: 50, Nodes: []string{"n3"}}
+	body, _ = json.Marshal(appView)
+	sConfig.EXPECT().Get(ns, "c1", "").Return(&specV1.Configuration{Name: "c1"}, nil)
+	sSecret.EXPECT().Get(ns, gomock.Any(), "").Return(&specV1.Secret{}, nil).Times(2)
+	sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Name: "abc", Version: "1", Selector: "b=b"}, nil)
+	sNode.EXPECT().List(ns, &models.ListOptions{LabelSelector: "b=b"}).Return(&models.NodeList{Items: []specV1.Node{
+		{Name: "n1", Desire: specV1.Desire{"apps": []specV1.AppInfo{{Name: "abc", Version: "1"}}}},
+		{Name: "n2"}, {Name: "n3"}, {Name: "n4"},
+	}}, nil)
+	code, res = dryRun(http.MethodPut, "/v1/apps/abc?dryRun=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, [][]string{{"n3"}, {"n1", "n2"}, {"n4"}}, res.Waves)
+	assert.Equal(t, []models.DesireChange{
+		{Node: "n1", App: "abc", Action: models.DesireActionUpdate, Version: "1", Wave: 2},
+		{Node: "n2", App: "abc", Action: models.DesireActionAdd, Wave: 2},
+		{Node: "n3", App: "abc", Action: models.DesireActionAdd, Wave: 1},
+		{Node: "n4", App: "abc", Action: models.DesireActionAdd, Wave: 3},
+	}, res.Desires)
+	appView.Rollout = nil
+	body, _ = json.Marshal(appView)
+
+	// the conflicted name is still reported
+	sConfig.EXPECT().Get(ns, "c1", "").Return(&specV1.Configuration{Name: "c1"}, nil)
+	sSecret.EXPECT().Get(ns, gomock.Any(), "").Return(&specV1.Secret{}, nil).Times(2)
+	sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Name: "abc"}, nil)
+	code, _ = dryRun(http.MethodPost, "/v1/apps?dryRun=true")
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestDryRunConfig(t *testing.T) {
+	api, router, mockCtl := initConfigAPI(t)
+	defer mockCtl.Finish()
+	sApp, sConfig := ms.NewMockApplicationService(mockCtl), ms.NewMockConfigService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig}
+	sNode, sIndex := ms.NewMockNodeService(mockCtl), ms.NewMockIndexService(mockCtl)
+	api.Node, api.Index = sNode, sIndex
+
+	view := &models.ConfigurationView{
+		Name: "abc",
+		Data: []models.ConfigDataItem{{Key: "k", Value: map[string]string{"type": ConfigTypeKV, "value": "v2"}}},
+	}
+	body, _ := json.Marshal(view)
+	dryRun := func(method, path string) *models.DryRunResult {
+		req, _ := http.NewRequest(method, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		res := new(models.DryRunResult)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		return res
+	}
+
+	sConfig.EXPECT().Get("default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	res := dryRun(http.MethodPost, "/v1/configs?dryRun=true")
+	assert.True(t, res.Valid)
+	assert.Empty(t, res.Apps)
+
+	sConfig.EXPECT().Get("default", "abc", "").Return(&specV1.Configuration{Name: "abc", Version: "1", Data: map[string]string{"k": "v1"}}, nil)
+	sIndex.EXPECT().ListAppIndexByConfig("default", "abc").Return([]string{"app1", "app2"}, nil)
+	sApp.EXPECT().Get("default", "app1", "").Return(&specV1.Application{Name: "app1", Selector: "a=a"}, nil)
+	sApp.EXPECT().Get("default", "app2", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=a"}).Return(&models.NodeList{Items: []specV1.Node{
+		{Name: "n1", Desire: specV1.Desire{"apps": []specV1.AppInfo{{Name: "app1", Version: "3"}}}},
+	}}, nil)
+	res = dryRun(http.MethodPut, "/v1/configs/abc?dryRun=true")
+	assert.Equal(t, []string{"app1"}, res.Apps)
+	assert.Equal(t, []string{"n1"}, res.Nodes)
+	assert.Equal(t, []models.DesireChange{{Node: "n1", App: "app1", Action: models.DesireActionUpdate, Version: "3"}}, res.Desires)
+
+	// not modified
+	sConfig.EXPECT().Get("default", "abc", "").Return(&specV1.Configuration{Name: "abc", Version: "1", Data: map[string]string{"k": "v2"}}, nil)
+	res = dryRun(http.MethodPut, "/v1/configs/abc?dryRun=true")
+	assert.True(t, res.Valid)
+	assert.Empty(t, res.Apps)
+}
+
+func TestDryRunSecret(t *testing.T) {
+	api, router, mockCtl := initSecretAPI(t)
+	defer mockCtl.Finish()
+	sApp, sSecret := ms.NewMockApplicationService(mockCtl), ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Secret: sSecret}
+	sNode, sIndex := ms.NewMockNodeService(mockCtl), ms.NewMockIndexService(mockCtl)
+	api.Node, api.Index = sNode, sIndex
+
+	body, _ := json.Marshal(&models.SecretView{Name: "abc", Data: map[string]string{"k": "v2"}})
+	dryRun := func(method, path string) *models.DryRunResult {
+		req, _ := http.NewRequest(method, path, bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		res := new(models.DryRunResult)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		return res
+	}
+
+	sSecret.EXPECT().Get("default", "abc", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	res := dryRun(http.MethodPost, "/v1/secrets?dryRun=true")
+	assert.True(t, res.Valid)
+
+	sSecret.EXPECT().Get("default", "abc", "").Return(&specV1.Secret{Name: "abc", Version: "1", Labels: map[string]string{specV1.SecretLabel: specV1.SecretConfig}, Data: map[string][]byte{"k": []byte("v1")}}, nil)
+	sIndex.EXPECT().ListAppIndexBySecret("default", "abc").Return([]string{"app1"}, nil)
+	sApp.EXPECT().Get("default", "app1", "").Return(&specV1.Application{Name: "app1", Selector: "a=a"}, nil)
+	sNode.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=a"}).Return(genDryRunNodes(), nil)
+	res = dryRun(http.MethodPut, "/v1/secrets/abc?dryRun=true")
+	assert.Equal(t, []string{"app1"}, res.Apps)
+	assert.Equal(t, []string{"n1", "n2"}, res.Nodes)
+	assert.Equal(t, []models.DesireChange{
+		{Node: "n1", App: "app1", Action: models.DesireActionAdd},
+		{Node: "n2", App: "app1", Action: models.DesireActionAdd},
+	}, res.Desires)
+}
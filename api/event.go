@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-contrib/sse"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// the interval to check the offline nodes and to keep the stream alive
+var nodeEventCheckInterval = 5 * time.Second
+
+// StreamNodeEvent push the node events of the namespace as server-sent events,
+// the online and offline events are derived from the reports with the same offline duration as the node views.
+// The stream ends once a write fails, e.g. the client is gone or the write timeout of the admin server is exceeded,
+// which is found by the ping at the latest, clients reconnect as usual for server-sent events
+func (api *API) StreamNodeEvent(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	nodeList, err := api.Node.List(ns, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	tracker := newNodeStatusTracker(offlineDuration)
+	now := time.Now().UTC()
+	for idx := range nodeList.Items {
+		view, err := nodeList.Items[idx].View(offlineDuration)
+		if err != nil {
+			return nil, err
+		}
+		tracker.seed(view, now)
+	}
+
+	ch, err := api.Event.SubscribeNodeEvent(ns)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := api.Event.UnsubscribeNodeEvent(ns, ch); err != nil {
+			log.L().Warn("failed to unsubscribe node events", log.Any(c.GetTrace()), log.Error(err))
+		}
+	}()
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	if err = writeEvent(c, "ping", ""); err != nil {
+		return nil, nil
+	}
+
+	ticker := time.NewTicker(nodeEventCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return nil, nil
+		case msg, ok := <-ch:
+			if !ok {
+				return nil, nil
+			}
+			event, err := service.DecodeNodeEvent(msg)
+			if err != nil {
+				log.L().Warn("failed to decode node event", log.Any(c.GetTrace()), log.Error(err))
+				continue
+			}
+			// the commands are only delivered to nodes
+			if event.Type == models.NodeEventCommand {
+				continue
+			}
+			if event.Type == models.NodeEventReport {
+				if online := tracker.report(event.Name, event.Time); online != nil {
+					online.Namespace = ns
+					if err = writeEvent(c, online.Type, online); err != nil {
+						return nil, nil
+					}
+				}
+			}
+			if err = writeEvent(c, event.Type, event); err != nil {
+				return nil, nil
+			}
+		case now := <-ticker.C:
+			for _, offline := range tracker.expire(now.UTC()) {
+				offline.Namespace = ns
+				if err = writeEvent(c, offline.Type, offline); err != nil {
+					return nil, nil
+				}
+			}
+			if err = writeEvent(c, "ping", ""); err != nil {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// writeEvent writes and flushes the server-sent event, the event is encoded ahead
+// since the errors of writing are ignored by the encoder of gin
+func writeEvent(c *common.Context, name string, data interface{}) error {
+	var buf bytes.Buffer
+	if err := sse.Encode(&buf, sse.Event{Event: name, Data: data}); err != nil {
+		return err
+	}
+	if _, err := c.Writer.Write(buf.Bytes()); err != nil {
+		log.L().Debug("failed to write node event", log.Any(c.GetTrace()), log.Error(err))
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// nodeStatusTracker tracks the last report time of nodes to find the online and offline transitions
+type nodeStatusTracker struct {
+	timeout time.Duration
+	reports map[string]time.Time
+	online  map[string]bool
+}
+
+func newNodeStatusTracker(timeout time.Duration) *nodeStatusTracker {
+	return &nodeStatusTracker{
+		timeout: timeout,
+		reports: map[string]time.Time{},
+		online:  map[string]bool{},
+	}
+}
+
+func (t *nodeStatusTracker) seed(view *specV1.NodeView, now time.Time) {
+	if view.Report == nil || view.Report.Time == nil {
+		t.online[view.Name] = false
+		return
+	}
+	t.reports[view.Name] = *view.Report.Time
+	t.online[view.Name] = now.Before(view.Report.Time.Add(t.timeout))
+}
+
+// report returns the online event if the node was offline
+func (t *nodeStatusTracker) report(name string, reportTime time.Time) *models.NodeEvent {
+	t.reports[name] = reportTime
+	if t.online[name] {
+		return nil
+	}
+	t.online[name] = true
+	return &models.NodeEvent{Type: models.NodeEventOnline, Name: name, Time: reportTime}
+}
+
+// expire returns the offline events of the online nodes not reported within the timeout
+func (t *nodeStatusTracker) expire(now time.Time) []*models.NodeEvent {
+	var names []string
+	for name, online := range t.online {
+		if online && !now.Before(t.reports[name].Add(t.timeout)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	events := make([]*models.NodeEvent, 0, len(names))
+	for _, name := range names {
+		t.online[name] = false
+		events = append(events, &models.NodeEvent{Type: models.NodeEventOffline, Name: name, Time: now})
+	}
+	return events
+}
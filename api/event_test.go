@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initEventAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		events := v1.Group("/events")
+		events.GET("/nodes", mockIM, common.WrapperRaw(api.StreamNodeEvent))
+	}
+	return api, router, mockCtl
+}
+
+func TestStreamNodeEvent(t *testing.T) {
+	api, router, mockCtl := initEventAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sEvent := ms.NewMockEventService(mockCtl)
+	api.Node = sNode
+	api.Event = sEvent
+
+	interval := nodeEventCheckInterval
+	nodeEventCheckInterval = 10 * time.Millisecond
+	defer func() { nodeEventCheckInterval = interval }()
+
+	sNode.EXPECT().List("default", gomock.Any()).Return(nil, fmt.Errorf("error"))
+	req, _ := http.NewRequest(http.MethodGet, "/v1/events/nodes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// node01 goes offline since its last report is stale, node02 comes online by a report
+	stale := time.Now().UTC().Add(-time.Hour)
+	nodeList := &models.NodeList{Items: []specV1.Node{
+		{Namespace: "default", Name: "node01", Report: specV1.Report{"time": time.Now().UTC().Add(-offlineDuration + 100*time.Millisecond)}},
+		{Namespace: "default", Name: "node02", Report: specV1.Report{"time": stale}},
+	}}
+	ch := make(chan interface{}, 1)
+	sNode.EXPECT().List("default", gomock.Any()).Return(nodeList, nil)
+	sEvent.EXPECT().SubscribeNodeEvent("default").Return(ch, nil)
+	unsubscribed := make(chan struct{})
+	sEvent.EXPECT().UnsubscribeNodeEvent("default", ch).DoAndReturn(func(_ string, _ chan interface{}) error {
+		close(unsubscribed)
+		return nil
+	})
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/events/nodes", nil)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	report, _ := json.Marshal(&models.NodeEvent{Type: models.NodeEventReport, Namespace: "default", Name: "node02", Time: time.Now().UTC()})
+	ch <- report
+
+	events := map[string]string{}
+	scanner := bufio.NewScanner(resp.Body)
+	var typ string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event:") {
+			typ = line[len("event:"):]
+			continue
+		}
+		if strings.HasPrefix(line, "data:") && typ != "ping" {
+			event := new(models.NodeEvent)
+			assert.NoError(t, json.Unmarshal([]byte(line[len("data:"):]), event))
+			assert.Equal(t, typ, event.Type)
+			assert.Equal(t, "default", event.Namespace)
+			events[event.Type] = event.Name
+		}
+		if len(events) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, map[string]string{
+		models.NodeEventOnline:  "node02",
+		models.NodeEventReport:  "node02",
+		models.NodeEventOffline: "node01",
+	}, events)
+
+	cancel()
+	select {
+	case <-unsubscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("node events are not unsubscribed")
+	}
+}
+
+type failedWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w *failedWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("i/o timeout")
+}
+
+func TestStreamNodeEventWriteFailed(t *testing.T) {
+	api, router, mockCtl := initEventAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sEvent := ms.NewMockEventService(mockCtl)
+	api.Node = sNode
+	api.Event = sEvent
+
+	// the stream ends once the write fails, e.g. the write timeout is exceeded
+	ch := make(chan interface{}, 1)
+	sNode.EXPECT().List("default", gomock.Any()).Return(&models.NodeList{}, nil)
+	sEvent.EXPECT().SubscribeNodeEvent("default").Return(ch, nil)
+	sEvent.EXPECT().UnsubscribeNodeEvent("default", ch).Return(nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/events/nodes", nil)
+	router.ServeHTTP(&failedWriter{httptest.NewRecorder()}, req)
+}
+
+func TestNodeStatusTracker(t *testing.T) {
+	now := time.Now().UTC()
+	tracker := newNodeStatusTracker(time.Minute)
+	tracker.seed(&specV1.NodeView{Name: "node01", Report: &specV1.ReportView{Time: &now}}, now)
+	tracker.seed(&specV1.NodeView{Name: "node02"}, now)
+
+	assert.Nil(t, tracker.report("node01", now))
+	online := tracker.report("node02", now)
+	assert.Equal(t, models.NodeEventOnline, online.Type)
+	assert.Equal(t, "node02", online.Name)
+	assert.Nil(t, tracker.report("node02", now))
+
+	assert.Empty(t, tracker.expire(now.Add(time.Second)))
+	offline := tracker.expire(now.Add(time.Minute))
+	assert.Len(t, offline, 2)
+	assert.Equal(t, "node01", offline[0].Name)
+	assert.Equal(t, "node02", offline[1].Name)
+	assert.Equal(t, models.NodeEventOffline, offline[0].Type)
+	assert.Empty(t, tracker.expire(now.Add(time.Hour)))
+
+	online = tracker.report("node01", now.Add(time.Hour))
+	assert.Equal(t, models.NodeEventOnline, online.Type)
+}
@@ -1,25 +1,38 @@
 package api
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"strconv"
 	"time"
 
+	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 //go:generate mockgen -destination=../mock/api/init.go -package=api github.com/baetyl/baetyl-cloud/v2/api InitAPI
 
 type InitAPI struct {
-	Init service.InitService
-	Auth service.AuthService
+	Init     service.InitService
+	Auth     service.AuthService
+	Node     service.NodeService
+	Index    service.IndexService
+	Batch    service.BatchService
+	Callback service.CallbackService
+	License  service.LicenseService
 }
 
-func NewInitAPI(cfg *config.CloudConfig) (*InitAPI, error) {
+// NewInitAPI NewInitAPI, the callback service is shared with the admin api to dispatch the callbacks by the same workers
+func NewInitAPI(cfg *config.CloudConfig, callbackService service.CallbackService) (*InitAPI, error) {
 	initService, err := service.NewInitService(cfg)
 	if err != nil {
 		return nil, err
@@ -28,9 +41,30 @@ func NewInitAPI(cfg *config.CloudConfig) (*InitAPI, error) {
 	if err != nil {
 		return nil, err
 	}
+	nodeService, err := service.NewNodeService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	indexService, err := service.NewIndexService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	batchService, err := service.NewBatchService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	licenseService, err := service.NewLicenseService(cfg)
+	if err != nil {
+		return nil, err
+	}
 	return &InitAPI{
-		Init: initService,
-		Auth: authService,
+		Init:     initService,
+		Auth:     authService,
+		Node:     nodeService,
+		Index:    indexService,
+		Batch:    batchService,
+		Callback: callbackService,
+		License:  licenseService,
 	}, nil
 }
 
@@ -112,3 +146,202 @@ func CheckAndParseToken(token string, genToken func(map[string]interface{}) (str
 	}
 	return info, nil
 }
+
+// activeSignatureWindow the max difference between the timestamp of the activation signature and the server time
+const activeSignatureWindow = 5 * time.Minute
+
+// Activate activates a device by the batch and its fingerprint value, the node
+// of the record is created if not exist and the init deployment is returned,
+// a record is activated only once
+func (api *InitAPI) Activate(c *common.Context) (interface{}, error) {
+	req := new(models.ActiveRequest)
+	if err := c.LoadBody(req); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	batch, err := api.Batch.Get(req.Namespace, req.BatchName)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkActiveSecurity(batch, req); err != nil {
+		return nil, err
+	}
+	record, err := api.Batch.GetRecordByFingerprint(batch.Namespace, batch.Name, req.FingerprintValue)
+	if err != nil {
+		return nil, err
+	}
+	created := false
+	if record == nil {
+		if batch.EnableWhitelist == common.EnableWhitelist {
+			log.L().Info("fingerprint not in whitelist",
+				log.Any("namespace", batch.Namespace),
+				log.Any("batch", batch.Name),
+				log.Any("fingerprint", req.FingerprintValue))
+			return nil, common.Error(common.ErrRequestAccessDenied)
+		}
+		records, err := api.Batch.CreateRecords(batch.Namespace, batch.Name, []string{req.FingerprintValue})
+		if err != nil {
+			return nil, err
+		}
+		record = &records[0]
+		created = true
+	}
+	if record.Active == common.Activated {
+		log.L().Info("record already activated",
+			log.Any("namespace", batch.Namespace),
+			log.Any("batch", batch.Name),
+			log.Any("record", record.Name))
+		return nil, common.Error(common.ErrRegisterRecordActivated)
+	}
+	if record.NodeName == "" {
+		record.NodeName = record.Name
+	}
+	if err = api.ensureNode(batch, record.NodeName); err != nil {
+		if created {
+			api.deleteCreatedRecord(batch, record)
+		}
+		return nil, err
+	}
+
+	// the record is activated only if no other request has activated it, before the credentials are issued
+	record.Active = common.Activated
+	record.ActiveIP = c.ClientIP()
+	record.ActiveTime = time.Now()
+	if _, err = api.Batch.ActivateRecord(record); err != nil {
+		if e, ok := err.(errors.Coder); created && (!ok || e.Code() != common.ErrRegisterRecordActivated) {
+			api.deleteCreatedRecord(batch, record)
+		}
+		return nil, err
+	}
+	if batch.CallbackName != "" {
+		args := map[string]string{}
+		for k, v := range req.PenetrateData {
+			args[k] = v
+		}
+		args[service.CallbackArgEvent] = service.CallbackEventActivate
+		args[service.CallbackArgNamespace] = batch.Namespace
+		args[service.CallbackArgBatchName] = batch.Name
+		args[service.CallbackArgNodeName] = record.NodeName
+		args[service.CallbackArgFingerprintValue] = record.FingerprintValue
+		args[service.CallbackArgIP] = record.ActiveIP
+		api.Callback.Dispatch(batch.CallbackName, batch.Namespace, args)
+	}
+	return api.Init.GetResource(batch.Namespace, record.NodeName, service.TemplateInitDeploymentYaml, map[string]interface{}{
+		"KubeNodeName": req.KubeNodeName,
+	})
+}
+
+// deleteCreatedRecord removes the record created by the activation request so that the device activates from scratch
+func (api *InitAPI) deleteCreatedRecord(batch *models.Batch, record *models.Record) {
+	if err := api.Batch.DeleteRecord(batch.Namespace, batch.Name, record.Name); err != nil {
+		common.LogDirtyData(err,
+			log.Any("type", common.Batch),
+			log.Any(common.KeyContextNamespace, batch.Namespace),
+			log.Any("batch", batch.Name),
+			log.Any("record", record.Name))
+	}
+}
+
+// ensureNode create the node with the labels of the batch if not exist, the node created is removed if it fails to deploy the system apps
+func (api *InitAPI) ensureNode(batch *models.Batch, nodeName string) error {
+	_, err := api.Node.Get(batch.Namespace, nodeName)
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return err
+	}
+	if err = api.License.CheckQuota(batch.Namespace, api.nodeNumberCollector); err != nil {
+		return err
+	}
+	labels := map[string]string{}
+	for k, v := range batch.Labels {
+		labels[k] = v
+	}
+	node := &specV1.Node{
+		Name:      nodeName,
+		Namespace: batch.Namespace,
+		Labels: common.AddSystemLabel(labels, map[string]string{
+			common.LabelNodeName: nodeName,
+		}),
+	}
+	if _, err = api.Node.Create(batch.Namespace, node); err != nil {
+		return err
+	}
+	if err = api.deploySysApps(batch.Namespace, nodeName); err != nil {
+		if e := api.Node.Delete(batch.Namespace, nodeName); e != nil {
+			common.LogDirtyData(e,
+				log.Any("type", common.Node),
+				log.Any(common.KeyContextNamespace, batch.Namespace),
+				log.Any("name", nodeName))
+		}
+		return err
+	}
+	return nil
+}
+
+func (api *InitAPI) deploySysApps(namespace, nodeName string) error {
+	apps, err := api.Init.GenApps(namespace, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		nodes, err := api.Node.UpdateNodeAppVersion(namespace, app, models.DeployTriggerApp)
+		if err != nil {
+			return err
+		}
+		if err = api.Index.RefreshNodesIndexByApp(namespace, app.Name, nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (api *InitAPI) nodeNumberCollector(namespace string) (map[string]int, error) {
+	list, err := api.Node.List(namespace, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{
+		plugin.QuotaNode: len(list.Items),
+	}, nil
+}
+
+// checkActiveSecurity checks the proof of the activation request, a batch
+// with token security expects the signature generated by GenActiveSignature
+// within activeSignatureWindow of the server time
+func checkActiveSecurity(batch *models.Batch, req *models.ActiveRequest) error {
+	switch batch.SecurityType {
+	case "", common.None:
+		return nil
+	case common.Token:
+		if req.SecurityType != common.Token {
+			return common.Error(common.ErrRequestAccessDenied)
+		}
+		signed := time.Unix(req.Timestamp, 0)
+		if d := time.Since(signed); d > activeSignatureWindow || d < -activeSignatureWindow {
+			log.L().Info("activation signature expired",
+				log.Any("namespace", batch.Namespace),
+				log.Any("batch", batch.Name),
+				log.Any("timestamp", req.Timestamp))
+			return common.Error(common.ErrRequestAccessDenied)
+		}
+		expected := GenActiveSignature(batch.SecurityKey, batch.Namespace, batch.Name, req.FingerprintValue, req.Timestamp)
+		if !hmac.Equal([]byte(expected), []byte(req.SecurityValue)) {
+			log.L().Info("activation signature not match",
+				log.Any("namespace", batch.Namespace),
+				log.Any("batch", batch.Name))
+			return common.Error(common.ErrRequestAccessDenied)
+		}
+		return nil
+	default:
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "security type ("+string(batch.SecurityType)+") is not supported by activation"))
+	}
+}
+
+// GenActiveSignature generates the hex encoded HMAC-SHA256 of "namespace/batchName/fingerprintValue/timestamp" with the security key
+func GenActiveSignature(key, namespace, batchName, fingerprintValue string, timestamp int64) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(namespace + "/" + batchName + "/" + fingerprintValue + "/" + strconv.FormatInt(timestamp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
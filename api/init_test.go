@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +18,8 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
@@ -28,13 +32,14 @@ func initInitAPI(t *testing.T) (*InitAPI, *gin.Engine, *gomock.Controller) {
 	{
 		init := v1.Group("/init")
 		init.GET("/:resource", mockIM, common.WrapperRaw(api.GetResource))
+		v1.POST("/activate", common.WrapperRaw(api.Activate))
 	}
 	return api, router, mockCtl
 }
 
 func TestNewInitAPI(t *testing.T) {
 	// bad case
-	_, err := NewInitAPI(&config.CloudConfig{})
+	_, err := NewInitAPI(&config.CloudConfig{}, nil)
 	assert.Error(t, err)
 }
 
@@ -122,3 +127,197 @@ func TestInitAPIImpl_CheckAndParseToken(t *testing.T) {
 	assert.Equal(t, info[service.InfoName], res[service.InfoName].(string))
 	assert.Equal(t, info[service.InfoNamespace], res[service.InfoNamespace].(string))
 }
+
+func TestInitAPI_Activate(t *testing.T) {
+	api, router, mockCtl := initInitAPI(t)
+	defer mockCtl.Finish()
+	mInit := ms.NewMockInitService(mockCtl)
+	mNode := ms.NewMockNodeService(mockCtl)
+	mIndex := ms.NewMockIndexService(mockCtl)
+	mBatch := ms.NewMockBatchService(mockCtl)
+	mCallback := ms.NewMockCallbackService(mockCtl)
+	mLicense := ms.NewMockLicenseService(mockCtl)
+	api.Init, api.Node, api.Index = mInit, mNode, mIndex
+	api.Batch, api.Callback, api.License = mBatch, mCallback, mLicense
+
+	batch := &models.Batch{
+		Name:         "b1",
+		Namespace:    "default",
+		SecurityType: common.Token,
+		SecurityKey:  "key",
+		CallbackName: "cb",
+		Labels:       map[string]string{"a": "b", common.LabelBatch: "b1"},
+	}
+	activate := func(req *models.ActiveRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r, _ := http.NewRequest(http.MethodPost, "/v1/activate", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+	now := time.Now().Unix()
+	req := &models.ActiveRequest{
+		Namespace:        "default",
+		BatchName:        "b1",
+		FingerprintValue: "sn01",
+		SecurityType:     common.Token,
+		SecurityValue:    GenActiveSignature("key", "default", "b1", "sn01", now),
+		Timestamp:        now,
+		PenetrateData:    map[string]string{"x": "y"},
+	}
+
+	// invalid signature
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	w := activate(&models.ActiveRequest{
+		Namespace:        "default",
+		BatchName:        "b1",
+		FingerprintValue: "sn01",
+		SecurityType:     common.Token,
+		SecurityValue:    "bad",
+		Timestamp:        now,
+	})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// the signature is replayed out of the window
+	expired := now - 3600
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	w = activate(&models.ActiveRequest{
+		Namespace:        "default",
+		BatchName:        "b1",
+		FingerprintValue: "sn01",
+		SecurityType:     common.Token,
+		SecurityValue:    GenActiveSignature("key", "default", "b1", "sn01", expired),
+		Timestamp:        expired,
+	})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// the timestamp is not signed
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	w = activate(&models.ActiveRequest{
+		Namespace:        "default",
+		BatchName:        "b1",
+		FingerprintValue: "sn01",
+		SecurityType:     common.Token,
+		SecurityValue:    GenActiveSignature("key", "default", "b1", "sn01", now),
+		Timestamp:        now + 1,
+	})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// new record and node
+	record := models.NewRecord("default", "b1", "sn01")
+	app := &specV1.Application{Name: "baetyl-core"}
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(nil, nil)
+	mBatch.EXPECT().CreateRecords("default", "b1", []string{"sn01"}).Return([]models.Record{record}, nil)
+	mNode.EXPECT().Get("default", record.Name).Return(nil, common.Error(common.ErrResourceNotFound))
+	mLicense.EXPECT().CheckQuota("default", gomock.Any()).Return(nil)
+	mNode.EXPECT().Create("default", gomock.Any()).DoAndReturn(func(_ string, node *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, record.Name, node.Name)
+		assert.Equal(t, "b", node.Labels["a"])
+		assert.Equal(t, "b1", node.Labels[common.LabelBatch])
+		assert.Equal(t, record.Name, node.Labels[common.LabelNodeName])
+		return node, nil
+	})
+	mInit.EXPECT().GenApps("default", record.Name).Return([]*specV1.Application{app}, nil)
+	mNode.EXPECT().UpdateNodeAppVersion("default", app, models.DeployTriggerApp).Return([]string{record.Name}, nil)
+	mIndex.EXPECT().RefreshNodesIndexByApp("default", app.Name, []string{record.Name}).Return(nil)
+	mBatch.EXPECT().ActivateRecord(gomock.Any()).DoAndReturn(func(r *models.Record) (*models.Record, error) {
+		assert.Equal(t, common.Activated, r.Active)
+		assert.Equal(t, record.Name, r.NodeName)
+		return r, nil
+	})
+	mCallback.EXPECT().Dispatch("cb", "default", gomock.Any()).Do(func(_, _ string, args map[string]string) {
+		assert.Equal(t, "y", args["x"])
+		assert.Equal(t, service.CallbackEventActivate, args[service.CallbackArgEvent])
+		assert.Equal(t, record.Name, args[service.CallbackArgNodeName])
+		assert.Equal(t, "sn01", args[service.CallbackArgFingerprintValue])
+	})
+	mInit.EXPECT().GetResource("default", record.Name, service.TemplateInitDeploymentYaml, gomock.Any()).Return([]byte("init"), nil)
+	w = activate(req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "init", w.Body.String())
+
+	// existing record bound to an existing node
+	record.NodeName = "node01"
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(&record, nil)
+	mNode.EXPECT().Get("default", "node01").Return(&specV1.Node{Name: "node01"}, nil)
+	mBatch.EXPECT().ActivateRecord(gomock.Any()).Return(&record, nil)
+	mCallback.EXPECT().Dispatch("cb", "default", gomock.Any())
+	mInit.EXPECT().GetResource("default", "node01", service.TemplateInitDeploymentYaml, gomock.Any()).Return([]byte("init"), nil)
+	w = activate(req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the record activated above never activates again
+	assert.Equal(t, common.Activated, record.Active)
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(&record, nil)
+	w = activate(req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the record created is removed if the node fails to create
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(nil, nil)
+	mBatch.EXPECT().CreateRecords("default", "b1", []string{"sn01"}).Return([]models.Record{models.NewRecord("default", "b1", "sn01")}, nil)
+	mNode.EXPECT().Get("default", gomock.Any()).Return(nil, common.Error(common.ErrResourceNotFound))
+	mLicense.EXPECT().CheckQuota("default", gomock.Any()).Return(common.Error(common.ErrLicenseQuota, common.Field("name", plugin.QuotaNode)))
+	mBatch.EXPECT().DeleteRecord("default", "b1", gomock.Any()).Return(nil)
+	w = activate(req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the node created is removed with the record if the system apps fail to deploy
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(nil, nil)
+	mBatch.EXPECT().CreateRecords("default", "b1", []string{"sn01"}).Return([]models.Record{models.NewRecord("default", "b1", "sn01")}, nil)
+	mNode.EXPECT().Get("default", gomock.Any()).Return(nil, common.Error(common.ErrResourceNotFound))
+	mLicense.EXPECT().CheckQuota("default", gomock.Any()).Return(nil)
+	mNode.EXPECT().Create("default", gomock.Any()).Return(nil, nil)
+	mInit.EXPECT().GenApps("default", gomock.Any()).Return(nil, common.Error(common.ErrDatabase))
+	mNode.EXPECT().Delete("default", gomock.Any()).Return(nil)
+	mBatch.EXPECT().DeleteRecord("default", "b1", gomock.Any()).Return(nil)
+	w = activate(req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the record activated by a concurrent request is neither activated again nor removed
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(nil, nil)
+	mBatch.EXPECT().CreateRecords("default", "b1", []string{"sn01"}).Return([]models.Record{models.NewRecord("default", "b1", "sn01")}, nil)
+	mNode.EXPECT().Get("default", gomock.Any()).Return(&specV1.Node{}, nil)
+	mBatch.EXPECT().ActivateRecord(gomock.Any()).Return(nil, common.Error(common.ErrRegisterRecordActivated))
+	w = activate(req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the record created is removed if it fails to activate
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(nil, nil)
+	mBatch.EXPECT().CreateRecords("default", "b1", []string{"sn01"}).Return([]models.Record{models.NewRecord("default", "b1", "sn01")}, nil)
+	mNode.EXPECT().Get("default", gomock.Any()).Return(&specV1.Node{}, nil)
+	mBatch.EXPECT().ActivateRecord(gomock.Any()).Return(nil, common.Error(common.ErrDatabase))
+	mBatch.EXPECT().DeleteRecord("default", "b1", gomock.Any()).Return(nil)
+	w = activate(req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the existing record is kept
+	record.Active = common.Inactivated
+	mBatch.EXPECT().Get("default", "b1").Return(batch, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(&record, nil)
+	mNode.EXPECT().Get("default", "node01").Return(nil, common.Error(common.ErrDatabase))
+	w = activate(req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// whitelist
+	whitelist := &models.Batch{
+		Name:            "b1",
+		Namespace:       "default",
+		SecurityType:    common.None,
+		EnableWhitelist: common.EnableWhitelist,
+	}
+	mBatch.EXPECT().Get("default", "b1").Return(whitelist, nil)
+	mBatch.EXPECT().GetRecordByFingerprint("default", "b1", "sn01").Return(nil, nil)
+	w = activate(req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// invalid request
+	w = activate(&models.ActiveRequest{Namespace: "default"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
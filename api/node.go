@@ -117,10 +117,6 @@ func (api *API) CreateNode(c *common.Context) (interface{}, error) {
 	ns := c.GetNamespace()
 	n.Namespace = ns
 
-	n.Labels = common.AddSystemLabel(n.Labels, map[string]string{
-		common.LabelNodeName: n.Name,
-	})
-
 	oldNode, err := api.Node.Get(n.Namespace, n.Name)
 	if err != nil {
 		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
@@ -132,31 +128,45 @@ func (api *API) CreateNode(c *common.Context) (interface{}, error) {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
 	}
 
+	node, err := api.createNode(n)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := node.View(offlineDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	view.Desire = nil
+	view.Report = nil
+	return view, nil
+}
+
+// createNode creates the node with the system applications
+func (api *API) createNode(n *v1.Node) (*v1.Node, error) {
+	n.Labels = common.AddSystemLabel(n.Labels, map[string]string{
+		common.LabelNodeName: n.Name,
+	})
+
 	node, err := api.Node.Create(n.Namespace, n)
 	if err != nil {
 		return nil, err
 	}
 
+	// the node created is returned with the error, so that the caller is able to delete it
 	apps, err := api.Init.GenApps(n.Namespace, n.Name)
 	if err != nil {
-		return nil, err
+		return node, err
 	}
 
 	for _, app := range apps {
 		err = api.UpdateNodeAndAppIndex(n.Namespace, app)
 		if err != nil {
-			return nil, errors.Trace(err)
+			return node, errors.Trace(err)
 		}
 	}
-
-	view, err := node.View(offlineDuration)
-	if err != nil {
-		return nil, err
-	}
-
-	view.Desire = nil
-	view.Report = nil
-	return view, nil
+	return node, nil
 }
 
 // UpdateNode update the node
@@ -192,18 +202,22 @@ func (api *API) UpdateNode(c *common.Context) (interface{}, error) {
 
 // DeleteNode delete the node
 func (api *API) DeleteNode(c *common.Context) (interface{}, error) {
-	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return nil, api.deleteNode(c.GetNamespace(), c.GetNameFromParam())
+}
+
+// deleteNode deletes the node and cleans the system applications with their configs and secrets
+func (api *API) deleteNode(ns, n string) error {
 	node, err := api.Node.Get(ns, n)
 	if err != nil {
 		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
-			return nil, nil
+			return nil
 		}
-		return nil, err
+		return err
 	}
 
 	// Delete Node
-	if err := api.Node.Delete(c.GetNamespace(), c.GetNameFromParam()); err != nil {
-		return nil, err
+	if err := api.Node.Delete(ns, n); err != nil {
+		return err
 	}
 
 	sysAppInfos := node.Desire.AppInfos(true)
@@ -275,7 +289,30 @@ func (api *API) DeleteNode(c *common.Context) (interface{}, error) {
 				log.Any("app", ai.Name))
 		}
 	}
-	return nil, nil
+	api.dispatchNodeCallback(ns, node, service.CallbackEventNodeDelete)
+	return nil
+}
+
+// dispatchNodeCallback sends the callback of the batch which the node is activated by
+func (api *API) dispatchNodeCallback(ns string, node *v1.Node, event string) {
+	batchName := node.Labels[common.LabelBatch]
+	if batchName == "" {
+		return
+	}
+	batch, err := api.Batch.Get(ns, batchName)
+	if err != nil {
+		log.L().Warn("failed to get batch of node", log.Any(common.KeyContextNamespace, ns), log.Any("batch", batchName), log.Error(err))
+		return
+	}
+	if batch.CallbackName == "" {
+		return
+	}
+	api.Callback.Dispatch(batch.CallbackName, ns, map[string]string{
+		service.CallbackArgEvent:     event,
+		service.CallbackArgNamespace: ns,
+		service.CallbackArgBatchName: batchName,
+		service.CallbackArgNodeName:  node.Name,
+	})
 }
 
 // GetAppByNode list app
@@ -307,26 +344,44 @@ func (api *API) GetAppByNode(c *common.Context) (interface{}, error) {
 // GenInitCmdFromNode generate install command
 func (api *API) GenInitCmdFromNode(c *common.Context) (interface{}, error) {
 	ns, name := c.GetNamespace(), c.Param("name")
-	_, err := api.Node.Get(ns, name)
+	cmd, err := api.genInitCmd(ns, name, c.Query("mode"))
 	if err != nil {
 		return nil, err
 	}
+	return map[string]string{"cmd": cmd}, nil
+}
+
+func (api *API) genInitCmd(ns, name, mode string) (string, error) {
+	_, err := api.Node.Get(ns, name)
+	if err != nil {
+		return "", err
+	}
 
-	mode := c.Query("mode")
 	params := map[string]interface{}{
 		"InitApplyYaml": "baetyl-init-deployment.yml",
 		"mode":          mode,
 	}
 	cmd, err := api.Init.GetResource(ns, name, service.TemplateBaetylInitCommand, params)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	return map[string]string{"cmd": string(cmd.([]byte))}, nil
+	return string(cmd.([]byte)), nil
 }
 
-// GetNodeDeployHistory list node // TODO will support later
+// GetNodeDeployHistory list the deploy history of the node
 func (api *API) GetNodeDeployHistory(c *common.Context) (interface{}, error) {
-	return nil, nil
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.NodeDeployFilter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if params.Start < 0 || params.End < 0 || (params.End > 0 && params.Start > params.End) {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid time range"))
+	}
+	if _, err := api.Node.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return api.Node.ListDeployHistory(ns, n, params)
 }
 
 func (api *API) ParseAndCheckNode(c *common.Context) (*v1.Node, error) {
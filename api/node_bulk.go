@@ -0,0 +1,261 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	maxBulkNodes   = 500
+	contentTypeCSV = "text/csv"
+)
+
+// CreateNodes create the nodes listed in the json or csv body, all nodes are
+// checked before any of them is created and the nodes created by the request are deleted if one fails,
+// the csv body has the header of name, labels (k1=v1;k2=v2) and description
+func (api *API) CreateNodes(c *common.Context) (interface{}, error) {
+	bulk, err := api.parseNodeBulk(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(bulk.Nodes) == 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "nodes are required"))
+	}
+	if len(bulk.Nodes) > maxBulkNodes {
+		return nil, common.Error(common.ErrInvalidArrayLength)
+	}
+	ns := c.GetNamespace()
+	if err = api.License.CheckQuota(ns, api.bulkNodeNumberCollector(len(bulk.Nodes))); err != nil {
+		return nil, err
+	}
+
+	results := make([]models.NodeBulkResult, len(bulk.Nodes))
+	invalid := false
+	names := map[string]bool{}
+	for i, item := range bulk.Nodes {
+		results[i].Name = item.Name
+		if err = api.checkNodeBulkItem(ns, &item, names); err != nil {
+			results[i].Error = err.Error()
+			invalid = true
+		}
+		names[item.Name] = true
+	}
+	if invalid {
+		for i := range results {
+			if results[i].Error == "" {
+				results[i].Error = "canceled since other nodes are invalid"
+			}
+		}
+		return toNodeBulkView(results), nil
+	}
+
+	for i, item := range bulk.Nodes {
+		node := &v1.Node{
+			Name:        item.Name,
+			Namespace:   ns,
+			Labels:      item.Labels,
+			Description: item.Description,
+		}
+		created, err := api.createNode(node)
+		if err != nil {
+			results[i].Error = err.Error()
+			// the failed node is deleted only if stored by this request, it may be created by others meanwhile
+			if created != nil {
+				api.rollbackNode(ns, item.Name)
+			}
+			api.rollbackNodes(ns, results[:i], item.Name)
+			for j := i + 1; j < len(results); j++ {
+				results[j].Error = fmt.Sprintf("canceled since node (%s) failed to create", item.Name)
+			}
+			return toNodeBulkView(results), nil
+		}
+		results[i].Success = true
+	}
+	return toNodeBulkView(results), nil
+}
+
+// DeleteNodes delete the nodes with the same cleanup as DeleteNode, the nodes not found are deleted successfully
+func (api *API) DeleteNodes(c *common.Context) (interface{}, error) {
+	names := new(models.NodeBulkNames)
+	if err := c.LoadBody(names); err != nil {
+		return nil, err
+	}
+	ns := c.GetNamespace()
+	results := make([]models.NodeBulkResult, 0, len(names.Names))
+	for _, name := range names.Names {
+		res := models.NodeBulkResult{Name: name, Success: true}
+		if err := api.deleteNode(ns, name); err != nil {
+			res.Success, res.Error = false, err.Error()
+		}
+		results = append(results, res)
+	}
+	return toNodeBulkView(results), nil
+}
+
+// GenInitCmdFromNodes generate the install commands of the nodes
+func (api *API) GenInitCmdFromNodes(c *common.Context) (interface{}, error) {
+	names := new(models.NodeBulkNames)
+	if err := c.LoadBody(names); err != nil {
+		return nil, err
+	}
+	ns, mode := c.GetNamespace(), c.Query("mode")
+	results := make([]models.NodeBulkResult, 0, len(names.Names))
+	for _, name := range names.Names {
+		res := models.NodeBulkResult{Name: name}
+		cmd, err := api.genInitCmd(ns, name, mode)
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Success, res.Cmd = true, cmd
+		}
+		results = append(results, res)
+	}
+	return toNodeBulkView(results), nil
+}
+
+func (api *API) parseNodeBulk(c *common.Context) (*models.NodeBulk, error) {
+	bulk := new(models.NodeBulk)
+	if c.ContentType() != contentTypeCSV {
+		if err := c.LoadBody(bulk); err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		return bulk, nil
+	}
+	items, err := parseNodeBulkCSV(c.Request.Body)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	bulk.Nodes = items
+	return bulk, nil
+}
+
+func parseNodeBulkCSV(r io.Reader) ([]models.NodeBulkItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("the column (name) is required")
+	}
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	var items []models.NodeBulkItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		item := models.NodeBulkItem{
+			Name:        column(record, "name"),
+			Description: column(record, "description"),
+		}
+		if labels := column(record, "labels"); labels != "" {
+			item.Labels = map[string]string{}
+			for _, kv := range strings.Split(labels, ";") {
+				if kv = strings.TrimSpace(kv); kv == "" {
+					continue
+				}
+				parts := strings.SplitN(kv, "=", 2)
+				if len(parts) != 2 {
+					return nil, fmt.Errorf("the label (%s) of node (%s) is invalid", kv, item.Name)
+				}
+				item.Labels[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// checkNodeBulkItem checks the node is valid, not duplicated in the request and not existed
+func (api *API) checkNodeBulkItem(ns string, item *models.NodeBulkItem, names map[string]bool) error {
+	if err := common.ValidateStruct(item); err != nil {
+		return err
+	}
+	if names[item.Name] {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is duplicated"))
+	}
+	_, err := api.Node.Get(ns, item.Name)
+	if err == nil {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
+	}
+	if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return err
+	}
+	return nil
+}
+
+// rollbackNodes deletes the nodes created by the request before the failed one, the nodes are created by
+// the node, init and index services without a transaction in common, so they are compensated by the same cleanup
+// as DeleteNode instead, the nodes failed to delete are logged as dirty data
+func (api *API) rollbackNodes(ns string, results []models.NodeBulkResult, failed string) {
+	for i := range results {
+		if !results[i].Success {
+			continue
+		}
+		api.rollbackNode(ns, results[i].Name)
+		results[i].Success = false
+		results[i].Error = fmt.Sprintf("rolled back since node (%s) failed to create", failed)
+	}
+}
+
+func (api *API) rollbackNode(ns, name string) {
+	if err := api.deleteNode(ns, name); err != nil {
+		common.LogDirtyData(err,
+			log.Any("type", common.Node),
+			log.Any(common.KeyContextNamespace, ns),
+			log.Any("name", name))
+	}
+}
+
+// bulkNodeNumberCollector counts the nodes as if the added nodes were created except the last one,
+// since the quota is exceeded once the count reaches the limit
+func (api *API) bulkNodeNumberCollector(added int) plugin.QuotaCollector {
+	return func(namespace string) (map[string]int, error) {
+		counts, err := api.NodeNumberCollector(namespace)
+		if err != nil {
+			return nil, err
+		}
+		counts[plugin.QuotaNode] += added - 1
+		return counts, nil
+	}
+}
+
+func toNodeBulkView(results []models.NodeBulkResult) *models.NodeBulkView {
+	view := &models.NodeBulkView{Total: len(results), Items: results}
+	for _, res := range results {
+		if res.Success {
+			view.Succeeded++
+		} else {
+			view.Failed++
+		}
+	}
+	return view
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initNodeBulkAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		bulk := v1.Group("/bulk/nodes")
+		bulk.POST("", mockIM, common.Wrapper(api.CreateNodes))
+		bulk.POST("/delete", mockIM, common.Wrapper(api.DeleteNodes))
+		bulk.POST("/init", mockIM, common.Wrapper(api.GenInitCmdFromNodes))
+	}
+	return api, router, mockCtl
+}
+
+func doNodeBulk(t *testing.T, router *gin.Engine, path, contentType string, body []byte) (int, *models.NodeBulkView) {
+	req, _ := http.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return w.Code, nil
+	}
+	view := new(models.NodeBulkView)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	return w.Code, view
+}
+
+func TestCreateNodes(t *testing.T) {
+	api, router, mockCtl := initNodeBulkAPI(t)
+	defer mockCtl.Finish()
+	sNode, sIndex, sInit, sLicense := ms.NewMockNodeService(mockCtl), ms.NewMockIndexService(mockCtl), ms.NewMockInitService(mockCtl), ms.NewMockLicenseService(mockCtl)
+	api.Node, api.Index, api.Init, api.License = sNode, sIndex, sInit, sLicense
+
+	notFound := common.Error(common.ErrResourceNotFound)
+	sLicense.EXPECT().CheckQuota("default", gomock.Any()).DoAndReturn(func(ns string, collector plugin.QuotaCollector) error {
+		sNode.EXPECT().List(ns, gomock.Any()).Return(&models.NodeList{Items: []specV1.Node{{Name: "n0"}}}, nil)
+		counts, err := collector(ns)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, counts[plugin.QuotaNode])
+		return nil
+	})
+	sNode.EXPECT().Get("default", "n1").Return(nil, notFound)
+	sNode.EXPECT().Get("default", "n2").Return(nil, notFound)
+	sNode.EXPECT().Create("default", gomock.Any()).DoAndReturn(func(_ string, n *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, n.Name, n.Labels[common.LabelNodeName])
+		return n, nil
+	}).Times(2)
+	app := &specV1.Application{Name: "baetyl-core", Namespace: "default"}
+	sInit.EXPECT().GenApps("default", gomock.Any()).Return([]*specV1.Application{app}, nil).Times(2)
+	sNode.EXPECT().UpdateNodeAppVersion("default", app, models.DeployTriggerApp).Return([]string{"n1"}, nil).Times(2)
+	sIndex.EXPECT().RefreshNodesIndexByApp("default", app.Name, []string{"n1"}).Return(nil).Times(2)
+	body, _ := json.Marshal(&models.NodeBulk{Nodes: []models.NodeBulkItem{
+		{Name: "n1", Labels: map[string]string{"a": "a"}},
+		{Name: "n2", Description: "desc"},
+	}})
+	code, view := doNodeBulk(t, router, "/v1/bulk/nodes", "application/json", body)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, view.Succeeded)
+	assert.Equal(t, 0, view.Failed)
+
+	// the invalid and duplicated nodes cancel the request
+	sLicense.EXPECT().CheckQuota("default", gomock.Any()).Return(nil)
+	sNode.EXPECT().Get("default", "n3").Return(&specV1.Node{Name: "n3"}, nil)
+	sNode.EXPECT().Get("default", "n4").Return(nil, notFound)
+	csv := "name,labels,description\nn3,a=a;b=b,\nN-5,,\nn4,,desc\nn4,,\n"
+	code, view = doNodeBulk(t, router, "/v1/bulk/nodes", contentTypeCSV, []byte(csv))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, view.Succeeded)
+	assert.Equal(t, 4, view.Failed)
+	assert.Contains(t, view.Items[0].Error, "this name is already in use")
+	assert.Contains(t, view.Items[1].Error, "The field (Name)")
+	assert.Equal(t, "canceled since other nodes are invalid", view.Items[2].Error)
+	assert.Contains(t, view.Items[3].Error, "this name is duplicated")
+
+	// the created nodes are deleted if one fails
+	sLicense.EXPECT().CheckQuota("default", gomock.Any()).Return(nil)
+	sNode.EXPECT().Get("default", "n6").Return(nil, notFound)
+	sNode.EXPECT().Get("default", "n7").Return(nil, notFound)
+	sNode.EXPECT().Get("default", "n8").Return(nil, notFound)
+	sNode.EXPECT().Create("default", gomock.Any()).Return(&specV1.Node{Name: "n6"}, nil)
+	sInit.EXPECT().GenApps("default", "n6").Return(nil, nil)
+	sNode.EXPECT().Create("default", gomock.Any()).Return(nil, fmt.Errorf("error"))
+	sNode.EXPECT().Get("default", "n6").Return(&specV1.Node{Name: "n6", Namespace: "default"}, nil)
+	sNode.EXPECT().Delete("default", "n6").Return(nil)
+	csv = "name\nn6\nn7\nn8\n"
+	code, view = doNodeBulk(t, router, "/v1/bulk/nodes", contentTypeCSV, []byte(csv))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3, view.Failed)
+	assert.Equal(t, "rolled back since node (n7) failed to create", view.Items[0].Error)
+	assert.Equal(t, "error", view.Items[1].Error)
+	assert.Equal(t, "canceled since node (n7) failed to create", view.Items[2].Error)
+
+	// the failed node is deleted too if it is stored
+	sLicense.EXPECT().CheckQuota("default", gomock.Any()).Return(nil)
+	sNode.EXPECT().Get("default", "n9").Return(nil, notFound)
+	sNode.EXPECT().Create("default", gomock.Any()).Return(&specV1.Node{Name: "n9"}, nil)
+	sInit.EXPECT().GenApps("default", "n9").Return(nil, fmt.Errorf("error"))
+	sNode.EXPECT().Get("default", "n9").Return(&specV1.Node{Name: "n9", Namespace: "default"}, nil)
+	sNode.EXPECT().Delete("default", "n9").Return(nil)
+	code, view = doNodeBulk(t, router, "/v1/bulk/nodes", contentTypeCSV, []byte("name\nn9\n"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, view.Failed)
+	assert.Equal(t, "error", view.Items[0].Error)
+
+	sLicense.EXPECT().CheckQuota("default", gomock.Any()).Return(common.Error(common.ErrLicenseQuota, common.Field("name", plugin.QuotaNode), common.Field("limit", 2)))
+	code, _ = doNodeBulk(t, router, "/v1/bulk/nodes", contentTypeCSV, []byte(csv))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = doNodeBulk(t, router, "/v1/bulk/nodes", contentTypeCSV, []byte("labels\na=a\n"))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doNodeBulk(t, router, "/v1/bulk/nodes", contentTypeCSV, []byte("name,labels\nn1,a\n"))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doNodeBulk(t, router, "/v1/bulk/nodes", "application/json", []byte(`{"nodes":[]}`))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doNodeBulk(t, router, "/v1/bulk/nodes", contentTypeCSV, []byte("name\n"+strings.Repeat("n\n", maxBulkNodes+1)))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestDeleteNodes(t *testing.T) {
+	api, router, mockCtl := initNodeBulkAPI(t)
+	defer mockCtl.Finish()
+	sNode, sIndex, sApp := ms.NewMockNodeService(mockCtl), ms.NewMockIndexService(mockCtl), ms.NewMockApplicationService(mockCtl)
+	api.Node, api.Index = sNode, sIndex
+	api.AppCombinedService = &service.AppCombinedService{App: sApp}
+
+	node := &specV1.Node{Name: "n1", Namespace: "default", Desire: specV1.Desire{
+		"sysapps": []specV1.AppInfo{{Name: "baetyl-core-n1", Version: "1"}},
+	}}
+	sNode.EXPECT().Get("default", "n1").Return(node, nil)
+	sNode.EXPECT().Delete("default", "n1").Return(nil)
+	sApp.EXPECT().Get("default", "baetyl-core-n1", "").Return(&specV1.Application{Name: "baetyl-core-n1", Version: "2"}, nil)
+	sApp.EXPECT().Delete("default", "baetyl-core-n1", "2").Return(nil)
+	sIndex.EXPECT().RefreshNodesIndexByApp("default", "baetyl-core-n1", gomock.Any()).Return(nil)
+	sNode.EXPECT().Get("default", "n2").Return(nil, common.Error(common.ErrResourceNotFound))
+	sNode.EXPECT().Get("default", "n3").Return(nil, fmt.Errorf("error"))
+	body, _ := json.Marshal(&models.NodeBulkNames{Names: []string{"n1", "n2", "n3"}})
+	code, view := doNodeBulk(t, router, "/v1/bulk/nodes/delete", "application/json", body)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, view.Succeeded)
+	assert.Equal(t, 1, view.Failed)
+	assert.Equal(t, "error", view.Items[2].Error)
+
+	body, _ = json.Marshal(&models.NodeBulkNames{Names: []string{"N1"}})
+	code, _ = doNodeBulk(t, router, "/v1/bulk/nodes/delete", "application/json", body)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestGenInitCmdFromNodes(t *testing.T) {
+	api, router, mockCtl := initNodeBulkAPI(t)
+	defer mockCtl.Finish()
+	sNode, sInit := ms.NewMockNodeService(mockCtl), ms.NewMockInitService(mockCtl)
+	api.Node, api.Init = sNode, sInit
+
+	sNode.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1"}, nil)
+	sInit.EXPECT().GetResource("default", "n1", service.TemplateBaetylInitCommand, gomock.Any()).DoAndReturn(func(_, _, _ string, params map[string]interface{}) (interface{}, error) {
+		assert.Equal(t, "native", params["mode"])
+		return []byte("curl n1"), nil
+	})
+	sNode.EXPECT().Get("default", "n2").Return(nil, common.Error(common.ErrResourceNotFound))
+	body, _ := json.Marshal(&models.NodeBulkNames{Names: []string{"n1", "n2"}})
+	code, view := doNodeBulk(t, router, "/v1/bulk/nodes/init?mode=native", "application/json", body)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, view.Succeeded)
+	assert.Equal(t, "curl n1", view.Items[0].Cmd)
+	assert.NotEmpty(t, view.Items[1].Error)
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetNodeGroup get the group with its members and their online/offline counts
+func (api *API) GetNodeGroup(c *common.Context) (interface{}, error) {
+	group, err := api.NodeGroup.Get(c.GetNamespace(), c.GetNameFromParam())
+	if err != nil {
+		return nil, err
+	}
+	return api.toNodeGroupView(group)
+}
+
+// ListNodeGroup list the groups with the online/offline counts of their members
+func (api *API) ListNodeGroup(c *common.Context) (interface{}, error) {
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	list, err := api.NodeGroup.List(c.GetNamespace(), params)
+	if err != nil {
+		return nil, err
+	}
+	groups := list.Items.([]models.NodeGroup)
+	views := make([]models.NodeGroupView, 0, len(groups))
+	for i := range groups {
+		view, err := api.toNodeGroupView(&groups[i])
+		if err != nil {
+			return nil, err
+		}
+		view.Nodes = nil
+		views = append(views, *view)
+	}
+	list.Items = views
+	return list, nil
+}
+
+// CreateNodeGroup create the group, the nodes of the static group are added as its members
+func (api *API) CreateNodeGroup(c *common.Context) (interface{}, error) {
+	group := new(models.NodeGroup)
+	if err := c.LoadBody(group); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	group, err := api.NodeGroup.Create(c.GetNamespace(), group)
+	if err != nil {
+		return nil, err
+	}
+	return api.toNodeGroupView(group)
+}
+
+// UpdateNodeGroup update the description of the group and the selector of the dynamic group
+func (api *API) UpdateNodeGroup(c *common.Context) (interface{}, error) {
+	group := &models.NodeGroup{Name: c.GetNameFromParam()}
+	if err := c.LoadBody(group); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	group.Name = c.GetNameFromParam()
+	group, err := api.NodeGroup.Update(c.GetNamespace(), group)
+	if err != nil {
+		return nil, err
+	}
+	return api.toNodeGroupView(group)
+}
+
+// DeleteNodeGroup delete the group which is not assigned to any application
+func (api *API) DeleteNodeGroup(c *common.Context) (interface{}, error) {
+	return nil, api.NodeGroup.Delete(c.GetNamespace(), c.GetNameFromParam())
+}
+
+// ListNodeGroupNodes list the members of the group
+func (api *API) ListNodeGroupNodes(c *common.Context) (interface{}, error) {
+	nodeList, err := api.NodeGroup.ListNodes(c.GetNamespace(), c.GetNameFromParam())
+	if err != nil {
+		return nil, err
+	}
+	nodeViewList := models.NodeViewList{
+		Total:       len(nodeList.Items),
+		ListOptions: nodeList.ListOptions,
+		Items:       make([]v1.NodeView, 0, len(nodeList.Items)),
+	}
+	for idx := range nodeList.Items {
+		view, err := nodeList.Items[idx].View(offlineDuration)
+		if err != nil {
+			return nil, err
+		}
+		view.Desire = nil
+		nodeViewList.Items = append(nodeViewList.Items, *view)
+	}
+	return nodeViewList, nil
+}
+
+// UpdateNodeGroupNodes add the nodes to or remove them from the static group
+func (api *API) UpdateNodeGroupNodes(c *common.Context) (interface{}, error) {
+	members := new(models.NodeGroupMembers)
+	if err := c.LoadBody(members); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	group, err := api.NodeGroup.UpdateMembers(c.GetNamespace(), c.GetNameFromParam(), members)
+	if err != nil {
+		return nil, err
+	}
+	return api.toNodeGroupView(group)
+}
+
+// UpdateNodeGroupLabels add or remove the labels of all members of the group
+func (api *API) UpdateNodeGroupLabels(c *common.Context) (interface{}, error) {
+	labels := new(models.NodeGroupLabels)
+	if err := c.LoadBody(labels); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	nodes, err := api.NodeGroup.UpdateLabels(c.GetNamespace(), c.GetNameFromParam(), labels)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"nodes": nodes}, nil
+}
+
+// AssignNodeGroupApp deploy the application to the members of the group
+func (api *API) AssignNodeGroupApp(c *common.Context) (interface{}, error) {
+	app, err := api.NodeGroup.AssignApp(c.GetNamespace(), c.GetNameFromParam(), c.Param("app"))
+	if err != nil {
+		return nil, err
+	}
+	return api.toApplicationView(app)
+}
+
+func (api *API) toNodeGroupView(group *models.NodeGroup) (*models.NodeGroupView, error) {
+	nodes, err := api.NodeGroup.ListNodes(group.Namespace, group.Name)
+	if err != nil {
+		return nil, err
+	}
+	view := &models.NodeGroupView{
+		NodeGroup: *group,
+		Type:      models.NodeGroupStatic,
+		Total:     len(nodes.Items),
+	}
+	if group.IsDynamic() {
+		view.Type = models.NodeGroupDynamic
+	}
+	view.Nodes = make([]string, 0, len(nodes.Items))
+	for idx := range nodes.Items {
+		n, err := nodes.Items[idx].View(offlineDuration)
+		if err != nil {
+			return nil, err
+		}
+		if n.Ready {
+			view.Online++
+		} else {
+			view.Offline++
+		}
+		view.Nodes = append(view.Nodes, n.Name)
+	}
+	return view, nil
+}
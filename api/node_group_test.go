@@ -0,0 +1,184 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initNodeGroupAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		groups := v1.Group("/groups")
+		groups.GET("/:name", mockIM, common.Wrapper(api.GetNodeGroup))
+		groups.PUT("/:name", mockIM, common.Wrapper(api.UpdateNodeGroup))
+		groups.DELETE("/:name", mockIM, common.Wrapper(api.DeleteNodeGroup))
+		groups.POST("", mockIM, common.Wrapper(api.CreateNodeGroup))
+		groups.GET("", mockIM, common.Wrapper(api.ListNodeGroup))
+		groups.GET("/:name/nodes", mockIM, common.Wrapper(api.ListNodeGroupNodes))
+		groups.PUT("/:name/nodes", mockIM, common.Wrapper(api.UpdateNodeGroupNodes))
+		groups.PUT("/:name/labels", mockIM, common.Wrapper(api.UpdateNodeGroupLabels))
+		groups.PUT("/:name/apps/:app", mockIM, common.Wrapper(api.AssignNodeGroupApp))
+	}
+	return api, router, mockCtl
+}
+
+func genNodeGroupMembers() *models.NodeList {
+	return &models.NodeList{
+		Items: []specV1.Node{
+			{Name: "n1", Namespace: "default", Report: specV1.Report{"time": time.Now().Format(time.RFC3339Nano), "nodestats": map[string]interface{}{}}},
+			{Name: "n2", Namespace: "default"},
+		},
+	}
+}
+
+func TestNodeGroup(t *testing.T) {
+	api, router, mockCtl := initNodeGroupAPI(t)
+	defer mockCtl.Finish()
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	api.NodeGroup = sGroup
+
+	group := &models.NodeGroup{Name: "g1", Namespace: "default", Nodes: []string{"n1", "n2"}}
+	sGroup.EXPECT().Get("default", "g1").Return(group, nil)
+	sGroup.EXPECT().ListNodes("default", "g1").Return(genNodeGroupMembers(), nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/groups/g1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var view models.NodeGroupView
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &view))
+	assert.Equal(t, models.NodeGroupStatic, view.Type)
+	assert.Equal(t, 2, view.Total)
+	assert.Equal(t, 1, view.Online)
+	assert.Equal(t, 1, view.Offline)
+	assert.Equal(t, []string{"n1", "n2"}, view.Nodes)
+
+	sGroup.EXPECT().Get("default", "g2").Return(nil, common.Error(common.ErrResourceNotFound))
+	req, _ = http.NewRequest(http.MethodGet, "/v1/groups/g2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	dynamic := models.NodeGroup{Name: "g3", Namespace: "default", Selector: "a=a"}
+	sGroup.EXPECT().List("default", gomock.Any()).Return(&models.ListView{Total: 1, Items: []models.NodeGroup{dynamic}}, nil)
+	sGroup.EXPECT().ListNodes("default", "g3").Return(genNodeGroupMembers(), nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/groups", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Total int                    `json:"total"`
+		Items []models.NodeGroupView `json:"items"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, models.NodeGroupDynamic, list.Items[0].Type)
+	assert.Nil(t, list.Items[0].Nodes)
+
+	sGroup.EXPECT().Create("default", gomock.Any()).DoAndReturn(func(_ string, g *models.NodeGroup) (*models.NodeGroup, error) {
+		assert.Equal(t, []string{"n1", "n2"}, g.Nodes)
+		return group, nil
+	})
+	sGroup.EXPECT().ListNodes("default", "g1").Return(genNodeGroupMembers(), nil)
+	body, _ := json.Marshal(group)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/groups", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/v1/groups", bytes.NewReader([]byte("{")))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sGroup.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, g *models.NodeGroup) (*models.NodeGroup, error) {
+		assert.Equal(t, "g1", g.Name)
+		assert.Equal(t, "desc", g.Description)
+		return group, nil
+	})
+	sGroup.EXPECT().ListNodes("default", "g1").Return(genNodeGroupMembers(), nil)
+	body, _ = json.Marshal(&models.NodeGroup{Name: "other", Description: "desc"})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/groups/g1", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sGroup.EXPECT().Delete("default", "g1").Return(common.Error(common.ErrResourceHasBeenUsed))
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/groups/g1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	sGroup.EXPECT().Delete("default", "g1").Return(nil)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/groups/g1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestNodeGroupMembers(t *testing.T) {
+	api, router, mockCtl := initNodeGroupAPI(t)
+	defer mockCtl.Finish()
+	sGroup := ms.NewMockNodeGroupService(mockCtl)
+	api.NodeGroup = sGroup
+
+	sGroup.EXPECT().ListNodes("default", "g1").Return(genNodeGroupMembers(), nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/groups/g1/nodes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var nodes models.NodeViewList
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &nodes))
+	assert.Equal(t, 2, nodes.Total)
+	assert.True(t, nodes.Items[0].Ready)
+	assert.False(t, nodes.Items[1].Ready)
+
+	group := &models.NodeGroup{Name: "g1", Namespace: "default"}
+	sGroup.EXPECT().UpdateMembers("default", "g1", gomock.Any()).DoAndReturn(func(_, _ string, m *models.NodeGroupMembers) (*models.NodeGroup, error) {
+		assert.Equal(t, []string{"n3"}, m.Add)
+		assert.Equal(t, []string{"n2"}, m.Remove)
+		return group, nil
+	})
+	sGroup.EXPECT().ListNodes("default", "g1").Return(genNodeGroupMembers(), nil)
+	body, _ := json.Marshal(&models.NodeGroupMembers{Add: []string{"n3"}, Remove: []string{"n2"}})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/groups/g1/nodes", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sGroup.EXPECT().UpdateLabels("default", "g1", gomock.Any()).Return([]string{"n1"}, nil)
+	body, _ = json.Marshal(&models.NodeGroupLabels{Add: map[string]string{"a": "a"}})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/groups/g1/labels", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"nodes":["n1"]}`, w.Body.String())
+
+	sGroup.EXPECT().AssignApp("default", "g1", "app1").Return(&specV1.Application{Name: "app1", Namespace: "default", Selector: "baetyl-group-g1=true"}, nil)
+	req, _ = http.NewRequest(http.MethodPut, "/v1/groups/g1/apps/app1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sGroup.EXPECT().AssignApp("default", "g1", "app2").Return(nil, common.Error(common.ErrResourceNotFound))
+	req, _ = http.NewRequest(http.MethodPut, "/v1/groups/g1/apps/app2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
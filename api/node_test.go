@@ -417,7 +417,7 @@ func TestCreateNode(t *testing.T) {
 	}
 	nodeList := []string{"s0", "s1", "s2"}
 
-	sNode.EXPECT().UpdateNodeAppVersion(mNode.Namespace, gomock.Any()).Return(nodeList, nil).AnyTimes()
+	sNode.EXPECT().UpdateNodeAppVersion(mNode.Namespace, gomock.Any(), models.DeployTriggerApp).Return(nodeList, nil).AnyTimes()
 	sIndex.EXPECT().RefreshNodesIndexByApp(mNode.Namespace, gomock.Any(), nodeList).AnyTimes()
 	sInit.EXPECT().GenApps(mNode.Namespace, gomock.Any()).Return([]*specV1.Application{app1, app2}, nil).Times(2)
 
@@ -888,10 +888,49 @@ func TestAPI_GetNodeDeployHistory(t *testing.T) {
 	sNode := ms.NewMockNodeService(mockCtl)
 	api.Node = sNode
 
-	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys", nil)
-	w2 := httptest.NewRecorder()
-	router.ServeHTTP(w2, req)
-	assert.Equal(t, http.StatusOK, w2.Code)
+	node := getMockNode()
+	histories := &models.ListView{
+		Total:    1,
+		PageNo:   1,
+		PageSize: 20,
+		Items: []models.NodeDeployHistory{
+			{
+				Id:         1,
+				Namespace:  node.Namespace,
+				NodeName:   node.Name,
+				AppName:    "app01",
+				OldVersion: "1",
+				NewVersion: "2",
+				Trigger:    models.DeployTriggerConfig,
+			},
+		},
+	}
+	filter := &models.NodeDeployFilter{
+		Filter: models.Filter{PageNo: 1, PageSize: 20},
+		Start:  100,
+		End:    200,
+	}
+	sNode.EXPECT().Get(node.Namespace, node.Name).Return(node, nil).Times(1)
+	sNode.EXPECT().ListDeployHistory(node.Namespace, node.Name, filter).Return(histories, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys?pageNo=1&pageSize=20&start=100&end=200", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"appName":"app01"`)
+
+	// invalid time range
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys?start=200&end=100", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// node not found
+	sNode.EXPECT().Get(node.Namespace, node.Name).Return(nil, common.Error(common.ErrResourceNotFound,
+		common.Field("type", "node"), common.Field("name", node.Name))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/deploys", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
 func TestGenInitCmdFromNode(t *testing.T) {
@@ -1093,3 +1132,43 @@ func TestAPI_NodeNumberCollector(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, res[plugin.QuotaNode])
 }
+
+func TestDeleteNodeOfBatch(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+
+	sNode := ms.NewMockNodeService(mockCtl)
+	sBatch := ms.NewMockBatchService(mockCtl)
+	sCallback := ms.NewMockCallbackService(mockCtl)
+	api.Node, api.Batch, api.Callback = sNode, sBatch, sCallback
+
+	mNode := &specV1.Node{
+		Namespace: "default",
+		Name:      "abc",
+		Labels:    map[string]string{common.LabelBatch: "b1"},
+	}
+	sNode.EXPECT().Get(mNode.Namespace, mNode.Name).Return(mNode, nil)
+	sNode.EXPECT().Delete(mNode.Namespace, mNode.Name).Return(nil)
+	sBatch.EXPECT().Get(mNode.Namespace, "b1").Return(&models.Batch{Name: "b1", CallbackName: "cb"}, nil)
+	sCallback.EXPECT().Dispatch("cb", mNode.Namespace, map[string]string{
+		service.CallbackArgEvent:     service.CallbackEventNodeDelete,
+		service.CallbackArgNamespace: mNode.Namespace,
+		service.CallbackArgBatchName: "b1",
+		service.CallbackArgNodeName:  mNode.Name,
+	})
+
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/nodes/abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// failure of getting batch is ignored
+	sNode.EXPECT().Get(mNode.Namespace, mNode.Name).Return(mNode, nil)
+	sNode.EXPECT().Delete(mNode.Namespace, mNode.Name).Return(nil)
+	sBatch.EXPECT().Get(mNode.Namespace, "b1").Return(nil, common.Error(common.ErrResourceNotFound))
+
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/nodes/abc", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// CreateOTA upgrade baetyl-core of the nodes specified by names, a label selector or a batch
+func (api *API) CreateOTA(c *common.Context) (interface{}, error) {
+	req := new(models.OTARequest)
+	if err := c.LoadBody(req); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	return api.OTA.Create(c.GetNamespace(), req)
+}
+
+// GetOTA get the ota task of the node
+func (api *API) GetOTA(c *common.Context) (interface{}, error) {
+	return api.OTA.Get(c.GetNamespace(), c.GetNameFromParam(), c.Param("id"))
+}
+
+// ListOTA list the ota tasks of the node, filtered by the state in query if present
+func (api *API) ListOTA(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.TaskFilter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Node.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return api.OTA.List(ns, n, params)
+}
+
+// CancelOTA cancel the running ota task of the node
+func (api *API) CancelOTA(c *common.Context) (interface{}, error) {
+	return api.OTA.Cancel(c.GetNamespace(), c.GetNameFromParam(), c.Param("id"))
+}
+
+// RetryOTA retry the failed, timed out or canceled ota task of the node
+func (api *API) RetryOTA(c *common.Context) (interface{}, error) {
+	return api.OTA.Retry(c.GetNamespace(), c.GetNameFromParam(), c.Param("id"))
+}
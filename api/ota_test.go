@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initOTAAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		v1.POST("/ota", mockIM, common.Wrapper(api.CreateOTA))
+		ota := v1.Group("/nodes/:name/ota")
+		ota.GET("", mockIM, common.Wrapper(api.ListOTA))
+		ota.GET("/:id", mockIM, common.Wrapper(api.GetOTA))
+		ota.POST("/:id/cancel", mockIM, common.Wrapper(api.CancelOTA))
+		ota.POST("/:id/retry", mockIM, common.Wrapper(api.RetryOTA))
+	}
+	return api, router, mockCtl
+}
+
+func TestCreateOTA(t *testing.T) {
+	api, router, mockCtl := initOTAAPI(t)
+	defer mockCtl.Finish()
+	sOTA := ms.NewMockOTAService(mockCtl)
+	api.OTA = sOTA
+
+	sOTA.EXPECT().Create("default", gomock.Any()).DoAndReturn(func(_ string, r *models.OTARequest) (*models.OTAView, error) {
+		assert.Equal(t, "v2.2.0", r.Version)
+		assert.Equal(t, "a=a", r.Selector)
+		return &models.OTAView{
+			Tasks:    []models.OTATask{{ID: "t1", Node: "n1", State: models.OTARunning}},
+			Failures: []models.OTAFailure{{Node: "n2", Error: "error"}},
+		}, nil
+	})
+	body, _ := json.Marshal(&models.OTARequest{Version: "v2.2.0", Selector: "a=a"})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/ota", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res models.OTAView
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Len(t, res.Tasks, 1)
+	assert.Len(t, res.Failures, 1)
+
+	sOTA.EXPECT().Create("default", gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid))
+	req, _ = http.NewRequest(http.MethodPost, "/v1/ota", bytes.NewReader([]byte("{}")))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/v1/ota", bytes.NewReader([]byte("{")))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestNodeOTA(t *testing.T) {
+	api, router, mockCtl := initOTAAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	sOTA := ms.NewMockOTAService(mockCtl)
+	api.Node, api.OTA = sNode, sOTA
+
+	sOTA.EXPECT().Get("default", "n1", "t1").Return(&models.OTATask{ID: "t1", State: models.OTARunning}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/n1/ota/t1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sNode.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1"}, nil)
+	sOTA.EXPECT().List("default", "n1", gomock.Any()).DoAndReturn(func(_, _ string, f *models.TaskFilter) (*models.ListView, error) {
+		assert.Equal(t, models.OTAFailed, f.State)
+		return &models.ListView{Total: 1, Items: []models.OTATask{{ID: "t1"}}}, nil
+	})
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/n1/ota?state=failed", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sNode.EXPECT().Get("default", "n2").Return(nil, common.Error(common.ErrResourceNotFound))
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/n2/ota", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	sOTA.EXPECT().Cancel("default", "n1", "t1").Return(&models.OTATask{ID: "t1", State: models.OTACanceled}, nil)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/n1/ota/t1/cancel", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sOTA.EXPECT().Retry("default", "n1", "t1").Return(nil, common.Error(common.ErrRequestParamInvalid))
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/n1/ota/t1/retry", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetRoleBinding get the role binding of a user
+func (api *API) GetRoleBinding(c *common.Context) (interface{}, error) {
+	return api.RBAC.GetRoleBinding(c.GetNamespace(), c.Param("user"))
+}
+
+// ListRoleBinding list the role bindings of the namespace
+func (api *API) ListRoleBinding(c *common.Context) (interface{}, error) {
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	return api.RBAC.ListRoleBinding(c.GetNamespace(), params)
+}
+
+// CreateRoleBinding bind a role to a user
+func (api *API) CreateRoleBinding(c *common.Context) (interface{}, error) {
+	binding, err := parseAndCheckRoleBinding(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.RBAC.CreateRoleBinding(binding)
+}
+
+// UpdateRoleBinding change the role of a user
+func (api *API) UpdateRoleBinding(c *common.Context) (interface{}, error) {
+	binding, err := parseAndCheckRoleBinding(c)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = api.RBAC.GetRoleBinding(binding.Namespace, binding.User); err != nil {
+		return nil, err
+	}
+	return api.RBAC.UpdateRoleBinding(binding)
+}
+
+// DeleteRoleBinding delete the role binding of a user
+func (api *API) DeleteRoleBinding(c *common.Context) (interface{}, error) {
+	ns, user := c.GetNamespace(), c.Param("user")
+	if _, err := api.RBAC.GetRoleBinding(ns, user); err != nil {
+		return nil, err
+	}
+	return nil, api.RBAC.DeleteRoleBinding(ns, user)
+}
+
+func parseAndCheckRoleBinding(c *common.Context) (*models.RoleBinding, error) {
+	binding := new(models.RoleBinding)
+	if err := c.LoadBody(binding); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if user := c.Param("user"); user != "" {
+		binding.User = user
+	}
+	if binding.User == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "user is required"))
+	}
+	if _, ok := models.RolePermissions[binding.Role]; !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "role should be one of viewer/operator/admin"))
+	}
+	binding.Namespace = c.GetNamespace()
+	return binding, nil
+}
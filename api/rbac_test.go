@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initRoleBindingAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		rolebindings := v1.Group("/rolebindings")
+		rolebindings.GET("/:user", mockIM, common.Wrapper(api.GetRoleBinding))
+		rolebindings.PUT("/:user", mockIM, common.Wrapper(api.UpdateRoleBinding))
+		rolebindings.DELETE("/:user", mockIM, common.Wrapper(api.DeleteRoleBinding))
+		rolebindings.POST("", mockIM, common.Wrapper(api.CreateRoleBinding))
+		rolebindings.GET("", mockIM, common.Wrapper(api.ListRoleBinding))
+	}
+	return api, router, mockCtl
+}
+
+func TestGetRoleBinding(t *testing.T) {
+	api, router, mockCtl := initRoleBindingAPI(t)
+	defer mockCtl.Finish()
+	sRBAC := ms.NewMockRBACService(mockCtl)
+	api.RBAC = sRBAC
+
+	sRBAC.EXPECT().GetRoleBinding("default", "user01").Return(&models.RoleBinding{User: "user01"}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/rolebindings/user01", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sRBAC.EXPECT().ListRoleBinding("default", gomock.Any()).Return(&models.ListView{Items: []models.RoleBinding{}}, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/rolebindings", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCreateRoleBinding(t *testing.T) {
+	api, router, mockCtl := initRoleBindingAPI(t)
+	defer mockCtl.Finish()
+	sRBAC := ms.NewMockRBACService(mockCtl)
+	api.RBAC = sRBAC
+
+	binding := &models.RoleBinding{Namespace: "default", User: "user01", Role: models.RoleOperator}
+	sRBAC.EXPECT().CreateRoleBinding(binding).Return(binding, nil)
+	body, _ := json.Marshal(&models.RoleBinding{Namespace: "other", User: "user01", Role: models.RoleOperator})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/rolebindings", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, v := range []*models.RoleBinding{
+		{Role: models.RoleOperator},
+		{User: "user01"},
+		{User: "user01", Role: "root"},
+	} {
+		body, _ = json.Marshal(v)
+		req, _ = http.NewRequest(http.MethodPost, "/v1/rolebindings", bytes.NewReader(body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, v)
+	}
+}
+
+func TestUpdateAndDeleteRoleBinding(t *testing.T) {
+	api, router, mockCtl := initRoleBindingAPI(t)
+	defer mockCtl.Finish()
+	sRBAC := ms.NewMockRBACService(mockCtl)
+	api.RBAC = sRBAC
+
+	binding := &models.RoleBinding{Namespace: "default", User: "user01", Role: models.RoleAdmin}
+	sRBAC.EXPECT().GetRoleBinding("default", "user01").Return(binding, nil)
+	sRBAC.EXPECT().UpdateRoleBinding(binding).Return(binding, nil)
+	body, _ := json.Marshal(&models.RoleBinding{Role: models.RoleAdmin})
+	req, _ := http.NewRequest(http.MethodPut, "/v1/rolebindings/user01", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sRBAC.EXPECT().GetRoleBinding("default", "user02").Return(nil, common.Error(common.ErrResourceNotFound))
+	req, _ = http.NewRequest(http.MethodPut, "/v1/rolebindings/user02", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	sRBAC.EXPECT().GetRoleBinding("default", "user01").Return(binding, nil)
+	sRBAC.EXPECT().DeleteRoleBinding("default", "user01").Return(nil)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/rolebindings/user01", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
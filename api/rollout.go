@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetRollout get a rollout
+func (api *API) GetRollout(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Rollout.Get(ns, n)
+}
+
+// ListRollout list rollouts
+func (api *API) ListRollout(c *common.Context) (interface{}, error) {
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	return api.Rollout.List(c.GetNamespace(), params)
+}
+
+// PauseRollout stop starting the next waves of the rollout
+func (api *API) PauseRollout(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Rollout.Pause(ns, n)
+}
+
+// ResumeRollout continue the paused rollout
+func (api *API) ResumeRollout(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Rollout.Resume(ns, n)
+}
+
+// AbortRollout abort the rollout, the nodes already updated keep the new version
+func (api *API) AbortRollout(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Rollout.Abort(ns, n)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initRolloutAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		rollouts := v1.Group("/rollouts")
+		rollouts.GET("/:name", mockIM, common.Wrapper(api.GetRollout))
+		rollouts.GET("", mockIM, common.Wrapper(api.ListRollout))
+		rollouts.POST("/:name/pause", mockIM, common.Wrapper(api.PauseRollout))
+		rollouts.POST("/:name/resume", mockIM, common.Wrapper(api.ResumeRollout))
+		rollouts.POST("/:name/abort", mockIM, common.Wrapper(api.AbortRollout))
+	}
+	return api, router, mockCtl
+}
+
+func TestGetRollout(t *testing.T) {
+	api, router, mockCtl := initRolloutAPI(t)
+	defer mockCtl.Finish()
+	sRollout := ms.NewMockRolloutService(mockCtl)
+	api.Rollout = sRollout
+
+	sRollout.EXPECT().Get("default", "app-2").Return(&models.Rollout{Name: "app-2"}, nil)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/rollouts/app-2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sRollout.EXPECT().Get("default", "app-3").Return(nil, common.Error(common.ErrResourceNotFound))
+	req, _ = http.NewRequest(http.MethodGet, "/v1/rollouts/app-3", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	sRollout.EXPECT().List("default", gomock.Any()).Return(&models.ListView{Items: []models.Rollout{}}, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/rollouts?name=app", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestChangeRolloutStatus(t *testing.T) {
+	api, router, mockCtl := initRolloutAPI(t)
+	defer mockCtl.Finish()
+	sRollout := ms.NewMockRolloutService(mockCtl)
+	api.Rollout = sRollout
+
+	sRollout.EXPECT().Pause("default", "app-2").Return(&models.Rollout{Status: models.RolloutPaused}, nil)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/rollouts/app-2/pause", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sRollout.EXPECT().Resume("default", "app-2").Return(nil, common.Error(common.ErrRequestParamInvalid))
+	req, _ = http.NewRequest(http.MethodPost, "/v1/rollouts/app-2/resume", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sRollout.EXPECT().Abort("default", "app-2").Return(&models.Rollout{Status: models.RolloutAborted}, nil)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/rollouts/app-2/abort", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -49,6 +49,10 @@ func (api *API) CreateSecret(c *common.Context) (interface{}, error) {
 	if sd != nil {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
 	}
+	if c.IsDryRun() {
+		c.SetDryRunHandled()
+		return &models.DryRunResult{Valid: true, Resource: cfg}, nil
+	}
 	return wrapSecret(api.Secret.Create(ns, cfg.ToSecret()))
 }
 
@@ -63,9 +67,23 @@ func (api *API) UpdateSecret(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	dryRun := c.IsDryRun()
+	if dryRun {
+		c.SetDryRunHandled()
+	}
 	if sd.Equal(cfg) {
+		if dryRun {
+			return &models.DryRunResult{Valid: true, Resource: sd}, nil
+		}
 		return sd, nil
 	}
+	if dryRun {
+		appNames, err := api.Index.ListAppIndexBySecret(ns, cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		return api.dryRunReferencedBy(ns, cfg, appNames)
+	}
 	cfg.Version = sd.Version
 	cfg.UpdateTimestamp = time.Now()
 	res, err := wrapSecret(api.Secret.Update(ns, cfg.ToSecret()))
@@ -163,7 +181,7 @@ func (api *API) updateAppSecret(namespace string, secret *specV1.Secret) error {
 		if err != nil {
 			return err
 		}
-		_, err = api.Node.UpdateNodeAppVersion(namespace, app)
+		_, err = api.Rollout.UpdateNodeAppVersion(namespace, app, models.DeployTriggerSecret)
 		if err != nil {
 			return err
 		}
@@ -1,9 +1,13 @@
 package api
 
 import (
+	"time"
+
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
@@ -12,10 +16,14 @@ import (
 type SyncAPI interface {
 	Report(msg specV1.Message) (*specV1.Message, error)
 	Desire(msg specV1.Message) (*specV1.Message, error)
+	Delta(msg specV1.Message) (*specV1.Message, error)
+	Command(msg specV1.Message) (*specV1.Message, error)
+	Commands(msg specV1.Message) (*specV1.Message, error)
 }
 
 type SyncAPIImpl struct {
 	Sync service.SyncService
+	Cmd  service.CommandService
 }
 
 func NewSyncAPI(cfg *config.CloudConfig) (SyncAPI, error) {
@@ -23,8 +31,13 @@ func NewSyncAPI(cfg *config.CloudConfig) (SyncAPI, error) {
 	if err != nil {
 		return nil, err
 	}
+	commandService, err := service.NewCommandService(cfg)
+	if err != nil {
+		return nil, err
+	}
 	return &SyncAPIImpl{
 		Sync: syncService,
+		Cmd:  commandService,
 	}, nil
 }
 
@@ -37,10 +50,13 @@ func (s *SyncAPIImpl) Report(msg specV1.Message) (*specV1.Message, error) {
 	}
 
 	setNodeAddressIfExist(msg, &report)
-	desire, err := s.Sync.Report(msg.Metadata["namespace"], msg.Metadata["name"], report)
+	ns, n := msg.Metadata["namespace"], msg.Metadata["name"]
+	syncRequests.WithLabelValues(ns, string(specV1.MessageReport)).Inc()
+	desire, err := s.Sync.Report(ns, n, report)
 	if err != nil {
 		return nil, err
 	}
+	nodeReports.report(ns, n, time.Now())
 	return &specV1.Message{
 		Kind:     specV1.MessageReport,
 		Metadata: msg.Metadata,
@@ -56,10 +72,13 @@ func (s *SyncAPIImpl) Desire(msg specV1.Message) (*specV1.Message, error) {
 		return nil, err
 	}
 
-	res, err := s.Sync.Desire(msg.Metadata["namespace"], desireRes.Infos, msg.Metadata)
+	ns := msg.Metadata["namespace"]
+	syncRequests.WithLabelValues(ns, string(specV1.MessageDesire)).Inc()
+	res, err := s.Sync.Desire(ns, desireRes.Infos, msg.Metadata)
 	if err != nil {
 		return nil, err
 	}
+	recordDesireResources(res)
 	return &specV1.Message{
 		Kind:     specV1.MessageDesire,
 		Metadata: msg.Metadata,
@@ -67,6 +86,49 @@ func (s *SyncAPIImpl) Desire(msg specV1.Message) (*specV1.Message, error) {
 	}, nil
 }
 
+// Delta for pushing the delta to node once its desire is changed
+func (s *SyncAPIImpl) Delta(msg specV1.Message) (*specV1.Message, error) {
+	delta, err := s.Sync.Delta(msg.Metadata["namespace"], msg.Metadata["name"])
+	if err != nil {
+		return nil, err
+	}
+	return &specV1.Message{
+		Kind:     common.MessageDelta,
+		Metadata: msg.Metadata,
+		Content:  specV1.LazyValue{Value: delta},
+	}, nil
+}
+
+// Command for node responding the result of the command
+func (s *SyncAPIImpl) Command(msg specV1.Message) (*specV1.Message, error) {
+	var result models.CommandResult
+	if err := msg.Content.Unmarshal(&result); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	cmd, err := s.Cmd.Respond(msg.Metadata["namespace"], msg.Metadata["name"], &result)
+	if err != nil {
+		return nil, err
+	}
+	return &specV1.Message{
+		Kind:     specV1.MessageCMD,
+		Metadata: msg.Metadata,
+		Content:  specV1.LazyValue{Value: cmd},
+	}, nil
+}
+
+// Commands for delivering the pending commands to node once it is connected
+func (s *SyncAPIImpl) Commands(msg specV1.Message) (*specV1.Message, error) {
+	cmds, err := s.Cmd.ListPending(msg.Metadata["namespace"], msg.Metadata["name"])
+	if err != nil {
+		return nil, err
+	}
+	return &specV1.Message{
+		Kind:     common.MessageCommands,
+		Metadata: msg.Metadata,
+		Content:  specV1.LazyValue{Value: cmds},
+	}, nil
+}
+
 func setNodeAddressIfExist(msg specV1.Message, report *specV1.Report) {
 	if addr, ok := msg.Metadata["address"]; !ok {
 		return
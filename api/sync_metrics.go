@@ -0,0 +1,103 @@
+package api
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common/metrics"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// the nodes not reported for a long time are forgotten by the gauges, e.g. deleted nodes
+const nodeReportRetention = 24 * time.Hour
+
+var (
+	syncRequests = metrics.NewCounterVec("baetyl_cloud_sync_requests_total",
+		"The number of report and desire requests of nodes.", "namespace", "type")
+	syncDesireResources = metrics.NewCounterVec("baetyl_cloud_sync_desire_resources_total",
+		"The number of resources served to the desire requests of nodes.", "kind")
+	// the nodes are tracked by the replica which they report to, a node reconnected to another replica
+	// is counted as offline by the previous one until it expires
+	nodeReports = newNodeReportTracker(nodeReportReplica(), offlineDuration, nodeReportRetention)
+	nodeStatus  = metrics.NewGaugeFunc("baetyl_cloud_nodes",
+		"The number of nodes reported to the replica since it started, by the status derived from the report time.",
+		func() map[string]float64 { return nodeReports.collect(time.Now()) }, "replica", "namespace", "status")
+)
+
+func init() {
+	metrics.MustRegister(syncRequests, syncDesireResources, nodeStatus)
+}
+
+func recordDesireResources(values []specV1.ResourceValue) {
+	for _, v := range values {
+		syncDesireResources.WithLabelValues(string(v.Kind)).Inc()
+	}
+}
+
+// nodeReportReplica returns the hostname as the replica label, which is the pod name in kubernetes
+func nodeReportReplica() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
+
+// nodeReportTracker keeps the last report time of nodes reported to this replica by namespace
+type nodeReportTracker struct {
+	replica   string
+	timeout   time.Duration
+	retention time.Duration
+	reports   map[string]map[string]time.Time
+	mu        sync.Mutex
+}
+
+func newNodeReportTracker(replica string, timeout, retention time.Duration) *nodeReportTracker {
+	return &nodeReportTracker{
+		replica:   replica,
+		timeout:   timeout,
+		retention: retention,
+		reports:   map[string]map[string]time.Time{},
+	}
+}
+
+func (t *nodeReportTracker) report(namespace, name string, reportTime time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	nodes, ok := t.reports[namespace]
+	if !ok {
+		nodes = map[string]time.Time{}
+		t.reports[namespace] = nodes
+	}
+	nodes[name] = reportTime
+}
+
+// collect returns the number of online and offline nodes by replica and namespace, the expired nodes are removed
+func (t *nodeReportTracker) collect(now time.Time) map[string]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := map[string]float64{}
+	for ns, nodes := range t.reports {
+		var online, offline float64
+		for name, reportTime := range nodes {
+			switch {
+			case now.Before(reportTime.Add(t.timeout)):
+				online++
+			case now.Before(reportTime.Add(t.retention)):
+				offline++
+			default:
+				delete(nodes, name)
+			}
+		}
+		if len(nodes) == 0 {
+			delete(t.reports, ns)
+			continue
+		}
+		res[metrics.LabelValues(t.replica, ns, models.NodeEventOnline)] = online
+		res[metrics.LabelValues(t.replica, ns, models.NodeEventOffline)] = offline
+	}
+	return res
+}
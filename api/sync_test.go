@@ -4,13 +4,18 @@ import (
 	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/metrics"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 func TestNewSyncAPI(t *testing.T) {
@@ -51,8 +56,10 @@ func TestSyncAPIImpl_Report(t *testing.T) {
 		Content:  specV1.LazyValue{},
 	}
 	mSync.EXPECT().Report("default", "test", gomock.Any()).Return(resp, nil).Times(1)
+	count := testutil.ToFloat64(syncRequests.WithLabelValues("default", "report"))
 	res, err := sync.Report(msg)
 	assert.NoError(t, err)
+	assert.Equal(t, count+1, testutil.ToFloat64(syncRequests.WithLabelValues("default", "report")))
 	assert.EqualValues(t, expMsg.Kind, res.Kind)
 	assert.EqualValues(t, expMsg.Metadata, res.Metadata)
 
@@ -80,15 +87,22 @@ func TestSyncAPIImpl_Desire(t *testing.T) {
 	assert.NoError(t, err)
 	err = msg.Content.UnmarshalJSON(bt)
 	assert.NoError(t, err)
-	resp := []specV1.ResourceValue{}
+	resp := []specV1.ResourceValue{
+		{ResourceInfo: specV1.ResourceInfo{Kind: specV1.KindApplication, Name: "app01"}},
+		{ResourceInfo: specV1.ResourceInfo{Kind: specV1.KindConfiguration, Name: "cfg01"}},
+	}
 	expMsg := &specV1.Message{
 		Kind:     msg.Kind,
 		Metadata: msg.Metadata,
 		Content:  specV1.LazyValue{},
 	}
 	mSync.EXPECT().Desire("default", nil, msg.Metadata).Return(resp, nil).Times(1)
+	count := testutil.ToFloat64(syncRequests.WithLabelValues("default", "desire"))
+	apps := testutil.ToFloat64(syncDesireResources.WithLabelValues(string(specV1.KindApplication)))
 	res, err := sync.Desire(msg)
 	assert.NoError(t, err)
+	assert.Equal(t, count+1, testutil.ToFloat64(syncRequests.WithLabelValues("default", "desire")))
+	assert.Equal(t, apps+1, testutil.ToFloat64(syncDesireResources.WithLabelValues(string(specV1.KindApplication))))
 	assert.EqualValues(t, expMsg.Kind, res.Kind)
 	assert.EqualValues(t, expMsg.Metadata, res.Metadata)
 
@@ -97,3 +111,104 @@ func TestSyncAPIImpl_Desire(t *testing.T) {
 	_, err = sync.Desire(msg)
 	assert.Error(t, err)
 }
+
+func TestNodeReportTracker(t *testing.T) {
+	tracker := newNodeReportTracker("cloud-0", time.Minute, time.Hour)
+	now := time.Now()
+	tracker.report("default", "node01", now)
+	tracker.report("default", "node02", now.Add(-time.Minute*2))
+	tracker.report("default", "node03", now.Add(-time.Hour*2))
+	tracker.report("other", "node01", now.Add(-time.Hour*2))
+	res := tracker.collect(now)
+	assert.Equal(t, map[string]float64{
+		metrics.LabelValues("cloud-0", "default", "online"):  1,
+		metrics.LabelValues("cloud-0", "default", "offline"): 1,
+	}, res)
+	assert.Len(t, tracker.reports, 1)
+	assert.Len(t, tracker.reports["default"], 2)
+
+	tracker.report("default", "node02", now)
+	res = tracker.collect(now)
+	assert.Equal(t, float64(2), res[metrics.LabelValues("cloud-0", "default", "online")])
+	assert.Equal(t, float64(0), res[metrics.LabelValues("cloud-0", "default", "offline")])
+}
+
+func TestSyncAPIImpl_Delta(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sync := &SyncAPIImpl{}
+	mSync := ms.NewMockSyncService(mockCtl)
+	sync.Sync = mSync
+
+	msg := specV1.Message{
+		Kind:     common.MessageDelta,
+		Metadata: map[string]string{"name": "test", "namespace": "default"},
+	}
+	delta := specV1.Desire{"apps": []specV1.AppInfo{{Name: "app01", Version: "v2"}}}
+	mSync.EXPECT().Delta("default", "test").Return(delta, nil)
+	res, err := sync.Delta(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, common.MessageDelta, res.Kind)
+	assert.Equal(t, delta, res.Content.Value)
+
+	mSync.EXPECT().Delta("default", "test").Return(nil, os.ErrInvalid)
+	_, err = sync.Delta(msg)
+	assert.Error(t, err)
+}
+
+func TestSyncAPIImpl_Command(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sync := &SyncAPIImpl{}
+	mCmd := ms.NewMockCommandService(mockCtl)
+	sync.Cmd = mCmd
+
+	msg := specV1.Message{
+		Kind:     specV1.MessageCMD,
+		Metadata: map[string]string{"name": "test", "namespace": "default"},
+		Content:  specV1.LazyValue{},
+	}
+	assert.NoError(t, msg.Content.UnmarshalJSON([]byte(`{"id":"c0","result":{"version":"v2"}}`)))
+	cmd := &models.Command{ID: "c0", State: models.CommandSucceeded}
+	mCmd.EXPECT().Respond("default", "test", gomock.Any()).DoAndReturn(func(_, _ string, result *models.CommandResult) (*models.Command, error) {
+		assert.Equal(t, "c0", result.ID)
+		assert.Equal(t, map[string]interface{}{"version": "v2"}, result.Result)
+		return cmd, nil
+	})
+	res, err := sync.Command(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, specV1.MessageCMD, res.Kind)
+	assert.Equal(t, cmd, res.Content.Value)
+
+	mCmd.EXPECT().Respond("default", "test", gomock.Any()).Return(nil, os.ErrInvalid)
+	_, err = sync.Command(msg)
+	assert.Error(t, err)
+
+	msg.Content = specV1.LazyValue{}
+	assert.NoError(t, msg.Content.UnmarshalJSON([]byte(`"bad"`)))
+	_, err = sync.Command(msg)
+	assert.Error(t, err)
+}
+
+func TestSyncAPIImpl_Commands(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sync := &SyncAPIImpl{}
+	mCmd := ms.NewMockCommandService(mockCtl)
+	sync.Cmd = mCmd
+
+	msg := specV1.Message{
+		Kind:     common.MessageCommands,
+		Metadata: map[string]string{"name": "test", "namespace": "default"},
+	}
+	cmds := []models.Command{{ID: "c0", State: models.CommandPending}}
+	mCmd.EXPECT().ListPending("default", "test").Return(cmds, nil)
+	res, err := sync.Commands(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, common.MessageCommands, res.Kind)
+	assert.Equal(t, cmds, res.Content.Value)
+
+	mCmd.EXPECT().ListPending("default", "test").Return(nil, os.ErrInvalid)
+	_, err = sync.Commands(msg)
+	assert.Error(t, err)
+}
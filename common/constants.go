@@ -1,5 +1,7 @@
 package common
 
+import specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
 type Resource string
 type VolumeType string
 type State string
@@ -79,6 +81,8 @@ const (
 
 	DesiredApplications    = "apps"
 	DesiredSysApplications = "sysapps"
+	// DesiredCommands the pending commands returned to the nodes synchronizing over http
+	DesiredCommands = "commands"
 
 	// Receive receive
 	Receive State = "RECEIVE"
@@ -104,6 +108,10 @@ const (
 	LabelAppName     = "baetyl-app-name"
 	LabelSystem      = "baetyl-cloud-system"
 	LabelBatch       = "baetyl-batch"
+	// LabelNodeGroup the label of the applications assigned to the node group
+	LabelNodeGroup = "baetyl-node-group"
+	// LabelNodeGroupPrefix the prefix of the labels of the members of the static node groups
+	LabelNodeGroupPrefix = "baetyl-group-"
 )
 
 const (
@@ -151,6 +159,13 @@ const (
 	EnableWhitelist           = 0x1
 )
 
+// MessageDelta the message kind of the delta pushed to nodes once their desire is changed
+const MessageDelta specV1.MessageKind = "delta"
+
+// MessageCommands the message kind of the pending commands delivered to nodes once they are connected,
+// each command is delivered as a message of kind cmd, which is also the kind of the command results of nodes
+const MessageCommands specV1.MessageKind = "commands"
+
 const (
 	Sync     CertType = "sync"
 	Internal CertType = "internal"
@@ -4,13 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
 	"github.com/baetyl/baetyl-go/v2/utils"
 	"github.com/gin-gonic/gin"
 	uuid "github.com/satori/go.uuid"
-	"gopkg.in/go-playground/validator.v9"
 )
 
 // Context context
@@ -71,6 +71,22 @@ func (c *Context) GetNameFromParam() string {
 	return c.Param("name")
 }
 
+// IsDryRun returns true if the request only validates the resource without persisting it
+func (c *Context) IsDryRun() bool {
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+	return dryRun
+}
+
+// SetDryRunHandled marks the request handled in dry run by the handler, nothing is persisted
+func (c *Context) SetDryRunHandled() {
+	c.Set("dryRunHandled", true)
+}
+
+// IsDryRunHandled returns true if the handler honored the dry run of the request
+func (c *Context) IsDryRunHandled() bool {
+	return c.GetBool("dryRunHandled")
+}
+
 // SetTrace set the trace key and value
 func (c *Context) SetTrace() {
 	k := GetTraceHeader()
@@ -92,13 +108,8 @@ func (c *Context) LoadBody(obj interface{}) error {
 	if err != nil {
 		return err
 	}
-	err = validate.Struct(obj)
+	err = ValidateStruct(obj)
 	if err != nil {
-		if es, ok := err.(validator.ValidationErrors); ok {
-			for _, v := range es {
-				return Error(Code(v.Tag()), Field(v.Tag(), v.Field()), Field("error", err.Error()))
-			}
-		}
 		return err
 	}
 	return utils.SetDefaults(obj)
@@ -132,6 +143,8 @@ func PopulateFailedResponse(cc *Context, err error, abort bool) {
 	}
 
 	log.L().Error("process failed.", log.Any(cc.GetTrace()), log.Code(err))
+	// keep the error for the middlewares, e.g. audit
+	cc.Error(err)
 
 	k, v := cc.GetTrace()
 	body := gin.H{
@@ -245,6 +258,7 @@ func WrapperMis(handler HandlerFunc) func(c *gin.Context) {
 func PopulateFailedMisResponse(cc *Context, err error, abort bool) {
 	var status int = http.StatusOK
 	log.L().Error("process failed.", log.Any(cc.GetTrace()), log.Code(err))
+	cc.Error(err)
 
 	body := gin.H{
 		"status": 1,
@@ -22,6 +22,7 @@ const (
 
 	// * request
 	ErrRequestAccessDenied   = "ErrRequestAccessDenied"
+	ErrRequestForbidden      = "ErrRequestForbidden"
 	ErrRequestMethodNotFound = "ErrRequestMethodNotFound"
 	ErrRequestParamInvalid   = "ErrRequestParamInvalid"
 	// * resource
@@ -29,6 +30,7 @@ const (
 	ErrResourceAccessForbidden = "ErrResourceAccessForbidden"
 	ErrResourceConflict        = "ErrResourceConflict"
 	ErrResourceHasBeenUsed     = "ErrResourceHasBeenUsed"
+	ErrResourceVersionConflict = "ErrResourceVersionConflict"
 	ErrNodeNotReady            = "ErrNodeNotReady"
 	ErrInvalidToken            = "ErrInvalidToken"
 
@@ -94,6 +96,7 @@ var templates = map[Code]string{
 	ErrPluginInvalid:  "The plugin {{.name}} is invalid, not implement all interfaces of {{.kind}}.",
 	// * request
 	ErrRequestAccessDenied:   "The request access is denied.",
+	ErrRequestForbidden:      "The user{{if .user}} ({{.user}}){{end}} is not allowed to {{.verb}} {{.resource}}{{if .namespace}} in namespace({{.namespace}}){{end}}.",
 	ErrRequestMethodNotFound: "The request method is not found.",
 	ErrRequestParamInvalid:   "The request parameter is invalid.{{if .error}} ({{.error}}){{end}}",
 	// * resource
@@ -101,6 +104,7 @@ var templates = map[Code]string{
 	ErrResourceAccessForbidden: `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} connot be accessed{{if .namespace}} in namespace({{.namespace}}){{end}}.`,
 	ErrResourceConflict:        `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} already exist.`,
 	ErrResourceHasBeenUsed:     `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} has been used.`,
+	ErrResourceVersionConflict: `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} has been modified, the version{{if .version}} ({{.version}}){{end}} is outdated.`,
 	// * volumes
 	ErrVolumeType: "The volume{{if .name}} ({{.name}}){{end}} type should be{{if .type}} ({{.type}}){{end}}.",
 	// * unknown
@@ -164,7 +168,7 @@ func getHTTPStatus(c Code) int {
 		return http.StatusNotFound
 	case ErrRequestAccessDenied:
 		return http.StatusUnauthorized
-	case ErrResourceHasBeenUsed:
+	case ErrResourceHasBeenUsed, ErrRequestForbidden:
 		return http.StatusForbidden
 	case ErrUnknown:
 		return http.StatusInternalServerError
@@ -0,0 +1,76 @@
+// Package metrics registers the metrics of baetyl-cloud into the prometheus client and exposes them
+package metrics
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefBuckets the default buckets of latency histograms, in seconds
+var DefBuckets = prometheus.DefBuckets
+
+// MustRegister registers the collectors into the default registry, panics if any name is registered twice
+func MustRegister(cs ...prometheus.Collector) {
+	prometheus.MustRegister(cs...)
+}
+
+// Handler serves the metrics of the default registry
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// NewCounterVec create a counter vector
+func NewCounterVec(name, help string, labels ...string) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+}
+
+// NewHistogramVec create a histogram vector, DefBuckets is used if buckets is empty
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+}
+
+// GaugeFunc the gauges collected when the metrics are scraped
+type GaugeFunc struct {
+	desc    *prometheus.Desc
+	labels  int
+	collect func() map[string]float64
+}
+
+// NewGaugeFunc create a gauge collected by the function, which returns the values keyed by the joined label values,
+// use LabelValues to build the keys
+func NewGaugeFunc(name, help string, collect func() map[string]float64, labels ...string) *GaugeFunc {
+	return &GaugeFunc{
+		desc:    prometheus.NewDesc(name, help, labels, nil),
+		labels:  len(labels),
+		collect: collect,
+	}
+}
+
+// LabelValues returns the key of the label values for GaugeFunc
+func LabelValues(values ...string) string {
+	return strings.Join(values, "\xff")
+}
+
+// Describe implements prometheus.Collector
+func (g *GaugeFunc) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+// Collect implements prometheus.Collector
+func (g *GaugeFunc) Collect(ch chan<- prometheus.Metric) {
+	for k, v := range g.collect() {
+		var values []string
+		if g.labels > 0 {
+			values = strings.Split(k, "\xff")
+		}
+		m, err := prometheus.NewConstMetric(g.desc, prometheus.GaugeValue, v, values...)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(g.desc, err)
+			continue
+		}
+		ch <- m
+	}
+}
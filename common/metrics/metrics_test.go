@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGaugeFunc(t *testing.T) {
+	g := NewGaugeFunc("test_a", "A.", func() map[string]float64 {
+		return map[string]float64{
+			LabelValues("default", "online"):  2,
+			LabelValues("default", "offline"): 1,
+		}
+	}, "namespace", "status")
+	r := prometheus.NewPedanticRegistry()
+	assert.NoError(t, r.Register(g))
+	assert.Error(t, r.Register(g))
+
+	expect := `# HELP test_a A.
+# TYPE test_a gauge
+test_a{namespace="default",status="offline"} 1
+test_a{namespace="default",status="online"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(r, strings.NewReader(expect), "test_a"))
+
+	// the keys not matching the labels are reported as errors
+	bad := NewGaugeFunc("test_b", "B.", func() map[string]float64 {
+		return map[string]float64{LabelValues("default"): 1}
+	}, "namespace", "status")
+	r = prometheus.NewRegistry()
+	assert.NoError(t, r.Register(bad))
+	_, err := r.Gather()
+	assert.Error(t, err)
+}
+
+func TestVec(t *testing.T) {
+	c := NewCounterVec("test_requests_total", "The requests.", "method")
+	c.WithLabelValues("GET").Inc()
+	c.WithLabelValues("GET").Add(2)
+	assert.Equal(t, float64(3), testutil.ToFloat64(c.WithLabelValues("GET")))
+
+	h := NewHistogramVec("test_duration_seconds", "The latency.", []float64{0.1, 1}, "type")
+	h.WithLabelValues("report").Observe(0.05)
+	h.WithLabelValues("report").Observe(5)
+	expect := `# HELP test_duration_seconds The latency.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{type="report",le="0.1"} 1
+test_duration_seconds_bucket{type="report",le="1"} 1
+test_duration_seconds_bucket{type="report",le="+Inf"} 2
+test_duration_seconds_sum{type="report"} 5.05
+test_duration_seconds_count{type="report"} 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(h, strings.NewReader(expect)))
+}
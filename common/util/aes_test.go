@@ -0,0 +1,39 @@
+package util
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveKey(t *testing.T) {
+	key := DeriveKey("password", []byte("salt"), 4096)
+	assert.Equal(t, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a", hex.EncodeToString(key))
+	key = DeriveKey("password", []byte("salt"), 1)
+	assert.Equal(t, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b", hex.EncodeToString(key))
+}
+
+func TestGCM(t *testing.T) {
+	salt, err := GenerateSalt(16)
+	assert.NoError(t, err)
+	assert.Len(t, salt, 16)
+	key := DeriveKey("passphrase", salt, 1)
+
+	c1, err := GCMEncrypt([]byte("data"), key)
+	assert.NoError(t, err)
+	c2, err := GCMEncrypt([]byte("data"), key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, c1, c2)
+
+	p, err := GCMDecrypt(c1, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "data", string(p))
+
+	_, err = GCMDecrypt(c1, DeriveKey("other", salt, 1))
+	assert.Error(t, err)
+	_, err = GCMDecrypt([]byte{1}, key)
+	assert.Equal(t, ErrCiphertextTooShort, err)
+	_, err = GCMEncrypt([]byte("data"), []byte("short"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrTokenFormat = errors.New("token format invalid")
+)
+
+// EncodeToken encodes the meta and its signature into a bearer token, as base64url(meta).base64url(sign)
+func EncodeToken(meta, sign []byte) string {
+	return base64.RawURLEncoding.EncodeToString(meta) + "." + base64.RawURLEncoding.EncodeToString(sign)
+}
+
+// DecodeToken decodes the meta and its signature from a bearer token
+func DecodeToken(token string) ([]byte, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, nil, ErrTokenFormat
+	}
+	meta, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, ErrTokenFormat
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, ErrTokenFormat
+	}
+	return meta, sign, nil
+}
+
+// GenerateAPIKey generates a random key with the prefix
+func GenerateAPIKey(prefix string) (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(b), nil
+}
+
+// HashAPIKey returns the hex encoded sha256 digest of the key, only the digest is stored
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
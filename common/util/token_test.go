@@ -0,0 +1,35 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken(t *testing.T) {
+	token := EncodeToken([]byte(`{"user":"a"}`), []byte{1, 2, 3})
+	meta, sign, err := DecodeToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"user":"a"}`, string(meta))
+	assert.Equal(t, []byte{1, 2, 3}, sign)
+
+	for _, v := range []string{"", "abc", "a.", ".b", "a.b.c", "!!.b", "a.!!"} {
+		_, _, err = DecodeToken(v)
+		assert.Equal(t, ErrTokenFormat, err, v)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	k1, err := GenerateAPIKey("bk_")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(k1, "bk_"))
+	assert.Len(t, k1, 51)
+	k2, err := GenerateAPIKey("bk_")
+	assert.NoError(t, err)
+	assert.NotEqual(t, k1, k2)
+
+	assert.Len(t, HashAPIKey(k1), 64)
+	assert.Equal(t, HashAPIKey(k1), HashAPIKey(k1))
+	assert.NotEqual(t, HashAPIKey(k1), HashAPIKey(k2))
+}
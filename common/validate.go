@@ -51,6 +51,21 @@ func init() {
 	}
 }
 
+// ValidateStruct validates the fields of the struct by their validate tags,
+// the first failed tag is returned as the code of the error
+func ValidateStruct(obj interface{}) error {
+	err := validate.Struct(obj)
+	if err != nil {
+		if es, ok := err.(validator.ValidationErrors); ok {
+			for _, v := range es {
+				return Error(Code(v.Tag()), Field(v.Tag(), v.Field()), Field("error", err.Error()))
+			}
+		}
+		return err
+	}
+	return nil
+}
+
 func genValidFunc(str string) validator.Func {
 	return func(fl validator.FieldLevel) bool {
 		match, _ := regexp.MatchString(str, fl.Field().String())
@@ -9,16 +9,42 @@ import (
 
 // CloudConfig baetyl-cloud config
 type CloudConfig struct {
-	InitServer  Server     `yaml:"initServer" json:"initServer" default:"{\"port\":\":9003\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000}"`
-	AdminServer Server     `yaml:"adminServer" json:"adminServer" default:"{\"port\":\":9004\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000}"`
-	MisServer   MisServer  `yaml:"misServer" json:"misServer" default:"{\"port\":\":9006\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"authToken\":\"baetyl-cloud-token\",\"tokenHeader\":\"baetyl-cloud-token\",\"userHeader\":\"baetyl-cloud-user\"}"`
-	LogInfo     log.Config `yaml:"logger" json:"logger"`
-	Cache       struct {
+	InitServer    Server     `yaml:"initServer" json:"initServer" default:"{\"port\":\":9003\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000}"`
+	AdminServer   Server     `yaml:"adminServer" json:"adminServer" default:"{\"port\":\":9004\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000}"`
+	MisServer     MisServer  `yaml:"misServer" json:"misServer" default:"{\"port\":\":9006\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"authToken\":\"baetyl-cloud-token\",\"tokenHeader\":\"baetyl-cloud-token\",\"userHeader\":\"baetyl-cloud-user\"}"`
+	MetricsServer Server     `yaml:"metricsServer" json:"metricsServer" default:"{\"port\":\":9007\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000}"`
+	LogInfo       log.Config `yaml:"logger" json:"logger"`
+	Cache         struct {
 		ExpirationDuration time.Duration `yaml:"expirationDuration" json:"expirationDuration" default:"10m"`
 	} `yaml:"cache" json:"cache"`
 	Template struct {
 		Path string `yaml:"path" json:"path" default:"/etc/baetyl/templates"`
 	} `yaml:"template" json:"template"`
+	Callback struct {
+		Timeout    time.Duration `yaml:"timeout" json:"timeout" default:"10s"`
+		Retries    int           `yaml:"retries" json:"retries" default:"3"`
+		Backoff    time.Duration `yaml:"backoff" json:"backoff" default:"1s"`
+		MaxBackoff time.Duration `yaml:"maxBackoff" json:"maxBackoff" default:"30s"`
+		Workers    int           `yaml:"workers" json:"workers" default:"8"`
+		QueueSize  int           `yaml:"queueSize" json:"queueSize" default:"1024"`
+		// LogLimit the number of the latest delivery logs kept for each callback, all logs are kept if it is 0
+		LogLimit int `yaml:"logLimit" json:"logLimit" default:"1000"`
+	} `yaml:"callback" json:"callback"`
+	Rollout struct {
+		Interval time.Duration `yaml:"interval" json:"interval" default:"10s"`
+		// LockTimeout the lease of the lock, which keeps the other replicas from reconciling the rollouts meanwhile
+		LockTimeout time.Duration `yaml:"lockTimeout" json:"lockTimeout" default:"5m"`
+	} `yaml:"rollout" json:"rollout"`
+	CertManager struct {
+		Interval    time.Duration `yaml:"interval" json:"interval" default:"1h"`
+		RenewBefore time.Duration `yaml:"renewBefore" json:"renewBefore" default:"720h"`
+		// LockTimeout the lease of the lock, which keeps the other replicas from renewing the certificates meanwhile
+		LockTimeout time.Duration `yaml:"lockTimeout" json:"lockTimeout" default:"30m"`
+	} `yaml:"certManager" json:"certManager"`
+	RBAC struct {
+		Enabled    bool     `yaml:"enabled" json:"enabled"`
+		SuperUsers []string `yaml:"superUsers" json:"superUsers" default:"[]"`
+	} `yaml:"rbac" json:"rbac"`
 	Plugin struct {
 		Pubsub    string   `yaml:"pubsub" json:"pubsub" default:"defaultpubsub"`
 		PKI       string   `yaml:"pki" json:"pki" default:"defaultpki"`
@@ -29,6 +55,8 @@ type CloudConfig struct {
 		Functions []string `yaml:"functions" json:"functions" default:"[]"`
 		Property  string   `yaml:"property" json:"property" default:"database"`
 		SyncLinks []string `yaml:"synclinks" json:"synclinks" default:"[\"httplink\"]"`
+		// KMS the kms plugin to encrypt the secrets and the private keys at rest, the encryption is disabled if empty
+		KMS string `yaml:"kms" json:"kms"`
 		// TODO: deprecated
 
 		ModelStorage    string `yaml:"modelStorage" json:"modelStorage" default:"kube"`
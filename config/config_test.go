@@ -28,6 +28,11 @@ func TestDefaultValue(t *testing.T) {
 	expect.MisServer.TokenHeader = "baetyl-cloud-token"
 	expect.MisServer.UserHeader = "baetyl-cloud-user"
 
+	expect.MetricsServer.Port = ":9007"
+	expect.MetricsServer.WriteTimeout = time.Second * 30
+	expect.MetricsServer.ReadTimeout = time.Second * 30
+	expect.MetricsServer.ShutdownTime = time.Second * 3
+
 	expect.LogInfo.Level = "info"
 	expect.LogInfo.MaxAge = 15
 	expect.LogInfo.MaxSize = 50
@@ -49,6 +54,19 @@ func TestDefaultValue(t *testing.T) {
 	expect.Template.Path = "/etc/baetyl/templates"
 
 	expect.Cache.ExpirationDuration = time.Minute * 10
+	expect.Callback.Timeout = time.Second * 10
+	expect.Callback.Retries = 3
+	expect.Callback.Backoff = time.Second
+	expect.Callback.MaxBackoff = time.Second * 30
+	expect.Callback.Workers = 8
+	expect.Callback.QueueSize = 1024
+	expect.Callback.LogLimit = 1000
+	expect.Rollout.Interval = time.Second * 10
+	expect.Rollout.LockTimeout = time.Minute * 5
+	expect.CertManager.Interval = time.Hour
+	expect.CertManager.LockTimeout = time.Minute * 30
+	expect.CertManager.RenewBefore = time.Hour * 720
+	expect.RBAC.SuperUsers = []string{}
 	// case 0
 	cfg := &CloudConfig{}
 	err := utils.UnmarshalYAML(nil, cfg)
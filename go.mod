@@ -1,22 +1,30 @@
 module github.com/baetyl/baetyl-cloud/v2
 
-go 1.13
+go 1.16
 
 require (
+	github.com/256dpi/gomqtt v0.14.2
 	github.com/aws/aws-sdk-go v1.32.8
 	github.com/baetyl/baetyl-go/v2 v2.0.56
 	github.com/gin-contrib/cache v1.1.0
+	github.com/gin-contrib/sse v0.1.0
 	github.com/gin-gonic/gin v1.6.3
 	github.com/go-sql-driver/mysql v1.5.0
 	github.com/golang/mock v1.2.0
+	github.com/gomodule/redigo v2.0.0+incompatible
 	github.com/google/uuid v1.1.1
+	github.com/gorilla/websocket v1.4.1
 	github.com/imdario/mergo v0.3.9 // indirect
 	github.com/jinzhu/copier v0.0.0-20190924061706-b57f9002281a
 	github.com/jmoiron/sqlx v1.2.0
+	github.com/lib/pq v1.0.0
 	github.com/mattn/go-sqlite3 v1.14.0
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.5.1
+	github.com/prometheus/client_model v0.2.0
 	github.com/satori/go.uuid v1.2.0
 	github.com/stretchr/testify v1.5.1
+	golang.org/x/crypto v0.0.0-20190510104115-cbcb75029529
 	golang.org/x/tools v0.0.0-20191205225056-3393d29bb9fe // indirect
 	gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
 	gopkg.in/go-playground/validator.v9 v9.31.0
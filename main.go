@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"runtime"
 
 	"github.com/baetyl/baetyl-go/v2/context"
@@ -13,14 +14,22 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/server"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/apikey"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/awss3"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/database"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/auth"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/kms"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/license"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/pki"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/pubsub"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/kube"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/link/httplink"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/link/mqttlink"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/link/wslink"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/redis"
 )
 
 func main() {
@@ -38,6 +47,15 @@ func main() {
 
 		common.SetConfFile(ctx.ConfFile())
 
+		if args := flag.Args(); len(args) > 0 {
+			switch args[0] {
+			case "migrate":
+				return migrate(args[1:])
+			case "rotate":
+				return rotate(&cfg, args[1:])
+			}
+		}
+
 		a, err := api.NewAPI(&cfg)
 		if err != nil {
 			return err
@@ -46,7 +64,7 @@ func main() {
 		if err != nil {
 			return err
 		}
-		ia, err := api.NewInitAPI(&cfg)
+		ia, err := api.NewInitAPI(&cfg, a.Callback)
 		if err != nil {
 			return err
 		}
@@ -79,6 +97,31 @@ func main() {
 		defer as.Close()
 		ctx.Log().Info("init  server starting")
 
+		ms, err := server.NewMetricsServer(&cfg)
+		if err != nil {
+			return err
+		}
+		ms.InitRoute()
+		go ms.Run()
+		defer ms.Close()
+		ctx.Log().Info("metrics server starting")
+
+		rc, err := server.NewRolloutController(&cfg)
+		if err != nil {
+			return err
+		}
+		go rc.Run()
+		defer rc.Close()
+		ctx.Log().Info("rollout controller starting")
+
+		cc, err := server.NewCertController(&cfg)
+		if err != nil {
+			return err
+		}
+		go cc.Run()
+		defer cc.Close()
+		ctx.Log().Info("certificate controller starting")
+
 		ctx.Wait()
 		return nil
 	})
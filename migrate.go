@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database"
+)
+
+const migrateUsage = "usage: baetyl-cloud [-c conf.yml] migrate up [steps] | down [steps] | status"
+
+// migrate applies, reverts or shows the migrations of the schema of the database,
+// all pending migrations are applied by up and the latest migration is reverted by down if steps is omitted
+func migrate(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(migrateUsage)
+	}
+	var steps int
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return errors.New(migrateUsage)
+		}
+		steps = n
+	}
+
+	m, err := database.NewMigrator()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	var res []database.MigrationStatus
+	switch args[0] {
+	case "up":
+		res, err = m.Up(steps)
+	case "down":
+		if steps == 0 {
+			steps = 1
+		}
+		res, err = m.Down(steps)
+	case "status":
+		res, err = m.Status()
+	default:
+		return errors.New(migrateUsage)
+	}
+	for _, s := range res {
+		applyTime := "-"
+		if !s.ApplyTime.IsZero() {
+			applyTime = s.ApplyTime.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-8d %-8t %-20s %s\n", s.Version, s.Applied, applyTime, s.Description)
+	}
+	return err
+}
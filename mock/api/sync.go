@@ -33,6 +33,51 @@ func (m *MockSyncAPI) EXPECT() *MockSyncAPIMockRecorder {
 	return m.recorder
 }
 
+// Command mocks base method
+func (m *MockSyncAPI) Command(arg0 v1.Message) (*v1.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Command", arg0)
+	ret0, _ := ret[0].(*v1.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Command indicates an expected call of Command
+func (mr *MockSyncAPIMockRecorder) Command(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Command", reflect.TypeOf((*MockSyncAPI)(nil).Command), arg0)
+}
+
+// Commands mocks base method
+func (m *MockSyncAPI) Commands(arg0 v1.Message) (*v1.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commands", arg0)
+	ret0, _ := ret[0].(*v1.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Commands indicates an expected call of Commands
+func (mr *MockSyncAPIMockRecorder) Commands(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commands", reflect.TypeOf((*MockSyncAPI)(nil).Commands), arg0)
+}
+
+// Delta mocks base method
+func (m *MockSyncAPI) Delta(arg0 v1.Message) (*v1.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delta", arg0)
+	ret0, _ := ret[0].(*v1.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delta indicates an expected call of Delta
+func (mr *MockSyncAPIMockRecorder) Delta(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delta", reflect.TypeOf((*MockSyncAPI)(nil).Delta), arg0)
+}
+
 // Desire mocks base method
 func (m *MockSyncAPI) Desire(arg0 v1.Message) (*v1.Message, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: KMS)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockKMS is a mock of KMS interface
+type MockKMS struct {
+	ctrl     *gomock.Controller
+	recorder *MockKMSMockRecorder
+}
+
+// MockKMSMockRecorder is the mock recorder for MockKMS
+type MockKMSMockRecorder struct {
+	mock *MockKMS
+}
+
+// NewMockKMS creates a new mock instance
+func NewMockKMS(ctrl *gomock.Controller) *MockKMS {
+	mock := &MockKMS{ctrl: ctrl}
+	mock.recorder = &MockKMSMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockKMS) EXPECT() *MockKMSMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockKMS) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockKMSMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockKMS)(nil).Close))
+}
+
+// CurrentKeyID mocks base method
+func (m *MockKMS) CurrentKeyID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentKeyID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CurrentKeyID indicates an expected call of CurrentKeyID
+func (mr *MockKMSMockRecorder) CurrentKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentKeyID", reflect.TypeOf((*MockKMS)(nil).CurrentKeyID))
+}
+
+// Decrypt mocks base method
+func (m *MockKMS) Decrypt(arg0 string, arg1 []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrypt", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decrypt indicates an expected call of Decrypt
+func (mr *MockKMSMockRecorder) Decrypt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrypt", reflect.TypeOf((*MockKMS)(nil).Decrypt), arg0, arg1)
+}
+
+// Encrypt mocks base method
+func (m *MockKMS) Encrypt(arg0 []byte) (string, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encrypt", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Encrypt indicates an expected call of Encrypt
+func (mr *MockKMSMockRecorder) Encrypt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encrypt", reflect.TypeOf((*MockKMS)(nil).Encrypt), arg0)
+}
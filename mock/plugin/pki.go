@@ -9,6 +9,7 @@ import (
 	plugin "github.com/baetyl/baetyl-cloud/v2/plugin"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockPKI is a mock of PKI interface
@@ -180,6 +181,36 @@ func (mr *MockPKIMockRecorder) DeleteClientCert(certId interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientCert", reflect.TypeOf((*MockPKI)(nil).DeleteClientCert), certId)
 }
 
+// ListCertByNotAfter mocks base method
+func (m *MockPKI) ListCertByNotAfter(before time.Time) ([]plugin.Cert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCertByNotAfter", before)
+	ret0, _ := ret[0].([]plugin.Cert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCertByNotAfter indicates an expected call of ListCertByNotAfter
+func (mr *MockPKIMockRecorder) ListCertByNotAfter(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCertByNotAfter", reflect.TypeOf((*MockPKI)(nil).ListCertByNotAfter), before)
+}
+
+// RotatePrivateKeys mocks base method
+func (m *MockPKI) RotatePrivateKeys() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotatePrivateKeys")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotatePrivateKeys indicates an expected call of RotatePrivateKeys
+func (mr *MockPKIMockRecorder) RotatePrivateKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotatePrivateKeys", reflect.TypeOf((*MockPKI)(nil).RotatePrivateKeys))
+}
+
 // Close mocks base method
 func (m *MockPKI) Close() error {
 	m.ctrl.T.Helper()
@@ -289,6 +320,36 @@ func (mr *MockPKIStorageMockRecorder) CountCertByParentId(parentId interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCertByParentId", reflect.TypeOf((*MockPKIStorage)(nil).CountCertByParentId), parentId)
 }
 
+// ListCertByNotAfter mocks base method
+func (m *MockPKIStorage) ListCertByNotAfter(before time.Time) ([]plugin.Cert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCertByNotAfter", before)
+	ret0, _ := ret[0].([]plugin.Cert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCertByNotAfter indicates an expected call of ListCertByNotAfter
+func (mr *MockPKIStorageMockRecorder) ListCertByNotAfter(before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCertByNotAfter", reflect.TypeOf((*MockPKIStorage)(nil).ListCertByNotAfter), before)
+}
+
+// ListCertWithPrivateKey mocks base method
+func (m *MockPKIStorage) ListCertWithPrivateKey() ([]plugin.Cert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCertWithPrivateKey")
+	ret0, _ := ret[0].([]plugin.Cert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCertWithPrivateKey indicates an expected call of ListCertWithPrivateKey
+func (mr *MockPKIStorageMockRecorder) ListCertWithPrivateKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCertWithPrivateKey", reflect.TypeOf((*MockPKIStorage)(nil).ListCertWithPrivateKey))
+}
+
 // Close mocks base method
 func (m *MockPKIStorage) Close() error {
 	m.ctrl.T.Helper()
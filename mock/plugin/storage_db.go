@@ -12,6 +12,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	sqlx "github.com/jmoiron/sqlx"
 	reflect "reflect"
+	time "time"
 )
 
 // MockDBStorage is a mock of DBStorage interface
@@ -37,6 +38,36 @@ func (m *MockDBStorage) EXPECT() *MockDBStorageMockRecorder {
 	return m.recorder
 }
 
+// ActivateRecord mocks base method
+func (m *MockDBStorage) ActivateRecord(arg0 *models.Record) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateRecord", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateRecord indicates an expected call of ActivateRecord
+func (mr *MockDBStorageMockRecorder) ActivateRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateRecord", reflect.TypeOf((*MockDBStorage)(nil).ActivateRecord), arg0)
+}
+
+// ActivateRecordTx mocks base method
+func (m *MockDBStorage) ActivateRecordTx(arg0 *sqlx.Tx, arg1 *models.Record) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateRecordTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateRecordTx indicates an expected call of ActivateRecordTx
+func (mr *MockDBStorageMockRecorder) ActivateRecordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateRecordTx", reflect.TypeOf((*MockDBStorage)(nil).ActivateRecordTx), arg0, arg1)
+}
+
 // Close mocks base method
 func (m *MockDBStorage) Close() error {
 	m.ctrl.T.Helper()
@@ -51,6 +82,36 @@ func (mr *MockDBStorageMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDBStorage)(nil).Close))
 }
 
+// CountAPIKey mocks base method
+func (m *MockDBStorage) CountAPIKey(arg0, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAPIKey indicates an expected call of CountAPIKey
+func (mr *MockDBStorageMockRecorder) CountAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAPIKey", reflect.TypeOf((*MockDBStorage)(nil).CountAPIKey), arg0, arg1)
+}
+
+// CountAPIKeyTx mocks base method
+func (m *MockDBStorage) CountAPIKeyTx(arg0 *sqlx.Tx, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAPIKeyTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAPIKeyTx indicates an expected call of CountAPIKeyTx
+func (mr *MockDBStorageMockRecorder) CountAPIKeyTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAPIKeyTx", reflect.TypeOf((*MockDBStorage)(nil).CountAPIKeyTx), arg0, arg1, arg2)
+}
+
 // CountApplication mocks base method
 func (m *MockDBStorage) CountApplication(arg0 *sqlx.Tx, arg1, arg2 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +127,51 @@ func (mr *MockDBStorageMockRecorder) CountApplication(arg0, arg1, arg2 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountApplication", reflect.TypeOf((*MockDBStorage)(nil).CountApplication), arg0, arg1, arg2)
 }
 
+// CountApplicationHistory mocks base method
+func (m *MockDBStorage) CountApplicationHistory(arg0, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountApplicationHistory", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountApplicationHistory indicates an expected call of CountApplicationHistory
+func (mr *MockDBStorageMockRecorder) CountApplicationHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountApplicationHistory", reflect.TypeOf((*MockDBStorage)(nil).CountApplicationHistory), arg0, arg1)
+}
+
+// CountAuditLog mocks base method
+func (m *MockDBStorage) CountAuditLog(arg0 *models.AuditFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAuditLog", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAuditLog indicates an expected call of CountAuditLog
+func (mr *MockDBStorageMockRecorder) CountAuditLog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuditLog", reflect.TypeOf((*MockDBStorage)(nil).CountAuditLog), arg0)
+}
+
+// CountAuditLogTx mocks base method
+func (m *MockDBStorage) CountAuditLogTx(arg0 *sqlx.Tx, arg1 *models.AuditFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAuditLogTx", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAuditLogTx indicates an expected call of CountAuditLogTx
+func (mr *MockDBStorageMockRecorder) CountAuditLogTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuditLogTx", reflect.TypeOf((*MockDBStorage)(nil).CountAuditLogTx), arg0, arg1)
+}
+
 // CountBatch mocks base method
 func (m *MockDBStorage) CountBatch(arg0, arg1 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +232,156 @@ func (mr *MockDBStorageMockRecorder) CountBatchTx(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBatchTx", reflect.TypeOf((*MockDBStorage)(nil).CountBatchTx), arg0, arg1, arg2)
 }
 
+// CountCallback mocks base method
+func (m *MockDBStorage) CountCallback(arg0, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCallback", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCallback indicates an expected call of CountCallback
+func (mr *MockDBStorageMockRecorder) CountCallback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCallback", reflect.TypeOf((*MockDBStorage)(nil).CountCallback), arg0, arg1)
+}
+
+// CountCallbackLog mocks base method
+func (m *MockDBStorage) CountCallbackLog(arg0, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCallbackLog", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCallbackLog indicates an expected call of CountCallbackLog
+func (mr *MockDBStorageMockRecorder) CountCallbackLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCallbackLog", reflect.TypeOf((*MockDBStorage)(nil).CountCallbackLog), arg0, arg1)
+}
+
+// CountCallbackLogTx mocks base method
+func (m *MockDBStorage) CountCallbackLogTx(arg0 *sqlx.Tx, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCallbackLogTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCallbackLogTx indicates an expected call of CountCallbackLogTx
+func (mr *MockDBStorageMockRecorder) CountCallbackLogTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCallbackLogTx", reflect.TypeOf((*MockDBStorage)(nil).CountCallbackLogTx), arg0, arg1, arg2)
+}
+
+// CountCallbackTx mocks base method
+func (m *MockDBStorage) CountCallbackTx(arg0 *sqlx.Tx, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCallbackTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCallbackTx indicates an expected call of CountCallbackTx
+func (mr *MockDBStorageMockRecorder) CountCallbackTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCallbackTx", reflect.TypeOf((*MockDBStorage)(nil).CountCallbackTx), arg0, arg1, arg2)
+}
+
+// CountNodeDeployHistory mocks base method
+func (m *MockDBStorage) CountNodeDeployHistory(arg0, arg1 string, arg2 *models.NodeDeployFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNodeDeployHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNodeDeployHistory indicates an expected call of CountNodeDeployHistory
+func (mr *MockDBStorageMockRecorder) CountNodeDeployHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNodeDeployHistory", reflect.TypeOf((*MockDBStorage)(nil).CountNodeDeployHistory), arg0, arg1, arg2)
+}
+
+// CountNodeDeployHistoryTx mocks base method
+func (m *MockDBStorage) CountNodeDeployHistoryTx(arg0 *sqlx.Tx, arg1, arg2 string, arg3 *models.NodeDeployFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNodeDeployHistoryTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNodeDeployHistoryTx indicates an expected call of CountNodeDeployHistoryTx
+func (mr *MockDBStorageMockRecorder) CountNodeDeployHistoryTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNodeDeployHistoryTx", reflect.TypeOf((*MockDBStorage)(nil).CountNodeDeployHistoryTx), arg0, arg1, arg2, arg3)
+}
+
+// CountNodeGroup mocks base method
+func (m *MockDBStorage) CountNodeGroup(arg0, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNodeGroup", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNodeGroup indicates an expected call of CountNodeGroup
+func (mr *MockDBStorageMockRecorder) CountNodeGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNodeGroup", reflect.TypeOf((*MockDBStorage)(nil).CountNodeGroup), arg0, arg1)
+}
+
+// CountNodeGroupTx mocks base method
+func (m *MockDBStorage) CountNodeGroupTx(arg0 *sqlx.Tx, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNodeGroupTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNodeGroupTx indicates an expected call of CountNodeGroupTx
+func (mr *MockDBStorageMockRecorder) CountNodeGroupTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNodeGroupTx", reflect.TypeOf((*MockDBStorage)(nil).CountNodeGroupTx), arg0, arg1, arg2)
+}
+
+// CountNodeTask mocks base method
+func (m *MockDBStorage) CountNodeTask(arg0, arg1 string, arg2 *models.TaskFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNodeTask", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNodeTask indicates an expected call of CountNodeTask
+func (mr *MockDBStorageMockRecorder) CountNodeTask(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNodeTask", reflect.TypeOf((*MockDBStorage)(nil).CountNodeTask), arg0, arg1, arg2)
+}
+
+// CountNodeTaskTx mocks base method
+func (m *MockDBStorage) CountNodeTaskTx(arg0 *sqlx.Tx, arg1, arg2 string, arg3 *models.TaskFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNodeTaskTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNodeTaskTx indicates an expected call of CountNodeTaskTx
+func (mr *MockDBStorageMockRecorder) CountNodeTaskTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNodeTaskTx", reflect.TypeOf((*MockDBStorage)(nil).CountNodeTaskTx), arg0, arg1, arg2, arg3)
+}
+
 // CountRecord mocks base method
 func (m *MockDBStorage) CountRecord(arg0, arg1, arg2 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -156,6 +412,66 @@ func (mr *MockDBStorageMockRecorder) CountRecordTx(arg0, arg1, arg2, arg3 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecordTx", reflect.TypeOf((*MockDBStorage)(nil).CountRecordTx), arg0, arg1, arg2, arg3)
 }
 
+// CountRoleBinding mocks base method
+func (m *MockDBStorage) CountRoleBinding(arg0, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRoleBinding", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRoleBinding indicates an expected call of CountRoleBinding
+func (mr *MockDBStorageMockRecorder) CountRoleBinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRoleBinding", reflect.TypeOf((*MockDBStorage)(nil).CountRoleBinding), arg0, arg1)
+}
+
+// CountRoleBindingTx mocks base method
+func (m *MockDBStorage) CountRoleBindingTx(arg0 *sqlx.Tx, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRoleBindingTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRoleBindingTx indicates an expected call of CountRoleBindingTx
+func (mr *MockDBStorageMockRecorder) CountRoleBindingTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRoleBindingTx", reflect.TypeOf((*MockDBStorage)(nil).CountRoleBindingTx), arg0, arg1, arg2)
+}
+
+// CountRollout mocks base method
+func (m *MockDBStorage) CountRollout(arg0, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRollout", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRollout indicates an expected call of CountRollout
+func (mr *MockDBStorageMockRecorder) CountRollout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRollout", reflect.TypeOf((*MockDBStorage)(nil).CountRollout), arg0, arg1)
+}
+
+// CountRolloutTx mocks base method
+func (m *MockDBStorage) CountRolloutTx(arg0 *sqlx.Tx, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRolloutTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRolloutTx indicates an expected call of CountRolloutTx
+func (mr *MockDBStorageMockRecorder) CountRolloutTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRolloutTx", reflect.TypeOf((*MockDBStorage)(nil).CountRolloutTx), arg0, arg1, arg2)
+}
+
 // CountTask mocks base method
 func (m *MockDBStorage) CountTask(arg0 *models.Task) (int, error) {
 	m.ctrl.T.Helper()
@@ -186,6 +502,36 @@ func (mr *MockDBStorageMockRecorder) Create(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDBStorage)(nil).Create), arg0)
 }
 
+// CreateAPIKey mocks base method
+func (m *MockDBStorage) CreateAPIKey(arg0 *models.APIKey) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey
+func (mr *MockDBStorageMockRecorder) CreateAPIKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockDBStorage)(nil).CreateAPIKey), arg0)
+}
+
+// CreateAPIKeyTx mocks base method
+func (m *MockDBStorage) CreateAPIKeyTx(arg0 *sqlx.Tx, arg1 *models.APIKey) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKeyTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKeyTx indicates an expected call of CreateAPIKeyTx
+func (mr *MockDBStorageMockRecorder) CreateAPIKeyTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKeyTx", reflect.TypeOf((*MockDBStorage)(nil).CreateAPIKeyTx), arg0, arg1)
+}
+
 // CreateApplication mocks base method
 func (m *MockDBStorage) CreateApplication(arg0 *v1.Application) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -216,6 +562,36 @@ func (mr *MockDBStorageMockRecorder) CreateApplicationWithTx(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplicationWithTx", reflect.TypeOf((*MockDBStorage)(nil).CreateApplicationWithTx), arg0, arg1)
 }
 
+// CreateAuditLog mocks base method
+func (m *MockDBStorage) CreateAuditLog(arg0 *models.AuditLog) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog
+func (mr *MockDBStorageMockRecorder) CreateAuditLog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*MockDBStorage)(nil).CreateAuditLog), arg0)
+}
+
+// CreateAuditLogTx mocks base method
+func (m *MockDBStorage) CreateAuditLogTx(arg0 *sqlx.Tx, arg1 *models.AuditLog) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLogTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLogTx indicates an expected call of CreateAuditLogTx
+func (mr *MockDBStorageMockRecorder) CreateAuditLogTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLogTx", reflect.TypeOf((*MockDBStorage)(nil).CreateAuditLogTx), arg0, arg1)
+}
+
 // CreateBatch mocks base method
 func (m *MockDBStorage) CreateBatch(arg0 *models.Batch) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -261,6 +637,36 @@ func (mr *MockDBStorageMockRecorder) CreateCallback(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCallback", reflect.TypeOf((*MockDBStorage)(nil).CreateCallback), arg0)
 }
 
+// CreateCallbackLog mocks base method
+func (m *MockDBStorage) CreateCallbackLog(arg0 *models.CallbackLog) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCallbackLog", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCallbackLog indicates an expected call of CreateCallbackLog
+func (mr *MockDBStorageMockRecorder) CreateCallbackLog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCallbackLog", reflect.TypeOf((*MockDBStorage)(nil).CreateCallbackLog), arg0)
+}
+
+// CreateCallbackLogTx mocks base method
+func (m *MockDBStorage) CreateCallbackLogTx(arg0 *sqlx.Tx, arg1 *models.CallbackLog) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCallbackLogTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCallbackLogTx indicates an expected call of CreateCallbackLogTx
+func (mr *MockDBStorageMockRecorder) CreateCallbackLogTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCallbackLogTx", reflect.TypeOf((*MockDBStorage)(nil).CreateCallbackLogTx), arg0, arg1)
+}
+
 // CreateCallbackTx mocks base method
 func (m *MockDBStorage) CreateCallbackTx(arg0 *sqlx.Tx, arg1 *models.Callback) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -306,37 +712,187 @@ func (mr *MockDBStorageMockRecorder) CreateIndexTx(arg0, arg1, arg2, arg3, arg4,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIndexTx", reflect.TypeOf((*MockDBStorage)(nil).CreateIndexTx), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
-// CreateRecord mocks base method
-func (m *MockDBStorage) CreateRecord(arg0 []models.Record) (sql.Result, error) {
+// CreateLock mocks base method
+func (m *MockDBStorage) CreateLock(arg0 *models.Lock) (sql.Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRecord", arg0)
+	ret := m.ctrl.Call(m, "CreateLock", arg0)
 	ret0, _ := ret[0].(sql.Result)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateRecord indicates an expected call of CreateRecord
-func (mr *MockDBStorageMockRecorder) CreateRecord(arg0 interface{}) *gomock.Call {
+// CreateLock indicates an expected call of CreateLock
+func (mr *MockDBStorageMockRecorder) CreateLock(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecord", reflect.TypeOf((*MockDBStorage)(nil).CreateRecord), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLock", reflect.TypeOf((*MockDBStorage)(nil).CreateLock), arg0)
 }
 
-// CreateRecordTx mocks base method
-func (m *MockDBStorage) CreateRecordTx(arg0 *sqlx.Tx, arg1 []models.Record) (sql.Result, error) {
+// CreateLockTx mocks base method
+func (m *MockDBStorage) CreateLockTx(arg0 *sqlx.Tx, arg1 *models.Lock) (sql.Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRecordTx", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateLockTx", arg0, arg1)
 	ret0, _ := ret[0].(sql.Result)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateRecordTx indicates an expected call of CreateRecordTx
-func (mr *MockDBStorageMockRecorder) CreateRecordTx(arg0, arg1 interface{}) *gomock.Call {
+// CreateLockTx indicates an expected call of CreateLockTx
+func (mr *MockDBStorageMockRecorder) CreateLockTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecordTx", reflect.TypeOf((*MockDBStorage)(nil).CreateRecordTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLockTx", reflect.TypeOf((*MockDBStorage)(nil).CreateLockTx), arg0, arg1)
 }
 
-// CreateTask mocks base method
+// CreateNodeDeployHistory mocks base method
+func (m *MockDBStorage) CreateNodeDeployHistory(arg0 []models.NodeDeployHistory) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNodeDeployHistory", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNodeDeployHistory indicates an expected call of CreateNodeDeployHistory
+func (mr *MockDBStorageMockRecorder) CreateNodeDeployHistory(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodeDeployHistory", reflect.TypeOf((*MockDBStorage)(nil).CreateNodeDeployHistory), arg0)
+}
+
+// CreateNodeDeployHistoryTx mocks base method
+func (m *MockDBStorage) CreateNodeDeployHistoryTx(arg0 *sqlx.Tx, arg1 []models.NodeDeployHistory) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNodeDeployHistoryTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNodeDeployHistoryTx indicates an expected call of CreateNodeDeployHistoryTx
+func (mr *MockDBStorageMockRecorder) CreateNodeDeployHistoryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodeDeployHistoryTx", reflect.TypeOf((*MockDBStorage)(nil).CreateNodeDeployHistoryTx), arg0, arg1)
+}
+
+// CreateNodeGroup mocks base method
+func (m *MockDBStorage) CreateNodeGroup(arg0 *models.NodeGroup) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNodeGroup", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNodeGroup indicates an expected call of CreateNodeGroup
+func (mr *MockDBStorageMockRecorder) CreateNodeGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodeGroup", reflect.TypeOf((*MockDBStorage)(nil).CreateNodeGroup), arg0)
+}
+
+// CreateNodeGroupTx mocks base method
+func (m *MockDBStorage) CreateNodeGroupTx(arg0 *sqlx.Tx, arg1 *models.NodeGroup) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNodeGroupTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNodeGroupTx indicates an expected call of CreateNodeGroupTx
+func (mr *MockDBStorageMockRecorder) CreateNodeGroupTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodeGroupTx", reflect.TypeOf((*MockDBStorage)(nil).CreateNodeGroupTx), arg0, arg1)
+}
+
+// CreateRecord mocks base method
+func (m *MockDBStorage) CreateRecord(arg0 []models.Record) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecord", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecord indicates an expected call of CreateRecord
+func (mr *MockDBStorageMockRecorder) CreateRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecord", reflect.TypeOf((*MockDBStorage)(nil).CreateRecord), arg0)
+}
+
+// CreateRecordTx mocks base method
+func (m *MockDBStorage) CreateRecordTx(arg0 *sqlx.Tx, arg1 []models.Record) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecordTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecordTx indicates an expected call of CreateRecordTx
+func (mr *MockDBStorageMockRecorder) CreateRecordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecordTx", reflect.TypeOf((*MockDBStorage)(nil).CreateRecordTx), arg0, arg1)
+}
+
+// CreateRoleBinding mocks base method
+func (m *MockDBStorage) CreateRoleBinding(arg0 *models.RoleBinding) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRoleBinding", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRoleBinding indicates an expected call of CreateRoleBinding
+func (mr *MockDBStorageMockRecorder) CreateRoleBinding(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRoleBinding", reflect.TypeOf((*MockDBStorage)(nil).CreateRoleBinding), arg0)
+}
+
+// CreateRoleBindingTx mocks base method
+func (m *MockDBStorage) CreateRoleBindingTx(arg0 *sqlx.Tx, arg1 *models.RoleBinding) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRoleBindingTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRoleBindingTx indicates an expected call of CreateRoleBindingTx
+func (mr *MockDBStorageMockRecorder) CreateRoleBindingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRoleBindingTx", reflect.TypeOf((*MockDBStorage)(nil).CreateRoleBindingTx), arg0, arg1)
+}
+
+// CreateRollout mocks base method
+func (m *MockDBStorage) CreateRollout(arg0 *models.Rollout) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRollout", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRollout indicates an expected call of CreateRollout
+func (mr *MockDBStorageMockRecorder) CreateRollout(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRollout", reflect.TypeOf((*MockDBStorage)(nil).CreateRollout), arg0)
+}
+
+// CreateRolloutTx mocks base method
+func (m *MockDBStorage) CreateRolloutTx(arg0 *sqlx.Tx, arg1 *models.Rollout) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRolloutTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRolloutTx indicates an expected call of CreateRolloutTx
+func (mr *MockDBStorageMockRecorder) CreateRolloutTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRolloutTx", reflect.TypeOf((*MockDBStorage)(nil).CreateRolloutTx), arg0, arg1)
+}
+
+// CreateTask mocks base method
 func (m *MockDBStorage) CreateTask(arg0 *models.Task) (sql.Result, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateTask", arg0)
@@ -380,6 +936,36 @@ func (mr *MockDBStorageMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDBStorage)(nil).Delete), arg0, arg1)
 }
 
+// DeleteAPIKey mocks base method
+func (m *MockDBStorage) DeleteAPIKey(arg0 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKey", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAPIKey indicates an expected call of DeleteAPIKey
+func (mr *MockDBStorageMockRecorder) DeleteAPIKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKey", reflect.TypeOf((*MockDBStorage)(nil).DeleteAPIKey), arg0)
+}
+
+// DeleteAPIKeyTx mocks base method
+func (m *MockDBStorage) DeleteAPIKeyTx(arg0 *sqlx.Tx, arg1 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKeyTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAPIKeyTx indicates an expected call of DeleteAPIKeyTx
+func (mr *MockDBStorageMockRecorder) DeleteAPIKeyTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKeyTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteAPIKeyTx), arg0, arg1)
+}
+
 // DeleteApplication mocks base method
 func (m *MockDBStorage) DeleteApplication(arg0, arg1, arg2 string) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -455,6 +1041,36 @@ func (mr *MockDBStorageMockRecorder) DeleteCallback(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCallback", reflect.TypeOf((*MockDBStorage)(nil).DeleteCallback), arg0, arg1)
 }
 
+// DeleteCallbackLog mocks base method
+func (m *MockDBStorage) DeleteCallbackLog(arg0, arg1 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCallbackLog", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCallbackLog indicates an expected call of DeleteCallbackLog
+func (mr *MockDBStorageMockRecorder) DeleteCallbackLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCallbackLog", reflect.TypeOf((*MockDBStorage)(nil).DeleteCallbackLog), arg0, arg1)
+}
+
+// DeleteCallbackLogTx mocks base method
+func (m *MockDBStorage) DeleteCallbackLogTx(arg0 *sqlx.Tx, arg1, arg2 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCallbackLogTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCallbackLogTx indicates an expected call of DeleteCallbackLogTx
+func (mr *MockDBStorageMockRecorder) DeleteCallbackLogTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCallbackLogTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteCallbackLogTx), arg0, arg1, arg2)
+}
+
 // DeleteCallbackTx mocks base method
 func (m *MockDBStorage) DeleteCallbackTx(arg0 *sqlx.Tx, arg1, arg2 string) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -470,6 +1086,36 @@ func (mr *MockDBStorageMockRecorder) DeleteCallbackTx(arg0, arg1, arg2 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCallbackTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteCallbackTx), arg0, arg1, arg2)
 }
 
+// DeleteExpiredLock mocks base method
+func (m *MockDBStorage) DeleteExpiredLock(arg0 string, arg1 time.Time) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredLock", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredLock indicates an expected call of DeleteExpiredLock
+func (mr *MockDBStorageMockRecorder) DeleteExpiredLock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredLock", reflect.TypeOf((*MockDBStorage)(nil).DeleteExpiredLock), arg0, arg1)
+}
+
+// DeleteExpiredLockTx mocks base method
+func (m *MockDBStorage) DeleteExpiredLockTx(arg0 *sqlx.Tx, arg1 string, arg2 time.Time) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredLockTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredLockTx indicates an expected call of DeleteExpiredLockTx
+func (mr *MockDBStorageMockRecorder) DeleteExpiredLockTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredLockTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteExpiredLockTx), arg0, arg1, arg2)
+}
+
 // DeleteIndex mocks base method
 func (m *MockDBStorage) DeleteIndex(arg0 string, arg1, arg2 common.Resource, arg3 string) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -500,6 +1146,96 @@ func (mr *MockDBStorageMockRecorder) DeleteIndexTx(arg0, arg1, arg2, arg3, arg4
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIndexTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteIndexTx), arg0, arg1, arg2, arg3, arg4)
 }
 
+// DeleteLock mocks base method
+func (m *MockDBStorage) DeleteLock(arg0, arg1 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLock", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLock indicates an expected call of DeleteLock
+func (mr *MockDBStorageMockRecorder) DeleteLock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLock", reflect.TypeOf((*MockDBStorage)(nil).DeleteLock), arg0, arg1)
+}
+
+// DeleteLockTx mocks base method
+func (m *MockDBStorage) DeleteLockTx(arg0 *sqlx.Tx, arg1, arg2 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLockTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLockTx indicates an expected call of DeleteLockTx
+func (mr *MockDBStorageMockRecorder) DeleteLockTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLockTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteLockTx), arg0, arg1, arg2)
+}
+
+// DeleteNodeDeployHistory mocks base method
+func (m *MockDBStorage) DeleteNodeDeployHistory(arg0, arg1 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNodeDeployHistory", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNodeDeployHistory indicates an expected call of DeleteNodeDeployHistory
+func (mr *MockDBStorageMockRecorder) DeleteNodeDeployHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNodeDeployHistory", reflect.TypeOf((*MockDBStorage)(nil).DeleteNodeDeployHistory), arg0, arg1)
+}
+
+// DeleteNodeDeployHistoryTx mocks base method
+func (m *MockDBStorage) DeleteNodeDeployHistoryTx(arg0 *sqlx.Tx, arg1, arg2 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNodeDeployHistoryTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNodeDeployHistoryTx indicates an expected call of DeleteNodeDeployHistoryTx
+func (mr *MockDBStorageMockRecorder) DeleteNodeDeployHistoryTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNodeDeployHistoryTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteNodeDeployHistoryTx), arg0, arg1, arg2)
+}
+
+// DeleteNodeGroup mocks base method
+func (m *MockDBStorage) DeleteNodeGroup(arg0, arg1 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNodeGroup", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNodeGroup indicates an expected call of DeleteNodeGroup
+func (mr *MockDBStorageMockRecorder) DeleteNodeGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNodeGroup", reflect.TypeOf((*MockDBStorage)(nil).DeleteNodeGroup), arg0, arg1)
+}
+
+// DeleteNodeGroupTx mocks base method
+func (m *MockDBStorage) DeleteNodeGroupTx(arg0 *sqlx.Tx, arg1, arg2 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNodeGroupTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteNodeGroupTx indicates an expected call of DeleteNodeGroupTx
+func (mr *MockDBStorageMockRecorder) DeleteNodeGroupTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNodeGroupTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteNodeGroupTx), arg0, arg1, arg2)
+}
+
 // DeleteRecord mocks base method
 func (m *MockDBStorage) DeleteRecord(arg0, arg1, arg2 string) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -530,6 +1266,36 @@ func (mr *MockDBStorageMockRecorder) DeleteRecordTx(arg0, arg1, arg2, arg3 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecordTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteRecordTx), arg0, arg1, arg2, arg3)
 }
 
+// DeleteRoleBinding mocks base method
+func (m *MockDBStorage) DeleteRoleBinding(arg0, arg1 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRoleBinding", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRoleBinding indicates an expected call of DeleteRoleBinding
+func (mr *MockDBStorageMockRecorder) DeleteRoleBinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoleBinding", reflect.TypeOf((*MockDBStorage)(nil).DeleteRoleBinding), arg0, arg1)
+}
+
+// DeleteRoleBindingTx mocks base method
+func (m *MockDBStorage) DeleteRoleBindingTx(arg0 *sqlx.Tx, arg1, arg2 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRoleBindingTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRoleBindingTx indicates an expected call of DeleteRoleBindingTx
+func (mr *MockDBStorageMockRecorder) DeleteRoleBindingTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRoleBindingTx", reflect.TypeOf((*MockDBStorage)(nil).DeleteRoleBindingTx), arg0, arg1, arg2)
+}
+
 // DeleteTask mocks base method
 func (m *MockDBStorage) DeleteTask(arg0 string) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -563,301 +1329,856 @@ func (mr *MockDBStorageMockRecorder) DeleteTaskTx(arg0, arg1 interface{}) *gomoc
 // Get mocks base method
 func (m *MockDBStorage) Get(arg0, arg1 string) (*models.Shadow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, arg1)
-	ret0, _ := ret[0].(*models.Shadow)
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*models.Shadow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockDBStorageMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDBStorage)(nil).Get), arg0, arg1)
+}
+
+// GetAPIKey mocks base method
+func (m *MockDBStorage) GetAPIKey(arg0 string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKey", arg0)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKey indicates an expected call of GetAPIKey
+func (mr *MockDBStorageMockRecorder) GetAPIKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockDBStorage)(nil).GetAPIKey), arg0)
+}
+
+// GetAPIKeyByHash mocks base method
+func (m *MockDBStorage) GetAPIKeyByHash(arg0 string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", arg0)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash
+func (mr *MockDBStorageMockRecorder) GetAPIKeyByHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockDBStorage)(nil).GetAPIKeyByHash), arg0)
+}
+
+// GetAPIKeyByHashTx mocks base method
+func (m *MockDBStorage) GetAPIKeyByHashTx(arg0 *sqlx.Tx, arg1 string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHashTx", arg0, arg1)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHashTx indicates an expected call of GetAPIKeyByHashTx
+func (mr *MockDBStorageMockRecorder) GetAPIKeyByHashTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHashTx", reflect.TypeOf((*MockDBStorage)(nil).GetAPIKeyByHashTx), arg0, arg1)
+}
+
+// GetAPIKeyTx mocks base method
+func (m *MockDBStorage) GetAPIKeyTx(arg0 *sqlx.Tx, arg1 string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyTx", arg0, arg1)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyTx indicates an expected call of GetAPIKeyTx
+func (mr *MockDBStorageMockRecorder) GetAPIKeyTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyTx", reflect.TypeOf((*MockDBStorage)(nil).GetAPIKeyTx), arg0, arg1)
+}
+
+// GetApplication mocks base method
+func (m *MockDBStorage) GetApplication(arg0, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplication", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplication indicates an expected call of GetApplication
+func (mr *MockDBStorageMockRecorder) GetApplication(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplication", reflect.TypeOf((*MockDBStorage)(nil).GetApplication), arg0, arg1, arg2)
+}
+
+// GetBatch mocks base method
+func (m *MockDBStorage) GetBatch(arg0, arg1 string) (*models.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", arg0, arg1)
+	ret0, _ := ret[0].(*models.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch
+func (mr *MockDBStorageMockRecorder) GetBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockDBStorage)(nil).GetBatch), arg0, arg1)
+}
+
+// GetBatchTx mocks base method
+func (m *MockDBStorage) GetBatchTx(arg0 *sqlx.Tx, arg1, arg2 string) (*models.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatchTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatchTx indicates an expected call of GetBatchTx
+func (mr *MockDBStorageMockRecorder) GetBatchTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatchTx", reflect.TypeOf((*MockDBStorage)(nil).GetBatchTx), arg0, arg1, arg2)
+}
+
+// GetCallback mocks base method
+func (m *MockDBStorage) GetCallback(arg0, arg1 string) (*models.Callback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCallback", arg0, arg1)
+	ret0, _ := ret[0].(*models.Callback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallback indicates an expected call of GetCallback
+func (mr *MockDBStorageMockRecorder) GetCallback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallback", reflect.TypeOf((*MockDBStorage)(nil).GetCallback), arg0, arg1)
+}
+
+// GetCallbackTx mocks base method
+func (m *MockDBStorage) GetCallbackTx(arg0 *sqlx.Tx, arg1, arg2 string) (*models.Callback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCallbackTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Callback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallbackTx indicates an expected call of GetCallbackTx
+func (mr *MockDBStorageMockRecorder) GetCallbackTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallbackTx", reflect.TypeOf((*MockDBStorage)(nil).GetCallbackTx), arg0, arg1, arg2)
+}
+
+// GetLock mocks base method
+func (m *MockDBStorage) GetLock(arg0 string) (*models.Lock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLock", arg0)
+	ret0, _ := ret[0].(*models.Lock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLock indicates an expected call of GetLock
+func (mr *MockDBStorageMockRecorder) GetLock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLock", reflect.TypeOf((*MockDBStorage)(nil).GetLock), arg0)
+}
+
+// GetLockTx mocks base method
+func (m *MockDBStorage) GetLockTx(arg0 *sqlx.Tx, arg1 string) (*models.Lock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLockTx", arg0, arg1)
+	ret0, _ := ret[0].(*models.Lock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLockTx indicates an expected call of GetLockTx
+func (mr *MockDBStorageMockRecorder) GetLockTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockTx", reflect.TypeOf((*MockDBStorage)(nil).GetLockTx), arg0, arg1)
+}
+
+// GetNodeGroup mocks base method
+func (m *MockDBStorage) GetNodeGroup(arg0, arg1 string) (*models.NodeGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeGroup", arg0, arg1)
+	ret0, _ := ret[0].(*models.NodeGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeGroup indicates an expected call of GetNodeGroup
+func (mr *MockDBStorageMockRecorder) GetNodeGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeGroup", reflect.TypeOf((*MockDBStorage)(nil).GetNodeGroup), arg0, arg1)
+}
+
+// GetNodeGroupTx mocks base method
+func (m *MockDBStorage) GetNodeGroupTx(arg0 *sqlx.Tx, arg1, arg2 string) (*models.NodeGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeGroupTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.NodeGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeGroupTx indicates an expected call of GetNodeGroupTx
+func (mr *MockDBStorageMockRecorder) GetNodeGroupTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeGroupTx", reflect.TypeOf((*MockDBStorage)(nil).GetNodeGroupTx), arg0, arg1, arg2)
+}
+
+// GetRecord mocks base method
+func (m *MockDBStorage) GetRecord(arg0, arg1, arg2 string) (*models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecord indicates an expected call of GetRecord
+func (mr *MockDBStorageMockRecorder) GetRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecord", reflect.TypeOf((*MockDBStorage)(nil).GetRecord), arg0, arg1, arg2)
+}
+
+// GetRecordByFingerprint mocks base method
+func (m *MockDBStorage) GetRecordByFingerprint(arg0, arg1, arg2 string) (*models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordByFingerprint", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordByFingerprint indicates an expected call of GetRecordByFingerprint
+func (mr *MockDBStorageMockRecorder) GetRecordByFingerprint(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordByFingerprint", reflect.TypeOf((*MockDBStorage)(nil).GetRecordByFingerprint), arg0, arg1, arg2)
+}
+
+// GetRecordByFingerprintTx mocks base method
+func (m *MockDBStorage) GetRecordByFingerprintTx(arg0 *sqlx.Tx, arg1, arg2, arg3 string) (*models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordByFingerprintTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordByFingerprintTx indicates an expected call of GetRecordByFingerprintTx
+func (mr *MockDBStorageMockRecorder) GetRecordByFingerprintTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordByFingerprintTx", reflect.TypeOf((*MockDBStorage)(nil).GetRecordByFingerprintTx), arg0, arg1, arg2, arg3)
+}
+
+// GetRecordTx mocks base method
+func (m *MockDBStorage) GetRecordTx(arg0 *sqlx.Tx, arg1, arg2, arg3 string) (*models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordTx indicates an expected call of GetRecordTx
+func (mr *MockDBStorageMockRecorder) GetRecordTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordTx", reflect.TypeOf((*MockDBStorage)(nil).GetRecordTx), arg0, arg1, arg2, arg3)
+}
+
+// GetRoleBinding mocks base method
+func (m *MockDBStorage) GetRoleBinding(arg0, arg1 string) (*models.RoleBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleBinding", arg0, arg1)
+	ret0, _ := ret[0].(*models.RoleBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleBinding indicates an expected call of GetRoleBinding
+func (mr *MockDBStorageMockRecorder) GetRoleBinding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleBinding", reflect.TypeOf((*MockDBStorage)(nil).GetRoleBinding), arg0, arg1)
+}
+
+// GetRoleBindingTx mocks base method
+func (m *MockDBStorage) GetRoleBindingTx(arg0 *sqlx.Tx, arg1, arg2 string) (*models.RoleBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleBindingTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.RoleBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleBindingTx indicates an expected call of GetRoleBindingTx
+func (mr *MockDBStorageMockRecorder) GetRoleBindingTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleBindingTx", reflect.TypeOf((*MockDBStorage)(nil).GetRoleBindingTx), arg0, arg1, arg2)
+}
+
+// GetRollout mocks base method
+func (m *MockDBStorage) GetRollout(arg0, arg1 string) (*models.Rollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRollout", arg0, arg1)
+	ret0, _ := ret[0].(*models.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRollout indicates an expected call of GetRollout
+func (mr *MockDBStorageMockRecorder) GetRollout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRollout", reflect.TypeOf((*MockDBStorage)(nil).GetRollout), arg0, arg1)
+}
+
+// GetRolloutTx mocks base method
+func (m *MockDBStorage) GetRolloutTx(arg0 *sqlx.Tx, arg1, arg2 string) (*models.Rollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolloutTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolloutTx indicates an expected call of GetRolloutTx
+func (mr *MockDBStorageMockRecorder) GetRolloutTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolloutTx", reflect.TypeOf((*MockDBStorage)(nil).GetRolloutTx), arg0, arg1, arg2)
+}
+
+// GetTask mocks base method
+func (m *MockDBStorage) GetTask(arg0 string) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTask", arg0)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTask indicates an expected call of GetTask
+func (mr *MockDBStorageMockRecorder) GetTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockDBStorage)(nil).GetTask), arg0)
+}
+
+// GetTaskTx mocks base method
+func (m *MockDBStorage) GetTaskTx(arg0 *sqlx.Tx, arg1 string) (*models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskTx", arg0, arg1)
+	ret0, _ := ret[0].(*models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskTx indicates an expected call of GetTaskTx
+func (mr *MockDBStorageMockRecorder) GetTaskTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskTx", reflect.TypeOf((*MockDBStorage)(nil).GetTaskTx), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockDBStorage) List(arg0 string, arg1 *models.NodeList) (*models.ShadowList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].(*models.ShadowList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockDBStorageMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDBStorage)(nil).List), arg0, arg1)
+}
+
+// ListAPIKey mocks base method
+func (m *MockDBStorage) ListAPIKey(arg0 string, arg1 *models.Filter) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKey", arg0, arg1)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKey indicates an expected call of ListAPIKey
+func (mr *MockDBStorageMockRecorder) ListAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKey", reflect.TypeOf((*MockDBStorage)(nil).ListAPIKey), arg0, arg1)
+}
+
+// ListAPIKeyTx mocks base method
+func (m *MockDBStorage) ListAPIKeyTx(arg0 *sqlx.Tx, arg1 string, arg2 *models.Filter) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeyTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeyTx indicates an expected call of ListAPIKeyTx
+func (mr *MockDBStorageMockRecorder) ListAPIKeyTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeyTx", reflect.TypeOf((*MockDBStorage)(nil).ListAPIKeyTx), arg0, arg1, arg2)
+}
+
+// ListApplication mocks base method
+func (m *MockDBStorage) ListApplication(arg0 string, arg1 *models.Filter) ([]v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplication", arg0, arg1)
+	ret0, _ := ret[0].([]v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplication indicates an expected call of ListApplication
+func (mr *MockDBStorageMockRecorder) ListApplication(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplication", reflect.TypeOf((*MockDBStorage)(nil).ListApplication), arg0, arg1)
+}
+
+// ListApplicationHistory mocks base method
+func (m *MockDBStorage) ListApplicationHistory(arg0, arg1 string, arg2 *models.Filter) ([]models.ApplicationVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.ApplicationVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicationHistory indicates an expected call of ListApplicationHistory
+func (mr *MockDBStorageMockRecorder) ListApplicationHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationHistory", reflect.TypeOf((*MockDBStorage)(nil).ListApplicationHistory), arg0, arg1, arg2)
+}
+
+// ListAuditLog mocks base method
+func (m *MockDBStorage) ListAuditLog(arg0 *models.AuditFilter) ([]models.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLog", arg0)
+	ret0, _ := ret[0].([]models.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLog indicates an expected call of ListAuditLog
+func (mr *MockDBStorageMockRecorder) ListAuditLog(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLog", reflect.TypeOf((*MockDBStorage)(nil).ListAuditLog), arg0)
+}
+
+// ListAuditLogTx mocks base method
+func (m *MockDBStorage) ListAuditLogTx(arg0 *sqlx.Tx, arg1 *models.AuditFilter) ([]models.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogTx", arg0, arg1)
+	ret0, _ := ret[0].([]models.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogTx indicates an expected call of ListAuditLogTx
+func (mr *MockDBStorageMockRecorder) ListAuditLogTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogTx", reflect.TypeOf((*MockDBStorage)(nil).ListAuditLogTx), arg0, arg1)
+}
+
+// ListBatch mocks base method
+func (m *MockDBStorage) ListBatch(arg0 string, arg1 *models.Filter) ([]models.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBatch", arg0, arg1)
+	ret0, _ := ret[0].([]models.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBatch indicates an expected call of ListBatch
+func (mr *MockDBStorageMockRecorder) ListBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBatch", reflect.TypeOf((*MockDBStorage)(nil).ListBatch), arg0, arg1)
+}
+
+// ListBatchTx mocks base method
+func (m *MockDBStorage) ListBatchTx(arg0 *sqlx.Tx, arg1 string, arg2 *models.Filter) ([]models.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBatchTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBatchTx indicates an expected call of ListBatchTx
+func (mr *MockDBStorageMockRecorder) ListBatchTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBatchTx", reflect.TypeOf((*MockDBStorage)(nil).ListBatchTx), arg0, arg1, arg2)
+}
+
+// ListCallback mocks base method
+func (m *MockDBStorage) ListCallback(arg0 string, arg1 *models.Filter) ([]models.Callback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCallback", arg0, arg1)
+	ret0, _ := ret[0].([]models.Callback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCallback indicates an expected call of ListCallback
+func (mr *MockDBStorageMockRecorder) ListCallback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCallback", reflect.TypeOf((*MockDBStorage)(nil).ListCallback), arg0, arg1)
+}
+
+// ListCallbackLog mocks base method
+func (m *MockDBStorage) ListCallbackLog(arg0, arg1 string, arg2 *models.Filter) ([]models.CallbackLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCallbackLog", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.CallbackLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCallbackLog indicates an expected call of ListCallbackLog
+func (mr *MockDBStorageMockRecorder) ListCallbackLog(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCallbackLog", reflect.TypeOf((*MockDBStorage)(nil).ListCallbackLog), arg0, arg1, arg2)
+}
+
+// ListCallbackLogTx mocks base method
+func (m *MockDBStorage) ListCallbackLogTx(arg0 *sqlx.Tx, arg1, arg2 string, arg3 *models.Filter) ([]models.CallbackLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCallbackLogTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.CallbackLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCallbackLogTx indicates an expected call of ListCallbackLogTx
+func (mr *MockDBStorageMockRecorder) ListCallbackLogTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCallbackLogTx", reflect.TypeOf((*MockDBStorage)(nil).ListCallbackLogTx), arg0, arg1, arg2, arg3)
+}
+
+// ListCallbackTx mocks base method
+func (m *MockDBStorage) ListCallbackTx(arg0 *sqlx.Tx, arg1 string, arg2 *models.Filter) ([]models.Callback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCallbackTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Callback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCallbackTx indicates an expected call of ListCallbackTx
+func (mr *MockDBStorageMockRecorder) ListCallbackTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCallbackTx", reflect.TypeOf((*MockDBStorage)(nil).ListCallbackTx), arg0, arg1, arg2)
+}
+
+// ListIndex mocks base method
+func (m *MockDBStorage) ListIndex(arg0 string, arg1, arg2 common.Resource, arg3 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIndex", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Get indicates an expected call of Get
-func (mr *MockDBStorageMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+// ListIndex indicates an expected call of ListIndex
+func (mr *MockDBStorageMockRecorder) ListIndex(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDBStorage)(nil).Get), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIndex", reflect.TypeOf((*MockDBStorage)(nil).ListIndex), arg0, arg1, arg2, arg3)
 }
 
-// GetApplication mocks base method
-func (m *MockDBStorage) GetApplication(arg0, arg1, arg2 string) (*v1.Application, error) {
+// ListIndexTx mocks base method
+func (m *MockDBStorage) ListIndexTx(arg0 *sqlx.Tx, arg1 string, arg2, arg3 common.Resource, arg4 string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetApplication", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*v1.Application)
+	ret := m.ctrl.Call(m, "ListIndexTx", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetApplication indicates an expected call of GetApplication
-func (mr *MockDBStorageMockRecorder) GetApplication(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListIndexTx indicates an expected call of ListIndexTx
+func (mr *MockDBStorageMockRecorder) ListIndexTx(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplication", reflect.TypeOf((*MockDBStorage)(nil).GetApplication), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIndexTx", reflect.TypeOf((*MockDBStorage)(nil).ListIndexTx), arg0, arg1, arg2, arg3, arg4)
 }
 
-// GetBatch mocks base method
-func (m *MockDBStorage) GetBatch(arg0, arg1 string) (*models.Batch, error) {
+// ListNamespaceRolloutByStatus mocks base method
+func (m *MockDBStorage) ListNamespaceRolloutByStatus(arg0 string, arg1 []string) ([]models.Rollout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBatch", arg0, arg1)
-	ret0, _ := ret[0].(*models.Batch)
+	ret := m.ctrl.Call(m, "ListNamespaceRolloutByStatus", arg0, arg1)
+	ret0, _ := ret[0].([]models.Rollout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBatch indicates an expected call of GetBatch
-func (mr *MockDBStorageMockRecorder) GetBatch(arg0, arg1 interface{}) *gomock.Call {
+// ListNamespaceRolloutByStatus indicates an expected call of ListNamespaceRolloutByStatus
+func (mr *MockDBStorageMockRecorder) ListNamespaceRolloutByStatus(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockDBStorage)(nil).GetBatch), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespaceRolloutByStatus", reflect.TypeOf((*MockDBStorage)(nil).ListNamespaceRolloutByStatus), arg0, arg1)
 }
 
-// GetBatchTx mocks base method
-func (m *MockDBStorage) GetBatchTx(arg0 *sqlx.Tx, arg1, arg2 string) (*models.Batch, error) {
+// ListNamespaceRolloutByStatusTx mocks base method
+func (m *MockDBStorage) ListNamespaceRolloutByStatusTx(arg0 *sqlx.Tx, arg1 string, arg2 []string) ([]models.Rollout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBatchTx", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*models.Batch)
+	ret := m.ctrl.Call(m, "ListNamespaceRolloutByStatusTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Rollout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBatchTx indicates an expected call of GetBatchTx
-func (mr *MockDBStorageMockRecorder) GetBatchTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListNamespaceRolloutByStatusTx indicates an expected call of ListNamespaceRolloutByStatusTx
+func (mr *MockDBStorageMockRecorder) ListNamespaceRolloutByStatusTx(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatchTx", reflect.TypeOf((*MockDBStorage)(nil).GetBatchTx), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespaceRolloutByStatusTx", reflect.TypeOf((*MockDBStorage)(nil).ListNamespaceRolloutByStatusTx), arg0, arg1, arg2)
 }
 
-// GetCallback mocks base method
-func (m *MockDBStorage) GetCallback(arg0, arg1 string) (*models.Callback, error) {
+// ListNodeDeployHistory mocks base method
+func (m *MockDBStorage) ListNodeDeployHistory(arg0, arg1 string, arg2 *models.NodeDeployFilter) ([]models.NodeDeployHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCallback", arg0, arg1)
-	ret0, _ := ret[0].(*models.Callback)
+	ret := m.ctrl.Call(m, "ListNodeDeployHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.NodeDeployHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCallback indicates an expected call of GetCallback
-func (mr *MockDBStorageMockRecorder) GetCallback(arg0, arg1 interface{}) *gomock.Call {
+// ListNodeDeployHistory indicates an expected call of ListNodeDeployHistory
+func (mr *MockDBStorageMockRecorder) ListNodeDeployHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallback", reflect.TypeOf((*MockDBStorage)(nil).GetCallback), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodeDeployHistory", reflect.TypeOf((*MockDBStorage)(nil).ListNodeDeployHistory), arg0, arg1, arg2)
 }
 
-// GetCallbackTx mocks base method
-func (m *MockDBStorage) GetCallbackTx(arg0 *sqlx.Tx, arg1, arg2 string) (*models.Callback, error) {
+// ListNodeDeployHistoryTx mocks base method
+func (m *MockDBStorage) ListNodeDeployHistoryTx(arg0 *sqlx.Tx, arg1, arg2 string, arg3 *models.NodeDeployFilter) ([]models.NodeDeployHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCallbackTx", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*models.Callback)
+	ret := m.ctrl.Call(m, "ListNodeDeployHistoryTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.NodeDeployHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCallbackTx indicates an expected call of GetCallbackTx
-func (mr *MockDBStorageMockRecorder) GetCallbackTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListNodeDeployHistoryTx indicates an expected call of ListNodeDeployHistoryTx
+func (mr *MockDBStorageMockRecorder) ListNodeDeployHistoryTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallbackTx", reflect.TypeOf((*MockDBStorage)(nil).GetCallbackTx), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodeDeployHistoryTx", reflect.TypeOf((*MockDBStorage)(nil).ListNodeDeployHistoryTx), arg0, arg1, arg2, arg3)
 }
 
-// GetRecord mocks base method
-func (m *MockDBStorage) GetRecord(arg0, arg1, arg2 string) (*models.Record, error) {
+// ListNodeGroup mocks base method
+func (m *MockDBStorage) ListNodeGroup(arg0 string, arg1 *models.Filter) ([]models.NodeGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecord", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*models.Record)
+	ret := m.ctrl.Call(m, "ListNodeGroup", arg0, arg1)
+	ret0, _ := ret[0].([]models.NodeGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecord indicates an expected call of GetRecord
-func (mr *MockDBStorageMockRecorder) GetRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListNodeGroup indicates an expected call of ListNodeGroup
+func (mr *MockDBStorageMockRecorder) ListNodeGroup(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecord", reflect.TypeOf((*MockDBStorage)(nil).GetRecord), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodeGroup", reflect.TypeOf((*MockDBStorage)(nil).ListNodeGroup), arg0, arg1)
 }
 
-// GetRecordByFingerprint mocks base method
-func (m *MockDBStorage) GetRecordByFingerprint(arg0, arg1, arg2 string) (*models.Record, error) {
+// ListNodeGroupTx mocks base method
+func (m *MockDBStorage) ListNodeGroupTx(arg0 *sqlx.Tx, arg1 string, arg2 *models.Filter) ([]models.NodeGroup, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecordByFingerprint", arg0, arg1, arg2)
-	ret0, _ := ret[0].(*models.Record)
+	ret := m.ctrl.Call(m, "ListNodeGroupTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.NodeGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecordByFingerprint indicates an expected call of GetRecordByFingerprint
-func (mr *MockDBStorageMockRecorder) GetRecordByFingerprint(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListNodeGroupTx indicates an expected call of ListNodeGroupTx
+func (mr *MockDBStorageMockRecorder) ListNodeGroupTx(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordByFingerprint", reflect.TypeOf((*MockDBStorage)(nil).GetRecordByFingerprint), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodeGroupTx", reflect.TypeOf((*MockDBStorage)(nil).ListNodeGroupTx), arg0, arg1, arg2)
 }
 
-// GetRecordByFingerprintTx mocks base method
-func (m *MockDBStorage) GetRecordByFingerprintTx(arg0 *sqlx.Tx, arg1, arg2, arg3 string) (*models.Record, error) {
+// ListNodeTask mocks base method
+func (m *MockDBStorage) ListNodeTask(arg0, arg1 string, arg2 *models.TaskFilter) ([]models.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecordByFingerprintTx", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(*models.Record)
+	ret := m.ctrl.Call(m, "ListNodeTask", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecordByFingerprintTx indicates an expected call of GetRecordByFingerprintTx
-func (mr *MockDBStorageMockRecorder) GetRecordByFingerprintTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// ListNodeTask indicates an expected call of ListNodeTask
+func (mr *MockDBStorageMockRecorder) ListNodeTask(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordByFingerprintTx", reflect.TypeOf((*MockDBStorage)(nil).GetRecordByFingerprintTx), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodeTask", reflect.TypeOf((*MockDBStorage)(nil).ListNodeTask), arg0, arg1, arg2)
 }
 
-// GetRecordTx mocks base method
-func (m *MockDBStorage) GetRecordTx(arg0 *sqlx.Tx, arg1, arg2, arg3 string) (*models.Record, error) {
+// ListNodeTaskTx mocks base method
+func (m *MockDBStorage) ListNodeTaskTx(arg0 *sqlx.Tx, arg1, arg2 string, arg3 *models.TaskFilter) ([]models.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecordTx", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].(*models.Record)
+	ret := m.ctrl.Call(m, "ListNodeTaskTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRecordTx indicates an expected call of GetRecordTx
-func (mr *MockDBStorageMockRecorder) GetRecordTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// ListNodeTaskTx indicates an expected call of ListNodeTaskTx
+func (mr *MockDBStorageMockRecorder) ListNodeTaskTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordTx", reflect.TypeOf((*MockDBStorage)(nil).GetRecordTx), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodeTaskTx", reflect.TypeOf((*MockDBStorage)(nil).ListNodeTaskTx), arg0, arg1, arg2, arg3)
 }
 
-// GetTask mocks base method
-func (m *MockDBStorage) GetTask(arg0 string) (*models.Task, error) {
+// ListRecord mocks base method
+func (m *MockDBStorage) ListRecord(arg0, arg1 string, arg2 *models.Filter) ([]models.Record, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTask", arg0)
-	ret0, _ := ret[0].(*models.Task)
+	ret := m.ctrl.Call(m, "ListRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Record)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTask indicates an expected call of GetTask
-func (mr *MockDBStorageMockRecorder) GetTask(arg0 interface{}) *gomock.Call {
+// ListRecord indicates an expected call of ListRecord
+func (mr *MockDBStorageMockRecorder) ListRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockDBStorage)(nil).GetTask), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecord", reflect.TypeOf((*MockDBStorage)(nil).ListRecord), arg0, arg1, arg2)
 }
 
-// GetTaskTx mocks base method
-func (m *MockDBStorage) GetTaskTx(arg0 *sqlx.Tx, arg1 string) (*models.Task, error) {
+// ListRecordTx mocks base method
+func (m *MockDBStorage) ListRecordTx(arg0 *sqlx.Tx, arg1, arg2 string, arg3 *models.Filter) ([]models.Record, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTaskTx", arg0, arg1)
-	ret0, _ := ret[0].(*models.Task)
+	ret := m.ctrl.Call(m, "ListRecordTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.Record)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTaskTx indicates an expected call of GetTaskTx
-func (mr *MockDBStorageMockRecorder) GetTaskTx(arg0, arg1 interface{}) *gomock.Call {
+// ListRecordTx indicates an expected call of ListRecordTx
+func (mr *MockDBStorageMockRecorder) ListRecordTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskTx", reflect.TypeOf((*MockDBStorage)(nil).GetTaskTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordTx", reflect.TypeOf((*MockDBStorage)(nil).ListRecordTx), arg0, arg1, arg2, arg3)
 }
 
-// List mocks base method
-func (m *MockDBStorage) List(arg0 string, arg1 *models.NodeList) (*models.ShadowList, error) {
+// ListRoleBinding mocks base method
+func (m *MockDBStorage) ListRoleBinding(arg0 string, arg1 *models.Filter) ([]models.RoleBinding, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", arg0, arg1)
-	ret0, _ := ret[0].(*models.ShadowList)
+	ret := m.ctrl.Call(m, "ListRoleBinding", arg0, arg1)
+	ret0, _ := ret[0].([]models.RoleBinding)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// List indicates an expected call of List
-func (mr *MockDBStorageMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+// ListRoleBinding indicates an expected call of ListRoleBinding
+func (mr *MockDBStorageMockRecorder) ListRoleBinding(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDBStorage)(nil).List), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoleBinding", reflect.TypeOf((*MockDBStorage)(nil).ListRoleBinding), arg0, arg1)
 }
 
-// ListApplication mocks base method
-func (m *MockDBStorage) ListApplication(arg0 string, arg1 *models.Filter) ([]v1.Application, error) {
+// ListRoleBindingTx mocks base method
+func (m *MockDBStorage) ListRoleBindingTx(arg0 *sqlx.Tx, arg1 string, arg2 *models.Filter) ([]models.RoleBinding, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListApplication", arg0, arg1)
-	ret0, _ := ret[0].([]v1.Application)
+	ret := m.ctrl.Call(m, "ListRoleBindingTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.RoleBinding)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListApplication indicates an expected call of ListApplication
-func (mr *MockDBStorageMockRecorder) ListApplication(arg0, arg1 interface{}) *gomock.Call {
+// ListRoleBindingTx indicates an expected call of ListRoleBindingTx
+func (mr *MockDBStorageMockRecorder) ListRoleBindingTx(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplication", reflect.TypeOf((*MockDBStorage)(nil).ListApplication), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoleBindingTx", reflect.TypeOf((*MockDBStorage)(nil).ListRoleBindingTx), arg0, arg1, arg2)
 }
 
-// ListBatch mocks base method
-func (m *MockDBStorage) ListBatch(arg0 string, arg1 *models.Filter) ([]models.Batch, error) {
+// ListRollout mocks base method
+func (m *MockDBStorage) ListRollout(arg0 string, arg1 *models.Filter) ([]models.Rollout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBatch", arg0, arg1)
-	ret0, _ := ret[0].([]models.Batch)
+	ret := m.ctrl.Call(m, "ListRollout", arg0, arg1)
+	ret0, _ := ret[0].([]models.Rollout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListBatch indicates an expected call of ListBatch
-func (mr *MockDBStorageMockRecorder) ListBatch(arg0, arg1 interface{}) *gomock.Call {
+// ListRollout indicates an expected call of ListRollout
+func (mr *MockDBStorageMockRecorder) ListRollout(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBatch", reflect.TypeOf((*MockDBStorage)(nil).ListBatch), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRollout", reflect.TypeOf((*MockDBStorage)(nil).ListRollout), arg0, arg1)
 }
 
-// ListBatchTx mocks base method
-func (m *MockDBStorage) ListBatchTx(arg0 *sqlx.Tx, arg1 string, arg2 *models.Filter) ([]models.Batch, error) {
+// ListRolloutByStatus mocks base method
+func (m *MockDBStorage) ListRolloutByStatus(arg0 string) ([]models.Rollout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBatchTx", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]models.Batch)
+	ret := m.ctrl.Call(m, "ListRolloutByStatus", arg0)
+	ret0, _ := ret[0].([]models.Rollout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListBatchTx indicates an expected call of ListBatchTx
-func (mr *MockDBStorageMockRecorder) ListBatchTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+// ListRolloutByStatus indicates an expected call of ListRolloutByStatus
+func (mr *MockDBStorageMockRecorder) ListRolloutByStatus(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBatchTx", reflect.TypeOf((*MockDBStorage)(nil).ListBatchTx), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRolloutByStatus", reflect.TypeOf((*MockDBStorage)(nil).ListRolloutByStatus), arg0)
 }
 
-// ListIndex mocks base method
-func (m *MockDBStorage) ListIndex(arg0 string, arg1, arg2 common.Resource, arg3 string) ([]string, error) {
+// ListRolloutByStatusTx mocks base method
+func (m *MockDBStorage) ListRolloutByStatusTx(arg0 *sqlx.Tx, arg1 string) ([]models.Rollout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListIndex", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "ListRolloutByStatusTx", arg0, arg1)
+	ret0, _ := ret[0].([]models.Rollout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListIndex indicates an expected call of ListIndex
-func (mr *MockDBStorageMockRecorder) ListIndex(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// ListRolloutByStatusTx indicates an expected call of ListRolloutByStatusTx
+func (mr *MockDBStorageMockRecorder) ListRolloutByStatusTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIndex", reflect.TypeOf((*MockDBStorage)(nil).ListIndex), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRolloutByStatusTx", reflect.TypeOf((*MockDBStorage)(nil).ListRolloutByStatusTx), arg0, arg1)
 }
 
-// ListIndexTx mocks base method
-func (m *MockDBStorage) ListIndexTx(arg0 *sqlx.Tx, arg1 string, arg2, arg3 common.Resource, arg4 string) ([]string, error) {
+// ListRolloutTx mocks base method
+func (m *MockDBStorage) ListRolloutTx(arg0 *sqlx.Tx, arg1 string, arg2 *models.Filter) ([]models.Rollout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListIndexTx", arg0, arg1, arg2, arg3, arg4)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "ListRolloutTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Rollout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListIndexTx indicates an expected call of ListIndexTx
-func (mr *MockDBStorageMockRecorder) ListIndexTx(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+// ListRolloutTx indicates an expected call of ListRolloutTx
+func (mr *MockDBStorageMockRecorder) ListRolloutTx(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIndexTx", reflect.TypeOf((*MockDBStorage)(nil).ListIndexTx), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRolloutTx", reflect.TypeOf((*MockDBStorage)(nil).ListRolloutTx), arg0, arg1, arg2)
 }
 
-// ListRecord mocks base method
-func (m *MockDBStorage) ListRecord(arg0, arg1 string, arg2 *models.Filter) ([]models.Record, error) {
+// PruneCallbackLog mocks base method
+func (m *MockDBStorage) PruneCallbackLog(arg0, arg1 string, arg2 int) (sql.Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListRecord", arg0, arg1, arg2)
-	ret0, _ := ret[0].([]models.Record)
+	ret := m.ctrl.Call(m, "PruneCallbackLog", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListRecord indicates an expected call of ListRecord
-func (mr *MockDBStorageMockRecorder) ListRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+// PruneCallbackLog indicates an expected call of PruneCallbackLog
+func (mr *MockDBStorageMockRecorder) PruneCallbackLog(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecord", reflect.TypeOf((*MockDBStorage)(nil).ListRecord), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneCallbackLog", reflect.TypeOf((*MockDBStorage)(nil).PruneCallbackLog), arg0, arg1, arg2)
 }
 
-// ListRecordTx mocks base method
-func (m *MockDBStorage) ListRecordTx(arg0 *sqlx.Tx, arg1, arg2 string, arg3 *models.Filter) ([]models.Record, error) {
+// PruneCallbackLogTx mocks base method
+func (m *MockDBStorage) PruneCallbackLogTx(arg0 *sqlx.Tx, arg1, arg2 string, arg3 int) (sql.Result, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListRecordTx", arg0, arg1, arg2, arg3)
-	ret0, _ := ret[0].([]models.Record)
+	ret := m.ctrl.Call(m, "PruneCallbackLogTx", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(sql.Result)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListRecordTx indicates an expected call of ListRecordTx
-func (mr *MockDBStorageMockRecorder) ListRecordTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+// PruneCallbackLogTx indicates an expected call of PruneCallbackLogTx
+func (mr *MockDBStorageMockRecorder) PruneCallbackLogTx(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordTx", reflect.TypeOf((*MockDBStorage)(nil).ListRecordTx), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneCallbackLogTx", reflect.TypeOf((*MockDBStorage)(nil).PruneCallbackLogTx), arg0, arg1, arg2, arg3)
 }
 
 // RefreshIndex mocks base method
@@ -874,6 +2195,36 @@ func (mr *MockDBStorageMockRecorder) RefreshIndex(arg0, arg1, arg2, arg3, arg4 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshIndex", reflect.TypeOf((*MockDBStorage)(nil).RefreshIndex), arg0, arg1, arg2, arg3, arg4)
 }
 
+// RenewLock mocks base method
+func (m *MockDBStorage) RenewLock(arg0 *models.Lock) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewLock", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewLock indicates an expected call of RenewLock
+func (mr *MockDBStorageMockRecorder) RenewLock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewLock", reflect.TypeOf((*MockDBStorage)(nil).RenewLock), arg0)
+}
+
+// RenewLockTx mocks base method
+func (m *MockDBStorage) RenewLockTx(arg0 *sqlx.Tx, arg1 *models.Lock) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewLockTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewLockTx indicates an expected call of RenewLockTx
+func (mr *MockDBStorageMockRecorder) RenewLockTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewLockTx", reflect.TypeOf((*MockDBStorage)(nil).RenewLockTx), arg0, arg1)
+}
+
 // Transact mocks base method
 func (m *MockDBStorage) Transact(arg0 func(*sqlx.Tx) error) error {
 	m.ctrl.T.Helper()
@@ -993,6 +2344,36 @@ func (mr *MockDBStorageMockRecorder) UpdateDesire(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDesire", reflect.TypeOf((*MockDBStorage)(nil).UpdateDesire), arg0)
 }
 
+// UpdateNodeGroup mocks base method
+func (m *MockDBStorage) UpdateNodeGroup(arg0 *models.NodeGroup) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeGroup", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNodeGroup indicates an expected call of UpdateNodeGroup
+func (mr *MockDBStorageMockRecorder) UpdateNodeGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeGroup", reflect.TypeOf((*MockDBStorage)(nil).UpdateNodeGroup), arg0)
+}
+
+// UpdateNodeGroupTx mocks base method
+func (m *MockDBStorage) UpdateNodeGroupTx(arg0 *sqlx.Tx, arg1 *models.NodeGroup) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeGroupTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNodeGroupTx indicates an expected call of UpdateNodeGroupTx
+func (mr *MockDBStorageMockRecorder) UpdateNodeGroupTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeGroupTx", reflect.TypeOf((*MockDBStorage)(nil).UpdateNodeGroupTx), arg0, arg1)
+}
+
 // UpdateRecord mocks base method
 func (m *MockDBStorage) UpdateRecord(arg0 *models.Record) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -1038,6 +2419,66 @@ func (mr *MockDBStorageMockRecorder) UpdateReport(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReport", reflect.TypeOf((*MockDBStorage)(nil).UpdateReport), arg0)
 }
 
+// UpdateRoleBinding mocks base method
+func (m *MockDBStorage) UpdateRoleBinding(arg0 *models.RoleBinding) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRoleBinding", arg0)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRoleBinding indicates an expected call of UpdateRoleBinding
+func (mr *MockDBStorageMockRecorder) UpdateRoleBinding(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRoleBinding", reflect.TypeOf((*MockDBStorage)(nil).UpdateRoleBinding), arg0)
+}
+
+// UpdateRoleBindingTx mocks base method
+func (m *MockDBStorage) UpdateRoleBindingTx(arg0 *sqlx.Tx, arg1 *models.RoleBinding) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRoleBindingTx", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRoleBindingTx indicates an expected call of UpdateRoleBindingTx
+func (mr *MockDBStorageMockRecorder) UpdateRoleBindingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRoleBindingTx", reflect.TypeOf((*MockDBStorage)(nil).UpdateRoleBindingTx), arg0, arg1)
+}
+
+// UpdateRollout mocks base method
+func (m *MockDBStorage) UpdateRollout(arg0 *models.Rollout, arg1 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRollout", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRollout indicates an expected call of UpdateRollout
+func (mr *MockDBStorageMockRecorder) UpdateRollout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRollout", reflect.TypeOf((*MockDBStorage)(nil).UpdateRollout), arg0, arg1)
+}
+
+// UpdateRolloutTx mocks base method
+func (m *MockDBStorage) UpdateRolloutTx(arg0 *sqlx.Tx, arg1 *models.Rollout, arg2 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRolloutTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRolloutTx indicates an expected call of UpdateRolloutTx
+func (mr *MockDBStorageMockRecorder) UpdateRolloutTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRolloutTx", reflect.TypeOf((*MockDBStorage)(nil).UpdateRolloutTx), arg0, arg1, arg2)
+}
+
 // UpdateTask mocks base method
 func (m *MockDBStorage) UpdateTask(arg0 *models.Task) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -1053,6 +2494,36 @@ func (mr *MockDBStorageMockRecorder) UpdateTask(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockDBStorage)(nil).UpdateTask), arg0)
 }
 
+// UpdateTaskByState mocks base method
+func (m *MockDBStorage) UpdateTaskByState(arg0 *models.Task, arg1 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskByState", arg0, arg1)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskByState indicates an expected call of UpdateTaskByState
+func (mr *MockDBStorageMockRecorder) UpdateTaskByState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskByState", reflect.TypeOf((*MockDBStorage)(nil).UpdateTaskByState), arg0, arg1)
+}
+
+// UpdateTaskByStateTx mocks base method
+func (m *MockDBStorage) UpdateTaskByStateTx(arg0 *sqlx.Tx, arg1 *models.Task, arg2 string) (sql.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskByStateTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(sql.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskByStateTx indicates an expected call of UpdateTaskByStateTx
+func (mr *MockDBStorageMockRecorder) UpdateTaskByStateTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskByStateTx", reflect.TypeOf((*MockDBStorage)(nil).UpdateTaskByStateTx), arg0, arg1, arg2)
+}
+
 // UpdateTaskTx mocks base method
 func (m *MockDBStorage) UpdateTaskTx(arg0 *sqlx.Tx, arg1 *models.Task) (sql.Result, error) {
 	m.ctrl.T.Helper()
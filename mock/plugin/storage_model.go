@@ -372,6 +372,21 @@ func (mr *MockModelStorageMockRecorder) ListConfig(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfig", reflect.TypeOf((*MockModelStorage)(nil).ListConfig), arg0, arg1)
 }
 
+// ListNamespace mocks base method
+func (m *MockModelStorage) ListNamespace() ([]models.Namespace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNamespace")
+	ret0, _ := ret[0].([]models.Namespace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNamespace indicates an expected call of ListNamespace
+func (mr *MockModelStorageMockRecorder) ListNamespace() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespace", reflect.TypeOf((*MockModelStorage)(nil).ListNamespace))
+}
+
 // ListNode mocks base method
 func (m *MockModelStorage) ListNode(arg0 string, arg1 *models.ListOptions) (*models.NodeList, error) {
 	m.ctrl.T.Helper()
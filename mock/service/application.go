@@ -93,6 +93,21 @@ func (mr *MockApplicationServiceMockRecorder) Get(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockApplicationService)(nil).Get), arg0, arg1, arg2)
 }
 
+// GetVersion mocks base method
+func (m *MockApplicationService) GetVersion(arg0, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion
+func (mr *MockApplicationServiceMockRecorder) GetVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockApplicationService)(nil).GetVersion), arg0, arg1, arg2)
+}
+
 // List mocks base method
 func (m *MockApplicationService) List(arg0 string, arg1 *models.ListOptions) (*models.ApplicationList, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +123,21 @@ func (mr *MockApplicationServiceMockRecorder) List(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockApplicationService)(nil).List), arg0, arg1)
 }
 
+// ListVersions mocks base method
+func (m *MockApplicationService) ListVersions(arg0, arg1 string, arg2 *models.Filter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions
+func (mr *MockApplicationServiceMockRecorder) ListVersions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockApplicationService)(nil).ListVersions), arg0, arg1, arg2)
+}
+
 // Update mocks base method
 func (m *MockApplicationService) Update(arg0 string, arg1 *v1.Application) (*v1.Application, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,227 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: BatchService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBatchService is a mock of BatchService interface
+type MockBatchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchServiceMockRecorder
+}
+
+// MockBatchServiceMockRecorder is the mock recorder for MockBatchService
+type MockBatchServiceMockRecorder struct {
+	mock *MockBatchService
+}
+
+// NewMockBatchService creates a new mock instance
+func NewMockBatchService(ctrl *gomock.Controller) *MockBatchService {
+	mock := &MockBatchService{ctrl: ctrl}
+	mock.recorder = &MockBatchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBatchService) EXPECT() *MockBatchServiceMockRecorder {
+	return m.recorder
+}
+
+// ActivateRecord mocks base method
+func (m *MockBatchService) ActivateRecord(arg0 *models.Record) (*models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateRecord", arg0)
+	ret0, _ := ret[0].(*models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateRecord indicates an expected call of ActivateRecord
+func (mr *MockBatchServiceMockRecorder) ActivateRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateRecord", reflect.TypeOf((*MockBatchService)(nil).ActivateRecord), arg0)
+}
+
+// Count mocks base method
+func (m *MockBatchService) Count(arg0 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count
+func (mr *MockBatchServiceMockRecorder) Count(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockBatchService)(nil).Count), arg0)
+}
+
+// Create mocks base method
+func (m *MockBatchService) Create(arg0 *models.Batch) (*models.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*models.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockBatchServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBatchService)(nil).Create), arg0)
+}
+
+// CreateRecords mocks base method
+func (m *MockBatchService) CreateRecords(arg0, arg1 string, arg2 []string) ([]models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecords", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecords indicates an expected call of CreateRecords
+func (mr *MockBatchServiceMockRecorder) CreateRecords(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecords", reflect.TypeOf((*MockBatchService)(nil).CreateRecords), arg0, arg1, arg2)
+}
+
+// Delete mocks base method
+func (m *MockBatchService) Delete(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockBatchServiceMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBatchService)(nil).Delete), arg0, arg1)
+}
+
+// DeleteRecord mocks base method
+func (m *MockBatchService) DeleteRecord(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecord indicates an expected call of DeleteRecord
+func (mr *MockBatchServiceMockRecorder) DeleteRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecord", reflect.TypeOf((*MockBatchService)(nil).DeleteRecord), arg0, arg1, arg2)
+}
+
+// Get mocks base method
+func (m *MockBatchService) Get(arg0, arg1 string) (*models.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*models.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockBatchServiceMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBatchService)(nil).Get), arg0, arg1)
+}
+
+// GetRecord mocks base method
+func (m *MockBatchService) GetRecord(arg0, arg1, arg2 string) (*models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecord indicates an expected call of GetRecord
+func (mr *MockBatchServiceMockRecorder) GetRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecord", reflect.TypeOf((*MockBatchService)(nil).GetRecord), arg0, arg1, arg2)
+}
+
+// GetRecordByFingerprint mocks base method
+func (m *MockBatchService) GetRecordByFingerprint(arg0, arg1, arg2 string) (*models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordByFingerprint", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordByFingerprint indicates an expected call of GetRecordByFingerprint
+func (mr *MockBatchServiceMockRecorder) GetRecordByFingerprint(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordByFingerprint", reflect.TypeOf((*MockBatchService)(nil).GetRecordByFingerprint), arg0, arg1, arg2)
+}
+
+// List mocks base method
+func (m *MockBatchService) List(arg0 string, arg1 *models.Filter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockBatchServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBatchService)(nil).List), arg0, arg1)
+}
+
+// ListRecord mocks base method
+func (m *MockBatchService) ListRecord(arg0, arg1 string, arg2 *models.Filter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecord", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecord indicates an expected call of ListRecord
+func (mr *MockBatchServiceMockRecorder) ListRecord(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecord", reflect.TypeOf((*MockBatchService)(nil).ListRecord), arg0, arg1, arg2)
+}
+
+// Update mocks base method
+func (m *MockBatchService) Update(arg0 *models.Batch) (*models.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(*models.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockBatchServiceMockRecorder) Update(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBatchService)(nil).Update), arg0)
+}
+
+// UpdateRecord mocks base method
+func (m *MockBatchService) UpdateRecord(arg0 *models.Record) (*models.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecord", arg0)
+	ret0, _ := ret[0].(*models.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRecord indicates an expected call of UpdateRecord
+func (mr *MockBatchServiceMockRecorder) UpdateRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecord", reflect.TypeOf((*MockBatchService)(nil).UpdateRecord), arg0)
+}
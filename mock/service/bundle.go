@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: BundleService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBundleService is a mock of BundleService interface
+type MockBundleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBundleServiceMockRecorder
+}
+
+// MockBundleServiceMockRecorder is the mock recorder for MockBundleService
+type MockBundleServiceMockRecorder struct {
+	mock *MockBundleService
+}
+
+// NewMockBundleService creates a new mock instance
+func NewMockBundleService(ctrl *gomock.Controller) *MockBundleService {
+	mock := &MockBundleService{ctrl: ctrl}
+	mock.recorder = &MockBundleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBundleService) EXPECT() *MockBundleServiceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method
+func (m *MockBundleService) Export(arg0, arg1 string) (*models.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", arg0, arg1)
+	ret0, _ := ret[0].(*models.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export
+func (mr *MockBundleServiceMockRecorder) Export(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockBundleService)(nil).Export), arg0, arg1)
+}
+
+// Plan mocks base method
+func (m *MockBundleService) Plan(arg0, arg1 string, arg2 *models.Bundle, arg3 *models.BundleApplyOptions) (*models.BundlePlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Plan", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.BundlePlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Plan indicates an expected call of Plan
+func (mr *MockBundleServiceMockRecorder) Plan(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockBundleService)(nil).Plan), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,163 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: CallbackService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCallbackService is a mock of CallbackService interface
+type MockCallbackService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCallbackServiceMockRecorder
+}
+
+// MockCallbackServiceMockRecorder is the mock recorder for MockCallbackService
+type MockCallbackServiceMockRecorder struct {
+	mock *MockCallbackService
+}
+
+// NewMockCallbackService creates a new mock instance
+func NewMockCallbackService(ctrl *gomock.Controller) *MockCallbackService {
+	mock := &MockCallbackService{ctrl: ctrl}
+	mock.recorder = &MockCallbackServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCallbackService) EXPECT() *MockCallbackServiceMockRecorder {
+	return m.recorder
+}
+
+// Callback mocks base method
+func (m *MockCallbackService) Callback(arg0, arg1 string, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Callback", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Callback indicates an expected call of Callback
+func (mr *MockCallbackServiceMockRecorder) Callback(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Callback", reflect.TypeOf((*MockCallbackService)(nil).Callback), arg0, arg1, arg2)
+}
+
+// Close mocks base method
+func (m *MockCallbackService) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockCallbackServiceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCallbackService)(nil).Close))
+}
+
+// Create mocks base method
+func (m *MockCallbackService) Create(arg0 *models.Callback) (*models.Callback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*models.Callback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCallbackServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCallbackService)(nil).Create), arg0)
+}
+
+// Delete mocks base method
+func (m *MockCallbackService) Delete(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockCallbackServiceMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCallbackService)(nil).Delete), arg0, arg1)
+}
+
+// Dispatch mocks base method
+func (m *MockCallbackService) Dispatch(arg0, arg1 string, arg2 map[string]string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Dispatch", arg0, arg1, arg2)
+}
+
+// Dispatch indicates an expected call of Dispatch
+func (mr *MockCallbackServiceMockRecorder) Dispatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockCallbackService)(nil).Dispatch), arg0, arg1, arg2)
+}
+
+// Get mocks base method
+func (m *MockCallbackService) Get(arg0, arg1 string) (*models.Callback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*models.Callback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockCallbackServiceMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCallbackService)(nil).Get), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockCallbackService) List(arg0 string, arg1 *models.Filter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockCallbackServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCallbackService)(nil).List), arg0, arg1)
+}
+
+// ListLog mocks base method
+func (m *MockCallbackService) ListLog(arg0, arg1 string, arg2 *models.Filter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLog", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLog indicates an expected call of ListLog
+func (mr *MockCallbackServiceMockRecorder) ListLog(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLog", reflect.TypeOf((*MockCallbackService)(nil).ListLog), arg0, arg1, arg2)
+}
+
+// Update mocks base method
+func (m *MockCallbackService) Update(arg0 *models.Callback) (*models.Callback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(*models.Callback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockCallbackServiceMockRecorder) Update(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCallbackService)(nil).Update), arg0)
+}
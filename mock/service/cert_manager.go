@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: CertManagerService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockCertManagerService is a mock of CertManagerService interface
+type MockCertManagerService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCertManagerServiceMockRecorder
+}
+
+// MockCertManagerServiceMockRecorder is the mock recorder for MockCertManagerService
+type MockCertManagerServiceMockRecorder struct {
+	mock *MockCertManagerService
+}
+
+// NewMockCertManagerService creates a new mock instance
+func NewMockCertManagerService(ctrl *gomock.Controller) *MockCertManagerService {
+	mock := &MockCertManagerService{ctrl: ctrl}
+	mock.recorder = &MockCertManagerServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCertManagerService) EXPECT() *MockCertManagerServiceMockRecorder {
+	return m.recorder
+}
+
+// ListExpiringCerts mocks base method
+func (m *MockCertManagerService) ListExpiringCerts(arg0 time.Duration) ([]models.ExpiringCert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiringCerts", arg0)
+	ret0, _ := ret[0].([]models.ExpiringCert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiringCerts indicates an expected call of ListExpiringCerts
+func (mr *MockCertManagerServiceMockRecorder) ListExpiringCerts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiringCerts", reflect.TypeOf((*MockCertManagerService)(nil).ListExpiringCerts), arg0)
+}
+
+// Reconcile mocks base method
+func (m *MockCertManagerService) Reconcile() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reconcile indicates an expected call of Reconcile
+func (mr *MockCertManagerServiceMockRecorder) Reconcile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockCertManagerService)(nil).Reconcile))
+}
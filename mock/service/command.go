@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: CommandService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCommandService is a mock of CommandService interface
+type MockCommandService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommandServiceMockRecorder
+}
+
+// MockCommandServiceMockRecorder is the mock recorder for MockCommandService
+type MockCommandServiceMockRecorder struct {
+	mock *MockCommandService
+}
+
+// NewMockCommandService creates a new mock instance
+func NewMockCommandService(ctrl *gomock.Controller) *MockCommandService {
+	mock := &MockCommandService{ctrl: ctrl}
+	mock.recorder = &MockCommandServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCommandService) EXPECT() *MockCommandServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockCommandService) Create(arg0 *models.Command) (*models.Command, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(*models.Command)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCommandServiceMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommandService)(nil).Create), arg0)
+}
+
+// Get mocks base method
+func (m *MockCommandService) Get(arg0, arg1, arg2 string) (*models.Command, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Command)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockCommandServiceMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCommandService)(nil).Get), arg0, arg1, arg2)
+}
+
+// List mocks base method
+func (m *MockCommandService) List(arg0, arg1 string, arg2 *models.TaskFilter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockCommandServiceMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCommandService)(nil).List), arg0, arg1, arg2)
+}
+
+// ListPending mocks base method
+func (m *MockCommandService) ListPending(arg0, arg1 string) ([]models.Command, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", arg0, arg1)
+	ret0, _ := ret[0].([]models.Command)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending
+func (mr *MockCommandServiceMockRecorder) ListPending(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockCommandService)(nil).ListPending), arg0, arg1)
+}
+
+// Respond mocks base method
+func (m *MockCommandService) Respond(arg0, arg1 string, arg2 *models.CommandResult) (*models.Command, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Respond", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Command)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Respond indicates an expected call of Respond
+func (mr *MockCommandServiceMockRecorder) Respond(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Respond", reflect.TypeOf((*MockCommandService)(nil).Respond), arg0, arg1, arg2)
+}
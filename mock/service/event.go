@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: EventService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockEventService is a mock of EventService interface
+type MockEventService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventServiceMockRecorder
+}
+
+// MockEventServiceMockRecorder is the mock recorder for MockEventService
+type MockEventServiceMockRecorder struct {
+	mock *MockEventService
+}
+
+// NewMockEventService creates a new mock instance
+func NewMockEventService(ctrl *gomock.Controller) *MockEventService {
+	mock := &MockEventService{ctrl: ctrl}
+	mock.recorder = &MockEventServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockEventService) EXPECT() *MockEventServiceMockRecorder {
+	return m.recorder
+}
+
+// PublishNodeEvent mocks base method
+func (m *MockEventService) PublishNodeEvent(arg0 *models.NodeEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishNodeEvent", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishNodeEvent indicates an expected call of PublishNodeEvent
+func (mr *MockEventServiceMockRecorder) PublishNodeEvent(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishNodeEvent", reflect.TypeOf((*MockEventService)(nil).PublishNodeEvent), arg0)
+}
+
+// SubscribeNodeEvent mocks base method
+func (m *MockEventService) SubscribeNodeEvent(arg0 string) (chan interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeNodeEvent", arg0)
+	ret0, _ := ret[0].(chan interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeNodeEvent indicates an expected call of SubscribeNodeEvent
+func (mr *MockEventServiceMockRecorder) SubscribeNodeEvent(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeNodeEvent", reflect.TypeOf((*MockEventService)(nil).SubscribeNodeEvent), arg0)
+}
+
+// UnsubscribeNodeEvent mocks base method
+func (m *MockEventService) UnsubscribeNodeEvent(arg0 string, arg1 chan interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeNodeEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribeNodeEvent indicates an expected call of UnsubscribeNodeEvent
+func (mr *MockEventServiceMockRecorder) UnsubscribeNodeEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeNodeEvent", reflect.TypeOf((*MockEventService)(nil).UnsubscribeNodeEvent), arg0, arg1)
+}
@@ -0,0 +1,61 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: LockService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockLockService is a mock of LockService interface
+type MockLockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockServiceMockRecorder
+}
+
+// MockLockServiceMockRecorder is the mock recorder for MockLockService
+type MockLockServiceMockRecorder struct {
+	mock *MockLockService
+}
+
+// NewMockLockService creates a new mock instance
+func NewMockLockService(ctrl *gomock.Controller) *MockLockService {
+	mock := &MockLockService{ctrl: ctrl}
+	mock.recorder = &MockLockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLockService) EXPECT() *MockLockServiceMockRecorder {
+	return m.recorder
+}
+
+// Lock mocks base method
+func (m *MockLockService) Lock(arg0 string, arg1 time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock
+func (mr *MockLockServiceMockRecorder) Lock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLockService)(nil).Lock), arg0, arg1)
+}
+
+// Unlock mocks base method
+func (m *MockLockService) Unlock(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Unlock", arg0)
+}
+
+// Unlock indicates an expected call of Unlock
+func (mr *MockLockServiceMockRecorder) Unlock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockLockService)(nil).Unlock), arg0)
+}
@@ -123,6 +123,21 @@ func (mr *MockNodeServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNodeService)(nil).List), arg0, arg1)
 }
 
+// ListDeployHistory mocks base method
+func (m *MockNodeService) ListDeployHistory(arg0, arg1 string, arg2 *models.NodeDeployFilter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeployHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeployHistory indicates an expected call of ListDeployHistory
+func (mr *MockNodeServiceMockRecorder) ListDeployHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeployHistory", reflect.TypeOf((*MockNodeService)(nil).ListDeployHistory), arg0, arg1, arg2)
+}
+
 // Update mocks base method
 func (m *MockNodeService) Update(arg0 string, arg1 *v1.Node) (*v1.Node, error) {
 	m.ctrl.T.Helper()
@@ -139,33 +154,33 @@ func (mr *MockNodeServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Ca
 }
 
 // UpdateDesire mocks base method
-func (m *MockNodeService) UpdateDesire(arg0, arg1 string, arg2 v1.Desire) (*models.Shadow, error) {
+func (m *MockNodeService) UpdateDesire(arg0, arg1 string, arg2 v1.Desire, arg3 string) (*models.Shadow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateDesire", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateDesire", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*models.Shadow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateDesire indicates an expected call of UpdateDesire
-func (mr *MockNodeServiceMockRecorder) UpdateDesire(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockNodeServiceMockRecorder) UpdateDesire(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDesire", reflect.TypeOf((*MockNodeService)(nil).UpdateDesire), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDesire", reflect.TypeOf((*MockNodeService)(nil).UpdateDesire), arg0, arg1, arg2, arg3)
 }
 
 // UpdateNodeAppVersion mocks base method
-func (m *MockNodeService) UpdateNodeAppVersion(arg0 string, arg1 *v1.Application) ([]string, error) {
+func (m *MockNodeService) UpdateNodeAppVersion(arg0 string, arg1 *v1.Application, arg2 string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateNodeAppVersion", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateNodeAppVersion", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateNodeAppVersion indicates an expected call of UpdateNodeAppVersion
-func (mr *MockNodeServiceMockRecorder) UpdateNodeAppVersion(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNodeServiceMockRecorder) UpdateNodeAppVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeAppVersion", reflect.TypeOf((*MockNodeService)(nil).UpdateNodeAppVersion), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeAppVersion", reflect.TypeOf((*MockNodeService)(nil).UpdateNodeAppVersion), arg0, arg1, arg2)
 }
 
 // UpdateReport mocks base method
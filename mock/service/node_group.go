@@ -0,0 +1,169 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: NodeGroupService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockNodeGroupService is a mock of NodeGroupService interface
+type MockNodeGroupService struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeGroupServiceMockRecorder
+}
+
+// MockNodeGroupServiceMockRecorder is the mock recorder for MockNodeGroupService
+type MockNodeGroupServiceMockRecorder struct {
+	mock *MockNodeGroupService
+}
+
+// NewMockNodeGroupService creates a new mock instance
+func NewMockNodeGroupService(ctrl *gomock.Controller) *MockNodeGroupService {
+	mock := &MockNodeGroupService{ctrl: ctrl}
+	mock.recorder = &MockNodeGroupServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNodeGroupService) EXPECT() *MockNodeGroupServiceMockRecorder {
+	return m.recorder
+}
+
+// AssignApp mocks base method
+func (m *MockNodeGroupService) AssignApp(arg0, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignApp indicates an expected call of AssignApp
+func (mr *MockNodeGroupServiceMockRecorder) AssignApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignApp", reflect.TypeOf((*MockNodeGroupService)(nil).AssignApp), arg0, arg1, arg2)
+}
+
+// Create mocks base method
+func (m *MockNodeGroupService) Create(arg0 string, arg1 *models.NodeGroup) (*models.NodeGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*models.NodeGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockNodeGroupServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNodeGroupService)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockNodeGroupService) Delete(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockNodeGroupServiceMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNodeGroupService)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method
+func (m *MockNodeGroupService) Get(arg0, arg1 string) (*models.NodeGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*models.NodeGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockNodeGroupServiceMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNodeGroupService)(nil).Get), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockNodeGroupService) List(arg0 string, arg1 *models.Filter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockNodeGroupServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNodeGroupService)(nil).List), arg0, arg1)
+}
+
+// ListNodes mocks base method
+func (m *MockNodeGroupService) ListNodes(arg0, arg1 string) (*models.NodeList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNodes", arg0, arg1)
+	ret0, _ := ret[0].(*models.NodeList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNodes indicates an expected call of ListNodes
+func (mr *MockNodeGroupServiceMockRecorder) ListNodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodes", reflect.TypeOf((*MockNodeGroupService)(nil).ListNodes), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockNodeGroupService) Update(arg0 string, arg1 *models.NodeGroup) (*models.NodeGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*models.NodeGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockNodeGroupServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNodeGroupService)(nil).Update), arg0, arg1)
+}
+
+// UpdateLabels mocks base method
+func (m *MockNodeGroupService) UpdateLabels(arg0, arg1 string, arg2 *models.NodeGroupLabels) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabels", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLabels indicates an expected call of UpdateLabels
+func (mr *MockNodeGroupServiceMockRecorder) UpdateLabels(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabels", reflect.TypeOf((*MockNodeGroupService)(nil).UpdateLabels), arg0, arg1, arg2)
+}
+
+// UpdateMembers mocks base method
+func (m *MockNodeGroupService) UpdateMembers(arg0, arg1 string, arg2 *models.NodeGroupMembers) (*models.NodeGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMembers", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.NodeGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMembers indicates an expected call of UpdateMembers
+func (mr *MockNodeGroupServiceMockRecorder) UpdateMembers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMembers", reflect.TypeOf((*MockNodeGroupService)(nil).UpdateMembers), arg0, arg1, arg2)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: OTAService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockOTAService is a mock of OTAService interface
+type MockOTAService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOTAServiceMockRecorder
+}
+
+// MockOTAServiceMockRecorder is the mock recorder for MockOTAService
+type MockOTAServiceMockRecorder struct {
+	mock *MockOTAService
+}
+
+// NewMockOTAService creates a new mock instance
+func NewMockOTAService(ctrl *gomock.Controller) *MockOTAService {
+	mock := &MockOTAService{ctrl: ctrl}
+	mock.recorder = &MockOTAServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOTAService) EXPECT() *MockOTAServiceMockRecorder {
+	return m.recorder
+}
+
+// Advance mocks base method
+func (m *MockOTAService) Advance(arg0, arg1 string, arg2 v1.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Advance", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Advance indicates an expected call of Advance
+func (mr *MockOTAServiceMockRecorder) Advance(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Advance", reflect.TypeOf((*MockOTAService)(nil).Advance), arg0, arg1, arg2)
+}
+
+// Cancel mocks base method
+func (m *MockOTAService) Cancel(arg0, arg1, arg2 string) (*models.OTATask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.OTATask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel
+func (mr *MockOTAServiceMockRecorder) Cancel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockOTAService)(nil).Cancel), arg0, arg1, arg2)
+}
+
+// Create mocks base method
+func (m *MockOTAService) Create(arg0 string, arg1 *models.OTARequest) (*models.OTAView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*models.OTAView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockOTAServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOTAService)(nil).Create), arg0, arg1)
+}
+
+// Get mocks base method
+func (m *MockOTAService) Get(arg0, arg1, arg2 string) (*models.OTATask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.OTATask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockOTAServiceMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOTAService)(nil).Get), arg0, arg1, arg2)
+}
+
+// List mocks base method
+func (m *MockOTAService) List(arg0, arg1 string, arg2 *models.TaskFilter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockOTAServiceMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOTAService)(nil).List), arg0, arg1, arg2)
+}
+
+// Retry mocks base method
+func (m *MockOTAService) Retry(arg0, arg1, arg2 string) (*models.OTATask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.OTATask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retry indicates an expected call of Retry
+func (mr *MockOTAServiceMockRecorder) Retry(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockOTAService)(nil).Retry), arg0, arg1, arg2)
+}
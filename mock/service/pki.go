@@ -76,6 +76,21 @@ func (mr *MockPKIServiceMockRecorder) GetCA() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCA", reflect.TypeOf((*MockPKIService)(nil).GetCA))
 }
 
+// RotatePrivateKeys mocks base method
+func (m *MockPKIService) RotatePrivateKeys() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotatePrivateKeys")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotatePrivateKeys indicates an expected call of RotatePrivateKeys
+func (mr *MockPKIServiceMockRecorder) RotatePrivateKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotatePrivateKeys", reflect.TypeOf((*MockPKIService)(nil).RotatePrivateKeys))
+}
+
 // SignClientCertificate mocks base method
 func (m *MockPKIService) SignClientCertificate(arg0 string, arg1 models.AltNames) (*models.PEMCredential, error) {
 	m.ctrl.T.Helper()
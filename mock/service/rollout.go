@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: RolloutService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockRolloutService is a mock of RolloutService interface
+type MockRolloutService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRolloutServiceMockRecorder
+}
+
+// MockRolloutServiceMockRecorder is the mock recorder for MockRolloutService
+type MockRolloutServiceMockRecorder struct {
+	mock *MockRolloutService
+}
+
+// NewMockRolloutService creates a new mock instance
+func NewMockRolloutService(ctrl *gomock.Controller) *MockRolloutService {
+	mock := &MockRolloutService{ctrl: ctrl}
+	mock.recorder = &MockRolloutServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRolloutService) EXPECT() *MockRolloutServiceMockRecorder {
+	return m.recorder
+}
+
+// Abort mocks base method
+func (m *MockRolloutService) Abort(arg0, arg1 string) (*models.Rollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abort", arg0, arg1)
+	ret0, _ := ret[0].(*models.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Abort indicates an expected call of Abort
+func (mr *MockRolloutServiceMockRecorder) Abort(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abort", reflect.TypeOf((*MockRolloutService)(nil).Abort), arg0, arg1)
+}
+
+// Create mocks base method
+func (m *MockRolloutService) Create(arg0 string, arg1 *v1.Application, arg2 *models.RolloutStrategy) (*models.Rollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockRolloutServiceMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRolloutService)(nil).Create), arg0, arg1, arg2)
+}
+
+// Get mocks base method
+func (m *MockRolloutService) Get(arg0, arg1 string) (*models.Rollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*models.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *MockRolloutServiceMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRolloutService)(nil).Get), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockRolloutService) List(arg0 string, arg1 *models.Filter) (*models.ListView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].(*models.ListView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockRolloutServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRolloutService)(nil).List), arg0, arg1)
+}
+
+// Pause mocks base method
+func (m *MockRolloutService) Pause(arg0, arg1 string) (*models.Rollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", arg0, arg1)
+	ret0, _ := ret[0].(*models.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pause indicates an expected call of Pause
+func (mr *MockRolloutServiceMockRecorder) Pause(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockRolloutService)(nil).Pause), arg0, arg1)
+}
+
+// Reconcile mocks base method
+func (m *MockRolloutService) Reconcile() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reconcile indicates an expected call of Reconcile
+func (mr *MockRolloutServiceMockRecorder) Reconcile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockRolloutService)(nil).Reconcile))
+}
+
+// Resume mocks base method
+func (m *MockRolloutService) Resume(arg0, arg1 string) (*models.Rollout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", arg0, arg1)
+	ret0, _ := ret[0].(*models.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resume indicates an expected call of Resume
+func (mr *MockRolloutServiceMockRecorder) Resume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockRolloutService)(nil).Resume), arg0, arg1)
+}
+
+// UpdateNodeAppVersion mocks base method
+func (m *MockRolloutService) UpdateNodeAppVersion(arg0 string, arg1 *v1.Application, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeAppVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNodeAppVersion indicates an expected call of UpdateNodeAppVersion
+func (mr *MockRolloutServiceMockRecorder) UpdateNodeAppVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeAppVersion", reflect.TypeOf((*MockRolloutService)(nil).UpdateNodeAppVersion), arg0, arg1, arg2)
+}
@@ -33,6 +33,21 @@ func (m *MockSyncService) EXPECT() *MockSyncServiceMockRecorder {
 	return m.recorder
 }
 
+// Delta mocks base method
+func (m *MockSyncService) Delta(arg0, arg1 string) (v1.Desire, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delta", arg0, arg1)
+	ret0, _ := ret[0].(v1.Desire)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delta indicates an expected call of Delta
+func (mr *MockSyncServiceMockRecorder) Delta(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delta", reflect.TypeOf((*MockSyncService)(nil).Delta), arg0, arg1)
+}
+
 // Desire mocks base method
 func (m *MockSyncService) Desire(arg0 string, arg1 []v1.ResourceInfo, arg2 map[string]string) ([]v1.ResourceValue, error) {
 	m.ctrl.T.Helper()
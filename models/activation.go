@@ -1,10 +1,25 @@
 package models
 
+import "github.com/baetyl/baetyl-cloud/v2/common"
+
 type Activation struct {
 	FingerprintValue string            `json:"fingerprintValue,omitempty" db:"fingerprint_value"`
 	PenetrateData    map[string]string `json:"penetrateData,omitempty" db:"penetrate_data"`
 }
 
+// ActiveRequest the request of a device to activate itself by the fingerprint value
+type ActiveRequest struct {
+	Namespace        string          `json:"namespace,omitempty" validate:"resourceName"`
+	BatchName        string          `json:"batchName,omitempty" validate:"resourceName"`
+	FingerprintValue string          `json:"fingerprintValue,omitempty" validate:"fingerprintValue"`
+	SecurityType     common.Security `json:"securityType,omitempty"`
+	SecurityValue    string          `json:"securityValue,omitempty"`
+	// Timestamp the unix seconds when the security value is signed, a signature is only accepted around it
+	Timestamp     int64             `json:"timestamp,omitempty"`
+	KubeNodeName  string            `json:"kubeNodeName,omitempty"`
+	PenetrateData map[string]string `json:"penetrateData,omitempty"`
+}
+
 type PackageParam struct {
 	Platform string `form:"platform"`
 }
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// APIKeyPrefix the prefix of api keys, used to tell api keys from signed tokens
+const APIKeyPrefix = "bk_"
+
+// APIKey the credential of a user to access the admin api of the namespaces
+type APIKey struct {
+	Name        string     `json:"name,omitempty" validate:"omitempty,resourceName"`
+	User        string     `json:"user,omitempty" binding:"required"`
+	Namespaces  []string   `json:"namespaces,omitempty" binding:"required"`
+	Key         string     `json:"key,omitempty"` // only returned when the key is created
+	KeyPrefix   string     `json:"keyPrefix,omitempty"`
+	KeyHash     string     `json:"-"`
+	Description string     `json:"description,omitempty"`
+	ExpireTime  *time.Time `json:"expireTime,omitempty"`
+	CreateTime  time.Time  `json:"createTime,omitempty"`
+	UpdateTime  time.Time  `json:"updateTime,omitempty"`
+}
+
+// Expired returns true if the key is expired at the time
+func (k *APIKey) Expired(t time.Time) bool {
+	return k.ExpireTime != nil && !k.ExpireTime.IsZero() && !t.Before(*k.ExpireTime)
+}
+
+// TokenClaims the claims of the bearer token signed by the auth plugin
+type TokenClaims struct {
+	User       string    `json:"user,omitempty"`
+	Namespaces []string  `json:"namespaces,omitempty"`
+	ExpireTime time.Time `json:"expireTime,omitempty"`
+}
+
+// TokenRequest the request to issue a bearer token
+type TokenRequest struct {
+	User       string   `json:"user,omitempty" binding:"required"`
+	Namespaces []string `json:"namespaces,omitempty" binding:"required"`
+	Expiration int64    `json:"expiration,omitempty"` // seconds, default 1 hour
+}
+
+// TokenView the bearer token issued
+type TokenView struct {
+	Token      string    `json:"token"`
+	ExpireTime time.Time `json:"expireTime"`
+}
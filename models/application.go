@@ -19,6 +19,7 @@ type ApplicationView struct {
 	Description       string            `json:"description,omitempty"`
 	System            bool              `json:"system,omitempty"`
 	Registries        []RegistryView    `json:"registries,omitempty"`
+	Rollout           *RolloutStrategy  `json:"rollout,omitempty"`
 }
 
 // VolumeView volume view
@@ -50,6 +51,21 @@ type ApplicationList struct {
 	Items       []AppItem    `json:"items"`
 }
 
+// ApplicationVersion a historical version of the application
+type ApplicationVersion struct {
+	Version     string              `json:"version,omitempty"`
+	CreateTime  time.Time           `json:"createTime,omitempty"`
+	Diffs       []ApplicationDiff   `json:"diffs,omitempty"`
+	Application *specV1.Application `json:"application,omitempty"`
+}
+
+// ApplicationDiff a changed field compared with the previous version
+type ApplicationDiff struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
 type ServiceFunction struct {
 	Functions []specV1.ServiceFunction `json:"functions,omitempty"`
 }
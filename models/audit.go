@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// the operations recorded in audit logs, other actions on resources (e.g. rollback) are recorded by their names
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditLog the record of a mutating request of the admin or mis server
+type AuditLog struct {
+	Id         int64     `json:"id,omitempty" db:"id"`
+	Namespace  string    `json:"namespace,omitempty" db:"namespace"`
+	User       string    `json:"user,omitempty" db:"user_id"`
+	RequestID  string    `json:"requestId,omitempty" db:"request_id"`
+	Method     string    `json:"method,omitempty" db:"method"`
+	Path       string    `json:"path,omitempty" db:"path"`
+	Resource   string    `json:"resource,omitempty" db:"resource"`
+	Name       string    `json:"name,omitempty" db:"name"`
+	Operation  string    `json:"operation,omitempty" db:"operation"`
+	Status     int       `json:"status,omitempty" db:"status"`
+	Code       string    `json:"code,omitempty" db:"code"` // the error code, empty if the request succeeded
+	Diff       string    `json:"diff,omitempty" db:"diff"` // the redacted changes, {"field": [before, after]}
+	CreateTime time.Time `json:"createTime,omitempty" db:"create_time"`
+}
+
+// AuditFilter the filter of audit logs, the name matches the resource name fuzzily
+type AuditFilter struct {
+	Filter
+	Namespace string `form:"namespace,omitempty"`
+	User      string `form:"user,omitempty"`
+	Resource  string `form:"resource,omitempty"`
+	Operation string `form:"operation,omitempty"`
+	Start     int64  `form:"start,omitempty"`
+	End       int64  `form:"end,omitempty"`
+}
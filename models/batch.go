@@ -10,7 +10,7 @@ type Batch struct {
 	Name            string            `json:"name,omitempty" validate:"omitempty,resourceName"`
 	Namespace       string            `json:"namespace,omitempty"`
 	Description     string            `json:"description,omitempty"`
-	QuotaNum        int               `json:"quotaNum,omitempty"`
+	QuotaNum        int               `json:"quotaNum,omitempty" default:"200"`
 	EnableWhitelist int               `json:"enableWhitelist,omitempty"`
 	SecurityType    common.Security   `json:"securityType,omitempty"`
 	SecurityKey     string            `json:"securityKey,omitempty"`
@@ -0,0 +1,75 @@
+package models
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+// BundleVersion the version of the bundle format
+const BundleVersion = "v1"
+
+// BundleEncryptionAlgorithm the algorithm encrypting the data of the secrets in the bundle,
+// the key is derived from the passphrase by PBKDF2 with HMAC-SHA256
+const BundleEncryptionAlgorithm = "pbkdf2-sha256+aes-256-gcm"
+
+// the kinds of the resources in the bundle, in the order of creating and updating,
+// the resources are deleted in the reverse order
+const (
+	BundleKindConfig      = "config"
+	BundleKindSecret      = "secret"
+	BundleKindRegistry    = "registry"
+	BundleKindCertificate = "certificate"
+	BundleKindNode        = "node"
+	BundleKindApp         = "app"
+)
+
+// the actions of the changes to apply the bundle
+const (
+	BundleActionCreate = "create"
+	BundleActionUpdate = "update"
+	BundleActionDelete = "delete"
+)
+
+// Bundle the resources created by users in a namespace, system resources are not included
+type Bundle struct {
+	Version      string                 `json:"version" yaml:"version"`
+	Namespace    string                 `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Encryption   *BundleEncryption      `json:"encryption,omitempty" yaml:"encryption,omitempty"`
+	Configs      []specV1.Configuration `json:"configs,omitempty" yaml:"configs,omitempty"`
+	Secrets      []specV1.Secret        `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	Registries   []specV1.Secret        `json:"registries,omitempty" yaml:"registries,omitempty"`
+	Certificates []specV1.Secret        `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+	Nodes        []specV1.Node          `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Applications []specV1.Application   `json:"apps,omitempty" yaml:"apps,omitempty"`
+}
+
+// BundleEncryption the encryption of the data of the secrets in the bundle
+type BundleEncryption struct {
+	Algorithm  string `json:"algorithm" yaml:"algorithm"`
+	Salt       []byte `json:"salt" yaml:"salt"`
+	Iterations int    `json:"iterations" yaml:"iterations"`
+}
+
+// BundleApplyOptions the options to apply the bundle
+type BundleApplyOptions struct {
+	DryRun bool `form:"dryRun,omitempty"`
+	Prune  bool `form:"prune,omitempty"`
+}
+
+// BundleChange the change of a resource to apply the bundle
+type BundleChange struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Action  string `json:"action"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+	// the resource in the bundle, nil if deleted
+	Resource interface{} `json:"-"`
+}
+
+// BundlePlan the changes to apply the bundle in the dependency order
+type BundlePlan struct {
+	DryRun    bool           `json:"dryRun"`
+	Prune     bool           `json:"prune"`
+	Unchanged int            `json:"unchanged"`
+	Changes   []BundleChange `json:"changes"`
+}
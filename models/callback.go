@@ -16,3 +16,19 @@ type Callback struct {
 	CreateTime  time.Time         `json:"createTime,omitempty"`
 	UpdateTime  time.Time         `json:"updateTime,omitempty"`
 }
+
+// CallbackLog the delivery of a callback request
+type CallbackLog struct {
+	Id           int64     `json:"id,omitempty" db:"id"`
+	Namespace    string    `json:"namespace,omitempty" db:"namespace"`
+	CallbackName string    `json:"callbackName,omitempty" db:"callback_name"`
+	Event        string    `json:"event,omitempty" db:"event"`
+	Method       string    `json:"method,omitempty" db:"method"`
+	Url          string    `json:"url,omitempty" db:"url"`
+	Request      string    `json:"request,omitempty" db:"request"`
+	StatusCode   int       `json:"statusCode,omitempty" db:"status_code"`
+	Response     string    `json:"response,omitempty" db:"response"`
+	Error        string    `json:"error,omitempty" db:"error"`
+	Attempts     int       `json:"attempts,omitempty" db:"attempts"`
+	CreateTime   time.Time `json:"createTime,omitempty" db:"create_time"`
+}
@@ -139,3 +139,25 @@ func fingerprint(data []byte) string {
 	}
 	return buf.String()
 }
+
+// the types of the certificates issued by the pki
+const (
+	CertTypeRoot   = "root"
+	CertTypeServer = "server"
+	CertTypeNode   = "node"
+	// CertTypeOrphan the certificate signed for the node which is deleted
+	CertTypeOrphan = "orphan"
+)
+
+// ExpiringCert the certificate issued by the pki which is expiring or expired,
+// the namespace and the name of the node are set for the node certificates
+type ExpiringCert struct {
+	CertID     string    `json:"certId"`
+	Type       string    `json:"type"`
+	CommonName string    `json:"commonName"`
+	Namespace  string    `json:"namespace,omitempty"`
+	NodeName   string    `json:"nodeName,omitempty"`
+	NotBefore  time.Time `json:"notBefore"`
+	NotAfter   time.Time `json:"notAfter"`
+	Expired    bool      `json:"expired"`
+}
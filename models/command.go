@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// the types of command
+const (
+	CommandRestartService = "restartService"
+	CommandTailLogs       = "tailLogs"
+	CommandNodeInfo       = "nodeInfo"
+)
+
+// the states of command
+const (
+	CommandPending   = "pending"
+	CommandSucceeded = "succeeded"
+	CommandFailed    = "failed"
+	CommandTimeout   = "timeout"
+)
+
+// TaskCommand the type of the tasks recording the commands
+const TaskCommand = "command"
+
+// CommandTypes the types of command which nodes are able to run
+var CommandTypes = map[string]bool{
+	CommandRestartService: true,
+	CommandTailLogs:       true,
+	CommandNodeInfo:       true,
+}
+
+// Command the command sent from the cloud to a node, which is recorded as a task of the node.
+// The command is pending until the node responds or the timeout (seconds) expires
+type Command struct {
+	ID         string            `json:"id,omitempty"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Node       string            `json:"node,omitempty"`
+	Type       string            `json:"type,omitempty"`
+	Args       map[string]string `json:"args,omitempty"`
+	Timeout    int               `json:"timeout,omitempty"`
+	State      string            `json:"state,omitempty"`
+	Result     interface{}       `json:"result,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	CreateTime time.Time         `json:"createTime,omitempty"`
+	UpdateTime time.Time         `json:"updateTime,omitempty"`
+}
+
+// CommandResult the result of the command responded by the node, the command fails if the error is not empty
+type CommandResult struct {
+	ID     string      `json:"id,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// commandStep the step of the task, which keeps the command and its result
+type commandStep struct {
+	Type    string            `json:"type"`
+	Args    map[string]string `json:"args,omitempty"`
+	Timeout int               `json:"timeout"`
+	Result  interface{}       `json:"result,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// Deadline returns the time when the pending command times out
+func (c *Command) Deadline() time.Time {
+	return c.CreateTime.Add(time.Duration(c.Timeout) * time.Second)
+}
+
+// ToTask returns the task recording the command
+func (c *Command) ToTask() (*Task, error) {
+	step, err := json.Marshal(&commandStep{
+		Type:    c.Type,
+		Args:    c.Args,
+		Timeout: c.Timeout,
+		Result:  c.Result,
+		Error:   c.Error,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Task{
+		TraceId:    c.ID,
+		Namespace:  c.Namespace,
+		Node:       c.Node,
+		Type:       TaskCommand,
+		State:      c.State,
+		Step:       string(step),
+		CreateTime: c.CreateTime,
+		UpdateTime: c.UpdateTime,
+	}, nil
+}
+
+// NewCommandFromTask returns the command recorded by the task
+func NewCommandFromTask(task *Task) (*Command, error) {
+	var step commandStep
+	if err := json.Unmarshal([]byte(task.Step), &step); err != nil {
+		return nil, err
+	}
+	return &Command{
+		ID:         task.TraceId,
+		Namespace:  task.Namespace,
+		Node:       task.Node,
+		Type:       step.Type,
+		Args:       step.Args,
+		Timeout:    step.Timeout,
+		State:      task.State,
+		Result:     step.Result,
+		Error:      step.Error,
+		CreateTime: task.CreateTime,
+		UpdateTime: task.UpdateTime,
+	}, nil
+}
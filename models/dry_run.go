@@ -0,0 +1,45 @@
+package models
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+// the actions of the changes to the desires of the nodes
+const (
+	DesireActionAdd    = "add"
+	DesireActionUpdate = "update"
+	DesireActionRemove = "remove"
+)
+
+// DryRunReference the config, secret, certificate or registry referenced by an application,
+// the kind is one of the kinds of the resources in the bundle
+type DryRunReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// DesireChange the change of an application in the desire of a node
+type DesireChange struct {
+	Node   string `json:"node"`
+	App    string `json:"app"`
+	Action string `json:"action"`
+	// the version of the application in the desire before the change
+	Version string `json:"version,omitempty"`
+	// the wave of the rollout in which the desire is changed, 0 if changed at once
+	Wave int `json:"wave,omitempty"`
+}
+
+// DryRunResult the result of creating or updating a resource in dry run, nothing is persisted
+type DryRunResult struct {
+	Valid    bool        `json:"valid"`
+	Resource interface{} `json:"resource,omitempty"`
+	// the configs generated for the services of a function application
+	Configs []specV1.Configuration `json:"configs,omitempty"`
+	Missing []DryRunReference      `json:"missing,omitempty"`
+	// the applications referencing the config or secret
+	Apps    []string       `json:"apps,omitempty"`
+	Nodes   []string       `json:"nodes,omitempty"`
+	Desires []DesireChange `json:"desires,omitempty"`
+	// the nodes updated in each wave if the update is rolled out
+	Waves [][]string `json:"waves,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+// the types of node event
+const (
+	NodeEventOnline  = "online"
+	NodeEventOffline = "offline"
+	NodeEventReport  = "report"
+	NodeEventDesire  = "desire"
+	NodeEventCommand = "command"
+)
+
+// NodeEvent the change of a node pushed to the event stream
+type NodeEvent struct {
+	Type      string        `json:"type"`
+	Namespace string        `json:"namespace"`
+	Name      string        `json:"name"`
+	Report    specV1.Report `json:"report,omitempty"`
+	Desire    specV1.Desire `json:"desire,omitempty"`
+	Command   *Command      `json:"command,omitempty"`
+	Time      time.Time     `json:"time"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Lock the lease of a named lock held by a replica until the expire time,
+// which keeps the replicas from running the same periodic task at the same time
+type Lock struct {
+	Name       string    `json:"name,omitempty" db:"name"`
+	Owner      string    `json:"owner,omitempty" db:"owner"`
+	ExpireTime time.Time `json:"expireTime,omitempty" db:"expire_time"`
+}
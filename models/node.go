@@ -1,9 +1,22 @@
 package models
 
 import (
+	"time"
+
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 )
 
+// the triggers of node deploys
+const (
+	DeployTriggerApp     = "app"
+	DeployTriggerConfig  = "config"
+	DeployTriggerSecret  = "secret"
+	DeployTriggerLabel   = "label"
+	DeployTriggerRollout = "rollout"
+	DeployTriggerOTA     = "ota"
+	DeployTriggerCert    = "certificate"
+)
+
 // NodeViewList node view list
 type NodeViewList struct {
 	Total       int               `json:"total"`
@@ -28,3 +41,55 @@ type ListOptions struct {
 type NodeNames struct {
 	Names []string `json:"names,"validate:"maxLength=20"`
 }
+
+// NodeBulk the nodes created in one request, all or none of them are created
+type NodeBulk struct {
+	Nodes []NodeBulkItem `json:"nodes"`
+}
+
+// NodeBulkItem the node created in bulk
+type NodeBulkItem struct {
+	Name        string            `json:"name" validate:"resourceName"`
+	Labels      map[string]string `json:"labels,omitempty" validate:"omitempty,validLabels"`
+	Description string            `json:"description,omitempty"`
+}
+
+// NodeBulkNames the names of the nodes deleted or initialized in bulk
+type NodeBulkNames struct {
+	Names []string `json:"names" validate:"required,maxLength=500,dive,resourceName"`
+}
+
+// NodeBulkResult the result of one node in the bulk operation
+type NodeBulkResult struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Cmd     string `json:"cmd,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NodeBulkView the results of the bulk operation
+type NodeBulkView struct {
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []NodeBulkResult `json:"items"`
+}
+
+// NodeDeployHistory the change of an application version in the desire of a node
+type NodeDeployHistory struct {
+	Id         int64     `json:"id,omitempty" db:"id"`
+	Namespace  string    `json:"namespace,omitempty" db:"namespace"`
+	NodeName   string    `json:"nodeName,omitempty" db:"node_name"`
+	AppName    string    `json:"appName,omitempty" db:"app_name"`
+	OldVersion string    `json:"oldVersion,omitempty" db:"old_version"`
+	NewVersion string    `json:"newVersion,omitempty" db:"new_version"`
+	Trigger    string    `json:"trigger,omitempty" db:"trigger_type"`
+	CreateTime time.Time `json:"createTime,omitempty" db:"create_time"`
+}
+
+// NodeDeployFilter filter of node deploy history, start and end are unix timestamps in seconds
+type NodeDeployFilter struct {
+	Filter
+	Start int64 `form:"start,omitempty"`
+	End   int64 `form:"end,omitempty"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// the types of node group
+const (
+	NodeGroupStatic  = "static"
+	NodeGroupDynamic = "dynamic"
+)
+
+// NodeGroup the group of nodes in the namespace.
+// The members of the dynamic group are the nodes matched by its selector,
+// the members of the static group are listed explicitly and labeled with the label of the group
+type NodeGroup struct {
+	Name        string    `json:"name,omitempty" db:"name" validate:"resourceName"`
+	Namespace   string    `json:"namespace,omitempty" db:"namespace"`
+	Description string    `json:"description,omitempty" db:"description"`
+	Selector    string    `json:"selector,omitempty" db:"selector"`
+	Nodes       []string  `json:"nodes,omitempty" db:"-" validate:"dive,resourceName"`
+	CreateTime  time.Time `json:"createTime,omitempty" db:"create_time"`
+	UpdateTime  time.Time `json:"updateTime,omitempty" db:"update_time"`
+}
+
+// NodeGroupView the group with the counts of its members
+type NodeGroupView struct {
+	NodeGroup `json:",inline"`
+	Type      string `json:"type"`
+	Total     int    `json:"total"`
+	Online    int    `json:"online"`
+	Offline   int    `json:"offline"`
+}
+
+// NodeGroupMembers the nodes added to or removed from the static group
+type NodeGroupMembers struct {
+	Add    []string `json:"add,omitempty" validate:"dive,resourceName"`
+	Remove []string `json:"remove,omitempty" validate:"dive,resourceName"`
+}
+
+// NodeGroupLabels the labels added to or removed from all members of the group
+type NodeGroupLabels struct {
+	Add    map[string]string `json:"add,omitempty" validate:"validLabels"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+// IsDynamic returns true if the members of the group are matched by its selector
+func (g *NodeGroup) IsDynamic() bool {
+	return g.Selector != ""
+}
+
+// MemberLabel returns the label key of the members of the static group
+func (g *NodeGroup) MemberLabel() string {
+	return common.LabelNodeGroupPrefix + g.Name
+}
+
+// MemberSelector returns the label selector matching the members of the group
+func (g *NodeGroup) MemberSelector() string {
+	if g.IsDynamic() {
+		return g.Selector
+	}
+	return g.MemberLabel() + "=true"
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// the states of ota task
+const (
+	OTARunning   = "running"
+	OTASucceeded = "succeeded"
+	OTAFailed    = "failed"
+	OTATimeout   = "timeout"
+	OTACanceled  = "canceled"
+)
+
+// TaskOTA the type of the tasks recording the ota upgrades of baetyl-core
+const TaskOTA = "ota"
+
+// OTARequest the request to upgrade baetyl-core of the nodes to the version (image tag) or the image,
+// the nodes are specified by names, a label selector or a batch, only one of them is allowed
+type OTARequest struct {
+	Version  string   `json:"version,omitempty"`
+	Image    string   `json:"image,omitempty"`
+	Nodes    []string `json:"nodes,omitempty"`
+	Selector string   `json:"selector,omitempty"`
+	Batch    string   `json:"batch,omitempty"`
+	Timeout  int      `json:"timeout,omitempty"`
+}
+
+// OTATask the upgrade of baetyl-core of a node, which is recorded as a task of the node.
+// The task is running until the node reports the new version of the core app or the timeout (seconds) expires
+type OTATask struct {
+	ID         string    `json:"id,omitempty"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Node       string    `json:"node,omitempty"`
+	App        string    `json:"app,omitempty"`
+	AppVersion string    `json:"appVersion,omitempty"`
+	OldVersion string    `json:"oldVersion,omitempty"`
+	NewVersion string    `json:"newVersion,omitempty"`
+	OldImage   string    `json:"oldImage,omitempty"`
+	NewImage   string    `json:"newImage,omitempty"`
+	Timeout    int       `json:"timeout,omitempty"`
+	State      string    `json:"state,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	StartTime  time.Time `json:"startTime,omitempty"`
+	CreateTime time.Time `json:"createTime,omitempty"`
+	UpdateTime time.Time `json:"updateTime,omitempty"`
+}
+
+// OTAFailure the node failed to start the upgrade
+type OTAFailure struct {
+	Node  string `json:"node,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// OTAView the tasks created by the ota request and the nodes failed to start
+type OTAView struct {
+	Tasks    []OTATask    `json:"tasks"`
+	Failures []OTAFailure `json:"failures,omitempty"`
+}
+
+// otaStep the step of the task, which keeps the progress of the upgrade
+type otaStep struct {
+	App        string    `json:"app"`
+	AppVersion string    `json:"appVersion,omitempty"`
+	OldImage   string    `json:"oldImage,omitempty"`
+	NewImage   string    `json:"newImage"`
+	Timeout    int       `json:"timeout"`
+	Message    string    `json:"message,omitempty"`
+	StartTime  time.Time `json:"startTime"`
+}
+
+// Deadline returns the time when the running task times out, the task restarts once retried
+func (o *OTATask) Deadline() time.Time {
+	return o.StartTime.Add(time.Duration(o.Timeout) * time.Second)
+}
+
+// Finished returns true if the task is no longer running
+func (o *OTATask) Finished() bool {
+	return o.State != OTARunning
+}
+
+// ToTask returns the task recording the upgrade
+func (o *OTATask) ToTask() (*Task, error) {
+	step, err := json.Marshal(&otaStep{
+		App:        o.App,
+		AppVersion: o.AppVersion,
+		OldImage:   o.OldImage,
+		NewImage:   o.NewImage,
+		Timeout:    o.Timeout,
+		Message:    o.Message,
+		StartTime:  o.StartTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Task{
+		TraceId:    o.ID,
+		Namespace:  o.Namespace,
+		Node:       o.Node,
+		Type:       TaskOTA,
+		State:      o.State,
+		Step:       string(step),
+		OldVersion: o.OldVersion,
+		NewVersion: o.NewVersion,
+		CreateTime: o.CreateTime,
+		UpdateTime: o.UpdateTime,
+	}, nil
+}
+
+// NewOTATaskFromTask returns the upgrade recorded by the task
+func NewOTATaskFromTask(task *Task) (*OTATask, error) {
+	var step otaStep
+	if err := json.Unmarshal([]byte(task.Step), &step); err != nil {
+		return nil, err
+	}
+	return &OTATask{
+		ID:         task.TraceId,
+		Namespace:  task.Namespace,
+		Node:       task.Node,
+		App:        step.App,
+		AppVersion: step.AppVersion,
+		OldVersion: task.OldVersion,
+		NewVersion: task.NewVersion,
+		OldImage:   step.OldImage,
+		NewImage:   step.NewImage,
+		Timeout:    step.Timeout,
+		State:      task.State,
+		Message:    step.Message,
+		StartTime:  step.StartTime,
+		CreateTime: task.CreateTime,
+		UpdateTime: task.UpdateTime,
+	}, nil
+}
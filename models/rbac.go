@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+)
+
+// the roles which can be bound to users in namespaces
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// the verbs of requests, GET and HEAD requests read resources, others write resources
+const (
+	VerbRead  = "read"
+	VerbWrite = "write"
+)
+
+// the resources authorized by roles
+const (
+	ResourceConfig      = "configs"
+	ResourceSecret      = "secrets"
+	ResourceRegistry    = "registries"
+	ResourceCertificate = "certificates"
+	ResourceNode        = "nodes"
+	ResourceApp         = "apps"
+	ResourceRoleBinding = "rolebindings"
+	ResourceAudit       = "audits"
+	ResourceCommand     = "commands"
+	ResourceOTA         = "ota"
+	ResourceBundle      = "bundles"
+	ResourceBatch       = "batches"
+	ResourceCallback    = "callbacks"
+	ResourceNamespace   = "namespaces"
+	ResourceFunction    = "functions"
+	ResourceObject      = "objects"
+	ResourceQuota       = "quotas"
+	ResourceProperty    = "properties"
+)
+
+var (
+	readOnly  = []string{VerbRead}
+	readWrite = []string{VerbRead, VerbWrite}
+)
+
+// RolePermissions the verbs on resources granted to each role,
+// viewers read the resources which are not sensitive,
+// operators deploy apps, run commands on nodes and upgrade them but cannot touch secrets, registries and certificates,
+// admins manage all resources and the role bindings of the namespace, read the audit logs,
+// export and apply the bundles of the namespace which contain all kinds of resources,
+// manage the callbacks which request external urls and delete the namespace,
+// the resources not listed are denied, e.g. a namespace is created by super users when rbac is enabled
+var RolePermissions = map[string]map[string][]string{
+	RoleViewer: {
+		ResourceConfig:    readOnly,
+		ResourceNode:      readOnly,
+		ResourceApp:       readOnly,
+		ResourceNamespace: readOnly,
+		ResourceFunction:  readOnly,
+		ResourceObject:    readOnly,
+		ResourceQuota:     readOnly,
+		ResourceProperty:  readOnly,
+	},
+	RoleOperator: {
+		ResourceConfig:    readWrite,
+		ResourceNode:      readWrite,
+		ResourceApp:       readWrite,
+		ResourceCommand:   readWrite,
+		ResourceOTA:       readWrite,
+		ResourceBatch:     readWrite,
+		ResourceNamespace: readOnly,
+		ResourceFunction:  readWrite,
+		ResourceObject:    readOnly,
+		ResourceQuota:     readOnly,
+		ResourceProperty:  readOnly,
+	},
+	RoleAdmin: {
+		ResourceConfig:      readWrite,
+		ResourceSecret:      readWrite,
+		ResourceRegistry:    readWrite,
+		ResourceCertificate: readWrite,
+		ResourceNode:        readWrite,
+		ResourceApp:         readWrite,
+		ResourceRoleBinding: readWrite,
+		ResourceAudit:       readOnly,
+		ResourceCommand:     readWrite,
+		ResourceOTA:         readWrite,
+		ResourceBundle:      readWrite,
+		ResourceBatch:       readWrite,
+		ResourceCallback:    readWrite,
+		ResourceNamespace:   readWrite,
+		ResourceFunction:    readWrite,
+		ResourceObject:      readOnly,
+		ResourceQuota:       readOnly,
+		ResourceProperty:    readOnly,
+	},
+}
+
+// RoleAllows returns true if the role is granted the verb on the resource
+func RoleAllows(role, resource, verb string) bool {
+	for _, v := range RolePermissions[role][resource] {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleBinding binds a role to a user in the namespace
+type RoleBinding struct {
+	Namespace   string    `json:"namespace,omitempty" db:"namespace"`
+	User        string    `json:"user,omitempty" db:"user_id"`
+	Role        string    `json:"role,omitempty" db:"role" binding:"required"`
+	Description string    `json:"description,omitempty" db:"description"`
+	CreateTime  time.Time `json:"createTime,omitempty" db:"create_time"`
+	UpdateTime  time.Time `json:"updateTime,omitempty" db:"update_time"`
+}
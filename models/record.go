@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
 
 type Record struct {
 	Name             string    `json:"name,omitempty" db:"name"`
@@ -14,3 +18,20 @@ type Record struct {
 	CreateTime       time.Time `json:"createTime,omitempty" db:"create_time"`
 	UpdateTime       time.Time `json:"updateTime,omitempty" db:"update_time"`
 }
+
+type RecordsRequest struct {
+	FingerprintValues []string `json:"fingerprintValues,omitempty" validate:"maxLength=500,dive,fingerprintValue"`
+}
+
+// NewRecord create an inactivated record of the batch
+func NewRecord(namespace, batchName, fingerprintValue string) Record {
+	return Record{
+		Name:             common.UUIDPrune(),
+		Namespace:        namespace,
+		BatchName:        batchName,
+		FingerprintValue: fingerprintValue,
+		Active:           common.Inactivated,
+		ActiveIP:         "0.0.0.0",
+		ActiveTime:       time.Unix(common.DefaultActiveTime, 0),
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// the status of rollout
+const (
+	RolloutRunning   = "running"
+	RolloutPaused    = "paused"
+	RolloutSucceeded = "succeeded"
+	RolloutAborted   = "aborted"
+)
+
+// the policies when the failed nodes exceed the threshold
+const (
+	RolloutPolicyPause = "pause"
+	RolloutPolicyAbort = "abort"
+)
+
+// Rollout the staged rollout of an application version to the matched nodes
+type Rollout struct {
+	Name       string          `json:"name,omitempty"`
+	Namespace  string          `json:"namespace,omitempty"`
+	AppName    string          `json:"appName,omitempty"`
+	AppVersion string          `json:"appVersion,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Strategy   RolloutStrategy `json:"strategy,omitempty"`
+	Progress   RolloutProgress `json:"progress,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	CreateTime time.Time       `json:"createTime,omitempty"`
+	UpdateTime time.Time       `json:"updateTime,omitempty"`
+}
+
+// RolloutStrategy how the nodes are updated in waves
+type RolloutStrategy struct {
+	// the percentage of the matched nodes updated in each wave
+	Percent int `json:"percent,omitempty"`
+	// the nodes updated in the first wave, the percentage is used if not set
+	Nodes []string `json:"nodes,omitempty"`
+	// the number of failed nodes tolerated
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	// pause or abort the rollout when the failed nodes exceed the threshold
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+	// the seconds to wait for the nodes of a wave to report the new version running
+	Timeout int64 `json:"timeout,omitempty"`
+}
+
+// RolloutProgress the progress of rollout
+type RolloutProgress struct {
+	Total     int       `json:"total"`
+	Deployed  int       `json:"deployed"`
+	Wave      int       `json:"wave"`
+	WaveNodes []string  `json:"waveNodes,omitempty"`
+	WaveTime  time.Time `json:"waveTime,omitempty"`
+	Nodes     []string  `json:"nodes,omitempty"`
+	Succeeded []string  `json:"succeeded,omitempty"`
+	Failed    []string  `json:"failed,omitempty"`
+	Skipped   []string  `json:"skipped,omitempty"`
+}
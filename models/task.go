@@ -14,3 +14,10 @@ type Task struct {
 	CreateTime time.Time `json:"createTime,omitempty" db:"create_time"`
 	UpdateTime time.Time `json:"updateTime,omitempty" db:"update_time"`
 }
+
+// TaskFilter the filter of the tasks of a node, the empty type or state matches all tasks
+type TaskFilter struct {
+	Filter
+	Type  string `form:"type,omitempty"`
+	State string `form:"state,omitempty"`
+}
@@ -0,0 +1,128 @@
+package apikey
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const bearerPrefix = "Bearer "
+
+// apiKeyAuth authenticates the requests by the api keys or the signed bearer tokens of users,
+// and scopes the requests to the namespaces granted to the users
+type apiKeyAuth struct {
+	cfg     CloudConfig
+	priv    *rsa.PrivateKey
+	storage plugin.DBStorage
+}
+
+func init() {
+	plugin.RegisterFactory("apikey", New)
+}
+
+// New New
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, err
+	}
+	key, err := ioutil.ReadFile(cfg.APIKey.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := util.BytesToPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := plugin.GetPlugin(cfg.APIKey.Storage)
+	if err != nil {
+		return nil, err
+	}
+	return &apiKeyAuth{
+		cfg:     cfg,
+		priv:    priv,
+		storage: ds.(plugin.DBStorage),
+	}, nil
+}
+
+// Authenticate reads the credential from the header 'Authorization: Bearer <api key or token>',
+// the namespace is read from the namespace header and must be granted to the user,
+// the first granted namespace is used if the header is absent
+func (a *apiKeyAuth) Authenticate(c *common.Context) error {
+	auth := c.GetHeader("Authorization")
+	if !strings.HasPrefix(auth, bearerPrefix) {
+		return common.Error(common.ErrInvalidToken)
+	}
+	credential := strings.TrimSpace(strings.TrimPrefix(auth, bearerPrefix))
+	if credential == "" {
+		return common.Error(common.ErrInvalidToken)
+	}
+
+	var user string
+	var namespaces []string
+	if strings.HasPrefix(credential, models.APIKeyPrefix) {
+		key, err := a.storage.GetAPIKeyByHash(util.HashAPIKey(credential))
+		if err != nil {
+			return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+		}
+		if key == nil || key.Expired(time.Now()) {
+			return common.Error(common.ErrInvalidToken)
+		}
+		user, namespaces = key.User, key.Namespaces
+	} else {
+		meta, sign, err := util.DecodeToken(credential)
+		if err != nil || !a.VerifyToken(meta, sign) {
+			return common.Error(common.ErrInvalidToken)
+		}
+		var claims models.TokenClaims
+		if err = json.Unmarshal(meta, &claims); err != nil {
+			return common.Error(common.ErrInvalidToken)
+		}
+		if !time.Now().Before(claims.ExpireTime) {
+			return common.Error(common.ErrInvalidToken)
+		}
+		user, namespaces = claims.User, claims.Namespaces
+	}
+	if user == "" || len(namespaces) == 0 {
+		return common.Error(common.ErrInvalidToken)
+	}
+
+	ns := c.GetHeader(a.cfg.APIKey.NamespaceHeader)
+	if ns == "" {
+		ns = namespaces[0]
+	} else if !contains(namespaces, ns) {
+		return common.Error(common.ErrRequestAccessDenied)
+	}
+	c.SetUser(common.User{ID: user})
+	c.SetNamespace(ns)
+	return nil
+}
+
+func (a *apiKeyAuth) SignToken(meta []byte) ([]byte, error) {
+	return util.SignPKCS1v15(meta, a.priv)
+}
+
+func (a *apiKeyAuth) VerifyToken(meta, sign []byte) bool {
+	return util.VerifyPKCS1v15(meta, sign, &a.priv.PublicKey)
+}
+
+// Close Close
+func (a *apiKeyAuth) Close() error {
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
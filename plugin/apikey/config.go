@@ -0,0 +1,9 @@
+package apikey
+
+type CloudConfig struct {
+	APIKey struct {
+		KeyFile         string `yaml:"keyFile" json:"keyFile" default:"etc/baetyl/token.key"`
+		NamespaceHeader string `yaml:"namespaceHeader" json:"namespaceHeader" default:"baetyl-namespace"`
+		Storage         string `yaml:"storage" json:"storage" default:"database"`
+	} `yaml:"apikey" json:"apikey"`
+}
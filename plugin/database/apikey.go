@@ -0,0 +1,124 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *dbStorage) GetAPIKey(name string) (*models.APIKey, error) {
+	return d.GetAPIKeyTx(nil, name)
+}
+
+func (d *dbStorage) GetAPIKeyByHash(hash string) (*models.APIKey, error) {
+	return d.GetAPIKeyByHashTx(nil, hash)
+}
+
+func (d *dbStorage) ListAPIKey(user string, filter *models.Filter) ([]models.APIKey, error) {
+	return d.ListAPIKeyTx(nil, user, filter)
+}
+
+func (d *dbStorage) CountAPIKey(user, name string) (int, error) {
+	return d.CountAPIKeyTx(nil, user, name)
+}
+
+func (d *dbStorage) CreateAPIKey(key *models.APIKey) (sql.Result, error) {
+	return d.CreateAPIKeyTx(nil, key)
+}
+
+func (d *dbStorage) DeleteAPIKey(name string) (sql.Result, error) {
+	return d.DeleteAPIKeyTx(nil, name)
+}
+
+func (d *dbStorage) GetAPIKeyTx(tx *sqlx.Tx, name string) (*models.APIKey, error) {
+	selectSQL := `
+SELECT name, user_id, namespaces, key_prefix, key_hash, 
+description, expire_time, create_time, update_time 
+FROM baetyl_api_key 
+WHERE name=? LIMIT 1
+`
+	var keys []entities.APIKey
+	if err := d.query(tx, selectSQL, &keys, name); err != nil {
+		return nil, err
+	}
+	if len(keys) > 0 {
+		return entities.ToAPIKeyModel(&keys[0]), nil
+	}
+	return nil, nil
+}
+
+func (d *dbStorage) GetAPIKeyByHashTx(tx *sqlx.Tx, hash string) (*models.APIKey, error) {
+	selectSQL := `
+SELECT name, user_id, namespaces, key_prefix, key_hash, 
+description, expire_time, create_time, update_time 
+FROM baetyl_api_key 
+WHERE key_hash=? LIMIT 1
+`
+	var keys []entities.APIKey
+	if err := d.query(tx, selectSQL, &keys, hash); err != nil {
+		return nil, err
+	}
+	if len(keys) > 0 {
+		return entities.ToAPIKeyModel(&keys[0]), nil
+	}
+	return nil, nil
+}
+
+func (d *dbStorage) ListAPIKeyTx(tx *sqlx.Tx, user string, filter *models.Filter) ([]models.APIKey, error) {
+	selectSQL := `
+SELECT name, user_id, namespaces, key_prefix, key_hash, 
+description, expire_time, create_time, update_time 
+FROM baetyl_api_key 
+WHERE (?='' OR user_id=?) AND name LIKE ? ORDER BY create_time DESC 
+`
+	keys := []entities.APIKey{}
+	args := []interface{}{user, user, filter.GetFuzzyName()}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	if err := d.query(tx, selectSQL, &keys, args...); err != nil {
+		return nil, err
+	}
+	var res []models.APIKey
+	for _, k := range keys {
+		res = append(res, *entities.ToAPIKeyModel(&k))
+	}
+	return res, nil
+}
+
+func (d *dbStorage) CountAPIKeyTx(tx *sqlx.Tx, user, name string) (int, error) {
+	selectSQL := `
+SELECT count(name) AS count
+FROM baetyl_api_key WHERE (?='' OR user_id=?) AND name LIKE ?
+`
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(tx, selectSQL, &res, user, user, name); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
+func (d *dbStorage) CreateAPIKeyTx(tx *sqlx.Tx, key *models.APIKey) (sql.Result, error) {
+	insertSQL := `
+INSERT INTO baetyl_api_key (
+name, user_id, namespaces, key_prefix, 
+key_hash, description, expire_time) 
+VALUES (?,?,?,?,?,?,?)
+`
+	keyDB := entities.FromAPIKeyModel(key)
+	return d.exec(tx, insertSQL, keyDB.Name, keyDB.UserID, keyDB.Namespaces,
+		keyDB.KeyPrefix, keyDB.KeyHash, keyDB.Description, keyDB.ExpireTime)
+}
+
+func (d *dbStorage) DeleteAPIKeyTx(tx *sqlx.Tx, name string) (sql.Result, error) {
+	deleteSQL := `
+DELETE FROM baetyl_api_key WHERE name=?
+`
+	return d.exec(tx, deleteSQL, name)
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	apiKeyTables = []string{
+		`
+CREATE TABLE baetyl_api_key
+(
+    name        varchar(128)  NOT NULL DEFAULT '' PRIMARY KEY,
+    user_id     varchar(128)  NOT NULL DEFAULT '',
+    namespaces  varchar(2048) NOT NULL DEFAULT '[]',
+    key_prefix  varchar(16)   NOT NULL DEFAULT '',
+    key_hash    varchar(64)   NOT NULL DEFAULT '' UNIQUE,
+    description varchar(1024) NOT NULL DEFAULT '',
+    expire_time timestamp     NULL DEFAULT NULL,
+    create_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *dbStorage) MockCreateAPIKeyTable() {
+	for _, sql := range apiKeyTables {
+		_, err := d.exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	expire := time.Unix(2000, 0).UTC()
+	key1 := &models.APIKey{
+		Name:        "key01",
+		User:        "user01",
+		Namespaces:  []string{"default", "test"},
+		KeyPrefix:   "bk_1234",
+		KeyHash:     "hash01",
+		Description: "desc",
+		ExpireTime:  &expire,
+	}
+	key2 := &models.APIKey{
+		Name:       "key02",
+		User:       "user02",
+		Namespaces: []string{"default"},
+		KeyPrefix:  "bk_5678",
+		KeyHash:    "hash02",
+	}
+
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAPIKeyTable()
+
+	res, err := db.CreateAPIKey(key1)
+	assert.NoError(t, err)
+	num, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	_, err = db.CreateAPIKey(key2)
+	assert.NoError(t, err)
+
+	// the key hash is unique
+	dup := *key2
+	dup.Name = "key03"
+	_, err = db.CreateAPIKey(&dup)
+	assert.Error(t, err)
+
+	resKey, err := db.GetAPIKey(key1.Name)
+	assert.NoError(t, err)
+	checkAPIKey(t, key1, resKey)
+	assert.Equal(t, expire.Unix(), resKey.ExpireTime.Unix())
+
+	resKey, err = db.GetAPIKeyByHash(key2.KeyHash)
+	assert.NoError(t, err)
+	checkAPIKey(t, key2, resKey)
+	assert.Nil(t, resKey.ExpireTime)
+
+	resKey, err = db.GetAPIKeyByHash("unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, resKey)
+
+	filter := &models.Filter{PageNo: 1, PageSize: 10}
+	keys, err := db.ListAPIKey("", filter)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	count, err := db.CountAPIKey("", filter.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	keys, err = db.ListAPIKey("user01", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	checkAPIKey(t, key1, &keys[0])
+	count, err = db.CountAPIKey("user01", "%")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	res, err = db.DeleteAPIKey(key1.Name)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	resKey, err = db.GetAPIKey(key1.Name)
+	assert.NoError(t, err)
+	assert.Nil(t, resKey)
+}
+
+func checkAPIKey(t *testing.T, expect, actual *models.APIKey) {
+	assert.Equal(t, expect.Name, actual.Name)
+	assert.Equal(t, expect.User, actual.User)
+	assert.Equal(t, expect.Namespaces, actual.Namespaces)
+	assert.Equal(t, expect.KeyPrefix, actual.KeyPrefix)
+	assert.Equal(t, expect.KeyHash, actual.KeyHash)
+	assert.Equal(t, expect.Description, actual.Description)
+}
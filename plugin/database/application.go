@@ -48,8 +48,8 @@ FROM baetyl_application_history WHERE namespace = ? AND name LIKE ? AND is_delet
 	var apps []entities.Application
 	args := []interface{}{namespace, filter.GetFuzzyName()}
 	if filter.GetLimitNumber() > 0 {
-		selectSQL = selectSQL + "LIMIT ?,?"
-		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
 	}
 	if err := d.query(nil, selectSQL, &apps, args...); err != nil {
 		return nil, err
@@ -113,3 +113,48 @@ FROM baetyl_application_history WHERE namespace=? AND name=?
 	}
 	return res[0].Count, nil
 }
+
+func (d *dbStorage) ListApplicationHistory(name, namespace string, filter *models.Filter) ([]models.ApplicationVersion, error) {
+	selectSQL := `
+SELECT  
+id, namespace, name, version, is_deleted, create_time, update_time, content
+FROM baetyl_application_history WHERE namespace = ? AND name = ? AND is_deleted = 0 
+ORDER BY id DESC 
+`
+	var apps []entities.Application
+	args := []interface{}{namespace, name}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	if err := d.query(nil, selectSQL, &apps, args...); err != nil {
+		return nil, err
+	}
+	var result []models.ApplicationVersion
+	for i := range apps {
+		application, err := entities.ToApplicationModel(&apps[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, models.ApplicationVersion{
+			Version:     apps[i].Version,
+			CreateTime:  apps[i].CreateTime,
+			Application: application,
+		})
+	}
+	return result, nil
+}
+
+func (d *dbStorage) CountApplicationHistory(name, namespace string) (int, error) {
+	selectSQL := `
+SELECT count(name) AS count
+FROM baetyl_application_history WHERE namespace=? AND name=? AND is_deleted = 0
+`
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(nil, selectSQL, &res, namespace, name); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
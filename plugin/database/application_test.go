@@ -135,6 +135,44 @@ func TestDbStorage_GetApplication(t *testing.T) {
 
 }
 
+func TestDbStorage_ListApplicationHistory(t *testing.T) {
+	db := mockDb(t)
+	app := &specV1.Application{
+		Name:      "app01",
+		Namespace: "default",
+		Selector:  "a=a",
+	}
+	for _, v := range []string{"1", "2", "3"} {
+		app.Version = v
+		app.Description = "desc" + v
+		_, err := db.CreateApplication(app)
+		assert.NoError(t, err)
+	}
+	_, err := db.DeleteApplication(app.Name, app.Namespace, "1")
+	assert.NoError(t, err)
+
+	num, err := db.CountApplicationHistory(app.Name, app.Namespace)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, num)
+
+	versions, err := db.ListApplicationHistory(app.Name, app.Namespace, &models.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "3", versions[0].Version)
+	assert.Equal(t, "desc3", versions[0].Application.Description)
+	assert.False(t, versions[0].CreateTime.IsZero())
+	assert.Equal(t, "2", versions[1].Version)
+
+	versions, err = db.ListApplicationHistory(app.Name, app.Namespace, &models.Filter{PageNo: 2, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Len(t, versions, 1)
+	assert.Equal(t, "2", versions[0].Version)
+
+	versions, err = db.ListApplicationHistory("app02", app.Namespace, &models.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, versions, 0)
+}
+
 func checkApplication(t *testing.T, expect, actual *specV1.Application) {
 	assert.Equal(t, expect.Name, actual.Name)
 	assert.Equal(t, expect.Namespace, actual.Namespace)
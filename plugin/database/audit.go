@@ -0,0 +1,84 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func (d *dbStorage) CreateAuditLog(audit *models.AuditLog) (sql.Result, error) {
+	return d.CreateAuditLogTx(nil, audit)
+}
+
+func (d *dbStorage) ListAuditLog(filter *models.AuditFilter) ([]models.AuditLog, error) {
+	return d.ListAuditLogTx(nil, filter)
+}
+
+func (d *dbStorage) CountAuditLog(filter *models.AuditFilter) (int, error) {
+	return d.CountAuditLogTx(nil, filter)
+}
+
+func (d *dbStorage) CreateAuditLogTx(tx *sqlx.Tx, audit *models.AuditLog) (sql.Result, error) {
+	insertSQL := `
+INSERT INTO baetyl_audit_log (
+namespace, user_id, request_id, method, path, 
+resource, name, operation, status, code, diff) 
+VALUES (?,?,?,?,?,?,?,?,?,?,?)
+`
+	return d.exec(tx, insertSQL, audit.Namespace, audit.User, audit.RequestID, audit.Method, audit.Path,
+		audit.Resource, audit.Name, audit.Operation, audit.Status, audit.Code, audit.Diff)
+}
+
+func (d *dbStorage) ListAuditLogTx(tx *sqlx.Tx, filter *models.AuditFilter) ([]models.AuditLog, error) {
+	selectSQL := `
+SELECT id, namespace, user_id, request_id, method, path, 
+resource, name, operation, status, code, diff, create_time 
+FROM baetyl_audit_log 
+` + auditCondition + `ORDER BY id DESC 
+`
+	args := auditArgs(filter)
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	audits := []models.AuditLog{}
+	if err := d.query(tx, selectSQL, &audits, args...); err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+func (d *dbStorage) CountAuditLogTx(tx *sqlx.Tx, filter *models.AuditFilter) (int, error) {
+	selectSQL := `
+SELECT count(id) AS count
+FROM baetyl_audit_log 
+` + auditCondition
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(tx, selectSQL, &res, auditArgs(filter)...); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
+// the empty conditions of the filter match all audit logs
+const auditCondition = `WHERE (?='' OR namespace=?) AND (?='' OR user_id=?) AND (?='' OR resource=?) 
+AND (?='' OR operation=?) AND name LIKE ? AND create_time>=? AND create_time<=? 
+`
+
+func auditArgs(filter *models.AuditFilter) []interface{} {
+	start, end := time.Unix(0, 0).UTC(), time.Unix(1<<33, 0).UTC()
+	if filter.Start > 0 {
+		start = time.Unix(filter.Start, 0).UTC()
+	}
+	if filter.End > 0 {
+		end = time.Unix(filter.End, 0).UTC()
+	}
+	return []interface{}{filter.Namespace, filter.Namespace, filter.User, filter.User,
+		filter.Resource, filter.Resource, filter.Operation, filter.Operation,
+		filter.GetFuzzyName(), start, end}
+}
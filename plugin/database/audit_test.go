@@ -0,0 +1,139 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	auditTables = []string{
+		`
+CREATE TABLE baetyl_audit_log
+(
+    id          integer       PRIMARY KEY AUTOINCREMENT,
+    namespace   varchar(64)   NOT NULL DEFAULT '',
+    user_id     varchar(128)  NOT NULL DEFAULT '',
+    request_id  varchar(64)   NOT NULL DEFAULT '',
+    method      varchar(16)   NOT NULL DEFAULT '',
+    path        varchar(512)  NOT NULL DEFAULT '',
+    resource    varchar(64)   NOT NULL DEFAULT '',
+    name        varchar(128)  NOT NULL DEFAULT '',
+    operation   varchar(32)   NOT NULL DEFAULT '',
+    status      int           NOT NULL DEFAULT 0,
+    code        varchar(64)   NOT NULL DEFAULT '',
+    diff        text,
+    create_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *dbStorage) MockCreateAuditTable() {
+	for _, sql := range auditTables {
+		_, err := d.exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	audits := []*models.AuditLog{
+		{
+			Namespace: "default",
+			User:      "user01",
+			RequestID: "req01",
+			Method:    "PUT",
+			Path:      "/v1/configs/cfg01",
+			Resource:  "configs",
+			Name:      "cfg01",
+			Operation: models.AuditUpdate,
+			Status:    200,
+			Diff:      `{"data.a":["1","2"]}`,
+		},
+		{
+			Namespace: "default",
+			User:      "user02",
+			Method:    "DELETE",
+			Path:      "/v1/secrets/sec01",
+			Resource:  "secrets",
+			Name:      "sec01",
+			Operation: models.AuditDelete,
+			Status:    403,
+			Code:      "ErrRequestForbidden",
+		},
+		{
+			User:      "mis",
+			Method:    "POST",
+			Path:      "/v1/apikeys",
+			Resource:  "apikeys",
+			Name:      "key01",
+			Operation: models.AuditCreate,
+			Status:    200,
+		},
+	}
+
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAuditTable()
+
+	for _, a := range audits {
+		res, err := db.CreateAuditLog(a)
+		assert.NoError(t, err)
+		num, err := res.RowsAffected()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), num)
+	}
+
+	filter := &models.AuditFilter{Filter: models.Filter{PageNo: 1, PageSize: 10}}
+	list, err := db.ListAuditLog(filter)
+	assert.NoError(t, err)
+	assert.Len(t, list, 3)
+	assert.Equal(t, "key01", list[0].Name)
+	count, err := db.CountAuditLog(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	filter = &models.AuditFilter{Namespace: "default"}
+	list, err = db.ListAuditLog(filter)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	filter = &models.AuditFilter{Namespace: "default", User: "user01", Resource: "configs", Operation: models.AuditUpdate}
+	list, err = db.ListAuditLog(filter)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	a := list[0]
+	assert.Equal(t, audits[0].RequestID, a.RequestID)
+	assert.Equal(t, audits[0].Path, a.Path)
+	assert.Equal(t, audits[0].Method, a.Method)
+	assert.Equal(t, audits[0].Status, a.Status)
+	assert.Equal(t, audits[0].Diff, a.Diff)
+	count, err = db.CountAuditLog(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	filter = &models.AuditFilter{Filter: models.Filter{Name: "sec"}}
+	list, err = db.ListAuditLog(filter)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "ErrRequestForbidden", list[0].Code)
+
+	filter = &models.AuditFilter{Start: time.Now().Add(time.Hour).Unix()}
+	count, err = db.CountAuditLog(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	filter = &models.AuditFilter{End: time.Now().Add(time.Hour).Unix()}
+	count, err = db.CountAuditLog(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
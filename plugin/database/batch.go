@@ -44,7 +44,7 @@ SELECT
 name, namespace, description, quota_num, enable_whitelist,
 security_type, security_key, callback_name,
 labels, fingerprint, create_time, update_time 
-FROM baetyl_batch WHERE namespace=? AND name=? LIMIT 0,1
+FROM baetyl_batch WHERE namespace=? AND name=? LIMIT 1
 `
 	batchs := []entities.Batch{}
 	if err := d.query(tx, selectSQL, &batchs, ns, name); err != nil {
@@ -67,8 +67,8 @@ FROM baetyl_batch WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC
 	batchs := []entities.Batch{}
 	args := []interface{}{ns, filter.GetFuzzyName()}
 	if filter.GetLimitNumber() > 0 {
-		selectSQL = selectSQL + "LIMIT ?,?"
-		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
 	}
 	if err := d.query(tx, selectSQL, &batchs, args...); err != nil {
 		return nil, err
@@ -13,6 +13,14 @@ func (d *dbStorage) GetCallback(name, namespace string) (*models.Callback, error
 	return d.GetCallbackTx(nil, name, namespace)
 }
 
+func (d *dbStorage) ListCallback(namespace string, filter *models.Filter) ([]models.Callback, error) {
+	return d.ListCallbackTx(nil, namespace, filter)
+}
+
+func (d *dbStorage) CountCallback(namespace, name string) (int, error) {
+	return d.CountCallbackTx(nil, namespace, name)
+}
+
 func (d *dbStorage) CreateCallback(callback *models.Callback) (sql.Result, error) {
 	return d.CreateCallbackTx(nil, callback)
 }
@@ -31,7 +39,7 @@ SELECT name, namespace, method, params,
 header, body, url, description, create_time, 
 update_time 
 FROM baetyl_callback 
-WHERE namespace=? and name=? LIMIT 0,1
+WHERE namespace=? and name=? LIMIT 1
 `
 	var callback []entities.Callback
 	if err := d.query(tx, selectSQL, &callback, namespace, name); err != nil {
@@ -43,6 +51,44 @@ WHERE namespace=? and name=? LIMIT 0,1
 	return nil, nil
 }
 
+func (d *dbStorage) ListCallbackTx(tx *sqlx.Tx, namespace string, filter *models.Filter) ([]models.Callback, error) {
+	selectSQL := `
+SELECT name, namespace, method, params, 
+header, body, url, description, create_time, 
+update_time 
+FROM baetyl_callback 
+WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC 
+`
+	callbacks := []entities.Callback{}
+	args := []interface{}{namespace, filter.GetFuzzyName()}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	if err := d.query(tx, selectSQL, &callbacks, args...); err != nil {
+		return nil, err
+	}
+	var res []models.Callback
+	for _, c := range callbacks {
+		res = append(res, *entities.ToCallbackModel(&c))
+	}
+	return res, nil
+}
+
+func (d *dbStorage) CountCallbackTx(tx *sqlx.Tx, namespace, name string) (int, error) {
+	selectSQL := `
+SELECT count(name) AS count
+FROM baetyl_callback WHERE namespace=? AND name LIKE ?
+`
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(tx, selectSQL, &res, namespace, name); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
 func (d *dbStorage) CreateCallbackTx(tx *sqlx.Tx, callback *models.Callback) (sql.Result, error) {
 	insertSQL := `
 INSERT INTO baetyl_callback (
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func (d *dbStorage) CreateCallbackLog(callbackLog *models.CallbackLog) (sql.Result, error) {
+	return d.CreateCallbackLogTx(nil, callbackLog)
+}
+
+func (d *dbStorage) ListCallbackLog(callbackName, ns string, filter *models.Filter) ([]models.CallbackLog, error) {
+	return d.ListCallbackLogTx(nil, callbackName, ns, filter)
+}
+
+func (d *dbStorage) CountCallbackLog(callbackName, ns string) (int, error) {
+	return d.CountCallbackLogTx(nil, callbackName, ns)
+}
+
+func (d *dbStorage) DeleteCallbackLog(callbackName, ns string) (sql.Result, error) {
+	return d.DeleteCallbackLogTx(nil, callbackName, ns)
+}
+
+func (d *dbStorage) PruneCallbackLog(callbackName, ns string, limit int) (sql.Result, error) {
+	return d.PruneCallbackLogTx(nil, callbackName, ns, limit)
+}
+
+func (d *dbStorage) CreateCallbackLogTx(tx *sqlx.Tx, callbackLog *models.CallbackLog) (sql.Result, error) {
+	insertSQL := `
+INSERT INTO baetyl_callback_log (
+namespace, callback_name, event, method, 
+url, request, status_code, response, 
+error, attempts) 
+VALUES (?,?,?,?,?,?,?,?,?,?)
+`
+	return d.exec(tx, insertSQL, callbackLog.Namespace, callbackLog.CallbackName,
+		callbackLog.Event, callbackLog.Method, callbackLog.Url, callbackLog.Request,
+		callbackLog.StatusCode, callbackLog.Response, callbackLog.Error, callbackLog.Attempts)
+}
+
+func (d *dbStorage) ListCallbackLogTx(tx *sqlx.Tx, callbackName, ns string, filter *models.Filter) ([]models.CallbackLog, error) {
+	selectSQL := `
+SELECT id, namespace, callback_name, event, 
+method, url, request, status_code, response, 
+error, attempts, create_time 
+FROM baetyl_callback_log 
+WHERE namespace=? AND callback_name=? ORDER BY id DESC 
+`
+	args := []interface{}{ns, callbackName}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	var logs []models.CallbackLog
+	if err := d.query(tx, selectSQL, &logs, args...); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (d *dbStorage) CountCallbackLogTx(tx *sqlx.Tx, callbackName, ns string) (int, error) {
+	selectSQL := `
+SELECT count(id) AS count
+FROM baetyl_callback_log WHERE namespace=? AND callback_name=?
+`
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(tx, selectSQL, &res, ns, callbackName); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
+func (d *dbStorage) DeleteCallbackLogTx(tx *sqlx.Tx, callbackName, ns string) (sql.Result, error) {
+	deleteSQL := `
+DELETE FROM baetyl_callback_log WHERE namespace=? AND callback_name=?
+`
+	return d.exec(tx, deleteSQL, ns, callbackName)
+}
+
+// PruneCallbackLogTx deletes the logs of the callback except the latest ones of the limit
+func (d *dbStorage) PruneCallbackLogTx(tx *sqlx.Tx, callbackName, ns string, limit int) (sql.Result, error) {
+	deleteSQL := `
+DELETE FROM baetyl_callback_log WHERE namespace=? AND callback_name=? AND id <= (
+SELECT id FROM (
+SELECT id FROM baetyl_callback_log WHERE namespace=? AND callback_name=? ORDER BY id DESC LIMIT 1 OFFSET ?
+) t)
+`
+	return d.exec(tx, deleteSQL, ns, callbackName, ns, callbackName, limit)
+}
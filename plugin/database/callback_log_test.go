@@ -0,0 +1,112 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	callbackLogTables = []string{
+		`
+CREATE TABLE baetyl_callback_log
+(
+    id            integer       PRIMARY KEY AUTOINCREMENT,
+    namespace     varchar(64)   NOT NULL DEFAULT '',
+    callback_name varchar(128)  NOT NULL DEFAULT '',
+    event         varchar(64)   NOT NULL DEFAULT '',
+    method        varchar(36)   NOT NULL DEFAULT 'GET',
+    url           varchar(1024) NOT NULL DEFAULT '',
+    request       text,
+    status_code   int           NOT NULL DEFAULT 0,
+    response      text,
+    error         varchar(1024) NOT NULL DEFAULT '',
+    attempts      int           NOT NULL DEFAULT 0,
+    create_time   timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *dbStorage) MockCreateCallbackLogTable() {
+	for _, sql := range callbackLogTables {
+		_, err := d.exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestCallbackLog(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateCallbackLogTable()
+
+	for i := 0; i < 3; i++ {
+		res, err := db.CreateCallbackLog(&models.CallbackLog{
+			Namespace:    "default",
+			CallbackName: "cb",
+			Event:        "activate",
+			Method:       "POST",
+			Url:          "http://localhost/cb",
+			Request:      "{}",
+			StatusCode:   200 + i,
+			Attempts:     i + 1,
+		})
+		assert.NoError(t, err)
+		num, err := res.RowsAffected()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), num)
+	}
+	_, err = db.CreateCallbackLog(&models.CallbackLog{Namespace: "default", CallbackName: "other"})
+	assert.NoError(t, err)
+
+	count, err := db.CountCallbackLog("cb", "default")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	logs, err := db.ListCallbackLog("cb", "default", &models.Filter{PageNo: 1, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, 202, logs[0].StatusCode)
+	assert.Equal(t, 3, logs[0].Attempts)
+	assert.Equal(t, "activate", logs[0].Event)
+
+	logs, err = db.ListCallbackLog("cb", "default", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 3)
+
+	// only the latest logs are kept
+	res, err := db.PruneCallbackLog("cb", "default", 3)
+	assert.NoError(t, err)
+	num, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), num)
+	res, err = db.PruneCallbackLog("cb", "default", 2)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	logs, err = db.ListCallbackLog("cb", "default", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+	assert.Equal(t, 202, logs[0].StatusCode)
+	assert.Equal(t, 201, logs[1].StatusCode)
+
+	res, err = db.DeleteCallbackLog("cb", "default")
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), num)
+
+	count, err = db.CountCallbackLog("other", "default")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
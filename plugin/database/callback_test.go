@@ -73,6 +73,19 @@ func TestCallback(t *testing.T) {
 	assert.NoError(t, err)
 	checkCallback(t, call, resCall)
 
+	filter := &models.Filter{
+		PageNo:   1,
+		PageSize: 10,
+	}
+	resCallList, err := db.ListCallback(call.Namespace, filter)
+	assert.NoError(t, err)
+	assert.Len(t, resCallList, 1)
+	checkCallback(t, call, &resCallList[0])
+
+	count, err := db.CountCallback(call.Namespace, filter.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
 	call.Params = map[string]string{"b": "b"}
 	res, err = db.UpdateCallback(call)
 	assert.NoError(t, err)
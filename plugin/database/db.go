@@ -12,8 +12,9 @@ import (
 
 // dbStorage
 type dbStorage struct {
-	db  *sqlx.DB
-	cfg CloudConfig
+	db      *sqlx.DB
+	cfg     CloudConfig
+	dialect dialect
 }
 
 func init() {
@@ -26,6 +27,24 @@ func New() (plugin.Plugin, error) {
 	if err := common.LoadConfig(&cfg); err != nil {
 		return nil, err
 	}
+	d, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Database.Migration.Enabled {
+		if _, err = newMigrator(d).Up(0); err != nil {
+			d.Close()
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func open(cfg CloudConfig) (*dbStorage, error) {
+	dialect, err := newDialect(cfg.Database.Type)
+	if err != nil {
+		return nil, err
+	}
 	db, err := sqlx.Open(cfg.Database.Type, cfg.Database.URL)
 	if err != nil {
 		return nil, err
@@ -35,11 +54,13 @@ func New() (plugin.Plugin, error) {
 	db.SetConnMaxLifetime(time.Duration(cfg.Database.ConnMaxLifetime) * time.Second)
 	err = db.Ping()
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 	return &dbStorage{
-		db:  db,
-		cfg: cfg,
+		db:      db,
+		cfg:     cfg,
+		dialect: dialect,
 	}, nil
 }
 
@@ -68,6 +89,7 @@ func (d *dbStorage) Transact(handler func(*sqlx.Tx) error) (err error) {
 }
 
 func (d *dbStorage) exec(tx *sqlx.Tx, sql string, args ...interface{}) (sql.Result, error) {
+	sql = d.dialect.rebind(sql)
 	if tx == nil {
 		return d.db.Exec(sql, args...)
 	}
@@ -75,6 +97,7 @@ func (d *dbStorage) exec(tx *sqlx.Tx, sql string, args ...interface{}) (sql.Resu
 }
 
 func (d *dbStorage) query(tx *sqlx.Tx, sql string, data interface{}, args ...interface{}) error {
+	sql = d.dialect.rebind(sql)
 	if tx == nil {
 		return d.db.Select(data, sql, args...)
 	}
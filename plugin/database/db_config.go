@@ -1,5 +1,7 @@
 package database
 
+import "time"
+
 // CloudConfig baetyl-cloud config
 type CloudConfig struct {
 	Database struct {
@@ -8,5 +10,10 @@ type CloudConfig struct {
 		MaxConns        int    `yaml:"maxConns" json:"maxConns" default:20`
 		MaxIdleConns    int    `yaml:"maxIdleConns" json:"maxIdleConns" default:5`
 		ConnMaxLifetime int    `yaml:"connMaxLifetime" json:"connMaxLifetime" default:150`
+		Migration       struct {
+			// Enabled applies the pending migrations of the schema when the plugin is created
+			Enabled     bool          `yaml:"enabled" json:"enabled"`
+			LockTimeout time.Duration `yaml:"lockTimeout" json:"lockTimeout" default:"10m"`
+		} `yaml:"migration" json:"migration"`
 	} `yaml:"database" json:"database" default:"{}"`
 }
@@ -17,5 +17,5 @@ func MockNewDB() (*dbStorage, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &dbStorage{db: db, cfg: cfg}, nil
+	return &dbStorage{db: db, cfg: cfg, dialect: &sqliteDialect{}}, nil
 }
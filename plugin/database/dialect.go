@@ -0,0 +1,85 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// dialect the differences of the sql among the supported databases,
+// the statements of the entities are written with ? placeholders and the portable LIMIT ? OFFSET ? clause,
+// the auto increment ids and the update times of the rows are maintained by the schema of each database
+// which is shipped in scripts/common
+type dialect interface {
+	// name returns the name of the dialect which is also the directory of the migrations
+	name() string
+	// rebind replaces the ? placeholders with the bind vars of the database
+	rebind(query string) string
+	// upsert returns the statement inserting the row of the columns,
+	// or applying the updates to the existing row if the row conflicts with the keys
+	upsert(table string, keys, columns []string, updates string) string
+}
+
+// newDialect returns the dialect of the database type which is also the name of the driver
+func newDialect(typ string) (dialect, error) {
+	switch typ {
+	case "mysql":
+		return &mysqlDialect{}, nil
+	case "sqlite3":
+		return &sqliteDialect{}, nil
+	case "postgres", "pgx":
+		return &postgresDialect{}, nil
+	default:
+		return nil, common.Error(common.ErrDatabase, common.Field("error", fmt.Sprintf("the database type (%s) is not supported", typ)))
+	}
+}
+
+type mysqlDialect struct{}
+
+func (d *mysqlDialect) name() string {
+	return "mysql"
+}
+
+func (d *mysqlDialect) rebind(query string) string {
+	return query
+}
+
+func (d *mysqlDialect) upsert(table string, keys, columns []string, updates string) string {
+	return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", insertSQL(table, columns), updates)
+}
+
+type sqliteDialect struct{}
+
+func (d *sqliteDialect) name() string {
+	return "sqlite3"
+}
+
+func (d *sqliteDialect) rebind(query string) string {
+	return query
+}
+
+func (d *sqliteDialect) upsert(table string, keys, columns []string, updates string) string {
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", insertSQL(table, columns), strings.Join(keys, ", "), updates)
+}
+
+type postgresDialect struct{}
+
+func (d *postgresDialect) name() string {
+	return "postgres"
+}
+
+func (d *postgresDialect) rebind(query string) string {
+	return sqlx.Rebind(sqlx.DOLLAR, query)
+}
+
+func (d *postgresDialect) upsert(table string, keys, columns []string, updates string) string {
+	return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", insertSQL(table, columns), strings.Join(keys, ", "), updates)
+}
+
+func insertSQL(table string, columns []string) string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table,
+		strings.Join(columns, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+}
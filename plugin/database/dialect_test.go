@@ -0,0 +1,88 @@
+package database
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// MockCreateSchema creates the tables of the sqlite schema shipped in scripts/common
+func MockCreateSchema(d *dbStorage) error {
+	schema, err := ioutil.ReadFile("../../scripts/common/tables_sqlite.sql")
+	if err != nil {
+		return err
+	}
+	_, err = d.exec(nil, string(schema))
+	return err
+}
+
+func TestNewDialect(t *testing.T) {
+	for _, typ := range []string{"mysql", "sqlite3", "postgres", "pgx"} {
+		d, err := newDialect(typ)
+		assert.NoError(t, err)
+		assert.NotNil(t, d)
+	}
+	_, err := newDialect("oracle")
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrDatabase, err.(interface{ Code() string }).Code())
+}
+
+func TestDialectSQL(t *testing.T) {
+	query := "SELECT name FROM t WHERE a=? AND b=? LIMIT ? OFFSET ?"
+	keys, columns := []string{"id"}, []string{"id", "version"}
+	updates := "version=t.version+1"
+
+	d := &mysqlDialect{}
+	assert.Equal(t, query, d.rebind(query))
+	assert.Equal(t, "INSERT INTO t (id, version) VALUES (?, ?) ON DUPLICATE KEY UPDATE version=t.version+1",
+		d.upsert("t", keys, columns, updates))
+
+	s := &sqliteDialect{}
+	assert.Equal(t, query, s.rebind(query))
+	assert.Equal(t, "INSERT INTO t (id, version) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET version=t.version+1",
+		s.upsert("t", keys, columns, updates))
+
+	p := &postgresDialect{}
+	assert.Equal(t, "SELECT name FROM t WHERE a=$1 AND b=$2 LIMIT $3 OFFSET $4", p.rebind(query))
+	assert.Equal(t, "INSERT INTO t (id, version) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET version=t.version+1",
+		p.rebind(p.upsert("t", keys, columns, updates)))
+}
+
+func TestSQLiteSchema(t *testing.T) {
+	db, err := MockNewDB()
+	assert.NoError(t, err)
+	db.db.SetMaxOpenConns(1)
+	assert.NoError(t, MockCreateSchema(db))
+	// the schema can be applied again
+	assert.NoError(t, MockCreateSchema(db))
+
+	assert.NoError(t, db.CreateProperty(&models.Property{Name: "a", Value: "1"}))
+	assert.NoError(t, db.CreateProperty(&models.Property{Name: "b", Value: "2"}))
+
+	type row struct {
+		Id         int64     `db:"id"`
+		UpdateTime time.Time `db:"update_time"`
+	}
+	var rows []row
+	assert.NoError(t, db.query(nil, "SELECT id, update_time FROM baetyl_property ORDER BY id", &rows))
+	assert.Len(t, rows, 2)
+	assert.Equal(t, int64(1), rows[0].Id)
+	assert.Equal(t, int64(2), rows[1].Id)
+
+	// the update time is refreshed by the trigger unless it is set explicitly
+	past := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = db.exec(nil, "UPDATE baetyl_property SET update_time=? WHERE name=?", past, "a")
+	assert.NoError(t, err)
+	rows = nil
+	assert.NoError(t, db.query(nil, "SELECT id, update_time FROM baetyl_property WHERE name=?", &rows, "a"))
+	assert.Equal(t, past, rows[0].UpdateTime.UTC())
+	assert.NoError(t, db.UpdateProperty(&models.Property{Name: "a", Value: "3"}))
+	rows = nil
+	assert.NoError(t, db.query(nil, "SELECT id, update_time FROM baetyl_property WHERE name=?", &rows, "a"))
+	assert.True(t, rows[0].UpdateTime.After(past))
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type APIKey struct {
+	Name        string     `db:"name"`
+	UserID      string     `db:"user_id"`
+	Namespaces  string     `db:"namespaces"`
+	KeyPrefix   string     `db:"key_prefix"`
+	KeyHash     string     `db:"key_hash"`
+	Description string     `db:"description"`
+	ExpireTime  *time.Time `db:"expire_time"`
+	CreateTime  time.Time  `db:"create_time"`
+	UpdateTime  time.Time  `db:"update_time"`
+}
+
+func ToAPIKeyModel(k *APIKey) *models.APIKey {
+	var namespaces []string
+	if err := json.Unmarshal([]byte(k.Namespaces), &namespaces); err != nil {
+		log.L().Error("api key db namespaces unmarshal error", log.Any("namespaces", k.Namespaces))
+	}
+	return &models.APIKey{
+		Name:        k.Name,
+		User:        k.UserID,
+		Namespaces:  namespaces,
+		KeyPrefix:   k.KeyPrefix,
+		KeyHash:     k.KeyHash,
+		Description: k.Description,
+		ExpireTime:  k.ExpireTime,
+		CreateTime:  k.CreateTime,
+		UpdateTime:  k.UpdateTime,
+	}
+}
+
+func FromAPIKeyModel(k *models.APIKey) *APIKey {
+	namespaces, err := json.Marshal(k.Namespaces)
+	if err != nil {
+		log.L().Error("api key namespaces marshal error", log.Any("namespaces", k.Namespaces))
+		namespaces = []byte("[]")
+	}
+	return &APIKey{
+		Name:        k.Name,
+		UserID:      k.User,
+		Namespaces:  string(namespaces),
+		KeyPrefix:   k.KeyPrefix,
+		KeyHash:     k.KeyHash,
+		Description: k.Description,
+		ExpireTime:  k.ExpireTime,
+		CreateTime:  k.CreateTime,
+		UpdateTime:  k.UpdateTime,
+	}
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestConvertAPIKey(t *testing.T) {
+	expire := time.Unix(2000, 0)
+	key := &models.APIKey{
+		Name:        "key01",
+		User:        "user01",
+		Namespaces:  []string{"default", "test"},
+		KeyPrefix:   "bk_12345678",
+		KeyHash:     "hash",
+		Description: "desc",
+		ExpireTime:  &expire,
+		CreateTime:  time.Unix(1000, 10),
+		UpdateTime:  time.Unix(1000, 10),
+	}
+	keyDB := &APIKey{
+		Name:        "key01",
+		UserID:      "user01",
+		Namespaces:  "[\"default\",\"test\"]",
+		KeyPrefix:   "bk_12345678",
+		KeyHash:     "hash",
+		Description: "desc",
+		ExpireTime:  &expire,
+		CreateTime:  time.Unix(1000, 10),
+		UpdateTime:  time.Unix(1000, 10),
+	}
+	assert.EqualValues(t, key, ToAPIKeyModel(keyDB))
+	assert.EqualValues(t, keyDB, FromAPIKeyModel(key))
+}
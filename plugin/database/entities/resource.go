@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+)
+
+// Resource the node, application, configuration or secret stored as json content
+type Resource struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Version    string    `db:"version"`
+	Content    string    `db:"content"`
+	CreateTime time.Time `db:"create_time"`
+	UpdateTime time.Time `db:"update_time"`
+}
+
+// FromResourceModel marshals the model into the content of the resource
+func FromResourceModel(namespace, name, version string, model interface{}) (*Resource, error) {
+	content, err := json.Marshal(model)
+	if err != nil {
+		log.L().Error("resource translate to db model error",
+			log.Any("namespace", namespace),
+			log.Any("name", name),
+			log.Any("version", version))
+		return nil, err
+	}
+	return &Resource{
+		Namespace: namespace,
+		Name:      name,
+		Version:   version,
+		Content:   string(content),
+	}, nil
+}
+
+// ToResourceModel unmarshals the content of the resource into the model
+func ToResourceModel(resource *Resource, model interface{}) error {
+	if resource.Content == "" {
+		return nil
+	}
+	err := json.Unmarshal([]byte(resource.Content), model)
+	if err != nil {
+		log.L().Error("resource db to resource error",
+			log.Any("namespace", resource.Namespace),
+			log.Any("name", resource.Name),
+			log.Any("version", resource.Version))
+	}
+	return err
+}
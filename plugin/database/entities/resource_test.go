@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertResource(t *testing.T) {
+	config := &specV1.Configuration{
+		Name:      "c1",
+		Namespace: "default",
+		Labels:    map[string]string{"a": "a"},
+		Data:      map[string]string{"k": "v"},
+	}
+	resource, err := FromResourceModel(config.Namespace, config.Name, "1", config)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", resource.Namespace)
+	assert.Equal(t, "c1", resource.Name)
+	assert.Equal(t, "1", resource.Version)
+	assert.Equal(t, `{"name":"c1","namespace":"default","labels":{"a":"a"},"data":{"k":"v"},"createTime":"0001-01-01T00:00:00Z","updateTime":"0001-01-01T00:00:00Z"}`, resource.Content)
+
+	res := new(specV1.Configuration)
+	assert.NoError(t, ToResourceModel(resource, res))
+	assert.Equal(t, config, res)
+
+	resource.Content = "{"
+	assert.Error(t, ToResourceModel(resource, res))
+	resource.Content = ""
+	assert.NoError(t, ToResourceModel(resource, res))
+}
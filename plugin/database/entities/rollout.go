@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type Rollout struct {
+	Name       string    `db:"name"`
+	Namespace  string    `db:"namespace"`
+	AppName    string    `db:"app_name"`
+	AppVersion string    `db:"app_version"`
+	Status     string    `db:"status"`
+	Strategy   string    `db:"strategy"`
+	Progress   string    `db:"progress"`
+	Message    string    `db:"message"`
+	CreateTime time.Time `db:"create_time"`
+	UpdateTime time.Time `db:"update_time"`
+}
+
+func ToRolloutModel(rollout *Rollout) *models.Rollout {
+	var strategy models.RolloutStrategy
+	if err := json.Unmarshal([]byte(rollout.Strategy), &strategy); err != nil {
+		log.L().Error("rollout db strategy unmarshal error", log.Any("strategy", rollout.Strategy))
+	}
+	var progress models.RolloutProgress
+	if err := json.Unmarshal([]byte(rollout.Progress), &progress); err != nil {
+		log.L().Error("rollout db progress unmarshal error", log.Any("progress", rollout.Progress))
+	}
+	return &models.Rollout{
+		Name:       rollout.Name,
+		Namespace:  rollout.Namespace,
+		AppName:    rollout.AppName,
+		AppVersion: rollout.AppVersion,
+		Status:     rollout.Status,
+		Strategy:   strategy,
+		Progress:   progress,
+		Message:    rollout.Message,
+		CreateTime: rollout.CreateTime,
+		UpdateTime: rollout.UpdateTime,
+	}
+}
+
+func FromRolloutModel(rollout *models.Rollout) *Rollout {
+	strategy, err := json.Marshal(rollout.Strategy)
+	if err != nil {
+		log.L().Error("rollout strategy marshal error", log.Any("strategy", rollout.Strategy))
+		strategy = []byte("{}")
+	}
+	progress, err := json.Marshal(rollout.Progress)
+	if err != nil {
+		log.L().Error("rollout progress marshal error", log.Any("progress", rollout.Progress))
+		progress = []byte("{}")
+	}
+	return &Rollout{
+		Name:       rollout.Name,
+		Namespace:  rollout.Namespace,
+		AppName:    rollout.AppName,
+		AppVersion: rollout.AppVersion,
+		Status:     rollout.Status,
+		Strategy:   string(strategy),
+		Progress:   string(progress),
+		Message:    rollout.Message,
+		CreateTime: rollout.CreateTime,
+		UpdateTime: rollout.UpdateTime,
+	}
+}
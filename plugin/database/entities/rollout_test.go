@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestConvertRollout(t *testing.T) {
+	rollout := &models.Rollout{
+		Name:       "app-1",
+		Namespace:  "default",
+		AppName:    "app",
+		AppVersion: "1",
+		Status:     models.RolloutRunning,
+		Strategy: models.RolloutStrategy{
+			Percent:       50,
+			FailurePolicy: models.RolloutPolicyPause,
+		},
+		Progress: models.RolloutProgress{
+			Total:     2,
+			Deployed:  1,
+			Wave:      1,
+			WaveNodes: []string{"n1"},
+			WaveTime:  time.Unix(1000, 0).UTC(),
+			Nodes:     []string{"n1", "n2"},
+		},
+		CreateTime: time.Unix(1000, 10),
+		UpdateTime: time.Unix(1000, 10),
+	}
+	rolloutDB := &Rollout{
+		Name:       "app-1",
+		Namespace:  "default",
+		AppName:    "app",
+		AppVersion: "1",
+		Status:     models.RolloutRunning,
+		Strategy:   `{"percent":50,"failurePolicy":"pause"}`,
+		Progress:   `{"total":2,"deployed":1,"wave":1,"waveNodes":["n1"],"waveTime":"1970-01-01T00:16:40Z","nodes":["n1","n2"]}`,
+		CreateTime: time.Unix(1000, 10),
+		UpdateTime: time.Unix(1000, 10),
+	}
+	assert.EqualValues(t, rolloutDB, FromRolloutModel(rollout))
+	assert.EqualValues(t, rollout, ToRolloutModel(rolloutDB))
+}
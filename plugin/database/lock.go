@@ -0,0 +1,77 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func (d *dbStorage) GetLock(name string) (*models.Lock, error) {
+	return d.GetLockTx(nil, name)
+}
+
+func (d *dbStorage) CreateLock(lock *models.Lock) (sql.Result, error) {
+	return d.CreateLockTx(nil, lock)
+}
+
+func (d *dbStorage) RenewLock(lock *models.Lock) (sql.Result, error) {
+	return d.RenewLockTx(nil, lock)
+}
+
+func (d *dbStorage) DeleteLock(name, owner string) (sql.Result, error) {
+	return d.DeleteLockTx(nil, name, owner)
+}
+
+func (d *dbStorage) DeleteExpiredLock(name string, now time.Time) (sql.Result, error) {
+	return d.DeleteExpiredLockTx(nil, name, now)
+}
+
+func (d *dbStorage) GetLockTx(tx *sqlx.Tx, name string) (*models.Lock, error) {
+	selectSQL := `
+SELECT name, owner, expire_time 
+FROM baetyl_lock 
+WHERE name=? LIMIT 1
+`
+	var locks []models.Lock
+	if err := d.query(tx, selectSQL, &locks, name); err != nil {
+		return nil, err
+	}
+	if len(locks) > 0 {
+		return &locks[0], nil
+	}
+	return nil, nil
+}
+
+func (d *dbStorage) CreateLockTx(tx *sqlx.Tx, lock *models.Lock) (sql.Result, error) {
+	insertSQL := `
+INSERT INTO baetyl_lock (name, owner, expire_time) 
+VALUES (?,?,?)
+`
+	return d.exec(tx, insertSQL, lock.Name, lock.Owner, lock.ExpireTime)
+}
+
+// RenewLockTx affects no row if the lock is not held by the owner
+func (d *dbStorage) RenewLockTx(tx *sqlx.Tx, lock *models.Lock) (sql.Result, error) {
+	updateSQL := `
+UPDATE baetyl_lock SET expire_time=? 
+WHERE name=? AND owner=?
+`
+	return d.exec(tx, updateSQL, lock.ExpireTime, lock.Name, lock.Owner)
+}
+
+func (d *dbStorage) DeleteLockTx(tx *sqlx.Tx, name, owner string) (sql.Result, error) {
+	deleteSQL := `
+DELETE FROM baetyl_lock WHERE name=? AND owner=?
+`
+	return d.exec(tx, deleteSQL, name, owner)
+}
+
+func (d *dbStorage) DeleteExpiredLockTx(tx *sqlx.Tx, name string, now time.Time) (sql.Result, error) {
+	deleteSQL := `
+DELETE FROM baetyl_lock WHERE name=? AND expire_time<?
+`
+	return d.exec(tx, deleteSQL, name, now)
+}
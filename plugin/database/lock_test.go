@@ -0,0 +1,67 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestLock(t *testing.T) {
+	db := mockMigratedDB(t)
+	now := time.Now().UTC()
+	lock := &models.Lock{Name: "rollout", Owner: "r0", ExpireTime: now.Add(time.Minute)}
+
+	res, err := db.GetLock(lock.Name)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	_, err = db.CreateLock(lock)
+	assert.NoError(t, err)
+	// held by the owner
+	_, err = db.CreateLock(&models.Lock{Name: "rollout", Owner: "r1", ExpireTime: now.Add(time.Minute)})
+	assert.Error(t, err)
+	res, err = db.GetLock(lock.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, "r0", res.Owner)
+
+	// renewed by the owner only
+	result, err := db.RenewLock(&models.Lock{Name: "rollout", Owner: "r1", ExpireTime: now.Add(time.Hour)})
+	assert.NoError(t, err)
+	n, err := result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	lock.ExpireTime = now.Add(time.Second)
+	result, err = db.RenewLock(lock)
+	assert.NoError(t, err)
+	n, err = result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	// not expired
+	result, err = db.DeleteExpiredLock(lock.Name, now)
+	assert.NoError(t, err)
+	n, err = result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	result, err = db.DeleteExpiredLock(lock.Name, now.Add(time.Minute))
+	assert.NoError(t, err)
+	n, err = result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	_, err = db.CreateLock(lock)
+	assert.NoError(t, err)
+	result, err = db.DeleteLock(lock.Name, "r1")
+	assert.NoError(t, err)
+	n, err = result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	result, err = db.DeleteLock(lock.Name, "r0")
+	assert.NoError(t, err)
+	n, err = result.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
@@ -0,0 +1,434 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// the migrations of each dialect are named as <version>_<description>.up.sql and <version>_<description>.down.sql,
+// the baseline (version 1) creates the tables existing before the migrations are introduced and is irreversible,
+// so it has no down script
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+const (
+	migrationDir       = "migrations"
+	migrationBaseline  = int64(1)
+	migrationLockRetry = time.Second
+)
+
+var migrationTables = []string{`
+CREATE TABLE IF NOT EXISTS baetyl_schema_migration (
+  version bigint NOT NULL,
+  description varchar(255) NOT NULL DEFAULT '',
+  apply_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (version)
+)`, `
+CREATE TABLE IF NOT EXISTS baetyl_schema_lock (
+  id int NOT NULL,
+  owner varchar(128) NOT NULL DEFAULT '',
+  lock_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+)`}
+
+// migration a versioned change of the schema
+type migration struct {
+	version     int64
+	description string
+	up          string
+	down        string
+}
+
+// MigrationStatus the status of a migration of the schema
+type MigrationStatus struct {
+	Version     int64
+	Description string
+	Applied     bool
+	ApplyTime   time.Time
+}
+
+// Migrator applies or reverts the migrations of the schema in order,
+// the applied versions are recorded in the table baetyl_schema_migration
+// and the table baetyl_schema_lock prevents the replicas from migrating at the same time
+type Migrator struct {
+	d           *dbStorage
+	migrations  []migration
+	lockTimeout time.Duration
+	owner       string
+	// lockLost is set to 1 if the lock is taken over while migrating
+	lockLost int32
+}
+
+// NewMigrator creates the migrator of the database configured
+func NewMigrator() (*Migrator, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, err
+	}
+	d, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(d), nil
+}
+
+func newMigrator(d *dbStorage) *Migrator {
+	hostname, _ := os.Hostname()
+	return &Migrator{
+		d:           d,
+		lockTimeout: d.cfg.Database.Migration.LockTimeout,
+		owner:       fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// Close Close
+func (m *Migrator) Close() error {
+	return m.d.Close()
+}
+
+// Status returns the status of all migrations in the order of the versions
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	if err = m.prepare(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var res []MigrationStatus
+	for _, mi := range migrations {
+		status := MigrationStatus{Version: mi.version, Description: mi.description}
+		if s, ok := applied[mi.version]; ok {
+			status = s
+		}
+		res = append(res, status)
+	}
+	return res, nil
+}
+
+// Up applies at most steps pending migrations, all pending migrations are applied if steps is not positive,
+// the migrations applied are returned
+func (m *Migrator) Up(steps int) ([]MigrationStatus, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	var res []MigrationStatus
+	err = m.locked(func() error {
+		applied, err := m.applied()
+		if err != nil {
+			return err
+		}
+		for _, mi := range migrations {
+			if _, ok := applied[mi.version]; ok {
+				continue
+			}
+			if steps > 0 && len(res) == steps {
+				break
+			}
+			if err = m.apply(mi, mi.up, true); err != nil {
+				return err
+			}
+			res = append(res, MigrationStatus{Version: mi.version, Description: mi.description, Applied: true})
+		}
+		return nil
+	})
+	return res, err
+}
+
+// Down reverts at most steps applied migrations from the latest one, all applied migrations except the baseline are reverted
+// if steps is not positive, the migrations reverted are returned. Reverting the baseline is rejected
+func (m *Migrator) Down(steps int) ([]MigrationStatus, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	var res []MigrationStatus
+	err = m.locked(func() error {
+		applied, err := m.applied()
+		if err != nil {
+			return err
+		}
+		var reverts []migration
+		for i := len(migrations) - 1; i >= 0; i-- {
+			mi := migrations[i]
+			if _, ok := applied[mi.version]; !ok || mi.version <= migrationBaseline {
+				continue
+			}
+			reverts = append(reverts, mi)
+		}
+		if steps > len(reverts) {
+			return common.Error(common.ErrDatabase,
+				common.Field("error", fmt.Sprintf("the baseline migration (%d) is irreversible, at most %d migrations can be reverted", migrationBaseline, len(reverts))))
+		}
+		if steps > 0 {
+			reverts = reverts[:steps]
+		}
+		for _, mi := range reverts {
+			if err = m.apply(mi, mi.down, false); err != nil {
+				return err
+			}
+			res = append(res, MigrationStatus{Version: mi.version, Description: mi.description})
+		}
+		return nil
+	})
+	return res, err
+}
+
+// apply executes the statements of the migration and records the version in a transaction,
+// note that the statements of ddl are committed implicitly by mysql
+func (m *Migrator) apply(mi migration, script string, up bool) error {
+	if atomic.LoadInt32(&m.lockLost) == 1 {
+		return common.Error(common.ErrDatabase, common.Field("error", "the lock of the migrations is lost"))
+	}
+	err := m.d.Transact(func(tx *sqlx.Tx) error {
+		for _, stmt := range splitStatements(script) {
+			if _, err := tx.Exec(stmt); err != nil {
+				return common.Error(common.ErrDatabase,
+					common.Field("error", fmt.Sprintf("failed to migrate version %d: %s", mi.version, err.Error())))
+			}
+		}
+		if up {
+			_, err := m.d.exec(tx, "INSERT INTO baetyl_schema_migration (version, description) VALUES (?, ?)", mi.version, mi.description)
+			return err
+		}
+		_, err := m.d.exec(tx, "DELETE FROM baetyl_schema_migration WHERE version=?", mi.version)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	log.L().Info("migration done", log.Any("version", mi.version), log.Any("description", mi.description), log.Any("up", up))
+	return nil
+}
+
+func (m *Migrator) applied() (map[int64]MigrationStatus, error) {
+	var rows []struct {
+		Version     int64     `db:"version"`
+		Description string    `db:"description"`
+		ApplyTime   time.Time `db:"apply_time"`
+	}
+	if err := m.d.query(nil, "SELECT version, description, apply_time FROM baetyl_schema_migration", &rows); err != nil {
+		return nil, err
+	}
+	res := map[int64]MigrationStatus{}
+	for _, r := range rows {
+		res[r.Version] = MigrationStatus{Version: r.Version, Description: r.Description, Applied: true, ApplyTime: r.ApplyTime}
+	}
+	return res, nil
+}
+
+func (m *Migrator) prepare() error {
+	for _, stmt := range migrationTables {
+		if _, err := m.d.exec(nil, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// locked runs the handler with the lock of the migrations held, the lock time is refreshed while the handler runs,
+// so the lock not refreshed within the lock timeout is regarded as abandoned by a crashed replica and taken over
+func (m *Migrator) locked(handler func() error) error {
+	if err := m.prepare(); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(m.lockTimeout)
+	for {
+		ok, err := m.lock()
+		if err != nil {
+			return err
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			return common.Error(common.ErrDatabase, common.Field("error", "timeout to wait for the lock of the migrations"))
+		}
+		log.L().Info("waiting for the lock of the migrations")
+		time.Sleep(migrationLockRetry)
+	}
+	defer m.unlock()
+	stop := m.keepLock()
+	defer stop()
+	return handler()
+}
+
+// keepLock refreshes the lock time until stopped, the pending migrations are not applied once the lock is lost
+func (m *Migrator) keepLock() func() {
+	atomic.StoreInt32(&m.lockLost, 0)
+	interval := m.lockTimeout / 3
+	if interval <= 0 {
+		interval = migrationLockRetry
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				res, err := m.d.exec(nil, "UPDATE baetyl_schema_lock SET lock_time=? WHERE id=1 AND owner=?", time.Now().UTC(), m.owner)
+				if err != nil {
+					log.L().Warn("failed to refresh the lock of the migrations", log.Error(err))
+					continue
+				}
+				if n, err := res.RowsAffected(); err == nil && n == 0 {
+					atomic.StoreInt32(&m.lockLost, 1)
+					log.L().Error("the lock of the migrations is lost", log.Any("owner", m.owner))
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (m *Migrator) lock() (bool, error) {
+	now := time.Now().UTC()
+	if _, err := m.d.exec(nil, "DELETE FROM baetyl_schema_lock WHERE id=1 AND lock_time<?", now.Add(-m.lockTimeout)); err != nil {
+		return false, err
+	}
+	if _, err := m.d.exec(nil, "INSERT INTO baetyl_schema_lock (id, owner, lock_time) VALUES (1, ?, ?)", m.owner, now); err != nil {
+		var owners []string
+		if qerr := m.d.query(nil, "SELECT owner FROM baetyl_schema_lock WHERE id=1", &owners); qerr != nil || len(owners) == 0 {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m *Migrator) unlock() {
+	if _, err := m.d.exec(nil, "DELETE FROM baetyl_schema_lock WHERE id=1 AND owner=?", m.owner); err != nil {
+		log.L().Error("failed to release the lock of the migrations", log.Error(err))
+	}
+}
+
+func (m *Migrator) load() ([]migration, error) {
+	if m.migrations != nil {
+		return m.migrations, nil
+	}
+	migrations, err := loadMigrations(m.d.dialect.name())
+	if err != nil {
+		return nil, err
+	}
+	m.migrations = migrations
+	return migrations, nil
+}
+
+// loadMigrations loads the embedded migrations of the dialect in the order of the versions
+func loadMigrations(dialect string) ([]migration, error) {
+	dir := path.Join(migrationDir, dialect)
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	migrations := map[int64]*migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var base string
+		up := strings.HasSuffix(name, ".up.sql")
+		if up {
+			base = strings.TrimSuffix(name, ".up.sql")
+		} else if strings.HasSuffix(name, ".down.sql") {
+			base = strings.TrimSuffix(name, ".down.sql")
+		} else {
+			continue
+		}
+		parts := strings.SplitN(base, "_", 2)
+		version, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) != 2 {
+			return nil, common.Error(common.ErrDatabase, common.Field("error", fmt.Sprintf("the name of the migration (%s) is invalid", name)))
+		}
+		content, err := migrationFiles.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		mi, ok := migrations[version]
+		if !ok {
+			mi = &migration{version: version, description: strings.Replace(parts[1], "_", " ", -1)}
+			migrations[version] = mi
+		}
+		if up {
+			mi.up = string(content)
+		} else {
+			mi.down = string(content)
+		}
+	}
+	var res []migration
+	for _, mi := range migrations {
+		if mi.version < migrationBaseline {
+			return nil, common.Error(common.ErrDatabase, common.Field("error", fmt.Sprintf("the version of the migration (%d) of %s is less than the baseline", mi.version, dialect)))
+		}
+		if mi.up == "" || (mi.down == "" && mi.version > migrationBaseline) {
+			return nil, common.Error(common.ErrDatabase, common.Field("error", fmt.Sprintf("the migration (%d) of %s lacks the up or down script", mi.version, dialect)))
+		}
+		res = append(res, *mi)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].version < res[j].version
+	})
+	return res, nil
+}
+
+// splitStatements splits the script into statements ended with semicolons,
+// the semicolons in the bodies of the sqlite triggers (BEGIN ... END;) and the postgres functions ($$ ... $$) are kept
+func splitStatements(script string) []string {
+	var res []string
+	var stmt []string
+	var inBody, inDollar bool
+	for _, line := range strings.Split(script, "\n") {
+		code := line
+		if i := strings.Index(code, "--"); i >= 0 && strings.Count(code[:i], "'")%2 == 0 {
+			code = code[:i]
+		}
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		stmt = append(stmt, line)
+		if strings.Count(code, "$$")%2 == 1 {
+			inDollar = !inDollar
+		}
+		upper := strings.ToUpper(code)
+		if upper == "BEGIN" && !inDollar {
+			inBody = true
+		}
+		if inBody && upper == "END;" {
+			inBody = false
+		}
+		if !inBody && !inDollar && strings.HasSuffix(code, ";") {
+			res = append(res, strings.Join(stmt, "\n"))
+			stmt = nil
+		}
+	}
+	if len(stmt) > 0 {
+		res = append(res, strings.Join(stmt, "\n"))
+	}
+	return res
+}
@@ -0,0 +1,246 @@
+package database
+
+import (
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func mockNewMigrator(t *testing.T) *Migrator {
+	db, err := MockNewDB()
+	assert.NoError(t, err)
+	// the in-memory database is dropped with the connection
+	db.db.SetMaxOpenConns(1)
+	db.cfg.Database.Migration.LockTimeout = time.Second
+	return newMigrator(db)
+}
+
+// mockMigratedDB returns the database with all migrations applied
+func mockMigratedDB(t *testing.T) *dbStorage {
+	m := mockNewMigrator(t)
+	_, err := m.Up(0)
+	assert.NoError(t, err)
+	return m.d
+}
+
+func TestLoadMigrations(t *testing.T) {
+	for _, d := range []dialect{&mysqlDialect{}, &sqliteDialect{}, &postgresDialect{}} {
+		migrations, err := loadMigrations(d.name())
+		assert.NoError(t, err)
+		assert.NotEmpty(t, migrations)
+		assert.Equal(t, int64(1), migrations[0].version)
+		assert.Equal(t, "init", migrations[0].description)
+		// the baseline is irreversible
+		assert.Empty(t, migrations[0].down)
+		for i := 1; i < len(migrations); i++ {
+			assert.True(t, migrations[i-1].version < migrations[i].version)
+			assert.NotEmpty(t, migrations[i].down)
+		}
+	}
+	_, err := loadMigrations("oracle")
+	assert.Error(t, err)
+
+	// the schemas shipped for manual installation create the same tables and indexes as the migrations
+	objects := func(script string) []string {
+		var res []string
+		for _, m := range schemaObject.FindAllStringSubmatch(script, -1) {
+			res = append(res, strings.ToLower(m[1])+" "+m[2])
+		}
+		sort.Strings(res)
+		return res
+	}
+	for d, file := range map[string]string{"mysql": "tables.sql", "sqlite3": "tables_sqlite.sql", "postgres": "tables_postgres.sql"} {
+		migrations, err := loadMigrations(d)
+		assert.NoError(t, err)
+		var ups []string
+		for _, mi := range migrations {
+			ups = append(ups, mi.up)
+		}
+		schema, err := ioutil.ReadFile("../../scripts/common/" + file)
+		assert.NoError(t, err)
+		assert.Equal(t, objects(string(schema)), objects(strings.Join(ups, "\n")), d)
+	}
+}
+
+var schemaObject = regexp.MustCompile("(?i)CREATE (TABLE|INDEX) IF NOT EXISTS `?(\\w+)`?")
+
+func TestSplitStatements(t *testing.T) {
+	script := `
+-- the comment; is ignored
+CREATE TABLE a (
+  id int, -- the id;
+  name varchar(16) DEFAULT '--'
+);
+CREATE TRIGGER a_update AFTER UPDATE ON a
+BEGIN
+  UPDATE a SET name = 'a' WHERE id = NEW.id;
+END;
+CREATE OR REPLACE FUNCTION f() RETURNS TRIGGER AS $$
+BEGIN
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+DROP TABLE a`
+	stmts := splitStatements(script)
+	assert.Len(t, stmts, 4)
+	assert.Equal(t, "CREATE TABLE a (\n  id int, -- the id;\n  name varchar(16) DEFAULT '--'\n);", stmts[0])
+	assert.Equal(t, "CREATE TRIGGER a_update AFTER UPDATE ON a\nBEGIN\n  UPDATE a SET name = 'a' WHERE id = NEW.id;\nEND;", stmts[1])
+	assert.Equal(t, "CREATE OR REPLACE FUNCTION f() RETURNS TRIGGER AS $$\nBEGIN\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;", stmts[2])
+	assert.Equal(t, "DROP TABLE a", stmts[3])
+}
+
+func TestMigrator(t *testing.T) {
+	m := mockNewMigrator(t)
+	m.migrations = []migration{
+		{version: 1, description: "create a", up: "CREATE TABLE a (id int);", down: "DROP TABLE a;"},
+		{version: 2, description: "add name", up: "ALTER TABLE a ADD COLUMN name varchar(16);", down: "CREATE TABLE b (id int);\nINSERT INTO b SELECT id FROM a;\nDROP TABLE a;\nALTER TABLE b RENAME TO a;"},
+	}
+
+	status, err := m.Status()
+	assert.NoError(t, err)
+	assert.Len(t, status, 2)
+	assert.False(t, status[0].Applied)
+	assert.False(t, status[1].Applied)
+
+	res, err := m.Up(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []MigrationStatus{{Version: 1, Description: "create a", Applied: true}}, res)
+	_, err = m.d.exec(nil, "INSERT INTO a (id) VALUES (1)")
+	assert.NoError(t, err)
+
+	res, err = m.Up(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []MigrationStatus{{Version: 2, Description: "add name", Applied: true}}, res)
+	_, err = m.d.exec(nil, "UPDATE a SET name='a' WHERE id=1")
+	assert.NoError(t, err)
+
+	res, err = m.Up(0)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+	status, err = m.Status()
+	assert.NoError(t, err)
+	assert.True(t, status[0].Applied)
+	assert.True(t, status[1].Applied)
+	assert.False(t, status[1].ApplyTime.IsZero())
+
+	res, err = m.Down(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []MigrationStatus{{Version: 2, Description: "add name"}}, res)
+	_, err = m.d.exec(nil, "UPDATE a SET name='a' WHERE id=1")
+	assert.Error(t, err)
+
+	// the failed migration is not recorded
+	m.migrations[1].up = "ALTER TABLE c ADD COLUMN name varchar(16);"
+	_, err = m.Up(0)
+	assert.Error(t, err)
+	status, err = m.Status()
+	assert.NoError(t, err)
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[1].Applied)
+
+	// the baseline is irreversible
+	res, err = m.Down(0)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+	_, err = m.Down(1)
+	assert.Error(t, err)
+	_, err = m.d.exec(nil, "INSERT INTO a (id) VALUES (1)")
+	assert.NoError(t, err)
+
+	m.migrations[1].up = "ALTER TABLE a ADD COLUMN name varchar(16);"
+	_, err = m.Up(0)
+	assert.NoError(t, err)
+	res, err = m.Down(2)
+	assert.Error(t, err)
+	assert.Empty(t, res)
+	res, err = m.Down(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []MigrationStatus{{Version: 2, Description: "add name"}}, res)
+}
+
+func TestMigratorLock(t *testing.T) {
+	m := mockNewMigrator(t)
+	m.migrations = []migration{{version: 1, description: "create a", up: "CREATE TABLE a (id int);", down: "DROP TABLE a;"}}
+	assert.NoError(t, m.prepare())
+
+	// held by another replica
+	_, err := m.d.exec(nil, "INSERT INTO baetyl_schema_lock (id, owner, lock_time) VALUES (1, ?, ?)", "other", time.Now().UTC().Add(time.Hour))
+	assert.NoError(t, err)
+	_, err = m.Up(0)
+	assert.Error(t, err)
+
+	// abandoned by a crashed replica
+	_, err = m.d.exec(nil, "UPDATE baetyl_schema_lock SET lock_time=? WHERE id=1", time.Now().UTC().Add(-time.Hour))
+	assert.NoError(t, err)
+	res, err := m.Up(0)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+
+	// released after migrating
+	var owners []string
+	assert.NoError(t, m.d.query(nil, "SELECT owner FROM baetyl_schema_lock", &owners))
+	assert.Empty(t, owners)
+
+	// refreshed while migrating longer than the lock timeout
+	m.lockTimeout = 300 * time.Millisecond
+	other := newMigrator(m.d)
+	other.owner = "other"
+	err = m.locked(func() error {
+		time.Sleep(2 * m.lockTimeout)
+		ok, err := other.lock()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// the migrations stop once the lock is lost
+	err = m.locked(func() error {
+		_, err := m.d.exec(nil, "DELETE FROM baetyl_schema_lock WHERE id=1")
+		assert.NoError(t, err)
+		time.Sleep(m.lockTimeout)
+		return m.apply(m.migrations[0], m.migrations[0].down, false)
+	})
+	assert.Error(t, err)
+}
+
+func TestMigrateSQLite(t *testing.T) {
+	m := mockNewMigrator(t)
+	res, err := m.Up(0)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res)
+
+	// the tables are the same as the ones created by the schema
+	tables := func(d *dbStorage) []string {
+		var names []string
+		assert.NoError(t, d.query(nil, "SELECT name FROM sqlite_master WHERE name NOT LIKE 'sqlite_%' AND name NOT LIKE 'baetyl_schema_%' ORDER BY name", &names))
+		return names
+	}
+	db, err := MockNewDB()
+	assert.NoError(t, err)
+	db.db.SetMaxOpenConns(1)
+	assert.NoError(t, MockCreateSchema(db))
+	assert.Equal(t, tables(db), tables(m.d))
+
+	ms := &modelStorage{dbStorage: m.d}
+	_, err = ms.CreateNamespace(&models.Namespace{Name: "default"})
+	assert.NoError(t, err)
+
+	// the tables of the baseline are kept
+	res, err = m.Down(0)
+	assert.NoError(t, err)
+	assert.Len(t, res, len(m.migrations)-1)
+	assert.Contains(t, tables(m.d), "baetyl_batch")
+	assert.NotContains(t, tables(m.d), "baetyl_namespace")
+	res, err = m.Up(0)
+	assert.NoError(t, err)
+	assert.Len(t, res, len(m.migrations)-1)
+	assert.Equal(t, tables(db), tables(m.d))
+}
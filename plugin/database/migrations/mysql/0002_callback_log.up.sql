@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `baetyl_callback_log` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `callback_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'callback name',
+  `event` varchar(64) NOT NULL DEFAULT '' COMMENT '触发事件',
+  `method` varchar(36) NOT NULL DEFAULT 'GET' COMMENT 'Get/Post/Put/Delete',
+  `url` varchar(1024) NOT NULL DEFAULT '' COMMENT 'url',
+  `request` text COMMENT '请求内容',
+  `status_code` int(11) NOT NULL DEFAULT '0' COMMENT '响应状态码',
+  `response` text COMMENT '响应内容',
+  `error` varchar(1024) NOT NULL DEFAULT '' COMMENT '错误信息',
+  `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '请求次数',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_callback` (`namespace`,`callback_name`,`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='回调投递记录';
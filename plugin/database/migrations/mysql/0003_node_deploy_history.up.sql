@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS `baetyl_node_deploy_history` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `node_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'node名称',
+  `app_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app名称',
+  `old_version` varchar(36) NOT NULL DEFAULT '' COMMENT '原app版本',
+  `new_version` varchar(36) NOT NULL DEFAULT '' COMMENT '新app版本',
+  `trigger_type` varchar(32) NOT NULL DEFAULT '' COMMENT '触发类型 app/config/secret/label/rollout',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_node_date` (`namespace`,`node_name`,`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点部署历史';
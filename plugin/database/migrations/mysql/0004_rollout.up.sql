@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `baetyl_rollout` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `app_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app名称',
+  `app_version` varchar(36) NOT NULL DEFAULT '' COMMENT 'app版本',
+  `status` varchar(16) NOT NULL DEFAULT '' COMMENT '状态 running/paused/succeeded/aborted',
+  `strategy` varchar(2048) NOT NULL DEFAULT '{}' COMMENT '发布策略',
+  `progress` mediumtext COMMENT '发布进度',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT '状态说明',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='应用灰度发布表';
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS `baetyl_api_key` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `user_id` varchar(128) NOT NULL DEFAULT '' COMMENT '用户id',
+  `namespaces` varchar(2048) NOT NULL DEFAULT '[]' COMMENT '可访问的命名空间',
+  `key_prefix` varchar(16) NOT NULL DEFAULT '' COMMENT 'key前缀，用于识别',
+  `key_hash` varchar(64) NOT NULL DEFAULT '' COMMENT 'key的sha256摘要',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `expire_time` timestamp NULL DEFAULT NULL COMMENT '过期时间，为空则不过期',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`name`),
+  UNIQUE KEY `unique_key_hash` (`key_hash`),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='API key表';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `baetyl_role_binding` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `user_id` varchar(128) NOT NULL DEFAULT '' COMMENT '用户id',
+  `role` varchar(32) NOT NULL DEFAULT '' COMMENT '角色 viewer/operator/admin',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_user` (`namespace`,`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='角色绑定表';
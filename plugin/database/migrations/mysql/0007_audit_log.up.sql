@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `baetyl_audit_log` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `user_id` varchar(128) NOT NULL DEFAULT '' COMMENT '操作用户',
+  `request_id` varchar(64) NOT NULL DEFAULT '' COMMENT '请求id',
+  `method` varchar(16) NOT NULL DEFAULT '' COMMENT '请求方法',
+  `path` varchar(512) NOT NULL DEFAULT '' COMMENT '请求路径',
+  `resource` varchar(64) NOT NULL DEFAULT '' COMMENT '资源类型',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '资源名称',
+  `operation` varchar(32) NOT NULL DEFAULT '' COMMENT '操作 create/update/delete等',
+  `status` int(11) NOT NULL DEFAULT '0' COMMENT 'http状态码',
+  `code` varchar(64) NOT NULL DEFAULT '' COMMENT '错误码，成功为空',
+  `diff` mediumtext COMMENT '脱敏后的变更内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_time` (`namespace`,`create_time`),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='审计日志表';
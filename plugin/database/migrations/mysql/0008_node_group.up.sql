@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `baetyl_node_group` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `selector` varchar(2048) NOT NULL DEFAULT '' COMMENT '动态分组的标签选择器，为空则为静态分组',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点分组表';
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS baetyl_resource_version;
+DROP TABLE IF EXISTS baetyl_secret;
+DROP TABLE IF EXISTS baetyl_configuration;
+DROP TABLE IF EXISTS baetyl_application;
+DROP TABLE IF EXISTS baetyl_node;
+DROP TABLE IF EXISTS baetyl_namespace;
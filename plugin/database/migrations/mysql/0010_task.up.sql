@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `baetyl_task` (
+  `trace_id` varchar(36) NOT NULL DEFAULT '' COMMENT '任务id',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `node` varchar(128) NOT NULL DEFAULT '' COMMENT 'node名称',
+  `type` varchar(32) NOT NULL DEFAULT '' COMMENT '任务类型 command/ota',
+  `state` varchar(16) NOT NULL DEFAULT '0' COMMENT '任务状态',
+  `step` text NOT NULL COMMENT '任务内容',
+  `old_version` varchar(36) NOT NULL DEFAULT '' COMMENT '原版本',
+  `new_version` varchar(36) NOT NULL DEFAULT '' COMMENT '新版本',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`trace_id`),
+  KEY `idx_node_type_state` (`namespace`,`node`,`type`,`state`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点任务表';
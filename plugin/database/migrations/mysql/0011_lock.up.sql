@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS `baetyl_lock` (
+  `name` varchar(64) NOT NULL DEFAULT '' COMMENT '锁名称',
+  `owner` varchar(128) NOT NULL DEFAULT '' COMMENT '持有者',
+  `expire_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '过期时间',
+  PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='副本间的锁';
@@ -0,0 +1,13 @@
+-- 节点部署历史
+CREATE TABLE IF NOT EXISTS baetyl_node_deploy_history (
+  id BIGSERIAL, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  node_name varchar(128) NOT NULL DEFAULT '', -- node名称
+  app_name varchar(128) NOT NULL DEFAULT '', -- app名称
+  old_version varchar(36) NOT NULL DEFAULT '', -- 原app版本
+  new_version varchar(36) NOT NULL DEFAULT '', -- 新app版本
+  trigger_type varchar(32) NOT NULL DEFAULT '', -- 触发类型 app/config/secret/label/rollout
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS baetyl_node_deploy_history_idx_node_date ON baetyl_node_deploy_history (namespace, node_name, create_time);
@@ -0,0 +1,15 @@
+-- 角色绑定表
+CREATE TABLE IF NOT EXISTS baetyl_role_binding (
+  id BIGSERIAL, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  user_id varchar(128) NOT NULL DEFAULT '', -- 用户id
+  role varchar(32) NOT NULL DEFAULT '', -- 角色 viewer/operator/admin
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  PRIMARY KEY (id),
+  UNIQUE (namespace, user_id)
+);
+DROP TRIGGER IF EXISTS baetyl_role_binding_update_time ON baetyl_role_binding;
+CREATE TRIGGER baetyl_role_binding_update_time BEFORE UPDATE ON baetyl_role_binding
+FOR EACH ROW EXECUTE PROCEDURE baetyl_update_time();
@@ -0,0 +1,15 @@
+-- 节点分组表
+CREATE TABLE IF NOT EXISTS baetyl_node_group (
+  id BIGSERIAL, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  selector varchar(2048) NOT NULL DEFAULT '', -- 动态分组的标签选择器，为空则为静态分组
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  PRIMARY KEY (id),
+  UNIQUE (namespace, name)
+);
+DROP TRIGGER IF EXISTS baetyl_node_group_update_time ON baetyl_node_group;
+CREATE TRIGGER baetyl_node_group_update_time BEFORE UPDATE ON baetyl_node_group
+FOR EACH ROW EXECUTE PROCEDURE baetyl_update_time();
@@ -0,0 +1,79 @@
+-- 命名空间表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_namespace (
+  id BIGSERIAL, -- 主键
+  name varchar(64) NOT NULL DEFAULT '', -- 名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  PRIMARY KEY (id),
+  UNIQUE (name)
+);
+
+-- 节点表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_node (
+  id BIGSERIAL, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  PRIMARY KEY (id),
+  UNIQUE (namespace, name)
+);
+DROP TRIGGER IF EXISTS baetyl_node_update_time ON baetyl_node;
+CREATE TRIGGER baetyl_node_update_time BEFORE UPDATE ON baetyl_node
+FOR EACH ROW EXECUTE PROCEDURE baetyl_update_time();
+
+-- 应用表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_application (
+  id BIGSERIAL, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  PRIMARY KEY (id),
+  UNIQUE (namespace, name)
+);
+DROP TRIGGER IF EXISTS baetyl_application_update_time ON baetyl_application;
+CREATE TRIGGER baetyl_application_update_time BEFORE UPDATE ON baetyl_application
+FOR EACH ROW EXECUTE PROCEDURE baetyl_update_time();
+
+-- 配置表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_configuration (
+  id BIGSERIAL, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  PRIMARY KEY (id),
+  UNIQUE (namespace, name)
+);
+DROP TRIGGER IF EXISTS baetyl_configuration_update_time ON baetyl_configuration;
+CREATE TRIGGER baetyl_configuration_update_time BEFORE UPDATE ON baetyl_configuration
+FOR EACH ROW EXECUTE PROCEDURE baetyl_update_time();
+
+-- 密文表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_secret (
+  id BIGSERIAL, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  PRIMARY KEY (id),
+  UNIQUE (namespace, name)
+);
+DROP TRIGGER IF EXISTS baetyl_secret_update_time ON baetyl_secret;
+CREATE TRIGGER baetyl_secret_update_time BEFORE UPDATE ON baetyl_secret
+FOR EACH ROW EXECUTE PROCEDURE baetyl_update_time();
+
+-- 资源版本序列，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_resource_version (
+  id int NOT NULL, -- 主键
+  version bigint NOT NULL DEFAULT '0', -- 资源的最新版本
+  PRIMARY KEY (id)
+);
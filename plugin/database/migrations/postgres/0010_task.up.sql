@@ -0,0 +1,18 @@
+-- 节点任务表
+CREATE TABLE IF NOT EXISTS baetyl_task (
+  trace_id varchar(36) NOT NULL DEFAULT '', -- 任务id
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  node varchar(128) NOT NULL DEFAULT '', -- node名称
+  type varchar(32) NOT NULL DEFAULT '', -- 任务类型 command/ota
+  state varchar(16) NOT NULL DEFAULT '0', -- 任务状态
+  step text NOT NULL, -- 任务内容
+  old_version varchar(36) NOT NULL DEFAULT '', -- 原版本
+  new_version varchar(36) NOT NULL DEFAULT '', -- 新版本
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  PRIMARY KEY (trace_id)
+);
+CREATE INDEX IF NOT EXISTS baetyl_task_idx_node_type_state ON baetyl_task (namespace, node, type, state);
+DROP TRIGGER IF EXISTS baetyl_task_update_time ON baetyl_task;
+CREATE TRIGGER baetyl_task_update_time BEFORE UPDATE ON baetyl_task
+FOR EACH ROW EXECUTE PROCEDURE baetyl_update_time();
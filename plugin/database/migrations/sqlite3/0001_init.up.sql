@@ -0,0 +1,193 @@
+-- the schema of baetyl-cloud for sqlite, the update_time columns are maintained by the triggers
+
+-- application历史信息表
+CREATE TABLE IF NOT EXISTS baetyl_application_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- app名称
+  version varchar(36) NOT NULL DEFAULT '', -- app版本
+  is_deleted smallint NOT NULL DEFAULT '0', -- 删除标记1:删除
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  content text -- app详情
+);
+CREATE INDEX IF NOT EXISTS baetyl_application_history_idx_app_history ON baetyl_application_history (namespace, name, version);
+CREATE INDEX IF NOT EXISTS baetyl_application_history_idx_app_date ON baetyl_application_history (namespace, create_time);
+CREATE TRIGGER IF NOT EXISTS baetyl_application_history_update_time AFTER UPDATE ON baetyl_application_history
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_application_history SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 批号管理
+CREATE TABLE IF NOT EXISTS baetyl_batch (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  name varchar(128) NOT NULL DEFAULT '', -- 批号
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  description varchar(1024) NOT NULL DEFAULT '', -- 型号描述信息
+  quota_num int NOT NULL DEFAULT '200', -- 数量
+  enable_whitelist int NOT NULL DEFAULT '1', -- 是否启用白名单
+  security_type varchar(32) NOT NULL DEFAULT 'Token', -- 安全等级 None/Token/Cert/Dongle
+  security_key varchar(64) NOT NULL DEFAULT '', -- null/token/cert_id/dongle_id
+  callback_name varchar(64) NOT NULL DEFAULT '', -- callback name
+  labels varchar(2048) NOT NULL DEFAULT '{}', -- 标签，json格式字符串,会设置到激活的node上
+  fingerprint varchar(1024) NOT NULL DEFAULT '{}', -- 设备指纹信息，json格式字符串，包含类型等数据
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_batch_update_time AFTER UPDATE ON baetyl_batch
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_batch SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 注册记录管理
+CREATE TABLE IF NOT EXISTS baetyl_batch_record (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  name varchar(128) NOT NULL DEFAULT '', -- 注册记录uuid
+  batch_name varchar(128) NOT NULL DEFAULT '', -- 批号
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  fingerprint_value varchar(255) NOT NULL DEFAULT '', -- 注册序列号,多种组合采用逗号隔开
+  active int NOT NULL DEFAULT '0', -- 是否激活
+  node_name varchar(64) NOT NULL DEFAULT '', -- node资源名称
+  active_ip varchar(64) NOT NULL DEFAULT '0.0.0.0', -- 激活ip
+  active_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 激活时间
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, batch_name, fingerprint_value),
+  UNIQUE (namespace, batch_name, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_batch_record_update_time AFTER UPDATE ON baetyl_batch_record
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_batch_record SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 回调
+CREATE TABLE IF NOT EXISTS baetyl_callback (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  name varchar(128) NOT NULL DEFAULT '', -- callback uuid
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  method varchar(36) NOT NULL DEFAULT 'GET', -- Get/Post/Put/Delete
+  params varchar(2048) NOT NULL DEFAULT '{}', -- query params
+  body varchar(2048) NOT NULL DEFAULT '{}', -- body
+  header varchar(1024) NOT NULL DEFAULT '{}', -- header
+  url varchar(1024) NOT NULL DEFAULT '', -- url
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_callback_update_time AFTER UPDATE ON baetyl_callback
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_callback SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 应用与配置索引表
+CREATE TABLE IF NOT EXISTS baetyl_index_application_config (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  application varchar(128) NOT NULL DEFAULT '', -- app名称
+  config varchar(128) NOT NULL DEFAULT '', -- config名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 更新时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_config_idx_application ON baetyl_index_application_config (namespace, application);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_config_idx_config ON baetyl_index_application_config (namespace, config);
+CREATE TRIGGER IF NOT EXISTS baetyl_index_application_config_update_time AFTER UPDATE ON baetyl_index_application_config
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_index_application_config SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 应用与节点索引表
+CREATE TABLE IF NOT EXISTS baetyl_index_application_node (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  application varchar(128) NOT NULL DEFAULT '', -- app名称
+  node varchar(128) NOT NULL DEFAULT '', -- node名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 更新时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_node_idx_application ON baetyl_index_application_node (namespace, application);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_node_idx_node ON baetyl_index_application_node (namespace, node);
+CREATE TRIGGER IF NOT EXISTS baetyl_index_application_node_update_time AFTER UPDATE ON baetyl_index_application_node
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_index_application_node SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 应用与secret索引表
+CREATE TABLE IF NOT EXISTS baetyl_index_application_secret (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  application varchar(128) NOT NULL DEFAULT '', -- app名称
+  secret varchar(128) NOT NULL DEFAULT '', -- secret名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 更新时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_secret_idx_application ON baetyl_index_application_secret (namespace, application);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_secret_idx_secret ON baetyl_index_application_secret (namespace, secret);
+CREATE TRIGGER IF NOT EXISTS baetyl_index_application_secret_update_time AFTER UPDATE ON baetyl_index_application_secret
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_index_application_secret SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 节点影子
+CREATE TABLE IF NOT EXISTS baetyl_node_shadow (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  name varchar(128) NOT NULL DEFAULT '', -- node影子名称
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  report text, -- 上报内容
+  desire text, -- 期望内容
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_node_shadow_update_time AFTER UPDATE ON baetyl_node_shadow
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_node_shadow SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 证书表
+CREATE TABLE IF NOT EXISTS baetyl_certificate (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  cert_id varchar(128) NOT NULL DEFAULT '', -- 证书id
+  parent_id varchar(64) NOT NULL DEFAULT '', -- 上级证书id
+  type varchar(64) NOT NULL DEFAULT '', -- 证书类型（根 CA、二级 CA、节点客户端证书、模块客户端证书、模块服务端证书）
+  common_name varchar(128) NOT NULL DEFAULT '', -- 常用名
+  description varchar(256) NOT NULL DEFAULT '', -- 描述信息
+  not_before datetime NOT NULL DEFAULT '2017-01-01 00:00:00', -- 证书生效时间
+  not_after datetime NOT NULL DEFAULT '2017-01-01 00:00:00', -- 记录失效时间
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录更新时间
+  csr text, -- csr请求生成证书的信息
+  content text, -- 证书内容
+  private_key text, -- 根证书private_key信息
+  UNIQUE (cert_id)
+);
+CREATE INDEX IF NOT EXISTS baetyl_certificate_idx_parent_id ON baetyl_certificate (parent_id);
+CREATE TRIGGER IF NOT EXISTS baetyl_certificate_update_time AFTER UPDATE ON baetyl_certificate
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_certificate SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- System configuration property table
+CREATE TABLE IF NOT EXISTS baetyl_property (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- primary key
+  name varchar(128) NOT NULL DEFAULT '', -- name
+  value text NOT NULL, -- value
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- create time
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- update time
+  UNIQUE (name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_property_update_time AFTER UPDATE ON baetyl_property
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_property SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
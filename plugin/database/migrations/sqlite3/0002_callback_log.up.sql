@@ -0,0 +1,16 @@
+-- 回调投递记录
+CREATE TABLE IF NOT EXISTS baetyl_callback_log (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  callback_name varchar(128) NOT NULL DEFAULT '', -- callback name
+  event varchar(64) NOT NULL DEFAULT '', -- 触发事件
+  method varchar(36) NOT NULL DEFAULT 'GET', -- Get/Post/Put/Delete
+  url varchar(1024) NOT NULL DEFAULT '', -- url
+  request text, -- 请求内容
+  status_code int NOT NULL DEFAULT '0', -- 响应状态码
+  response text, -- 响应内容
+  error varchar(1024) NOT NULL DEFAULT '', -- 错误信息
+  attempts int NOT NULL DEFAULT '0', -- 请求次数
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 创建时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_callback_log_idx_callback ON baetyl_callback_log (namespace, callback_name, create_time);
@@ -0,0 +1,21 @@
+-- 应用灰度发布表
+CREATE TABLE IF NOT EXISTS baetyl_rollout (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  app_name varchar(128) NOT NULL DEFAULT '', -- app名称
+  app_version varchar(36) NOT NULL DEFAULT '', -- app版本
+  status varchar(16) NOT NULL DEFAULT '', -- 状态 running/paused/succeeded/aborted
+  strategy varchar(2048) NOT NULL DEFAULT '{}', -- 发布策略
+  progress text, -- 发布进度
+  message varchar(1024) NOT NULL DEFAULT '', -- 状态说明
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE INDEX IF NOT EXISTS baetyl_rollout_idx_status ON baetyl_rollout (status);
+CREATE TRIGGER IF NOT EXISTS baetyl_rollout_update_time AFTER UPDATE ON baetyl_rollout
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_rollout SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,21 @@
+-- API key表
+CREATE TABLE IF NOT EXISTS baetyl_api_key (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  user_id varchar(128) NOT NULL DEFAULT '', -- 用户id
+  namespaces varchar(2048) NOT NULL DEFAULT '[]', -- 可访问的命名空间
+  key_prefix varchar(16) NOT NULL DEFAULT '', -- key前缀，用于识别
+  key_hash varchar(64) NOT NULL DEFAULT '', -- key的sha256摘要
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  expire_time timestamp NULL DEFAULT NULL, -- 过期时间，为空则不过期
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (name),
+  UNIQUE (key_hash)
+);
+CREATE INDEX IF NOT EXISTS baetyl_api_key_idx_user_id ON baetyl_api_key (user_id);
+CREATE TRIGGER IF NOT EXISTS baetyl_api_key_update_time AFTER UPDATE ON baetyl_api_key
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_api_key SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,16 @@
+-- 角色绑定表
+CREATE TABLE IF NOT EXISTS baetyl_role_binding (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  user_id varchar(128) NOT NULL DEFAULT '', -- 用户id
+  role varchar(32) NOT NULL DEFAULT '', -- 角色 viewer/operator/admin
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, user_id)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_role_binding_update_time AFTER UPDATE ON baetyl_role_binding
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_role_binding SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
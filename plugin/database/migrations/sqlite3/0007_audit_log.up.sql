@@ -0,0 +1,18 @@
+-- 审计日志表
+CREATE TABLE IF NOT EXISTS baetyl_audit_log (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  user_id varchar(128) NOT NULL DEFAULT '', -- 操作用户
+  request_id varchar(64) NOT NULL DEFAULT '', -- 请求id
+  method varchar(16) NOT NULL DEFAULT '', -- 请求方法
+  path varchar(512) NOT NULL DEFAULT '', -- 请求路径
+  resource varchar(64) NOT NULL DEFAULT '', -- 资源类型
+  name varchar(128) NOT NULL DEFAULT '', -- 资源名称
+  operation varchar(32) NOT NULL DEFAULT '', -- 操作 create/update/delete等
+  status int NOT NULL DEFAULT '0', -- http状态码
+  code varchar(64) NOT NULL DEFAULT '', -- 错误码，成功为空
+  diff text, -- 脱敏后的变更内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 创建时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_audit_log_idx_namespace_time ON baetyl_audit_log (namespace, create_time);
+CREATE INDEX IF NOT EXISTS baetyl_audit_log_idx_user_id ON baetyl_audit_log (user_id);
@@ -0,0 +1,16 @@
+-- 节点分组表
+CREATE TABLE IF NOT EXISTS baetyl_node_group (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  selector varchar(2048) NOT NULL DEFAULT '', -- 动态分组的标签选择器，为空则为静态分组
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_node_group_update_time AFTER UPDATE ON baetyl_node_group
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_node_group SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,82 @@
+-- 命名空间表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_namespace (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  name varchar(64) NOT NULL DEFAULT '', -- 名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  UNIQUE (name)
+);
+
+-- 节点表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_node (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_node_update_time AFTER UPDATE ON baetyl_node
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_node SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 应用表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_application (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_application_update_time AFTER UPDATE ON baetyl_application
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_application SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 配置表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_configuration (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_configuration_update_time AFTER UPDATE ON baetyl_configuration
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_configuration SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 密文表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_secret (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_secret_update_time AFTER UPDATE ON baetyl_secret
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_secret SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 资源版本序列，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_resource_version (
+  id int NOT NULL, -- 主键
+  version bigint NOT NULL DEFAULT '0', -- 资源的最新版本
+  PRIMARY KEY (id)
+);
@@ -0,0 +1,7 @@
+-- 副本间的锁
+CREATE TABLE IF NOT EXISTS baetyl_lock (
+  name varchar(64) NOT NULL DEFAULT '', -- 锁名称
+  owner varchar(128) NOT NULL DEFAULT '', -- 持有者
+  expire_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 过期时间
+  PRIMARY KEY (name)
+);
@@ -0,0 +1,273 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/jinzhu/copier"
+	"github.com/jmoiron/sqlx"
+	"k8s.io/apimachinery/pkg/fields"
+	kl "k8s.io/apimachinery/pkg/labels"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+// the tables of the resources of the model storage
+const (
+	tableNamespace     = "baetyl_namespace"
+	tableNode          = "baetyl_node"
+	tableApplication   = "baetyl_application"
+	tableConfiguration = "baetyl_configuration"
+	tableSecret        = "baetyl_secret"
+)
+
+// modelStorage the model storage on the database without kubernetes,
+// the resources are stored as json contents and versioned by a sequence increased on every change
+type modelStorage struct {
+	*dbStorage
+}
+
+func init() {
+	plugin.RegisterFactory("sql", NewModelStorage)
+}
+
+// NewModelStorage NewModelStorage
+func NewModelStorage() (plugin.Plugin, error) {
+	d, err := New()
+	if err != nil {
+		return nil, err
+	}
+	return &modelStorage{dbStorage: d.(*dbStorage)}, nil
+}
+
+// IsLabelMatch IsLabelMatch
+func (m *modelStorage) IsLabelMatch(labelSelector string, labels map[string]string) (bool, error) {
+	selector, err := kl.Parse(labelSelector)
+	if err != nil {
+		return false, err
+	}
+	labelSet := kl.Set{}
+	copier.Copy(&labelSet, &labels)
+	return selector.Matches(labelSet), nil
+}
+
+func (m *modelStorage) getResource(table, namespace, name string) (*entities.Resource, error) {
+	return m.getResourceTx(nil, table, namespace, name)
+}
+
+// createResource inserts the resource with a new version and returns the resource inserted
+func (m *modelStorage) createResource(table, kind string, resource *entities.Resource) (*entities.Resource, error) {
+	var res *entities.Resource
+	err := m.Transact(func(tx *sqlx.Tx) error {
+		old, err := m.getResourceTx(tx, table, resource.Namespace, resource.Name)
+		if err != nil {
+			return err
+		}
+		if old != nil {
+			return common.Error(common.ErrResourceConflict,
+				common.Field("type", kind),
+				common.Field("name", resource.Name))
+		}
+		version, err := m.nextVersionTx(tx)
+		if err != nil {
+			return err
+		}
+		insertSQL := fmt.Sprintf(`
+INSERT INTO %s (namespace, name, version, content)
+VALUES (?, ?, ?, ?)
+`, table)
+		if _, err = m.exec(tx, insertSQL, resource.Namespace, resource.Name, version, resource.Content); err != nil {
+			return err
+		}
+		res, err = m.getResourceTx(tx, table, resource.Namespace, resource.Name)
+		return err
+	})
+	return res, err
+}
+
+// updateResource updates the resource with a new version and returns the resource updated,
+// the update fails if the version of the resource is set and not the latest
+func (m *modelStorage) updateResource(table, kind string, resource *entities.Resource) (*entities.Resource, error) {
+	var res *entities.Resource
+	err := m.Transact(func(tx *sqlx.Tx) error {
+		old, err := m.getResourceTx(tx, table, resource.Namespace, resource.Name)
+		if err != nil {
+			return err
+		}
+		if old == nil {
+			return resourceNotFound(kind, resource.Namespace, resource.Name)
+		}
+		if resource.Version != "" && resource.Version != old.Version {
+			return resourceVersionConflict(kind, resource.Name, resource.Version)
+		}
+		version, err := m.nextVersionTx(tx)
+		if err != nil {
+			return err
+		}
+		updateSQL := fmt.Sprintf(`
+UPDATE %s SET version=?, content=?, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=? AND version=?
+`, table)
+		result, err := m.exec(tx, updateSQL, version, resource.Content, resource.Namespace, resource.Name, old.Version)
+		if err != nil {
+			return err
+		}
+		if num, err := result.RowsAffected(); err != nil {
+			return err
+		} else if num == 0 {
+			return resourceVersionConflict(kind, resource.Name, old.Version)
+		}
+		res, err = m.getResourceTx(tx, table, resource.Namespace, resource.Name)
+		return err
+	})
+	return res, err
+}
+
+func (m *modelStorage) deleteResource(table, kind, namespace, name string) error {
+	deleteSQL := fmt.Sprintf(`
+DELETE FROM %s WHERE namespace=? AND name=?
+`, table)
+	result, err := m.exec(nil, deleteSQL, namespace, name)
+	if err != nil {
+		return err
+	}
+	if num, err := result.RowsAffected(); err != nil {
+		return err
+	} else if num == 0 {
+		return resourceNotFound(kind, namespace, name)
+	}
+	return nil
+}
+
+// listResource lists the resources matched by the selectors of the list options in the order of the names,
+// at most limit resources are returned and the continue token is set if there are more resources
+func (m *modelStorage) listResource(table, namespace string, listOptions *models.ListOptions) ([]entities.Resource, error) {
+	labelSelector, err := kl.Parse(listOptions.LabelSelector)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	fieldSelector, err := fields.ParseSelector(listOptions.FieldSelector)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	after, err := base64.RawURLEncoding.DecodeString(listOptions.Continue)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the continue token is invalid"))
+	}
+
+	resources := []entities.Resource{}
+	next := string(after)
+	for {
+		batch, err := m.listResourceTx(nil, table, namespace, next, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			ok, err := matchResource(&batch[i], labelSelector, fieldSelector)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			if listOptions.Limit > 0 && int64(len(resources)) == listOptions.Limit {
+				last := resources[len(resources)-1].Name
+				listOptions.Continue = base64.RawURLEncoding.EncodeToString([]byte(last))
+				return resources, nil
+			}
+			resources = append(resources, batch[i])
+		}
+		if len(batch) < batchSize {
+			break
+		}
+		next = batch[len(batch)-1].Name
+	}
+	listOptions.Continue = ""
+	return resources, nil
+}
+
+func (m *modelStorage) getResourceTx(tx *sqlx.Tx, table, namespace, name string) (*entities.Resource, error) {
+	selectSQL := fmt.Sprintf(`
+SELECT id, namespace, name, version, content, create_time, update_time
+FROM %s WHERE namespace=? AND name=? LIMIT 1
+`, table)
+	var resources []entities.Resource
+	if err := m.query(tx, selectSQL, &resources, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(resources) > 0 {
+		return &resources[0], nil
+	}
+	return nil, nil
+}
+
+func (m *modelStorage) listResourceTx(tx *sqlx.Tx, table, namespace, after string, limit int) ([]entities.Resource, error) {
+	selectSQL := fmt.Sprintf(`
+SELECT id, namespace, name, version, content, create_time, update_time
+FROM %s WHERE namespace=? AND name>? ORDER BY name LIMIT ?
+`, table)
+	var resources []entities.Resource
+	if err := m.query(tx, selectSQL, &resources, namespace, after, limit); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// nextVersionTx increases the sequence shared by all resources, so the versions of a resource always increase
+// even if the resource is deleted and created again
+func (m *modelStorage) nextVersionTx(tx *sqlx.Tx) (string, error) {
+	upsertSQL := m.dialect.upsert("baetyl_resource_version", []string{"id"}, []string{"id", "version"},
+		"version=baetyl_resource_version.version+1")
+	if _, err := m.exec(tx, upsertSQL, 1, 1); err != nil {
+		return "", err
+	}
+	selectSQL := `
+SELECT version FROM baetyl_resource_version WHERE id=1
+`
+	var res []struct {
+		Version int64 `db:"version"`
+	}
+	if err := m.query(tx, selectSQL, &res); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(res[0].Version, 10), nil
+}
+
+// matchResource matches the labels in the content of the resource and the fields metadata.name and metadata.namespace
+func matchResource(resource *entities.Resource, labelSelector kl.Selector, fieldSelector fields.Selector) (bool, error) {
+	if !fieldSelector.Matches(fields.Set{
+		"metadata.name":      resource.Name,
+		"metadata.namespace": resource.Namespace,
+	}) {
+		return false, nil
+	}
+	if labelSelector.Empty() {
+		return true, nil
+	}
+	var meta struct {
+		Labels map[string]string `json:"labels"`
+	}
+	if err := json.Unmarshal([]byte(resource.Content), &meta); err != nil {
+		return false, err
+	}
+	return labelSelector.Matches(kl.Set(meta.Labels)), nil
+}
+
+func resourceNotFound(kind, namespace, name string) error {
+	return common.Error(common.ErrResourceNotFound,
+		common.Field("type", kind),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func resourceVersionConflict(kind, name, version string) error {
+	return common.Error(common.ErrResourceVersionConflict,
+		common.Field("type", kind),
+		common.Field("name", name),
+		common.Field("version", version))
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+const kindApplication = "application"
+
+// GetApplication the latest version is returned whatever the version is, the same as the kube storage,
+// the history versions are stored in baetyl_application_history
+func (m *modelStorage) GetApplication(namespace, name, version string) (*specV1.Application, error) {
+	res, err := m.getResource(tableApplication, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, resourceNotFound(kindApplication, namespace, name)
+	}
+	return toAppModel(res)
+}
+
+func (m *modelStorage) CreateApplication(namespace string, application *specV1.Application) (*specV1.Application, error) {
+	app, err := fromAppModel(namespace, application)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.createResource(tableApplication, kindApplication, app)
+	if err != nil {
+		return nil, err
+	}
+	return toAppModel(res)
+}
+
+func (m *modelStorage) UpdateApplication(namespace string, application *specV1.Application) (*specV1.Application, error) {
+	app, err := fromAppModel(namespace, application)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.updateResource(tableApplication, kindApplication, app)
+	if err != nil {
+		return nil, err
+	}
+	return toAppModel(res)
+}
+
+func (m *modelStorage) DeleteApplication(namespace, name string) error {
+	return m.deleteResource(tableApplication, kindApplication, namespace, name)
+}
+
+func (m *modelStorage) ListApplication(namespace string, listOptions *models.ListOptions) (*models.ApplicationList, error) {
+	if listOptions == nil {
+		listOptions = &models.ListOptions{}
+	}
+	list, err := m.listResource(tableApplication, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.ApplicationList{
+		Total:       len(list),
+		ListOptions: listOptions,
+		Items:       make([]models.AppItem, 0, len(list)),
+	}
+	for i := range list {
+		app, err := toAppModel(&list[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, models.AppItem{
+			Name:              app.Name,
+			Type:              app.Type,
+			Namespace:         app.Namespace,
+			Version:           app.Version,
+			Labels:            app.Labels,
+			Selector:          app.Selector,
+			CreationTimestamp: app.CreationTimestamp,
+			Description:       app.Description,
+			System:            app.System,
+		})
+	}
+	return res, nil
+}
+
+func fromAppModel(namespace string, application *specV1.Application) (*entities.Resource, error) {
+	app := *application
+	app.Namespace = namespace
+	return entities.FromResourceModel(namespace, app.Name, app.Version, &app)
+}
+
+func toAppModel(resource *entities.Resource) (*specV1.Application, error) {
+	app := new(specV1.Application)
+	if err := entities.ToResourceModel(resource, app); err != nil {
+		return nil, err
+	}
+	app.Namespace, app.Name, app.Version = resource.Namespace, resource.Name, resource.Version
+	app.CreationTimestamp = resource.CreateTime.UTC()
+	return app, nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+const kindConfiguration = "config"
+
+// GetConfig the latest version is returned whatever the version is, the same as the kube storage
+func (m *modelStorage) GetConfig(namespace, name, version string) (*specV1.Configuration, error) {
+	res, err := m.getResource(tableConfiguration, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, resourceNotFound(kindConfiguration, namespace, name)
+	}
+	return toConfigurationModel(res)
+}
+
+func (m *modelStorage) CreateConfig(namespace string, config *specV1.Configuration) (*specV1.Configuration, error) {
+	cfg, err := fromConfigurationModel(namespace, config)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.createResource(tableConfiguration, kindConfiguration, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return toConfigurationModel(res)
+}
+
+func (m *modelStorage) UpdateConfig(namespace string, config *specV1.Configuration) (*specV1.Configuration, error) {
+	cfg, err := fromConfigurationModel(namespace, config)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.updateResource(tableConfiguration, kindConfiguration, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return toConfigurationModel(res)
+}
+
+func (m *modelStorage) DeleteConfig(namespace, name string) error {
+	return m.deleteResource(tableConfiguration, kindConfiguration, namespace, name)
+}
+
+func (m *modelStorage) ListConfig(namespace string, listOptions *models.ListOptions) (*models.ConfigurationList, error) {
+	if listOptions == nil {
+		listOptions = &models.ListOptions{}
+	}
+	list, err := m.listResource(tableConfiguration, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.ConfigurationList{
+		Total:       len(list),
+		ListOptions: listOptions,
+		Items:       make([]specV1.Configuration, 0, len(list)),
+	}
+	for i := range list {
+		cfg, err := toConfigurationModel(&list[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, *cfg)
+	}
+	return res, nil
+}
+
+func fromConfigurationModel(namespace string, config *specV1.Configuration) (*entities.Resource, error) {
+	cfg := *config
+	cfg.Namespace = namespace
+	return entities.FromResourceModel(namespace, cfg.Name, cfg.Version, &cfg)
+}
+
+func toConfigurationModel(resource *entities.Resource) (*specV1.Configuration, error) {
+	cfg := new(specV1.Configuration)
+	if err := entities.ToResourceModel(resource, cfg); err != nil {
+		return nil, err
+	}
+	cfg.Namespace, cfg.Name, cfg.Version = resource.Namespace, resource.Name, resource.Version
+	cfg.CreationTimestamp = resource.CreateTime.UTC()
+	return cfg, nil
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func (m *modelStorage) GetNamespace(namespace string) (*models.Namespace, error) {
+	return m.GetNamespaceTx(nil, namespace)
+}
+
+func (m *modelStorage) CreateNamespace(namespace *models.Namespace) (*models.Namespace, error) {
+	var res *models.Namespace
+	err := m.Transact(func(tx *sqlx.Tx) error {
+		selectSQL := `
+SELECT name FROM baetyl_namespace WHERE name=?
+`
+		var names []string
+		if err := m.query(tx, selectSQL, &names, namespace.Name); err != nil {
+			return err
+		}
+		if len(names) > 0 {
+			return common.Error(common.ErrResourceConflict,
+				common.Field("type", "namespace"),
+				common.Field("name", namespace.Name))
+		}
+		insertSQL := `
+INSERT INTO baetyl_namespace (name) VALUES (?)
+`
+		if _, err := m.exec(tx, insertSQL, namespace.Name); err != nil {
+			return err
+		}
+		var err error
+		res, err = m.GetNamespaceTx(tx, namespace.Name)
+		return err
+	})
+	return res, err
+}
+
+func (m *modelStorage) ListNamespace() ([]models.Namespace, error) {
+	selectSQL := `
+SELECT name FROM baetyl_namespace ORDER BY name
+`
+	var names []string
+	if err := m.query(nil, selectSQL, &names); err != nil {
+		return nil, err
+	}
+	res := make([]models.Namespace, 0, len(names))
+	for _, name := range names {
+		res = append(res, models.Namespace{Name: name})
+	}
+	return res, nil
+}
+
+// DeleteNamespace deletes the namespace and all resources in the namespace
+func (m *modelStorage) DeleteNamespace(namespace *models.Namespace) error {
+	return m.Transact(func(tx *sqlx.Tx) error {
+		for _, table := range []string{tableNode, tableApplication, tableConfiguration, tableSecret} {
+			deleteSQL := fmt.Sprintf(`
+DELETE FROM %s WHERE namespace=?
+`, table)
+			if _, err := m.exec(tx, deleteSQL, namespace.Name); err != nil {
+				return err
+			}
+		}
+		deleteSQL := `
+DELETE FROM baetyl_namespace WHERE name=?
+`
+		_, err := m.exec(tx, deleteSQL, namespace.Name)
+		return err
+	})
+}
+
+func (m *modelStorage) GetNamespaceTx(tx *sqlx.Tx, namespace string) (*models.Namespace, error) {
+	selectSQL := `
+SELECT name FROM baetyl_namespace WHERE name=?
+`
+	var names []string
+	if err := m.query(tx, selectSQL, &names, namespace); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "namespace"),
+			common.Field("name", namespace))
+	}
+	return &models.Namespace{Name: names[0]}, nil
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+const kindNode = "node"
+
+func (m *modelStorage) GetNode(namespace, name string) (*specV1.Node, error) {
+	res, err := m.getResource(tableNode, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, resourceNotFound(kindNode, namespace, name)
+	}
+	return toNodeModel(res)
+}
+
+func (m *modelStorage) CreateNode(namespace string, node *specV1.Node) (*specV1.Node, error) {
+	n, err := fromNodeModel(namespace, node)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.createResource(tableNode, kindNode, n)
+	if err != nil {
+		return nil, err
+	}
+	return toNodeModel(res)
+}
+
+func (m *modelStorage) UpdateNode(namespace string, node *specV1.Node) (*specV1.Node, error) {
+	n, err := fromNodeModel(namespace, node)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.updateResource(tableNode, kindNode, n)
+	if err != nil {
+		return nil, err
+	}
+	return toNodeModel(res)
+}
+
+func (m *modelStorage) DeleteNode(namespace, name string) error {
+	return m.deleteResource(tableNode, kindNode, namespace, name)
+}
+
+func (m *modelStorage) ListNode(namespace string, listOptions *models.ListOptions) (*models.NodeList, error) {
+	if listOptions == nil {
+		listOptions = &models.ListOptions{}
+	}
+	list, err := m.listResource(tableNode, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.NodeList{
+		Total:       len(list),
+		ListOptions: listOptions,
+		Items:       make([]specV1.Node, 0, len(list)),
+	}
+	for i := range list {
+		n, err := toNodeModel(&list[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, *n)
+	}
+	return res, nil
+}
+
+// fromNodeModel the desire and report of the node are stored in the shadow
+func fromNodeModel(namespace string, node *specV1.Node) (*entities.Resource, error) {
+	n := *node
+	n.Namespace = namespace
+	n.Desire, n.Report = nil, nil
+	return entities.FromResourceModel(namespace, n.Name, n.Version, &n)
+}
+
+func toNodeModel(resource *entities.Resource) (*specV1.Node, error) {
+	n := new(specV1.Node)
+	if err := entities.ToResourceModel(resource, n); err != nil {
+		return nil, err
+	}
+	n.Namespace, n.Name, n.Version = resource.Namespace, resource.Name, resource.Version
+	n.CreationTimestamp = resource.CreateTime.UTC()
+	return n, nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+const kindSecret = "secret"
+
+// GetSecret the latest version is returned whatever the version is, the same as the kube storage
+func (m *modelStorage) GetSecret(namespace, name, version string) (*specV1.Secret, error) {
+	res, err := m.getResource(tableSecret, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, resourceNotFound(kindSecret, namespace, name)
+	}
+	return toSecretModel(res)
+}
+
+func (m *modelStorage) CreateSecret(namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
+	s, err := fromSecretModel(namespace, secret)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.createResource(tableSecret, kindSecret, s)
+	if err != nil {
+		return nil, err
+	}
+	return toSecretModel(res)
+}
+
+func (m *modelStorage) UpdateSecret(namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
+	s, err := fromSecretModel(namespace, secret)
+	if err != nil {
+		return nil, err
+	}
+	res, err := m.updateResource(tableSecret, kindSecret, s)
+	if err != nil {
+		return nil, err
+	}
+	return toSecretModel(res)
+}
+
+func (m *modelStorage) DeleteSecret(namespace, name string) error {
+	return m.deleteResource(tableSecret, kindSecret, namespace, name)
+}
+
+func (m *modelStorage) ListSecret(namespace string, listOptions *models.ListOptions) (*models.SecretList, error) {
+	if listOptions == nil {
+		listOptions = &models.ListOptions{}
+	}
+	list, err := m.listResource(tableSecret, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.SecretList{
+		Total:       len(list),
+		ListOptions: listOptions,
+		Items:       make([]specV1.Secret, 0, len(list)),
+	}
+	for i := range list {
+		s, err := toSecretModel(&list[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, *s)
+	}
+	return res, nil
+}
+
+func fromSecretModel(namespace string, secret *specV1.Secret) (*entities.Resource, error) {
+	s := *secret
+	s.Namespace = namespace
+	return entities.FromResourceModel(namespace, s.Name, s.Version, &s)
+}
+
+func toSecretModel(resource *entities.Resource) (*specV1.Secret, error) {
+	s := new(specV1.Secret)
+	if err := entities.ToResourceModel(resource, s); err != nil {
+		return nil, err
+	}
+	s.Namespace, s.Name, s.Version = resource.Namespace, resource.Name, resource.Version
+	s.CreationTimestamp = resource.CreateTime.UTC()
+	return s, nil
+}
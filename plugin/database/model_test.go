@@ -0,0 +1,291 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func MockNewModelStorage() (*modelStorage, error) {
+	db, err := MockNewDB()
+	if err != nil {
+		return nil, err
+	}
+	// the in-memory database is dropped with the connection
+	db.db.SetMaxOpenConns(1)
+	if err = MockCreateSchema(db); err != nil {
+		panic(fmt.Sprintf("create table exception: %s", err.Error()))
+	}
+	m := &modelStorage{dbStorage: db}
+	return m, nil
+}
+
+func TestModelStorageNamespace(t *testing.T) {
+	m, err := MockNewModelStorage()
+	assert.NoError(t, err)
+	var _ plugin.ModelStorage = m
+
+	_, err = m.GetNamespace("default")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	ns, err := m.CreateNamespace(&models.Namespace{Name: "default"})
+	assert.NoError(t, err)
+	assert.Equal(t, "default", ns.Name)
+	_, err = m.CreateNamespace(&models.Namespace{Name: "default"})
+	assert.Error(t, err)
+	ns, err = m.GetNamespace("default")
+	assert.NoError(t, err)
+	assert.Equal(t, "default", ns.Name)
+	_, err = m.CreateNamespace(&models.Namespace{Name: "alpha"})
+	assert.NoError(t, err)
+	list, err := m.ListNamespace()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Namespace{{Name: "alpha"}, {Name: "default"}}, list)
+
+	_, err = m.CreateConfig("default", &specV1.Configuration{Name: "c1"})
+	assert.NoError(t, err)
+	_, err = m.CreateConfig("other", &specV1.Configuration{Name: "c1"})
+	assert.NoError(t, err)
+	assert.NoError(t, m.DeleteNamespace(&models.Namespace{Name: "default"}))
+	_, err = m.GetNamespace("default")
+	assert.Error(t, err)
+	_, err = m.GetConfig("default", "c1", "")
+	assert.Error(t, err)
+	_, err = m.GetConfig("other", "c1", "")
+	assert.NoError(t, err)
+	// not found
+	assert.NoError(t, m.DeleteNamespace(&models.Namespace{Name: "default"}))
+}
+
+func TestModelStorageNode(t *testing.T) {
+	m, err := MockNewModelStorage()
+	assert.NoError(t, err)
+
+	node := &specV1.Node{
+		Name:        "n1",
+		Labels:      map[string]string{"a": "a"},
+		Annotations: map[string]string{"b": "b"},
+		Description: "desc",
+		Desire:      specV1.Desire{"apps": []interface{}{}},
+		Report:      specV1.Report{"time": "now"},
+	}
+	res, err := m.CreateNode("default", node)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", res.Namespace)
+	assert.Equal(t, "1", res.Version)
+	assert.Equal(t, node.Labels, res.Labels)
+	assert.Equal(t, node.Annotations, res.Annotations)
+	assert.Equal(t, "desc", res.Description)
+	assert.False(t, res.CreationTimestamp.IsZero())
+	// the desire and report are stored in the shadow
+	assert.Nil(t, res.Desire)
+	assert.Nil(t, res.Report)
+	_, err = m.CreateNode("default", node)
+	assert.Error(t, err)
+
+	// optimistic version
+	res.Labels["b"] = "b"
+	res, err = m.UpdateNode("default", res)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", res.Version)
+	stale := *res
+	stale.Version = "1"
+	_, err = m.UpdateNode("default", &stale)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(interface{ Code() string }).Code())
+	// the version is not checked if not set
+	stale.Version = ""
+	res, err = m.UpdateNode("default", &stale)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", res.Version)
+	_, err = m.UpdateNode("default", &specV1.Node{Name: "n0"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	res, err = m.GetNode("default", "n1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", res.Version)
+	assert.Equal(t, map[string]string{"a": "a", "b": "b"}, res.Labels)
+
+	assert.NoError(t, m.DeleteNode("default", "n1"))
+	_, err = m.GetNode("default", "n1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.Error(t, m.DeleteNode("default", "n1"))
+
+	// the version increases after the node is created again
+	res, err = m.CreateNode("default", &specV1.Node{Name: "n1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "4", res.Version)
+}
+
+func TestModelStorageList(t *testing.T) {
+	m, err := MockNewModelStorage()
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		labels := map[string]string{"index": fmt.Sprint(i)}
+		if i%2 == 0 {
+			labels["even"] = "true"
+		}
+		_, err = m.CreateNode("default", &specV1.Node{Name: fmt.Sprintf("n%d", i), Labels: labels})
+		assert.NoError(t, err)
+	}
+	_, err = m.CreateNode("other", &specV1.Node{Name: "n9"})
+	assert.NoError(t, err)
+
+	names := func(list *models.NodeList) []string {
+		var res []string
+		for _, n := range list.Items {
+			res = append(res, n.Name)
+		}
+		return res
+	}
+
+	list, err := m.ListNode("default", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, list.Total)
+	assert.Equal(t, []string{"n0", "n1", "n2", "n3", "n4"}, names(list))
+
+	list, err = m.ListNode("default", &models.ListOptions{LabelSelector: "even=true"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n0", "n2", "n4"}, names(list))
+	list, err = m.ListNode("default", &models.ListOptions{LabelSelector: "index in (1,2),!even"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, names(list))
+	list, err = m.ListNode("default", &models.ListOptions{FieldSelector: "metadata.name!=n0"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2", "n3", "n4"}, names(list))
+	_, err = m.ListNode("default", &models.ListOptions{LabelSelector: "a==="})
+	assert.Error(t, err)
+
+	// pagination
+	opts := &models.ListOptions{LabelSelector: "even=true", Limit: 2}
+	list, err = m.ListNode("default", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n0", "n2"}, names(list))
+	assert.NotEmpty(t, opts.Continue)
+	list, err = m.ListNode("default", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n4"}, names(list))
+	assert.Empty(t, opts.Continue)
+	opts = &models.ListOptions{Limit: 5}
+	list, err = m.ListNode("default", opts)
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 5)
+	assert.Empty(t, opts.Continue)
+	_, err = m.ListNode("default", &models.ListOptions{Continue: "!"})
+	assert.Error(t, err)
+
+	ok, err := m.IsLabelMatch("even=true", map[string]string{"even": "true"})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = m.IsLabelMatch("even!=true", map[string]string{"even": "true"})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, err = m.IsLabelMatch("a===", nil)
+	assert.Error(t, err)
+}
+
+func TestModelStorageApplication(t *testing.T) {
+	m, err := MockNewModelStorage()
+	assert.NoError(t, err)
+
+	app := &specV1.Application{
+		Name:     "app1",
+		Type:     common.ContainerApp,
+		Labels:   map[string]string{"a": "a"},
+		Selector: "a=a",
+		Services: []specV1.Service{{Name: "s1", Image: "image"}},
+		Volumes: []specV1.Volume{
+			{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c1", Version: "1"}}},
+		},
+		Description: "desc",
+		System:      true,
+	}
+	res, err := m.CreateApplication("default", app)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", res.Version)
+	assert.Equal(t, app.Services, res.Services)
+	assert.Equal(t, app.Volumes, res.Volumes)
+
+	res.Selector = "b=b"
+	res, err = m.UpdateApplication("default", res)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", res.Version)
+	res, err = m.GetApplication("default", "app1", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", res.Version)
+	assert.Equal(t, "b=b", res.Selector)
+
+	list, err := m.ListApplication("default", &models.ListOptions{LabelSelector: "a=a"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, models.AppItem{
+		Name:              "app1",
+		Type:              common.ContainerApp,
+		Labels:            app.Labels,
+		Selector:          "b=b",
+		Version:           "2",
+		Namespace:         "default",
+		CreationTimestamp: res.CreationTimestamp,
+		Description:       "desc",
+		System:            true,
+	}, list.Items[0])
+
+	assert.NoError(t, m.DeleteApplication("default", "app1"))
+	_, err = m.GetApplication("default", "app1", "")
+	assert.Error(t, err)
+}
+
+func TestModelStorageConfigAndSecret(t *testing.T) {
+	m, err := MockNewModelStorage()
+	assert.NoError(t, err)
+
+	cfg, err := m.CreateConfig("default", &specV1.Configuration{Name: "c1", Data: map[string]string{"k": "v"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", cfg.Version)
+	cfg.Data["k"] = "v2"
+	cfg, err = m.UpdateConfig("default", cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", cfg.Version)
+	cfg, err = m.GetConfig("default", "c1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", cfg.Data["k"])
+	configs, err := m.ListConfig("default", &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, configs.Total)
+	assert.NoError(t, m.DeleteConfig("default", "c1"))
+	_, err = m.GetConfig("default", "c1", "")
+	assert.Error(t, err)
+
+	secret := &specV1.Secret{
+		Name:   "s1",
+		Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry},
+		Data:   map[string][]byte{"password": []byte("p")},
+	}
+	s, err := m.CreateSecret("default", secret)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", s.Version)
+	assert.Equal(t, secret.Data, s.Data)
+	s.Data["password"] = []byte("p2")
+	s, err = m.UpdateSecret("default", s)
+	assert.NoError(t, err)
+	assert.Equal(t, "4", s.Version)
+	secrets, err := m.ListSecret("default", &models.ListOptions{LabelSelector: specV1.SecretLabel + "=" + specV1.SecretConfig})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, secrets.Total)
+	secrets, err = m.ListSecret("default", &models.ListOptions{LabelSelector: specV1.SecretLabel + "=" + specV1.SecretRegistry})
+	assert.NoError(t, err)
+	assert.Equal(t, "p2", string(secrets.Items[0].Data["password"]))
+	assert.NoError(t, m.DeleteSecret("default", "s1"))
+	_, err = m.GetSecret("default", "s1", "")
+	assert.Error(t, err)
+}
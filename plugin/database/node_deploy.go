@@ -0,0 +1,97 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func (d *dbStorage) CreateNodeDeployHistory(histories []models.NodeDeployHistory) (sql.Result, error) {
+	return d.CreateNodeDeployHistoryTx(nil, histories)
+}
+
+func (d *dbStorage) ListNodeDeployHistory(ns, nodeName string, filter *models.NodeDeployFilter) ([]models.NodeDeployHistory, error) {
+	return d.ListNodeDeployHistoryTx(nil, ns, nodeName, filter)
+}
+
+func (d *dbStorage) CountNodeDeployHistory(ns, nodeName string, filter *models.NodeDeployFilter) (int, error) {
+	return d.CountNodeDeployHistoryTx(nil, ns, nodeName, filter)
+}
+
+func (d *dbStorage) DeleteNodeDeployHistory(ns, nodeName string) (sql.Result, error) {
+	return d.DeleteNodeDeployHistoryTx(nil, ns, nodeName)
+}
+
+func (d *dbStorage) CreateNodeDeployHistoryTx(tx *sqlx.Tx, histories []models.NodeDeployHistory) (sql.Result, error) {
+	insertSQL := `
+INSERT INTO baetyl_node_deploy_history (
+namespace, node_name, app_name, old_version, 
+new_version, trigger_type, create_time)
+VALUES 
+`
+	vals := []interface{}{}
+	for _, h := range histories {
+		insertSQL += "(?,?,?,?,?,?,?),"
+		vals = append(vals, h.Namespace, h.NodeName, h.AppName, h.OldVersion,
+			h.NewVersion, h.Trigger, h.CreateTime)
+	}
+	return d.exec(tx, insertSQL[0:len(insertSQL)-1], vals...)
+}
+
+func (d *dbStorage) ListNodeDeployHistoryTx(tx *sqlx.Tx, ns, nodeName string, filter *models.NodeDeployFilter) ([]models.NodeDeployHistory, error) {
+	selectSQL := `
+SELECT id, namespace, node_name, app_name, 
+old_version, new_version, trigger_type, create_time 
+FROM baetyl_node_deploy_history 
+WHERE namespace=? AND node_name=? AND create_time>=? AND create_time<=? ORDER BY id DESC 
+`
+	start, end := deployTimeRange(filter)
+	args := []interface{}{ns, nodeName, start, end}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	var histories []models.NodeDeployHistory
+	if err := d.query(tx, selectSQL, &histories, args...); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+func (d *dbStorage) CountNodeDeployHistoryTx(tx *sqlx.Tx, ns, nodeName string, filter *models.NodeDeployFilter) (int, error) {
+	selectSQL := `
+SELECT count(id) AS count
+FROM baetyl_node_deploy_history 
+WHERE namespace=? AND node_name=? AND create_time>=? AND create_time<=?
+`
+	var res []struct {
+		Count int `db:"count"`
+	}
+	start, end := deployTimeRange(filter)
+	if err := d.query(tx, selectSQL, &res, ns, nodeName, start, end); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
+func (d *dbStorage) DeleteNodeDeployHistoryTx(tx *sqlx.Tx, ns, nodeName string) (sql.Result, error) {
+	deleteSQL := `
+DELETE FROM baetyl_node_deploy_history WHERE namespace=? AND node_name=?
+`
+	return d.exec(tx, deleteSQL, ns, nodeName)
+}
+
+// deployTimeRange returns the time range of the filter, the unset bounds are open
+func deployTimeRange(filter *models.NodeDeployFilter) (time.Time, time.Time) {
+	start, end := time.Unix(0, 0).UTC(), time.Unix(1<<33, 0).UTC()
+	if filter.Start > 0 {
+		start = time.Unix(filter.Start, 0).UTC()
+	}
+	if filter.End > 0 {
+		end = time.Unix(filter.End, 0).UTC()
+	}
+	return start, end
+}
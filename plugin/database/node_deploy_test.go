@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	nodeDeployTables = []string{
+		`
+CREATE TABLE baetyl_node_deploy_history
+(
+    id           integer      PRIMARY KEY AUTOINCREMENT,
+    namespace    varchar(64)  NOT NULL DEFAULT '',
+    node_name    varchar(128) NOT NULL DEFAULT '',
+    app_name     varchar(128) NOT NULL DEFAULT '',
+    old_version  varchar(36)  NOT NULL DEFAULT '',
+    new_version  varchar(36)  NOT NULL DEFAULT '',
+    trigger_type varchar(32)  NOT NULL DEFAULT '',
+    create_time  timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *dbStorage) MockCreateNodeDeployTable() {
+	for _, sql := range nodeDeployTables {
+		_, err := d.exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestNodeDeployHistory(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateNodeDeployTable()
+
+	base := time.Unix(1600000000, 0).UTC()
+	var histories []models.NodeDeployHistory
+	for i := 0; i < 3; i++ {
+		histories = append(histories, models.NodeDeployHistory{
+			Namespace:  "default",
+			NodeName:   "node01",
+			AppName:    "app01",
+			OldVersion: fmt.Sprint(i),
+			NewVersion: fmt.Sprint(i + 1),
+			Trigger:    models.DeployTriggerApp,
+			CreateTime: base.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	histories = append(histories, models.NodeDeployHistory{
+		Namespace:  "default",
+		NodeName:   "node02",
+		AppName:    "app01",
+		NewVersion: "1",
+		Trigger:    models.DeployTriggerLabel,
+		CreateTime: base,
+	})
+	res, err := db.CreateNodeDeployHistory(histories)
+	assert.NoError(t, err)
+	num, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), num)
+
+	filter := &models.NodeDeployFilter{Filter: models.Filter{PageNo: 1, PageSize: 2}}
+	list, err := db.ListNodeDeployHistory("default", "node01", filter)
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "3", list[0].NewVersion)
+	assert.Equal(t, "2", list[0].OldVersion)
+	assert.Equal(t, models.DeployTriggerApp, list[0].Trigger)
+	count, err := db.CountNodeDeployHistory("default", "node01", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// time range
+	filter = &models.NodeDeployFilter{
+		Start: base.Add(30 * time.Minute).Unix(),
+		End:   base.Add(90 * time.Minute).Unix(),
+	}
+	list, err = db.ListNodeDeployHistory("default", "node01", filter)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "2", list[0].NewVersion)
+	count, err = db.CountNodeDeployHistory("default", "node01", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	res, err = db.DeleteNodeDeployHistory("default", "node01")
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), num)
+	count, err = db.CountNodeDeployHistory("default", "node02", &models.NodeDeployFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
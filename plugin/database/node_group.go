@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func (d *dbStorage) GetNodeGroup(namespace, name string) (*models.NodeGroup, error) {
+	return d.GetNodeGroupTx(nil, namespace, name)
+}
+
+func (d *dbStorage) ListNodeGroup(namespace string, filter *models.Filter) ([]models.NodeGroup, error) {
+	return d.ListNodeGroupTx(nil, namespace, filter)
+}
+
+func (d *dbStorage) CountNodeGroup(namespace, name string) (int, error) {
+	return d.CountNodeGroupTx(nil, namespace, name)
+}
+
+func (d *dbStorage) CreateNodeGroup(group *models.NodeGroup) (sql.Result, error) {
+	return d.CreateNodeGroupTx(nil, group)
+}
+
+func (d *dbStorage) UpdateNodeGroup(group *models.NodeGroup) (sql.Result, error) {
+	return d.UpdateNodeGroupTx(nil, group)
+}
+
+func (d *dbStorage) DeleteNodeGroup(namespace, name string) (sql.Result, error) {
+	return d.DeleteNodeGroupTx(nil, namespace, name)
+}
+
+func (d *dbStorage) GetNodeGroupTx(tx *sqlx.Tx, namespace, name string) (*models.NodeGroup, error) {
+	selectSQL := `
+SELECT namespace, name, description, selector, 
+create_time, update_time 
+FROM baetyl_node_group 
+WHERE namespace=? AND name=? LIMIT 1
+`
+	var groups []models.NodeGroup
+	if err := d.query(tx, selectSQL, &groups, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(groups) > 0 {
+		return &groups[0], nil
+	}
+	return nil, nil
+}
+
+func (d *dbStorage) ListNodeGroupTx(tx *sqlx.Tx, namespace string, filter *models.Filter) ([]models.NodeGroup, error) {
+	selectSQL := `
+SELECT namespace, name, description, selector, 
+create_time, update_time 
+FROM baetyl_node_group 
+WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC 
+`
+	groups := []models.NodeGroup{}
+	args := []interface{}{namespace, filter.GetFuzzyName()}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	if err := d.query(tx, selectSQL, &groups, args...); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (d *dbStorage) CountNodeGroupTx(tx *sqlx.Tx, namespace, name string) (int, error) {
+	selectSQL := `
+SELECT count(name) AS count
+FROM baetyl_node_group WHERE namespace=? AND name LIKE ?
+`
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(tx, selectSQL, &res, namespace, name); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
+func (d *dbStorage) CreateNodeGroupTx(tx *sqlx.Tx, group *models.NodeGroup) (sql.Result, error) {
+	insertSQL := `
+INSERT INTO baetyl_node_group (namespace, name, description, selector) 
+VALUES (?,?,?,?)
+`
+	return d.exec(tx, insertSQL, group.Namespace, group.Name, group.Description, group.Selector)
+}
+
+func (d *dbStorage) UpdateNodeGroupTx(tx *sqlx.Tx, group *models.NodeGroup) (sql.Result, error) {
+	updateSQL := `
+UPDATE baetyl_node_group SET description=?, selector=? 
+WHERE namespace=? AND name=?
+`
+	return d.exec(tx, updateSQL, group.Description, group.Selector, group.Namespace, group.Name)
+}
+
+func (d *dbStorage) DeleteNodeGroupTx(tx *sqlx.Tx, namespace, name string) (sql.Result, error) {
+	deleteSQL := `
+DELETE FROM baetyl_node_group WHERE namespace=? AND name=?
+`
+	return d.exec(tx, deleteSQL, namespace, name)
+}
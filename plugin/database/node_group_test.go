@@ -0,0 +1,114 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	nodeGroupTables = []string{
+		`
+CREATE TABLE baetyl_node_group
+(
+    namespace   varchar(64)   NOT NULL DEFAULT '',
+    name        varchar(128)  NOT NULL DEFAULT '',
+    description varchar(1024) NOT NULL DEFAULT '',
+    selector    varchar(2048) NOT NULL DEFAULT '',
+    create_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (namespace, name)
+);
+`,
+	}
+)
+
+func (d *dbStorage) MockCreateNodeGroupTable() {
+	for _, sql := range nodeGroupTables {
+		_, err := d.exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestNodeGroup(t *testing.T) {
+	group := &models.NodeGroup{
+		Namespace:   "default",
+		Name:        "edge",
+		Description: "desc",
+	}
+
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateNodeGroupTable()
+
+	res, err := db.CreateNodeGroup(group)
+	assert.NoError(t, err)
+	num, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+
+	_, err = db.CreateNodeGroup(group)
+	assert.Error(t, err)
+
+	other := &models.NodeGroup{Namespace: "default", Name: "gateway", Selector: "type=gateway"}
+	_, err = db.CreateNodeGroup(other)
+	assert.NoError(t, err)
+
+	resGroup, err := db.GetNodeGroup(group.Namespace, group.Name)
+	assert.NoError(t, err)
+	checkNodeGroup(t, group, resGroup)
+
+	resGroup, err = db.GetNodeGroup(group.Namespace, "none")
+	assert.NoError(t, err)
+	assert.Nil(t, resGroup)
+
+	filter := &models.Filter{PageNo: 1, PageSize: 10}
+	groups, err := db.ListNodeGroup(group.Namespace, filter)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+	count, err := db.CountNodeGroup(group.Namespace, filter.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	filter = &models.Filter{Name: "gate"}
+	groups, err = db.ListNodeGroup(group.Namespace, filter)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+	checkNodeGroup(t, other, &groups[0])
+
+	other.Description = "updated"
+	other.Selector = "type in (gateway, router)"
+	res, err = db.UpdateNodeGroup(other)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	resGroup, err = db.GetNodeGroup(other.Namespace, other.Name)
+	assert.NoError(t, err)
+	checkNodeGroup(t, other, resGroup)
+
+	res, err = db.DeleteNodeGroup(group.Namespace, group.Name)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	resGroup, err = db.GetNodeGroup(group.Namespace, group.Name)
+	assert.NoError(t, err)
+	assert.Nil(t, resGroup)
+}
+
+func checkNodeGroup(t *testing.T, expect, actual *models.NodeGroup) {
+	assert.Equal(t, expect.Namespace, actual.Namespace)
+	assert.Equal(t, expect.Name, actual.Name)
+	assert.Equal(t, expect.Description, actual.Description)
+	assert.Equal(t, expect.Selector, actual.Selector)
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"os"
+	"time"
 
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
@@ -13,7 +14,7 @@ cert_id, parent_id, type, common_name,
 description, csr, content, private_key, not_before, not_after) 
 VALUES (?,?,?,?,?,?,?,?,?,?)
 `
-	_, err := d.db.Exec(insertSQL,
+	_, err := d.exec(nil, insertSQL,
 		cert.CertId, cert.ParentId, cert.Type,
 		cert.CommonName, cert.Description, cert.Csr,
 		cert.Content, cert.PrivateKey, cert.NotBefore, cert.NotAfter)
@@ -24,7 +25,7 @@ func (d dbStorage) DeleteCert(certId string) error {
 	deleteSQL := `
 DELETE FROM baetyl_certificate where cert_id=?
 `
-	_, err := d.db.Exec(deleteSQL, certId)
+	_, err := d.exec(nil, deleteSQL, certId)
 	return err
 }
 
@@ -35,7 +36,7 @@ common_name=?,description=?,csr=?,content=?,private_key=?,
 not_before=?, not_after=? 
 WHERE cert_id=?
 `
-	_, err := d.db.Exec(updateSQL,
+	_, err := d.exec(nil, updateSQL,
 		cert.ParentId, cert.Type, cert.CommonName, cert.Description, cert.Csr,
 		cert.Content, cert.PrivateKey, cert.NotBefore, cert.NotAfter, cert.CertId)
 	return err
@@ -46,10 +47,10 @@ func (d dbStorage) GetCert(certId string) (*plugin.Cert, error) {
 SELECT cert_id, parent_id, type, common_name, 
 description, csr, content, private_key, not_before, not_after
 FROM baetyl_certificate 
-WHERE cert_id=? LIMIT 0,1
+WHERE cert_id=? LIMIT 1
 `
 	var cert []plugin.Cert
-	if err := d.db.Select(&cert, selectSQL, certId); err != nil {
+	if err := d.query(nil, selectSQL, &cert, certId); err != nil {
 		return nil, err
 	}
 	if len(cert) > 0 {
@@ -67,8 +68,36 @@ WHERE parent_id=?
 	var res []struct {
 		Count int `db:"count"`
 	}
-	if err := d.db.Select(&res, selectSQL, parentId); err != nil {
+	if err := d.query(nil, selectSQL, &res, parentId); err != nil {
 		return 0, err
 	}
 	return res[0].Count, nil
 }
+
+func (d dbStorage) ListCertByNotAfter(before time.Time) ([]plugin.Cert, error) {
+	selectSQL := `
+SELECT cert_id, parent_id, type, common_name, 
+description, csr, content, private_key, not_before, not_after
+FROM baetyl_certificate 
+WHERE not_after<? ORDER BY not_after
+`
+	var certs []plugin.Cert
+	if err := d.query(nil, selectSQL, &certs, before.UTC()); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+func (d dbStorage) ListCertWithPrivateKey() ([]plugin.Cert, error) {
+	selectSQL := `
+SELECT cert_id, parent_id, type, common_name, 
+description, csr, content, private_key, not_before, not_after
+FROM baetyl_certificate 
+WHERE private_key<>'' ORDER BY cert_id
+`
+	var certs []plugin.Cert
+	if err := d.query(nil, selectSQL, &certs); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
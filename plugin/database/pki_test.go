@@ -91,6 +91,31 @@ func TestCertificate(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, c1)
 
+	later := genCertificate()
+	later.CertId = "789"
+	later.NotAfter = timestamp.Add(time.Hour)
+	assert.NoError(t, db.CreateCert(*later))
+	certs, err := db.ListCertByNotAfter(timestamp)
+	assert.NoError(t, err)
+	assert.Len(t, certs, 0)
+	certs, err = db.ListCertByNotAfter(timestamp.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, certs, 1)
+	checkCertificate(t, certificate, &certs[0])
+	certs, err = db.ListCertByNotAfter(timestamp.Add(2 * time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, certs, 2)
+	assert.Equal(t, later.CertId, certs[1].CertId)
+
+	// the certs without private keys are not listed
+	later.PrivateKey = ""
+	assert.NoError(t, db.UpdateCert(*later))
+	certs, err = db.ListCertWithPrivateKey()
+	assert.NoError(t, err)
+	assert.Len(t, certs, 1)
+	checkCertificate(t, certificate, &certs[0])
+	assert.NoError(t, db.DeleteCert(later.CertId))
+
 	err = db.DeleteCert(certificate.CertId)
 	assert.NoError(t, err)
 
@@ -63,8 +63,8 @@ func (d *dbStorage) ListProperty(filter *models.Filter) ([]models.Property, erro
 	`
 	args := []interface{}{filter.GetFuzzyName()}
 	if filter.GetLimitNumber() > 0 {
-		selectSQL = selectSQL + "LIMIT ?,?"
-		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
 	}
 
 	if err := d.query(nil, selectSQL, &cs, args...); err != nil {
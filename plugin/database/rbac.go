@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func (d *dbStorage) GetRoleBinding(namespace, user string) (*models.RoleBinding, error) {
+	return d.GetRoleBindingTx(nil, namespace, user)
+}
+
+func (d *dbStorage) ListRoleBinding(namespace string, filter *models.Filter) ([]models.RoleBinding, error) {
+	return d.ListRoleBindingTx(nil, namespace, filter)
+}
+
+func (d *dbStorage) CountRoleBinding(namespace, user string) (int, error) {
+	return d.CountRoleBindingTx(nil, namespace, user)
+}
+
+func (d *dbStorage) CreateRoleBinding(binding *models.RoleBinding) (sql.Result, error) {
+	return d.CreateRoleBindingTx(nil, binding)
+}
+
+func (d *dbStorage) UpdateRoleBinding(binding *models.RoleBinding) (sql.Result, error) {
+	return d.UpdateRoleBindingTx(nil, binding)
+}
+
+func (d *dbStorage) DeleteRoleBinding(namespace, user string) (sql.Result, error) {
+	return d.DeleteRoleBindingTx(nil, namespace, user)
+}
+
+func (d *dbStorage) GetRoleBindingTx(tx *sqlx.Tx, namespace, user string) (*models.RoleBinding, error) {
+	selectSQL := `
+SELECT namespace, user_id, role, description, 
+create_time, update_time 
+FROM baetyl_role_binding 
+WHERE namespace=? AND user_id=? LIMIT 1
+`
+	var bindings []models.RoleBinding
+	if err := d.query(tx, selectSQL, &bindings, namespace, user); err != nil {
+		return nil, err
+	}
+	if len(bindings) > 0 {
+		return &bindings[0], nil
+	}
+	return nil, nil
+}
+
+func (d *dbStorage) ListRoleBindingTx(tx *sqlx.Tx, namespace string, filter *models.Filter) ([]models.RoleBinding, error) {
+	selectSQL := `
+SELECT namespace, user_id, role, description, 
+create_time, update_time 
+FROM baetyl_role_binding 
+WHERE namespace=? AND user_id LIKE ? ORDER BY create_time DESC 
+`
+	bindings := []models.RoleBinding{}
+	args := []interface{}{namespace, filter.GetFuzzyName()}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	if err := d.query(tx, selectSQL, &bindings, args...); err != nil {
+		return nil, err
+	}
+	return bindings, nil
+}
+
+func (d *dbStorage) CountRoleBindingTx(tx *sqlx.Tx, namespace, user string) (int, error) {
+	selectSQL := `
+SELECT count(user_id) AS count
+FROM baetyl_role_binding WHERE namespace=? AND user_id LIKE ?
+`
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(tx, selectSQL, &res, namespace, user); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
+func (d *dbStorage) CreateRoleBindingTx(tx *sqlx.Tx, binding *models.RoleBinding) (sql.Result, error) {
+	insertSQL := `
+INSERT INTO baetyl_role_binding (namespace, user_id, role, description) 
+VALUES (?,?,?,?)
+`
+	return d.exec(tx, insertSQL, binding.Namespace, binding.User, binding.Role, binding.Description)
+}
+
+func (d *dbStorage) UpdateRoleBindingTx(tx *sqlx.Tx, binding *models.RoleBinding) (sql.Result, error) {
+	updateSQL := `
+UPDATE baetyl_role_binding SET role=?, description=? 
+WHERE namespace=? AND user_id=?
+`
+	return d.exec(tx, updateSQL, binding.Role, binding.Description, binding.Namespace, binding.User)
+}
+
+func (d *dbStorage) DeleteRoleBindingTx(tx *sqlx.Tx, namespace, user string) (sql.Result, error) {
+	deleteSQL := `
+DELETE FROM baetyl_role_binding WHERE namespace=? AND user_id=?
+`
+	return d.exec(tx, deleteSQL, namespace, user)
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	roleBindingTables = []string{
+		`
+CREATE TABLE baetyl_role_binding
+(
+    namespace   varchar(64)   NOT NULL DEFAULT '',
+    user_id     varchar(128)  NOT NULL DEFAULT '',
+    role        varchar(32)   NOT NULL DEFAULT '',
+    description varchar(1024) NOT NULL DEFAULT '',
+    create_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (namespace, user_id)
+);
+`,
+	}
+)
+
+func (d *dbStorage) MockCreateRoleBindingTable() {
+	for _, sql := range roleBindingTables {
+		_, err := d.exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestRoleBinding(t *testing.T) {
+	binding := &models.RoleBinding{
+		Namespace:   "default",
+		User:        "user01",
+		Role:        models.RoleViewer,
+		Description: "desc",
+	}
+
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateRoleBindingTable()
+
+	res, err := db.CreateRoleBinding(binding)
+	assert.NoError(t, err)
+	num, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+
+	_, err = db.CreateRoleBinding(binding)
+	assert.Error(t, err)
+
+	other := &models.RoleBinding{Namespace: "test", User: "user01", Role: models.RoleAdmin}
+	_, err = db.CreateRoleBinding(other)
+	assert.NoError(t, err)
+
+	resBinding, err := db.GetRoleBinding(binding.Namespace, binding.User)
+	assert.NoError(t, err)
+	checkRoleBinding(t, binding, resBinding)
+
+	resBinding, err = db.GetRoleBinding(binding.Namespace, "user02")
+	assert.NoError(t, err)
+	assert.Nil(t, resBinding)
+
+	filter := &models.Filter{PageNo: 1, PageSize: 10}
+	bindings, err := db.ListRoleBinding(binding.Namespace, filter)
+	assert.NoError(t, err)
+	assert.Len(t, bindings, 1)
+	checkRoleBinding(t, binding, &bindings[0])
+	count, err := db.CountRoleBinding(binding.Namespace, filter.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	binding.Role = models.RoleOperator
+	binding.Description = "updated"
+	res, err = db.UpdateRoleBinding(binding)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	resBinding, err = db.GetRoleBinding(binding.Namespace, binding.User)
+	assert.NoError(t, err)
+	checkRoleBinding(t, binding, resBinding)
+
+	res, err = db.DeleteRoleBinding(binding.Namespace, binding.User)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	resBinding, err = db.GetRoleBinding(binding.Namespace, binding.User)
+	assert.NoError(t, err)
+	assert.Nil(t, resBinding)
+
+	resBinding, err = db.GetRoleBinding(other.Namespace, other.User)
+	assert.NoError(t, err)
+	checkRoleBinding(t, other, resBinding)
+}
+
+func checkRoleBinding(t *testing.T, expect, actual *models.RoleBinding) {
+	assert.Equal(t, expect.Namespace, actual.Namespace)
+	assert.Equal(t, expect.User, actual.User)
+	assert.Equal(t, expect.Role, actual.Role)
+	assert.Equal(t, expect.Description, actual.Description)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
@@ -32,6 +33,10 @@ func (d *dbStorage) UpdateRecord(record *models.Record) (sql.Result, error) {
 	return d.UpdateRecordTx(nil, record)
 }
 
+func (d *dbStorage) ActivateRecord(record *models.Record) (sql.Result, error) {
+	return d.ActivateRecordTx(nil, record)
+}
+
 func (d *dbStorage) DeleteRecord(batchName, recordName, ns string) (sql.Result, error) {
 	return d.DeleteRecordTx(nil, batchName, recordName, ns)
 }
@@ -112,8 +117,8 @@ FROM baetyl_batch_record WHERE namespace=? AND batch_name=? AND fingerprint_valu
 	records := []models.Record{}
 	args := []interface{}{ns, batchName, filter.GetFuzzyName()}
 	if filter.GetLimitNumber() > 0 {
-		selectSQL = selectSQL + "LIMIT ?,?"
-		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
 	}
 	if err := d.query(tx, selectSQL, &records, args...); err != nil {
 		return nil, err
@@ -150,6 +155,20 @@ WHERE namespace=? AND batch_name=? AND name = ?;
 		record.ActiveIP, record.ActiveTime, record.Namespace, record.BatchName, record.Name)
 }
 
+// ActivateRecordTx affects no row if the record is activated
+func (d *dbStorage) ActivateRecordTx(tx *sqlx.Tx, record *models.Record) (sql.Result, error) {
+	selectSQL := `
+UPDATE baetyl_batch_record
+SET active=?,
+    node_name=?,
+    active_ip=?,
+    active_time=?
+WHERE namespace=? AND batch_name=? AND name = ? AND active=?;
+`
+	return d.exec(tx, selectSQL, common.Activated, record.NodeName,
+		record.ActiveIP, record.ActiveTime, record.Namespace, record.BatchName, record.Name, common.Inactivated)
+}
+
 func (d *dbStorage) DeleteRecordTx(tx *sqlx.Tx, batchName, recordName, ns string) (sql.Result, error) {
 	selectSQL := `
 DELETE FROM baetyl_batch_record WHERE namespace=? AND batch_name=? AND name=?
@@ -91,6 +91,18 @@ func TestRecord(t *testing.T) {
 	assert.Equal(t, 1, len(records))
 
 	record.Active = 1
+	res, err = db.ActivateRecord(record)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	// activated once
+	res, err = db.ActivateRecord(record)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), num)
+
 	res, err = db.UpdateRecord(record)
 	assert.NoError(t, err)
 	num, err = res.RowsAffected()
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *dbStorage) GetRollout(name, namespace string) (*models.Rollout, error) {
+	return d.GetRolloutTx(nil, name, namespace)
+}
+
+func (d *dbStorage) ListRollout(namespace string, filter *models.Filter) ([]models.Rollout, error) {
+	return d.ListRolloutTx(nil, namespace, filter)
+}
+
+func (d *dbStorage) CountRollout(namespace, name string) (int, error) {
+	return d.CountRolloutTx(nil, namespace, name)
+}
+
+func (d *dbStorage) ListRolloutByStatus(status string) ([]models.Rollout, error) {
+	return d.ListRolloutByStatusTx(nil, status)
+}
+
+func (d *dbStorage) ListNamespaceRolloutByStatus(namespace string, statuses []string) ([]models.Rollout, error) {
+	return d.ListNamespaceRolloutByStatusTx(nil, namespace, statuses)
+}
+
+func (d *dbStorage) CreateRollout(rollout *models.Rollout) (sql.Result, error) {
+	return d.CreateRolloutTx(nil, rollout)
+}
+
+func (d *dbStorage) UpdateRollout(rollout *models.Rollout, oldStatus string) (sql.Result, error) {
+	return d.UpdateRolloutTx(nil, rollout, oldStatus)
+}
+
+func (d *dbStorage) GetRolloutTx(tx *sqlx.Tx, name, namespace string) (*models.Rollout, error) {
+	selectSQL := `
+SELECT name, namespace, app_name, app_version, 
+status, strategy, progress, message, create_time, 
+update_time 
+FROM baetyl_rollout 
+WHERE namespace=? AND name=? LIMIT 1
+`
+	var rollouts []entities.Rollout
+	if err := d.query(tx, selectSQL, &rollouts, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(rollouts) > 0 {
+		return entities.ToRolloutModel(&rollouts[0]), nil
+	}
+	return nil, nil
+}
+
+func (d *dbStorage) ListRolloutTx(tx *sqlx.Tx, namespace string, filter *models.Filter) ([]models.Rollout, error) {
+	selectSQL := `
+SELECT name, namespace, app_name, app_version, 
+status, strategy, progress, message, create_time, 
+update_time 
+FROM baetyl_rollout 
+WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC 
+`
+	var rollouts []entities.Rollout
+	args := []interface{}{namespace, filter.GetFuzzyName()}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	if err := d.query(tx, selectSQL, &rollouts, args...); err != nil {
+		return nil, err
+	}
+	var res []models.Rollout
+	for i := range rollouts {
+		res = append(res, *entities.ToRolloutModel(&rollouts[i]))
+	}
+	return res, nil
+}
+
+func (d *dbStorage) CountRolloutTx(tx *sqlx.Tx, namespace, name string) (int, error) {
+	selectSQL := `
+SELECT count(name) AS count
+FROM baetyl_rollout WHERE namespace=? AND name LIKE ?
+`
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(tx, selectSQL, &res, namespace, name); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
+func (d *dbStorage) ListRolloutByStatusTx(tx *sqlx.Tx, status string) ([]models.Rollout, error) {
+	selectSQL := `
+SELECT name, namespace, app_name, app_version, 
+status, strategy, progress, message, create_time, 
+update_time 
+FROM baetyl_rollout 
+WHERE status=? ORDER BY create_time
+`
+	var rollouts []entities.Rollout
+	if err := d.query(tx, selectSQL, &rollouts, status); err != nil {
+		return nil, err
+	}
+	var res []models.Rollout
+	for i := range rollouts {
+		res = append(res, *entities.ToRolloutModel(&rollouts[i]))
+	}
+	return res, nil
+}
+
+func (d *dbStorage) ListNamespaceRolloutByStatusTx(tx *sqlx.Tx, namespace string, statuses []string) ([]models.Rollout, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	selectSQL := `
+SELECT name, namespace, app_name, app_version, 
+status, strategy, progress, message, create_time, 
+update_time 
+FROM baetyl_rollout 
+WHERE namespace=? AND status IN (?` + strings.Repeat(",?", len(statuses)-1) + `) ORDER BY create_time
+`
+	args := []interface{}{namespace}
+	for _, s := range statuses {
+		args = append(args, s)
+	}
+	var rollouts []entities.Rollout
+	if err := d.query(tx, selectSQL, &rollouts, args...); err != nil {
+		return nil, err
+	}
+	var res []models.Rollout
+	for i := range rollouts {
+		res = append(res, *entities.ToRolloutModel(&rollouts[i]))
+	}
+	return res, nil
+}
+
+func (d *dbStorage) CreateRolloutTx(tx *sqlx.Tx, rollout *models.Rollout) (sql.Result, error) {
+	insertSQL := `
+INSERT INTO baetyl_rollout (
+name, namespace, app_name, app_version, 
+status, strategy, progress, message) 
+VALUES (?,?,?,?,?,?,?,?)
+`
+	rolloutDB := entities.FromRolloutModel(rollout)
+	return d.exec(tx, insertSQL, rolloutDB.Name, rolloutDB.Namespace,
+		rolloutDB.AppName, rolloutDB.AppVersion, rolloutDB.Status,
+		rolloutDB.Strategy, rolloutDB.Progress, rolloutDB.Message)
+}
+
+// UpdateRolloutTx update the rollout only if its status is still the old status
+func (d *dbStorage) UpdateRolloutTx(tx *sqlx.Tx, rollout *models.Rollout, oldStatus string) (sql.Result, error) {
+	updateSQL := `
+UPDATE baetyl_rollout SET status=?, progress=?, message=? 
+WHERE namespace=? AND name=? AND status=?
+`
+	rolloutDB := entities.FromRolloutModel(rollout)
+	return d.exec(tx, updateSQL, rolloutDB.Status, rolloutDB.Progress, rolloutDB.Message,
+		rolloutDB.Namespace, rolloutDB.Name, oldStatus)
+}
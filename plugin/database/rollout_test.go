@@ -0,0 +1,142 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	rolloutTables = []string{
+		`
+CREATE TABLE baetyl_rollout
+(
+    id          integer       PRIMARY KEY AUTOINCREMENT,
+    name        varchar(128)  NOT NULL DEFAULT '',
+    namespace   varchar(64)   NOT NULL DEFAULT '',
+    app_name    varchar(128)  NOT NULL DEFAULT '',
+    app_version varchar(36)   NOT NULL DEFAULT '',
+    status      varchar(16)   NOT NULL DEFAULT '',
+    strategy    varchar(2048) NOT NULL DEFAULT '{}',
+    progress    text,
+    message     varchar(1024) NOT NULL DEFAULT '',
+    create_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *dbStorage) MockCreateRolloutTable() {
+	for _, sql := range rolloutTables {
+		_, err := d.exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestRollout(t *testing.T) {
+	rollout := &models.Rollout{
+		Name:       "app-1",
+		Namespace:  "default",
+		AppName:    "app",
+		AppVersion: "1",
+		Status:     models.RolloutRunning,
+		Strategy: models.RolloutStrategy{
+			Percent:       50,
+			FailurePolicy: models.RolloutPolicyPause,
+		},
+		Progress: models.RolloutProgress{
+			Total:     2,
+			Deployed:  1,
+			Wave:      1,
+			WaveNodes: []string{"n1"},
+			Nodes:     []string{"n1", "n2"},
+		},
+	}
+
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateRolloutTable()
+
+	res, err := db.CreateRollout(rollout)
+	assert.NoError(t, err)
+	num, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+
+	resRollout, err := db.GetRollout(rollout.Name, rollout.Namespace)
+	assert.NoError(t, err)
+	checkRollout(t, rollout, resRollout)
+
+	resRollout, err = db.GetRollout("app-2", rollout.Namespace)
+	assert.NoError(t, err)
+	assert.Nil(t, resRollout)
+
+	filter := &models.Filter{
+		PageNo:   1,
+		PageSize: 10,
+		Name:     "app",
+	}
+	list, err := db.ListRollout(rollout.Namespace, filter)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	checkRollout(t, rollout, &list[0])
+
+	count, err := db.CountRollout(rollout.Namespace, filter.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	list, err = db.ListRolloutByStatus(models.RolloutRunning)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	list, err = db.ListNamespaceRolloutByStatus(rollout.Namespace, []string{models.RolloutRunning, models.RolloutPaused})
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	list, err = db.ListNamespaceRolloutByStatus("other", []string{models.RolloutRunning, models.RolloutPaused})
+	assert.NoError(t, err)
+	assert.Len(t, list, 0)
+	list, err = db.ListNamespaceRolloutByStatus(rollout.Namespace, []string{models.RolloutPaused})
+	assert.NoError(t, err)
+	assert.Len(t, list, 0)
+
+	// the status is changed by others
+	rollout.Status = models.RolloutSucceeded
+	rollout.Progress.Succeeded = []string{"n1", "n2"}
+	res, err = db.UpdateRollout(rollout, models.RolloutPaused)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), num)
+
+	res, err = db.UpdateRollout(rollout, models.RolloutRunning)
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	resRollout, err = db.GetRollout(rollout.Name, rollout.Namespace)
+	assert.NoError(t, err)
+	checkRollout(t, rollout, resRollout)
+
+	list, err = db.ListRolloutByStatus(models.RolloutRunning)
+	assert.NoError(t, err)
+	assert.Len(t, list, 0)
+}
+
+func checkRollout(t *testing.T, expect, actual *models.Rollout) {
+	assert.Equal(t, expect.Name, actual.Name)
+	assert.Equal(t, expect.Namespace, actual.Namespace)
+	assert.Equal(t, expect.AppName, actual.AppName)
+	assert.Equal(t, expect.AppVersion, actual.AppVersion)
+	assert.Equal(t, expect.Status, actual.Status)
+	assert.EqualValues(t, expect.Strategy, actual.Strategy)
+	assert.EqualValues(t, expect.Progress, actual.Progress)
+}
@@ -21,6 +21,10 @@ func (d *dbStorage) UpdateTask(task *models.Task) (sql.Result, error) {
 	return d.UpdateTaskTx(nil, task)
 }
 
+func (d *dbStorage) UpdateTaskByState(task *models.Task, oldState string) (sql.Result, error) {
+	return d.UpdateTaskByStateTx(nil, task, oldState)
+}
+
 func (d *dbStorage) DeleteTask(traceId string) (sql.Result, error) {
 	return d.DeleteTaskTx(nil, traceId)
 }
@@ -37,6 +41,14 @@ func (d *dbStorage) CountTask(task *models.Task) (int, error) {
 	return res[0].Count, nil
 }
 
+func (d *dbStorage) ListNodeTask(ns, node string, filter *models.TaskFilter) ([]models.Task, error) {
+	return d.ListNodeTaskTx(nil, ns, node, filter)
+}
+
+func (d *dbStorage) CountNodeTask(ns, node string, filter *models.TaskFilter) (int, error) {
+	return d.CountNodeTaskTx(nil, ns, node, filter)
+}
+
 func (d *dbStorage) GetTaskTx(tx *sqlx.Tx, traceId string) (*models.Task, error) {
 	selectSQL := `SELECT * FROM baetyl_task where trace_id=?`
 	var tasks []models.Task
@@ -62,7 +74,50 @@ func (d *dbStorage) UpdateTaskTx(tx *sqlx.Tx, task *models.Task) (sql.Result, er
 	return d.exec(tx, updateSQL, task.State, task.Step, time.Now(), task.TraceId)
 }
 
+// UpdateTaskByStateTx affects no row if the state of the task is no longer the old state
+func (d *dbStorage) UpdateTaskByStateTx(tx *sqlx.Tx, task *models.Task, oldState string) (sql.Result, error) {
+	updateSQL := `UPDATE baetyl_task SET state=?,step=?,update_time=? WHERE trace_id=? AND state=?`
+	return d.exec(tx, updateSQL, task.State, task.Step, time.Now(), task.TraceId, oldState)
+}
+
 func (d *dbStorage) DeleteTaskTx(tx *sqlx.Tx, traceId string) (sql.Result, error) {
 	deleteSQL := `DELETE FROM baetyl_task WHERE trace_id=?`
 	return d.exec(tx, deleteSQL, traceId)
 }
+
+func (d *dbStorage) ListNodeTaskTx(tx *sqlx.Tx, ns, node string, filter *models.TaskFilter) ([]models.Task, error) {
+	selectSQL := `
+SELECT trace_id, namespace, node, type, state, step, 
+old_version, new_version, create_time, update_time 
+FROM baetyl_task 
+` + nodeTaskCondition + `ORDER BY create_time DESC 
+`
+	args := []interface{}{ns, node, filter.Type, filter.Type, filter.State, filter.State}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ? OFFSET ?"
+		args = append(args, filter.GetLimitNumber(), filter.GetLimitOffset())
+	}
+	tasks := []models.Task{}
+	if err := d.query(tx, selectSQL, &tasks, args...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (d *dbStorage) CountNodeTaskTx(tx *sqlx.Tx, ns, node string, filter *models.TaskFilter) (int, error) {
+	selectSQL := `
+SELECT count(trace_id) AS count
+FROM baetyl_task 
+` + nodeTaskCondition
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.query(tx, selectSQL, &res, ns, node, filter.Type, filter.Type, filter.State, filter.State); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
+// the empty type or state of the filter matches all tasks of the node
+const nodeTaskCondition = `WHERE namespace=? AND node=? AND (?='' OR type=?) AND (?='' OR state=?) 
+`
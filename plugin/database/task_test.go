@@ -1,7 +1,6 @@
 package database
 
 import (
-	"fmt"
 	"testing"
 	"time"
 
@@ -10,35 +9,6 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
-var (
-	taskTables = []string{
-		`
-CREATE TABLE baetyl_task
-(
-    trace_id          varchar(36)  NOT NULL DEFAULT '' PRIMARY KEY,
-    namespace         varchar(64)  NOT NULL DEFAULT '',
-    node              varchar(128)   NOT NULL DEFAULT '',
-    type              varchar(32)  NOT NULL DEFAULT '',
-    state             varchar(16)       NOT NULL DEFAULT '0',
-    step              text   NOT NULL,
-    old_version       varchar(36)   NOT NULL DEFAULT '',
-    new_version       varchar(36)     NOT NULL DEFAULT '',
-    create_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    update_time       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-`,
-	}
-)
-
-func (d *dbStorage) MockCreateTaskTable() {
-	for _, sql := range taskTables {
-		_, err := d.exec(nil, sql)
-		if err != nil {
-			panic(fmt.Sprintf("create table exception: %s", err.Error()))
-		}
-	}
-}
-
 func TestTask(t *testing.T) {
 	task := &models.Task{
 		TraceId:    "d6cb4c5e2b9611eaa104186590da6863",
@@ -53,13 +23,7 @@ func TestTask(t *testing.T) {
 		UpdateTime: time.Now(),
 	}
 
-	db, err := MockNewDB()
-	if err != nil {
-		fmt.Printf("get mock sqlite3 error = %s", err.Error())
-		t.Fail()
-		return
-	}
-	db.MockCreateTaskTable()
+	db := mockMigratedDB(t)
 	res, err := db.CreateTask(task)
 	assert.NoError(t, err)
 	num, err := res.RowsAffected()
@@ -80,6 +44,22 @@ func TestTask(t *testing.T) {
 	assert.NoError(t, err)
 	checkTask(t, task, resTask)
 
+	// updated only in the old state
+	task.State = "2"
+	res, err = db.UpdateTaskByState(task, "0")
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), num)
+	res, err = db.UpdateTaskByState(task, "1")
+	assert.NoError(t, err)
+	num, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), num)
+	resTask, err = db.GetTask(task.TraceId)
+	assert.NoError(t, err)
+	checkTask(t, task, resTask)
+
 	taskNum, err := db.CountTask(task)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, taskNum)
@@ -101,3 +81,94 @@ func checkTask(t *testing.T, expect, actual *models.Task) {
 	assert.Equal(t, expect.OldVersion, actual.OldVersion)
 	assert.Equal(t, expect.NewVersion, actual.NewVersion)
 }
+
+func TestListNodeTask(t *testing.T) {
+	db := mockMigratedDB(t)
+	tasks := []*models.Task{
+		{TraceId: "t0", Namespace: "default", Node: "n0", Type: models.TaskCommand, State: models.CommandPending, Step: "{}"},
+		{TraceId: "t1", Namespace: "default", Node: "n0", Type: models.TaskCommand, State: models.CommandSucceeded, Step: "{}"},
+		{TraceId: "t2", Namespace: "default", Node: "n0", Type: "APP", State: "1", Step: "2"},
+		{TraceId: "t3", Namespace: "default", Node: "n1", Type: models.TaskCommand, State: models.CommandPending, Step: "{}"},
+	}
+	for _, task := range tasks {
+		_, err := db.CreateTask(task)
+		assert.NoError(t, err)
+	}
+
+	filter := &models.TaskFilter{}
+	res, err := db.ListNodeTask("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Len(t, res, 3)
+	count, err := db.CountNodeTask("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	filter = &models.TaskFilter{Type: models.TaskCommand}
+	res, err = db.ListNodeTask("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	filter = &models.TaskFilter{Type: models.TaskCommand, State: models.CommandPending}
+	res, err = db.ListNodeTask("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	checkTask(t, tasks[0], &res[0])
+	count, err = db.CountNodeTask("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	filter = &models.TaskFilter{Filter: models.Filter{PageNo: 2, PageSize: 2}}
+	res, err = db.ListNodeTask("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+
+	res, err = db.ListNodeTask("default", "n2", &models.TaskFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+}
+
+func TestOTATask(t *testing.T) {
+	db := mockMigratedDB(t)
+	ota := &models.OTATask{
+		ID:         "o0",
+		Namespace:  "default",
+		Node:       "n0",
+		App:        "baetyl-core-n0",
+		OldVersion: "1",
+		NewVersion: "2",
+		OldImage:   "baetyl:v2.1.0",
+		NewImage:   "baetyl:v2.2.0",
+		Timeout:    600,
+		State:      models.OTARunning,
+		StartTime:  time.Now().UTC().Truncate(time.Second),
+	}
+	task, err := ota.ToTask()
+	assert.NoError(t, err)
+	_, err = db.CreateTask(task)
+	assert.NoError(t, err)
+	// the trace id is unique
+	_, err = db.CreateTask(task)
+	assert.Error(t, err)
+
+	filter := &models.TaskFilter{Type: models.TaskOTA, State: models.OTARunning}
+	res, err := db.ListNodeTask("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	actual, err := models.NewOTATaskFromTask(&res[0])
+	assert.NoError(t, err)
+	assert.Equal(t, ota.NewImage, actual.NewImage)
+	assert.Equal(t, ota.OldImage, actual.OldImage)
+	assert.True(t, ota.StartTime.Equal(actual.StartTime))
+
+	ota.State = models.OTASucceeded
+	task, err = ota.ToTask()
+	assert.NoError(t, err)
+	_, err = db.UpdateTask(task)
+	assert.NoError(t, err)
+	count, err := db.CountNodeTask("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	count, err = db.CountNodeTask("default", "n0", &models.TaskFilter{Type: models.TaskOTA})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,16 @@
+package kms
+
+// CloudConfig baetyl-cloud config
+type CloudConfig struct {
+	DefaultKMS struct {
+		// Current the id of the key used to encrypt, the other keys are only used to decrypt during the rotation
+		Current string `yaml:"current" json:"current" validate:"nonzero"`
+		Keys    []Key  `yaml:"keys" json:"keys" validate:"nonzero"`
+	} `yaml:"defaultkms" json:"defaultkms"`
+}
+
+// Key the key-encryption key stored in the file as base64 encoded 16, 24 or 32 bytes
+type Key struct {
+	ID   string `yaml:"id" json:"id" validate:"nonzero"`
+	File string `yaml:"file" json:"file" validate:"nonzero"`
+}
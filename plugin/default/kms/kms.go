@@ -0,0 +1,93 @@
+package kms
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+var keyIDPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// defaultKMS the kms with the key-encryption keys loaded from the files
+type defaultKMS struct {
+	current string
+	keys    map[string][]byte
+}
+
+func init() {
+	plugin.RegisterFactory("defaultkms", New)
+}
+
+// New New
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, err
+	}
+	keys := map[string][]byte{}
+	for _, k := range cfg.DefaultKMS.Keys {
+		if !keyIDPattern.MatchString(k.ID) {
+			return nil, kmsError(fmt.Sprintf("the id of the key (%s) is invalid", k.ID))
+		}
+		if _, ok := keys[k.ID]; ok {
+			return nil, kmsError(fmt.Sprintf("the key (%s) is duplicated", k.ID))
+		}
+		data, err := ioutil.ReadFile(k.File)
+		if err != nil {
+			return nil, err
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, kmsError(fmt.Sprintf("the key (%s) is not base64 encoded", k.ID))
+		}
+		if l := len(key); l != 16 && l != 24 && l != 32 {
+			return nil, kmsError(fmt.Sprintf("the size of the key (%s) is invalid", k.ID))
+		}
+		keys[k.ID] = key
+	}
+	if _, ok := keys[cfg.DefaultKMS.Current]; !ok {
+		return nil, kmsError(fmt.Sprintf("the current key (%s) is not found", cfg.DefaultKMS.Current))
+	}
+	return &defaultKMS{
+		current: cfg.DefaultKMS.Current,
+		keys:    keys,
+	}, nil
+}
+
+func (k *defaultKMS) CurrentKeyID() string {
+	return k.current
+}
+
+func (k *defaultKMS) Encrypt(plaintext []byte) (string, []byte, error) {
+	ciphertext, err := util.GCMEncrypt(plaintext, k.keys[k.current])
+	if err != nil {
+		return "", nil, err
+	}
+	return k.current, ciphertext, nil
+}
+
+func (k *defaultKMS) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, kmsError(fmt.Sprintf("the key (%s) is not found", keyID))
+	}
+	plaintext, err := util.GCMDecrypt(ciphertext, key)
+	if err != nil {
+		return nil, kmsError(err.Error())
+	}
+	return plaintext, nil
+}
+
+func (k *defaultKMS) Close() error {
+	return nil
+}
+
+func kmsError(msg string) error {
+	return common.Error(common.ErrIO, common.Field("error", msg))
+}
@@ -0,0 +1,91 @@
+package kms
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const confData = `
+defaultkms:
+  current: k2
+  keys:
+  - id: k1
+    file: etc/baetyl/k1.key
+  - id: k2
+    file: etc/baetyl/k2.key
+`
+
+func genConfig(workspace, conf string) error {
+	if err := os.MkdirAll(workspace, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path.Join(workspace, "cloud.yml"), []byte(conf), 0755); err != nil {
+		return err
+	}
+	for name, size := range map[string]int{"k1.key": 16, "k2.key": 32, "k3.key": 20} {
+		key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat(name[:2], size)[:size]))
+		if err := ioutil.WriteFile(path.Join(workspace, name), []byte(key+"\n"), 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDefaultKMS(t *testing.T) {
+	assert.NoError(t, genConfig("etc/baetyl", confData))
+	defer os.RemoveAll(path.Dir("etc/baetyl"))
+
+	p, err := plugin.GetPlugin("defaultkms")
+	assert.NoError(t, err)
+	kms := p.(plugin.KMS)
+	assert.Equal(t, "k2", kms.CurrentKeyID())
+
+	keyID, ciphertext, err := kms.Encrypt([]byte("data key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "k2", keyID)
+	plaintext, err := kms.Decrypt(keyID, ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "data key", string(plaintext))
+
+	_, err = kms.Decrypt("k1", ciphertext)
+	assert.Error(t, err)
+	_, err = kms.Decrypt("k3", ciphertext)
+	assert.Error(t, err)
+	assert.NoError(t, kms.Close())
+
+	// the data encrypted by the envelope is readable after the current key is rotated
+	e, err := plugin.NewEnvelope("defaultkms")
+	assert.NoError(t, err)
+	data, err := e.Encrypt([]byte("secret"))
+	assert.NoError(t, err)
+	p.(*defaultKMS).current = "k1"
+	assert.True(t, e.Outdated(data))
+	plaintext, err = e.Decrypt(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+}
+
+func TestDefaultKMSInvalid(t *testing.T) {
+	defer os.RemoveAll(path.Dir("etc/baetyl"))
+	cases := []string{
+		"defaultkms:\n  current: k3\n  keys:\n  - id: k1\n    file: etc/baetyl/k1.key\n",
+		"defaultkms:\n  current: k3\n  keys:\n  - id: k3\n    file: etc/baetyl/k3.key\n",
+		"defaultkms:\n  current: k1\n  keys:\n  - id: k1\n    file: etc/baetyl/k0.key\n",
+		"defaultkms:\n  current: k1\n  keys:\n  - id: k1\n    file: etc/baetyl/cloud.yml\n",
+		"defaultkms:\n  current: k$1\n  keys:\n  - id: k$1\n    file: etc/baetyl/k1.key\n",
+		"defaultkms:\n  current: k1\n  keys:\n  - id: k1\n    file: etc/baetyl/k1.key\n  - id: k1\n    file: etc/baetyl/k2.key\n",
+	}
+	for _, c := range cases {
+		assert.NoError(t, genConfig("etc/baetyl", c))
+		_, err := New()
+		assert.Error(t, err, c)
+	}
+}
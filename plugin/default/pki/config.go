@@ -11,4 +11,7 @@ type CloudConfig struct {
 		RootDuration  time.Duration `yaml:"rootDuration" json:"rootDuration" default:"438000h"` // 50*365*24
 		Persistent    string        `yaml:"persistent" json:"persistent" default:"database"`
 	} `yaml:"defaultpki" json:"defaultpki"`
+	Plugin struct {
+		KMS string `yaml:"kms" json:"kms"`
+	} `yaml:"plugin" json:"plugin"`
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"io/ioutil"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/pki"
 
@@ -31,6 +32,7 @@ type defaultPkiClient struct {
 	cfg       CloudConfig
 	sto       plugin.PKIStorage
 	pkiClient pki.PKI
+	envelope  *plugin.Envelope
 }
 
 func init() {
@@ -53,6 +55,11 @@ func NewPKI() (plugin.Plugin, error) {
 		return nil, ErrPlugin
 	}
 
+	envelope, err := plugin.NewEnvelope(cfg.Plugin.KMS)
+	if err != nil {
+		return nil, err
+	}
+
 	pkiClient, err := pki.NewPKIClient()
 	if err != nil {
 		return nil, err
@@ -61,6 +68,7 @@ func NewPKI() (plugin.Plugin, error) {
 		cfg:       cfg,
 		sto:       sto,
 		pkiClient: pkiClient,
+		envelope:  envelope,
 	}
 	err = cli.checkRootCA()
 	if err != nil {
@@ -139,6 +147,47 @@ func (p *defaultPkiClient) DeleteClientCert(certId string) error {
 	return p.sto.DeleteCert(certId)
 }
 
+func (p *defaultPkiClient) ListCertByNotAfter(before time.Time) ([]plugin.Cert, error) {
+	certs, err := p.sto.ListCertByNotAfter(before)
+	if err != nil {
+		return nil, err
+	}
+	for i := range certs {
+		certs[i].PrivateKey = ""
+	}
+	return certs, nil
+}
+
+// RotatePrivateKeys encrypts the private keys not encrypted by the current key-encryption key again
+func (p *defaultPkiClient) RotatePrivateKeys() (int, error) {
+	if !p.envelope.Enabled() {
+		return 0, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the kms is not configured"))
+	}
+	certs, err := p.sto.ListCertWithPrivateKey()
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	for _, cert := range certs {
+		if !p.envelope.Outdated([]byte(cert.PrivateKey)) {
+			continue
+		}
+		privateKey, err := p.envelope.Decrypt([]byte(cert.PrivateKey))
+		if err != nil {
+			return count, err
+		}
+		if privateKey, err = p.envelope.Encrypt(privateKey); err != nil {
+			return count, err
+		}
+		cert.PrivateKey = string(privateKey)
+		if err = p.sto.UpdateCert(cert); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
 func (p *defaultPkiClient) Close() error {
 	return p.sto.Close()
 }
@@ -204,12 +253,17 @@ func (p *defaultPkiClient) saveCert(certId string, cert *pki.CertPem, csr []byte
 	if crtInfo[0].IsCA {
 		tp = TypeIssuingCA
 	}
+	// the private key is encrypted at rest if the kms is configured
+	privateKey, err := p.envelope.Encrypt([]byte(base64.StdEncoding.EncodeToString(cert.Key)))
+	if err != nil {
+		return err
+	}
 	certView := plugin.Cert{
 		CertId:     certId,
 		Type:       tp,
 		CommonName: crtInfo[0].Subject.CommonName,
 		Content:    base64.StdEncoding.EncodeToString(cert.Crt),
-		PrivateKey: base64.StdEncoding.EncodeToString(cert.Key),
+		PrivateKey: string(privateKey),
 		Csr:        base64.StdEncoding.EncodeToString(csr),
 		NotBefore:  crtInfo[0].NotBefore,
 		NotAfter:   crtInfo[0].NotAfter,
@@ -226,7 +280,11 @@ func (p *defaultPkiClient) getRootCA(certId string) (*pki.CertPem, error) {
 	if err != nil {
 		return nil, err
 	}
-	key, err := base64.StdEncoding.DecodeString(res.PrivateKey)
+	privateKey, err := p.envelope.Decrypt([]byte(res.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(string(privateKey))
 	if err != nil {
 		return nil, err
 	}
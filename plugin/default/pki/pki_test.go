@@ -9,6 +9,7 @@ import (
 	"path"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/pki"
 	"github.com/golang/mock/gomock"
@@ -119,6 +120,7 @@ func genDefaultPkiClient(t *testing.T) (*defaultPkiClient, *mockPKI.MockPKIStora
 		cfg:       *cfg,
 		sto:       mockSto,
 		pkiClient: pkiClient,
+		envelope:  &plugin.Envelope{},
 	}, mockSto
 }
 
@@ -267,9 +269,111 @@ func TestDefaultPkiClient_DeleteClientCert(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDefaultPkiClient_ListCertByNotAfter(t *testing.T) {
+	p, s := genDefaultPkiClient(t)
+	before := time.Now()
+	s.EXPECT().ListCertByNotAfter(before).Return([]plugin.Cert{*genRootCAView()}, nil).Times(1)
+	res, err := p.ListCertByNotAfter(before)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "root.ca", res[0].CommonName)
+	assert.Empty(t, res[0].PrivateKey)
+
+	s.EXPECT().ListCertByNotAfter(before).Return(nil, os.ErrNotExist).Times(1)
+	_, err = p.ListCertByNotAfter(before)
+	assert.Error(t, err)
+}
+
 func TestDefaultPkiClient_Close(t *testing.T) {
 	p, s := genDefaultPkiClient(t)
 	s.EXPECT().Close().Return(nil).Times(1)
 	err := p.Close()
 	assert.NoError(t, err)
 }
+
+func TestDefaultPkiClient_EncryptPrivateKey(t *testing.T) {
+	p, s := genDefaultPkiClient(t)
+	mockKMS := mockPKI.NewMockKMS(gomock.NewController(t))
+	plugin.RegisterFactory("pkitestkms", func() (plugin.Plugin, error) {
+		return mockKMS, nil
+	})
+	envelope, err := plugin.NewEnvelope("pkitestkms")
+	assert.NoError(t, err)
+	p.envelope = envelope
+
+	mockKMS.EXPECT().Encrypt(gomock.Any()).DoAndReturn(func(key []byte) (string, []byte, error) {
+		return "k1", key, nil
+	})
+	mockKMS.EXPECT().Decrypt("k1", gomock.Any()).DoAndReturn(func(_ string, key []byte) ([]byte, error) {
+		return key, nil
+	})
+	var saved plugin.Cert
+	s.EXPECT().CreateCert(gomock.Any()).DoAndReturn(func(cert plugin.Cert) error {
+		saved = cert
+		return nil
+	})
+	assert.NoError(t, p.saveCert(RootCertId, &pki.CertPem{Crt: []byte(caPem), Key: []byte(caKey)}, []byte("")))
+	assert.True(t, plugin.IsEnveloped([]byte(saved.PrivateKey)))
+	assert.NotContains(t, saved.PrivateKey, base64.StdEncoding.EncodeToString([]byte(caKey)))
+
+	s.EXPECT().GetCert(RootCertId).Return(&saved, nil)
+	res, err := p.getRootCA(RootCertId)
+	assert.NoError(t, err)
+	assert.Equal(t, caKey, string(res.Key))
+
+	// the private key stored before the encryption is enabled is still readable
+	s.EXPECT().GetCert(RootCertId).Return(genRootCAView(), nil)
+	res, err = p.getRootCA(RootCertId)
+	assert.NoError(t, err)
+	assert.Equal(t, caKey, string(res.Key))
+}
+
+func TestDefaultPkiClient_RotatePrivateKeys(t *testing.T) {
+	p, s := genDefaultPkiClient(t)
+	_, err := p.RotatePrivateKeys()
+	assert.Error(t, err)
+
+	mockKMS := mockPKI.NewMockKMS(gomock.NewController(t))
+	plugin.RegisterFactory("pkirotatekms", func() (plugin.Plugin, error) {
+		return mockKMS, nil
+	})
+	envelope, err := plugin.NewEnvelope("pkirotatekms")
+	assert.NoError(t, err)
+	p.envelope = envelope
+	mockKMS.EXPECT().CurrentKeyID().Return("k2").AnyTimes()
+	mockKMS.EXPECT().Encrypt(gomock.Any()).DoAndReturn(func(key []byte) (string, []byte, error) {
+		return "k2", key, nil
+	}).AnyTimes()
+	mockKMS.EXPECT().Decrypt(gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, key []byte) ([]byte, error) {
+		return key, nil
+	}).AnyTimes()
+
+	// the private keys not encrypted or encrypted by the previous key are encrypted again
+	plain := genRootCAView()
+	current, err := envelope.Encrypt([]byte(plain.PrivateKey))
+	assert.NoError(t, err)
+	previous := *plain
+	previous.CertId = "previous"
+	previous.PrivateKey = strings.Replace(string(current), "$k2$", "$k1$", 1)
+	latest := *plain
+	latest.CertId = "latest"
+	latest.PrivateKey = string(current)
+	s.EXPECT().ListCertWithPrivateKey().Return([]plugin.Cert{*plain, previous, latest}, nil)
+	var rotated []string
+	s.EXPECT().UpdateCert(gomock.Any()).DoAndReturn(func(cert plugin.Cert) error {
+		assert.True(t, strings.Contains(cert.PrivateKey, "$k2$"))
+		key, err := envelope.Decrypt([]byte(cert.PrivateKey))
+		assert.NoError(t, err)
+		assert.Equal(t, plain.PrivateKey, string(key))
+		rotated = append(rotated, cert.CertId)
+		return nil
+	}).Times(2)
+	count, err := p.RotatePrivateKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{plain.CertId, "previous"}, rotated)
+
+	s.EXPECT().ListCertWithPrivateKey().Return(nil, os.ErrNotExist)
+	_, err = p.RotatePrivateKeys()
+	assert.Error(t, err)
+}
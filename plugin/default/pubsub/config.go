@@ -0,0 +1,14 @@
+package pubsub
+
+// CloudConfig baetyl-cloud config
+type CloudConfig struct {
+	DefaultPubsub Config `yaml:"defaultpubsub" json:"defaultpubsub"`
+}
+
+// Config the limits of subscriptions
+type Config struct {
+	// the buffered messages of each subscription, the message is dropped for the subscription with full buffer
+	BufferSize int `yaml:"bufferSize" json:"bufferSize" default:"64"`
+	// the max subscriptions of each topic, unlimited if 0
+	MaxSubscribers int `yaml:"maxSubscribers" json:"maxSubscribers" default:"1024"`
+}
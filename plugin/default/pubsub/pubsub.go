@@ -0,0 +1,141 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+var (
+	ErrPubsubClosed       = errors.New("pubsub is closed")
+	ErrTooManySubscribers = errors.New("the subscribers of the topic exceed the limit")
+)
+
+func init() {
+	plugin.RegisterFactory("defaultpubsub", New)
+}
+
+// New create the in-process pubsub for single replica deployments
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, err
+	}
+	return NewMemoryPubsub(cfg.DefaultPubsub), nil
+}
+
+// MemoryPubsub delivers the messages to the subscriptions in the same process.
+// Each subscription is a buffered channel, which is closed when unsubscribed or the pubsub is closed
+type MemoryPubsub struct {
+	cfg    Config
+	topics map[string]map[chan interface{}]struct{}
+	closed bool
+	lock   sync.RWMutex
+	log    *log.Logger
+}
+
+// NewMemoryPubsub create the in-process pubsub, also used by the networked pubsub to fan out the received messages
+func NewMemoryPubsub(cfg Config) *MemoryPubsub {
+	return &MemoryPubsub{
+		cfg:    cfg,
+		topics: map[string]map[chan interface{}]struct{}{},
+		log:    log.L().With(log.Any("plugin", "defaultpubsub")),
+	}
+}
+
+// Publish send the message to all subscriptions of the topic without blocking, a subscription is skipped if its buffer is full
+func (m *MemoryPubsub) Publish(topic string, msg interface{}) error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.closed {
+		return ErrPubsubClosed
+	}
+	dropped := 0
+	for ch := range m.topics[topic] {
+		if !m.send(ch, msg) {
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		m.log.Warn("message is dropped for slow subscribers", log.Any("topic", topic), log.Any("dropped", dropped))
+		return fmt.Errorf("failed to deliver the message of topic (%s) to %d subscribers", topic, dropped)
+	}
+	return nil
+}
+
+// Subscribe create a subscription of the topic
+func (m *MemoryPubsub) Subscribe(topic string) (chan interface{}, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.closed {
+		return nil, ErrPubsubClosed
+	}
+	chs, ok := m.topics[topic]
+	if !ok {
+		chs = map[chan interface{}]struct{}{}
+		m.topics[topic] = chs
+	}
+	if m.cfg.MaxSubscribers > 0 && len(chs) >= m.cfg.MaxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+	ch := make(chan interface{}, m.cfg.BufferSize)
+	chs[ch] = struct{}{}
+	return ch, nil
+}
+
+// Unsubscribe remove and close the subscription, nothing happens if the subscription does not exist
+func (m *MemoryPubsub) Unsubscribe(topic string, ch chan interface{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	chs, ok := m.topics[topic]
+	if !ok {
+		return nil
+	}
+	if _, ok = chs[ch]; !ok {
+		return nil
+	}
+	delete(chs, ch)
+	close(ch)
+	if len(chs) == 0 {
+		delete(m.topics, topic)
+	}
+	return nil
+}
+
+// Subscribers the count of subscriptions of the topic
+func (m *MemoryPubsub) Subscribers(topic string) int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return len(m.topics[topic])
+}
+
+// Close close all subscriptions, the pubsub can not be used any more
+func (m *MemoryPubsub) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	for topic, chs := range m.topics {
+		for ch := range chs {
+			close(ch)
+		}
+		delete(m.topics, topic)
+	}
+	return nil
+}
+
+func (m *MemoryPubsub) send(ch chan interface{}, msg interface{}) bool {
+	select {
+	case ch <- msg:
+		return true
+	default:
+		return false
+	}
+}
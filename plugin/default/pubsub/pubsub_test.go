@@ -0,0 +1,85 @@
+package pubsub
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const confData = `
+defaultpubsub:
+  bufferSize: 2
+  maxSubscribers: 3
+`
+
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pubsub")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "cloud.yml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(confData), 0755))
+	common.SetConfFile(file)
+	defer common.SetConfFile(common.ValueConfFile)
+
+	p, err := plugin.GetPlugin("defaultpubsub")
+	assert.NoError(t, err)
+	ps, ok := p.(plugin.Pubsub)
+	assert.True(t, ok)
+	m := ps.(*MemoryPubsub)
+	assert.Equal(t, Config{BufferSize: 2, MaxSubscribers: 3}, m.cfg)
+}
+
+func TestMemoryPubsub(t *testing.T) {
+	m := NewMemoryPubsub(Config{BufferSize: 1, MaxSubscribers: 2})
+
+	// no subscriber
+	assert.NoError(t, m.Publish("t1", "a"))
+
+	ch1, err := m.Subscribe("t1")
+	assert.NoError(t, err)
+	ch2, err := m.Subscribe("t1")
+	assert.NoError(t, err)
+	_, err = m.Subscribe("t1")
+	assert.Equal(t, ErrTooManySubscribers, err)
+	ch3, err := m.Subscribe("t2")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, m.Subscribers("t1"))
+
+	assert.NoError(t, m.Publish("t1", "b"))
+	assert.Equal(t, "b", <-ch1)
+
+	// the buffer of ch2 is full, the message is dropped for ch2 only
+	assert.Error(t, m.Publish("t1", "c"))
+	assert.Equal(t, "c", <-ch1)
+	assert.Equal(t, "b", <-ch2)
+	assert.Len(t, ch3, 0)
+
+	// unsubscribe closes the channel
+	assert.NoError(t, m.Unsubscribe("t1", ch1))
+	_, ok := <-ch1
+	assert.False(t, ok)
+	assert.NoError(t, m.Unsubscribe("t1", ch1))
+	assert.NoError(t, m.Unsubscribe("t3", ch1))
+	assert.Equal(t, 1, m.Subscribers("t1"))
+	assert.NoError(t, m.Publish("t1", "d"))
+	assert.Equal(t, "d", <-ch2)
+
+	assert.NoError(t, m.Unsubscribe("t1", ch2))
+	assert.Equal(t, 0, m.Subscribers("t1"))
+	assert.NotContains(t, m.topics, "t1")
+
+	// close all subscriptions
+	assert.NoError(t, m.Close())
+	_, ok = <-ch3
+	assert.False(t, ok)
+	assert.NoError(t, m.Close())
+	assert.Equal(t, ErrPubsubClosed, m.Publish("t2", "e"))
+	_, err = m.Subscribe("t2")
+	assert.Equal(t, ErrPubsubClosed, err)
+}
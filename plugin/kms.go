@@ -0,0 +1,138 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+)
+
+//go:generate mockgen -destination=../mock/plugin/kms.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin KMS
+
+// KMS the key management service which encrypts the data keys by the key-encryption keys
+type KMS interface {
+	// CurrentKeyID returns the id of the key-encryption key used to encrypt
+	CurrentKeyID() string
+	// Encrypt encrypts the data key by the current key-encryption key, the id of the key is returned
+	Encrypt(plaintext []byte) (string, []byte, error)
+	// Decrypt decrypts the data key by the key-encryption key of the id
+	Decrypt(keyID string, ciphertext []byte) ([]byte, error)
+	io.Closer
+}
+
+const (
+	envelopePrefix  = "$baetyl-envelope$v1$"
+	envelopeDataKey = 32
+)
+
+// Envelope encrypts the data by AES-GCM with a random data key, the data key encrypted by the kms
+// and the id of the key-encryption key are stored with the ciphertext as
+// $baetyl-envelope$v1$<key id>$<encrypted data key>$<ciphertext>,
+// the data not encrypted by the envelope is returned as it is when decrypted,
+// and the encryption is disabled if the kms is not configured
+type Envelope struct {
+	kms KMS
+}
+
+// NewEnvelope creates the envelope with the kms plugin, the encryption is disabled if the name is empty
+func NewEnvelope(name string) (*Envelope, error) {
+	if name == "" {
+		return &Envelope{}, nil
+	}
+	p, err := GetPlugin(name)
+	if err != nil {
+		return nil, err
+	}
+	kms, ok := p.(KMS)
+	if !ok {
+		return nil, common.Error(common.ErrPluginInvalid, common.Field("name", name), common.Field("kind", "kms"))
+	}
+	return &Envelope{kms: kms}, nil
+}
+
+// Enabled returns whether the encryption is enabled
+func (e *Envelope) Enabled() bool {
+	return e.kms != nil
+}
+
+// Encrypt encrypts the plaintext, the empty plaintext is not encrypted
+func (e *Envelope) Encrypt(plaintext []byte) ([]byte, error) {
+	if e.kms == nil || len(plaintext) == 0 {
+		return plaintext, nil
+	}
+	dataKey, err := util.GenerateSalt(envelopeDataKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := util.GCMEncrypt(plaintext, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	keyID, encryptedKey, err := e.kms.Encrypt(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(envelopePrefix)
+	buf.WriteString(keyID)
+	buf.WriteString("$")
+	buf.WriteString(base64.StdEncoding.EncodeToString(encryptedKey))
+	buf.WriteString("$")
+	buf.WriteString(base64.StdEncoding.EncodeToString(ciphertext))
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts the data encrypted by the envelope
+func (e *Envelope) Decrypt(data []byte) ([]byte, error) {
+	if !IsEnveloped(data) {
+		return data, nil
+	}
+	if e.kms == nil {
+		return nil, envelopeError("the kms is not configured to decrypt the data")
+	}
+	parts := strings.Split(string(data[len(envelopePrefix):]), "$")
+	if len(parts) != 3 {
+		return nil, envelopeError("the envelope is malformed")
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, envelopeError("the data key is malformed")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, envelopeError("the ciphertext is malformed")
+	}
+	dataKey, err := e.kms.Decrypt(parts[0], encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := util.GCMDecrypt(ciphertext, dataKey)
+	if err != nil {
+		return nil, envelopeError(err.Error())
+	}
+	return plaintext, nil
+}
+
+// Outdated returns whether the data should be encrypted again by the current key-encryption key,
+// that is the data is not encrypted yet or encrypted by another key-encryption key
+func (e *Envelope) Outdated(data []byte) bool {
+	if e.kms == nil || len(data) == 0 {
+		return false
+	}
+	if !IsEnveloped(data) {
+		return true
+	}
+	return !bytes.HasPrefix(data[len(envelopePrefix):], []byte(e.kms.CurrentKeyID()+"$"))
+}
+
+// IsEnveloped returns whether the data is encrypted by the envelope
+func IsEnveloped(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(envelopePrefix))
+}
+
+func envelopeError(msg string) error {
+	return common.Error(common.ErrIO, common.Field("error", "failed to decrypt the envelope: "+msg))
+}
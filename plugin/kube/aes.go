@@ -1,28 +1,11 @@
 package kube
 
 import (
-	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 )
 
-func EncryptMap(data map[string][]byte, key []byte) (result map[string][]byte, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err, _ = r.(error)
-		}
-	}()
-	result = map[string][]byte{}
-	for k, v := range data {
-		b, err := Encrypt(v, key)
-		if err != nil {
-			return nil, err
-		}
-		result[k] = b
-	}
-	return result, nil
-}
-
+// DecryptMap decrypts the data encrypted by the aes of the previous versions, the key is also used as the iv
 func DecryptMap(data map[string][]byte, key []byte) (result map[string][]byte, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -40,19 +23,6 @@ func DecryptMap(data map[string][]byte, key []byte) (result map[string][]byte, e
 	return result, nil
 }
 
-func Encrypt(plaintext, key []byte) ([]byte, error) {
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, err
-	}
-	blockSize := block.BlockSize()
-	plaintext = PKCS7Padding(plaintext, blockSize)
-	blockMode := cipher.NewCBCEncrypter(block, key[:blockSize])
-	crypted := make([]byte, len(plaintext))
-	blockMode.CryptBlocks(crypted, plaintext)
-	return crypted, nil
-}
-
 func Decrypt(ciphertext, key []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -66,12 +36,6 @@ func Decrypt(ciphertext, key []byte) ([]byte, error) {
 	return origData, nil
 }
 
-func PKCS7Padding(ciphertext []byte, blockSize int) []byte {
-	padding := blockSize - len(ciphertext)%blockSize
-	padtext := bytes.Repeat([]byte{byte(padding)}, padding)
-	return append(ciphertext, padtext...)
-}
-
 func PKCS7UnPadding(origData []byte) []byte {
 	length := len(origData)
 	unpadding := int(origData[length-1])
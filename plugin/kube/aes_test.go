@@ -1,23 +1,37 @@
 package kube
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// legacyEncrypt encrypts the data as the previous versions
+func legacyEncrypt(plaintext, key []byte) []byte {
+	block, _ := aes.NewCipher(key)
+	blockSize := block.BlockSize()
+	padding := blockSize - len(plaintext)%blockSize
+	plaintext = append(plaintext, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	crypted := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, key[:blockSize]).CryptBlocks(crypted, plaintext)
+	return crypted
+}
+
 func TestAES(t *testing.T) {
-	testmap := map[string][]byte{
-		"key-a": []byte("hello"),
-		"key-b": []byte("world"),
+	key := []byte("0123456789abcdef")
+	emap := map[string][]byte{
+		"key-a": legacyEncrypt([]byte("hello"), key),
+		"key-b": legacyEncrypt([]byte("world"), key),
 	}
-	emap, _ := EncryptMap(testmap, []byte("0123456789abcdef"))
-	dmap, _ := DecryptMap(emap, []byte("0123456789abcdef"))
-
-	assert.Equal(t, testmap["key-a"], dmap["key-a"])
-	assert.Equal(t, testmap["key-b"], dmap["key-b"])
+	dmap, err := DecryptMap(emap, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(dmap["key-a"]))
+	assert.Equal(t, "world", string(dmap["key-b"]))
 
-	itext, _ := Encrypt([]byte("hello world"), []byte("0123456789abcdef"))
-	otext, _ := Decrypt(itext, []byte("0123456789abcdef"))
+	otext, err := Decrypt(legacyEncrypt([]byte("hello world"), key), key)
+	assert.NoError(t, err)
 	assert.Equal(t, "hello world", string(otext))
 }
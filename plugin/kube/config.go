@@ -5,7 +5,7 @@ type CloudConfig struct {
 	Kube struct {
 		OutCluster bool   `yaml:"outCluster" json:"outCluster"`
 		ConfigPath string `yaml:"configPath" json:"configPath" default:"etc/baetyl/kubeconfig.yml"`
-		// TODO Remove from plugin
+		// AES the key only decrypts the secrets stored by the previous versions, which are stored without it once updated
 		AES struct {
 			Key string `yaml:"key" json:"key" default:"baetyl2020202020"`
 		} `yaml:"aes" json:"aes" default:"{}"`
@@ -47,6 +47,19 @@ func (c *client) CreateNamespace(namespace *models.Namespace) (*models.Namespace
 	return toNamespaceModel(n), nil
 }
 
+func (c *client) ListNamespace() ([]models.Namespace, error) {
+	defer utils.Trace(c.log.Debug, "ListNamespace")()
+	list, err := c.coreV1.Namespaces().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]models.Namespace, 0, len(list.Items))
+	for i := range list.Items {
+		res = append(res, *toNamespaceModel(&list.Items[i]))
+	}
+	return res, nil
+}
+
 func (c *client) DeleteNamespace(namespace *models.Namespace) error {
 	defer utils.Trace(c.log.Debug, "DeleteNamespace")()
 	err := c.coreV1.Namespaces().Delete(namespace.Name, &metav1.DeleteOptions{})
@@ -57,6 +57,13 @@ func TestCreateNamespace(t *testing.T) {
 	assert.Equal(t, "test", namespace.Name)
 }
 
+func TestListNamespace(t *testing.T) {
+	c := initNamespaceClient()
+	res, err := c.ListNamespace()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Namespace{{Name: "default"}}, res)
+}
+
 func TestDeleteNamespace(t *testing.T) {
 	c := initNamespaceClient()
 	ns := &models.Namespace{
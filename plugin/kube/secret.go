@@ -15,15 +15,25 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// the secrets stored by the previous versions are encrypted by the aes key of the config,
+// they are decrypted when read and stored as they are once updated, e.g. by the rotate command,
+// the secrets stored as they are have the annotation of the encoding
+const (
+	annotationSecretEncoding = common.BaetylCloudGroup + "/secret-encoding"
+	secretEncodingNone       = "none"
+)
+
 func (c *client) toSecretModel(secret *v1alpha1.Secret) *specV1.Secret {
 	res := &specV1.Secret{Version: secret.ObjectMeta.ResourceVersion}
 	err := copier.Copy(res, secret)
 	if err != nil {
 		panic(fmt.Sprintf("copier exception: %s", err.Error()))
 	}
-	res.Data, err = DecryptMap(res.Data, c.aesKey)
-	if err != nil {
-		log.L().Error("decrypt exception", log.Error(err))
+	if secret.Annotations[annotationSecretEncoding] != secretEncodingNone {
+		res.Data, err = DecryptMap(res.Data, c.aesKey)
+		if err != nil {
+			log.L().Error("decrypt exception", log.Error(err))
+		}
 	}
 	if desc, ok := secret.Annotations[common.AnnotationDescription]; ok {
 		res.Description = desc
@@ -32,7 +42,12 @@ func (c *client) toSecretModel(secret *v1alpha1.Secret) *specV1.Secret {
 		res.UpdateTimestamp, _ = time.Parse(common.TimeFormat, us)
 	}
 	res.CreationTimestamp = secret.CreationTimestamp.Time.UTC()
-	res.Annotations = secret.Annotations
+	res.Annotations = map[string]string{}
+	for k, v := range secret.Annotations {
+		if k != annotationSecretEncoding {
+			res.Annotations[k] = v
+		}
+	}
 
 	return res
 }
@@ -63,16 +78,19 @@ func (c *client) fromSecretModel(secret *specV1.Secret) (*v1alpha1.Secret, error
 	if err != nil {
 		panic(fmt.Sprintf("copier exception: %s", err.Error()))
 	}
-	res.Data, err = EncryptMap(res.Data, c.aesKey)
-	if err != nil {
-		log.L().Error("encrypt exception", log.Error(err))
-	}
 	res.Annotations = map[string]string{}
 	if secret.Annotations != nil {
 		res.Annotations = secret.Annotations
 	}
 	res.Annotations[common.AnnotationDescription] = secret.Description
 	res.Annotations[common.AnnotationUpdateTimestamp] = secret.UpdateTimestamp.UTC().Format(common.TimeFormat)
+	// the annotations of the caller are not changed by the encoding
+	annotations := map[string]string{}
+	for k, v := range res.Annotations {
+		annotations[k] = v
+	}
+	annotations[annotationSecretEncoding] = secretEncodingNone
+	res.Annotations = annotations
 
 	return res, nil
 }
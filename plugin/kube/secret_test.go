@@ -132,6 +132,34 @@ func TestUpdateSecret(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestLegacySecret(t *testing.T) {
+	c := initSecretMapClient()
+	legacy := &v1alpha1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-legacy", Namespace: "default"},
+		Data:       map[string][]byte{"password": legacyEncrypt([]byte("pwd"), c.aesKey)},
+	}
+	_, err := c.customClient.CloudV1alpha1().Secrets("default").Create(legacy)
+	assert.NoError(t, err)
+
+	// the secret encrypted by the previous versions is decrypted
+	secret, err := c.GetSecret("default", "test-legacy", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "pwd", string(secret.Data["password"]))
+
+	// and stored as it is once updated
+	secret, err = c.UpdateSecret("default", secret)
+	assert.NoError(t, err)
+	assert.Equal(t, "pwd", string(secret.Data["password"]))
+	assert.NotContains(t, secret.Annotations, annotationSecretEncoding)
+	stored, err := c.customClient.CloudV1alpha1().Secrets("default").Get("test-legacy", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "pwd", string(stored.Data["password"]))
+	assert.Equal(t, secretEncodingNone, stored.Annotations[annotationSecretEncoding])
+	secret, err = c.GetSecret("default", "test-legacy", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "pwd", string(secret.Data["password"]))
+}
+
 func TestDeleteSecret(t *testing.T) {
 	c := initSecretMapClient()
 	err := c.DeleteSecret("default", "test-delete")
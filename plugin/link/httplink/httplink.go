@@ -38,6 +38,7 @@ func NewHTTPLink() (plugin.Plugin, error) {
 	}
 
 	router := gin.New()
+	router.Use(server.MetricsHandler("sync"))
 	svr := &http.Server{
 		Addr:           cfg.HTTPLink.Port,
 		Handler:        router,
@@ -112,6 +113,7 @@ func (l *httpLink) initRouter() {
 		sync := v1.Group("/sync")
 		sync.POST("/report", common.Wrapper(l.wrapper(specV1.MessageReport)))
 		sync.POST("/desire", common.Wrapper(l.wrapper(specV1.MessageDesire)))
+		sync.POST("/cmd", common.Wrapper(l.wrapper(specV1.MessageCMD)))
 	}
 }
 
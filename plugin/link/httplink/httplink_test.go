@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/server"
 )
@@ -131,3 +132,69 @@ func TestNewHTTPLink(t *testing.T) {
 	err = link.Close()
 	assert.NoError(t, err)
 }
+
+func TestHTTPLinkCommands(t *testing.T) {
+	cfg := &CloudConfig{}
+	common.SetConfFile(path.Join(genHTTPLinkConf(t), "config.yml"))
+	err := common.LoadConfig(cfg)
+	assert.NoError(t, err)
+
+	err = os.Setenv("HTTP_LINK_PORT", "9940")
+	assert.NoError(t, err)
+	defer os.Unsetenv("HTTP_LINK_PORT")
+
+	pl, err := NewHTTPLink()
+	assert.NoError(t, err)
+	link := pl.(plugin.SyncLink)
+
+	cmd := models.Command{ID: "c0", Namespace: "default", Node: "test", Type: "restart", State: models.CommandPending}
+	link.AddMsgRouter(string(specV1.MessageReport), server.HandlerMessage(func(m specV1.Message) (*specV1.Message, error) {
+		return &specV1.Message{Content: specV1.LazyValue{Value: specV1.Desire{}}}, nil
+	}))
+	link.AddMsgRouter(string(common.MessageCommands), server.HandlerMessage(func(m specV1.Message) (*specV1.Message, error) {
+		assert.Equal(t, "default", m.Metadata["namespace"])
+		assert.Equal(t, "test", m.Metadata["name"])
+		return &specV1.Message{Content: specV1.LazyValue{Value: []models.Command{cmd}}}, nil
+	}))
+	link.AddMsgRouter(string(specV1.MessageCMD), server.HandlerMessage(func(m specV1.Message) (*specV1.Message, error) {
+		var result models.CommandResult
+		assert.NoError(t, m.Content.Unmarshal(&result))
+		assert.Equal(t, "c0", result.ID)
+		assert.Equal(t, "test", m.Metadata["name"])
+		res := cmd
+		res.State, res.Result = models.CommandSucceeded, result.Result
+		return &specV1.Message{Content: specV1.LazyValue{Value: res}}, nil
+	}))
+
+	go link.Start()
+	defer link.Close()
+
+	cli := http.NewClient(&http.ClientOptions{
+		Address: "http://0.0.0.0:9940",
+	})
+	for {
+		_, err := cli.GetURL("http://0.0.0.0:9940/health")
+		if err == nil {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+
+	// the pending commands are returned along with the delta
+	resp, err := cli.PostJSON("v1/sync/report", []byte("{}"), map[string]string{"cn": "default.test"})
+	assert.NoError(t, err)
+	var delta struct {
+		Commands []models.Command `json:"commands"`
+	}
+	assert.NoError(t, json.Unmarshal(resp, &delta))
+	assert.Len(t, delta.Commands, 1)
+	assert.Equal(t, "c0", delta.Commands[0].ID)
+
+	// the result of the command is responded
+	resp, err = cli.PostJSON("v1/sync/cmd", []byte(`{"id":"c0","result":"ok"}`), map[string]string{"cn": "default.test"})
+	assert.NoError(t, err)
+	var res models.Command
+	assert.NoError(t, json.Unmarshal(resp, &res))
+	assert.Equal(t, models.CommandSucceeded, res.State)
+	assert.Equal(t, "ok", res.Result)
+}
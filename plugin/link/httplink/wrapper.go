@@ -3,9 +3,11 @@ package httplink
 import (
 	"strings"
 
+	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/link"
 	"github.com/baetyl/baetyl-cloud/v2/server"
 )
 
@@ -41,7 +43,7 @@ func (l *httpLink) wrapper(tp specV1.MessageKind) common.HandlerFunc {
 			if err != nil {
 				return nil, err
 			}
-			return resp.Content.Value, nil
+			return l.withCommands(ns, n, resp.Content.Value), nil
 		}
 	case specV1.MessageDesire:
 		return func(c *common.Context) (interface{}, error) {
@@ -75,8 +77,61 @@ func (l *httpLink) wrapper(tp specV1.MessageKind) common.HandlerFunc {
 			}
 			return resp.Content.Value, nil
 		}
+	case specV1.MessageCMD:
+		return func(c *common.Context) (interface{}, error) {
+			ns, n := c.GetNamespace(), c.GetName()
+			if ns == "" || n == "" {
+				return nil, common.Error(common.ErrRequestParamInvalid)
+			}
+			body, err := c.GetRawData()
+			if err != nil {
+				return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+			}
+
+			msg := specV1.Message{
+				Kind:     specV1.MessageCMD,
+				Content:  specV1.LazyValue{},
+				Metadata: map[string]string{},
+			}
+			err = msg.Content.UnmarshalJSON(body)
+			if err != nil {
+				return nil, err
+			}
+			msg.Metadata["name"] = n
+			msg.Metadata["namespace"] = ns
+			resp, err := l.msgRouter[string(specV1.MessageCMD)].(server.HandlerMessage)(msg)
+			if err != nil {
+				return nil, err
+			}
+			return resp.Content.Value, nil
+		}
 	}
 	return func(c *common.Context) (interface{}, error) {
 		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "messageType"))
 	}
 }
+
+// withCommands returns the pending commands along with the delta, since the commands can't be pushed over http
+func (l *httpLink) withCommands(ns, n string, value interface{}) interface{} {
+	delta, ok := value.(specV1.Desire)
+	if !ok {
+		return value
+	}
+	msgs, err := link.Commands(l.msgRouter, ns, n)
+	if err != nil {
+		log.L().Warn("failed to list the pending commands", log.Any("namespace", ns), log.Any("name", n), log.Error(err))
+		return value
+	}
+	if len(msgs) == 0 {
+		return value
+	}
+	cmds := make([]interface{}, 0, len(msgs))
+	for _, m := range msgs {
+		cmds = append(cmds, m.Content.Value)
+	}
+	if delta == nil {
+		delta = specV1.Desire{}
+	}
+	delta[common.DesiredCommands] = cmds
+	return delta
+}
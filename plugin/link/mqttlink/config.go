@@ -0,0 +1,20 @@
+package mqttlink
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+)
+
+type CloudConfig struct {
+	MQTTLink MQTTLinkConfig `yaml:"mqttlink" json:"mqttlink" default:"{\"port\":\":9008\",\"shutdownTime\":3000000000,\"sessionQueueSize\":100}"`
+}
+
+// MQTTLinkConfig the config of the embedded broker, nodes are authenticated by the common names of their certificates,
+// so the ca, cert and key are required
+type MQTTLinkConfig struct {
+	Port             string            `yaml:"port" json:"port" default:":9008"`
+	ShutdownTime     time.Duration     `yaml:"shutdownTime" json:"shutdownTime" default:"3s"`
+	SessionQueueSize int               `yaml:"sessionQueueSize" json:"sessionQueueSize" default:"100"`
+	Certificate      utils.Certificate `yaml:",inline" json:",inline"`
+}
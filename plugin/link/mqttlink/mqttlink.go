@@ -0,0 +1,420 @@
+package mqttlink
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/256dpi/gomqtt/broker"
+	"github.com/256dpi/gomqtt/packet"
+	"github.com/256dpi/gomqtt/transport"
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/baetyl/baetyl-go/v2/utils"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/link"
+	"github.com/baetyl/baetyl-cloud/v2/server"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+const (
+	MQTTLinkPort = "MQTT_LINK_PORT"
+
+	// TopicReport the topic published by nodes to report, the delta is responded to the delta topic of the node
+	TopicReport = "baetyl/sync/report"
+	// TopicDesire the topic published by nodes to request the desired resources,
+	// the resources are responded to the desire topic of the node
+	TopicDesire = "baetyl/sync/desire"
+	// TopicCommand the topic published by nodes to respond the results of the commands, nothing is responded
+	TopicCommand = "baetyl/sync/cmd"
+
+	// the prefix of the topics subscribed by a node, e.g. baetyl/sync/default/node01/
+	topicNodePrefix = "baetyl/sync/%s/%s/"
+	// the suffix of the topic to receive the deltas, which are pushed once the desire of the node is changed
+	topicSuffixDelta = "delta"
+	// the suffix of the topic to receive the desired resources
+	topicSuffixDesire = "desire"
+	// the suffix of the topic to receive the commands, the pending commands are pushed once it is subscribed
+	topicSuffixCommand = "cmd"
+)
+
+var (
+	ErrNodeUnauthorized = errors.New("failed to authenticate node")
+	ErrTopicNotAllowed  = errors.New("topic is not allowed")
+	ErrTLSRequired      = errors.New("mqttlink requires the ca, cert and key to verify the client certificates")
+)
+
+// node the node connected to the broker
+type node struct {
+	namespace string
+	name      string
+}
+
+func (n *node) id() string {
+	return n.namespace + "." + n.name
+}
+
+func (n *node) topic(suffix string) string {
+	return fmt.Sprintf(topicNodePrefix, n.namespace, n.name) + suffix
+}
+
+type mqttLink struct {
+	cfg       *CloudConfig
+	tls       *tls.Config
+	backend   *broker.MemoryBackend
+	engine    *broker.Engine
+	server    transport.Server
+	events    service.EventService
+	msgRouter map[string]interface{}
+	// the nodes by clients
+	nodes map[*broker.Client]*node
+	// the clients by node ids, to push the deltas
+	clients map[string]*broker.Client
+	watcher *link.EventWatcher
+	mu      sync.Mutex
+}
+
+func init() {
+	plugin.RegisterFactory("mqttlink", NewMQTTLink)
+}
+
+func NewMQTTLink() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, err
+	}
+	var cloudCfg config.CloudConfig
+	if err := common.LoadConfig(&cloudCfg); err != nil {
+		return nil, err
+	}
+	events, err := service.NewEventService(&cloudCfg)
+	if err != nil {
+		return nil, err
+	}
+	if port := os.Getenv(MQTTLinkPort); port != "" {
+		cfg.MQTTLink.Port = ":" + port
+	}
+	return newMQTTLink(&cfg, events)
+}
+
+func newMQTTLink(cfg *CloudConfig, events service.EventService) (*mqttLink, error) {
+	l := &mqttLink{
+		cfg:       cfg,
+		events:    events,
+		msgRouter: map[string]interface{}{},
+		nodes:     map[*broker.Client]*node{},
+		clients:   map[string]*broker.Client{},
+	}
+	// nodes are authenticated by their certificates only, the link never starts without tls
+	if cfg.MQTTLink.Certificate.Cert == "" ||
+		cfg.MQTTLink.Certificate.Key == "" ||
+		cfg.MQTTLink.Certificate.CA == "" {
+		return nil, ErrTLSRequired
+	}
+	t, err := utils.NewTLSConfigServer(utils.Certificate{
+		CA:             cfg.MQTTLink.Certificate.CA,
+		Cert:           cfg.MQTTLink.Certificate.Cert,
+		Key:            cfg.MQTTLink.Certificate.Key,
+		ClientAuthType: tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		return nil, err
+	}
+	l.tls = t
+	l.watcher = link.NewEventWatcher(events, l)
+	l.backend = broker.NewMemoryBackend()
+	l.backend.SessionQueueSize = cfg.MQTTLink.SessionQueueSize
+	l.engine = broker.NewEngine(&linkBackend{MemoryBackend: l.backend, link: l})
+	l.engine.OnError = func(err error) {
+		log.L().Info("sync server mqtt stopped", log.Error(err))
+	}
+	return l, nil
+}
+
+func (l *mqttLink) Start() {
+	if err := l.listen(); err != nil {
+		log.L().Error("failed to start sync server mqtt", log.Error(err))
+	}
+}
+
+func (l *mqttLink) listen() error {
+	var err error
+	l.server, err = transport.CreateSecureNetServer(l.cfg.MQTTLink.Port, l.tls)
+	if err != nil {
+		return err
+	}
+	l.engine.Accept(l.server)
+	return nil
+}
+
+func (l *mqttLink) AddMsgRouter(k string, v interface{}) {
+	l.msgRouter[k] = v
+}
+
+func (l *mqttLink) Close() error {
+	// the engine blocks on closing if it never accepts connections
+	if l.server != nil {
+		l.server.Close()
+		l.engine.Close()
+	}
+	l.backend.Close(l.cfg.MQTTLink.ShutdownTime)
+	l.watcher.Close()
+	return nil
+}
+
+// commonName returns the common name of the verified client certificate, the username is never trusted
+func (l *mqttLink) commonName(client *broker.Client) string {
+	nc, ok := client.Conn().(*transport.NetConn)
+	if !ok {
+		return ""
+	}
+	tc, ok := nc.UnderlyingConn().(*tls.Conn)
+	if !ok {
+		return ""
+	}
+	state := tc.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return ""
+	}
+	return state.PeerCertificates[0].Subject.CommonName
+}
+
+// register keeps the node of the client, and watches the node events of its namespace
+func (l *mqttLink) register(client *broker.Client, n *node) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.watcher.Watch(n.namespace); err != nil {
+		return err
+	}
+	l.nodes[client] = n
+	l.clients[n.id()] = client
+	return nil
+}
+
+func (l *mqttLink) unregister(client *broker.Client) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n, ok := l.nodes[client]
+	if !ok {
+		return
+	}
+	delete(l.nodes, client)
+	// the client may be replaced by the reconnected one
+	if l.clients[n.id()] == client {
+		delete(l.clients, n.id())
+	}
+	l.watcher.Unwatch(n.namespace)
+}
+
+func (l *mqttLink) getNode(client *broker.Client) *node {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.nodes[client]
+}
+
+func (l *mqttLink) connected(n *node) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.clients[n.id()]
+	return ok
+}
+
+func (l *mqttLink) PushDelta(namespace, name string) {
+	n := &node{namespace: namespace, name: name}
+	if l.connected(n) {
+		l.push(n)
+	}
+}
+
+func (l *mqttLink) PushCommand(namespace, name string, cmd *models.Command) {
+	n := &node{namespace: namespace, name: name}
+	if l.connected(n) {
+		l.pushCommand(n, link.CommandMessage(cmd))
+	}
+}
+
+// push sends the delta to the node, nothing is sent if the desire is already reported
+func (l *mqttLink) push(n *node) {
+	resp, err := link.Delta(l.msgRouter, n.namespace, n.name)
+	if err != nil {
+		log.L().Error("failed to get node delta",
+			log.Any(common.KeyContextNamespace, n.namespace),
+			log.Any("name", n.name),
+			log.Error(err))
+		return
+	}
+	if resp == nil {
+		return
+	}
+	if err = l.send(nil, n, topicSuffixDelta, resp, 0); err != nil {
+		log.L().Error("failed to push node delta",
+			log.Any(common.KeyContextNamespace, n.namespace),
+			log.Any("name", n.name),
+			log.Error(err))
+	}
+}
+
+// resume sends the pending commands to the node
+func (l *mqttLink) resume(n *node) {
+	msgs, err := link.Commands(l.msgRouter, n.namespace, n.name)
+	if err != nil {
+		log.L().Error("failed to get node commands",
+			log.Any(common.KeyContextNamespace, n.namespace),
+			log.Any("name", n.name),
+			log.Error(err))
+		return
+	}
+	for _, msg := range msgs {
+		l.pushCommand(n, msg)
+	}
+}
+
+func (l *mqttLink) pushCommand(n *node, msg *specV1.Message) {
+	if err := l.send(nil, n, topicSuffixCommand, msg, packet.QOSAtLeastOnce); err != nil {
+		log.L().Error("failed to push node command",
+			log.Any(common.KeyContextNamespace, n.namespace),
+			log.Any("name", n.name),
+			log.Any("command", msg.Metadata["commandId"]),
+			log.Error(err))
+	}
+}
+
+// handle routes the message of the node, and sends the response to the node if the topic has one
+func (l *mqttLink) handle(client *broker.Client, n *node, pkt *packet.Message) error {
+	var kind specV1.MessageKind
+	var suffix string
+	switch pkt.Topic {
+	case TopicReport:
+		kind, suffix = specV1.MessageReport, topicSuffixDelta
+	case TopicDesire:
+		kind, suffix = specV1.MessageDesire, topicSuffixDesire
+	case TopicCommand:
+		kind = specV1.MessageCMD
+	default:
+		return ErrTopicNotAllowed
+	}
+	msg := specV1.Message{
+		Kind:    kind,
+		Content: specV1.LazyValue{},
+		Metadata: map[string]string{
+			"namespace": n.namespace,
+			"name":      n.name,
+		},
+	}
+	if host, _, err := net.SplitHostPort(client.Conn().RemoteAddr().String()); err == nil {
+		msg.Metadata["address"] = host
+	}
+	if err := msg.Content.UnmarshalJSON(pkt.Payload); err != nil {
+		log.L().Error("failed to decode node message", log.Any("topic", pkt.Topic), log.Any("node", n.id()), log.Error(err))
+		return nil
+	}
+	resp, err := l.msgRouter[string(kind)].(server.HandlerMessage)(msg)
+	if err != nil {
+		log.L().Error("failed to handle node message", log.Any("topic", pkt.Topic), log.Any("node", n.id()), log.Error(err))
+		return nil
+	}
+	if suffix == "" {
+		return nil
+	}
+	return l.send(client, n, suffix, resp, pkt.QOS)
+}
+
+// send publishes the content of the message to the topic of the node,
+// the client is the publisher which is nil if the message is pushed by the cloud
+func (l *mqttLink) send(client *broker.Client, n *node, suffix string, msg *specV1.Message, qos packet.QOS) error {
+	payload, err := msg.Content.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return l.backend.Publish(client, &packet.Message{
+		Topic:   n.topic(suffix),
+		Payload: payload,
+		QOS:     qos,
+	}, nil)
+}
+
+// linkBackend authenticates the nodes, restricts the topics of nodes and handles the messages published by nodes,
+// the messages of nodes are never forwarded to other nodes
+type linkBackend struct {
+	*broker.MemoryBackend
+	link *mqttLink
+}
+
+func (b *linkBackend) Authenticate(client *broker.Client, user, password string) (bool, error) {
+	ok, err := b.MemoryBackend.Authenticate(client, user, password)
+	if !ok || err != nil {
+		return ok, err
+	}
+	cn := b.link.commonName(client)
+	ns, name, ok := server.ParseNodeCommonName(cn)
+	if !ok {
+		log.L().Error("extract node common name error", log.Any("commonName", cn))
+		return false, nil
+	}
+	if err = b.link.register(client, &node{namespace: ns, name: name}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Setup uses the node as the client id, so that the session of a node is never taken over by others
+func (b *linkBackend) Setup(client *broker.Client, _ string, clean bool) (broker.Session, bool, error) {
+	n := b.link.getNode(client)
+	if n == nil {
+		return nil, false, ErrNodeUnauthorized
+	}
+	sess, resumed, err := b.MemoryBackend.Setup(client, n.id(), clean)
+	if err != nil {
+		b.link.unregister(client)
+	}
+	return sess, resumed, err
+}
+
+func (b *linkBackend) Subscribe(client *broker.Client, subs []packet.Subscription, ack broker.Ack) error {
+	n := b.link.getNode(client)
+	if n == nil {
+		return ErrNodeUnauthorized
+	}
+	prefix := n.topic("")
+	resume := false
+	for _, sub := range subs {
+		if !strings.HasPrefix(sub.Topic, prefix) {
+			return ErrTopicNotAllowed
+		}
+		resume = resume || sub.Topic == n.topic(topicSuffixCommand) || sub.Topic == n.topic("#") || sub.Topic == n.topic("+")
+	}
+	if err := b.MemoryBackend.Subscribe(client, subs, ack); err != nil {
+		return err
+	}
+	if resume {
+		go b.link.resume(n)
+	}
+	return nil
+}
+
+func (b *linkBackend) Publish(client *broker.Client, msg *packet.Message, ack broker.Ack) error {
+	n := b.link.getNode(client)
+	if n == nil {
+		return ErrNodeUnauthorized
+	}
+	if err := b.link.handle(client, n, msg); err != nil {
+		return err
+	}
+	if ack != nil {
+		ack()
+	}
+	return nil
+}
+
+func (b *linkBackend) Terminate(client *broker.Client) error {
+	b.link.unregister(client)
+	return b.MemoryBackend.Terminate(client)
+}
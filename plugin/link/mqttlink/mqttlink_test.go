@@ -0,0 +1,337 @@
+package mqttlink
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/256dpi/gomqtt/client"
+	"github.com/256dpi/gomqtt/packet"
+	"github.com/256dpi/gomqtt/transport"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/pubsub"
+	"github.com/baetyl/baetyl-cloud/v2/server"
+)
+
+const waitTimeout = 3 * time.Second
+
+// testPKI signs the certificates of the link and the nodes
+type testPKI struct {
+	dir    string
+	caCert *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	dir, err := ioutil.TempDir("", "mqttlink")
+	assert.NoError(t, err)
+	caCert, caKey := genCert(t, dir, "ca", "root.ca", nil, nil)
+	genCert(t, dir, "server", "baetyl-cloud", caCert, caKey)
+	return &testPKI{dir: dir, caCert: caCert, caKey: caKey}
+}
+
+func (p *testPKI) config(cfg *CloudConfig) {
+	cfg.MQTTLink.Certificate.CA = path.Join(p.dir, "ca.pem")
+	cfg.MQTTLink.Certificate.Cert = path.Join(p.dir, "server.pem")
+	cfg.MQTTLink.Certificate.Key = path.Join(p.dir, "server.key")
+}
+
+// dialer returns the dialer with the client certificate of the common name, or without certificate if cn is empty
+func (p *testPKI) dialer(t *testing.T, cn string) client.Dialer {
+	pool := x509.NewCertPool()
+	pool.AddCert(p.caCert)
+	tc := &tls.Config{RootCAs: pool, ServerName: "localhost"}
+	if cn != "" {
+		genCert(t, p.dir, cn, cn, p.caCert, p.caKey)
+		keyPair, err := tls.LoadX509KeyPair(path.Join(p.dir, cn+".pem"), path.Join(p.dir, cn+".key"))
+		assert.NoError(t, err)
+		tc.Certificates = []tls.Certificate{keyPair}
+	}
+	return transport.NewDialer(transport.DialConfig{TLSConfig: tc})
+}
+
+func initMQTTLink(t *testing.T, p *testPKI) (*mqttLink, *ms.MockEventService, chan interface{}, *gomock.Controller) {
+	mockCtl := gomock.NewController(t)
+	mEvent := ms.NewMockEventService(mockCtl)
+	events := make(chan interface{}, 10)
+	mEvent.EXPECT().SubscribeNodeEvent("default").Return(events, nil).AnyTimes()
+	mEvent.EXPECT().UnsubscribeNodeEvent("default", gomock.Any()).Return(nil).AnyTimes()
+
+	cfg := &CloudConfig{}
+	p.config(cfg)
+	cfg.MQTTLink.Port = "127.0.0.1:0"
+	cfg.MQTTLink.SessionQueueSize = 10
+	cfg.MQTTLink.ShutdownTime = time.Second
+	link, err := newMQTTLink(cfg, mEvent)
+	assert.NoError(t, err)
+
+	link.AddMsgRouter(string(specV1.MessageReport), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		assert.Equal(t, "default", msg.Metadata["namespace"])
+		assert.Equal(t, "127.0.0.1", msg.Metadata["address"])
+		var report specV1.Report
+		assert.NoError(t, msg.Content.Unmarshal(&report))
+		assert.Equal(t, "v1", report["version"])
+		return &specV1.Message{Content: specV1.LazyValue{Value: specV1.Desire{"version": "v2"}}}, nil
+	}))
+	link.AddMsgRouter(string(specV1.MessageDesire), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		var req specV1.DesireRequest
+		assert.NoError(t, msg.Content.Unmarshal(&req))
+		values := []specV1.ResourceValue{{ResourceInfo: req.Infos[0]}}
+		return &specV1.Message{Content: specV1.LazyValue{Value: specV1.DesireResponse{Values: values}}}, nil
+	}))
+	link.AddMsgRouter(string(common.MessageDelta), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		assert.Equal(t, "default", msg.Metadata["namespace"])
+		return &specV1.Message{Content: specV1.LazyValue{Value: specV1.Desire{"version": "v3"}}}, nil
+	}))
+	link.AddMsgRouter(string(specV1.MessageCMD), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		assert.Equal(t, "node01", msg.Metadata["name"])
+		var result models.CommandResult
+		assert.NoError(t, msg.Content.Unmarshal(&result))
+		assert.Equal(t, "c0", result.ID)
+		return &specV1.Message{Content: specV1.LazyValue{Value: &models.Command{ID: "c0", State: models.CommandSucceeded}}}, nil
+	}))
+	link.AddMsgRouter(string(common.MessageCommands), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		// only node01 has the pending command
+		var cmds []models.Command
+		if msg.Metadata["name"] == "node01" {
+			cmds = append(cmds, models.Command{ID: "c0", Namespace: "default", Node: "node01", Type: models.CommandNodeInfo})
+		}
+		return &specV1.Message{Content: specV1.LazyValue{Value: cmds}}, nil
+	}))
+	assert.NoError(t, link.listen())
+	return link, mEvent, events, mockCtl
+}
+
+func connect(t *testing.T, url string, dialer client.Dialer) (*client.Client, chan *packet.Message, chan error) {
+	c := client.New()
+	msgs := make(chan *packet.Message, 10)
+	errs := make(chan error, 10)
+	c.Callback = func(msg *packet.Message, err error) error {
+		if err != nil {
+			errs <- err
+			return nil
+		}
+		msgs <- msg
+		return nil
+	}
+	cfg := client.NewConfig(url)
+	cfg.Dialer = dialer
+	cf, err := c.Connect(cfg)
+	assert.NoError(t, err)
+	if err = cf.Wait(waitTimeout); err != nil {
+		return nil, nil, errs
+	}
+	return c, msgs, errs
+}
+
+func receive(t *testing.T, msgs chan *packet.Message) *packet.Message {
+	select {
+	case msg := <-msgs:
+		return msg
+	case <-time.After(waitTimeout):
+		assert.FailNow(t, "no message received")
+	}
+	return nil
+}
+
+func TestNewMQTTLink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mqttlink")
+	assert.NoError(t, err)
+	conf := path.Join(dir, "config.yml")
+	assert.NoError(t, ioutil.WriteFile(conf, []byte("mqttlink:\n  sessionQueueSize: 20\n"), 0600))
+	common.SetConfFile(conf)
+	assert.NoError(t, os.Setenv(MQTTLinkPort, "9938"))
+	defer os.Unsetenv(MQTTLinkPort)
+
+	// the link never starts without tls
+	_, err = NewMQTTLink()
+	assert.Equal(t, ErrTLSRequired, err)
+
+	p := newTestPKI(t)
+	assert.NoError(t, ioutil.WriteFile(conf, []byte("mqttlink:\n  sessionQueueSize: 20\n  ca: "+path.Join(p.dir, "ca.pem")+
+		"\n  cert: "+path.Join(p.dir, "server.pem")+"\n  key: "+path.Join(p.dir, "server.key")+"\n"), 0600))
+	pl, err := NewMQTTLink()
+	assert.NoError(t, err)
+	link := pl.(*mqttLink)
+	assert.Equal(t, ":9938", link.cfg.MQTTLink.Port)
+	assert.Equal(t, 20, link.backend.SessionQueueSize)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, link.tls.ClientAuth)
+	assert.NoError(t, link.Close())
+}
+
+func TestMQTTLink(t *testing.T) {
+	p := newTestPKI(t)
+	link, _, events, mockCtl := initMQTTLink(t, p)
+	defer mockCtl.Finish()
+	defer link.Close()
+	addr := link.server.Addr().String()
+
+	// the common name is not a node
+	c, _, _ := connect(t, "tls://"+addr, p.dialer(t, "bad"))
+	assert.Nil(t, c)
+
+	c, msgs, _ := connect(t, "tls://"+addr, p.dialer(t, "default.node01"))
+	assert.NotNil(t, c)
+	defer c.Disconnect()
+	sf, err := c.Subscribe("baetyl/sync/default/node01/#", packet.QOSAtLeastOnce)
+	assert.NoError(t, err)
+	assert.NoError(t, sf.Wait(waitTimeout))
+
+	// the pending commands are pushed once subscribed
+	msg := receive(t, msgs)
+	assert.Equal(t, "baetyl/sync/default/node01/cmd", msg.Topic)
+	var cmd models.Command
+	assert.NoError(t, json.Unmarshal(msg.Payload, &cmd))
+	assert.Equal(t, "c0", cmd.ID)
+	assert.Equal(t, models.CommandNodeInfo, cmd.Type)
+
+	// the result of the command is not responded
+	pf, err := c.Publish(TopicCommand, []byte(`{"id":"c0","result":{"os":"linux"}}`), packet.QOSAtLeastOnce, false)
+	assert.NoError(t, err)
+	assert.NoError(t, pf.Wait(waitTimeout))
+
+	// report
+	pf, err = c.Publish(TopicReport, []byte(`{"version":"v1"}`), packet.QOSAtLeastOnce, false)
+	assert.NoError(t, err)
+	assert.NoError(t, pf.Wait(waitTimeout))
+	msg = receive(t, msgs)
+	assert.Equal(t, "baetyl/sync/default/node01/delta", msg.Topic)
+	assert.JSONEq(t, `{"version":"v2"}`, string(msg.Payload))
+
+	// desire
+	req, _ := json.Marshal(specV1.DesireRequest{Infos: []specV1.ResourceInfo{{Kind: specV1.KindApplication, Name: "app01", Version: "1"}}})
+	pf, err = c.Publish(TopicDesire, req, packet.QOSAtMostOnce, false)
+	assert.NoError(t, err)
+	assert.NoError(t, pf.Wait(waitTimeout))
+	msg = receive(t, msgs)
+	assert.Equal(t, "baetyl/sync/default/node01/desire", msg.Topic)
+	var res specV1.DesireResponse
+	assert.NoError(t, json.Unmarshal(msg.Payload, &res))
+	assert.Len(t, res.Values, 1)
+	assert.Equal(t, "app01", res.Values[0].Name)
+
+	// the delta is pushed once the desire is changed, the events of other nodes are ignored
+	events <- &models.NodeEvent{Type: models.NodeEventDesire, Namespace: "default", Name: "node02"}
+	events <- &models.NodeEvent{Type: models.NodeEventReport, Namespace: "default", Name: "node01"}
+	events <- &models.NodeEvent{Type: models.NodeEventDesire, Namespace: "default", Name: "node01"}
+	msg = receive(t, msgs)
+	assert.Equal(t, "baetyl/sync/default/node01/delta", msg.Topic)
+	assert.JSONEq(t, `{"version":"v3"}`, string(msg.Payload))
+
+	// the command is pushed once queued
+	events <- &models.NodeEvent{Type: models.NodeEventCommand, Namespace: "default", Name: "node01",
+		Command: &models.Command{ID: "c1", Namespace: "default", Node: "node01", Type: models.CommandTailLogs}}
+	msg = receive(t, msgs)
+	assert.Equal(t, "baetyl/sync/default/node01/cmd", msg.Topic)
+	assert.NoError(t, json.Unmarshal(msg.Payload, &cmd))
+	assert.Equal(t, "c1", cmd.ID)
+
+	// nodes cannot subscribe the topics of others
+	c2, _, errs := connect(t, "tls://"+addr, p.dialer(t, "default.node02"))
+	assert.NotNil(t, c2)
+	_, err = c2.Subscribe("baetyl/sync/default/node01/#", packet.QOSAtLeastOnce)
+	assert.NoError(t, err)
+	select {
+	case <-errs:
+	case <-time.After(waitTimeout):
+		assert.FailNow(t, "the client is not closed")
+	}
+
+	// nodes cannot publish to other topics
+	c3, _, errs := connect(t, "tls://"+addr, p.dialer(t, "default.node03"))
+	assert.NotNil(t, c3)
+	_, err = c3.Publish("baetyl/sync/default/node01/delta", []byte("{}"), packet.QOSAtMostOnce, false)
+	assert.NoError(t, err)
+	select {
+	case <-errs:
+	case <-time.After(waitTimeout):
+		assert.FailNow(t, "the client is not closed")
+	}
+}
+
+func TestMQTTLinkCertificate(t *testing.T) {
+	p := newTestPKI(t)
+	link, _, _, mockCtl := initMQTTLink(t, p)
+	defer mockCtl.Finish()
+	defer link.Close()
+	addr := link.server.Addr().String()
+
+	// the username is ignored, the node is authenticated by the certificate
+	c, msgs, _ := connect(t, "tls://default.other@"+addr, p.dialer(t, "default.client"))
+	assert.NotNil(t, c)
+	defer c.Disconnect()
+	sf, err := c.Subscribe("baetyl/sync/default/client/#", packet.QOSAtLeastOnce)
+	assert.NoError(t, err)
+	assert.NoError(t, sf.Wait(waitTimeout))
+	pf, err := c.Publish(TopicReport, []byte(`{"version":"v1"}`), packet.QOSAtLeastOnce, false)
+	assert.NoError(t, err)
+	assert.NoError(t, pf.Wait(waitTimeout))
+	msg := receive(t, msgs)
+	assert.Equal(t, "baetyl/sync/default/client/delta", msg.Topic)
+
+	// the client without certificate is rejected even if the username is a node
+	c, _, _ = connect(t, "tls://default.client@"+addr, p.dialer(t, ""))
+	assert.Nil(t, c)
+
+	// the certificate signed by another ca is rejected
+	other := newTestPKI(t)
+	genCert(t, other.dir, "default.client", "default.client", other.caCert, other.caKey)
+	keyPair, err := tls.LoadX509KeyPair(path.Join(other.dir, "default.client.pem"), path.Join(other.dir, "default.client.key"))
+	assert.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(p.caCert)
+	dialer := transport.NewDialer(transport.DialConfig{TLSConfig: &tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{keyPair},
+		ServerName:   "localhost",
+	}})
+	c, _, _ = connect(t, "tls://"+addr, dialer)
+	assert.Nil(t, c)
+}
+
+// genCert generates the certificate signed by the parent, or the self-signed ca if the parent is nil
+func genCert(t *testing.T, dir, name, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+	}
+	if parent == nil {
+		tpl.IsCA = true
+		tpl.BasicConstraintsValid = true
+		tpl.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = tpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, ioutil.WriteFile(path.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return cert, key
+}
@@ -0,0 +1,175 @@
+// Package link provides the helpers shared by the sync links which keep connections with nodes
+package link
+
+import (
+	"sync"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/server"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// EventHandler pushes the changes to the connected nodes
+type EventHandler interface {
+	// PushDelta pushes the delta once the desire of the node is changed by NodeService.UpdateDesire
+	PushDelta(namespace, name string)
+	// PushCommand pushes the command once it is queued by CommandService.Create
+	PushCommand(namespace, name string, cmd *models.Command)
+}
+
+// EventWatcher watches the node events of the namespaces with connected nodes
+type EventWatcher struct {
+	events  service.EventService
+	handler EventHandler
+	// the watches by namespace, counted by the connected nodes
+	watches map[string]*watch
+	mu      sync.Mutex
+}
+
+type watch struct {
+	count int
+	quit  chan struct{}
+}
+
+// NewEventWatcher create a watcher calling the handler with the events of the connected nodes
+func NewEventWatcher(events service.EventService, handler EventHandler) *EventWatcher {
+	return &EventWatcher{
+		events:  events,
+		handler: handler,
+		watches: map[string]*watch{},
+	}
+}
+
+// Watch watches the namespace once a node of the namespace is connected
+func (w *EventWatcher) Watch(namespace string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if wa, ok := w.watches[namespace]; ok {
+		wa.count++
+		return nil
+	}
+	ch, err := w.events.SubscribeNodeEvent(namespace)
+	if err != nil {
+		return err
+	}
+	wa := &watch{count: 1, quit: make(chan struct{})}
+	w.watches[namespace] = wa
+	go w.run(namespace, ch, wa.quit)
+	return nil
+}
+
+// Unwatch stops watching the namespace once no node of the namespace is connected
+func (w *EventWatcher) Unwatch(namespace string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wa, ok := w.watches[namespace]
+	if !ok {
+		return
+	}
+	wa.count--
+	if wa.count > 0 {
+		return
+	}
+	close(wa.quit)
+	delete(w.watches, namespace)
+}
+
+// Close stops watching all namespaces
+func (w *EventWatcher) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ns, wa := range w.watches {
+		close(wa.quit)
+		delete(w.watches, ns)
+	}
+}
+
+func (w *EventWatcher) run(namespace string, ch chan interface{}, quit chan struct{}) {
+	defer func() {
+		if err := w.events.UnsubscribeNodeEvent(namespace, ch); err != nil {
+			log.L().Warn("failed to unsubscribe node events", log.Any(common.KeyContextNamespace, namespace), log.Error(err))
+		}
+	}()
+	for {
+		select {
+		case <-quit:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			event, err := service.DecodeNodeEvent(msg)
+			if err != nil {
+				log.L().Warn("failed to decode node event", log.Any(common.KeyContextNamespace, namespace), log.Error(err))
+				continue
+			}
+			switch event.Type {
+			case models.NodeEventDesire:
+				w.handler.PushDelta(namespace, event.Name)
+			case models.NodeEventCommand:
+				if event.Command != nil {
+					w.handler.PushCommand(namespace, event.Name, event.Command)
+				}
+			}
+		}
+	}
+}
+
+// Delta returns the delta message of the node by the delta router of the link,
+// nil is returned if the desire of the node is already reported
+func Delta(router map[string]interface{}, namespace, name string) (*specV1.Message, error) {
+	handler, ok := router[string(common.MessageDelta)].(server.HandlerMessage)
+	if !ok {
+		return nil, common.Error(common.ErrRequestMethodNotFound)
+	}
+	msg := specV1.Message{
+		Kind:     common.MessageDelta,
+		Metadata: map[string]string{"namespace": namespace, "name": name},
+	}
+	resp, err := handler(msg)
+	if err != nil {
+		return nil, err
+	}
+	if delta, ok := resp.Content.Value.(specV1.Desire); ok && len(delta) == 0 {
+		return nil, nil
+	}
+	return resp, nil
+}
+
+// Commands returns the messages of the pending commands of the node by the commands router of the link
+func Commands(router map[string]interface{}, namespace, name string) ([]*specV1.Message, error) {
+	handler, ok := router[string(common.MessageCommands)].(server.HandlerMessage)
+	if !ok {
+		return nil, common.Error(common.ErrRequestMethodNotFound)
+	}
+	resp, err := handler(specV1.Message{
+		Kind:     common.MessageCommands,
+		Metadata: map[string]string{"namespace": namespace, "name": name},
+	})
+	if err != nil {
+		return nil, err
+	}
+	cmds, _ := resp.Content.Value.([]models.Command)
+	msgs := make([]*specV1.Message, 0, len(cmds))
+	for i := range cmds {
+		msgs = append(msgs, CommandMessage(&cmds[i]))
+	}
+	return msgs, nil
+}
+
+// CommandMessage returns the message delivering the command to the node
+func CommandMessage(cmd *models.Command) *specV1.Message {
+	return &specV1.Message{
+		Kind: specV1.MessageCMD,
+		Metadata: map[string]string{
+			"namespace": cmd.Namespace,
+			"name":      cmd.Node,
+			"commandId": cmd.ID,
+		},
+		Content: specV1.LazyValue{Value: cmd},
+	}
+}
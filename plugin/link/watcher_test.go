@@ -0,0 +1,131 @@
+package link
+
+import (
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/server"
+)
+
+type testHandler struct {
+	pushed chan string
+}
+
+func (h *testHandler) PushDelta(namespace, name string) {
+	h.pushed <- "delta:" + namespace + "/" + name
+}
+
+func (h *testHandler) PushCommand(namespace, name string, cmd *models.Command) {
+	h.pushed <- "cmd:" + namespace + "/" + name + "/" + cmd.ID
+}
+
+func TestEventWatcher(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mEvent := ms.NewMockEventService(mockCtl)
+	events := make(chan interface{}, 10)
+	unsubscribed := make(chan struct{})
+	mEvent.EXPECT().SubscribeNodeEvent("default").Return(events, nil).Times(1)
+	mEvent.EXPECT().UnsubscribeNodeEvent("default", gomock.Any()).DoAndReturn(func(string, chan interface{}) error {
+		close(unsubscribed)
+		return nil
+	}).Times(1)
+	mEvent.EXPECT().SubscribeNodeEvent("other").Return(nil, common.Error(common.ErrRequestParamInvalid)).Times(1)
+
+	pushed := make(chan string, 10)
+	w := NewEventWatcher(mEvent, &testHandler{pushed: pushed})
+	assert.NoError(t, w.Watch("default"))
+	assert.NoError(t, w.Watch("default"))
+	assert.Error(t, w.Watch("other"))
+
+	events <- &models.NodeEvent{Type: models.NodeEventReport, Namespace: "default", Name: "n0"}
+	events <- []byte("{")
+	events <- []byte(`{"type":"desire","namespace":"default","name":"n1"}`)
+	events <- &models.NodeEvent{Type: models.NodeEventCommand, Namespace: "default", Name: "n1"}
+	events <- &models.NodeEvent{Type: models.NodeEventCommand, Namespace: "default", Name: "n1", Command: &models.Command{ID: "c0"}}
+	for _, expected := range []string{"delta:default/n1", "cmd:default/n1/c0"} {
+		select {
+		case id := <-pushed:
+			assert.Equal(t, expected, id)
+		case <-time.After(time.Second):
+			assert.FailNow(t, "no event pushed")
+		}
+	}
+
+	// the namespace is still watched by the other node
+	w.Unwatch("default")
+	w.Unwatch("other")
+	events <- &models.NodeEvent{Type: models.NodeEventDesire, Namespace: "default", Name: "n2"}
+	select {
+	case id := <-pushed:
+		assert.Equal(t, "delta:default/n2", id)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no desire event pushed")
+	}
+
+	w.Unwatch("default")
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		assert.FailNow(t, "node events not unsubscribed")
+	}
+	w.Close()
+}
+
+func TestDelta(t *testing.T) {
+	router := map[string]interface{}{}
+	_, err := Delta(router, "default", "n0")
+	assert.Error(t, err)
+
+	var delta specV1.Desire
+	router[string(common.MessageDelta)] = server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		assert.Equal(t, "default", msg.Metadata["namespace"])
+		if msg.Metadata["name"] == "bad" {
+			return nil, common.Error(common.ErrResourceNotFound, common.Field("name", "bad"))
+		}
+		return &specV1.Message{Kind: common.MessageDelta, Content: specV1.LazyValue{Value: delta}}, nil
+	})
+	_, err = Delta(router, "default", "bad")
+	assert.Error(t, err)
+
+	delta = specV1.Desire{}
+	msg, err := Delta(router, "default", "n0")
+	assert.NoError(t, err)
+	assert.Nil(t, msg)
+
+	delta = specV1.Desire{"apps": []interface{}{}}
+	msg, err = Delta(router, "default", "n0")
+	assert.NoError(t, err)
+	assert.Equal(t, common.MessageDelta, msg.Kind)
+	assert.Equal(t, delta, msg.Content.Value)
+}
+
+func TestCommands(t *testing.T) {
+	router := map[string]interface{}{}
+	_, err := Commands(router, "default", "n0")
+	assert.Error(t, err)
+
+	router[string(common.MessageCommands)] = server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		if msg.Metadata["name"] == "bad" {
+			return nil, common.Error(common.ErrDatabase)
+		}
+		cmds := []models.Command{{ID: "c0", Namespace: "default", Node: "n0", Type: models.CommandNodeInfo}}
+		return &specV1.Message{Kind: common.MessageCommands, Content: specV1.LazyValue{Value: cmds}}, nil
+	})
+	_, err = Commands(router, "default", "bad")
+	assert.Error(t, err)
+
+	msgs, err := Commands(router, "default", "n0")
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, specV1.MessageCMD, msgs[0].Kind)
+	assert.Equal(t, map[string]string{"namespace": "default", "name": "n0", "commandId": "c0"}, msgs[0].Metadata)
+	assert.Equal(t, "c0", msgs[0].Content.Value.(*models.Command).ID)
+}
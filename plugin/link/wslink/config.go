@@ -0,0 +1,25 @@
+package wslink
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/link/httplink"
+)
+
+type CloudConfig struct {
+	// HTTPLink the tls setup of httplink is used if the certificate of wslink is not configured
+	HTTPLink httplink.HTTPLinkConfig `yaml:"httplink" json:"httpLink" default:"{\"port\":\":9005\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"commonName\":\"common-name\"}"`
+	WSLink   WSLinkConfig            `yaml:"wslink" json:"wslink" default:"{\"port\":\":9009\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"pingInterval\":20000000000,\"pongTimeout\":60000000000,\"maxMessageSize\":4194304,\"sendQueueSize\":16}"`
+}
+
+// WSLinkConfig the config of the websocket server, each node keeps a long-lived websocket,
+// which is closed if no pong or message is received from the node within the pong timeout
+type WSLinkConfig struct {
+	config.Server  `yaml:",inline" json:",inline"`
+	CommonName     string        `yaml:"commonName" json:"commonName"`
+	PingInterval   time.Duration `yaml:"pingInterval" json:"pingInterval" default:"20s"`
+	PongTimeout    time.Duration `yaml:"pongTimeout" json:"pongTimeout" default:"60s"`
+	MaxMessageSize int64         `yaml:"maxMessageSize" json:"maxMessageSize" default:"4194304"`
+	SendQueueSize  int           `yaml:"sendQueueSize" json:"sendQueueSize" default:"16"`
+}
@@ -0,0 +1,456 @@
+package wslink
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/link"
+	"github.com/baetyl/baetyl-cloud/v2/server"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+const (
+	WSLinkPort = "WS_LINK_PORT"
+
+	// MessageError the kind of the message responded if the message of the node fails to be handled,
+	// the content contains the code and the message of the error
+	MessageError specV1.MessageKind = "error"
+)
+
+var (
+	ErrConnClosed = errors.New("websocket is closed")
+	ErrConnBusy   = errors.New("websocket send queue is full")
+)
+
+type node struct {
+	namespace string
+	name      string
+	address   string
+}
+
+func (n *node) id() string {
+	return n.namespace + "/" + n.name
+}
+
+// conn the websocket of a node, all frames are written by the writing goroutine
+type conn struct {
+	node *node
+	ws   *websocket.Conn
+	send chan *specV1.Message
+	quit chan struct{}
+	once sync.Once
+}
+
+// write queues the message without blocking, the slow websocket is closed if its send queue is full,
+// the node resumes from the delta and the pending commands once it is reconnected
+func (c *conn) write(msg *specV1.Message) error {
+	select {
+	case <-c.quit:
+		return ErrConnClosed
+	default:
+	}
+	select {
+	case c.send <- msg:
+		return nil
+	default:
+		c.close()
+		return ErrConnBusy
+	}
+}
+
+func (c *conn) close() {
+	c.once.Do(func() {
+		close(c.quit)
+	})
+}
+
+type wsLink struct {
+	cfg       *CloudConfig
+	router    *gin.Engine
+	svr       *http.Server
+	upgrader  websocket.Upgrader
+	msgRouter map[string]interface{}
+	// the websockets by node ids, only the latest one of a node is kept
+	conns   map[string]*conn
+	watcher *link.EventWatcher
+	mu      sync.Mutex
+}
+
+func init() {
+	plugin.RegisterFactory("wslink", NewWSLink)
+}
+
+func NewWSLink() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, err
+	}
+	var cloudCfg config.CloudConfig
+	if err := common.LoadConfig(&cloudCfg); err != nil {
+		return nil, err
+	}
+	events, err := service.NewEventService(&cloudCfg)
+	if err != nil {
+		return nil, err
+	}
+	if port := os.Getenv(WSLinkPort); port != "" {
+		cfg.WSLink.Port = ":" + port
+	}
+	return newWSLink(&cfg, events)
+}
+
+func newWSLink(cfg *CloudConfig, events service.EventService) (*wsLink, error) {
+	router := gin.New()
+	router.Use(server.MetricsHandler("ws"))
+	svr := &http.Server{
+		Addr:           cfg.WSLink.Port,
+		Handler:        router,
+		ReadTimeout:    cfg.WSLink.ReadTimeout,
+		WriteTimeout:   cfg.WSLink.WriteTimeout,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	// the nodes are authenticated the same as httplink unless wslink has its own certificate
+	cert := cfg.WSLink.Certificate
+	if cert.Cert == "" || cert.Key == "" || cert.CA == "" {
+		cert = cfg.HTTPLink.Certificate
+	}
+	if cert.Cert != "" && cert.Key != "" && cert.CA != "" {
+		t, err := utils.NewTLSConfigServer(utils.Certificate{
+			CA:             cert.CA,
+			Cert:           cert.Cert,
+			Key:            cert.Key,
+			ClientAuthType: tls.RequireAnyClientCert,
+		})
+		if err != nil {
+			return nil, err
+		}
+		svr.TLSConfig = t
+	}
+
+	if svr.TLSConfig == nil {
+		header := cfg.WSLink.CommonName
+		if header == "" {
+			header = cfg.HTTPLink.CommonName
+		}
+		router.Use(server.ExtractNodeCommonNameFromHeaderOf(header))
+	} else {
+		router.Use(server.ExtractNodeCommonNameFromCert)
+	}
+
+	l := &wsLink{
+		cfg:    cfg,
+		router: router,
+		svr:    svr,
+		upgrader: websocket.Upgrader{
+			HandshakeTimeout: cfg.WSLink.WriteTimeout,
+		},
+		msgRouter: map[string]interface{}{},
+		conns:     map[string]*conn{},
+	}
+	l.watcher = link.NewEventWatcher(events, l)
+	l.initRouter()
+	return l, nil
+}
+
+func (l *wsLink) Start() {
+	if l.svr.TLSConfig == nil {
+		if err := l.svr.ListenAndServe(); err != nil {
+			log.L().Info("sync server websocket stopped", log.Error(err))
+		}
+	} else {
+		if err := l.svr.ListenAndServeTLS("", ""); err != nil {
+			log.L().Info("sync server secure websocket stopped", log.Error(err))
+		}
+	}
+}
+
+func (l *wsLink) AddMsgRouter(k string, v interface{}) {
+	l.msgRouter[k] = v
+}
+
+func (l *wsLink) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.cfg.WSLink.ShutdownTime)
+	defer cancel()
+	err := l.svr.Shutdown(ctx)
+
+	// the hijacked connections are not closed by the http server
+	l.mu.Lock()
+	for _, c := range l.conns {
+		c.close()
+	}
+	l.mu.Unlock()
+	l.watcher.Close()
+	return err
+}
+
+func (l *wsLink) initRouter() {
+	l.router.NoRoute(server.NoRouteHandler)
+	l.router.NoMethod(server.NoMethodHandler)
+	l.router.GET("/health", server.Health)
+
+	l.router.Use(server.RequestIDHandler)
+	l.router.Use(server.LoggerHandler)
+	v1 := l.router.Group("v1")
+	{
+		sync := v1.Group("/sync")
+		sync.GET("/ws", l.serve)
+	}
+}
+
+// serve upgrades the request of the node to the websocket, and handles the messages of the node until it is closed
+func (l *wsLink) serve(c *gin.Context) {
+	cc := common.NewContext(c)
+	n := &node{namespace: cc.GetNamespace(), name: cc.GetName(), address: c.ClientIP()}
+	ws, err := l.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// the error is already responded by the upgrader
+		log.L().Warn("failed to upgrade websocket", log.Any(cc.GetTrace()), log.Error(err))
+		return
+	}
+	cn := &conn{
+		node: n,
+		ws:   ws,
+		send: make(chan *specV1.Message, l.cfg.WSLink.SendQueueSize),
+		quit: make(chan struct{}),
+	}
+	if err = l.register(cn); err != nil {
+		log.L().Error("failed to register websocket", log.Any(cc.GetTrace()), log.Error(err))
+		ws.Close()
+		return
+	}
+	defer l.unregister(cn)
+
+	go l.writing(cn)
+	// the node resumes from the delta and the pending commands which may be missed during its reconnection
+	l.PushDelta(n.namespace, n.name)
+	l.resume(cn)
+	l.reading(cn)
+}
+
+// register keeps the websocket of the node, and closes the stale one if the node is reconnected
+func (l *wsLink) register(c *conn) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.watcher.Watch(c.node.namespace); err != nil {
+		return err
+	}
+	if old, ok := l.conns[c.node.id()]; ok {
+		old.close()
+	}
+	l.conns[c.node.id()] = c
+	return nil
+}
+
+func (l *wsLink) unregister(c *conn) {
+	c.close()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conns[c.node.id()] == c {
+		delete(l.conns, c.node.id())
+	}
+	l.watcher.Unwatch(c.node.namespace)
+}
+
+func (l *wsLink) getConn(namespace, name string) *conn {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.conns[(&node{namespace: namespace, name: name}).id()]
+}
+
+// PushDelta sends the delta to the connected node, nothing is sent if the desire is already reported
+func (l *wsLink) PushDelta(namespace, name string) {
+	c := l.getConn(namespace, name)
+	if c == nil {
+		return
+	}
+	msg, err := link.Delta(l.msgRouter, namespace, name)
+	if err != nil {
+		log.L().Error("failed to get node delta",
+			log.Any(common.KeyContextNamespace, namespace),
+			log.Any("name", name),
+			log.Error(err))
+		return
+	}
+	if msg == nil {
+		return
+	}
+	if err = c.write(msg); err != nil {
+		log.L().Warn("failed to push node delta",
+			log.Any(common.KeyContextNamespace, namespace),
+			log.Any("name", name),
+			log.Error(err))
+	}
+}
+
+// PushCommand sends the command to the connected node
+func (l *wsLink) PushCommand(namespace, name string, cmd *models.Command) {
+	c := l.getConn(namespace, name)
+	if c == nil {
+		return
+	}
+	l.pushCommand(c, link.CommandMessage(cmd))
+}
+
+// resume sends the pending commands to the node
+func (l *wsLink) resume(c *conn) {
+	msgs, err := link.Commands(l.msgRouter, c.node.namespace, c.node.name)
+	if err != nil {
+		log.L().Error("failed to get node commands",
+			log.Any(common.KeyContextNamespace, c.node.namespace),
+			log.Any("name", c.node.name),
+			log.Error(err))
+		return
+	}
+	for _, msg := range msgs {
+		l.pushCommand(c, msg)
+	}
+}
+
+func (l *wsLink) pushCommand(c *conn, msg *specV1.Message) {
+	if err := c.write(msg); err != nil {
+		log.L().Warn("failed to push node command",
+			log.Any(common.KeyContextNamespace, c.node.namespace),
+			log.Any("name", c.node.name),
+			log.Any("command", msg.Metadata["commandId"]),
+			log.Error(err))
+	}
+}
+
+// reading handles the messages of the node, the websocket is closed if no pong or message is received in time
+func (l *wsLink) reading(c *conn) {
+	defer c.close()
+	c.ws.SetReadLimit(l.cfg.WSLink.MaxMessageSize)
+	extend := func() error {
+		return c.ws.SetReadDeadline(time.Now().Add(l.cfg.WSLink.PongTimeout))
+	}
+	extend()
+	c.ws.SetPongHandler(func(string) error {
+		return extend()
+	})
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.L().Warn("websocket of node closed",
+					log.Any(common.KeyContextNamespace, c.node.namespace),
+					log.Any("name", c.node.name),
+					log.Error(err))
+			}
+			return
+		}
+		extend()
+		var msg specV1.Message
+		var resp *specV1.Message
+		if err = json.Unmarshal(data, &msg); err != nil {
+			resp = errorMessage(nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error())))
+		} else {
+			resp = l.handle(c.node, &msg)
+		}
+		if err = c.write(resp); err != nil {
+			return
+		}
+	}
+}
+
+// writing writes the messages and the pings to the node until the websocket is closed
+func (l *wsLink) writing(c *conn) {
+	ticker := time.NewTicker(l.cfg.WSLink.PingInterval)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+	}()
+	for {
+		select {
+		case msg := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(l.cfg.WSLink.WriteTimeout))
+			if err := c.ws.WriteJSON(msg); err != nil {
+				log.L().Warn("failed to write websocket of node",
+					log.Any(common.KeyContextNamespace, c.node.namespace),
+					log.Any("name", c.node.name),
+					log.Error(err))
+				c.close()
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(l.cfg.WSLink.WriteTimeout)); err != nil {
+				c.close()
+				return
+			}
+		case <-c.quit:
+			c.ws.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(l.cfg.WSLink.WriteTimeout))
+			return
+		}
+	}
+}
+
+// handle routes the message of the node by its kind, the response keeps the metadata of the message,
+// so that the node is able to match the responses with the requests
+func (l *wsLink) handle(n *node, msg *specV1.Message) *specV1.Message {
+	if msg.Kind == specV1.MessageKeep {
+		return &specV1.Message{Kind: specV1.MessageKeep, Metadata: msg.Metadata}
+	}
+	handler, ok := l.msgRouter[string(msg.Kind)].(server.HandlerMessage)
+	if !ok {
+		return errorMessage(msg.Metadata, common.Error(common.ErrRequestMethodNotFound))
+	}
+	req := specV1.Message{
+		Kind:     msg.Kind,
+		Content:  msg.Content,
+		Metadata: map[string]string{},
+	}
+	for k, v := range msg.Metadata {
+		req.Metadata[k] = v
+	}
+	// the node is identified by the websocket, never by the metadata of the message
+	req.Metadata["namespace"] = n.namespace
+	req.Metadata["name"] = n.name
+	req.Metadata["address"] = n.address
+	resp, err := handler(req)
+	if err != nil {
+		log.L().Error("failed to handle message of node",
+			log.Any(common.KeyContextNamespace, n.namespace),
+			log.Any("name", n.name),
+			log.Any("kind", msg.Kind),
+			log.Error(err))
+		return errorMessage(msg.Metadata, err)
+	}
+	kind := resp.Kind
+	if kind == "" {
+		kind = msg.Kind
+	}
+	return &specV1.Message{Kind: kind, Metadata: msg.Metadata, Content: resp.Content}
+}
+
+func errorMessage(meta map[string]string, err error) *specV1.Message {
+	code := common.ErrUnknown
+	if e, ok := err.(errors.Coder); ok {
+		code = e.Code()
+	}
+	return &specV1.Message{
+		Kind:     MessageError,
+		Metadata: meta,
+		Content: specV1.LazyValue{Value: map[string]string{
+			"code":    code,
+			"message": err.Error(),
+		}},
+	}
+}
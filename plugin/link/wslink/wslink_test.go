@@ -0,0 +1,314 @@
+package wslink
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/pubsub"
+	"github.com/baetyl/baetyl-cloud/v2/server"
+)
+
+const waitTimeout = 3 * time.Second
+
+func initWSLink(t *testing.T) (*wsLink, *httptest.Server, chan interface{}, *gomock.Controller) {
+	mockCtl := gomock.NewController(t)
+	mEvent := ms.NewMockEventService(mockCtl)
+	events := make(chan interface{}, 10)
+	mEvent.EXPECT().SubscribeNodeEvent("default").Return(events, nil).AnyTimes()
+	mEvent.EXPECT().UnsubscribeNodeEvent("default", gomock.Any()).Return(nil).AnyTimes()
+
+	cfg := &CloudConfig{}
+	cfg.HTTPLink.CommonName = "common-name"
+	cfg.WSLink.ReadTimeout = time.Second
+	cfg.WSLink.WriteTimeout = time.Second
+	cfg.WSLink.ShutdownTime = time.Second
+	cfg.WSLink.PingInterval = 50 * time.Millisecond
+	cfg.WSLink.PongTimeout = time.Second
+	cfg.WSLink.MaxMessageSize = 1 << 20
+	cfg.WSLink.SendQueueSize = 10
+	link, err := newWSLink(cfg, mEvent)
+	assert.NoError(t, err)
+
+	link.AddMsgRouter(string(specV1.MessageReport), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		assert.Equal(t, "default", msg.Metadata["namespace"])
+		assert.Equal(t, "n0", msg.Metadata["name"])
+		assert.Equal(t, "127.0.0.1", msg.Metadata["address"])
+		var report specV1.Report
+		assert.NoError(t, msg.Content.Unmarshal(&report))
+		if report["version"] != "v1" {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "bad version"))
+		}
+		return &specV1.Message{Kind: specV1.MessageReport, Content: specV1.LazyValue{Value: specV1.Desire{"version": "v2"}}}, nil
+	}))
+	link.AddMsgRouter(string(specV1.MessageDesire), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		var req specV1.DesireRequest
+		assert.NoError(t, msg.Content.Unmarshal(&req))
+		values := []specV1.ResourceValue{{ResourceInfo: req.Infos[0]}}
+		return &specV1.Message{Content: specV1.LazyValue{Value: specV1.DesireResponse{Values: values}}}, nil
+	}))
+	var deltas int32
+	link.AddMsgRouter(string(common.MessageDelta), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		assert.Equal(t, "default", msg.Metadata["namespace"])
+		// nothing to push on the first connection
+		if atomic.AddInt32(&deltas, 1) == 1 {
+			return &specV1.Message{Kind: common.MessageDelta, Content: specV1.LazyValue{Value: specV1.Desire{}}}, nil
+		}
+		return &specV1.Message{Kind: common.MessageDelta, Content: specV1.LazyValue{Value: specV1.Desire{"version": "v3"}}}, nil
+	}))
+	link.AddMsgRouter(string(specV1.MessageCMD), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		var result models.CommandResult
+		assert.NoError(t, msg.Content.Unmarshal(&result))
+		assert.Equal(t, "c0", result.ID)
+		return &specV1.Message{Kind: specV1.MessageCMD, Content: specV1.LazyValue{Value: &models.Command{ID: "c0", State: models.CommandSucceeded}}}, nil
+	}))
+	var resumes int32
+	link.AddMsgRouter(string(common.MessageCommands), server.HandlerMessage(func(msg specV1.Message) (*specV1.Message, error) {
+		// the command is pending on the first connection only
+		var cmds []models.Command
+		if atomic.AddInt32(&resumes, 1) == 1 {
+			cmds = append(cmds, models.Command{ID: "c0", Namespace: "default", Node: msg.Metadata["name"], Type: models.CommandNodeInfo})
+		}
+		return &specV1.Message{Kind: common.MessageCommands, Content: specV1.LazyValue{Value: cmds}}, nil
+	}))
+	return link, httptest.NewServer(link.router), events, mockCtl
+}
+
+func dial(t *testing.T, svr *httptest.Server, cn string) (*websocket.Conn, *http.Response, error) {
+	url := "ws" + strings.TrimPrefix(svr.URL, "http") + "/v1/sync/ws"
+	header := http.Header{}
+	if cn != "" {
+		header.Set("common-name", cn)
+	}
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+func receive(t *testing.T, ws *websocket.Conn) *specV1.Message {
+	assert.NoError(t, ws.SetReadDeadline(time.Now().Add(waitTimeout)))
+	var msg specV1.Message
+	assert.NoError(t, ws.ReadJSON(&msg))
+	return &msg
+}
+
+func TestWSLink(t *testing.T) {
+	link, svr, events, mockCtl := initWSLink(t)
+	defer mockCtl.Finish()
+	defer svr.Close()
+	defer link.Close()
+
+	// the node is not authenticated
+	_, resp, err := dial(t, svr, "")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	ws, _, err := dial(t, svr, "default.n0")
+	assert.NoError(t, err)
+	var pings int32
+	ws.SetPingHandler(func(data string) error {
+		atomic.AddInt32(&pings, 1)
+		return ws.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	// the pending commands are pushed once connected
+	msg := receive(t, ws)
+	assert.Equal(t, specV1.MessageCMD, msg.Kind)
+	assert.Equal(t, "c0", msg.Metadata["commandId"])
+	var cmd models.Command
+	assert.NoError(t, msg.Content.Unmarshal(&cmd))
+	assert.Equal(t, models.CommandNodeInfo, cmd.Type)
+
+	// the result of the command
+	assert.NoError(t, ws.WriteJSON(specV1.Message{
+		Kind:     specV1.MessageCMD,
+		Metadata: map[string]string{"id": "0"},
+		Content:  specV1.LazyValue{Value: models.CommandResult{ID: "c0", Result: "linux"}},
+	}))
+	msg = receive(t, ws)
+	assert.Equal(t, specV1.MessageCMD, msg.Kind)
+	assert.Equal(t, "0", msg.Metadata["id"])
+	assert.NoError(t, msg.Content.Unmarshal(&cmd))
+	assert.Equal(t, models.CommandSucceeded, cmd.State)
+
+	// report
+	report := specV1.Message{
+		Kind:     specV1.MessageReport,
+		Metadata: map[string]string{"id": "1", "namespace": "other"},
+		Content:  specV1.LazyValue{Value: specV1.Report{"version": "v1"}},
+	}
+	assert.NoError(t, ws.WriteJSON(report))
+	msg = receive(t, ws)
+	assert.Equal(t, specV1.MessageReport, msg.Kind)
+	assert.Equal(t, map[string]string{"id": "1", "namespace": "other"}, msg.Metadata)
+	var delta specV1.Desire
+	assert.NoError(t, msg.Content.Unmarshal(&delta))
+	assert.Equal(t, "v2", delta["version"])
+
+	// report failed
+	report.Content = specV1.LazyValue{Value: specV1.Report{"version": "v0"}}
+	assert.NoError(t, ws.WriteJSON(report))
+	msg = receive(t, ws)
+	assert.Equal(t, MessageError, msg.Kind)
+	assert.Equal(t, "1", msg.Metadata["id"])
+	var res map[string]string
+	assert.NoError(t, msg.Content.Unmarshal(&res))
+	assert.Equal(t, common.ErrRequestParamInvalid, res["code"])
+
+	// desire
+	desire := specV1.Message{
+		Kind:     specV1.MessageDesire,
+		Metadata: map[string]string{"id": "2"},
+		Content: specV1.LazyValue{Value: specV1.DesireRequest{
+			Infos: []specV1.ResourceInfo{{Kind: specV1.KindApplication, Name: "app", Version: "1"}},
+		}},
+	}
+	assert.NoError(t, ws.WriteJSON(desire))
+	msg = receive(t, ws)
+	assert.Equal(t, specV1.MessageDesire, msg.Kind)
+	assert.Equal(t, "2", msg.Metadata["id"])
+	var desireResp specV1.DesireResponse
+	assert.NoError(t, msg.Content.Unmarshal(&desireResp))
+	assert.Len(t, desireResp.Values, 1)
+	assert.Equal(t, "app", desireResp.Values[0].Name)
+
+	// keepalive
+	assert.NoError(t, ws.WriteJSON(specV1.Message{Kind: specV1.MessageKeep, Metadata: map[string]string{"id": "3"}}))
+	msg = receive(t, ws)
+	assert.Equal(t, specV1.MessageKeep, msg.Kind)
+	assert.Equal(t, "3", msg.Metadata["id"])
+
+	// unknown kind
+	assert.NoError(t, ws.WriteJSON(specV1.Message{Kind: specV1.MessageData, Metadata: map[string]string{"id": "4"}}))
+	msg = receive(t, ws)
+	assert.Equal(t, MessageError, msg.Kind)
+	assert.Equal(t, "4", msg.Metadata["id"])
+	assert.NoError(t, msg.Content.Unmarshal(&res))
+	assert.Equal(t, common.ErrRequestMethodNotFound, res["code"])
+
+	// invalid message
+	assert.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte("{")))
+	msg = receive(t, ws)
+	assert.Equal(t, MessageError, msg.Kind)
+	assert.NoError(t, msg.Content.Unmarshal(&res))
+	assert.Equal(t, common.ErrRequestParamInvalid, res["code"])
+
+	// push once the desire is changed, the events of other nodes are ignored
+	events <- &models.NodeEvent{Type: models.NodeEventDesire, Namespace: "default", Name: "n1"}
+	events <- &models.NodeEvent{Type: models.NodeEventDesire, Namespace: "default", Name: "n0"}
+	msg = receive(t, ws)
+	assert.Equal(t, common.MessageDelta, msg.Kind)
+	assert.NoError(t, msg.Content.Unmarshal(&delta))
+	assert.Equal(t, "v3", delta["version"])
+
+	// the command is pushed once queued
+	events <- &models.NodeEvent{Type: models.NodeEventCommand, Namespace: "default", Name: "n0",
+		Command: &models.Command{ID: "c1", Namespace: "default", Node: "n0", Type: models.CommandTailLogs}}
+	msg = receive(t, ws)
+	assert.Equal(t, specV1.MessageCMD, msg.Kind)
+	assert.Equal(t, "c1", msg.Metadata["commandId"])
+
+	// the pings are handled while reading the messages
+	time.Sleep(3 * link.cfg.WSLink.PingInterval)
+	assert.NoError(t, ws.WriteJSON(specV1.Message{Kind: specV1.MessageKeep}))
+	msg = receive(t, ws)
+	assert.Equal(t, specV1.MessageKeep, msg.Kind)
+	assert.True(t, atomic.LoadInt32(&pings) > 0)
+
+	// the reconnected node resumes from the delta, and the stale websocket is closed
+	ws2, _, err := dial(t, svr, "default.n0")
+	assert.NoError(t, err)
+	msg = receive(t, ws2)
+	assert.Equal(t, common.MessageDelta, msg.Kind)
+	assert.NoError(t, ws.SetReadDeadline(time.Now().Add(waitTimeout)))
+	_, _, err = ws.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+
+	// the websockets are closed with the link
+	assert.NoError(t, link.Close())
+	assert.NoError(t, ws2.SetReadDeadline(time.Now().Add(waitTimeout)))
+	_, _, err = ws2.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+}
+
+func TestWSLinkPongTimeout(t *testing.T) {
+	link, svr, _, mockCtl := initWSLink(t)
+	defer mockCtl.Finish()
+	defer svr.Close()
+	defer link.Close()
+	link.cfg.WSLink.PingInterval = time.Hour
+	link.cfg.WSLink.PongTimeout = 100 * time.Millisecond
+
+	ws, _, err := dial(t, svr, "default.n0")
+	assert.NoError(t, err)
+	// the pending command is received before the websocket is closed
+	assert.NoError(t, ws.SetReadDeadline(time.Now().Add(waitTimeout)))
+	_, _, err = ws.ReadMessage()
+	assert.NoError(t, err)
+	_, _, err = ws.ReadMessage()
+	assert.Error(t, err)
+	assert.Eventually(t, func() bool {
+		return link.getConn("default", "n0") == nil
+	}, waitTimeout, 10*time.Millisecond)
+}
+
+func TestNewWSLink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wslink")
+	assert.NoError(t, err)
+	conf := path.Join(dir, "config.yml")
+	assert.NoError(t, ioutil.WriteFile(conf, []byte("httplink:\n  commonName: cn\nwslink:\n  pingInterval: 10s\n"), 0600))
+	common.SetConfFile(conf)
+	assert.NoError(t, os.Setenv(WSLinkPort, "9939"))
+	defer os.Unsetenv(WSLinkPort)
+
+	pl, err := NewWSLink()
+	assert.NoError(t, err)
+	link := pl.(*wsLink)
+	assert.Equal(t, ":9939", link.svr.Addr)
+	assert.Equal(t, 10*time.Second, link.cfg.WSLink.PingInterval)
+	assert.Equal(t, 60*time.Second, link.cfg.WSLink.PongTimeout)
+	assert.Nil(t, link.svr.TLSConfig)
+	// the header of wslink never overrides the one of the other servers
+	assert.Equal(t, "common-name", server.HeaderCommonName)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/sync/ws", nil)
+	req.Header.Set("common-name", "default.n0")
+	link.router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/sync/ws", nil)
+	req.Header.Set("cn", "default.n0")
+	link.router.ServeHTTP(w, req)
+	// the node is identified, the upgrade fails without the websocket headers
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, link.Close())
+}
+
+func TestConnWrite(t *testing.T) {
+	c := &conn{
+		node: &node{namespace: "default", name: "n0"},
+		send: make(chan *specV1.Message, 1),
+		quit: make(chan struct{}),
+	}
+	assert.NoError(t, c.write(&specV1.Message{Kind: specV1.MessageReport}))
+	// the slow websocket is closed instead of blocking the writer
+	assert.Equal(t, ErrConnBusy, c.write(&specV1.Message{Kind: specV1.MessageReport}))
+	select {
+	case <-c.quit:
+	default:
+		t.Fatal("websocket is not closed")
+	}
+	assert.Equal(t, ErrConnClosed, c.write(&specV1.Message{Kind: specV1.MessageReport}))
+}
@@ -39,6 +39,12 @@ type PKI interface {
 	GetClientCert(certId string) ([]byte, error)
 	DeleteClientCert(certId string) error
 
+	// ListCertByNotAfter lists the certs expiring before the time, the private keys are not returned
+	ListCertByNotAfter(before time.Time) ([]Cert, error)
+	// RotatePrivateKeys encrypts the private keys again by the current key-encryption key of the kms,
+	// the number of the private keys encrypted is returned
+	RotatePrivateKeys() (int, error)
+
 	// close
 	io.Closer
 }
@@ -49,5 +55,9 @@ type PKIStorage interface {
 	UpdateCert(cert Cert) error
 	GetCert(certId string) (*Cert, error)
 	CountCertByParentId(parentId string) (int, error)
+	// ListCertByNotAfter lists the certs expiring before the time in the order of the expiry
+	ListCertByNotAfter(before time.Time) ([]Cert, error)
+	// ListCertWithPrivateKey lists the certs whose private keys are stored
+	ListCertWithPrivateKey() ([]Cert, error)
 	io.Closer
 }
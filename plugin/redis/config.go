@@ -0,0 +1,24 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin/default/pubsub"
+)
+
+// CloudConfig baetyl-cloud config
+type CloudConfig struct {
+	Redis Config `yaml:"redis" json:"redis"`
+}
+
+// Config the redis server shared by the replicas and the limits of local subscriptions
+type Config struct {
+	Address           string        `yaml:"address" json:"address" default:"127.0.0.1:6379"`
+	Password          string        `yaml:"password" json:"password"`
+	DB                int           `yaml:"db" json:"db"`
+	Prefix            string        `yaml:"prefix" json:"prefix" default:"baetyl-cloud:"`
+	DialTimeout       time.Duration `yaml:"dialTimeout" json:"dialTimeout" default:"5s"`
+	ReconnectInterval time.Duration `yaml:"reconnectInterval" json:"reconnectInterval" default:"3s"`
+	MaxIdle           int           `yaml:"maxIdle" json:"maxIdle" default:"8"`
+	pubsub.Config     `yaml:",inline" json:",inline"`
+}
@@ -0,0 +1,210 @@
+package redis
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gomodule/redigo/redis"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/default/pubsub"
+)
+
+func init() {
+	plugin.RegisterFactory("redis", New)
+}
+
+// redisPubsub shares the messages between replicas by the pubsub of redis.
+// The messages received from redis are fanned out to the local subscriptions,
+// so each replica subscribes a topic of redis only once
+type redisPubsub struct {
+	cfg    Config
+	pool   *redis.Pool
+	local  *pubsub.MemoryPubsub
+	conn   redis.PubSubConn
+	topics map[string]struct{}
+	// guards the writes to the subscribing connection and the topics
+	lock sync.Mutex
+	done chan struct{}
+	wg   sync.WaitGroup
+	log  *log.Logger
+}
+
+// New create the pubsub over redis
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, err
+	}
+	return newRedisPubsub(cfg.Redis)
+}
+
+func newRedisPubsub(cfg Config) (*redisPubsub, error) {
+	r := &redisPubsub{
+		cfg:    cfg,
+		local:  pubsub.NewMemoryPubsub(cfg.Config),
+		topics: map[string]struct{}{},
+		done:   make(chan struct{}),
+		log:    log.L().With(log.Any("plugin", "redis")),
+	}
+	r.pool = &redis.Pool{
+		Dial:        r.dial,
+		MaxIdle:     cfg.MaxIdle,
+		IdleTimeout: 5 * time.Minute,
+	}
+	conn, err := r.dial()
+	if err != nil {
+		return nil, err
+	}
+	r.conn = redis.PubSubConn{Conn: conn}
+	r.wg.Add(1)
+	go r.receiving()
+	return r, nil
+}
+
+// Publish publish the message to redis, the message is sent as is if it is []byte or string, otherwise encoded as json.
+// The subscribers of all replicas receive the message as []byte
+func (r *redisPubsub) Publish(topic string, msg interface{}) error {
+	var payload interface{}
+	switch m := msg.(type) {
+	case []byte, string:
+		payload = m
+	default:
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		payload = data
+	}
+	conn := r.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("PUBLISH", r.cfg.Prefix+topic, payload)
+	return err
+}
+
+// Subscribe create a local subscription, the topic is subscribed from redis by the first subscription
+func (r *redisPubsub) Subscribe(topic string) (chan interface{}, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ch, err := r.local.Subscribe(topic)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.topics[topic]; ok {
+		return ch, nil
+	}
+	if err = r.conn.Subscribe(r.cfg.Prefix + topic); err != nil {
+		r.local.Unsubscribe(topic, ch)
+		return nil, err
+	}
+	r.topics[topic] = struct{}{}
+	return ch, nil
+}
+
+// Unsubscribe remove and close the local subscription, the topic is unsubscribed from redis with the last subscription
+func (r *redisPubsub) Unsubscribe(topic string, ch chan interface{}) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if err := r.local.Unsubscribe(topic, ch); err != nil {
+		return err
+	}
+	if _, ok := r.topics[topic]; !ok || r.local.Subscribers(topic) > 0 {
+		return nil
+	}
+	delete(r.topics, topic)
+	return r.conn.Unsubscribe(r.cfg.Prefix + topic)
+}
+
+// Close stop receiving from redis and close all local subscriptions
+func (r *redisPubsub) Close() error {
+	select {
+	case <-r.done:
+		return nil
+	default:
+	}
+	close(r.done)
+	r.lock.Lock()
+	r.conn.Close()
+	r.lock.Unlock()
+	r.wg.Wait()
+	r.local.Close()
+	return r.pool.Close()
+}
+
+func (r *redisPubsub) dial() (redis.Conn, error) {
+	return redis.Dial("tcp", r.cfg.Address,
+		redis.DialPassword(r.cfg.Password),
+		redis.DialDatabase(r.cfg.DB),
+		redis.DialConnectTimeout(r.cfg.DialTimeout),
+		redis.DialWriteTimeout(r.cfg.DialTimeout))
+}
+
+func (r *redisPubsub) receiving() {
+	defer r.wg.Done()
+	for {
+		r.lock.Lock()
+		conn := r.conn
+		r.lock.Unlock()
+		switch v := conn.Receive().(type) {
+		case redis.Message:
+			topic := strings.TrimPrefix(v.Channel, r.cfg.Prefix)
+			if err := r.local.Publish(topic, v.Data); err != nil {
+				r.log.Warn("failed to deliver message", log.Any("topic", topic), log.Error(err))
+			}
+		case error:
+			select {
+			case <-r.done:
+				return
+			default:
+			}
+			r.log.Error("failed to receive from redis, reconnecting", log.Error(v))
+			if !r.reconnect() {
+				return
+			}
+		}
+	}
+}
+
+// reconnect replace the subscribing connection and subscribe the topics again, the messages published in the meantime are lost
+func (r *redisPubsub) reconnect() bool {
+	for {
+		select {
+		case <-r.done:
+			return false
+		case <-time.After(r.cfg.ReconnectInterval):
+		}
+		c, err := r.dial()
+		if err != nil {
+			r.log.Error("failed to reconnect to redis", log.Error(err))
+			continue
+		}
+		r.lock.Lock()
+		select {
+		case <-r.done:
+			r.lock.Unlock()
+			c.Close()
+			return false
+		default:
+		}
+		r.conn.Close()
+		r.conn = redis.PubSubConn{Conn: c}
+		var channels []interface{}
+		for topic := range r.topics {
+			channels = append(channels, r.cfg.Prefix+topic)
+		}
+		if len(channels) > 0 {
+			err = r.conn.Subscribe(channels...)
+		}
+		r.lock.Unlock()
+		if err != nil {
+			r.log.Error("failed to subscribe topics again", log.Error(err))
+			continue
+		}
+		r.log.Info("reconnected to redis")
+		return true
+	}
+}
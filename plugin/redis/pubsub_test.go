@@ -0,0 +1,278 @@
+package redis
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/default/pubsub"
+)
+
+// fakeRedis serves the commands of redis used by the pubsub
+type fakeRedis struct {
+	ln    net.Listener
+	lock  sync.Mutex
+	conns map[*fakeConn]struct{}
+	subs  map[string]map[*fakeConn]struct{}
+}
+
+type fakeConn struct {
+	net.Conn
+	lock sync.Mutex
+}
+
+func (c *fakeConn) write(format string, args ...interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	fmt.Fprintf(c.Conn, format, args...)
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	f := &fakeRedis{
+		ln:    ln,
+		conns: map[*fakeConn]struct{}{},
+		subs:  map[string]map[*fakeConn]struct{}{},
+	}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			fc := &fakeConn{Conn: c}
+			f.lock.Lock()
+			f.conns[fc] = struct{}{}
+			f.lock.Unlock()
+			go f.handle(fc)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) handle(c *fakeConn) {
+	defer f.drop(c)
+	reader := bufio.NewReader(c)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			c.write("+PONG\r\n")
+		case "AUTH", "SELECT":
+			c.write("+OK\r\n")
+		case "PUBLISH":
+			c.write(":%d\r\n", f.publish(args[1], args[2]))
+		case "SUBSCRIBE", "UNSUBSCRIBE":
+			for _, ch := range args[1:] {
+				f.lock.Lock()
+				if strings.ToUpper(args[0]) == "SUBSCRIBE" {
+					if f.subs[ch] == nil {
+						f.subs[ch] = map[*fakeConn]struct{}{}
+					}
+					f.subs[ch][c] = struct{}{}
+				} else {
+					delete(f.subs[ch], c)
+				}
+				f.lock.Unlock()
+				kind := strings.ToLower(args[0])
+				c.write("*3\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n:1\r\n", len(kind), kind, len(ch), ch)
+			}
+		default:
+			c.write("-ERR unknown command\r\n")
+		}
+	}
+}
+
+func (f *fakeRedis) publish(ch, msg string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for c := range f.subs[ch] {
+		c.write("*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(ch), ch, len(msg), msg)
+	}
+	return len(f.subs[ch])
+}
+
+func (f *fakeRedis) subscribers(ch string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.subs[ch])
+}
+
+func (f *fakeRedis) drop(c *fakeConn) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	c.Close()
+	delete(f.conns, c)
+	for _, subs := range f.subs {
+		delete(subs, c)
+	}
+}
+
+// dropAll close all connections to simulate the restart of redis
+func (f *fakeRedis) dropAll() {
+	f.lock.Lock()
+	conns := f.conns
+	f.conns = map[*fakeConn]struct{}{}
+	f.lock.Unlock()
+	for c := range conns {
+		f.drop(c)
+	}
+}
+
+func (f *fakeRedis) Close() {
+	f.ln.Close()
+	f.dropAll()
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line)[1:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err = reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line)[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+func genConfig(address string) Config {
+	return Config{
+		Address:           address,
+		Prefix:            "test:",
+		DialTimeout:       time.Second,
+		ReconnectInterval: 10 * time.Millisecond,
+		Config:            pubsub.Config{BufferSize: 8, MaxSubscribers: 2},
+	}
+}
+
+func receive(t *testing.T, ch chan interface{}) interface{} {
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("message is not received")
+	}
+	return nil
+}
+
+func TestRedisPubsub(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	// the replicas share the messages by redis
+	r1, err := newRedisPubsub(genConfig(f.ln.Addr().String()))
+	assert.NoError(t, err)
+	defer r1.Close()
+	r2, err := newRedisPubsub(genConfig(f.ln.Addr().String()))
+	assert.NoError(t, err)
+	defer r2.Close()
+
+	ch1, err := r1.Subscribe("t1")
+	assert.NoError(t, err)
+	ch2, err := r1.Subscribe("t1")
+	assert.NoError(t, err)
+	_, err = r1.Subscribe("t1")
+	assert.Equal(t, pubsub.ErrTooManySubscribers, err)
+	ch3, err := r2.Subscribe("t1")
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool { return f.subscribers("test:t1") == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, r2.Publish("t1", []byte("a")))
+	assert.Equal(t, []byte("a"), receive(t, ch1))
+	assert.Equal(t, []byte("a"), receive(t, ch2))
+	assert.Equal(t, []byte("a"), receive(t, ch3))
+
+	assert.NoError(t, r1.Publish("t1", map[string]string{"k": "v"}))
+	assert.Equal(t, []byte(`{"k":"v"}`), receive(t, ch1))
+	assert.Equal(t, []byte(`{"k":"v"}`), receive(t, ch2))
+	assert.Equal(t, []byte(`{"k":"v"}`), receive(t, ch3))
+
+	// the topic is unsubscribed from redis with the last local subscription
+	assert.NoError(t, r1.Unsubscribe("t1", ch1))
+	_, ok := <-ch1
+	assert.False(t, ok)
+	assert.NoError(t, r1.Unsubscribe("t1", ch1))
+	assert.Equal(t, 2, f.subscribers("test:t1"))
+	assert.NoError(t, r1.Unsubscribe("t1", ch2))
+	assert.Eventually(t, func() bool { return f.subscribers("test:t1") == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// the topics are subscribed again after reconnected
+	f.dropAll()
+	assert.Eventually(t, func() bool { return f.subscribers("test:t1") == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, r1.Publish("t1", "b"))
+	assert.Equal(t, []byte("b"), receive(t, ch3))
+
+	// close all local subscriptions
+	assert.NoError(t, r2.Close())
+	_, ok = <-ch3
+	assert.False(t, ok)
+	assert.NoError(t, r2.Close())
+}
+
+func TestRedisPubsub_DialFailed(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	_, err = newRedisPubsub(genConfig(addr))
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	f := newFakeRedis(t)
+	defer f.Close()
+
+	dir, err := ioutil.TempDir("", "redis")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "cloud.yml")
+	conf := fmt.Sprintf("redis:\n  address: %s\n  bufferSize: 2\n", f.ln.Addr().String())
+	assert.NoError(t, ioutil.WriteFile(file, []byte(conf), 0755))
+	common.SetConfFile(file)
+	defer common.SetConfFile(common.ValueConfFile)
+
+	p, err := plugin.GetPlugin("redis")
+	assert.NoError(t, err)
+	defer p.Close()
+	_, ok := p.(plugin.Pubsub)
+	assert.True(t, ok)
+	r := p.(*redisPubsub)
+	assert.Equal(t, "baetyl-cloud:", r.cfg.Prefix)
+	assert.Equal(t, 2, r.cfg.BufferSize)
+	assert.Equal(t, 1024, r.cfg.MaxSubscribers)
+}
@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"database/sql"
+	"time"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/jmoiron/sqlx"
@@ -48,6 +49,7 @@ type DBStorage interface {
 	ListRecord(batchName, ns string, filter *models.Filter) ([]models.Record, error)
 	CreateRecord(records []models.Record) (sql.Result, error)
 	UpdateRecord(record *models.Record) (sql.Result, error)
+	ActivateRecord(record *models.Record) (sql.Result, error)
 	DeleteRecord(batchName, recordName, ns string) (sql.Result, error)
 	GetRecordTx(tx *sqlx.Tx, batchName, recordName, ns string) (*models.Record, error)
 	CountRecordTx(tx *sqlx.Tx, batchName, fingerprintValue, ns string) (int, error)
@@ -55,30 +57,141 @@ type DBStorage interface {
 	ListRecordTx(tx *sqlx.Tx, batchName, ns string, filter *models.Filter) ([]models.Record, error)
 	CreateRecordTx(tx *sqlx.Tx, records []models.Record) (sql.Result, error)
 	UpdateRecordTx(tx *sqlx.Tx, record *models.Record) (sql.Result, error)
+	ActivateRecordTx(tx *sqlx.Tx, record *models.Record) (sql.Result, error)
 	DeleteRecordTx(tx *sqlx.Tx, batchName, recordName, ns string) (sql.Result, error)
 
 	// task
 	CreateTask(task *models.Task) (sql.Result, error)
 	UpdateTask(task *models.Task) (sql.Result, error)
+	UpdateTaskByState(task *models.Task, oldState string) (sql.Result, error)
 	GetTask(traceId string) (*models.Task, error)
 	DeleteTask(traceId string) (sql.Result, error)
 	CountTask(task *models.Task) (int, error)
+	ListNodeTask(ns, node string, filter *models.TaskFilter) ([]models.Task, error)
+	CountNodeTask(ns, node string, filter *models.TaskFilter) (int, error)
 
 	GetTaskTx(tx *sqlx.Tx, traceId string) (*models.Task, error)
 	CreateTaskTx(tx *sqlx.Tx, task *models.Task) (sql.Result, error)
 	UpdateTaskTx(tx *sqlx.Tx, task *models.Task) (sql.Result, error)
+	UpdateTaskByStateTx(tx *sqlx.Tx, task *models.Task, oldState string) (sql.Result, error)
 	DeleteTaskTx(tx *sqlx.Tx, traceId string) (sql.Result, error)
+	ListNodeTaskTx(tx *sqlx.Tx, ns, node string, filter *models.TaskFilter) ([]models.Task, error)
+	CountNodeTaskTx(tx *sqlx.Tx, ns, node string, filter *models.TaskFilter) (int, error)
 
 	// callback
 	GetCallback(name, namespace string) (*models.Callback, error)
+	ListCallback(namespace string, filter *models.Filter) ([]models.Callback, error)
+	CountCallback(namespace, name string) (int, error)
 	CreateCallback(callback *models.Callback) (sql.Result, error)
 	UpdateCallback(callback *models.Callback) (sql.Result, error)
 	DeleteCallback(name, ns string) (sql.Result, error)
 	GetCallbackTx(tx *sqlx.Tx, name, namespace string) (*models.Callback, error)
+	ListCallbackTx(tx *sqlx.Tx, namespace string, filter *models.Filter) ([]models.Callback, error)
+	CountCallbackTx(tx *sqlx.Tx, namespace, name string) (int, error)
 	CreateCallbackTx(tx *sqlx.Tx, callback *models.Callback) (sql.Result, error)
 	UpdateCallbackTx(tx *sqlx.Tx, callback *models.Callback) (sql.Result, error)
 	DeleteCallbackTx(tx *sqlx.Tx, name, ns string) (sql.Result, error)
 
+	// callback log
+	CreateCallbackLog(callbackLog *models.CallbackLog) (sql.Result, error)
+	ListCallbackLog(callbackName, ns string, filter *models.Filter) ([]models.CallbackLog, error)
+	CountCallbackLog(callbackName, ns string) (int, error)
+	DeleteCallbackLog(callbackName, ns string) (sql.Result, error)
+	PruneCallbackLog(callbackName, ns string, limit int) (sql.Result, error)
+	CreateCallbackLogTx(tx *sqlx.Tx, callbackLog *models.CallbackLog) (sql.Result, error)
+	ListCallbackLogTx(tx *sqlx.Tx, callbackName, ns string, filter *models.Filter) ([]models.CallbackLog, error)
+	CountCallbackLogTx(tx *sqlx.Tx, callbackName, ns string) (int, error)
+	DeleteCallbackLogTx(tx *sqlx.Tx, callbackName, ns string) (sql.Result, error)
+	PruneCallbackLogTx(tx *sqlx.Tx, callbackName, ns string, limit int) (sql.Result, error)
+
+	// node deploy history
+	CreateNodeDeployHistory(histories []models.NodeDeployHistory) (sql.Result, error)
+	ListNodeDeployHistory(ns, nodeName string, filter *models.NodeDeployFilter) ([]models.NodeDeployHistory, error)
+	CountNodeDeployHistory(ns, nodeName string, filter *models.NodeDeployFilter) (int, error)
+	DeleteNodeDeployHistory(ns, nodeName string) (sql.Result, error)
+	CreateNodeDeployHistoryTx(tx *sqlx.Tx, histories []models.NodeDeployHistory) (sql.Result, error)
+	ListNodeDeployHistoryTx(tx *sqlx.Tx, ns, nodeName string, filter *models.NodeDeployFilter) ([]models.NodeDeployHistory, error)
+	CountNodeDeployHistoryTx(tx *sqlx.Tx, ns, nodeName string, filter *models.NodeDeployFilter) (int, error)
+	DeleteNodeDeployHistoryTx(tx *sqlx.Tx, ns, nodeName string) (sql.Result, error)
+
+	// rollout
+	GetRollout(name, namespace string) (*models.Rollout, error)
+	ListRollout(namespace string, filter *models.Filter) ([]models.Rollout, error)
+	CountRollout(namespace, name string) (int, error)
+	ListRolloutByStatus(status string) ([]models.Rollout, error)
+	ListNamespaceRolloutByStatus(namespace string, statuses []string) ([]models.Rollout, error)
+	CreateRollout(rollout *models.Rollout) (sql.Result, error)
+	UpdateRollout(rollout *models.Rollout, oldStatus string) (sql.Result, error)
+	GetRolloutTx(tx *sqlx.Tx, name, namespace string) (*models.Rollout, error)
+	ListRolloutTx(tx *sqlx.Tx, namespace string, filter *models.Filter) ([]models.Rollout, error)
+	CountRolloutTx(tx *sqlx.Tx, namespace, name string) (int, error)
+	ListRolloutByStatusTx(tx *sqlx.Tx, status string) ([]models.Rollout, error)
+	ListNamespaceRolloutByStatusTx(tx *sqlx.Tx, namespace string, statuses []string) ([]models.Rollout, error)
+	CreateRolloutTx(tx *sqlx.Tx, rollout *models.Rollout) (sql.Result, error)
+	UpdateRolloutTx(tx *sqlx.Tx, rollout *models.Rollout, oldStatus string) (sql.Result, error)
+
+	// api key
+	GetAPIKey(name string) (*models.APIKey, error)
+	GetAPIKeyByHash(hash string) (*models.APIKey, error)
+	ListAPIKey(user string, filter *models.Filter) ([]models.APIKey, error)
+	CountAPIKey(user, name string) (int, error)
+	CreateAPIKey(key *models.APIKey) (sql.Result, error)
+	DeleteAPIKey(name string) (sql.Result, error)
+	GetAPIKeyTx(tx *sqlx.Tx, name string) (*models.APIKey, error)
+	GetAPIKeyByHashTx(tx *sqlx.Tx, hash string) (*models.APIKey, error)
+	ListAPIKeyTx(tx *sqlx.Tx, user string, filter *models.Filter) ([]models.APIKey, error)
+	CountAPIKeyTx(tx *sqlx.Tx, user, name string) (int, error)
+	CreateAPIKeyTx(tx *sqlx.Tx, key *models.APIKey) (sql.Result, error)
+	DeleteAPIKeyTx(tx *sqlx.Tx, name string) (sql.Result, error)
+
+	// role binding
+	GetRoleBinding(namespace, user string) (*models.RoleBinding, error)
+	ListRoleBinding(namespace string, filter *models.Filter) ([]models.RoleBinding, error)
+	CountRoleBinding(namespace, user string) (int, error)
+	CreateRoleBinding(binding *models.RoleBinding) (sql.Result, error)
+	UpdateRoleBinding(binding *models.RoleBinding) (sql.Result, error)
+	DeleteRoleBinding(namespace, user string) (sql.Result, error)
+	GetRoleBindingTx(tx *sqlx.Tx, namespace, user string) (*models.RoleBinding, error)
+	ListRoleBindingTx(tx *sqlx.Tx, namespace string, filter *models.Filter) ([]models.RoleBinding, error)
+	CountRoleBindingTx(tx *sqlx.Tx, namespace, user string) (int, error)
+	CreateRoleBindingTx(tx *sqlx.Tx, binding *models.RoleBinding) (sql.Result, error)
+	UpdateRoleBindingTx(tx *sqlx.Tx, binding *models.RoleBinding) (sql.Result, error)
+	DeleteRoleBindingTx(tx *sqlx.Tx, namespace, user string) (sql.Result, error)
+
+	// node group
+	GetNodeGroup(namespace, name string) (*models.NodeGroup, error)
+	ListNodeGroup(namespace string, filter *models.Filter) ([]models.NodeGroup, error)
+	CountNodeGroup(namespace, name string) (int, error)
+	CreateNodeGroup(group *models.NodeGroup) (sql.Result, error)
+	UpdateNodeGroup(group *models.NodeGroup) (sql.Result, error)
+	DeleteNodeGroup(namespace, name string) (sql.Result, error)
+	GetNodeGroupTx(tx *sqlx.Tx, namespace, name string) (*models.NodeGroup, error)
+	ListNodeGroupTx(tx *sqlx.Tx, namespace string, filter *models.Filter) ([]models.NodeGroup, error)
+	CountNodeGroupTx(tx *sqlx.Tx, namespace, name string) (int, error)
+	CreateNodeGroupTx(tx *sqlx.Tx, group *models.NodeGroup) (sql.Result, error)
+	UpdateNodeGroupTx(tx *sqlx.Tx, group *models.NodeGroup) (sql.Result, error)
+	DeleteNodeGroupTx(tx *sqlx.Tx, namespace, name string) (sql.Result, error)
+
+	// audit log
+	CreateAuditLog(audit *models.AuditLog) (sql.Result, error)
+	ListAuditLog(filter *models.AuditFilter) ([]models.AuditLog, error)
+	CountAuditLog(filter *models.AuditFilter) (int, error)
+	CreateAuditLogTx(tx *sqlx.Tx, audit *models.AuditLog) (sql.Result, error)
+	ListAuditLogTx(tx *sqlx.Tx, filter *models.AuditFilter) ([]models.AuditLog, error)
+	CountAuditLogTx(tx *sqlx.Tx, filter *models.AuditFilter) (int, error)
+
+	// lock
+	GetLock(name string) (*models.Lock, error)
+	CreateLock(lock *models.Lock) (sql.Result, error)
+	RenewLock(lock *models.Lock) (sql.Result, error)
+	DeleteLock(name, owner string) (sql.Result, error)
+	DeleteExpiredLock(name string, now time.Time) (sql.Result, error)
+	GetLockTx(tx *sqlx.Tx, name string) (*models.Lock, error)
+	CreateLockTx(tx *sqlx.Tx, lock *models.Lock) (sql.Result, error)
+	RenewLockTx(tx *sqlx.Tx, lock *models.Lock) (sql.Result, error)
+	DeleteLockTx(tx *sqlx.Tx, name, owner string) (sql.Result, error)
+	DeleteExpiredLockTx(tx *sqlx.Tx, name string, now time.Time) (sql.Result, error)
+
 	// application
 	CreateApplication(app *specV1.Application) (sql.Result, error)
 	UpdateApplication(app *specV1.Application, oldVersion string) (sql.Result, error)
@@ -89,6 +202,8 @@ type DBStorage interface {
 	UpdateApplicationWithTx(tx *sqlx.Tx, app *specV1.Application, oldVersion string) (sql.Result, error)
 	DeleteApplicationWithTx(tx *sqlx.Tx, name, namespace, version string) (sql.Result, error)
 	CountApplication(tx *sqlx.Tx, name, namespace string) (int, error)
+	ListApplicationHistory(name, namespace string, filter *models.Filter) ([]models.ApplicationVersion, error)
+	CountApplicationHistory(name, namespace string) (int, error)
 
 	Shadow
 }
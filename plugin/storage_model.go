@@ -13,6 +13,7 @@ type ModelStorage interface {
 	GetNamespace(namespace string) (*models.Namespace, error)
 	CreateNamespace(namespace *models.Namespace) (*models.Namespace, error)
 	DeleteNamespace(namespace *models.Namespace) error
+	ListNamespace() ([]models.Namespace, error)
 
 	GetNode(namespace, name string) (*specV1.Node, error)
 	CreateNode(namespace string, node *specV1.Node) (*specV1.Node, error)
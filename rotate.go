@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// rotate encrypts the secrets of the namespaces, all namespaces if none is specified, and the private keys of the pki
+// again by the current key-encryption key of the kms, the data encrypted by the previous keys is still readable until rotated
+func rotate(cfg *config.CloudConfig, namespaces []string) error {
+	if len(namespaces) == 0 {
+		ns, err := service.NewNamespaceService(cfg)
+		if err != nil {
+			return err
+		}
+		list, err := ns.List()
+		if err != nil {
+			return err
+		}
+		for _, item := range list {
+			namespaces = append(namespaces, item.Name)
+		}
+	}
+	ss, err := service.NewSecretService(cfg)
+	if err != nil {
+		return err
+	}
+	for _, ns := range namespaces {
+		count, err := ss.Rotate(ns)
+		fmt.Printf("%-32s %d\n", ns, count)
+		if err != nil {
+			return err
+		}
+	}
+	ps, err := service.NewPKIService(cfg)
+	if err != nil {
+		return err
+	}
+	count, err := ps.RotatePrivateKeys()
+	fmt.Printf("%-32s %d\n", "(pki private keys)", count)
+	return err
+}
@@ -68,6 +68,23 @@ CREATE TABLE IF NOT EXISTS `baetyl_callback` (
   UNIQUE KEY `unique_ns_name` (`namespace`,`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='回调';
 
+CREATE TABLE IF NOT EXISTS `baetyl_callback_log` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `callback_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'callback name',
+  `event` varchar(64) NOT NULL DEFAULT '' COMMENT '触发事件',
+  `method` varchar(36) NOT NULL DEFAULT 'GET' COMMENT 'Get/Post/Put/Delete',
+  `url` varchar(1024) NOT NULL DEFAULT '' COMMENT 'url',
+  `request` text COMMENT '请求内容',
+  `status_code` int(11) NOT NULL DEFAULT '0' COMMENT '响应状态码',
+  `response` text COMMENT '响应内容',
+  `error` varchar(1024) NOT NULL DEFAULT '' COMMENT '错误信息',
+  `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '请求次数',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_callback` (`namespace`,`callback_name`,`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='回调投递记录';
+
 CREATE TABLE IF NOT EXISTS `baetyl_index_application_config` (
   `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
   `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
@@ -116,6 +133,96 @@ CREATE TABLE IF NOT EXISTS `baetyl_node_shadow` (
   UNIQUE KEY `unique_name` (`namespace`,`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点影子';
 
+CREATE TABLE IF NOT EXISTS `baetyl_node_deploy_history` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `node_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'node名称',
+  `app_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app名称',
+  `old_version` varchar(36) NOT NULL DEFAULT '' COMMENT '原app版本',
+  `new_version` varchar(36) NOT NULL DEFAULT '' COMMENT '新app版本',
+  `trigger_type` varchar(32) NOT NULL DEFAULT '' COMMENT '触发类型 app/config/secret/label/rollout',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_node_date` (`namespace`,`node_name`,`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点部署历史';
+
+CREATE TABLE IF NOT EXISTS `baetyl_rollout` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `app_name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app名称',
+  `app_version` varchar(36) NOT NULL DEFAULT '' COMMENT 'app版本',
+  `status` varchar(16) NOT NULL DEFAULT '' COMMENT '状态 running/paused/succeeded/aborted',
+  `strategy` varchar(2048) NOT NULL DEFAULT '{}' COMMENT '发布策略',
+  `progress` mediumtext COMMENT '发布进度',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT '状态说明',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='应用灰度发布表';
+
+CREATE TABLE IF NOT EXISTS `baetyl_api_key` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `user_id` varchar(128) NOT NULL DEFAULT '' COMMENT '用户id',
+  `namespaces` varchar(2048) NOT NULL DEFAULT '[]' COMMENT '可访问的命名空间',
+  `key_prefix` varchar(16) NOT NULL DEFAULT '' COMMENT 'key前缀，用于识别',
+  `key_hash` varchar(64) NOT NULL DEFAULT '' COMMENT 'key的sha256摘要',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `expire_time` timestamp NULL DEFAULT NULL COMMENT '过期时间，为空则不过期',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`name`),
+  UNIQUE KEY `unique_key_hash` (`key_hash`),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='API key表';
+
+CREATE TABLE IF NOT EXISTS `baetyl_role_binding` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `user_id` varchar(128) NOT NULL DEFAULT '' COMMENT '用户id',
+  `role` varchar(32) NOT NULL DEFAULT '' COMMENT '角色 viewer/operator/admin',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_user` (`namespace`,`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='角色绑定表';
+
+CREATE TABLE IF NOT EXISTS `baetyl_node_group` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
+  `selector` varchar(2048) NOT NULL DEFAULT '' COMMENT '动态分组的标签选择器，为空则为静态分组',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点分组表';
+
+CREATE TABLE IF NOT EXISTS `baetyl_audit_log` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `user_id` varchar(128) NOT NULL DEFAULT '' COMMENT '操作用户',
+  `request_id` varchar(64) NOT NULL DEFAULT '' COMMENT '请求id',
+  `method` varchar(16) NOT NULL DEFAULT '' COMMENT '请求方法',
+  `path` varchar(512) NOT NULL DEFAULT '' COMMENT '请求路径',
+  `resource` varchar(64) NOT NULL DEFAULT '' COMMENT '资源类型',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '资源名称',
+  `operation` varchar(32) NOT NULL DEFAULT '' COMMENT '操作 create/update/delete等',
+  `status` int(11) NOT NULL DEFAULT '0' COMMENT 'http状态码',
+  `code` varchar(64) NOT NULL DEFAULT '' COMMENT '错误码，成功为空',
+  `diff` mediumtext COMMENT '脱敏后的变更内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_time` (`namespace`,`create_time`),
+  KEY `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='审计日志表';
+
 CREATE TABLE IF NOT EXISTS `baetyl_certificate` (
   `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
   `cert_id` varchar(128) NOT NULL DEFAULT '' COMMENT '证书id',
@@ -145,4 +252,88 @@ CREATE TABLE IF NOT EXISTS `baetyl_property` (
   UNIQUE KEY `unique_name` (`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='System configuration property table';
 
+CREATE TABLE IF NOT EXISTS `baetyl_namespace` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `name` varchar(64) NOT NULL DEFAULT '' COMMENT '名称',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='命名空间表，模型存储插件sql使用';
+
+CREATE TABLE IF NOT EXISTS `baetyl_node` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '版本',
+  `content` mediumtext COMMENT '内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点表，模型存储插件sql使用';
+
+CREATE TABLE IF NOT EXISTS `baetyl_application` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '版本',
+  `content` mediumtext COMMENT '内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='应用表，模型存储插件sql使用';
+
+CREATE TABLE IF NOT EXISTS `baetyl_configuration` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '版本',
+  `content` mediumtext COMMENT '内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='配置表，模型存储插件sql使用';
+
+CREATE TABLE IF NOT EXISTS `baetyl_secret` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '版本',
+  `content` mediumtext COMMENT '内容',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='密文表，模型存储插件sql使用';
+
+CREATE TABLE IF NOT EXISTS `baetyl_resource_version` (
+  `id` int(11) NOT NULL COMMENT '主键',
+  `version` bigint(20) unsigned NOT NULL DEFAULT '0' COMMENT '资源的最新版本',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='资源版本序列，模型存储插件sql使用';
+
+CREATE TABLE IF NOT EXISTS `baetyl_task` (
+  `trace_id` varchar(36) NOT NULL DEFAULT '' COMMENT '任务id',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `node` varchar(128) NOT NULL DEFAULT '' COMMENT 'node名称',
+  `type` varchar(32) NOT NULL DEFAULT '' COMMENT '任务类型 command/ota',
+  `state` varchar(16) NOT NULL DEFAULT '0' COMMENT '任务状态',
+  `step` text NOT NULL COMMENT '任务内容',
+  `old_version` varchar(36) NOT NULL DEFAULT '' COMMENT '原版本',
+  `new_version` varchar(36) NOT NULL DEFAULT '' COMMENT '新版本',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`trace_id`),
+  KEY `idx_node_type_state` (`namespace`,`node`,`type`,`state`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点任务表';
+
+CREATE TABLE IF NOT EXISTS `baetyl_lock` (
+  `name` varchar(64) NOT NULL DEFAULT '' COMMENT '锁名称',
+  `owner` varchar(128) NOT NULL DEFAULT '' COMMENT '持有者',
+  `expire_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '过期时间',
+  PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='副本间的锁';
+
 COMMIT;
\ No newline at end of file
@@ -0,0 +1,432 @@
+-- the schema of baetyl-cloud for sqlite, the update_time columns are maintained by the triggers
+
+-- application历史信息表
+CREATE TABLE IF NOT EXISTS baetyl_application_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- app名称
+  version varchar(36) NOT NULL DEFAULT '', -- app版本
+  is_deleted smallint NOT NULL DEFAULT '0', -- 删除标记1:删除
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  content text -- app详情
+);
+CREATE INDEX IF NOT EXISTS baetyl_application_history_idx_app_history ON baetyl_application_history (namespace, name, version);
+CREATE INDEX IF NOT EXISTS baetyl_application_history_idx_app_date ON baetyl_application_history (namespace, create_time);
+CREATE TRIGGER IF NOT EXISTS baetyl_application_history_update_time AFTER UPDATE ON baetyl_application_history
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_application_history SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 批号管理
+CREATE TABLE IF NOT EXISTS baetyl_batch (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  name varchar(128) NOT NULL DEFAULT '', -- 批号
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  description varchar(1024) NOT NULL DEFAULT '', -- 型号描述信息
+  quota_num int NOT NULL DEFAULT '200', -- 数量
+  enable_whitelist int NOT NULL DEFAULT '1', -- 是否启用白名单
+  security_type varchar(32) NOT NULL DEFAULT 'Token', -- 安全等级 None/Token/Cert/Dongle
+  security_key varchar(64) NOT NULL DEFAULT '', -- null/token/cert_id/dongle_id
+  callback_name varchar(64) NOT NULL DEFAULT '', -- callback name
+  labels varchar(2048) NOT NULL DEFAULT '{}', -- 标签，json格式字符串,会设置到激活的node上
+  fingerprint varchar(1024) NOT NULL DEFAULT '{}', -- 设备指纹信息，json格式字符串，包含类型等数据
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_batch_update_time AFTER UPDATE ON baetyl_batch
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_batch SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 注册记录管理
+CREATE TABLE IF NOT EXISTS baetyl_batch_record (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  name varchar(128) NOT NULL DEFAULT '', -- 注册记录uuid
+  batch_name varchar(128) NOT NULL DEFAULT '', -- 批号
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  fingerprint_value varchar(255) NOT NULL DEFAULT '', -- 注册序列号,多种组合采用逗号隔开
+  active int NOT NULL DEFAULT '0', -- 是否激活
+  node_name varchar(64) NOT NULL DEFAULT '', -- node资源名称
+  active_ip varchar(64) NOT NULL DEFAULT '0.0.0.0', -- 激活ip
+  active_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 激活时间
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, batch_name, fingerprint_value),
+  UNIQUE (namespace, batch_name, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_batch_record_update_time AFTER UPDATE ON baetyl_batch_record
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_batch_record SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 回调
+CREATE TABLE IF NOT EXISTS baetyl_callback (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  name varchar(128) NOT NULL DEFAULT '', -- callback uuid
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  method varchar(36) NOT NULL DEFAULT 'GET', -- Get/Post/Put/Delete
+  params varchar(2048) NOT NULL DEFAULT '{}', -- query params
+  body varchar(2048) NOT NULL DEFAULT '{}', -- body
+  header varchar(1024) NOT NULL DEFAULT '{}', -- header
+  url varchar(1024) NOT NULL DEFAULT '', -- url
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_callback_update_time AFTER UPDATE ON baetyl_callback
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_callback SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 回调投递记录
+CREATE TABLE IF NOT EXISTS baetyl_callback_log (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  callback_name varchar(128) NOT NULL DEFAULT '', -- callback name
+  event varchar(64) NOT NULL DEFAULT '', -- 触发事件
+  method varchar(36) NOT NULL DEFAULT 'GET', -- Get/Post/Put/Delete
+  url varchar(1024) NOT NULL DEFAULT '', -- url
+  request text, -- 请求内容
+  status_code int NOT NULL DEFAULT '0', -- 响应状态码
+  response text, -- 响应内容
+  error varchar(1024) NOT NULL DEFAULT '', -- 错误信息
+  attempts int NOT NULL DEFAULT '0', -- 请求次数
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 创建时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_callback_log_idx_callback ON baetyl_callback_log (namespace, callback_name, create_time);
+
+-- 应用与配置索引表
+CREATE TABLE IF NOT EXISTS baetyl_index_application_config (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  application varchar(128) NOT NULL DEFAULT '', -- app名称
+  config varchar(128) NOT NULL DEFAULT '', -- config名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 更新时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_config_idx_application ON baetyl_index_application_config (namespace, application);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_config_idx_config ON baetyl_index_application_config (namespace, config);
+CREATE TRIGGER IF NOT EXISTS baetyl_index_application_config_update_time AFTER UPDATE ON baetyl_index_application_config
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_index_application_config SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 应用与节点索引表
+CREATE TABLE IF NOT EXISTS baetyl_index_application_node (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  application varchar(128) NOT NULL DEFAULT '', -- app名称
+  node varchar(128) NOT NULL DEFAULT '', -- node名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 更新时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_node_idx_application ON baetyl_index_application_node (namespace, application);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_node_idx_node ON baetyl_index_application_node (namespace, node);
+CREATE TRIGGER IF NOT EXISTS baetyl_index_application_node_update_time AFTER UPDATE ON baetyl_index_application_node
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_index_application_node SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 应用与secret索引表
+CREATE TABLE IF NOT EXISTS baetyl_index_application_secret (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  application varchar(128) NOT NULL DEFAULT '', -- app名称
+  secret varchar(128) NOT NULL DEFAULT '', -- secret名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 更新时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_secret_idx_application ON baetyl_index_application_secret (namespace, application);
+CREATE INDEX IF NOT EXISTS baetyl_index_application_secret_idx_secret ON baetyl_index_application_secret (namespace, secret);
+CREATE TRIGGER IF NOT EXISTS baetyl_index_application_secret_update_time AFTER UPDATE ON baetyl_index_application_secret
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_index_application_secret SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 节点影子
+CREATE TABLE IF NOT EXISTS baetyl_node_shadow (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  name varchar(128) NOT NULL DEFAULT '', -- node影子名称
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  report text, -- 上报内容
+  desire text, -- 期望内容
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_node_shadow_update_time AFTER UPDATE ON baetyl_node_shadow
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_node_shadow SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 节点部署历史
+CREATE TABLE IF NOT EXISTS baetyl_node_deploy_history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  node_name varchar(128) NOT NULL DEFAULT '', -- node名称
+  app_name varchar(128) NOT NULL DEFAULT '', -- app名称
+  old_version varchar(36) NOT NULL DEFAULT '', -- 原app版本
+  new_version varchar(36) NOT NULL DEFAULT '', -- 新app版本
+  trigger_type varchar(32) NOT NULL DEFAULT '', -- 触发类型 app/config/secret/label/rollout
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 创建时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_node_deploy_history_idx_node_date ON baetyl_node_deploy_history (namespace, node_name, create_time);
+
+-- 应用灰度发布表
+CREATE TABLE IF NOT EXISTS baetyl_rollout (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  app_name varchar(128) NOT NULL DEFAULT '', -- app名称
+  app_version varchar(36) NOT NULL DEFAULT '', -- app版本
+  status varchar(16) NOT NULL DEFAULT '', -- 状态 running/paused/succeeded/aborted
+  strategy varchar(2048) NOT NULL DEFAULT '{}', -- 发布策略
+  progress text, -- 发布进度
+  message varchar(1024) NOT NULL DEFAULT '', -- 状态说明
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE INDEX IF NOT EXISTS baetyl_rollout_idx_status ON baetyl_rollout (status);
+CREATE TRIGGER IF NOT EXISTS baetyl_rollout_update_time AFTER UPDATE ON baetyl_rollout
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_rollout SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- API key表
+CREATE TABLE IF NOT EXISTS baetyl_api_key (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  user_id varchar(128) NOT NULL DEFAULT '', -- 用户id
+  namespaces varchar(2048) NOT NULL DEFAULT '[]', -- 可访问的命名空间
+  key_prefix varchar(16) NOT NULL DEFAULT '', -- key前缀，用于识别
+  key_hash varchar(64) NOT NULL DEFAULT '', -- key的sha256摘要
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  expire_time timestamp NULL DEFAULT NULL, -- 过期时间，为空则不过期
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (name),
+  UNIQUE (key_hash)
+);
+CREATE INDEX IF NOT EXISTS baetyl_api_key_idx_user_id ON baetyl_api_key (user_id);
+CREATE TRIGGER IF NOT EXISTS baetyl_api_key_update_time AFTER UPDATE ON baetyl_api_key
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_api_key SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 角色绑定表
+CREATE TABLE IF NOT EXISTS baetyl_role_binding (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  user_id varchar(128) NOT NULL DEFAULT '', -- 用户id
+  role varchar(32) NOT NULL DEFAULT '', -- 角色 viewer/operator/admin
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, user_id)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_role_binding_update_time AFTER UPDATE ON baetyl_role_binding
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_role_binding SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 节点分组表
+CREATE TABLE IF NOT EXISTS baetyl_node_group (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  description varchar(1024) NOT NULL DEFAULT '', -- 描述信息
+  selector varchar(2048) NOT NULL DEFAULT '', -- 动态分组的标签选择器，为空则为静态分组
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_node_group_update_time AFTER UPDATE ON baetyl_node_group
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_node_group SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 审计日志表
+CREATE TABLE IF NOT EXISTS baetyl_audit_log (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  user_id varchar(128) NOT NULL DEFAULT '', -- 操作用户
+  request_id varchar(64) NOT NULL DEFAULT '', -- 请求id
+  method varchar(16) NOT NULL DEFAULT '', -- 请求方法
+  path varchar(512) NOT NULL DEFAULT '', -- 请求路径
+  resource varchar(64) NOT NULL DEFAULT '', -- 资源类型
+  name varchar(128) NOT NULL DEFAULT '', -- 资源名称
+  operation varchar(32) NOT NULL DEFAULT '', -- 操作 create/update/delete等
+  status int NOT NULL DEFAULT '0', -- http状态码
+  code varchar(64) NOT NULL DEFAULT '', -- 错误码，成功为空
+  diff text, -- 脱敏后的变更内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP -- 创建时间
+);
+CREATE INDEX IF NOT EXISTS baetyl_audit_log_idx_namespace_time ON baetyl_audit_log (namespace, create_time);
+CREATE INDEX IF NOT EXISTS baetyl_audit_log_idx_user_id ON baetyl_audit_log (user_id);
+
+-- 证书表
+CREATE TABLE IF NOT EXISTS baetyl_certificate (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- ID,主键
+  cert_id varchar(128) NOT NULL DEFAULT '', -- 证书id
+  parent_id varchar(64) NOT NULL DEFAULT '', -- 上级证书id
+  type varchar(64) NOT NULL DEFAULT '', -- 证书类型（根 CA、二级 CA、节点客户端证书、模块客户端证书、模块服务端证书）
+  common_name varchar(128) NOT NULL DEFAULT '', -- 常用名
+  description varchar(256) NOT NULL DEFAULT '', -- 描述信息
+  not_before datetime NOT NULL DEFAULT '2017-01-01 00:00:00', -- 证书生效时间
+  not_after datetime NOT NULL DEFAULT '2017-01-01 00:00:00', -- 记录失效时间
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 记录更新时间
+  csr text, -- csr请求生成证书的信息
+  content text, -- 证书内容
+  private_key text, -- 根证书private_key信息
+  UNIQUE (cert_id)
+);
+CREATE INDEX IF NOT EXISTS baetyl_certificate_idx_parent_id ON baetyl_certificate (parent_id);
+CREATE TRIGGER IF NOT EXISTS baetyl_certificate_update_time AFTER UPDATE ON baetyl_certificate
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_certificate SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- System configuration property table
+CREATE TABLE IF NOT EXISTS baetyl_property (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- primary key
+  name varchar(128) NOT NULL DEFAULT '', -- name
+  value text NOT NULL, -- value
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- create time
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- update time
+  UNIQUE (name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_property_update_time AFTER UPDATE ON baetyl_property
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_property SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 命名空间表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_namespace (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  name varchar(64) NOT NULL DEFAULT '', -- 名称
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  UNIQUE (name)
+);
+
+-- 节点表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_node (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_node_update_time AFTER UPDATE ON baetyl_node
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_node SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 应用表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_application (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_application_update_time AFTER UPDATE ON baetyl_application
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_application SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 配置表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_configuration (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_configuration_update_time AFTER UPDATE ON baetyl_configuration
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_configuration SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 密文表，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_secret (
+  id INTEGER PRIMARY KEY AUTOINCREMENT, -- 主键
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  name varchar(128) NOT NULL DEFAULT '', -- 名称
+  version varchar(36) NOT NULL DEFAULT '', -- 版本
+  content text, -- 内容
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  UNIQUE (namespace, name)
+);
+CREATE TRIGGER IF NOT EXISTS baetyl_secret_update_time AFTER UPDATE ON baetyl_secret
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_secret SET update_time = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- 资源版本序列，模型存储插件sql使用
+CREATE TABLE IF NOT EXISTS baetyl_resource_version (
+  id int NOT NULL, -- 主键
+  version bigint NOT NULL DEFAULT '0', -- 资源的最新版本
+  PRIMARY KEY (id)
+);
+
+-- 节点任务表
+CREATE TABLE IF NOT EXISTS baetyl_task (
+  trace_id varchar(36) NOT NULL DEFAULT '', -- 任务id
+  namespace varchar(64) NOT NULL DEFAULT '', -- 命名空间
+  node varchar(128) NOT NULL DEFAULT '', -- node名称
+  type varchar(32) NOT NULL DEFAULT '', -- 任务类型 command/ota
+  state varchar(16) NOT NULL DEFAULT '0', -- 任务状态
+  step text NOT NULL, -- 任务内容
+  old_version varchar(36) NOT NULL DEFAULT '', -- 原版本
+  new_version varchar(36) NOT NULL DEFAULT '', -- 新版本
+  create_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 创建时间
+  update_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 更新时间
+  PRIMARY KEY (trace_id)
+);
+CREATE INDEX IF NOT EXISTS baetyl_task_idx_node_type_state ON baetyl_task (namespace, node, type, state);
+CREATE TRIGGER IF NOT EXISTS baetyl_task_update_time AFTER UPDATE ON baetyl_task
+FOR EACH ROW WHEN NEW.update_time = OLD.update_time
+BEGIN
+  UPDATE baetyl_task SET update_time = CURRENT_TIMESTAMP WHERE trace_id = NEW.trace_id;
+END;
+
+-- 副本间的锁
+CREATE TABLE IF NOT EXISTS baetyl_lock (
+  name varchar(64) NOT NULL DEFAULT '', -- 锁名称
+  owner varchar(128) NOT NULL DEFAULT '', -- 持有者
+  expire_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, -- 过期时间
+  PRIMARY KEY (name)
+);
@@ -11,6 +11,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/api"
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
@@ -67,6 +68,10 @@ func (s *AdminServer) SetAPI(api *api.API) {
 func (s *AdminServer) Close() {
 	ctx, _ := context.WithTimeout(context.Background(), s.cfg.AdminServer.ShutdownTime)
 	s.server.Shutdown(ctx)
+	// the queued callbacks are sent before exiting, the callback service shared with the init server is closed here
+	if s.api != nil {
+		s.api.Callback.Close()
+	}
 }
 
 // InitRoute init router
@@ -75,12 +80,14 @@ func (s *AdminServer) InitRoute() {
 	s.router.NoMethod(NoMethodHandler)
 	s.router.GET("/health", Health)
 
+	s.router.Use(MetricsHandler("admin"))
 	s.router.Use(RequestIDHandler)
 	s.router.Use(LoggerHandler)
 	s.router.Use(s.AuthHandler)
+	s.router.Use(s.AuditHandler())
 	v1 := s.router.Group("v1")
 	{
-		configs := v1.Group("/configs")
+		configs := v1.Group("/configs", s.RBACHandler(models.ResourceConfig))
 		configs.GET("/:name", common.Wrapper(s.api.GetConfig))
 		configs.PUT("/:name", common.Wrapper(s.api.UpdateConfig))
 		configs.DELETE("/:name", common.Wrapper(s.api.DeleteConfig))
@@ -89,7 +96,7 @@ func (s *AdminServer) InitRoute() {
 		configs.GET("/:name/apps", common.Wrapper(s.api.GetAppByConfig))
 	}
 	{
-		registry := v1.Group("/registries")
+		registry := v1.Group("/registries", s.RBACHandler(models.ResourceRegistry))
 		registry.GET("/:name", common.Wrapper(s.api.GetRegistry))
 		registry.PUT("/:name", common.Wrapper(s.api.UpdateRegistry))
 		registry.POST("/:name/refresh", common.Wrapper(s.api.RefreshRegistryPassword))
@@ -99,7 +106,7 @@ func (s *AdminServer) InitRoute() {
 		registry.GET("/:name/apps", common.Wrapper(s.api.GetAppByRegistry))
 	}
 	{
-		certificate := v1.Group("/certificates")
+		certificate := v1.Group("/certificates", s.RBACHandler(models.ResourceCertificate))
 		certificate.GET("/:name", common.Wrapper(s.api.GetCertificate))
 		certificate.PUT("/:name", common.Wrapper(s.api.UpdateCertificate))
 		certificate.DELETE("/:name", common.Wrapper(s.api.DeleteCertificate))
@@ -108,7 +115,7 @@ func (s *AdminServer) InitRoute() {
 		certificate.GET("/:name/apps", common.Wrapper(s.api.GetAppByCertificate))
 	}
 	{
-		configs := v1.Group("/secrets")
+		configs := v1.Group("/secrets", s.RBACHandler(models.ResourceSecret))
 		configs.GET("/:name", common.Wrapper(s.api.GetSecret))
 		configs.PUT("/:name", common.Wrapper(s.api.UpdateSecret))
 		configs.DELETE("/:name", common.Wrapper(s.api.DeleteSecret))
@@ -117,7 +124,7 @@ func (s *AdminServer) InitRoute() {
 		configs.GET("/:name/apps", common.Wrapper(s.api.GetAppBySecret))
 	}
 	{
-		nodes := v1.Group("/nodes")
+		nodes := v1.Group("/nodes", s.RBACHandler(models.ResourceNode))
 		nodes.GET("/:name", common.Wrapper(s.api.GetNode))
 		nodes.PUT("", common.Wrapper(s.api.GetNodes))
 		nodes.GET("/:name/apps", common.Wrapper(s.api.GetAppByNode))
@@ -128,23 +135,109 @@ func (s *AdminServer) InitRoute() {
 		nodes.GET("", common.Wrapper(s.api.ListNode))
 		nodes.GET("/:name/deploys", common.Wrapper(s.api.GetNodeDeployHistory))
 		nodes.GET("/:name/init", common.Wrapper(s.api.GenInitCmdFromNode))
+
+		bulk := v1.Group("/bulk/nodes", s.RBACHandler(models.ResourceNode))
+		bulk.POST("", s.NodeQuotaHandler, common.Wrapper(s.api.CreateNodes))
+		bulk.POST("/delete", common.Wrapper(s.api.DeleteNodes))
+		bulk.POST("/init", common.Wrapper(s.api.GenInitCmdFromNodes))
+	}
+	{
+		commands := v1.Group("/nodes/:name/commands", s.RBACHandler(models.ResourceCommand))
+		commands.POST("", common.Wrapper(s.api.CreateCommand))
+		commands.GET("", common.Wrapper(s.api.ListCommand))
+		commands.GET("/:id", common.Wrapper(s.api.GetCommand))
+	}
+	{
+		ota := v1.Group("/ota", s.RBACHandler(models.ResourceOTA))
+		ota.POST("", common.Wrapper(s.api.CreateOTA))
+		nodeOTA := v1.Group("/nodes/:name/ota", s.RBACHandler(models.ResourceOTA))
+		nodeOTA.GET("", common.Wrapper(s.api.ListOTA))
+		nodeOTA.GET("/:id", common.Wrapper(s.api.GetOTA))
+		nodeOTA.POST("/:id/cancel", common.Wrapper(s.api.CancelOTA))
+		nodeOTA.POST("/:id/retry", common.Wrapper(s.api.RetryOTA))
+	}
+	{
+		groups := v1.Group("/groups", s.RBACHandler(models.ResourceNode))
+		groups.GET("/:name", common.Wrapper(s.api.GetNodeGroup))
+		groups.PUT("/:name", common.Wrapper(s.api.UpdateNodeGroup))
+		groups.DELETE("/:name", common.Wrapper(s.api.DeleteNodeGroup))
+		groups.POST("", common.Wrapper(s.api.CreateNodeGroup))
+		groups.GET("", common.Wrapper(s.api.ListNodeGroup))
+		groups.GET("/:name/nodes", common.Wrapper(s.api.ListNodeGroupNodes))
+		groups.PUT("/:name/nodes", common.Wrapper(s.api.UpdateNodeGroupNodes))
+		groups.PUT("/:name/labels", common.Wrapper(s.api.UpdateNodeGroupLabels))
+		groups.PUT("/:name/apps/:app", common.Wrapper(s.api.AssignNodeGroupApp))
+	}
+	{
+		bundle := v1.Group("/bundle", s.RBACHandler(models.ResourceBundle))
+		bundle.GET("", common.WrapperRaw(s.api.ExportBundle))
+		bundle.POST("/apply", common.Wrapper(s.api.ApplyBundle))
 	}
 	{
-		apps := v1.Group("/apps")
+		events := v1.Group("/events", s.RBACHandler(models.ResourceNode))
+		events.GET("/nodes", common.WrapperRaw(s.api.StreamNodeEvent))
+	}
+	{
+		apps := v1.Group("/apps", s.RBACHandler(models.ResourceApp))
 		apps.GET("/:name", common.Wrapper(s.api.GetApplication))
 		apps.PUT("/:name", common.Wrapper(s.api.UpdateApplication))
 		apps.DELETE("/:name", common.Wrapper(s.api.DeleteApplication))
+		apps.GET("/:name/versions", common.Wrapper(s.api.ListApplicationVersion))
+		apps.POST("/:name/rollback", common.Wrapper(s.api.RollbackApplication))
 		apps.POST("", common.Wrapper(s.api.CreateApplication))
 		apps.GET("", common.Wrapper(s.api.ListApplication))
 	}
 	{
-		namespace := v1.Group("/namespace")
+		rollouts := v1.Group("/rollouts", s.RBACHandler(models.ResourceApp))
+		rollouts.GET("/:name", common.Wrapper(s.api.GetRollout))
+		rollouts.GET("", common.Wrapper(s.api.ListRollout))
+		rollouts.POST("/:name/pause", common.Wrapper(s.api.PauseRollout))
+		rollouts.POST("/:name/resume", common.Wrapper(s.api.ResumeRollout))
+		rollouts.POST("/:name/abort", common.Wrapper(s.api.AbortRollout))
+	}
+	{
+		batches := v1.Group("/batches", s.RBACHandler(models.ResourceBatch))
+		batches.GET("/:name", common.Wrapper(s.api.GetBatch))
+		batches.PUT("/:name", common.Wrapper(s.api.UpdateBatch))
+		batches.DELETE("/:name", common.Wrapper(s.api.DeleteBatch))
+		batches.POST("", s.BatchQuotaHandler, common.Wrapper(s.api.CreateBatch))
+		batches.GET("", common.Wrapper(s.api.ListBatch))
+		batches.GET("/:name/records", common.Wrapper(s.api.ListRecord))
+		batches.POST("/:name/records", common.Wrapper(s.api.CreateRecord))
+		batches.POST("/:name/generate", common.Wrapper(s.api.GenRecordRandom))
+		batches.GET("/:name/records/:record", common.Wrapper(s.api.GetRecord))
+		batches.PUT("/:name/records/:record", common.Wrapper(s.api.UpdateRecord))
+		batches.DELETE("/:name/records/:record", common.Wrapper(s.api.DeleteRecord))
+	}
+	{
+		callbacks := v1.Group("/callbacks", s.RBACHandler(models.ResourceCallback))
+		callbacks.GET("/:name", common.Wrapper(s.api.GetCallback))
+		callbacks.PUT("/:name", common.Wrapper(s.api.UpdateCallback))
+		callbacks.DELETE("/:name", common.Wrapper(s.api.DeleteCallback))
+		callbacks.POST("", common.Wrapper(s.api.CreateCallback))
+		callbacks.GET("", common.Wrapper(s.api.ListCallback))
+		callbacks.GET("/:name/logs", common.Wrapper(s.api.ListCallbackLog))
+	}
+	{
+		rolebindings := v1.Group("/rolebindings", s.RBACHandler(models.ResourceRoleBinding))
+		rolebindings.GET("/:user", common.Wrapper(s.api.GetRoleBinding))
+		rolebindings.PUT("/:user", common.Wrapper(s.api.UpdateRoleBinding))
+		rolebindings.DELETE("/:user", common.Wrapper(s.api.DeleteRoleBinding))
+		rolebindings.POST("", common.Wrapper(s.api.CreateRoleBinding))
+		rolebindings.GET("", common.Wrapper(s.api.ListRoleBinding))
+	}
+	{
+		audits := v1.Group("/audits", s.RBACHandler(models.ResourceAudit))
+		audits.GET("", common.Wrapper(s.api.ListAudit))
+	}
+	{
+		namespace := v1.Group("/namespace", s.RBACHandler(models.ResourceNamespace))
 		namespace.POST("", common.Wrapper(s.api.CreateNamespace))
 		namespace.GET("", common.Wrapper(s.api.GetNamespace))
 		namespace.DELETE("", common.Wrapper(s.api.DeleteNamespace))
 	}
 	{
-		function := v1.Group("/functions")
+		function := v1.Group("/functions", s.RBACHandler(models.ResourceFunction))
 		function.GET("", common.Wrapper(s.api.ListFunctionSources))
 		if len(s.cfg.Plugin.Functions) != 0 {
 			function.GET("/:source/functions", common.Wrapper(s.api.ListFunctions))
@@ -154,7 +247,7 @@ func (s *AdminServer) InitRoute() {
 	}
 	{
 		// Deprecated
-		objects := v1.Group("/objects")
+		objects := v1.Group("/objects", s.RBACHandler(models.ResourceObject))
 		objects.GET("", common.Wrapper(s.api.ListObjectSources))
 		if len(s.cfg.Plugin.Objects) != 0 {
 			objects.GET("/:source/buckets", common.Wrapper(s.api.ListBuckets))
@@ -163,11 +256,11 @@ func (s *AdminServer) InitRoute() {
 	}
 
 	{
-		properties := v1.Group("properties")
+		properties := v1.Group("properties", s.RBACHandler(models.ResourceProperty))
 		properties.GET("/:name", common.Wrapper(s.api.GetProperty))
 
 		// TODO: deprecated, to use property api
-		sysconfig := v1.Group("sysconfig")
+		sysconfig := v1.Group("sysconfig", s.RBACHandler(models.ResourceProperty))
 		sysconfig.GET("/baetyl_version/latest", common.Wrapper(func(c *common.Context) (interface{}, error) {
 			v, err := s.api.Prop.GetPropertyValue("baetyl-version-latest")
 			if err != nil {
@@ -199,13 +292,13 @@ func (s *AdminServer) InitRoute() {
 		}))
 	}
 	{
-		quotas := v1.Group("/quotas")
+		quotas := v1.Group("/quotas", s.RBACHandler(models.ResourceQuota))
 		quotas.GET("", common.Wrapper(s.api.GetQuota))
 	}
 
 	v2 := s.router.Group("v2")
 	{
-		objects := v2.Group("/objects")
+		objects := v2.Group("/objects", s.RBACHandler(models.ResourceObject))
 		objects.GET("", common.Wrapper(s.api.ListObjectSourcesV2))
 		if len(s.cfg.Plugin.Objects) != 0 {
 			objects.GET("/:source/buckets", common.Wrapper(s.api.ListBucketsV2))
@@ -233,6 +326,35 @@ func (s *AdminServer) AuthHandler(c *gin.Context) {
 	}
 }
 
+// the routes which read resources by the methods other than GET and HEAD
+var readRoutes = map[string]bool{
+	http.MethodPut + " /v1/nodes":            true,
+	http.MethodPost + " /v1/bulk/nodes/init": true,
+}
+
+// RBACHandler authorizes the user of the request by the role bound in the namespace,
+// GET and HEAD requests read the resource and others write the resource
+func (s *AdminServer) RBACHandler(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cc := common.NewContext(c)
+		verb := models.VerbWrite
+		method := c.Request.Method
+		if method == http.MethodGet || method == http.MethodHead || readRoutes[method+" "+c.FullPath()] {
+			verb = models.VerbRead
+		}
+		if err := s.api.RBAC.Authorize(cc.GetNamespace(), cc.GetUser().ID, resource, verb); err != nil {
+			log.L().Error("request authorize failed",
+				log.Any(cc.GetTrace()),
+				log.Any("namespace", cc.GetNamespace()),
+				log.Any("user", cc.GetUser().ID),
+				log.Any("resource", resource),
+				log.Any("verb", verb),
+				log.Error(err))
+			common.PopulateFailedResponse(cc, err, true)
+		}
+	}
+}
+
 func (s *AdminServer) NodeQuotaHandler(c *gin.Context) {
 	cc := common.NewContext(c)
 	namespace := cc.GetNamespace()
@@ -244,3 +366,15 @@ func (s *AdminServer) NodeQuotaHandler(c *gin.Context) {
 		common.PopulateFailedResponse(cc, err, true)
 	}
 }
+
+func (s *AdminServer) BatchQuotaHandler(c *gin.Context) {
+	cc := common.NewContext(c)
+	namespace := cc.GetNamespace()
+	if err := s.api.License.CheckQuota(namespace, s.api.BatchNumberCollector); err != nil {
+		log.L().Error("quota out of limit",
+			log.Any(cc.GetTrace()),
+			log.Any("namespace", cc.GetNamespace()),
+			log.Error(err))
+		common.PopulateFailedResponse(cc, err, true)
+	}
+}
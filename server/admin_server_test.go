@@ -34,6 +34,7 @@ func initAdminServerMock(t *testing.T) (*AdminServer, *mockPlugin.MockAuth, *moc
 	c.Plugin.Functions = []string{common.RandString(9)}
 	c.Plugin.License = common.RandString(9)
 	c.Plugin.Property = common.RandString(9)
+	c.Plugin.Pubsub = common.RandString(9)
 	mockCtl := gomock.NewController(t)
 
 	mockModelStorage := mockPlugin.NewMockModelStorage(mockCtl)
@@ -77,6 +78,10 @@ func initAdminServerMock(t *testing.T) (*AdminServer, *mockPlugin.MockAuth, *moc
 	plugin.RegisterFactory(c.Plugin.Property, func() (plugin.Plugin, error) {
 		return mockProperty, nil
 	})
+	mockPubsub := mockPlugin.NewMockPubsub(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Pubsub, func() (plugin.Plugin, error) {
+		return mockPubsub, nil
+	})
 
 	mockAPI, err := api.NewAPI(c)
 	assert.NoError(t, err)
@@ -137,6 +142,9 @@ func TestAdminServer_Handler(t *testing.T) {
 	mLicense := service.NewMockLicenseService(mockCtl)
 	s.api.License = mLicense
 	mLicense.EXPECT().CheckQuota(gomock.Any(), gomock.Any()).Return(fmt.Errorf("quota error"))
+	mAudit := service.NewMockAuditService(mockCtl)
+	s.api.Audit = mAudit
+	mAudit.EXPECT().Record(gomock.Any(), nil, nil).Return(nil)
 	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes", nil)
 	w4 = httptest.NewRecorder()
 	s.GetRoute().ServeHTTP(w4, req)
@@ -145,3 +153,103 @@ func TestAdminServer_Handler(t *testing.T) {
 	go s.Run()
 	defer s.Close()
 }
+
+func TestAdminServer_RBACHandler(t *testing.T) {
+	s, mkAuth, _, mockCtl := initAdminServerMock(t)
+	defer mockCtl.Finish()
+	s.InitRoute()
+
+	mRBAC := service.NewMockRBACService(mockCtl)
+	s.api.RBAC = mRBAC
+	mAudit := service.NewMockAuditService(mockCtl)
+	s.api.Audit = mAudit
+	mAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mSecret := service.NewMockSecretService(mockCtl)
+	s.api.Secret = mSecret
+	mSecret.EXPECT().Get("default", "abc", "").Return(nil, nil)
+	mkAuth.EXPECT().Authenticate(gomock.Any()).DoAndReturn(func(c *common.Context) error {
+		c.SetNamespace("default")
+		c.SetUser(common.User{ID: "user01"})
+		return nil
+	}).AnyTimes()
+
+	forbidden := common.Error(common.ErrRequestForbidden,
+		common.Field("user", "user01"),
+		common.Field("verb", models.VerbWrite),
+		common.Field("resource", models.ResourceSecret),
+		common.Field("namespace", "default"))
+	mRBAC.EXPECT().Authorize("default", "user01", models.ResourceSecret, models.VerbWrite).Return(forbidden)
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/secrets/abc", nil)
+	w := httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), common.ErrRequestForbidden)
+
+	mRBAC.EXPECT().Authorize("default", "user01", models.ResourceConfig, models.VerbRead).Return(nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs", nil)
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// PUT /v1/nodes reads nodes
+	mRBAC.EXPECT().Authorize("default", "user01", models.ResourceNode, models.VerbRead).Return(forbidden)
+	req, _ = http.NewRequest(http.MethodPut, "/v1/nodes", nil)
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	mRBAC.EXPECT().Authorize("default", "user01", models.ResourceApp, models.VerbWrite).Return(forbidden)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/rollouts/r1/pause", nil)
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	mRBAC.EXPECT().Authorize("default", "user01", models.ResourceRoleBinding, models.VerbRead).Return(nil)
+	mRBAC.EXPECT().ListRoleBinding("default", gomock.Any()).Return(&models.ListView{}, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/rolebindings", nil)
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAdminServer_RBACHandlerDenyViewer(t *testing.T) {
+	s, mkAuth, _, mockCtl := initAdminServerMock(t)
+	defer mockCtl.Finish()
+	s.InitRoute()
+
+	mRBAC := service.NewMockRBACService(mockCtl)
+	s.api.RBAC = mRBAC
+	mAudit := service.NewMockAuditService(mockCtl)
+	s.api.Audit = mAudit
+	mAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mkAuth.EXPECT().Authenticate(gomock.Any()).DoAndReturn(func(c *common.Context) error {
+		c.SetNamespace("default")
+		c.SetUser(common.User{ID: "viewer01"})
+		return nil
+	}).AnyTimes()
+	// the user is bound to the viewer role
+	mRBAC.EXPECT().Authorize("default", "viewer01", gomock.Any(), gomock.Any()).DoAndReturn(
+		func(namespace, user, resource, verb string) error {
+			if models.RoleAllows(models.RoleViewer, resource, verb) {
+				return nil
+			}
+			return common.Error(common.ErrRequestForbidden,
+				common.Field("user", user),
+				common.Field("verb", verb),
+				common.Field("resource", resource),
+				common.Field("namespace", namespace))
+		}).AnyTimes()
+
+	for _, r := range []struct{ method, url string }{
+		{http.MethodDelete, "/v1/namespace"},
+		{http.MethodPost, "/v1/callbacks"},
+		{http.MethodPost, "/v1/batches"},
+		{http.MethodPost, "/v1/functions/" + s.cfg.Plugin.Functions[0] + "/functions/f1/versions/v1"},
+	} {
+		req, _ := http.NewRequest(r.method, r.url, nil)
+		w := httptest.NewRecorder()
+		s.GetRoute().ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, r.url)
+		assert.Contains(t, w.Body.String(), common.ErrRequestForbidden)
+	}
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/api"
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// the max size of the response body kept for audit
+const maxAuditBodySize = 1 << 20
+
+// auditWriter keeps the response body for audit
+type auditWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *auditWriter) Write(b []byte) (int, error) {
+	if w.body.Len()+len(b) <= maxAuditBodySize {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	if w.body.Len()+len(s) <= maxAuditBodySize {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// auditor records the mutating requests of a server into the audit log
+type auditor struct {
+	api *api.API
+	// the handlers to read the resources before changed, by route
+	getters map[string]common.HandlerFunc
+	// returns the user of the request
+	user func(c *common.Context) string
+	// returns the resource in the response body
+	unwrap func(body []byte) []byte
+}
+
+// handle records the POST/PUT/DELETE requests with the states of the resources before and after the requests,
+// failed requests are recorded with the error codes and change nothing, the requests handled in dry run are not recorded,
+// while the routes ignoring the dry run are recorded as usual
+func (a *auditor) handle(c *gin.Context) {
+	method, route := c.Request.Method, c.FullPath()
+	if (method != http.MethodPost && method != http.MethodPut && method != http.MethodDelete) ||
+		readRoutes[method+" "+route] {
+		c.Next()
+		return
+	}
+	cc := common.NewContext(c)
+	var before []byte
+	if getter, ok := a.getters[route]; ok {
+		if res, err := getter(cc); err == nil && res != nil {
+			before, _ = json.Marshal(res)
+		}
+	}
+	w := &auditWriter{ResponseWriter: c.Writer, body: new(bytes.Buffer)}
+	c.Writer = w
+	c.Next()
+	if cc.IsDryRunHandled() {
+		return
+	}
+
+	audit := &models.AuditLog{
+		Namespace: cc.GetNamespace(),
+		User:      a.user(cc),
+		RequestID: w.Header().Get(common.GetTraceHeader()),
+		Method:    method,
+		Path:      c.Request.URL.Path,
+		Status:    w.Status(),
+	}
+	audit.Resource, audit.Operation = auditOperation(method, route)
+	after := before
+	if len(c.Errors) > 0 {
+		audit.Code = common.ErrUnknown
+		if e, ok := c.Errors.Last().Err.(errors.Coder); ok {
+			audit.Code = e.Code()
+		}
+	} else if method == http.MethodDelete {
+		after = nil
+	} else {
+		after = a.unwrap(w.body.Bytes())
+	}
+	audit.Name = auditName(c, before, after)
+	if err := a.api.Audit.Record(audit, before, after); err != nil {
+		log.L().Error("failed to record audit log",
+			log.Any(cc.GetTrace()),
+			log.Any("path", audit.Path),
+			log.Error(err))
+	}
+}
+
+// auditOperation returns the resource and the operation of the route, e.g.
+// POST /v1/apps is create, POST /v1/apps/:name/rollback is rollback
+func auditOperation(method, route string) (string, string) {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	if len(segments) > 1 {
+		// skip the version
+		segments = segments[1:]
+	}
+	resource := segments[0]
+	switch method {
+	case http.MethodPut:
+		return resource, models.AuditUpdate
+	case http.MethodDelete:
+		return resource, models.AuditDelete
+	}
+	last := segments[len(segments)-1]
+	if len(segments) == 1 || strings.HasPrefix(last, ":") {
+		return resource, models.AuditCreate
+	}
+	return resource, last
+}
+
+// auditName returns the resource name from the route params, or from the resource states
+func auditName(c *gin.Context, before, after []byte) string {
+	for _, p := range []string{"name", "user"} {
+		if v := c.Param(p); v != "" {
+			return v
+		}
+	}
+	for _, state := range [][]byte{after, before} {
+		var res struct {
+			Name string `json:"name"`
+		}
+		if len(state) > 0 && json.Unmarshal(state, &res) == nil && res.Name != "" {
+			return res.Name
+		}
+	}
+	return ""
+}
+
+// unwrapMisResponse returns the data of the mis response
+func unwrapMisResponse(body []byte) []byte {
+	var res struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil
+	}
+	return res.Data
+}
+
+func unwrapResponse(body []byte) []byte {
+	return body
+}
+
+// AuditHandler records the mutating requests of the admin server
+func (s *AdminServer) AuditHandler() gin.HandlerFunc {
+	a := &auditor{
+		api: s.api,
+		getters: map[string]common.HandlerFunc{
+			"/v1/configs/:name":            s.api.GetConfig,
+			"/v1/registries/:name":         s.api.GetRegistry,
+			"/v1/registries/:name/refresh": s.api.GetRegistry,
+			"/v1/certificates/:name":       s.api.GetCertificate,
+			"/v1/secrets/:name":            s.api.GetSecret,
+			"/v1/nodes/:name":              s.api.GetNode,
+			"/v1/apps/:name":               s.api.GetApplication,
+			"/v1/apps/:name/rollback":      s.api.GetApplication,
+			"/v1/batches/:name":            s.api.GetBatch,
+			"/v1/callbacks/:name":          s.api.GetCallback,
+			"/v1/rolebindings/:user":       s.api.GetRoleBinding,
+		},
+		user: func(c *common.Context) string {
+			return c.GetUser().ID
+		},
+		unwrap: unwrapResponse,
+	}
+	return a.handle
+}
+
+// auditHandler records the mutating requests of the mis server
+func (s *MisServer) auditHandler() gin.HandlerFunc {
+	a := &auditor{
+		api: s.api,
+		getters: map[string]common.HandlerFunc{
+			"/v1/properties/:name": s.api.GetProperty,
+			"/v1/apikeys/:name":    s.api.GetAPIKey,
+		},
+		user: func(c *common.Context) string {
+			return c.GetHeader(s.cfg.MisServer.UserHeader)
+		},
+		unwrap: unwrapMisResponse,
+	}
+	return a.handle
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAdminServer_AuditHandler(t *testing.T) {
+	s, mkAuth, _, mockCtl := initAdminServerMock(t)
+	defer mockCtl.Finish()
+	s.InitRoute()
+
+	mRBAC := service.NewMockRBACService(mockCtl)
+	s.api.RBAC = mRBAC
+	mAudit := service.NewMockAuditService(mockCtl)
+	s.api.Audit = mAudit
+	mkAuth.EXPECT().Authenticate(gomock.Any()).DoAndReturn(func(c *common.Context) error {
+		c.SetNamespace("default")
+		c.SetUser(common.User{ID: "admin01"})
+		return nil
+	}).AnyTimes()
+
+	// update with the states before and after
+	viewer := &models.RoleBinding{Namespace: "default", User: "user01", Role: models.RoleViewer}
+	operator := &models.RoleBinding{Namespace: "default", User: "user01", Role: models.RoleOperator}
+	mRBAC.EXPECT().Authorize("default", "admin01", models.ResourceRoleBinding, models.VerbWrite).Return(nil)
+	mRBAC.EXPECT().GetRoleBinding("default", "user01").Return(viewer, nil).Times(2)
+	mRBAC.EXPECT().UpdateRoleBinding(gomock.Any()).Return(operator, nil)
+	mAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(a *models.AuditLog, before, after []byte) error {
+		assert.Equal(t, "default", a.Namespace)
+		assert.Equal(t, "admin01", a.User)
+		assert.NotEmpty(t, a.RequestID)
+		assert.Equal(t, http.MethodPut, a.Method)
+		assert.Equal(t, "/v1/rolebindings/user01", a.Path)
+		assert.Equal(t, models.ResourceRoleBinding, a.Resource)
+		assert.Equal(t, "user01", a.Name)
+		assert.Equal(t, models.AuditUpdate, a.Operation)
+		assert.Equal(t, http.StatusOK, a.Status)
+		assert.Empty(t, a.Code)
+		assert.Contains(t, string(before), `"role":"viewer"`)
+		assert.Contains(t, string(after), `"role":"operator"`)
+		return nil
+	})
+	body, _ := json.Marshal(map[string]string{"role": models.RoleOperator})
+	req, _ := http.NewRequest(http.MethodPut, "/v1/rolebindings/user01", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the failed request changes nothing
+	forbidden := common.Error(common.ErrRequestForbidden,
+		common.Field("user", "admin01"),
+		common.Field("verb", models.VerbWrite),
+		common.Field("resource", models.ResourceRoleBinding))
+	mRBAC.EXPECT().GetRoleBinding("default", "user01").Return(viewer, nil)
+	mRBAC.EXPECT().Authorize("default", "admin01", models.ResourceRoleBinding, models.VerbWrite).Return(forbidden)
+	mAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(a *models.AuditLog, before, after []byte) error {
+		assert.Equal(t, models.AuditDelete, a.Operation)
+		assert.Equal(t, http.StatusForbidden, a.Status)
+		assert.Equal(t, common.ErrRequestForbidden, a.Code)
+		assert.Equal(t, before, after)
+		return nil
+	})
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/rolebindings/user01", nil)
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// reads are not recorded
+	mRBAC.EXPECT().Authorize("default", "admin01", models.ResourceRoleBinding, models.VerbRead).Return(nil)
+	mRBAC.EXPECT().GetRoleBinding("default", "user01").Return(viewer, nil)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/rolebindings/user01", nil)
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the dry runs handled are not recorded
+	mConfig := service.NewMockConfigService(mockCtl)
+	s.api.Config = mConfig
+	mRBAC.EXPECT().Authorize("default", "admin01", models.ResourceConfig, models.VerbWrite).Return(nil)
+	mConfig.EXPECT().Get("default", "c1", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	body, _ = json.Marshal(map[string]string{"name": "c1"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs?dryRun=true", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the dry runs rejected before handled are recorded
+	mRBAC.EXPECT().Authorize("default", "admin01", models.ResourceConfig, models.VerbWrite).Return(forbidden)
+	mAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(a *models.AuditLog, before, after []byte) error {
+		assert.Equal(t, models.AuditCreate, a.Operation)
+		assert.Equal(t, http.StatusForbidden, a.Status)
+		return nil
+	})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs?dryRun=true", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// the dry run is ignored by the route, so the request is recorded as usual
+	mRBAC.EXPECT().Authorize("default", "admin01", models.ResourceRoleBinding, models.VerbWrite).Return(nil)
+	mRBAC.EXPECT().GetRoleBinding("default", "user01").Return(viewer, nil).Times(2)
+	mRBAC.EXPECT().UpdateRoleBinding(gomock.Any()).Return(operator, nil)
+	mAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(a *models.AuditLog, before, after []byte) error {
+		assert.Equal(t, models.AuditUpdate, a.Operation)
+		assert.Contains(t, string(after), `"role":"operator"`)
+		return nil
+	})
+	body, _ = json.Marshal(map[string]string{"role": models.RoleOperator})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/rolebindings/user01?dryRun=true", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestMisServer_AuditHandler(t *testing.T) {
+	s, mockCtl := initMisServerMock(t)
+	defer mockCtl.Finish()
+	s.cfg.MisServer.UserHeader = "baetyl-cloud-user"
+	s.InitRoute()
+
+	mKey := service.NewMockAPIKeyService(mockCtl)
+	s.api.APIKey = mKey
+	mAudit := service.NewMockAuditService(mockCtl)
+	s.api.Audit = mAudit
+
+	key := &models.APIKey{Name: "key01", User: "user01", Namespaces: []string{"default"}}
+	mKey.EXPECT().Get("key01").Return(key, nil).Times(2)
+	mKey.EXPECT().Delete("key01").Return(nil)
+	mAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(a *models.AuditLog, before, after []byte) error {
+		assert.Empty(t, a.Namespace)
+		assert.Equal(t, "mis01", a.User)
+		assert.Equal(t, "apikeys", a.Resource)
+		assert.Equal(t, "key01", a.Name)
+		assert.Equal(t, models.AuditDelete, a.Operation)
+		assert.Contains(t, string(before), `"name":"key01"`)
+		assert.Nil(t, after)
+		return nil
+	})
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/apikeys/key01", nil)
+	req.Header.Set("baetyl-cloud-user", "mis01")
+	w := httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the resource is unwrapped from the mis response
+	mKey.EXPECT().Create(gomock.Any()).Return(&models.APIKey{Name: "key02", Key: "bk_123"}, nil)
+	mAudit.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(a *models.AuditLog, before, after []byte) error {
+		assert.Equal(t, models.AuditCreate, a.Operation)
+		assert.Equal(t, "key02", a.Name)
+		assert.Nil(t, before)
+		assert.Contains(t, string(after), `"key":"bk_123"`)
+		return nil
+	})
+	body, _ := json.Marshal(map[string]interface{}{"name": "key02", "user": "user01", "namespaces": []string{"default"}})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apikeys", bytes.NewReader(body))
+	req.Header.Set("baetyl-cloud-user", "mis01")
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuditOperation(t *testing.T) {
+	tests := []struct {
+		method, route, resource, operation string
+	}{
+		{http.MethodPost, "/v1/apps", "apps", models.AuditCreate},
+		{http.MethodPut, "/v1/apps/:name", "apps", models.AuditUpdate},
+		{http.MethodDelete, "/v1/apps/:name", "apps", models.AuditDelete},
+		{http.MethodPost, "/v1/apps/:name/rollback", "apps", "rollback"},
+		{http.MethodPost, "/v1/batches/:name/records", "batches", "records"},
+		{http.MethodPost, "/v1/functions/:source/functions/:name/versions/:version", "functions", models.AuditCreate},
+	}
+	for _, tt := range tests {
+		resource, operation := auditOperation(tt.method, tt.route)
+		assert.Equal(t, tt.resource, resource, tt.route)
+		assert.Equal(t, tt.operation, operation, tt.route)
+	}
+}
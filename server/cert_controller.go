@@ -0,0 +1,75 @@
+package server
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// the lock held by the replica renewing the certificates
+const certLock = "certificate"
+
+// CertController renews the expiring node certificates periodically, the replicas renew the certificates in turn
+type CertController struct {
+	cfg     *config.CloudConfig
+	manager service.CertManagerService
+	lock    service.LockService
+	done    chan struct{}
+}
+
+// NewCertController create certificate controller
+func NewCertController(config *config.CloudConfig) (*CertController, error) {
+	manager, err := service.NewCertManagerService(config)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := service.NewLockService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &CertController{
+		cfg:     config,
+		manager: manager,
+		lock:    lock,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Run reconcile the certificates at start and then periodically until closed
+func (r *CertController) Run() {
+	ticker := time.NewTicker(r.cfg.CertManager.Interval)
+	defer ticker.Stop()
+	for {
+		r.reconcile()
+		select {
+		case <-ticker.C:
+		case <-r.done:
+			log.L().Info("certificate controller stopped")
+			return
+		}
+	}
+}
+
+// reconcile renews the certificates only if the lock is held, so that no certificate is renewed twice by the replicas
+func (r *CertController) reconcile() {
+	ok, err := r.lock.Lock(certLock, r.cfg.CertManager.LockTimeout)
+	if err != nil {
+		log.L().Error("failed to lock certificates", log.Error(err))
+		return
+	}
+	if !ok {
+		return
+	}
+	defer r.lock.Unlock(certLock)
+	if err = r.manager.Reconcile(); err != nil {
+		log.L().Error("failed to reconcile certificates", log.Error(err))
+	}
+}
+
+// Close close controller
+func (r *CertController) Close() {
+	close(r.done)
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+)
+
+func TestCertController(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sCertManager := ms.NewMockCertManagerService(mockCtl)
+	sLock := ms.NewMockLockService(mockCtl)
+
+	cfg := &config.CloudConfig{}
+	cfg.CertManager.Interval = time.Millisecond
+	cfg.CertManager.LockTimeout = time.Minute
+	cc := &CertController{
+		cfg:     cfg,
+		manager: sCertManager,
+		lock:    sLock,
+		done:    make(chan struct{}),
+	}
+
+	// skipped if the lock is held by another replica or fails
+	sLock.EXPECT().Lock(certLock, time.Minute).Return(false, nil)
+	cc.reconcile()
+	sLock.EXPECT().Lock(certLock, time.Minute).Return(false, fmt.Errorf("error"))
+	cc.reconcile()
+
+	sLock.EXPECT().Lock(certLock, time.Minute).Return(true, nil).AnyTimes()
+	sLock.EXPECT().Unlock(certLock).AnyTimes()
+	reconciled := make(chan struct{})
+	sCertManager.EXPECT().Reconcile().DoAndReturn(func() error {
+		close(reconciled)
+		return nil
+	})
+	sCertManager.EXPECT().Reconcile().Return(nil).AnyTimes()
+
+	stopped := make(chan struct{})
+	go func() {
+		cc.Run()
+		close(stopped)
+	}()
+	select {
+	case <-reconciled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("certificates are not reconciled")
+	}
+	cc.Close()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("certificate controller is not stopped")
+	}
+}
@@ -68,24 +68,43 @@ func ExtractNodeCommonNameFromCert(c *gin.Context) {
 		return
 	}
 	cert := c.Request.TLS.PeerCertificates[0]
-	extractNodeCommonName(cc, cert.Subject.CommonName)
+	extractNodeCommonName(cc, "", cert.Subject.CommonName)
 }
 
 func ExtractNodeCommonNameFromHeader(c *gin.Context) {
 	cc := common.NewContext(c)
-	extractNodeCommonName(cc, c.GetHeader(HeaderCommonName))
+	extractNodeCommonName(cc, HeaderCommonName, c.GetHeader(HeaderCommonName))
 }
 
-func extractNodeCommonName(cc *common.Context, commonName string) {
+// ExtractNodeCommonNameFromHeaderOf returns the handler extracting the common name of the node from the given header,
+// so that the servers are able to use their own headers
+func ExtractNodeCommonNameFromHeaderOf(header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cc := common.NewContext(c)
+		extractNodeCommonName(cc, header, c.GetHeader(header))
+	}
+}
+
+// ParseNodeCommonName returns the namespace and the name of the node from the common name of its certificate,
+// which is formatted as {namespace}.{name}
+func ParseNodeCommonName(commonName string) (string, string, bool) {
 	res := strings.SplitN(commonName, ".", 2)
 	if len(res) != 2 || res[0] == "" || res[1] == "" {
+		return "", "", false
+	}
+	return res[0], res[1], true
+}
+
+func extractNodeCommonName(cc *common.Context, header, commonName string) {
+	ns, n, ok := ParseNodeCommonName(commonName)
+	if !ok {
 		log.L().Error("extract node common name error",
 			log.Any(cc.GetTrace()),
 			log.Any("commonName", commonName),
-			log.Any("HeaderCommonName", HeaderCommonName))
+			log.Any("HeaderCommonName", header))
 		common.PopulateFailedResponse(cc, common.Error(common.ErrRequestAccessDenied), true)
 		return
 	}
-	cc.SetNamespace(res[0])
-	cc.SetName(res[1])
+	cc.SetNamespace(ns)
+	cc.SetName(n)
 }
@@ -82,6 +82,7 @@ func (s *InitServer) InitRoute() {
 	s.router.NoMethod(NoMethodHandler)
 	s.router.GET("/health", Health)
 
+	s.router.Use(MetricsHandler("init"))
 	s.router.Use(RequestIDHandler)
 	s.router.Use(LoggerHandler)
 	v1 := s.router.Group("v1")
@@ -94,4 +95,8 @@ func (s *InitServer) InitRoute() {
 		initz := v1.Group("/init")
 		initz.GET("/:resource", common.WrapperRaw(s.api.GetResource))
 	}
+	{
+		activate := v1.Group("/activate")
+		activate.POST("", common.WrapperRaw(s.api.Activate))
+	}
 }
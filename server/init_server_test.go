@@ -16,6 +16,7 @@ import (
 	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 func initInitServerMock(t *testing.T) (*InitServer, *gomock.Controller, *config.CloudConfig) {
@@ -29,6 +30,7 @@ func initInitServerMock(t *testing.T) (*InitServer, *gomock.Controller, *config.
 	c.Plugin.Functions = []string{common.RandString(9)}
 	c.Plugin.License = common.RandString(9)
 	c.Plugin.Property = common.RandString(9)
+	c.Plugin.Pubsub = common.RandString(9)
 	c.InitServer.Certificate.CA = "../scripts/demo/native/certs/client_ca.crt"
 	c.InitServer.Certificate.Cert = "../scripts/demo/native/certs/server.crt"
 	c.InitServer.Certificate.Key = "../scripts/demo/native/certs/server.key"
@@ -75,8 +77,14 @@ func initInitServerMock(t *testing.T) (*InitServer, *gomock.Controller, *config.
 	plugin.RegisterFactory(c.Plugin.Property, func() (plugin.Plugin, error) {
 		return mockProperty, nil
 	})
+	mockPubsub := mockPlugin.NewMockPubsub(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Pubsub, func() (plugin.Plugin, error) {
+		return mockPubsub, nil
+	})
 
-	mockInitAPI, err := api.NewInitAPI(c)
+	callbackService, err := service.NewCallbackService(c)
+	assert.NoError(t, err)
+	mockInitAPI, err := api.NewInitAPI(c, callbackService)
 	assert.NoError(t, err)
 
 	s, err := NewInitServer(c)
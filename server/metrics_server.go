@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+
+	"github.com/baetyl/baetyl-cloud/v2/common/metrics"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+// the route label of the requests matching no route, to keep the series bounded
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = metrics.NewCounterVec("baetyl_cloud_http_requests_total",
+		"The number of http requests handled by the servers.", "server", "method", "route", "code")
+	httpRequestDuration = metrics.NewHistogramVec("baetyl_cloud_http_request_duration_seconds",
+		"The latency of http requests handled by the servers.", metrics.DefBuckets, "server", "method", "route")
+)
+
+func init() {
+	metrics.MustRegister(httpRequests, httpRequestDuration)
+}
+
+// MetricsHandler returns the handler recording the requests of the server by route
+func MetricsHandler(server string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+		httpRequests.WithLabelValues(server, method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpRequestDuration.WithLabelValues(server, method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsServer exposes the metrics of all servers
+type MetricsServer struct {
+	cfg    *config.CloudConfig
+	router *gin.Engine
+	server *http.Server
+}
+
+// NewMetricsServer create metrics server
+func NewMetricsServer(config *config.CloudConfig) (*MetricsServer, error) {
+	router := gin.New()
+	server := &http.Server{
+		Addr:           config.MetricsServer.Port,
+		Handler:        router,
+		ReadTimeout:    config.MetricsServer.ReadTimeout,
+		WriteTimeout:   config.MetricsServer.WriteTimeout,
+		MaxHeaderBytes: 1 << 20,
+	}
+	return &MetricsServer{
+		cfg:    config,
+		router: router,
+		server: server,
+	}, nil
+}
+
+// InitRoute init router
+func (s *MetricsServer) InitRoute() {
+	s.router.NoRoute(NoRouteHandler)
+	s.router.NoMethod(NoMethodHandler)
+	s.router.GET("/health", Health)
+	s.router.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
+
+// GetRoute get router
+func (s *MetricsServer) GetRoute() *gin.Engine {
+	return s.router
+}
+
+func (s *MetricsServer) Run() {
+	if err := s.server.ListenAndServe(); err != nil {
+		log.L().Info("metrics server stopped", log.Error(err))
+	}
+}
+
+// Close close server
+func (s *MetricsServer) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.MetricsServer.ShutdownTime)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestMetricsServer(t *testing.T) {
+	as, mkAuth, _, mockCtl := initAdminServerMock(t)
+	defer mockCtl.Finish()
+	as.InitRoute()
+
+	mkAuth.EXPECT().Authenticate(gomock.Any()).Return(nil).Times(2)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/configs", nil)
+	w := httptest.NewRecorder()
+	as.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/zzz", nil)
+	w = httptest.NewRecorder()
+	as.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	s, err := NewMetricsServer(&config.CloudConfig{})
+	assert.NoError(t, err)
+	s.InitRoute()
+	req, _ = http.NewRequest(http.MethodGet, "/metrics", nil)
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	body := w.Body.String()
+	assert.Contains(t, body, "# TYPE baetyl_cloud_http_requests_total counter")
+	assert.Contains(t, body, `baetyl_cloud_http_requests_total{code="200",method="GET",route="/v1/configs",server="admin"}`)
+	assert.Contains(t, body, `baetyl_cloud_http_requests_total{code="404",method="GET",route="unmatched",server="admin"}`)
+	assert.Contains(t, body, `baetyl_cloud_http_request_duration_seconds_count{method="GET",route="/v1/configs",server="admin"}`)
+	// the metrics without samples are not exposed, but they are registered
+	for _, name := range []string{"baetyl_cloud_shadow_update_duration_seconds", "baetyl_cloud_sync_requests_total", "baetyl_cloud_nodes"} {
+		assert.Error(t, prometheus.Register(prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: name})), name)
+	}
+
+	go s.Run()
+	defer s.Close()
+}
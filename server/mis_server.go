@@ -65,9 +65,11 @@ func (s *MisServer) InitRoute() {
 	s.router.NoMethod(NoMethodHandler)
 	s.router.GET("/health", Health)
 
+	s.router.Use(MetricsHandler("mis"))
 	s.router.Use(RequestIDHandler)
 	s.router.Use(LoggerHandler)
 	s.router.Use(s.authHandler)
+	s.router.Use(s.auditHandler())
 	v1 := s.router.Group("v1")
 	{
 		cache := v1.Group("/properties")
@@ -76,6 +78,21 @@ func (s *MisServer) InitRoute() {
 		cache.DELETE("/:name", common.WrapperMis(s.api.DeleteProperty))
 		cache.GET("", common.WrapperMis(s.api.ListProperty))
 		cache.PUT("/:name", common.WrapperMis(s.api.UpdateProperty))
+
+		apikeys := v1.Group("/apikeys")
+		apikeys.POST("", common.WrapperMis(s.api.CreateAPIKey))
+		apikeys.GET("", common.WrapperMis(s.api.ListAPIKey))
+		apikeys.GET("/:name", common.WrapperMis(s.api.GetAPIKey))
+		apikeys.DELETE("/:name", common.WrapperMis(s.api.DeleteAPIKey))
+
+		tokens := v1.Group("/tokens")
+		tokens.POST("", common.WrapperMis(s.api.IssueToken))
+
+		audits := v1.Group("/audits")
+		audits.GET("", common.WrapperMis(s.api.ListAuditMis))
+
+		certificates := v1.Group("/certificates")
+		certificates.GET("/expiring", common.WrapperMis(s.api.ListExpiringCertificates))
 	}
 }
 
@@ -28,6 +28,7 @@ func initMisServerMock(t *testing.T) (*MisServer, *gomock.Controller) {
 	c.Plugin.Functions = []string{common.RandString(9)}
 	c.Plugin.License = common.RandString(9)
 	c.Plugin.Property = common.RandString(9)
+	c.Plugin.Pubsub = common.RandString(9)
 	mockCtl := gomock.NewController(t)
 
 	mockModelStorage := mockPlugin.NewMockModelStorage(mockCtl)
@@ -71,6 +72,10 @@ func initMisServerMock(t *testing.T) (*MisServer, *gomock.Controller) {
 	plugin.RegisterFactory(c.Plugin.Property, func() (plugin.Plugin, error) {
 		return mockProperty, nil
 	})
+	mockPubsub := mockPlugin.NewMockPubsub(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Pubsub, func() (plugin.Plugin, error) {
+		return mockPubsub, nil
+	})
 
 	mockAPI, err := api.NewAPI(c)
 	assert.NoError(t, err)
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// the lock held by the replica reconciling the rollouts
+const rolloutLock = "rollout"
+
+// RolloutController drives the running rollouts wave by wave, the replicas reconcile the rollouts in turn
+type RolloutController struct {
+	cfg     *config.CloudConfig
+	rollout service.RolloutService
+	lock    service.LockService
+	done    chan struct{}
+}
+
+// NewRolloutController create rollout controller
+func NewRolloutController(config *config.CloudConfig) (*RolloutController, error) {
+	rollout, err := service.NewRolloutService(config)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := service.NewLockService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &RolloutController{
+		cfg:     config,
+		rollout: rollout,
+		lock:    lock,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Run reconcile the rollouts periodically until closed
+func (r *RolloutController) Run() {
+	ticker := time.NewTicker(r.cfg.Rollout.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.reconcile()
+		case <-r.done:
+			log.L().Info("rollout controller stopped")
+			return
+		}
+	}
+}
+
+// reconcile advances the rollouts only if the lock is held, so that no wave is started twice by the replicas
+func (r *RolloutController) reconcile() {
+	ok, err := r.lock.Lock(rolloutLock, r.cfg.Rollout.LockTimeout)
+	if err != nil {
+		log.L().Error("failed to lock rollouts", log.Error(err))
+		return
+	}
+	if !ok {
+		return
+	}
+	defer r.lock.Unlock(rolloutLock)
+	if err = r.rollout.Reconcile(); err != nil {
+		log.L().Error("failed to reconcile rollouts", log.Error(err))
+	}
+}
+
+// Close close controller
+func (r *RolloutController) Close() {
+	close(r.done)
+}
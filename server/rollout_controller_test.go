@@ -0,0 +1,61 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+)
+
+func TestRolloutController(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sRollout := ms.NewMockRolloutService(mockCtl)
+	sLock := ms.NewMockLockService(mockCtl)
+
+	cfg := &config.CloudConfig{}
+	cfg.Rollout.Interval = time.Millisecond
+	cfg.Rollout.LockTimeout = time.Minute
+	rc := &RolloutController{
+		cfg:     cfg,
+		rollout: sRollout,
+		lock:    sLock,
+		done:    make(chan struct{}),
+	}
+
+	// skipped if the lock is held by another replica or fails
+	sLock.EXPECT().Lock(rolloutLock, time.Minute).Return(false, nil)
+	rc.reconcile()
+	sLock.EXPECT().Lock(rolloutLock, time.Minute).Return(false, fmt.Errorf("error"))
+	rc.reconcile()
+
+	sLock.EXPECT().Lock(rolloutLock, time.Minute).Return(true, nil).AnyTimes()
+	sLock.EXPECT().Unlock(rolloutLock).AnyTimes()
+	reconciled := make(chan struct{})
+	sRollout.EXPECT().Reconcile().DoAndReturn(func() error {
+		close(reconciled)
+		return nil
+	})
+	sRollout.EXPECT().Reconcile().Return(nil).AnyTimes()
+
+	stopped := make(chan struct{})
+	go func() {
+		rc.Run()
+		close(stopped)
+	}()
+	select {
+	case <-reconciled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("rollouts are not reconciled")
+	}
+	rc.Close()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("rollout controller is not stopped")
+	}
+}
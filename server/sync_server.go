@@ -5,6 +5,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/api"
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
@@ -38,6 +39,9 @@ func (s *SyncServer) InitMsgRouter() {
 	for _, v := range s.links {
 		v.AddMsgRouter(string(specV1.MessageReport), HandlerMessage(s.syncAPI.Report))
 		v.AddMsgRouter(string(specV1.MessageDesire), HandlerMessage(s.syncAPI.Desire))
+		v.AddMsgRouter(string(common.MessageDelta), HandlerMessage(s.syncAPI.Delta))
+		v.AddMsgRouter(string(specV1.MessageCMD), HandlerMessage(s.syncAPI.Command))
+		v.AddMsgRouter(string(common.MessageCommands), HandlerMessage(s.syncAPI.Commands))
 	}
 }
 
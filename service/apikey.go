@@ -0,0 +1,148 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/apikey.go -package=service github.com/baetyl/baetyl-cloud/v2/service APIKeyService
+
+// APIKeyService manages the api keys of users and issues bearer tokens,
+// which are verified by the apikey auth plugin
+type APIKeyService interface {
+	Get(name string) (*models.APIKey, error)
+	List(user string, filter *models.Filter) (*models.ListView, error)
+	Create(key *models.APIKey) (*models.APIKey, error)
+	Delete(name string) error
+	IssueToken(req *models.TokenRequest) (*models.TokenView, error)
+}
+
+// the default expiration of bearer tokens
+const defaultTokenExpiration = time.Hour
+
+// the length of the key prefix which is kept to identify the key
+const apiKeyPrefixLength = len(models.APIKeyPrefix) + 8
+
+type apiKeyService struct {
+	storage plugin.DBStorage
+	auth    AuthService
+}
+
+// NewAPIKeyService NewAPIKeyService
+func NewAPIKeyService(config *config.CloudConfig) (APIKeyService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := NewAuthService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &apiKeyService{
+		storage: ds.(plugin.DBStorage),
+		auth:    auth,
+	}, nil
+}
+
+// Get get an api key, the key itself is never returned
+func (s *apiKeyService) Get(name string) (*models.APIKey, error) {
+	key, err := s.storage.GetAPIKey(name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if key == nil {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "apikey"),
+			common.Field("name", name))
+	}
+	return key, nil
+}
+
+// List list the api keys of the user, or of all users if the user is empty
+func (s *apiKeyService) List(user string, filter *models.Filter) (*models.ListView, error) {
+	keys, err := s.storage.ListAPIKey(user, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountAPIKey(user, filter.Name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    keys,
+	}, nil
+}
+
+// Create generates a random key for the user, only the digest of the key is stored,
+// so the key is returned only once
+func (s *apiKeyService) Create(key *models.APIKey) (*models.APIKey, error) {
+	old, err := s.storage.GetAPIKey(key.Name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if old != nil {
+		return nil, common.Error(common.ErrResourceConflict,
+			common.Field("type", "apikey"),
+			common.Field("name", key.Name))
+	}
+	plain, err := util.GenerateAPIKey(models.APIKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	key.KeyPrefix = plain[:apiKeyPrefixLength]
+	key.KeyHash = util.HashAPIKey(plain)
+	if _, err = s.storage.CreateAPIKey(key); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	res, err := s.Get(key.Name)
+	if err != nil {
+		return nil, err
+	}
+	res.Key = plain
+	return res, nil
+}
+
+// Delete delete an api key, the key is revoked immediately
+func (s *apiKeyService) Delete(name string) error {
+	if _, err := s.storage.DeleteAPIKey(name); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+// IssueToken signs a bearer token for the user with the auth plugin
+func (s *apiKeyService) IssueToken(req *models.TokenRequest) (*models.TokenView, error) {
+	expiration := defaultTokenExpiration
+	if req.Expiration > 0 {
+		expiration = time.Duration(req.Expiration) * time.Second
+	}
+	claims := models.TokenClaims{
+		User:       req.User,
+		Namespaces: req.Namespaces,
+		ExpireTime: time.Now().Add(expiration).UTC(),
+	}
+	meta, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	sign, err := s.auth.SignToken(meta)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenView{
+		Token:      util.EncodeToken(meta, sign),
+		ExpireTime: claims.ExpireTime,
+	}, nil
+}
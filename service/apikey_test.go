@@ -0,0 +1,136 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAPIKeyService_Get(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ks, err := NewAPIKeyService(mockObject.conf)
+	assert.NoError(t, err)
+
+	key := &models.APIKey{Name: "key01", User: "user01"}
+	mockObject.dbStorage.EXPECT().GetAPIKey(key.Name).Return(key, nil)
+	res, err := ks.Get(key.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, key, res)
+
+	mockObject.dbStorage.EXPECT().GetAPIKey(key.Name).Return(nil, nil)
+	_, err = ks.Get(key.Name)
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().GetAPIKey(key.Name).Return(nil, fmt.Errorf("error"))
+	_, err = ks.Get(key.Name)
+	assert.Error(t, err)
+}
+
+func TestAPIKeyService_List(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ks, err := NewAPIKeyService(mockObject.conf)
+	assert.NoError(t, err)
+
+	filter := &models.Filter{Name: "%"}
+	mockObject.dbStorage.EXPECT().ListAPIKey("user01", filter).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CountAPIKey("user01", "%").Return(0, nil)
+	res, err := ks.List("user01", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+	assert.Equal(t, []models.APIKey{}, res.Items)
+
+	mockObject.dbStorage.EXPECT().ListAPIKey("", filter).Return(nil, fmt.Errorf("error"))
+	_, err = ks.List("", filter)
+	assert.Error(t, err)
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ks, err := NewAPIKeyService(mockObject.conf)
+	assert.NoError(t, err)
+
+	key := &models.APIKey{Name: "key01", User: "user01", Namespaces: []string{"default"}}
+	mockObject.dbStorage.EXPECT().GetAPIKey(key.Name).Return(key, nil)
+	_, err = ks.Create(key)
+	assert.Error(t, err)
+
+	var stored *models.APIKey
+	mockObject.dbStorage.EXPECT().GetAPIKey(key.Name).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CreateAPIKey(key).DoAndReturn(func(k *models.APIKey) (interface{}, error) {
+		stored = &models.APIKey{Name: k.Name, User: k.User, KeyPrefix: k.KeyPrefix, KeyHash: k.KeyHash}
+		return nil, nil
+	})
+	mockObject.dbStorage.EXPECT().GetAPIKey(key.Name).DoAndReturn(func(string) (*models.APIKey, error) {
+		return stored, nil
+	})
+	res, err := ks.Create(key)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(res.Key, models.APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(res.Key, res.KeyPrefix))
+	assert.Len(t, res.KeyPrefix, len(models.APIKeyPrefix)+8)
+	assert.Equal(t, util.HashAPIKey(res.Key), res.KeyHash)
+
+	mockObject.dbStorage.EXPECT().GetAPIKey(key.Name).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CreateAPIKey(key).Return(nil, fmt.Errorf("error"))
+	_, err = ks.Create(key)
+	assert.Error(t, err)
+}
+
+func TestAPIKeyService_Delete(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ks, err := NewAPIKeyService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.dbStorage.EXPECT().DeleteAPIKey("key01").Return(nil, nil)
+	assert.NoError(t, ks.Delete("key01"))
+
+	mockObject.dbStorage.EXPECT().DeleteAPIKey("key01").Return(nil, fmt.Errorf("error"))
+	assert.Error(t, ks.Delete("key01"))
+}
+
+func TestAPIKeyService_IssueToken(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ks, err := NewAPIKeyService(mockObject.conf)
+	assert.NoError(t, err)
+
+	var signed []byte
+	mockObject.auth.EXPECT().SignToken(gomock.Any()).DoAndReturn(func(meta []byte) ([]byte, error) {
+		signed = meta
+		return []byte("sign"), nil
+	})
+	now := time.Now()
+	res, err := ks.IssueToken(&models.TokenRequest{User: "user01", Namespaces: []string{"default"}})
+	assert.NoError(t, err)
+	assert.WithinDuration(t, now.Add(defaultTokenExpiration), res.ExpireTime, time.Second)
+
+	meta, sign, err := util.DecodeToken(res.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, signed, meta)
+	assert.Equal(t, []byte("sign"), sign)
+	var claims models.TokenClaims
+	assert.NoError(t, json.Unmarshal(meta, &claims))
+	assert.Equal(t, "user01", claims.User)
+	assert.Equal(t, []string{"default"}, claims.Namespaces)
+
+	mockObject.auth.EXPECT().SignToken(gomock.Any()).Return([]byte("sign"), nil)
+	res, err = ks.IssueToken(&models.TokenRequest{User: "user01", Namespaces: []string{"default"}, Expiration: 60})
+	assert.NoError(t, err)
+	assert.WithinDuration(t, now.Add(time.Minute), res.ExpireTime, time.Second)
+
+	mockObject.auth.EXPECT().SignToken(gomock.Any()).Return(nil, fmt.Errorf("error"))
+	_, err = ks.IssueToken(&models.TokenRequest{User: "user01", Namespaces: []string{"default"}})
+	assert.Error(t, err)
+}
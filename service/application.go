@@ -1,6 +1,10 @@
 package service
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/baetyl/baetyl-go/v2/log"
@@ -22,6 +26,8 @@ type ApplicationService interface {
 	Delete(namespace, name, version string) error
 	List(namespace string, listOptions *models.ListOptions) (*models.ApplicationList, error)
 	CreateWithBase(namespace string, app, base *specV1.Application) (*specV1.Application, error)
+	GetVersion(namespace, name, version string) (*specV1.Application, error)
+	ListVersions(namespace, name string, filter *models.Filter) (*models.ListView, error)
 }
 
 type applicationService struct {
@@ -93,7 +99,7 @@ func (a *applicationService) Create(namespace string, app *specV1.Application) (
 
 // Update update application
 func (a *applicationService) Update(namespace string, app *specV1.Application) (*specV1.Application, error) {
-	err := a.validName(app)
+	err := CheckApplicationNames(app)
 	if err != nil {
 		return nil, err
 	}
@@ -159,6 +165,67 @@ func (a *applicationService) List(namespace string,
 	return a.storage.ListApplication(namespace, listOptions)
 }
 
+// GetVersion get the historical version of application
+func (a *applicationService) GetVersion(namespace, name, version string) (*specV1.Application, error) {
+	app, err := a.dbStorage.GetApplication(name, namespace, version)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if app == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "app"),
+			common.Field("name", name), common.Field("version", version))
+	}
+	return app, nil
+}
+
+// ListVersions list the historical versions of application, the latest first,
+// each version carries the diffs compared with its previous version
+func (a *applicationService) ListVersions(namespace, name string, filter *models.Filter) (*models.ListView, error) {
+	versions, err := a.dbStorage.ListApplicationHistory(name, namespace, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := a.dbStorage.CountApplicationHistory(name, namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if versions == nil {
+		versions = []models.ApplicationVersion{}
+	}
+
+	var prev *specV1.Application
+	if n := len(versions); n > 0 && filter.GetLimitNumber() > 0 {
+		// the previous version of the last one is on the next page
+		prevs, err := a.dbStorage.ListApplicationHistory(name, namespace, &models.Filter{
+			PageNo:   filter.GetLimitOffset() + n + 1,
+			PageSize: 1,
+		})
+		if err != nil {
+			return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+		}
+		if len(prevs) > 0 {
+			prev = prevs[0].Application
+		}
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if prev != nil {
+			diffs, err := diffApplication(prev, versions[i].Application)
+			if err != nil {
+				return nil, err
+			}
+			versions[i].Diffs = diffs
+		}
+		prev = versions[i].Application
+	}
+
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    versions,
+	}, nil
+}
+
 // CreateBaseOther create application with base
 func (a *applicationService) CreateWithBase(namespace string, app, base *specV1.Application) (*specV1.Application, error) {
 	if base != nil {
@@ -172,7 +239,7 @@ func (a *applicationService) CreateWithBase(namespace string, app, base *specV1.
 		app.Volumes = append(base.Volumes, app.Volumes...)
 	}
 
-	err := a.validName(app)
+	err := CheckApplicationNames(app)
 	if err != nil {
 		return nil, err
 	}
@@ -239,7 +306,9 @@ func (a *applicationService) getConfigsAndSecrets(namespace string, app *specV1.
 	return configs, secrets, nil
 }
 
-func (a *applicationService) validName(app *specV1.Application) error {
+// CheckApplicationNames checks the names of the volumes and the services of the application are unique
+// and the volumes mounted by the services exist
+func CheckApplicationNames(app *specV1.Application) error {
 	sf, vf := make(map[string]bool), make(map[string]bool)
 	for _, v := range app.Volumes {
 		if _, ok := vf[v.Name]; ok {
@@ -268,3 +337,68 @@ func (a *applicationService) validName(app *specV1.Application) error {
 
 	return nil
 }
+
+// diffApplication compares the specs of two versions field by field, the metadata of version is ignored
+func diffApplication(oldApp, newApp *specV1.Application) ([]models.ApplicationDiff, error) {
+	oldFields, err := flattenApplication(oldApp)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenApplication(newApp)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []models.ApplicationDiff
+	for path, v := range newFields {
+		if old, ok := oldFields[path]; !ok || !reflect.DeepEqual(old, v) {
+			diffs = append(diffs, models.ApplicationDiff{Path: path, Old: oldFields[path], New: v})
+		}
+	}
+	for path, v := range oldFields {
+		if _, ok := newFields[path]; !ok {
+			diffs = append(diffs, models.ApplicationDiff{Path: path, Old: v})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs, nil
+}
+
+func flattenApplication(app *specV1.Application) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if app == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(app)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	delete(m, "version")
+	delete(m, "createTime")
+	flattenValue("", m, fields)
+	return fields, nil
+}
+
+func flattenValue(path string, v interface{}, fields map[string]interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if path == "" {
+				flattenValue(k, item, fields)
+			} else {
+				flattenValue(path+"."+k, item, fields)
+			}
+		}
+	case []interface{}:
+		for i, item := range val {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), item, fields)
+		}
+	default:
+		fields[path] = val
+	}
+}
@@ -299,3 +299,96 @@ func TestNewCallbackService(t *testing.T) {
 
 	fmt.Println(string(b))
 }
+
+func TestDefaultApplicationService_GetVersion(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	as, err := NewApplicationService(mockObject.conf)
+	assert.NoError(t, err)
+
+	app := &specV1.Application{Namespace: "default", Name: "abc", Version: "1"}
+	mockObject.dbStorage.EXPECT().GetApplication("abc", "default", "1").Return(app, nil)
+	res, err := as.GetVersion("default", "abc", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, app, res)
+
+	mockObject.dbStorage.EXPECT().GetApplication("abc", "default", "2").Return(nil, nil)
+	_, err = as.GetVersion("default", "abc", "2")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	mockObject.dbStorage.EXPECT().GetApplication("abc", "default", "3").Return(nil, fmt.Errorf("error"))
+	_, err = as.GetVersion("default", "abc", "3")
+	assert.Error(t, err)
+}
+
+func TestDefaultApplicationService_ListVersions(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	as, err := NewApplicationService(mockObject.conf)
+	assert.NoError(t, err)
+
+	genVersion := func(version, image string) models.ApplicationVersion {
+		return models.ApplicationVersion{
+			Version: version,
+			Application: &specV1.Application{
+				Namespace: "default",
+				Name:      "abc",
+				Version:   version,
+				Services:  []specV1.Service{{Name: "s0", Image: image}},
+			},
+		}
+	}
+	v3, v2, v1 := genVersion("3", "image:3"), genVersion("2", "image:2"), genVersion("1", "image:2")
+	v1.Application.Selector = "a=a"
+
+	filter := &models.Filter{PageNo: 1, PageSize: 2}
+	mockObject.dbStorage.EXPECT().ListApplicationHistory("abc", "default", filter).Return([]models.ApplicationVersion{v3, v2}, nil)
+	mockObject.dbStorage.EXPECT().CountApplicationHistory("abc", "default").Return(3, nil)
+	mockObject.dbStorage.EXPECT().ListApplicationHistory("abc", "default", &models.Filter{PageNo: 3, PageSize: 1}).Return([]models.ApplicationVersion{v1}, nil)
+	res, err := as.ListVersions("default", "abc", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	versions := res.Items.([]models.ApplicationVersion)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, []models.ApplicationDiff{{Path: "services[0].image", Old: "image:2", New: "image:3"}}, versions[0].Diffs)
+	assert.Equal(t, []models.ApplicationDiff{{Path: "selector", Old: "a=a"}}, versions[1].Diffs)
+
+	// the first version has no diffs
+	filter = &models.Filter{}
+	mockObject.dbStorage.EXPECT().ListApplicationHistory("abc", "default", filter).Return([]models.ApplicationVersion{v1}, nil)
+	mockObject.dbStorage.EXPECT().CountApplicationHistory("abc", "default").Return(1, nil)
+	res, err = as.ListVersions("default", "abc", filter)
+	assert.NoError(t, err)
+	assert.Nil(t, res.Items.([]models.ApplicationVersion)[0].Diffs)
+
+	mockObject.dbStorage.EXPECT().ListApplicationHistory("abc", "default", filter).Return(nil, fmt.Errorf("error"))
+	_, err = as.ListVersions("default", "abc", filter)
+	assert.Error(t, err)
+}
+
+func TestDiffApplication(t *testing.T) {
+	oldApp := &specV1.Application{
+		Name:     "abc",
+		Version:  "1",
+		Labels:   map[string]string{"a": "a", "b": "b"},
+		Services: []specV1.Service{{Name: "s0", Image: "image", Replica: 1}},
+	}
+	newApp := &specV1.Application{
+		Name:     "abc",
+		Version:  "2",
+		Labels:   map[string]string{"a": "c"},
+		Services: []specV1.Service{{Name: "s0", Image: "image", Replica: 2}},
+	}
+	diffs, err := diffApplication(oldApp, newApp)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ApplicationDiff{
+		{Path: "labels.a", Old: "a", New: "c"},
+		{Path: "labels.b", Old: "b"},
+		{Path: "services[0].replica", Old: float64(1), New: float64(2)},
+	}, diffs)
+
+	diffs, err = diffApplication(oldApp, oldApp)
+	assert.NoError(t, err)
+	assert.Nil(t, diffs)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/audit.go -package=service github.com/baetyl/baetyl-cloud/v2/service AuditService
+
+// AuditService records who changed which resource and how
+type AuditService interface {
+	Record(audit *models.AuditLog, before, after []byte) error
+	List(filter *models.AuditFilter) (*models.ListView, error)
+}
+
+const redactedValue = "******"
+
+// the fields whose values are never recorded, such as securityKey and apiToken, matched by the lowercase suffixes
+var sensitiveSuffixes = []string{
+	"password",
+	"token",
+	"key",
+	"secret",
+	"authorization",
+	"credential",
+	"credentials",
+}
+
+// the fields whose values are never recorded, matched by the lowercase names
+var sensitiveFields = map[string]bool{
+	"securityvalue": true,
+}
+
+// the resources whose data are never recorded
+var sensitiveData = map[string]bool{
+	models.ResourceSecret:      true,
+	models.ResourceCertificate: true,
+}
+
+// the fields which change on every update
+var ignoredFields = map[string]bool{
+	"createTime": true,
+	"updateTime": true,
+}
+
+type auditService struct {
+	storage plugin.DBStorage
+}
+
+// NewAuditService NewAuditService
+func NewAuditService(config *config.CloudConfig) (AuditService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	return &auditService{
+		storage: ds.(plugin.DBStorage),
+	}, nil
+}
+
+// Record records the audit log with the redacted diff between the json states of the resource before and after the request
+func (s *auditService) Record(audit *models.AuditLog, before, after []byte) error {
+	audit.Diff = AuditDiff(audit.Resource, before, after)
+	if _, err := s.storage.CreateAuditLog(audit); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+// List list audit logs with pagination, the latest first
+func (s *auditService) List(filter *models.AuditFilter) (*models.ListView, error) {
+	audits, err := s.storage.ListAuditLog(filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountAuditLog(filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if audits == nil {
+		audits = []models.AuditLog{}
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    audits,
+	}, nil
+}
+
+// AuditDiff returns the changed fields between the json states in json, as {"a.b[0]": [before, after]},
+// the values of sensitive fields are redacted, the states which are not json objects are ignored
+func AuditDiff(resource string, before, after []byte) string {
+	b, a := map[string]interface{}{}, map[string]interface{}{}
+	flattenState("", parseState(before), b)
+	flattenState("", parseState(after), a)
+
+	diff := map[string][]interface{}{}
+	for k, bv := range b {
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(av, bv) {
+			diff[k] = []interface{}{bv, av}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			diff[k] = []interface{}{nil, av}
+		}
+	}
+	if len(diff) == 0 {
+		return ""
+	}
+	for k, v := range diff {
+		if !isSensitive(resource, k) {
+			continue
+		}
+		for i := range v {
+			if v[i] != nil {
+				v[i] = redactedValue
+			}
+		}
+	}
+	res, err := json.Marshal(diff)
+	if err != nil {
+		return ""
+	}
+	return string(res)
+}
+
+func parseState(data []byte) map[string]interface{} {
+	var state map[string]interface{}
+	if len(data) == 0 || json.Unmarshal(data, &state) != nil {
+		return nil
+	}
+	return state
+}
+
+func flattenState(prefix string, v interface{}, out map[string]interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 && prefix != "" {
+			out[prefix] = t
+		}
+		for k, e := range t {
+			if prefix == "" && ignoredFields[k] {
+				continue
+			}
+			p := k
+			if prefix != "" {
+				p = prefix + "." + k
+			}
+			flattenState(p, e, out)
+		}
+	case []interface{}:
+		if len(t) == 0 {
+			out[prefix] = t
+		}
+		for i, e := range t {
+			flattenState(prefix+"["+strconv.Itoa(i)+"]", e, out)
+		}
+	default:
+		if prefix != "" {
+			out[prefix] = t
+		}
+	}
+}
+
+func isSensitive(resource, field string) bool {
+	segments := strings.Split(field, ".")
+	if sensitiveData[resource] && strings.HasPrefix(segments[0], "data") {
+		return true
+	}
+	for _, s := range segments {
+		if i := strings.Index(s, "["); i >= 0 {
+			s = s[:i]
+		}
+		s = strings.ToLower(s)
+		if sensitiveFields[s] {
+			return true
+		}
+		for _, suffix := range sensitiveSuffixes {
+			if strings.HasSuffix(s, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAuditDiff(t *testing.T) {
+	before := []byte(`{"name":"cfg","data":{"a":"1","b":"2"},"labels":{"x":"y"},"updateTime":"t1","services":[{"image":"v1"}]}`)
+	after := []byte(`{"name":"cfg","data":{"a":"1","c":"3"},"labels":{},"updateTime":"t2","services":[{"image":"v2"},{"image":"v3"}]}`)
+
+	var diff map[string][]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(AuditDiff(models.ResourceConfig, before, after)), &diff))
+	assert.Equal(t, map[string][]interface{}{
+		"data.b":            {"2", nil},
+		"data.c":            {nil, "3"},
+		"labels.x":          {"y", nil},
+		"labels":            {nil, map[string]interface{}{}},
+		"services[0].image": {"v1", "v2"},
+		"services[1].image": {nil, "v3"},
+	}, diff)
+
+	// the data of secrets are redacted
+	diff = nil
+	assert.NoError(t, json.Unmarshal([]byte(AuditDiff(models.ResourceSecret, before, after)), &diff))
+	assert.Equal(t, []interface{}{redactedValue, nil}, diff["data.b"])
+	assert.Equal(t, []interface{}{nil, redactedValue}, diff["data.c"])
+	assert.Equal(t, []interface{}{"v1", "v2"}, diff["services[0].image"])
+
+	// the sensitive fields are redacted in all resources
+	before = []byte(`{"name":"r","password":"p1","keys":[{"token":"t1"}]}`)
+	after = []byte(`{"name":"r","password":"p2","keys":[{"token":"t2"}]}`)
+	diff = nil
+	assert.NoError(t, json.Unmarshal([]byte(AuditDiff(models.ResourceRegistry, before, after)), &diff))
+	assert.Equal(t, map[string][]interface{}{
+		"password":      {redactedValue, redactedValue},
+		"keys[0].token": {redactedValue, redactedValue},
+	}, diff)
+
+	// the fields with the sensitive suffixes are redacted
+	before = []byte(`{"name":"b","securityType":"Token","securityKey":"k1","labels":{"a":"1"}}`)
+	after = []byte(`{"name":"b","securityType":"Token","securityKey":"k2","labels":{"a":"2"},"apiToken":"t","dbPassword":"p","clientSecret":"s","securityValue":"v"}`)
+	diff = nil
+	assert.NoError(t, json.Unmarshal([]byte(AuditDiff(models.ResourceBatch, before, after)), &diff))
+	assert.Equal(t, map[string][]interface{}{
+		"securityKey":   {redactedValue, redactedValue},
+		"labels.a":      {"1", "2"},
+		"apiToken":      {nil, redactedValue},
+		"dbPassword":    {nil, redactedValue},
+		"clientSecret":  {nil, redactedValue},
+		"securityValue": {nil, redactedValue},
+	}, diff)
+
+	// create and delete
+	assert.Equal(t, `{"name":[null,"a"]}`, AuditDiff(models.ResourceApp, nil, []byte(`{"name":"a"}`)))
+	assert.Equal(t, `{"name":["a",null]}`, AuditDiff(models.ResourceApp, []byte(`{"name":"a"}`), nil))
+	assert.Equal(t, "", AuditDiff(models.ResourceApp, []byte(`{"name":"a"}`), []byte(`{"name":"a"}`)))
+	assert.Equal(t, "", AuditDiff(models.ResourceApp, []byte(`not json`), []byte(`{"success":true}`)[:0]))
+}
+
+func TestAuditService(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	as, err := NewAuditService(mockObject.conf)
+	assert.NoError(t, err)
+
+	audit := &models.AuditLog{Namespace: "default", User: "user01", Resource: models.ResourceSecret, Name: "s"}
+	mockObject.dbStorage.EXPECT().CreateAuditLog(audit).DoAndReturn(func(a *models.AuditLog) (interface{}, error) {
+		assert.Equal(t, `{"data.a":["******","******"]}`, a.Diff)
+		return nil, nil
+	})
+	assert.NoError(t, as.Record(audit, []byte(`{"data":{"a":"1"}}`), []byte(`{"data":{"a":"2"}}`)))
+
+	mockObject.dbStorage.EXPECT().CreateAuditLog(gomock.Any()).Return(nil, fmt.Errorf("error"))
+	assert.Error(t, as.Record(audit, nil, nil))
+
+	filter := &models.AuditFilter{Namespace: "default"}
+	mockObject.dbStorage.EXPECT().ListAuditLog(filter).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CountAuditLog(filter).Return(0, nil)
+	res, err := as.List(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AuditLog{}, res.Items)
+
+	mockObject.dbStorage.EXPECT().ListAuditLog(filter).Return(nil, fmt.Errorf("error"))
+	_, err = as.List(filter)
+	assert.Error(t, err)
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/batch.go -package=service github.com/baetyl/baetyl-cloud/v2/service BatchService
+
+// BatchService manages batches and the registration records of each batch
+type BatchService interface {
+	Get(namespace, name string) (*models.Batch, error)
+	List(namespace string, filter *models.Filter) (*models.ListView, error)
+	Create(batch *models.Batch) (*models.Batch, error)
+	Update(batch *models.Batch) (*models.Batch, error)
+	Delete(namespace, name string) error
+	Count(namespace string) (int, error)
+
+	GetRecord(namespace, batchName, recordName string) (*models.Record, error)
+	GetRecordByFingerprint(namespace, batchName, fingerprintValue string) (*models.Record, error)
+	ListRecord(namespace, batchName string, filter *models.Filter) (*models.ListView, error)
+	CreateRecords(namespace, batchName string, fingerprintValues []string) ([]models.Record, error)
+	UpdateRecord(record *models.Record) (*models.Record, error)
+	ActivateRecord(record *models.Record) (*models.Record, error)
+	DeleteRecord(namespace, batchName, recordName string) error
+}
+
+type batchService struct {
+	storage plugin.DBStorage
+}
+
+// NewBatchService NewBatchService
+func NewBatchService(config *config.CloudConfig) (BatchService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	return &batchService{storage: ds.(plugin.DBStorage)}, nil
+}
+
+// Get get a batch
+func (s *batchService) Get(namespace, name string) (*models.Batch, error) {
+	batch, err := s.storage.GetBatch(name, namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if batch == nil {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", common.Batch),
+			common.Field("name", name),
+			common.Field("namespace", namespace))
+	}
+	return batch, nil
+}
+
+// List list batches with pagination
+func (s *batchService) List(namespace string, filter *models.Filter) (*models.ListView, error) {
+	batches, err := s.storage.ListBatch(namespace, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountBatch(namespace, filter.Name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if batches == nil {
+		batches = []models.Batch{}
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    batches,
+	}, nil
+}
+
+// Create create a batch, the referenced callback must exist
+func (s *batchService) Create(batch *models.Batch) (*models.Batch, error) {
+	old, err := s.storage.GetBatch(batch.Name, batch.Namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if old != nil {
+		return nil, common.Error(common.ErrResourceConflict,
+			common.Field("type", common.Batch),
+			common.Field("name", batch.Name))
+	}
+	if err = s.checkCallback(batch); err != nil {
+		return nil, err
+	}
+	if _, err = s.storage.CreateBatch(batch); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return s.Get(batch.Namespace, batch.Name)
+}
+
+// Update update a batch, the quota can't be less than the number of existing records
+func (s *batchService) Update(batch *models.Batch) (*models.Batch, error) {
+	if err := s.checkCallback(batch); err != nil {
+		return nil, err
+	}
+	count, err := s.storage.CountRecord(batch.Name, "%", batch.Namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if batch.QuotaNum < count {
+		return nil, common.Error(common.ErrRegisterQuotaNumOut, common.Field("num", count))
+	}
+	if _, err = s.storage.UpdateBatch(batch); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return s.Get(batch.Namespace, batch.Name)
+}
+
+// Delete delete a batch which has no record
+func (s *batchService) Delete(namespace, name string) error {
+	count, err := s.storage.CountRecord(name, "%", namespace)
+	if err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if count > 0 {
+		return common.Error(common.ErrRegisterDeleteRecord, common.Field("name", name))
+	}
+	if _, err = s.storage.DeleteBatch(name, namespace); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+// Count count all batches of the namespace
+func (s *batchService) Count(namespace string) (int, error) {
+	count, err := s.storage.CountBatch(namespace, "%")
+	if err != nil {
+		return 0, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return count, nil
+}
+
+// GetRecord get a record of the batch
+func (s *batchService) GetRecord(namespace, batchName, recordName string) (*models.Record, error) {
+	record, err := s.storage.GetRecord(batchName, recordName, namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if record == nil {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "record"),
+			common.Field("name", recordName),
+			common.Field("namespace", namespace))
+	}
+	return record, nil
+}
+
+// GetRecordByFingerprint get a record of the batch by the fingerprint value, return nil if not exist
+func (s *batchService) GetRecordByFingerprint(namespace, batchName, fingerprintValue string) (*models.Record, error) {
+	record, err := s.storage.GetRecordByFingerprint(batchName, namespace, fingerprintValue)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return record, nil
+}
+
+// ListRecord list records of the batch, filter.Name matches the fingerprint value
+func (s *batchService) ListRecord(namespace, batchName string, filter *models.Filter) (*models.ListView, error) {
+	records, err := s.storage.ListRecord(batchName, namespace, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountRecord(batchName, filter.Name, namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    records,
+	}, nil
+}
+
+// CreateRecords create records for the fingerprint values within the quota of the batch
+func (s *batchService) CreateRecords(namespace, batchName string, fingerprintValues []string) ([]models.Record, error) {
+	batch, err := s.Get(namespace, batchName)
+	if err != nil {
+		return nil, err
+	}
+	var records []models.Record
+	err = s.storage.Transact(func(tx *sqlx.Tx) error {
+		count, err := s.storage.CountRecordTx(tx, batchName, "%", namespace)
+		if err != nil {
+			return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+		}
+		if count+len(fingerprintValues) > batch.QuotaNum {
+			return common.Error(common.ErrRegisterQuotaNumOut, common.Field("num", batch.QuotaNum))
+		}
+		values := map[string]bool{}
+		for _, v := range fingerprintValues {
+			if values[v] {
+				return common.Error(common.ErrResourceConflict,
+					common.Field("type", "record"),
+					common.Field("name", v))
+			}
+			values[v] = true
+			old, err := s.storage.GetRecordByFingerprintTx(tx, batchName, namespace, v)
+			if err != nil {
+				return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+			}
+			if old != nil {
+				return common.Error(common.ErrResourceConflict,
+					common.Field("type", "record"),
+					common.Field("name", v))
+			}
+			records = append(records, models.NewRecord(namespace, batchName, v))
+		}
+		if _, err = s.storage.CreateRecordTx(tx, records); err != nil {
+			return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.L().Info("records created",
+		log.Any("namespace", namespace),
+		log.Any("batch", batchName),
+		log.Any("count", len(records)))
+	return records, nil
+}
+
+// UpdateRecord update the activation info of a record
+func (s *batchService) UpdateRecord(record *models.Record) (*models.Record, error) {
+	if _, err := s.storage.UpdateRecord(record); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return s.GetRecord(record.Namespace, record.BatchName, record.Name)
+}
+
+// ActivateRecord activate a record which is not activated, fail if the record is activated by another request
+func (s *batchService) ActivateRecord(record *models.Record) (*models.Record, error) {
+	res, err := s.storage.ActivateRecord(record)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return nil, common.Error(common.ErrRegisterRecordActivated)
+	}
+	return s.GetRecord(record.Namespace, record.BatchName, record.Name)
+}
+
+// DeleteRecord delete a record which is not activated
+func (s *batchService) DeleteRecord(namespace, batchName, recordName string) error {
+	record, err := s.GetRecord(namespace, batchName, recordName)
+	if err != nil {
+		return err
+	}
+	if record.Active == common.Activated {
+		return common.Error(common.ErrRegisterRecordActivated)
+	}
+	if _, err = s.storage.DeleteRecord(batchName, recordName, namespace); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+func (s *batchService) checkCallback(batch *models.Batch) error {
+	if batch.CallbackName == "" {
+		return nil
+	}
+	callback, err := s.storage.GetCallback(batch.CallbackName, batch.Namespace)
+	if err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if callback == nil {
+		return common.Error(common.ErrResourceNotFound,
+			common.Field("type", "callback"),
+			common.Field("name", batch.CallbackName),
+			common.Field("namespace", batch.Namespace))
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func genBatchTestCase() *models.Batch {
+	return &models.Batch{
+		Name:         "b1",
+		Namespace:    "default",
+		QuotaNum:     2,
+		SecurityType: common.None,
+		CallbackName: "cb",
+	}
+}
+
+func TestBatchService_Get(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	bs, err := NewBatchService(mockObject.conf)
+	assert.NoError(t, err)
+
+	batch := genBatchTestCase()
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(batch, nil)
+	res, err := bs.Get(batch.Namespace, batch.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, batch, res)
+
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(nil, nil)
+	_, err = bs.Get(batch.Namespace, batch.Name)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(nil, fmt.Errorf("db error"))
+	_, err = bs.Get(batch.Namespace, batch.Name)
+	assert.Error(t, err)
+}
+
+func TestBatchService_List(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	bs, err := NewBatchService(mockObject.conf)
+	assert.NoError(t, err)
+
+	filter := &models.Filter{PageNo: 1, PageSize: 10, Name: "%"}
+	mockObject.dbStorage.EXPECT().ListBatch("default", filter).Return([]models.Batch{*genBatchTestCase()}, nil)
+	mockObject.dbStorage.EXPECT().CountBatch("default", "%").Return(1, nil)
+	res, err := bs.List("default", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Len(t, res.Items, 1)
+}
+
+func TestBatchService_Create(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	bs, err := NewBatchService(mockObject.conf)
+	assert.NoError(t, err)
+	batch := genBatchTestCase()
+
+	// conflict
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(batch, nil)
+	_, err = bs.Create(batch)
+	assert.Error(t, err)
+
+	// callback not found
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetCallback(batch.CallbackName, batch.Namespace).Return(nil, nil)
+	_, err = bs.Create(batch)
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetCallback(batch.CallbackName, batch.Namespace).Return(&models.Callback{}, nil)
+	mockObject.dbStorage.EXPECT().CreateBatch(batch).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(batch, nil)
+	res, err := bs.Create(batch)
+	assert.NoError(t, err)
+	assert.Equal(t, batch, res)
+}
+
+func TestBatchService_Update(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	bs, err := NewBatchService(mockObject.conf)
+	assert.NoError(t, err)
+	batch := genBatchTestCase()
+	batch.CallbackName = ""
+
+	mockObject.dbStorage.EXPECT().CountRecord(batch.Name, "%", batch.Namespace).Return(3, nil)
+	_, err = bs.Update(batch)
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().CountRecord(batch.Name, "%", batch.Namespace).Return(1, nil)
+	mockObject.dbStorage.EXPECT().UpdateBatch(batch).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(batch, nil)
+	_, err = bs.Update(batch)
+	assert.NoError(t, err)
+}
+
+func TestBatchService_Delete(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	bs, err := NewBatchService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.dbStorage.EXPECT().CountRecord("b1", "%", "default").Return(1, nil)
+	err = bs.Delete("default", "b1")
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().CountRecord("b1", "%", "default").Return(0, nil)
+	mockObject.dbStorage.EXPECT().DeleteBatch("b1", "default").Return(nil, nil)
+	err = bs.Delete("default", "b1")
+	assert.NoError(t, err)
+}
+
+func TestBatchService_CreateRecords(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	bs, err := NewBatchService(mockObject.conf)
+	assert.NoError(t, err)
+	batch := genBatchTestCase()
+
+	transact := func(handler func(*sqlx.Tx) error) error {
+		return handler(nil)
+	}
+
+	// quota exceeded
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(batch, nil)
+	mockObject.dbStorage.EXPECT().Transact(gomock.Any()).DoAndReturn(transact)
+	mockObject.dbStorage.EXPECT().CountRecordTx(nil, batch.Name, "%", batch.Namespace).Return(1, nil)
+	_, err = bs.CreateRecords(batch.Namespace, batch.Name, []string{"a", "b"})
+	assert.Error(t, err)
+
+	// duplicated
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(batch, nil)
+	mockObject.dbStorage.EXPECT().Transact(gomock.Any()).DoAndReturn(transact)
+	mockObject.dbStorage.EXPECT().CountRecordTx(nil, batch.Name, "%", batch.Namespace).Return(0, nil)
+	mockObject.dbStorage.EXPECT().GetRecordByFingerprintTx(nil, batch.Name, batch.Namespace, "a").Return(nil, nil)
+	_, err = bs.CreateRecords(batch.Namespace, batch.Name, []string{"a", "a"})
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().GetBatch(batch.Name, batch.Namespace).Return(batch, nil)
+	mockObject.dbStorage.EXPECT().Transact(gomock.Any()).DoAndReturn(transact)
+	mockObject.dbStorage.EXPECT().CountRecordTx(nil, batch.Name, "%", batch.Namespace).Return(0, nil)
+	mockObject.dbStorage.EXPECT().GetRecordByFingerprintTx(nil, batch.Name, batch.Namespace, gomock.Any()).Return(nil, nil).Times(2)
+	mockObject.dbStorage.EXPECT().CreateRecordTx(nil, gomock.Any()).Return(nil, nil)
+	records, err := bs.CreateRecords(batch.Namespace, batch.Name, []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "a", records[0].FingerprintValue)
+	assert.Equal(t, common.Inactivated, records[0].Active)
+	assert.NotEqual(t, records[0].Name, records[1].Name)
+}
+
+func TestBatchService_Record(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	bs, err := NewBatchService(mockObject.conf)
+	assert.NoError(t, err)
+	record := models.NewRecord("default", "b1", "sn")
+
+	mockObject.dbStorage.EXPECT().GetRecord("b1", record.Name, "default").Return(&record, nil)
+	res, err := bs.GetRecord("default", "b1", record.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, &record, res)
+
+	mockObject.dbStorage.EXPECT().GetRecordByFingerprint("b1", "default", "sn").Return(&record, nil)
+	res, err = bs.GetRecordByFingerprint("default", "b1", "sn")
+	assert.NoError(t, err)
+	assert.Equal(t, &record, res)
+
+	filter := &models.Filter{Name: "%"}
+	mockObject.dbStorage.EXPECT().ListRecord("b1", "default", filter).Return([]models.Record{record}, nil)
+	mockObject.dbStorage.EXPECT().CountRecord("b1", "%", "default").Return(1, nil)
+	list, err := bs.ListRecord("default", "b1", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+
+	mockObject.dbStorage.EXPECT().UpdateRecord(&record).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetRecord("b1", record.Name, "default").Return(&record, nil)
+	_, err = bs.UpdateRecord(&record)
+	assert.NoError(t, err)
+
+	mockObject.dbStorage.EXPECT().ActivateRecord(&record).Return(driver.RowsAffected(1), nil)
+	mockObject.dbStorage.EXPECT().GetRecord("b1", record.Name, "default").Return(&record, nil)
+	_, err = bs.ActivateRecord(&record)
+	assert.NoError(t, err)
+
+	// activated by another request
+	mockObject.dbStorage.EXPECT().ActivateRecord(&record).Return(driver.RowsAffected(0), nil)
+	_, err = bs.ActivateRecord(&record)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrRegisterRecordActivated, err.(errors.Coder).Code())
+
+	mockObject.dbStorage.EXPECT().GetRecord("b1", record.Name, "default").Return(&record, nil)
+	mockObject.dbStorage.EXPECT().DeleteRecord("b1", record.Name, "default").Return(nil, nil)
+	err = bs.DeleteRecord("default", "b1", record.Name)
+	assert.NoError(t, err)
+
+	activated := record
+	activated.Active = common.Activated
+	mockObject.dbStorage.EXPECT().GetRecord("b1", record.Name, "default").Return(&activated, nil)
+	err = bs.DeleteRecord("default", "b1", record.Name)
+	assert.Error(t, err)
+}
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/bundle.go -package=service github.com/baetyl/baetyl-cloud/v2/service BundleService
+
+// the iterations of the key derived from the passphrase of bundles
+const bundleKeyIterations = 100000
+
+// the kinds of the bundle in the order of creating and updating
+var bundleKinds = []string{
+	models.BundleKindConfig,
+	models.BundleKindSecret,
+	models.BundleKindRegistry,
+	models.BundleKindCertificate,
+	models.BundleKindNode,
+	models.BundleKindApp,
+}
+
+// BundleService exports the resources of namespaces as bundles and diffs bundles against namespaces
+type BundleService interface {
+	// Export exports the configs, secrets, registries, certificates, nodes and applications created by users,
+	// the data of the secrets are encrypted if the passphrase is set
+	Export(namespace, passphrase string) (*models.Bundle, error)
+	// Plan diffs the bundle against the namespace and returns the changes in the dependency order,
+	// the resources not in the bundle are deleted if prune is set
+	Plan(namespace, passphrase string, bundle *models.Bundle, opts *models.BundleApplyOptions) (*models.BundlePlan, error)
+}
+
+type bundleService struct {
+	storage plugin.ModelStorage
+	secret  SecretService
+}
+
+// NewBundleService new bundle service
+func NewBundleService(config *config.CloudConfig) (BundleService, error) {
+	ms, err := plugin.GetPlugin(config.Plugin.ModelStorage)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := NewSecretService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &bundleService{
+		storage: ms.(plugin.ModelStorage),
+		secret:  ss,
+	}, nil
+}
+
+func (s *bundleService) Export(namespace, passphrase string) (*models.Bundle, error) {
+	resources, err := s.current(namespace)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &models.Bundle{
+		Version:   models.BundleVersion,
+		Namespace: namespace,
+	}
+	for _, name := range sortedNames(resources[models.BundleKindConfig]) {
+		bundle.Configs = append(bundle.Configs, *resources[models.BundleKindConfig][name].(*specV1.Configuration))
+	}
+	for _, name := range sortedNames(resources[models.BundleKindSecret]) {
+		bundle.Secrets = append(bundle.Secrets, *resources[models.BundleKindSecret][name].(*specV1.Secret))
+	}
+	for _, name := range sortedNames(resources[models.BundleKindRegistry]) {
+		bundle.Registries = append(bundle.Registries, *resources[models.BundleKindRegistry][name].(*specV1.Secret))
+	}
+	for _, name := range sortedNames(resources[models.BundleKindCertificate]) {
+		bundle.Certificates = append(bundle.Certificates, *resources[models.BundleKindCertificate][name].(*specV1.Secret))
+	}
+	for _, name := range sortedNames(resources[models.BundleKindNode]) {
+		bundle.Nodes = append(bundle.Nodes, *resources[models.BundleKindNode][name].(*specV1.Node))
+	}
+	for _, name := range sortedNames(resources[models.BundleKindApp]) {
+		bundle.Applications = append(bundle.Applications, *resources[models.BundleKindApp][name].(*specV1.Application))
+	}
+	if passphrase == "" {
+		return bundle, nil
+	}
+	salt, err := util.GenerateSalt(16)
+	if err != nil {
+		return nil, err
+	}
+	bundle.Encryption = &models.BundleEncryption{
+		Algorithm:  models.BundleEncryptionAlgorithm,
+		Salt:       salt,
+		Iterations: bundleKeyIterations,
+	}
+	key := util.DeriveKey(passphrase, salt, bundleKeyIterations)
+	if err = cryptBundleSecrets(bundle, key, util.GCMEncrypt); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func (s *bundleService) Plan(namespace, passphrase string, bundle *models.Bundle, opts *models.BundleApplyOptions) (*models.BundlePlan, error) {
+	if bundle.Version != models.BundleVersion {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the version of the bundle is not supported"))
+	}
+	if enc := bundle.Encryption; enc != nil {
+		if enc.Algorithm != models.BundleEncryptionAlgorithm || enc.Iterations <= 0 {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the encryption of the bundle is not supported"))
+		}
+		if passphrase == "" {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the passphrase of the encrypted bundle is required"))
+		}
+		key := util.DeriveKey(passphrase, enc.Salt, enc.Iterations)
+		if err := cryptBundleSecrets(bundle, key, util.GCMDecrypt); err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "failed to decrypt the secrets of the bundle, the passphrase may be wrong"))
+		}
+	}
+	desired, err := bundleResources(bundle)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.current(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.BundlePlan{DryRun: opts.DryRun, Prune: opts.Prune, Changes: []models.BundleChange{}}
+	for _, kind := range bundleKinds {
+		for _, name := range sortedNames(desired[kind]) {
+			res := desired[kind][name]
+			old, ok := current[kind][name]
+			if !ok {
+				plan.Changes = append(plan.Changes, models.BundleChange{Kind: kind, Name: name, Action: models.BundleActionCreate, Resource: res})
+				continue
+			}
+			equal, err := equalBundleResource(old, res)
+			if err != nil {
+				return nil, err
+			}
+			if equal {
+				plan.Unchanged++
+				continue
+			}
+			plan.Changes = append(plan.Changes, models.BundleChange{Kind: kind, Name: name, Action: models.BundleActionUpdate, Resource: res})
+		}
+	}
+	if !opts.Prune {
+		return plan, nil
+	}
+	for i := len(bundleKinds) - 1; i >= 0; i-- {
+		kind := bundleKinds[i]
+		for _, name := range sortedNames(current[kind]) {
+			if _, ok := desired[kind][name]; !ok {
+				plan.Changes = append(plan.Changes, models.BundleChange{Kind: kind, Name: name, Action: models.BundleActionDelete})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// current returns the resources created by users in the namespace by kind and name, cleaned as in bundles
+func (s *bundleService) current(namespace string) (map[string]map[string]interface{}, error) {
+	resources := map[string]map[string]interface{}{}
+	for _, kind := range bundleKinds {
+		resources[kind] = map[string]interface{}{}
+	}
+	userOptions := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+
+	configs, err := s.storage.ListConfig(namespace, userOptions)
+	if err != nil {
+		return nil, err
+	}
+	for i := range configs.Items {
+		cfg := &configs.Items[i]
+		if !cfg.System {
+			resources[models.BundleKindConfig][cfg.Name] = cleanBundleConfig(cfg)
+		}
+	}
+
+	// the secrets are read by the secret service to be decrypted from the envelope of the storage
+	secrets, err := s.secret.List(namespace, userOptions)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !secret.System {
+			resources[secretBundleKind(secret)][secret.Name] = cleanBundleSecret(secret)
+		}
+	}
+
+	nodes, err := s.storage.ListNode(namespace, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		resources[models.BundleKindNode][node.Name] = cleanBundleNode(node)
+	}
+
+	apps, err := s.storage.ListApplication(namespace, userOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range apps.Items {
+		if item.System {
+			continue
+		}
+		app, err := s.storage.GetApplication(namespace, item.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		resources[models.BundleKindApp][app.Name] = cleanBundleApp(app)
+	}
+	return resources, nil
+}
+
+// bundleResources returns the resources in the bundle by kind and name, system resources are rejected
+func bundleResources(bundle *models.Bundle) (map[string]map[string]interface{}, error) {
+	resources := map[string]map[string]interface{}{}
+	for _, kind := range bundleKinds {
+		resources[kind] = map[string]interface{}{}
+	}
+	add := func(kind, name string, labels map[string]string, system bool, res interface{}) error {
+		if name == "" {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the name of the "+kind+" is required"))
+		}
+		if err := common.ValidateStruct(res); err != nil {
+			return err
+		}
+		if _, ok := labels[common.LabelSystem]; ok || system {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the system "+kind+" ("+name+") can not be applied"))
+		}
+		if _, ok := resources[kind][name]; ok {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", "the "+kind+" ("+name+") is duplicated"))
+		}
+		resources[kind][name] = res
+		return nil
+	}
+	for i := range bundle.Configs {
+		cfg := cleanBundleConfig(&bundle.Configs[i])
+		if err := add(models.BundleKindConfig, cfg.Name, cfg.Labels, cfg.System, cfg); err != nil {
+			return nil, err
+		}
+	}
+	secrets := map[string][]specV1.Secret{
+		models.BundleKindSecret:      bundle.Secrets,
+		models.BundleKindRegistry:    bundle.Registries,
+		models.BundleKindCertificate: bundle.Certificates,
+	}
+	for kind, items := range secrets {
+		for i := range items {
+			secret := cleanBundleSecret(&items[i])
+			if secretBundleKind(secret) != kind {
+				return nil, common.Error(common.ErrRequestParamInvalid,
+					common.Field("error", "the label of the "+kind+" ("+secret.Name+") is invalid"))
+			}
+			if err := add(kind, secret.Name, secret.Labels, secret.System, secret); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i := range bundle.Nodes {
+		node := cleanBundleNode(&bundle.Nodes[i])
+		if err := add(models.BundleKindNode, node.Name, nil, false, node); err != nil {
+			return nil, err
+		}
+	}
+	for i := range bundle.Applications {
+		app := cleanBundleApp(&bundle.Applications[i])
+		if err := add(models.BundleKindApp, app.Name, app.Labels, app.System, app); err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+// cryptBundleSecrets encrypts or decrypts the data of the secrets in the bundle
+func cryptBundleSecrets(bundle *models.Bundle, key []byte, crypt func(data, key []byte) ([]byte, error)) error {
+	for _, items := range [][]specV1.Secret{bundle.Secrets, bundle.Registries, bundle.Certificates} {
+		for i := range items {
+			data := map[string][]byte{}
+			for k, v := range items[i].Data {
+				res, err := crypt(v, key)
+				if err != nil {
+					return err
+				}
+				data[k] = res
+			}
+			items[i].Data = data
+		}
+	}
+	return nil
+}
+
+func secretBundleKind(secret *specV1.Secret) string {
+	switch secret.Labels[specV1.SecretLabel] {
+	case specV1.SecretRegistry:
+		return models.BundleKindRegistry
+	case specV1.SecretCertificate:
+		return models.BundleKindCertificate
+	default:
+		return models.BundleKindSecret
+	}
+}
+
+// the resources in bundles are cleaned of the namespace, the versions and the timestamps,
+// which are decided by the namespace applying the bundle
+
+func cleanBundleConfig(cfg *specV1.Configuration) *specV1.Configuration {
+	res := *cfg
+	res.Namespace, res.Version = "", ""
+	res.CreationTimestamp, res.UpdateTimestamp = time.Time{}, time.Time{}
+	return &res
+}
+
+func cleanBundleSecret(secret *specV1.Secret) *specV1.Secret {
+	res := *secret
+	res.Namespace, res.Version = "", ""
+	res.CreationTimestamp, res.UpdateTimestamp = time.Time{}, time.Time{}
+	return &res
+}
+
+func cleanBundleNode(node *specV1.Node) *specV1.Node {
+	return &specV1.Node{
+		Name:        node.Name,
+		Labels:      node.Labels,
+		Annotations: node.Annotations,
+		Description: node.Description,
+	}
+}
+
+func cleanBundleApp(app *specV1.Application) *specV1.Application {
+	res := *app
+	res.Namespace, res.Version = "", ""
+	res.CreationTimestamp = time.Time{}
+	// the type is container by default as the application api does
+	if res.Type == "" {
+		res.Type = common.ContainerApp
+	}
+	res.Volumes = make([]specV1.Volume, len(app.Volumes))
+	for i, v := range app.Volumes {
+		// the versions of the configs and the secrets are the latest when applied
+		if v.Config != nil {
+			ref := *v.Config
+			ref.Version = ""
+			v.Config = &ref
+		}
+		if v.Secret != nil {
+			ref := *v.Secret
+			ref.Version = ""
+			v.Secret = &ref
+		}
+		res.Volumes[i] = v
+	}
+	return &res
+}
+
+// equalBundleResource compares the resources by their yaml, which is the same for nil and empty fields
+func equalBundleResource(a, b interface{}) (bool, error) {
+	ya, err := yaml.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	yb, err := yaml.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return string(ya) == string(yb), nil
+}
+
+func sortedNames(resources map[string]interface{}) []string {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func expectBundleResources(mocks *MockServices, secret *ms.MockSecretService, configs []specV1.Configuration, secrets []specV1.Secret, nodes []specV1.Node, apps []specV1.Application) {
+	userOptions := &models.ListOptions{LabelSelector: "!" + common.LabelSystem}
+	mocks.modelStorage.EXPECT().ListConfig("default", userOptions).Return(&models.ConfigurationList{Items: configs}, nil)
+	secret.EXPECT().List("default", userOptions).Return(&models.SecretList{Items: secrets}, nil)
+	mocks.modelStorage.EXPECT().ListNode("default", &models.ListOptions{}).Return(&models.NodeList{Items: nodes}, nil)
+	items := make([]models.AppItem, 0, len(apps))
+	for i := range apps {
+		items = append(items, models.AppItem{Name: apps[i].Name, System: apps[i].System})
+		if !apps[i].System {
+			mocks.modelStorage.EXPECT().GetApplication("default", apps[i].Name, "").Return(&apps[i], nil)
+		}
+	}
+	mocks.modelStorage.EXPECT().ListApplication("default", userOptions).Return(&models.ApplicationList{Items: items}, nil)
+}
+
+func genBundleResources() ([]specV1.Configuration, []specV1.Secret, []specV1.Node, []specV1.Application) {
+	now := time.Now()
+	configs := []specV1.Configuration{
+		{Name: "c1", Namespace: "default", Version: "1", Data: map[string]string{"a": "a"}, CreationTimestamp: now},
+		{Name: "c0", Namespace: "default", Version: "2", System: true},
+	}
+	secrets := []specV1.Secret{
+		{Name: "s1", Namespace: "default", Version: "3", Labels: map[string]string{specV1.SecretLabel: specV1.SecretConfig}, Data: map[string][]byte{"k": []byte("v")}},
+		{Name: "r1", Namespace: "default", Version: "4", Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}, Data: map[string][]byte{"password": []byte("p")}},
+		{Name: "t1", Namespace: "default", Version: "5", Labels: map[string]string{specV1.SecretLabel: specV1.SecretCertificate}, Data: map[string][]byte{"key": []byte("k")}},
+	}
+	nodes := []specV1.Node{
+		{Name: "n1", Namespace: "default", Version: "6", Labels: map[string]string{"a": "a"}, Report: specV1.Report{"time": now}},
+	}
+	apps := []specV1.Application{
+		{Name: "app1", Namespace: "default", Version: "7", Selector: "a=a", Volumes: []specV1.Volume{
+			{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c1", Version: "1"}}},
+			{Name: "v2", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s1", Version: "3"}}},
+		}},
+		{Name: "baetyl-core-n1", Namespace: "default", System: true},
+	}
+	return configs, secrets, nodes, apps
+}
+
+func TestBundleServiceExport(t *testing.T) {
+	mocks := InitMockEnvironment(t)
+	defer mocks.Close()
+	mSecret := ms.NewMockSecretService(mocks.ctl)
+	s := &bundleService{storage: mocks.modelStorage, secret: mSecret}
+
+	configs, secrets, nodes, apps := genBundleResources()
+	expectBundleResources(mocks, mSecret, configs, secrets, nodes, apps)
+	bundle, err := s.Export("default", "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.BundleVersion, bundle.Version)
+	assert.Nil(t, bundle.Encryption)
+	assert.Len(t, bundle.Configs, 1)
+	assert.Equal(t, "", bundle.Configs[0].Version)
+	assert.True(t, bundle.Configs[0].CreationTimestamp.IsZero())
+	assert.Equal(t, "s1", bundle.Secrets[0].Name)
+	assert.Equal(t, "v", string(bundle.Secrets[0].Data["k"]))
+	assert.Equal(t, "r1", bundle.Registries[0].Name)
+	assert.Equal(t, "t1", bundle.Certificates[0].Name)
+	assert.Equal(t, specV1.Node{Name: "n1", Labels: map[string]string{"a": "a"}}, bundle.Nodes[0])
+	assert.Len(t, bundle.Applications, 1)
+	assert.Equal(t, "", bundle.Applications[0].Volumes[0].Config.Version)
+	// the resources in the storage are not changed
+	assert.Equal(t, "1", apps[0].Volumes[0].Config.Version)
+
+	expectBundleResources(mocks, mSecret, configs, secrets, nodes, apps)
+	bundle, err = s.Export("default", "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, models.BundleEncryptionAlgorithm, bundle.Encryption.Algorithm)
+	assert.NotEqual(t, "v", string(bundle.Secrets[0].Data["k"]))
+	assert.Equal(t, "v", string(secrets[0].Data["k"]))
+}
+
+func TestBundleServicePlan(t *testing.T) {
+	mocks := InitMockEnvironment(t)
+	defer mocks.Close()
+	mSecret := ms.NewMockSecretService(mocks.ctl)
+	s := &bundleService{storage: mocks.modelStorage, secret: mSecret}
+
+	// the encrypted bundle is unchanged after a round trip of yaml
+	configs, secrets, nodes, apps := genBundleResources()
+	expectBundleResources(mocks, mSecret, configs, secrets, nodes, apps)
+	bundle, err := s.Export("default", "passphrase")
+	assert.NoError(t, err)
+	data, err := yaml.Marshal(bundle)
+	assert.NoError(t, err)
+
+	load := func() *models.Bundle {
+		b := new(models.Bundle)
+		assert.NoError(t, yaml.Unmarshal(data, b))
+		return b
+	}
+	_, err = s.Plan("default", "", load(), &models.BundleApplyOptions{})
+	assert.Error(t, err)
+	_, err = s.Plan("default", "wrong", load(), &models.BundleApplyOptions{})
+	assert.Error(t, err)
+
+	expectBundleResources(mocks, mSecret, configs, secrets, nodes, apps)
+	plan, err := s.Plan("default", "passphrase", load(), &models.BundleApplyOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.True(t, plan.DryRun)
+	assert.Equal(t, 6, plan.Unchanged)
+	assert.Len(t, plan.Changes, 0)
+
+	// create, update and prune in the dependency order
+	bundle = load()
+	bundle.Configs = append(bundle.Configs, specV1.Configuration{Name: "c2", Data: map[string]string{"b": "b"}})
+	bundle.Nodes[0].Labels["b"] = "b"
+	bundle.Applications = append(bundle.Applications, specV1.Application{Name: "app2", Selector: "b=b"})
+	bundle.Registries = nil
+	expectBundleResources(mocks, mSecret, configs, secrets, nodes, apps)
+	plan, err = s.Plan("default", "passphrase", bundle, &models.BundleApplyOptions{Prune: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, plan.Unchanged)
+	var changes []string
+	for _, c := range plan.Changes {
+		changes = append(changes, c.Action+" "+c.Kind+" "+c.Name)
+	}
+	assert.Equal(t, []string{"create config c2", "update node n1", "create app app2", "delete registry r1"}, changes)
+	assert.Equal(t, "c2", plan.Changes[0].Resource.(*specV1.Configuration).Name)
+
+	// invalid bundles
+	_, err = s.Plan("default", "", &models.Bundle{Version: "v0"}, &models.BundleApplyOptions{})
+	assert.Error(t, err)
+	_, err = s.Plan("default", "", &models.Bundle{Version: models.BundleVersion, Configs: []specV1.Configuration{{Name: "c0", System: true}}}, &models.BundleApplyOptions{})
+	assert.Error(t, err)
+	_, err = s.Plan("default", "", &models.Bundle{Version: models.BundleVersion, Applications: []specV1.Application{{Name: "app1"}, {Name: "app1"}}}, &models.BundleApplyOptions{})
+	assert.Error(t, err)
+	_, err = s.Plan("default", "", &models.Bundle{Version: models.BundleVersion, Registries: []specV1.Secret{{Name: "s1"}}}, &models.BundleApplyOptions{})
+	assert.Error(t, err)
+	_, err = s.Plan("default", "", &models.Bundle{Version: models.BundleVersion, Nodes: []specV1.Node{{Name: "N1"}}}, &models.BundleApplyOptions{})
+	assert.Error(t, err)
+}
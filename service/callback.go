@@ -0,0 +1,400 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/callback.go -package=service github.com/baetyl/baetyl-cloud/v2/service CallbackService
+
+// CallbackService manages callbacks which are referenced by batches
+type CallbackService interface {
+	Get(namespace, name string) (*models.Callback, error)
+	List(namespace string, filter *models.Filter) (*models.ListView, error)
+	Create(callback *models.Callback) (*models.Callback, error)
+	Update(callback *models.Callback) (*models.Callback, error)
+	Delete(namespace, name string) error
+	ListLog(namespace, name string, filter *models.Filter) (*models.ListView, error)
+	Callback(name, namespace string, args map[string]string) error
+	Dispatch(name, namespace string, args map[string]string)
+	Close() error
+}
+
+// the event variables which can be referenced in the url, params, header and body of callbacks, e.g. {{.nodeName}}
+const (
+	CallbackArgEvent            = "event"
+	CallbackArgNamespace        = "namespace"
+	CallbackArgBatchName        = "batchName"
+	CallbackArgNodeName         = "nodeName"
+	CallbackArgFingerprintValue = "fingerprintValue"
+	CallbackArgIP               = "ip"
+)
+
+// the events which trigger callbacks
+const (
+	CallbackEventActivate   = "activate"
+	CallbackEventNodeDelete = "nodeDelete"
+)
+
+const maxCallbackResponseSize = 1024
+
+type callbackService struct {
+	storage    plugin.DBStorage
+	client     *http.Client
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	logLimit   int
+	tasks      chan *callbackTask
+	closed     bool
+	mu         sync.RWMutex
+	wg         sync.WaitGroup
+}
+
+type callbackTask struct {
+	name      string
+	namespace string
+	args      map[string]string
+}
+
+type callbackRequest struct {
+	method string
+	url    string
+	header map[string]string
+	body   []byte
+}
+
+// NewCallbackService NewCallbackService
+func NewCallbackService(config *config.CloudConfig) (CallbackService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	s := &callbackService{
+		storage:    ds.(plugin.DBStorage),
+		client:     &http.Client{Timeout: config.Callback.Timeout},
+		retries:    config.Callback.Retries,
+		backoff:    config.Callback.Backoff,
+		maxBackoff: config.Callback.MaxBackoff,
+		logLimit:   config.Callback.LogLimit,
+		tasks:      make(chan *callbackTask, config.Callback.QueueSize),
+	}
+	workers := config.Callback.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go s.working()
+	}
+	return s, nil
+}
+
+// Get get a callback
+func (s *callbackService) Get(namespace, name string) (*models.Callback, error) {
+	callback, err := s.storage.GetCallback(name, namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if callback == nil {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "callback"),
+			common.Field("name", name),
+			common.Field("namespace", namespace))
+	}
+	return callback, nil
+}
+
+// List list callbacks with pagination
+func (s *callbackService) List(namespace string, filter *models.Filter) (*models.ListView, error) {
+	callbacks, err := s.storage.ListCallback(namespace, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountCallback(namespace, filter.Name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if callbacks == nil {
+		callbacks = []models.Callback{}
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    callbacks,
+	}, nil
+}
+
+// Create create a callback
+func (s *callbackService) Create(callback *models.Callback) (*models.Callback, error) {
+	old, err := s.storage.GetCallback(callback.Name, callback.Namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if old != nil {
+		return nil, common.Error(common.ErrResourceConflict,
+			common.Field("type", "callback"),
+			common.Field("name", callback.Name))
+	}
+	if _, err = s.storage.CreateCallback(callback); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return s.Get(callback.Namespace, callback.Name)
+}
+
+// Update update a callback
+func (s *callbackService) Update(callback *models.Callback) (*models.Callback, error) {
+	if _, err := s.storage.UpdateCallback(callback); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return s.Get(callback.Namespace, callback.Name)
+}
+
+// Delete delete a callback which is not referenced by any batch
+func (s *callbackService) Delete(namespace, name string) error {
+	count, err := s.storage.CountBatchByCallback(name, namespace)
+	if err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if count > 0 {
+		return common.Error(common.ErrRegisterDeleteCallback, common.Field("name", name))
+	}
+	if _, err = s.storage.DeleteCallback(name, namespace); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if _, err = s.storage.DeleteCallbackLog(name, namespace); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+// ListLog list the delivery history of a callback, the latest first
+func (s *callbackService) ListLog(namespace, name string, filter *models.Filter) (*models.ListView, error) {
+	logs, err := s.storage.ListCallbackLog(name, namespace, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountCallbackLog(name, namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if logs == nil {
+		logs = []models.CallbackLog{}
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    logs,
+	}, nil
+}
+
+// Callback renders the callback with the event args and sends the request,
+// server errors are retried with exponential backoff and the delivery is logged
+func (s *callbackService) Callback(name, namespace string, args map[string]string) error {
+	callback, err := s.Get(namespace, name)
+	if err != nil {
+		return err
+	}
+	record := &models.CallbackLog{
+		Namespace:    namespace,
+		CallbackName: name,
+		Event:        args[CallbackArgEvent],
+		Method:       callback.Method,
+		Url:          callback.Url,
+	}
+	req, err := renderCallback(callback, args)
+	if err == nil {
+		record.Url = req.url
+		record.Request = string(req.body)
+		err = s.send(req, record)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if _, e := s.storage.CreateCallbackLog(record); e != nil {
+		log.L().Error("failed to save callback log",
+			log.Any("namespace", namespace),
+			log.Any("name", name),
+			log.Error(e))
+	} else if s.logLimit > 0 {
+		if _, e = s.storage.PruneCallbackLog(name, namespace, s.logLimit); e != nil {
+			log.L().Error("failed to prune callback logs",
+				log.Any("namespace", namespace),
+				log.Any("name", name),
+				log.Error(e))
+		}
+	}
+	if err != nil {
+		return common.Error(common.ErrThirdServer, common.Field("name", name), common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+// Dispatch queues the callback to the workers without blocking, failures are only logged,
+// the callback is dropped if the queue is full or the service is closed
+func (s *callbackService) Dispatch(name, namespace string, args map[string]string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.closed {
+		select {
+		case s.tasks <- &callbackTask{name: name, namespace: namespace, args: args}:
+			return
+		default:
+		}
+	}
+	log.L().Error("failed to dispatch callback, the queue is full or closed",
+		log.Any("namespace", namespace),
+		log.Any("name", name),
+		log.Any("event", args[CallbackArgEvent]))
+}
+
+// Close stops dispatching and waits for the queued callbacks to be sent
+func (s *callbackService) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.tasks)
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *callbackService) working() {
+	defer s.wg.Done()
+	for t := range s.tasks {
+		if err := s.Callback(t.name, t.namespace, t.args); err != nil {
+			log.L().Error("failed to call back",
+				log.Any("namespace", t.namespace),
+				log.Any("name", t.name),
+				log.Any("event", t.args[CallbackArgEvent]),
+				log.Error(err))
+		}
+	}
+}
+
+func (s *callbackService) send(req *callbackRequest, record *models.CallbackLog) error {
+	backoff := s.backoff
+	for {
+		record.Attempts++
+		retry, err := s.do(req, record)
+		if err == nil || !retry || record.Attempts > s.retries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if s.maxBackoff > 0 && backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// do sends the request once and reports whether the failure can be retried
+func (s *callbackService) do(req *callbackRequest, record *models.CallbackLog) (bool, error) {
+	request, err := http.NewRequest(req.method, req.url, bytes.NewReader(req.body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range req.header {
+		request.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxCallbackResponseSize))
+	record.StatusCode = resp.StatusCode
+	record.Response = string(data)
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return false, nil
+	}
+	retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+}
+
+// renderCallback executes the url, params, header and body of the callback as
+// templates with the args, the body is the args merged with the rendered body of the callback
+func renderCallback(callback *models.Callback, args map[string]string) (*callbackRequest, error) {
+	render := func(text string) (string, error) {
+		tpl, err := template.New(callback.Name).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err = tpl.Execute(&buf, args); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	renderMap := func(m map[string]string) (map[string]string, error) {
+		res := map[string]string{}
+		for k, v := range m {
+			val, err := render(v)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = val
+		}
+		return res, nil
+	}
+
+	rawURL, err := render(callback.Url)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	params, err := renderMap(callback.Params)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+	header, err := renderMap(callback.Header)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderMap(callback.Body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range args {
+		if _, ok := body[k]; !ok {
+			body[k] = v
+		}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return &callbackRequest{
+		method: callback.Method,
+		url:    u.String(),
+		header: header,
+		body:   data,
+	}, nil
+}
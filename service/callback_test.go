@@ -0,0 +1,320 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func genCallbackTestCase() *models.Callback {
+	return &models.Callback{
+		Name:      "cb",
+		Namespace: "default",
+		Method:    "POST",
+		Url:       "http://localhost/callback",
+	}
+}
+
+func TestCallbackService_Get(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewCallbackService(mockObject.conf)
+	assert.NoError(t, err)
+
+	cb := genCallbackTestCase()
+	mockObject.dbStorage.EXPECT().GetCallback(cb.Name, cb.Namespace).Return(cb, nil)
+	res, err := cs.Get(cb.Namespace, cb.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, cb, res)
+
+	mockObject.dbStorage.EXPECT().GetCallback(cb.Name, cb.Namespace).Return(nil, nil)
+	_, err = cs.Get(cb.Namespace, cb.Name)
+	assert.Error(t, err)
+}
+
+func TestCallbackService_List(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewCallbackService(mockObject.conf)
+	assert.NoError(t, err)
+
+	filter := &models.Filter{Name: "%"}
+	mockObject.dbStorage.EXPECT().ListCallback("default", filter).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CountCallback("default", "%").Return(0, nil)
+	res, err := cs.List("default", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+	assert.Equal(t, []models.Callback{}, res.Items)
+}
+
+func TestCallbackService_CreateAndUpdate(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewCallbackService(mockObject.conf)
+	assert.NoError(t, err)
+	cb := genCallbackTestCase()
+
+	mockObject.dbStorage.EXPECT().GetCallback(cb.Name, cb.Namespace).Return(cb, nil)
+	_, err = cs.Create(cb)
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().GetCallback(cb.Name, cb.Namespace).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CreateCallback(cb).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetCallback(cb.Name, cb.Namespace).Return(cb, nil)
+	_, err = cs.Create(cb)
+	assert.NoError(t, err)
+
+	mockObject.dbStorage.EXPECT().UpdateCallback(cb).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetCallback(cb.Name, cb.Namespace).Return(cb, nil)
+	_, err = cs.Update(cb)
+	assert.NoError(t, err)
+}
+
+func TestCallbackService_Delete(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewCallbackService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.dbStorage.EXPECT().CountBatchByCallback("cb", "default").Return(1, nil)
+	err = cs.Delete("default", "cb")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is used")
+
+	mockObject.dbStorage.EXPECT().CountBatchByCallback("cb", "default").Return(0, nil)
+	mockObject.dbStorage.EXPECT().DeleteCallback("cb", "default").Return(nil, nil)
+	mockObject.dbStorage.EXPECT().DeleteCallbackLog("cb", "default").Return(nil, nil)
+	err = cs.Delete("default", "cb")
+	assert.NoError(t, err)
+}
+
+func TestCallbackService_ListLog(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewCallbackService(mockObject.conf)
+	assert.NoError(t, err)
+
+	filter := &models.Filter{PageNo: 1, PageSize: 10}
+	logs := []models.CallbackLog{{Id: 1, CallbackName: "cb", StatusCode: 200}}
+	mockObject.dbStorage.EXPECT().ListCallbackLog("cb", "default", filter).Return(logs, nil)
+	mockObject.dbStorage.EXPECT().CountCallbackLog("cb", "default").Return(1, nil)
+	res, err := cs.ListLog("default", "cb", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, logs, res.Items)
+
+	mockObject.dbStorage.EXPECT().ListCallbackLog("cb", "default", filter).Return(nil, fmt.Errorf("error"))
+	_, err = cs.ListLog("default", "cb", filter)
+	assert.Error(t, err)
+}
+
+func TestCallbackService_Callback(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	mockObject.conf.Callback.Timeout = time.Second
+	mockObject.conf.Callback.Retries = 2
+	mockObject.conf.Callback.Backoff = time.Millisecond
+	mockObject.conf.Callback.MaxBackoff = 2 * time.Millisecond
+	mockObject.conf.Callback.Workers = 1
+	mockObject.conf.Callback.QueueSize = 1
+	cs, err := NewCallbackService(mockObject.conf)
+	assert.NoError(t, err)
+
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "node01", r.URL.Query().Get("node"))
+		assert.Equal(t, "/hook/default", r.URL.Path)
+		assert.Equal(t, "default", r.Header.Get("X-Namespace"))
+		body := map[string]string{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "sn01", body["sn"])
+		assert.Equal(t, "", body["missing"])
+		switch body["status"] {
+		case "flaky":
+			if atomic.LoadInt32(&attempts) < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "down":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	cb := genCallbackTestCase()
+	cb.Url = ts.URL + "/hook/{{.namespace}}"
+	cb.Params = map[string]string{"node": "{{.nodeName}}"}
+	cb.Header = map[string]string{"X-Namespace": "{{.namespace}}"}
+	cb.Body = map[string]string{"sn": "{{.fingerprintValue}}", "status": "{{.status}}", "missing": "{{.missing}}"}
+	args := func(status string) map[string]string {
+		return map[string]string{
+			CallbackArgEvent:            CallbackEventActivate,
+			CallbackArgNamespace:        "default",
+			CallbackArgNodeName:         "node01",
+			CallbackArgFingerprintValue: "sn01",
+			"status":                    status,
+		}
+	}
+	mockObject.dbStorage.EXPECT().GetCallback(cb.Name, cb.Namespace).Return(cb, nil).AnyTimes()
+
+	// success at the first attempt
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).DoAndReturn(func(l *models.CallbackLog) (sql.Result, error) {
+		assert.Equal(t, CallbackEventActivate, l.Event)
+		assert.Equal(t, http.StatusOK, l.StatusCode)
+		assert.Equal(t, "ok", l.Response)
+		assert.Equal(t, 1, l.Attempts)
+		assert.Empty(t, l.Error)
+		assert.Contains(t, l.Url, "/hook/default?node=node01")
+		return nil, nil
+	})
+	err = cs.Callback(cb.Name, cb.Namespace, args("ok"))
+	assert.NoError(t, err)
+
+	// success after retry
+	atomic.StoreInt32(&attempts, 0)
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).DoAndReturn(func(l *models.CallbackLog) (sql.Result, error) {
+		assert.Equal(t, 2, l.Attempts)
+		assert.Equal(t, http.StatusOK, l.StatusCode)
+		return nil, nil
+	})
+	err = cs.Callback(cb.Name, cb.Namespace, args("flaky"))
+	assert.NoError(t, err)
+
+	// server error exhausts retries, the log failure is ignored
+	atomic.StoreInt32(&attempts, 0)
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).DoAndReturn(func(l *models.CallbackLog) (sql.Result, error) {
+		assert.Equal(t, 3, l.Attempts)
+		assert.Equal(t, http.StatusInternalServerError, l.StatusCode)
+		assert.NotEmpty(t, l.Error)
+		return nil, fmt.Errorf("db error")
+	})
+	err = cs.Callback(cb.Name, cb.Namespace, args("down"))
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	// client error is not retried
+	atomic.StoreInt32(&attempts, 0)
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).Return(nil, nil)
+	err = cs.Callback(cb.Name, cb.Namespace, args("bad"))
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+
+	// dispatch in background
+	done := make(chan struct{})
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).DoAndReturn(func(l *models.CallbackLog) (sql.Result, error) {
+		close(done)
+		return nil, nil
+	})
+	cs.Dispatch(cb.Name, cb.Namespace, args("ok"))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("callback is not dispatched")
+	}
+
+	// the worker is busy and the queue is full, the third callback is dropped
+	release := make(chan struct{})
+	var logs int32
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).DoAndReturn(func(l *models.CallbackLog) (sql.Result, error) {
+		if atomic.AddInt32(&logs, 1) == 1 {
+			<-release
+		}
+		return nil, nil
+	}).Times(2)
+	cs.Dispatch(cb.Name, cb.Namespace, args("ok"))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&logs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	cs.Dispatch(cb.Name, cb.Namespace, args("ok"))
+	cs.Dispatch(cb.Name, cb.Namespace, args("ok"))
+	close(release)
+
+	// the queued callback is sent before closed, nothing is dispatched after closed
+	assert.NoError(t, cs.Close())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&logs))
+	cs.Dispatch(cb.Name, cb.Namespace, args("ok"))
+	assert.NoError(t, cs.Close())
+}
+
+func TestCallbackService_CallbackNotFound(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewCallbackService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.dbStorage.EXPECT().GetCallback("cb", "default").Return(nil, nil)
+	err = cs.Callback("cb", "default", nil)
+	assert.Error(t, err)
+}
+
+func TestCallbackService_PruneLog(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	mockObject.conf.Callback.Timeout = time.Second
+	mockObject.conf.Callback.LogLimit = 2
+	cs, err := NewCallbackService(mockObject.conf)
+	assert.NoError(t, err)
+	defer cs.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+	cb := genCallbackTestCase()
+	cb.Url = ts.URL
+	mockObject.dbStorage.EXPECT().GetCallback(cb.Name, cb.Namespace).Return(cb, nil).AnyTimes()
+
+	// the logs exceeding the limit are pruned once a log is saved
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().PruneCallbackLog(cb.Name, cb.Namespace, 2).Return(nil, nil)
+	assert.NoError(t, cs.Callback(cb.Name, cb.Namespace, nil))
+
+	// the pruning failure is only logged
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().PruneCallbackLog(cb.Name, cb.Namespace, 2).Return(nil, fmt.Errorf("db error"))
+	assert.NoError(t, cs.Callback(cb.Name, cb.Namespace, nil))
+
+	// nothing is pruned if the log fails to save
+	mockObject.dbStorage.EXPECT().CreateCallbackLog(gomock.Any()).Return(nil, fmt.Errorf("db error"))
+	assert.NoError(t, cs.Callback(cb.Name, cb.Namespace, nil))
+}
+
+func TestRenderCallback(t *testing.T) {
+	cb := genCallbackTestCase()
+	cb.Url = "http://localhost/{{.nodeName}}?a=b"
+	req, err := renderCallback(cb, map[string]string{"nodeName": "n1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost/n1?a=b", req.url)
+	assert.Equal(t, `{"nodeName":"n1"}`, string(req.body))
+
+	req, err = renderCallback(cb, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(req.body))
+
+	// the args are merged into the body, the body of the callback takes precedence
+	cb.Body = map[string]string{"node": "{{.nodeName}}", "nodeName": "n0"}
+	req, err = renderCallback(cb, map[string]string{"nodeName": "n1", "ip": "1.1.1.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ip":"1.1.1.1","node":"n1","nodeName":"n0"}`, string(req.body))
+
+	cb.Body = map[string]string{"a": "{{.nodeName"}
+	_, err = renderCallback(cb, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/baetyl/baetyl-go/v2/pki"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/metrics"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/cert_manager.go -package=service github.com/baetyl/baetyl-cloud/v2/service CertManagerService
+
+// the status of the certificates not renewed
+const (
+	certStatusExpiring = "expiring"
+	certStatusExpired  = "expired"
+)
+
+var (
+	certRenewals = metrics.NewCounterVec("baetyl_cloud_certificate_renewals_total",
+		"The number of renewals of the node certificates by the result.", "result")
+	expiringCerts = &certExpiryTracker{}
+	certExpiry    = metrics.NewGaugeFunc("baetyl_cloud_certificates_expiring",
+		"The number of certificates expiring within the renewal window and not renewed by the last scan.",
+		expiringCerts.collect, "type", "status")
+)
+
+func init() {
+	metrics.MustRegister(certRenewals, certExpiry)
+}
+
+// CertManagerService watches the expiry of the certificates issued by the pki,
+// the node certificates are renewed and delivered to the nodes by the sync secrets of their core apps,
+// the root and server certificates are only reported since they are provided by the configuration
+type CertManagerService interface {
+	// ListExpiringCerts lists the certificates expiring within the duration including the expired ones,
+	// the renewal window is used if the duration is not positive
+	ListExpiringCerts(within time.Duration) ([]models.ExpiringCert, error)
+	// Reconcile renews the node certificates expiring within the renewal window and refreshes the metrics
+	Reconcile() error
+}
+
+type certManagerService struct {
+	pki         plugin.PKI
+	pkiService  PKIService
+	secret      SecretService
+	app         ApplicationService
+	node        NodeService
+	renewBefore time.Duration
+}
+
+// NewCertManagerService NewCertManagerService
+func NewCertManagerService(config *config.CloudConfig) (CertManagerService, error) {
+	pk, err := plugin.GetPlugin(config.Plugin.PKI)
+	if err != nil {
+		return nil, err
+	}
+	ps, err := NewPKIService(config)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := NewSecretService(config)
+	if err != nil {
+		return nil, err
+	}
+	as, err := NewApplicationService(config)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := NewNodeService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &certManagerService{
+		pki:         pk.(plugin.PKI),
+		pkiService:  ps,
+		secret:      ss,
+		app:         as,
+		node:        ns,
+		renewBefore: config.CertManager.RenewBefore,
+	}, nil
+}
+
+// ListExpiringCerts lists the certificates in the order of the expiry, the certificates signed for the existing nodes,
+// whose common names are <namespace>.<node>, are node certificates and the ones of the deleted nodes are orphan certificates,
+// the other certificates not of ca are server certificates. The certificate is skipped if its node fails to get
+func (s *certManagerService) ListExpiringCerts(within time.Duration) ([]models.ExpiringCert, error) {
+	if within <= 0 {
+		within = s.renewBefore
+	}
+	now := time.Now()
+	certs, err := s.pki.ListCertByNotAfter(now.Add(within))
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	res := []models.ExpiringCert{}
+	for _, c := range certs {
+		cert := models.ExpiringCert{
+			CertID:     c.CertId,
+			Type:       models.CertTypeServer,
+			CommonName: c.CommonName,
+			NotBefore:  c.NotBefore,
+			NotAfter:   c.NotAfter,
+			Expired:    !now.Before(c.NotAfter),
+		}
+		if isCACert(c.Content) {
+			cert.Type = models.CertTypeRoot
+		} else if ns, name, ok := nodeOfCert(c.CommonName); ok {
+			_, err = s.node.Get(ns, name)
+			if err == nil {
+				cert.Type = models.CertTypeNode
+				cert.Namespace = ns
+				cert.NodeName = name
+			} else if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				cert.Type = models.CertTypeOrphan
+			} else {
+				log.L().Error("failed to get node of certificate",
+					log.Any("namespace", ns),
+					log.Any("node", name),
+					log.Any("certId", c.CertId),
+					log.Error(err))
+				continue
+			}
+		}
+		res = append(res, cert)
+	}
+	return res, nil
+}
+
+// Reconcile renews the node certificates one by one, the failures are logged and retried by the next reconciliation
+func (s *certManagerService) Reconcile() error {
+	certs, err := s.ListExpiringCerts(s.renewBefore)
+	if err != nil {
+		return err
+	}
+	var pending []models.ExpiringCert
+	for _, cert := range certs {
+		if cert.Type == models.CertTypeNode {
+			if err = s.renew(&cert); err == nil {
+				certRenewals.WithLabelValues("success").Inc()
+				log.L().Info("node certificate renewed",
+					log.Any("namespace", cert.Namespace),
+					log.Any("node", cert.NodeName),
+					log.Any("certId", cert.CertID))
+				continue
+			}
+			certRenewals.WithLabelValues("failure").Inc()
+			log.L().Error("failed to renew node certificate",
+				log.Any("namespace", cert.Namespace),
+				log.Any("node", cert.NodeName),
+				log.Any("certId", cert.CertID),
+				log.Error(err))
+		}
+		pending = append(pending, cert)
+	}
+	expiringCerts.record(pending)
+	return nil
+}
+
+// renew signs a new certificate into the sync secret of the core app of the node if the secret still holds the certificate,
+// delivers the secret to the node by updating the core app and its version in the desire, then deletes the certificate,
+// the steps are repeatable so that the renewal interrupted is completed by the next reconciliation
+func (s *certManagerService) renew(cert *models.ExpiringCert) error {
+	app, err := getCoreApp(s.node, s.app, cert.Namespace, cert.NodeName)
+	if err != nil {
+		return err
+	}
+	var ref *specV1.ObjectReference
+	for _, v := range app.Volumes {
+		if v.Secret != nil && (v.Name == "node-cert" || v.Name == "cert-sync") {
+			ref = v.Secret
+			break
+		}
+	}
+	if ref == nil {
+		return common.Error(common.ErrResourceNotFound, common.Field("type", "secret"),
+			common.Field("name", "node-cert"), common.Field("namespace", cert.Namespace))
+	}
+	secret, err := s.secret.Get(cert.Namespace, ref.Name, "")
+	if err != nil {
+		return err
+	}
+	if secret.Annotations[common.AnnotationPkiCertID] == cert.CertID {
+		if secret, err = s.reissue(cert.Namespace, cert.CommonName, secret); err != nil {
+			return err
+		}
+	}
+	if common.CompareNumericalString(secret.Version, ref.Version) > 0 {
+		ref.Version = secret.Version
+		if app, err = s.app.Update(cert.Namespace, app); err != nil {
+			return err
+		}
+		if _, err = s.node.UpdateNodeAppVersion(cert.Namespace, app, models.DeployTriggerCert); err != nil {
+			return err
+		}
+	}
+	// the certificate replaced is no longer used by the node
+	return s.pkiService.DeleteClientCertificate(cert.CertID)
+}
+
+// reissue updates the secret with a new client certificate, the new certificate is deleted if the secret fails to update,
+// e.g. the secret is renewed by another replica at the same time
+func (s *certManagerService) reissue(namespace, cn string, secret *specV1.Secret) (*specV1.Secret, error) {
+	cred, err := s.pkiService.SignClientCertificate(cn, models.AltNames{})
+	if err != nil {
+		return nil, err
+	}
+	ca, err := s.pkiService.GetCA()
+	if err != nil {
+		s.discard(cred.CertId)
+		return nil, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data["client.pem"] = cred.CertPEM
+	secret.Data["client.key"] = cred.KeyPEM
+	secret.Data["ca.pem"] = ca
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[common.AnnotationPkiCertID] = cred.CertId
+	secret.UpdateTimestamp = time.Now()
+	res, err := s.secret.Update(namespace, secret)
+	if err != nil {
+		s.discard(cred.CertId)
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *certManagerService) discard(certID string) {
+	if err := s.pkiService.DeleteClientCertificate(certID); err != nil {
+		common.LogDirtyData(err, log.Any("type", "pki"), log.Any(common.AnnotationPkiCertID, certID))
+	}
+}
+
+// nodeOfCert returns the namespace and the name of the node from the common name of the node certificate
+func nodeOfCert(cn string) (string, string, bool) {
+	parts := strings.SplitN(cn, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// isCACert returns whether the base64 encoded certificate is a ca
+func isCACert(content string) bool {
+	crt, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return false
+	}
+	certs, err := pki.ParseCertificates(crt)
+	if err != nil || len(certs) == 0 {
+		return false
+	}
+	return certs[0].IsCA
+}
+
+// certExpiryTracker keeps the number of the certificates not renewed by the last scan
+type certExpiryTracker struct {
+	counts map[string]float64
+	mu     sync.Mutex
+}
+
+func (t *certExpiryTracker) record(certs []models.ExpiringCert) {
+	counts := map[string]float64{}
+	for _, typ := range []string{models.CertTypeRoot, models.CertTypeServer, models.CertTypeNode, models.CertTypeOrphan} {
+		counts[metrics.LabelValues(typ, certStatusExpiring)] = 0
+		counts[metrics.LabelValues(typ, certStatusExpired)] = 0
+	}
+	for _, c := range certs {
+		status := certStatusExpiring
+		if c.Expired {
+			status = certStatusExpired
+		}
+		counts[metrics.LabelValues(c.Type, status)]++
+	}
+	t.mu.Lock()
+	t.counts = counts
+	t.mu.Unlock()
+}
+
+func (t *certExpiryTracker) collect() map[string]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := make(map[string]float64, len(t.counts))
+	for k, v := range t.counts {
+		res[k] = v
+	}
+	return res
+}
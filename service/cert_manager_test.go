@@ -0,0 +1,212 @@
+package service
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/metrics"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+type certManagerMocks struct {
+	*MockServices
+	pkiService *ms.MockPKIService
+	secret     *ms.MockSecretService
+	app        *ms.MockApplicationService
+	node       *ms.MockNodeService
+}
+
+func initCertManagerService(t *testing.T) (*certManagerService, *certManagerMocks) {
+	mockObject := InitMockEnvironment(t)
+	mocks := &certManagerMocks{
+		MockServices: mockObject,
+		pkiService:   ms.NewMockPKIService(mockObject.ctl),
+		secret:       ms.NewMockSecretService(mockObject.ctl),
+		app:          ms.NewMockApplicationService(mockObject.ctl),
+		node:         ms.NewMockNodeService(mockObject.ctl),
+	}
+	return &certManagerService{
+		pki:         mockObject.pki,
+		pkiService:  mocks.pkiService,
+		secret:      mocks.secret,
+		app:         mocks.app,
+		node:        mocks.node,
+		renewBefore: 24 * time.Hour,
+	}, mocks
+}
+
+func genExpiringCert(t *testing.T, id, cn, file string, notAfter time.Time) plugin.Cert {
+	crt, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	return plugin.Cert{
+		CertId:     id,
+		CommonName: cn,
+		Content:    base64.StdEncoding.EncodeToString(crt),
+		NotAfter:   notAfter,
+	}
+}
+
+func genCertCoreApp(secretVersion string) *specV1.Application {
+	app := genCoreApp("n1", "3", "baetyl:v2.2.0")
+	app.Volumes = []specV1.Volume{{Name: "node-cert", VolumeSource: specV1.VolumeSource{
+		Secret: &specV1.ObjectReference{Name: "sync-cert", Version: secretVersion},
+	}}}
+	return app
+}
+
+func TestCertManagerService_ListExpiringCerts(t *testing.T) {
+	s, mocks := initCertManagerService(t)
+	defer mocks.Close()
+
+	now := time.Now()
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).DoAndReturn(func(before time.Time) ([]plugin.Cert, error) {
+		assert.WithinDuration(t, now.Add(time.Hour), before, time.Minute)
+		return []plugin.Cert{
+			genExpiringCert(t, "c0", "root.ca", "../scripts/native/certs/client_ca.crt", now.Add(-time.Minute)),
+			genExpiringCert(t, "c1", "default.n1", "../scripts/native/certs/server.crt", now.Add(time.Minute)),
+			genExpiringCert(t, "c2", "default.n2", "../scripts/native/certs/server.crt", now.Add(time.Minute)),
+			genExpiringCert(t, "c3", "baetyl-cloud", "../scripts/native/certs/server.crt", now.Add(time.Minute)),
+		}, nil
+	})
+	mocks.node.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1"}, nil)
+	mocks.node.EXPECT().Get("default", "n2").Return(nil, common.Error(common.ErrResourceNotFound))
+	res, err := s.ListExpiringCerts(time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, res, 4)
+	assert.Equal(t, models.CertTypeRoot, res[0].Type)
+	assert.True(t, res[0].Expired)
+	assert.Equal(t, models.ExpiringCert{
+		CertID:     "c1",
+		Type:       models.CertTypeNode,
+		CommonName: "default.n1",
+		Namespace:  "default",
+		NodeName:   "n1",
+		NotAfter:   res[1].NotAfter,
+	}, res[1])
+	assert.Equal(t, models.CertTypeOrphan, res[2].Type)
+	assert.Empty(t, res[2].NodeName)
+	assert.Equal(t, models.CertTypeServer, res[3].Type)
+
+	// the renewal window is used by default
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).DoAndReturn(func(before time.Time) ([]plugin.Cert, error) {
+		assert.WithinDuration(t, now.Add(24*time.Hour), before, time.Minute)
+		return nil, nil
+	})
+	res, err = s.ListExpiringCerts(0)
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	// the certificate is skipped if its node fails to get
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).Return([]plugin.Cert{
+		genExpiringCert(t, "c1", "default.n1", "../scripts/native/certs/server.crt", now),
+		genExpiringCert(t, "c3", "baetyl-cloud", "../scripts/native/certs/server.crt", now),
+	}, nil)
+	mocks.node.EXPECT().Get("default", "n1").Return(nil, common.Error(common.ErrDatabase))
+	res, err = s.ListExpiringCerts(0)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "c3", res[0].CertID)
+
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).Return(nil, common.Error(common.ErrDatabase))
+	_, err = s.ListExpiringCerts(0)
+	assert.Error(t, err)
+}
+
+func TestCertManagerService_Reconcile(t *testing.T) {
+	s, mocks := initCertManagerService(t)
+	defer mocks.Close()
+
+	now := time.Now()
+	certs := []plugin.Cert{
+		genExpiringCert(t, "c0", "root.ca", "../scripts/native/certs/client_ca.crt", now.Add(time.Hour)),
+		genExpiringCert(t, "c1", "default.n1", "../scripts/native/certs/server.crt", now.Add(time.Hour)),
+	}
+	expectCoreApp := func(secretVersion string, annotations map[string]string) {
+		mocks.node.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1"}, nil)
+		mocks.node.EXPECT().GetDesire("default", "n1").Return(&specV1.Desire{
+			common.DesiredSysApplications: []specV1.AppInfo{{Name: "baetyl-core-n1", Version: "3"}},
+		}, nil)
+		mocks.app.EXPECT().Get("default", "baetyl-core-n1", "").Return(genCertCoreApp("4"), nil)
+		mocks.secret.EXPECT().Get("default", "sync-cert", "").Return(&specV1.Secret{
+			Name:        "sync-cert",
+			Namespace:   "default",
+			Version:     secretVersion,
+			Annotations: annotations,
+			Data:        map[string][]byte{"client.pem": []byte("old")},
+		}, nil)
+	}
+	success := testutil.ToFloat64(certRenewals.WithLabelValues("success"))
+	failure := testutil.ToFloat64(certRenewals.WithLabelValues("failure"))
+
+	// renewed and delivered to the node
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).Return(certs, nil)
+	expectCoreApp("4", map[string]string{common.AnnotationPkiCertID: "c1"})
+	mocks.pkiService.EXPECT().SignClientCertificate("default.n1", models.AltNames{}).Return(&models.PEMCredential{
+		CertPEM: []byte("new"), KeyPEM: []byte("key"), CertId: "c9",
+	}, nil)
+	mocks.pkiService.EXPECT().GetCA().Return([]byte("ca"), nil)
+	mocks.secret.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, secret *specV1.Secret) (*specV1.Secret, error) {
+		assert.Equal(t, "c9", secret.Annotations[common.AnnotationPkiCertID])
+		assert.Equal(t, "new", string(secret.Data["client.pem"]))
+		assert.Equal(t, "key", string(secret.Data["client.key"]))
+		assert.Equal(t, "ca", string(secret.Data["ca.pem"]))
+		res := *secret
+		res.Version = "5"
+		return &res, nil
+	})
+	mocks.app.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "5", app.Volumes[0].Secret.Version)
+		return app, nil
+	})
+	mocks.node.EXPECT().UpdateNodeAppVersion("default", gomock.Any(), models.DeployTriggerCert).Return([]string{"n1"}, nil)
+	mocks.pkiService.EXPECT().DeleteClientCertificate("c1").Return(nil)
+	assert.NoError(t, s.Reconcile())
+	assert.Equal(t, success+1, testutil.ToFloat64(certRenewals.WithLabelValues("success")))
+	counts := expiringCerts.collect()
+	assert.Equal(t, float64(1), counts[metrics.LabelValues(models.CertTypeRoot, certStatusExpiring)])
+	assert.Equal(t, float64(0), counts[metrics.LabelValues(models.CertTypeNode, certStatusExpiring)])
+	orphans, ok := counts[metrics.LabelValues(models.CertTypeOrphan, certStatusExpiring)]
+	assert.True(t, ok)
+	assert.Equal(t, float64(0), orphans)
+
+	// the new certificate is discarded if the secret fails to update
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).Return(certs, nil)
+	expectCoreApp("4", map[string]string{common.AnnotationPkiCertID: "c1"})
+	mocks.pkiService.EXPECT().SignClientCertificate("default.n1", models.AltNames{}).Return(&models.PEMCredential{CertId: "c9"}, nil)
+	mocks.pkiService.EXPECT().GetCA().Return([]byte("ca"), nil)
+	mocks.secret.EXPECT().Update("default", gomock.Any()).Return(nil, common.Error(common.ErrResourceVersionConflict))
+	mocks.pkiService.EXPECT().DeleteClientCertificate("c9").Return(nil)
+	assert.NoError(t, s.Reconcile())
+	assert.Equal(t, failure+1, testutil.ToFloat64(certRenewals.WithLabelValues("failure")))
+	counts = expiringCerts.collect()
+	assert.Equal(t, float64(1), counts[metrics.LabelValues(models.CertTypeNode, certStatusExpiring)])
+
+	// the renewal interrupted after the secret updated is completed
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).Return(certs[1:], nil)
+	expectCoreApp("5", map[string]string{common.AnnotationPkiCertID: "c9"})
+	mocks.app.EXPECT().Update("default", gomock.Any()).Return(genCertCoreApp("5"), nil)
+	mocks.node.EXPECT().UpdateNodeAppVersion("default", gomock.Any(), models.DeployTriggerCert).Return([]string{"n1"}, nil)
+	mocks.pkiService.EXPECT().DeleteClientCertificate("c1").Return(nil)
+	assert.NoError(t, s.Reconcile())
+	assert.Equal(t, success+2, testutil.ToFloat64(certRenewals.WithLabelValues("success")))
+
+	// the certificate already delivered is deleted only
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).Return(certs[1:], nil)
+	expectCoreApp("4", map[string]string{common.AnnotationPkiCertID: "c9"})
+	mocks.pkiService.EXPECT().DeleteClientCertificate("c1").Return(nil)
+	assert.NoError(t, s.Reconcile())
+	assert.Equal(t, success+3, testutil.ToFloat64(certRenewals.WithLabelValues("success")))
+
+	mocks.pki.EXPECT().ListCertByNotAfter(gomock.Any()).Return(nil, common.Error(common.ErrDatabase))
+	assert.Error(t, s.Reconcile())
+}
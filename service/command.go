@@ -0,0 +1,228 @@
+package service
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/command.go -package=service github.com/baetyl/baetyl-cloud/v2/service CommandService
+
+// the limits of commands
+const (
+	DefaultCommandTimeout = 30
+	MaxCommandTimeout     = 600
+	MaxPendingCommands    = 10
+)
+
+// CommandService queues the commands of nodes, which are delivered by the sync links and responded by nodes
+type CommandService interface {
+	Create(cmd *models.Command) (*models.Command, error)
+	Get(namespace, node, id string) (*models.Command, error)
+	List(namespace, node string, filter *models.TaskFilter) (*models.ListView, error)
+	ListPending(namespace, node string) ([]models.Command, error)
+	Respond(namespace, node string, result *models.CommandResult) (*models.Command, error)
+}
+
+type commandService struct {
+	storage plugin.DBStorage
+	events  EventService
+}
+
+// NewCommandService NewCommandService
+func NewCommandService(config *config.CloudConfig) (CommandService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	es, err := NewEventService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &commandService{
+		storage: ds.(plugin.DBStorage),
+		events:  es,
+	}, nil
+}
+
+// Create queues the command of the node, and notifies the sync links to deliver it to the node if connected
+func (s *commandService) Create(cmd *models.Command) (*models.Command, error) {
+	if !models.CommandTypes[cmd.Type] {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "unknown command type "+cmd.Type))
+	}
+	if cmd.Timeout == 0 {
+		cmd.Timeout = DefaultCommandTimeout
+	}
+	if cmd.Timeout < 0 || cmd.Timeout > MaxCommandTimeout {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid command timeout"))
+	}
+	pending, err := s.ListPending(cmd.Namespace, cmd.Node)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) >= MaxPendingCommands {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "too many pending commands of the node"))
+	}
+
+	now := time.Now()
+	cmd.ID = common.UUIDPrune()
+	cmd.State = models.CommandPending
+	cmd.Result, cmd.Error = nil, ""
+	cmd.CreateTime, cmd.UpdateTime = now, now
+	task, err := cmd.ToTask()
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err = s.storage.CreateTask(task); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+
+	// the command is delivered once the node reconnects if the event is lost
+	event := &models.NodeEvent{
+		Type:      models.NodeEventCommand,
+		Namespace: cmd.Namespace,
+		Name:      cmd.Node,
+		Command:   cmd,
+		Time:      now.UTC(),
+	}
+	if err = s.events.PublishNodeEvent(event); err != nil {
+		log.L().Warn("failed to publish node command event",
+			log.Any(common.KeyContextNamespace, cmd.Namespace),
+			log.Any("name", cmd.Node),
+			log.Any("command", cmd.ID),
+			log.Error(err))
+	}
+	return cmd, nil
+}
+
+// Get get the command of the node
+func (s *commandService) Get(namespace, node, id string) (*models.Command, error) {
+	task, err := s.storage.GetTask(id)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if task == nil || task.Type != models.TaskCommand || task.Namespace != namespace || task.Node != node {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "command"),
+			common.Field("name", id), common.Field("namespace", namespace))
+	}
+	return s.decode(task)
+}
+
+// List list the commands of the node with pagination, the latest first
+func (s *commandService) List(namespace, node string, filter *models.TaskFilter) (*models.ListView, error) {
+	filter.Type = models.TaskCommand
+	tasks, err := s.storage.ListNodeTask(namespace, node, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountNodeTask(namespace, node, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	cmds := make([]models.Command, 0, len(tasks))
+	for i := range tasks {
+		cmd, err := s.decode(&tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		cmds = append(cmds, *cmd)
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    cmds,
+	}, nil
+}
+
+// ListPending list the pending commands of the node to deliver, the earliest first
+func (s *commandService) ListPending(namespace, node string) ([]models.Command, error) {
+	filter := &models.TaskFilter{Type: models.TaskCommand, State: models.CommandPending}
+	tasks, err := s.storage.ListNodeTask(namespace, node, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	cmds := make([]models.Command, 0, len(tasks))
+	for i := len(tasks) - 1; i >= 0; i-- {
+		cmd, err := s.decode(&tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		if cmd.State == models.CommandPending {
+			cmds = append(cmds, *cmd)
+		}
+	}
+	return cmds, nil
+}
+
+// Respond records the result of the pending command responded by the node
+func (s *commandService) Respond(namespace, node string, result *models.CommandResult) (*models.Command, error) {
+	cmd, err := s.Get(namespace, node, result.ID)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.State != models.CommandPending {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the command is already "+cmd.State))
+	}
+	cmd.State = models.CommandSucceeded
+	if result.Error != "" {
+		cmd.State = models.CommandFailed
+	}
+	cmd.Result, cmd.Error = result.Result, result.Error
+	ok, err := s.update(cmd, models.CommandPending)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the command is no longer pending"))
+	}
+	return cmd, nil
+}
+
+// decode returns the command of the task, the pending command is timed out once its deadline is passed
+func (s *commandService) decode(task *models.Task) (*models.Command, error) {
+	cmd, err := models.NewCommandFromTask(task)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if cmd.State == models.CommandPending && time.Now().After(cmd.Deadline()) {
+		cmd.State = models.CommandTimeout
+		ok, err := s.update(cmd, models.CommandPending)
+		if err != nil {
+			return nil, err
+		}
+		// responded by the node meanwhile
+		if !ok {
+			if task, err = s.storage.GetTask(cmd.ID); err != nil || task == nil {
+				return nil, common.Error(common.ErrDatabase, common.Field("error", "failed to reload the command "+cmd.ID))
+			}
+			if cmd, err = models.NewCommandFromTask(task); err != nil {
+				return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+			}
+		}
+	}
+	return cmd, nil
+}
+
+// update saves the command only if it is still in the old state, false is returned if its state is changed by others meanwhile
+func (s *commandService) update(cmd *models.Command, oldState string) (bool, error) {
+	cmd.UpdateTime = time.Now()
+	task, err := cmd.ToTask()
+	if err != nil {
+		return false, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	res, err := s.storage.UpdateTaskByState(task, oldState)
+	if err != nil {
+		return false, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return n > 0, nil
+}
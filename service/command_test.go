@@ -0,0 +1,139 @@
+package service
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestCommandService(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewCommandService(mockObject.conf)
+	assert.NoError(t, err)
+
+	pendingFilter := &models.TaskFilter{Type: models.TaskCommand, State: models.CommandPending}
+
+	// invalid commands
+	_, err = cs.Create(&models.Command{Namespace: "default", Node: "n0", Type: "reboot"})
+	assert.Error(t, err)
+	_, err = cs.Create(&models.Command{Namespace: "default", Node: "n0", Type: models.CommandTailLogs, Timeout: MaxCommandTimeout + 1})
+	assert.Error(t, err)
+
+	// too many pending commands
+	now := time.Now()
+	pending := make([]models.Task, MaxPendingCommands)
+	for i := range pending {
+		cmd := &models.Command{ID: fmt.Sprintf("c%d", i), Type: models.CommandNodeInfo, Timeout: 30, State: models.CommandPending, CreateTime: now}
+		task, err := cmd.ToTask()
+		assert.NoError(t, err)
+		pending[i] = *task
+	}
+	mockObject.dbStorage.EXPECT().ListNodeTask("default", "n0", pendingFilter).Return(pending, nil)
+	_, err = cs.Create(&models.Command{Namespace: "default", Node: "n0", Type: models.CommandNodeInfo})
+	assert.Error(t, err)
+
+	// created and published
+	var created *models.Task
+	mockObject.dbStorage.EXPECT().ListNodeTask("default", "n0", pendingFilter).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CreateTask(gomock.Any()).DoAndReturn(func(task *models.Task) (interface{}, error) {
+		created = task
+		return nil, nil
+	})
+	mockObject.pubsub.EXPECT().Publish("baetyl.node.event.default", gomock.Any()).Return(fmt.Errorf("error"))
+	cmd, err := cs.Create(&models.Command{
+		Namespace: "default",
+		Node:      "n0",
+		Type:      models.CommandTailLogs,
+		Args:      map[string]string{"service": "app"},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cmd.ID)
+	assert.Equal(t, models.CommandPending, cmd.State)
+	assert.Equal(t, DefaultCommandTimeout, cmd.Timeout)
+	assert.Equal(t, models.TaskCommand, created.Type)
+	assert.Equal(t, cmd.ID, created.TraceId)
+
+	// get
+	mockObject.dbStorage.EXPECT().GetTask(cmd.ID).Return(created, nil)
+	res, err := cs.Get("default", "n0", cmd.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"service": "app"}, res.Args)
+	mockObject.dbStorage.EXPECT().GetTask(cmd.ID).Return(created, nil)
+	_, err = cs.Get("default", "n1", cmd.ID)
+	assert.Error(t, err)
+	mockObject.dbStorage.EXPECT().GetTask("x").Return(nil, nil)
+	_, err = cs.Get("default", "n0", "x")
+	assert.Error(t, err)
+
+	// respond
+	var updated *models.Task
+	mockObject.dbStorage.EXPECT().GetTask(cmd.ID).Return(created, nil)
+	mockObject.dbStorage.EXPECT().UpdateTaskByState(gomock.Any(), models.CommandPending).DoAndReturn(func(task *models.Task, _ string) (interface{}, error) {
+		updated = task
+		return driver.RowsAffected(1), nil
+	})
+	res, err = cs.Respond("default", "n0", &models.CommandResult{ID: cmd.ID, Error: "no such service"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.CommandFailed, res.State)
+	assert.Equal(t, "no such service", res.Error)
+	assert.Equal(t, models.CommandFailed, updated.State)
+
+	// the finished command is not responded again
+	mockObject.dbStorage.EXPECT().GetTask(cmd.ID).Return(updated, nil)
+	_, err = cs.Respond("default", "n0", &models.CommandResult{ID: cmd.ID, Result: "ok"})
+	assert.Error(t, err)
+
+	// the command responded or timed out by others meanwhile
+	mockObject.dbStorage.EXPECT().GetTask(cmd.ID).Return(created, nil)
+	mockObject.dbStorage.EXPECT().UpdateTaskByState(gomock.Any(), models.CommandPending).Return(driver.RowsAffected(0), nil)
+	_, err = cs.Respond("default", "n0", &models.CommandResult{ID: cmd.ID, Result: "ok"})
+	assert.Error(t, err)
+
+	// the pending commands are timed out once their deadlines are passed
+	expired := &models.Command{ID: "c0", Namespace: "default", Node: "n0", Type: models.CommandNodeInfo,
+		Timeout: 1, State: models.CommandPending, CreateTime: now.Add(-time.Minute)}
+	expiredTask, err := expired.ToTask()
+	assert.NoError(t, err)
+	mockObject.dbStorage.EXPECT().ListNodeTask("default", "n0", pendingFilter).Return([]models.Task{*created, *expiredTask}, nil)
+	mockObject.dbStorage.EXPECT().UpdateTaskByState(gomock.Any(), models.CommandPending).DoAndReturn(func(task *models.Task, _ string) (interface{}, error) {
+		assert.Equal(t, "c0", task.TraceId)
+		assert.Equal(t, models.CommandTimeout, task.State)
+		return driver.RowsAffected(1), nil
+	})
+	cmds, err := cs.ListPending("default", "n0")
+	assert.NoError(t, err)
+	assert.Len(t, cmds, 1)
+	assert.Equal(t, cmd.ID, cmds[0].ID)
+
+	// the command responded before timed out is reloaded
+	respondedTask := *expiredTask
+	respondedTask.State = models.CommandSucceeded
+	mockObject.dbStorage.EXPECT().GetTask("c0").Return(expiredTask, nil)
+	mockObject.dbStorage.EXPECT().UpdateTaskByState(gomock.Any(), models.CommandPending).Return(driver.RowsAffected(0), nil)
+	mockObject.dbStorage.EXPECT().GetTask("c0").Return(&respondedTask, nil)
+	res, err = cs.Get("default", "n0", "c0")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CommandSucceeded, res.State)
+
+	// list
+	filter := &models.TaskFilter{Filter: models.Filter{PageNo: 1, PageSize: 10}}
+	mockObject.dbStorage.EXPECT().ListNodeTask("default", "n0", filter).Return([]models.Task{*updated}, nil)
+	mockObject.dbStorage.EXPECT().CountNodeTask("default", "n0", filter).Return(1, nil)
+	list, err := cs.List("default", "n0", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, models.TaskCommand, filter.Type)
+	assert.Equal(t, 1, list.Total)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, models.CommandFailed, list.Items.([]models.Command)[0].State)
+
+	mockObject.dbStorage.EXPECT().ListNodeTask("default", "n0", filter).Return(nil, fmt.Errorf("error"))
+	_, err = cs.List("default", "n0", filter)
+	assert.Error(t, err)
+}
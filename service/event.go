@@ -0,0 +1,95 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/event.go -package=service github.com/baetyl/baetyl-cloud/v2/service EventService
+
+const nodeEventTopic = "baetyl.node.event.%s"
+
+// EventService publishes the node events to the pubsub plugin, so that the events reported to one replica are received by all replicas
+type EventService interface {
+	PublishNodeEvent(event *models.NodeEvent) error
+	SubscribeNodeEvent(namespace string) (chan interface{}, error)
+	UnsubscribeNodeEvent(namespace string, ch chan interface{}) error
+}
+
+type eventService struct {
+	pubsub plugin.Pubsub
+	name   string
+}
+
+// NewEventService new EventService, the node events are not published if the pubsub plugin is not registered,
+// so that the nodes are still served while the node events can not be subscribed
+func NewEventService(config *config.CloudConfig) (EventService, error) {
+	ps, err := plugin.GetPlugin(config.Plugin.Pubsub)
+	if e, ok := err.(errors.Coder); ok && e.Code() == string(common.ErrPluginNotFound) {
+		log.L().Warn("the node events are disabled without the pubsub plugin", log.Any("plugin", config.Plugin.Pubsub))
+		return &eventService{name: config.Plugin.Pubsub}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &eventService{
+		pubsub: ps.(plugin.Pubsub),
+		name:   config.Plugin.Pubsub,
+	}, nil
+}
+
+// PublishNodeEvent publish the event to the topic of its namespace, the event is encoded as json to pass through networked pubsub
+func (e *eventService) PublishNodeEvent(event *models.NodeEvent) error {
+	if e.pubsub == nil {
+		return nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return e.pubsub.Publish(fmt.Sprintf(nodeEventTopic, event.Namespace), data)
+}
+
+// SubscribeNodeEvent subscribe the node events of the namespace, the messages are decoded by DecodeNodeEvent
+func (e *eventService) SubscribeNodeEvent(namespace string) (chan interface{}, error) {
+	if e.pubsub == nil {
+		return nil, common.Error(common.ErrPluginNotFound, common.Field("name", e.name))
+	}
+	return e.pubsub.Subscribe(fmt.Sprintf(nodeEventTopic, namespace))
+}
+
+// UnsubscribeNodeEvent unsubscribe the node events of the namespace
+func (e *eventService) UnsubscribeNodeEvent(namespace string, ch chan interface{}) error {
+	if e.pubsub == nil {
+		return nil
+	}
+	return e.pubsub.Unsubscribe(fmt.Sprintf(nodeEventTopic, namespace), ch)
+}
+
+// DecodeNodeEvent decode the message received from the subscription
+func DecodeNodeEvent(msg interface{}) (*models.NodeEvent, error) {
+	var data []byte
+	switch m := msg.(type) {
+	case *models.NodeEvent:
+		return m, nil
+	case []byte:
+		data = m
+	case string:
+		data = []byte(m)
+	default:
+		return nil, fmt.Errorf("unknown node event type %T", msg)
+	}
+	event := new(models.NodeEvent)
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
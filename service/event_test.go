@@ -0,0 +1,83 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestEventService(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	es, err := NewEventService(mockObject.conf)
+	assert.NoError(t, err)
+
+	event := &models.NodeEvent{
+		Type:      models.NodeEventDesire,
+		Namespace: "default",
+		Name:      "node01",
+		Desire:    specV1.Desire{"apps": []interface{}{}},
+		Time:      time.Unix(1000, 0).UTC(),
+	}
+	data, err := json.Marshal(event)
+	assert.NoError(t, err)
+	mockObject.pubsub.EXPECT().Publish("baetyl.node.event.default", data).Return(nil)
+	assert.NoError(t, es.PublishNodeEvent(event))
+
+	mockObject.pubsub.EXPECT().Publish("baetyl.node.event.default", data).Return(fmt.Errorf("error"))
+	assert.Error(t, es.PublishNodeEvent(event))
+
+	ch := make(chan interface{})
+	mockObject.pubsub.EXPECT().Subscribe("baetyl.node.event.default").Return(ch, nil)
+	res, err := es.SubscribeNodeEvent("default")
+	assert.NoError(t, err)
+	assert.Equal(t, ch, res)
+
+	mockObject.pubsub.EXPECT().Unsubscribe("baetyl.node.event.default", ch).Return(nil)
+	assert.NoError(t, es.UnsubscribeNodeEvent("default", ch))
+
+	// the node events are disabled without the pubsub plugin
+	conf := *mockObject.conf
+	conf.Plugin.Pubsub = "unknown"
+	es, err = NewEventService(&conf)
+	assert.NoError(t, err)
+	assert.NoError(t, es.PublishNodeEvent(event))
+	_, err = es.SubscribeNodeEvent("default")
+	assert.Error(t, err)
+	assert.NoError(t, es.UnsubscribeNodeEvent("default", ch))
+}
+
+func TestDecodeNodeEvent(t *testing.T) {
+	event := &models.NodeEvent{
+		Type:      models.NodeEventReport,
+		Namespace: "default",
+		Name:      "node01",
+		Time:      time.Unix(1000, 0).UTC(),
+	}
+	data, err := json.Marshal(event)
+	assert.NoError(t, err)
+
+	res, err := DecodeNodeEvent(data)
+	assert.NoError(t, err)
+	assert.Equal(t, event, res)
+
+	res, err = DecodeNodeEvent(string(data))
+	assert.NoError(t, err)
+	assert.Equal(t, event, res)
+
+	res, err = DecodeNodeEvent(event)
+	assert.NoError(t, err)
+	assert.Equal(t, event, res)
+
+	_, err = DecodeNodeEvent([]byte("{"))
+	assert.Error(t, err)
+
+	_, err = DecodeNodeEvent(1)
+	assert.Error(t, err)
+}
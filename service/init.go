@@ -28,7 +28,7 @@ const (
 	templateCoreAppYaml        = "baetyl-core-app.yml"
 	templateFuncConfYaml       = "baetyl-function-conf.yml"
 	templateFuncAppYaml        = "baetyl-function-app.yml"
-	templateInitDeploymentYaml = "baetyl-init-deployment.yml"
+	TemplateInitDeploymentYaml = "baetyl-init-deployment.yml"
 	TemplateBaetylInitCommand  = "baetyl-init-command"
 	TemplateKubeInitCommand    = "baetyl-kube-init-command"
 	TemplateNativeInitCommand  = "baetyl-native-init-command"
@@ -100,7 +100,7 @@ func NewInitService(config *config.CloudConfig) (InitService, error) {
 		Hooks:              map[string]interface{}{},
 		ResourceMapFunc:    map[string]GetInitResource{},
 	}
-	initService.ResourceMapFunc[templateInitDeploymentYaml] = initService.getInitDeploymentYaml
+	initService.ResourceMapFunc[TemplateInitDeploymentYaml] = initService.getInitDeploymentYaml
 	initService.ResourceMapFunc[TemplateBaetylInitCommand] = initService.GetInitCommand
 
 	return initService, nil
@@ -138,7 +138,7 @@ func (s *InitServiceImpl) getInitDeploymentYaml(ns, nodeName string, params map[
 	params["NodeCertCa"] = base64.StdEncoding.EncodeToString(cert.Data["ca.pem"])
 	params["EdgeNamespace"] = context.EdgeNamespace()
 	params["EdgeSystemNamespace"] = context.EdgeSystemNamespace()
-	return s.TemplateService.ParseTemplate(templateInitDeploymentYaml, params)
+	return s.TemplateService.ParseTemplate(TemplateInitDeploymentYaml, params)
 }
 
 func (s *InitServiceImpl) GetNodeCert(app *specV1.Application) (*specV1.Secret, error) {
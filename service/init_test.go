@@ -33,7 +33,7 @@ func TestInitService_GetResource(t *testing.T) {
 	as.TemplateService = tp
 	as.NodeService = ns
 	as.Secret = sc
-	as.ResourceMapFunc[templateInitDeploymentYaml] = as.getInitDeploymentYaml
+	as.ResourceMapFunc[TemplateInitDeploymentYaml] = as.getInitDeploymentYaml
 	desire := &v1.Desire{
 		"sysapps": []specV1.AppInfo{{
 			Name:    "baetyl-core-node01",
@@ -62,12 +62,12 @@ func TestInitService_GetResource(t *testing.T) {
 		Name:      "abc",
 	}
 	// good case : setup
-	tp.EXPECT().ParseTemplate(templateInitDeploymentYaml, gomock.Any()).Return([]byte("init"), nil).Times(1)
+	tp.EXPECT().ParseTemplate(TemplateInitDeploymentYaml, gomock.Any()).Return([]byte("init"), nil).Times(1)
 	ns.EXPECT().GetDesire("default", "node1").Return(desire, nil)
 	sApp.EXPECT().Get("default", "baetyl-core-node01", "").Return(app, nil)
 	sc.EXPECT().Get("default", "agent-conf", "").Return(sec, nil)
 
-	res, _ := as.GetResource("default", "node1", templateInitDeploymentYaml, nil)
+	res, _ := as.GetResource("default", "node1", TemplateInitDeploymentYaml, nil)
 	assert.Equal(t, res, []byte("init"))
 
 	// bad case : not found
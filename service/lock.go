@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/lock.go -package=service github.com/baetyl/baetyl-cloud/v2/service LockService
+
+// LockService leases the named locks in the database, so that the periodic tasks run by only one replica at a time
+type LockService interface {
+	// Lock acquires or renews the lock for the ttl, false is returned if the lock is held by another replica
+	Lock(name string, ttl time.Duration) (bool, error)
+	// Unlock releases the lock held by this replica
+	Unlock(name string)
+}
+
+type lockService struct {
+	storage plugin.DBStorage
+	owner   string
+}
+
+// NewLockService NewLockService
+func NewLockService(config *config.CloudConfig) (LockService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	return &lockService{
+		storage: ds.(plugin.DBStorage),
+		owner:   fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}, nil
+}
+
+// Lock the lock not renewed before it expires is regarded as abandoned by a crashed replica and taken over
+func (s *lockService) Lock(name string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	if _, err := s.storage.DeleteExpiredLock(name, now); err != nil {
+		return false, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	lock := &models.Lock{Name: name, Owner: s.owner, ExpireTime: now.Add(ttl)}
+	res, err := s.storage.RenewLock(lock)
+	if err != nil {
+		return false, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		return true, nil
+	}
+	if _, err = s.storage.CreateLock(lock); err != nil {
+		// the lock is created by another replica meanwhile
+		if held, gerr := s.storage.GetLock(name); gerr == nil && held != nil {
+			return false, nil
+		}
+		return false, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return true, nil
+}
+
+func (s *lockService) Unlock(name string) {
+	if _, err := s.storage.DeleteLock(name, s.owner); err != nil {
+		log.L().Error("failed to release the lock", log.Any("name", name), log.Error(err))
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestLockService(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ls, err := NewLockService(mockObject.conf)
+	assert.NoError(t, err)
+	owner := ls.(*lockService).owner
+
+	// acquired
+	mockObject.dbStorage.EXPECT().DeleteExpiredLock("rollout", gomock.Any()).Return(driver.RowsAffected(0), nil)
+	mockObject.dbStorage.EXPECT().RenewLock(gomock.Any()).Return(driver.RowsAffected(0), nil)
+	mockObject.dbStorage.EXPECT().CreateLock(gomock.Any()).DoAndReturn(func(lock *models.Lock) (interface{}, error) {
+		assert.Equal(t, "rollout", lock.Name)
+		assert.Equal(t, owner, lock.Owner)
+		assert.True(t, lock.ExpireTime.After(time.Now().Add(time.Minute-time.Second)))
+		return driver.RowsAffected(1), nil
+	})
+	ok, err := ls.Lock("rollout", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// renewed
+	mockObject.dbStorage.EXPECT().DeleteExpiredLock("rollout", gomock.Any()).Return(driver.RowsAffected(0), nil)
+	mockObject.dbStorage.EXPECT().RenewLock(gomock.Any()).Return(driver.RowsAffected(1), nil)
+	ok, err = ls.Lock("rollout", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// held by another replica
+	mockObject.dbStorage.EXPECT().DeleteExpiredLock("rollout", gomock.Any()).Return(driver.RowsAffected(0), nil)
+	mockObject.dbStorage.EXPECT().RenewLock(gomock.Any()).Return(driver.RowsAffected(0), nil)
+	mockObject.dbStorage.EXPECT().CreateLock(gomock.Any()).Return(nil, fmt.Errorf("duplicate"))
+	mockObject.dbStorage.EXPECT().GetLock("rollout").Return(&models.Lock{Name: "rollout", Owner: "other"}, nil)
+	ok, err = ls.Lock("rollout", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// database error
+	mockObject.dbStorage.EXPECT().DeleteExpiredLock("rollout", gomock.Any()).Return(driver.RowsAffected(0), nil)
+	mockObject.dbStorage.EXPECT().RenewLock(gomock.Any()).Return(driver.RowsAffected(0), nil)
+	mockObject.dbStorage.EXPECT().CreateLock(gomock.Any()).Return(nil, fmt.Errorf("error"))
+	mockObject.dbStorage.EXPECT().GetLock("rollout").Return(nil, nil)
+	_, err = ls.Lock("rollout", time.Minute)
+	assert.Error(t, err)
+	mockObject.dbStorage.EXPECT().DeleteExpiredLock("rollout", gomock.Any()).Return(nil, fmt.Errorf("error"))
+	_, err = ls.Lock("rollout", time.Minute)
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().DeleteLock("rollout", owner).Return(driver.RowsAffected(1), nil)
+	ls.Unlock("rollout")
+}
@@ -16,6 +16,7 @@ type NamespaceService interface {
 	Get(namespace string) (*models.Namespace, error)
 	Create(namespace *models.Namespace) (*models.Namespace, error)
 	Delete(namespace *models.Namespace) error
+	List() ([]models.Namespace, error)
 }
 
 type namespaceService struct {
@@ -52,3 +53,8 @@ func (s *namespaceService) Create(namespace *models.Namespace) (*models.Namespac
 func (s *namespaceService) Delete(namespace *models.Namespace) error {
 	return s.storage.DeleteNamespace(namespace)
 }
+
+// List list all namespaces
+func (s *namespaceService) List() ([]models.Namespace, error) {
+	return s.storage.ListNamespace()
+}
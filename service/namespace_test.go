@@ -55,3 +55,16 @@ func TestNamespaceService_Delete(t *testing.T) {
 	err = cs.Delete(ns)
 	assert.NoError(t, err)
 }
+
+func TestNamespaceService_List(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	list := []models.Namespace{{Name: "default"}, {Name: "test"}}
+	mockObject.modelStorage.EXPECT().ListNamespace().Return(list, nil)
+	cs, err := NewNamespaceService(mockObject.conf)
+	assert.NoError(t, err)
+	res, err := cs.List()
+	assert.NoError(t, err)
+	assert.Equal(t, list, res)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"sort"
 	"strings"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/metrics"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
@@ -24,20 +26,35 @@ type NodeService interface {
 	Delete(namespace, name string) error
 
 	UpdateReport(namespace, name string, report specV1.Report) (*models.Shadow, error)
-	UpdateDesire(namespace, name string, desire specV1.Desire) (*models.Shadow, error)
+	UpdateDesire(namespace, name string, desire specV1.Desire, trigger string) (*models.Shadow, error)
 
 	GetDesire(namespace, name string) (*specV1.Desire, error)
 
-	UpdateNodeAppVersion(namespace string, app *specV1.Application) ([]string, error)
+	UpdateNodeAppVersion(namespace string, app *specV1.Application, trigger string) ([]string, error)
 	DeleteNodeAppVersion(namespace string, app *specV1.Application) ([]string, error)
+
+	ListDeployHistory(namespace, name string, filter *models.NodeDeployFilter) (*models.ListView, error)
 }
 
 type nodeService struct {
 	storage      plugin.ModelStorage
+	dbStorage    plugin.DBStorage
 	indexService IndexService
+	eventService EventService
 	shadow       plugin.Shadow
 }
 
+var shadowUpdateDuration = metrics.NewHistogramVec("baetyl_cloud_shadow_update_duration_seconds",
+	"The latency of updating the reported or desired status of node shadows.", metrics.DefBuckets, "type")
+
+func init() {
+	metrics.MustRegister(shadowUpdateDuration)
+}
+
+func observeShadowUpdate(typ string, start time.Time) {
+	shadowUpdateDuration.WithLabelValues(typ).Observe(time.Since(start).Seconds())
+}
+
 // NewNodeService NewNodeService
 func NewNodeService(config *config.CloudConfig) (NodeService, error) {
 	ms, err := plugin.GetPlugin(config.Plugin.ModelStorage)
@@ -51,14 +68,26 @@ func NewNodeService(config *config.CloudConfig) (NodeService, error) {
 		return nil, err
 	}
 
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+
 	is, err := NewIndexService(config)
 	if err != nil {
 		return nil, err
 	}
 
+	es, err := NewEventService(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &nodeService{
 		storage:      ms.(plugin.ModelStorage),
+		dbStorage:    ds.(plugin.DBStorage),
 		indexService: is,
+		eventService: es,
 		shadow:       shadow.(plugin.Shadow),
 	}, nil
 }
@@ -167,11 +196,20 @@ func (n *nodeService) Delete(namespace, name string) error {
 			log.Any("name", name),
 			log.Any("operation", "delete"))
 	}
+
+	if _, err := n.dbStorage.DeleteNodeDeployHistory(namespace, name); err != nil {
+		common.LogDirtyData(err,
+			log.Any("type", "node deploy history"),
+			log.Any("namespace", namespace),
+			log.Any("name", name),
+			log.Any("operation", "delete"))
+	}
 	return nil
 }
 
 // UpdateReport Update Report
 func (n *nodeService) UpdateReport(namespace, name string, report specV1.Report) (*models.Shadow, error) {
+	defer observeShadowUpdate(models.NodeEventReport, time.Now())
 	shadow, err := n.shadow.Get(namespace, name)
 	if err != nil {
 		return nil, err
@@ -201,17 +239,25 @@ func (n *nodeService) UpdateReport(namespace, name string, report specV1.Report)
 	return n.shadow.UpdateReport(shadow)
 }
 
-// UpdateDesire Update Desire
-func (n *nodeService) UpdateDesire(namespace, name string, desire specV1.Desire) (*models.Shadow, error) {
+// UpdateDesire Update Desire, the changes of app versions are recorded as the deploy history with the trigger
+func (n *nodeService) UpdateDesire(namespace, name string, desire specV1.Desire, trigger string) (*models.Shadow, error) {
+	defer observeShadowUpdate(models.NodeEventDesire, time.Now())
 	shadow, err := n.shadow.Get(namespace, name)
 	if err != nil {
 		return nil, err
 	}
 
 	if shadow == nil {
-		return n.createShadow(namespace, name, desire, nil)
+		res, err := n.createShadow(namespace, name, desire, nil)
+		if err != nil {
+			return nil, err
+		}
+		n.recordDeployHistory(namespace, name, nil, desire, trigger)
+		n.publishDesire(namespace, name, desire)
+		return res, nil
 	}
 
+	oldVersions := appVersions(shadow.Desire)
 	if shadow.Desire == nil {
 		shadow.Desire = desire
 	} else {
@@ -221,7 +267,105 @@ func (n *nodeService) UpdateDesire(namespace, name string, desire specV1.Desire)
 		}
 	}
 
-	return n.shadow.UpdateDesire(shadow)
+	res, err := n.shadow.UpdateDesire(shadow)
+	if err != nil {
+		return nil, err
+	}
+	n.recordDeployHistory(namespace, name, oldVersions, shadow.Desire, trigger)
+	n.publishDesire(namespace, name, shadow.Desire)
+	return res, nil
+}
+
+// publishDesire notify the event stream of the desire change, the failure is only logged since the desire is already saved
+func (n *nodeService) publishDesire(namespace, name string, desire specV1.Desire) {
+	event := &models.NodeEvent{
+		Type:      models.NodeEventDesire,
+		Namespace: namespace,
+		Name:      name,
+		Desire:    desire,
+		Time:      time.Now().UTC(),
+	}
+	if err := n.eventService.PublishNodeEvent(event); err != nil {
+		log.L().Warn("failed to publish node desire event",
+			log.Any(common.KeyContextNamespace, namespace),
+			log.Any("name", name),
+			log.Error(err))
+	}
+}
+
+// ListDeployHistory list the deploy history of the node, the latest first
+func (n *nodeService) ListDeployHistory(namespace, name string, filter *models.NodeDeployFilter) (*models.ListView, error) {
+	histories, err := n.dbStorage.ListNodeDeployHistory(namespace, name, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := n.dbStorage.CountNodeDeployHistory(namespace, name, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if histories == nil {
+		histories = []models.NodeDeployHistory{}
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    histories,
+	}, nil
+}
+
+// recordDeployHistory records the app versions changed in the desire, the failure is only logged
+func (n *nodeService) recordDeployHistory(namespace, name string, oldVersions map[string]string, desire specV1.Desire, trigger string) {
+	newVersions := appVersions(desire)
+	now := time.Now().UTC()
+	var histories []models.NodeDeployHistory
+	add := func(app, oldVersion, newVersion string) {
+		histories = append(histories, models.NodeDeployHistory{
+			Namespace:  namespace,
+			NodeName:   name,
+			AppName:    app,
+			OldVersion: oldVersion,
+			NewVersion: newVersion,
+			Trigger:    trigger,
+			CreateTime: now,
+		})
+	}
+	for app, version := range newVersions {
+		if oldVersions[app] != version {
+			add(app, oldVersions[app], version)
+		}
+	}
+	for app, version := range oldVersions {
+		if _, ok := newVersions[app]; !ok {
+			add(app, version, "")
+		}
+	}
+	if len(histories) == 0 {
+		return
+	}
+	sort.Slice(histories, func(i, j int) bool {
+		return histories[i].AppName < histories[j].AppName
+	})
+	if _, err := n.dbStorage.CreateNodeDeployHistory(histories); err != nil {
+		common.LogDirtyData(err,
+			log.Any("type", "node deploy history"),
+			log.Any("namespace", namespace),
+			log.Any("name", name),
+			log.Any("operation", "create"))
+	}
+}
+
+func appVersions(desire specV1.Desire) map[string]string {
+	versions := map[string]string{}
+	if desire == nil {
+		return versions
+	}
+	for _, isSys := range []bool{true, false} {
+		for _, app := range desire.AppInfos(isSys) {
+			versions[app.Name] = app.Version
+		}
+	}
+	return versions
 }
 
 func (n *nodeService) GetDesire(namespace, name string) (*specV1.Desire, error) {
@@ -244,10 +388,14 @@ func (n *nodeService) updateNodeAndAppIndex(namespace string, node *specV1.Node)
 	}
 
 	desire, appNames := n.rematchApplicationsForNode(apps, node.Labels)
+	if err = n.keepRolloutVersions(namespace, node.Name, desire); err != nil {
+		log.L().Error("keep the versions of the apps rolled out failed", log.Error(err))
+		return err
+	}
 
 	node.Desire = desire
 
-	if _, err = n.UpdateDesire(node.Namespace, node.Name, desire); err != nil {
+	if _, err = n.UpdateDesire(node.Namespace, node.Name, desire, models.DeployTriggerLabel); err != nil {
 		log.L().Error("update node desired node failed", log.Error(err))
 		return err
 	}
@@ -259,6 +407,49 @@ func (n *nodeService) updateNodeAndAppIndex(namespace string, node *specV1.Node)
 	return nil
 }
 
+// keepRolloutVersions keeps the versions in the current desire of the node for the apps being rolled out,
+// until the node is updated by the waves of the rollouts, the nodes newly matched get the current versions
+func (n *nodeService) keepRolloutVersions(namespace, name string, desire specV1.Desire) error {
+	shadow, err := n.shadow.Get(namespace, name)
+	if err != nil {
+		return err
+	}
+	if shadow == nil || shadow.Desire == nil {
+		return nil
+	}
+	rollouts, err := n.dbStorage.ListNamespaceRolloutByStatus(namespace, []string{models.RolloutRunning, models.RolloutPaused})
+	if err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	pending := map[string]bool{}
+	for _, r := range rollouts {
+		pending[r.AppName] = true
+		deployed := r.Progress.Deployed
+		if deployed > len(r.Progress.Nodes) {
+			deployed = len(r.Progress.Nodes)
+		}
+		for _, node := range r.Progress.Nodes[:deployed] {
+			if node == name {
+				pending[r.AppName] = false
+			}
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	versions := appVersions(shadow.Desire)
+	for _, isSys := range []bool{true, false} {
+		infos := desire.AppInfos(isSys)
+		for i := range infos {
+			if v, ok := versions[infos[i].Name]; ok && pending[infos[i].Name] {
+				infos[i].Version = v
+			}
+		}
+		desire.SetAppInfos(isSys, infos)
+	}
+	return nil
+}
+
 // rematchApplicationsForNode rematch applications for node
 //   - param apps: all applications for the namespace
 //   - param nodeLabels: the labels of node
@@ -298,7 +489,7 @@ func (n *nodeService) rematchApplicationsForNode(apps *models.ApplicationList, l
 }
 
 // UpdateNodeAppVersion update the node desire's appVersion for app changed
-func (n *nodeService) UpdateNodeAppVersion(namespace string, app *specV1.Application) ([]string, error) {
+func (n *nodeService) UpdateNodeAppVersion(namespace string, app *specV1.Application, trigger string) ([]string, error) {
 	if app.Selector == "" {
 		return nil, nil
 	}
@@ -314,7 +505,7 @@ func (n *nodeService) UpdateNodeAppVersion(namespace string, app *specV1.Applica
 		node := &nodeList.Items[idx]
 		nodes = append(nodes, node.Name)
 		refreshNodeDesireByApp(node, app)
-		_, err := n.UpdateDesire(namespace, node.Name, node.Desire)
+		_, err := n.UpdateDesire(namespace, node.Name, node.Desire, trigger)
 		if err != nil {
 			return nil, err
 		}
@@ -365,7 +556,7 @@ func (n *nodeService) DeleteNodeAppVersion(namespace string, app *specV1.Applica
 			}
 			node.Desire.SetAppInfos(app.System, appInfos)
 
-			_, err = n.UpdateDesire(namespace, node.Name, node.Desire)
+			_, err = n.UpdateDesire(namespace, node.Name, node.Desire, models.DeployTriggerApp)
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/node_group.go -package=service github.com/baetyl/baetyl-cloud/v2/service NodeGroupService
+
+// the max length of label keys
+const labelKeyLength = 63
+
+// NodeGroupService manages the static and dynamic groups of nodes.
+// The members of the static groups are labeled with the labels of the groups,
+// so the applications of the nodes are rematched once the members or their labels are changed
+type NodeGroupService interface {
+	Get(namespace, name string) (*models.NodeGroup, error)
+	List(namespace string, filter *models.Filter) (*models.ListView, error)
+	Create(namespace string, group *models.NodeGroup) (*models.NodeGroup, error)
+	// Update updates the description and the selector of the dynamic group, the members are ignored
+	Update(namespace string, group *models.NodeGroup) (*models.NodeGroup, error)
+	Delete(namespace, name string) error
+	ListNodes(namespace, name string) (*models.NodeList, error)
+	UpdateMembers(namespace, name string, members *models.NodeGroupMembers) (*models.NodeGroup, error)
+	// UpdateLabels updates the labels of all members, returns the names of the updated nodes
+	UpdateLabels(namespace, name string, labels *models.NodeGroupLabels) ([]string, error)
+	// AssignApp sets the selector of the application to match the members of the group
+	AssignApp(namespace, name, appName string) (*specV1.Application, error)
+}
+
+type nodeGroupService struct {
+	storage      plugin.DBStorage
+	modelStorage plugin.ModelStorage
+	node         NodeService
+	app          ApplicationService
+	indexService IndexService
+}
+
+// NewNodeGroupService NewNodeGroupService
+func NewNodeGroupService(config *config.CloudConfig) (NodeGroupService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := plugin.GetPlugin(config.Plugin.ModelStorage)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := NewNodeService(config)
+	if err != nil {
+		return nil, err
+	}
+	as, err := NewApplicationService(config)
+	if err != nil {
+		return nil, err
+	}
+	is, err := NewIndexService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &nodeGroupService{
+		storage:      ds.(plugin.DBStorage),
+		modelStorage: ms.(plugin.ModelStorage),
+		node:         ns,
+		app:          as,
+		indexService: is,
+	}, nil
+}
+
+func (s *nodeGroupService) Get(namespace, name string) (*models.NodeGroup, error) {
+	group, err := s.storage.GetNodeGroup(namespace, name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if group == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "group"),
+			common.Field("name", name), common.Field("namespace", namespace))
+	}
+	return group, nil
+}
+
+func (s *nodeGroupService) List(namespace string, filter *models.Filter) (*models.ListView, error) {
+	groups, err := s.storage.ListNodeGroup(namespace, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountNodeGroup(namespace, filter.Name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    groups,
+	}, nil
+}
+
+// Create creates the group, the nodes of the static group are labeled as its members
+func (s *nodeGroupService) Create(namespace string, group *models.NodeGroup) (*models.NodeGroup, error) {
+	group.Namespace = namespace
+	if len(group.MemberLabel()) > labelKeyLength {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the name of group is too long"))
+	}
+	if group.IsDynamic() && len(group.Nodes) > 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the members of dynamic group are matched by its selector"))
+	}
+	if err := s.checkSelector(group); err != nil {
+		return nil, err
+	}
+	old, err := s.storage.GetNodeGroup(namespace, group.Name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if old != nil {
+		return nil, common.Error(common.ErrResourceConflict, common.Field("type", "group"), common.Field("name", group.Name))
+	}
+	for _, n := range group.Nodes {
+		if _, err = s.node.Get(namespace, n); err != nil {
+			return nil, err
+		}
+	}
+	if _, err = s.storage.CreateNodeGroup(group); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if len(group.Nodes) > 0 {
+		return s.UpdateMembers(namespace, group.Name, &models.NodeGroupMembers{Add: group.Nodes})
+	}
+	return s.Get(namespace, group.Name)
+}
+
+func (s *nodeGroupService) Update(namespace string, group *models.NodeGroup) (*models.NodeGroup, error) {
+	old, err := s.Get(namespace, group.Name)
+	if err != nil {
+		return nil, err
+	}
+	if old.IsDynamic() != group.IsDynamic() {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the type of group cannot be changed"))
+	}
+	if err = s.checkSelector(group); err != nil {
+		return nil, err
+	}
+	group.Namespace = namespace
+	if _, err = s.storage.UpdateNodeGroup(group); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	// the applications assigned to the dynamic group follow its selector
+	if old.Selector != group.Selector {
+		apps, err := s.listApps(namespace, group.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range apps {
+			if _, err = s.AssignApp(namespace, group.Name, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s.Get(namespace, group.Name)
+}
+
+// Delete deletes the group which is not assigned to any application, the labels of the members are removed
+func (s *nodeGroupService) Delete(namespace, name string) error {
+	group, err := s.Get(namespace, name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil
+		}
+		return err
+	}
+	apps, err := s.listApps(namespace, name)
+	if err != nil {
+		return err
+	}
+	if len(apps) > 0 {
+		return common.Error(common.ErrResourceHasBeenUsed, common.Field("type", "group"), common.Field("name", name))
+	}
+	if !group.IsDynamic() {
+		nodes, err := s.ListNodes(namespace, name)
+		if err != nil {
+			return err
+		}
+		members := &models.NodeGroupMembers{}
+		for _, n := range nodes.Items {
+			members.Remove = append(members.Remove, n.Name)
+		}
+		if _, err = s.UpdateMembers(namespace, name, members); err != nil {
+			return err
+		}
+	}
+	if _, err = s.storage.DeleteNodeGroup(namespace, name); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+func (s *nodeGroupService) ListNodes(namespace, name string) (*models.NodeList, error) {
+	group, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.node.List(namespace, &models.ListOptions{LabelSelector: group.MemberSelector()})
+}
+
+// UpdateMembers adds the nodes to or removes them from the static group by labeling them
+func (s *nodeGroupService) UpdateMembers(namespace, name string, members *models.NodeGroupMembers) (*models.NodeGroup, error) {
+	group, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if group.IsDynamic() {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the members of dynamic group are matched by its selector"))
+	}
+	key := group.MemberLabel()
+	for _, n := range members.Add {
+		if err = s.updateNode(namespace, n, func(labels map[string]string) bool {
+			if labels[key] == "true" {
+				return false
+			}
+			labels[key] = "true"
+			return true
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range members.Remove {
+		if err = s.updateNode(namespace, n, func(labels map[string]string) bool {
+			if _, ok := labels[key]; !ok {
+				return false
+			}
+			delete(labels, key)
+			return true
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(namespace, name)
+}
+
+func (s *nodeGroupService) UpdateLabels(namespace, name string, labels *models.NodeGroupLabels) ([]string, error) {
+	for k := range labels.Add {
+		if strings.Contains(k, "baetyl") {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the label ("+k+") is reserved"))
+		}
+	}
+	for _, k := range labels.Remove {
+		if strings.Contains(k, "baetyl") {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the label ("+k+") is reserved"))
+		}
+	}
+	nodes, err := s.ListNodes(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	updated := []string{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		changed := false
+		for k, v := range labels.Add {
+			if old, ok := node.Labels[k]; !ok || old != v {
+				node.Labels[k] = v
+				changed = true
+			}
+		}
+		for _, k := range labels.Remove {
+			if _, ok := node.Labels[k]; ok {
+				delete(node.Labels, k)
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if _, err = s.node.Update(namespace, node); err != nil {
+			return nil, err
+		}
+		updated = append(updated, node.Name)
+	}
+	return updated, nil
+}
+
+func (s *nodeGroupService) AssignApp(namespace, name, appName string) (*specV1.Application, error) {
+	group, err := s.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	oldApp, err := s.app.Get(namespace, appName, "")
+	if err != nil {
+		return nil, err
+	}
+	if oldApp.System {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "system application cannot be assigned to group"))
+	}
+	app := *oldApp
+	app.Labels = map[string]string{}
+	for k, v := range oldApp.Labels {
+		app.Labels[k] = v
+	}
+	app.Labels[common.LabelNodeGroup] = group.Name
+	app.Selector = group.MemberSelector()
+	res, err := s.app.Update(namespace, &app)
+	if err != nil {
+		return nil, err
+	}
+	if oldApp.Selector != "" && oldApp.Selector != res.Selector {
+		if _, err = s.node.DeleteNodeAppVersion(namespace, oldApp); err != nil {
+			return nil, err
+		}
+	}
+	nodes, err := s.node.UpdateNodeAppVersion(namespace, res, models.DeployTriggerApp)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.indexService.RefreshNodesIndexByApp(namespace, res.Name, nodes); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *nodeGroupService) checkSelector(group *models.NodeGroup) error {
+	if !group.IsDynamic() {
+		return nil
+	}
+	if _, err := s.modelStorage.IsLabelMatch(group.Selector, map[string]string{}); err != nil {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+// listApps returns the names of the applications assigned to the group
+func (s *nodeGroupService) listApps(namespace, name string) ([]string, error) {
+	apps, err := s.app.List(namespace, &models.ListOptions{LabelSelector: common.LabelNodeGroup + "=" + name})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, app := range apps.Items {
+		names = append(names, app.Name)
+	}
+	return names, nil
+}
+
+// updateNode updates the node if its labels are changed by the function
+func (s *nodeGroupService) updateNode(namespace, name string, change func(labels map[string]string) bool) error {
+	node, err := s.node.Get(namespace, name)
+	if err != nil {
+		return err
+	}
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	if !change(node.Labels) {
+		return nil
+	}
+	_, err = s.node.Update(namespace, node)
+	return err
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type nodeGroupMocks struct {
+	*MockServices
+	app   *ms.MockApplicationService
+	node  *ms.MockNodeService
+	index *ms.MockIndexService
+}
+
+func initNodeGroupService(t *testing.T) (*nodeGroupService, *nodeGroupMocks) {
+	mockObject := InitMockEnvironment(t)
+	mocks := &nodeGroupMocks{
+		MockServices: mockObject,
+		app:          ms.NewMockApplicationService(mockObject.ctl),
+		node:         ms.NewMockNodeService(mockObject.ctl),
+		index:        ms.NewMockIndexService(mockObject.ctl),
+	}
+	return &nodeGroupService{
+		storage:      mockObject.dbStorage,
+		modelStorage: mockObject.modelStorage,
+		node:         mocks.node,
+		app:          mocks.app,
+		indexService: mocks.index,
+	}, mocks
+}
+
+func TestNodeGroupServiceCreate(t *testing.T) {
+	s, mocks := initNodeGroupService(t)
+	defer mocks.Close()
+
+	// invalid groups
+	_, err := s.Create("default", &models.NodeGroup{Name: "a012345678901234567890123456789012345678901234567890"})
+	assert.Error(t, err)
+	_, err = s.Create("default", &models.NodeGroup{Name: "g1", Selector: "a=a", Nodes: []string{"n1"}})
+	assert.Error(t, err)
+	mocks.modelStorage.EXPECT().IsLabelMatch("a=", map[string]string{}).Return(false, fmt.Errorf("error"))
+	_, err = s.Create("default", &models.NodeGroup{Name: "g1", Selector: "a="})
+	assert.Error(t, err)
+
+	// conflict
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(&models.NodeGroup{Name: "g1"}, nil)
+	_, err = s.Create("default", &models.NodeGroup{Name: "g1"})
+	assert.Error(t, err)
+
+	// the member is not found
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(nil, nil)
+	mocks.node.EXPECT().Get("default", "n2").Return(nil, common.Error(common.ErrResourceNotFound))
+	_, err = s.Create("default", &models.NodeGroup{Name: "g1", Nodes: []string{"n2"}})
+	assert.Error(t, err)
+
+	// static group with members
+	group := &models.NodeGroup{Namespace: "default", Name: "g1"}
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(nil, nil)
+	mocks.node.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1"}, nil)
+	mocks.dbStorage.EXPECT().CreateNodeGroup(gomock.Any()).Return(nil, nil)
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(group, nil).Times(2)
+	mocks.node.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1", Labels: map[string]string{"a": "a"}}, nil)
+	mocks.node.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, node *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, map[string]string{"a": "a", "baetyl-group-g1": "true"}, node.Labels)
+		return node, nil
+	})
+	res, err := s.Create("default", &models.NodeGroup{Name: "g1", Nodes: []string{"n1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "baetyl-group-g1=true", res.MemberSelector())
+
+	// dynamic group
+	dynamic := &models.NodeGroup{Namespace: "default", Name: "g2", Selector: "a=a"}
+	mocks.modelStorage.EXPECT().IsLabelMatch("a=a", map[string]string{}).Return(false, nil)
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g2").Return(nil, nil)
+	mocks.dbStorage.EXPECT().CreateNodeGroup(gomock.Any()).Return(nil, nil)
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g2").Return(dynamic, nil)
+	res, err = s.Create("default", &models.NodeGroup{Name: "g2", Selector: "a=a"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a=a", res.MemberSelector())
+}
+
+func TestNodeGroupServiceMembers(t *testing.T) {
+	s, mocks := initNodeGroupService(t)
+	defer mocks.Close()
+
+	static := &models.NodeGroup{Namespace: "default", Name: "g1"}
+	dynamic := &models.NodeGroup{Namespace: "default", Name: "g2", Selector: "a=a"}
+
+	// the members of dynamic group are not changed
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g2").Return(dynamic, nil)
+	_, err := s.UpdateMembers("default", "g2", &models.NodeGroupMembers{Add: []string{"n1"}})
+	assert.Error(t, err)
+
+	// n1 is already a member, n2 is removed, n3 is not a member
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(static, nil).Times(2)
+	mocks.node.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1", Labels: map[string]string{"baetyl-group-g1": "true"}}, nil)
+	mocks.node.EXPECT().Get("default", "n2").Return(&specV1.Node{Name: "n2", Labels: map[string]string{"baetyl-group-g1": "true"}}, nil)
+	mocks.node.EXPECT().Get("default", "n3").Return(&specV1.Node{Name: "n3"}, nil)
+	mocks.node.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, node *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "n2", node.Name)
+		assert.Empty(t, node.Labels)
+		return node, nil
+	})
+	_, err = s.UpdateMembers("default", "g1", &models.NodeGroupMembers{Add: []string{"n1"}, Remove: []string{"n2", "n3"}})
+	assert.NoError(t, err)
+
+	// labels
+	_, err = s.UpdateLabels("default", "g1", &models.NodeGroupLabels{Remove: []string{"baetyl-node-name"}})
+	assert.Error(t, err)
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(static, nil)
+	mocks.node.EXPECT().List("default", &models.ListOptions{LabelSelector: "baetyl-group-g1=true"}).Return(&models.NodeList{
+		Items: []specV1.Node{
+			{Name: "n1", Labels: map[string]string{"a": "a"}},
+			{Name: "n2", Labels: map[string]string{"a": "b"}},
+		},
+	}, nil)
+	mocks.node.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, node *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "n2", node.Name)
+		assert.Equal(t, map[string]string{"a": "a"}, node.Labels)
+		return node, nil
+	})
+	nodes, err := s.UpdateLabels("default", "g1", &models.NodeGroupLabels{Add: map[string]string{"a": "a"}, Remove: []string{"b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n2"}, nodes)
+}
+
+func TestNodeGroupServiceApps(t *testing.T) {
+	s, mocks := initNodeGroupService(t)
+	defer mocks.Close()
+
+	static := &models.NodeGroup{Namespace: "default", Name: "g1"}
+	dynamic := &models.NodeGroup{Namespace: "default", Name: "g2", Selector: "a=a"}
+
+	// assign
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(static, nil)
+	mocks.app.EXPECT().Get("default", "app", "").Return(&specV1.Application{Name: "app", Selector: "b=b"}, nil)
+	mocks.app.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "baetyl-group-g1=true", app.Selector)
+		assert.Equal(t, "g1", app.Labels[common.LabelNodeGroup])
+		return app, nil
+	})
+	mocks.node.EXPECT().DeleteNodeAppVersion("default", &specV1.Application{Name: "app", Selector: "b=b"}).Return(nil, nil)
+	mocks.node.EXPECT().UpdateNodeAppVersion("default", gomock.Any(), models.DeployTriggerApp).Return([]string{"n1"}, nil)
+	mocks.index.EXPECT().RefreshNodesIndexByApp("default", "app", []string{"n1"}).Return(nil)
+	app, err := s.AssignApp("default", "g1", "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "baetyl-group-g1=true", app.Selector)
+
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(static, nil)
+	mocks.app.EXPECT().Get("default", "core", "").Return(&specV1.Application{Name: "core", System: true}, nil)
+	_, err = s.AssignApp("default", "g1", "core")
+	assert.Error(t, err)
+
+	// the assigned apps follow the selector of the dynamic group
+	updated := &models.NodeGroup{Namespace: "default", Name: "g2", Selector: "a in (a, b)"}
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g2").Return(dynamic, nil)
+	mocks.modelStorage.EXPECT().IsLabelMatch("a in (a, b)", map[string]string{}).Return(false, nil)
+	mocks.dbStorage.EXPECT().UpdateNodeGroup(updated).Return(nil, nil)
+	mocks.app.EXPECT().List("default", &models.ListOptions{LabelSelector: "baetyl-node-group=g2"}).Return(&models.ApplicationList{
+		Items: []models.AppItem{{Name: "app"}},
+	}, nil)
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g2").Return(updated, nil).Times(2)
+	mocks.app.EXPECT().Get("default", "app", "").Return(&specV1.Application{Name: "app", Selector: "a=a"}, nil)
+	mocks.app.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "a in (a, b)", app.Selector)
+		return app, nil
+	})
+	mocks.node.EXPECT().DeleteNodeAppVersion("default", gomock.Any()).Return(nil, nil)
+	mocks.node.EXPECT().UpdateNodeAppVersion("default", gomock.Any(), models.DeployTriggerApp).Return([]string{"n1", "n2"}, nil)
+	mocks.index.EXPECT().RefreshNodesIndexByApp("default", "app", []string{"n1", "n2"}).Return(nil)
+	_, err = s.Update("default", &models.NodeGroup{Name: "g2", Selector: "a in (a, b)"})
+	assert.NoError(t, err)
+
+	// the type cannot be changed
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g2").Return(dynamic, nil)
+	_, err = s.Update("default", &models.NodeGroup{Name: "g2"})
+	assert.Error(t, err)
+
+	// the group assigned to apps cannot be deleted
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g2").Return(dynamic, nil)
+	mocks.app.EXPECT().List("default", &models.ListOptions{LabelSelector: "baetyl-node-group=g2"}).Return(&models.ApplicationList{
+		Items: []models.AppItem{{Name: "app"}},
+	}, nil)
+	assert.Error(t, s.Delete("default", "g2"))
+
+	// the labels of the members of the static group are removed
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g1").Return(static, nil).Times(4)
+	mocks.app.EXPECT().List("default", &models.ListOptions{LabelSelector: "baetyl-node-group=g1"}).Return(&models.ApplicationList{}, nil)
+	mocks.node.EXPECT().List("default", &models.ListOptions{LabelSelector: "baetyl-group-g1=true"}).Return(&models.NodeList{
+		Items: []specV1.Node{{Name: "n1", Labels: map[string]string{"baetyl-group-g1": "true"}}},
+	}, nil)
+	mocks.node.EXPECT().Get("default", "n1").Return(&specV1.Node{Name: "n1", Labels: map[string]string{"baetyl-group-g1": "true"}}, nil)
+	mocks.node.EXPECT().Update("default", gomock.Any()).Return(nil, nil)
+	mocks.dbStorage.EXPECT().DeleteNodeGroup("default", "g1").Return(nil, nil)
+	assert.NoError(t, s.Delete("default", "g1"))
+
+	mocks.dbStorage.EXPECT().GetNodeGroup("default", "g3").Return(nil, nil)
+	assert.NoError(t, s.Delete("default", "g3"))
+}
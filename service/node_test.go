@@ -1,8 +1,10 @@
 package service
 
 import (
+	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
@@ -10,6 +12,8 @@ import (
 	"github.com/baetyl/baetyl-go/v2/spec/v1"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -86,9 +90,12 @@ func TestDefaultNodeService_List(t *testing.T) {
 	}
 
 	nsvc := nodeService{
-		storage: mockObject.modelStorage,
-		shadow:  mockObject.dbStorage,
+		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
+		shadow:       mockObject.dbStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	mockObject.modelStorage.EXPECT().ListNode(ns, s).Return(list, nil)
 	mockObject.dbStorage.EXPECT().List(ns, gomock.Any()).Return(shadowList, nil)
@@ -113,9 +120,13 @@ func TestDefaultNodeService_Delete(t *testing.T) {
 	mockIndexService := ms.NewMockIndexService(mockObject.ctl)
 	cs := nodeService{
 		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
 		indexService: mockIndexService,
 		shadow:       mockObject.dbStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockObject.dbStorage.EXPECT().DeleteNodeDeployHistory(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	node := genNodeTestCase()
 	mockObject.dbStorage.EXPECT().Delete(node.Namespace, node.Name).Return(nil).AnyTimes()
@@ -141,15 +152,19 @@ func TestDefaultNodeService_Create(t *testing.T) {
 	mockIndexService := ms.NewMockIndexService(mockObject.ctl)
 	ns := nodeService{
 		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
 		indexService: mockIndexService,
 		shadow:       mockObject.dbStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	node := genNodeTestCase()
 	shadow := genShadowTestCase()
 
 	mockObject.dbStorage.EXPECT().Create(gomock.Any()).Return(shadow, nil).AnyTimes()
 
 	mockObject.dbStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(shadow, nil).AnyTimes()
+	mockObject.dbStorage.EXPECT().ListNamespaceRolloutByStatus(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	mockObject.modelStorage.EXPECT().CreateNode(node.Namespace, node).Return(nil, fmt.Errorf("error"))
 	_, err := ns.Create(node.Namespace, node)
@@ -195,9 +210,13 @@ func TestDefaultNodeService_Update(t *testing.T) {
 	mockIndexService := ms.NewMockIndexService(mockObject.ctl)
 	ns := nodeService{
 		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
 		indexService: mockIndexService,
 		shadow:       mockObject.dbStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockObject.dbStorage.EXPECT().CreateNodeDeployHistory(gomock.Any()).Return(nil, nil).AnyTimes()
 	app := &specV1.Application{
 		Name:    "appTest",
 		Version: "1234",
@@ -212,6 +231,7 @@ func TestDefaultNodeService_Update(t *testing.T) {
 
 	mockObject.dbStorage.EXPECT().UpdateDesire(gomock.Any()).Return(shadow, nil).AnyTimes()
 	mockObject.dbStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(shadow, nil).AnyTimes()
+	mockObject.dbStorage.EXPECT().ListNamespaceRolloutByStatus(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
 	mockObject.modelStorage.EXPECT().UpdateNode(node.Namespace, node).Return(nil, fmt.Errorf("error"))
 	_, err := ns.Update(node.Namespace, node)
@@ -263,9 +283,13 @@ func TestUpdateNodeAppVersion(t *testing.T) {
 	mockIndexService := ms.NewMockIndexService(mockObject.ctl)
 	ss := nodeService{
 		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
 		indexService: mockIndexService,
 		shadow:       mockObject.dbStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockObject.dbStorage.EXPECT().CreateNodeDeployHistory(gomock.Any()).Return(nil, nil).AnyTimes()
 	app := &specV1.Application{
 		Name:    "appTest",
 		Version: "1234",
@@ -273,11 +297,11 @@ func TestUpdateNodeAppVersion(t *testing.T) {
 	node := genNodeTestCase()
 	shadow := genShadowTestCase()
 
-	_, err := ss.UpdateNodeAppVersion(node.Namespace, app)
+	_, err := ss.UpdateNodeAppVersion(node.Namespace, app, models.DeployTriggerApp)
 	assert.NoError(t, err)
 	app.Selector = "test=example"
 	mockObject.modelStorage.EXPECT().ListNode(node.Namespace, gomock.Any()).Return(nil, fmt.Errorf("error"))
-	_, err = ss.UpdateNodeAppVersion(node.Namespace, app)
+	_, err = ss.UpdateNodeAppVersion(node.Namespace, app, models.DeployTriggerApp)
 	assert.NotNil(t, err)
 
 	nodeList := &models.NodeList{
@@ -376,20 +400,20 @@ func TestUpdateNodeAppVersion(t *testing.T) {
 	mockObject.modelStorage.EXPECT().ListNode(node.Namespace, gomock.Any()).Return(nodeList, nil)
 	mockObject.modelStorage.EXPECT().UpdateNode(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	mockObject.dbStorage.EXPECT().UpdateDesire(gomock.Any()).Return(nil, nil).AnyTimes()
-	_, err = ss.UpdateNodeAppVersion(node.Namespace, app)
+	_, err = ss.UpdateNodeAppVersion(node.Namespace, app, models.DeployTriggerApp)
 	assert.NotNil(t, err)
 
 	mockObject.dbStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&shadowList.Items[2], nil).AnyTimes()
 	mockObject.dbStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(shadow, nil).AnyTimes()
 	mockObject.modelStorage.EXPECT().ListNode(node.Namespace, gomock.Any()).Return(nodeList, nil).AnyTimes()
 	mockObject.modelStorage.EXPECT().GetNode(gomock.Any(), gomock.Any()).Return(&nodeList.Items[0], nil).AnyTimes()
-	_, err = ss.UpdateNodeAppVersion(node.Namespace, app)
+	_, err = ss.UpdateNodeAppVersion(node.Namespace, app, models.DeployTriggerApp)
 	assert.NoError(t, err)
 
 	app.Labels = map[string]string{
 		common.LabelSystem: app.Name,
 	}
-	_, err = ss.UpdateNodeAppVersion(node.Namespace, app)
+	_, err = ss.UpdateNodeAppVersion(node.Namespace, app, models.DeployTriggerApp)
 	assert.NoError(t, err)
 }
 
@@ -399,9 +423,13 @@ func TestDeleteNodeAppVersion(t *testing.T) {
 	mockIndexService := ms.NewMockIndexService(mockObject.ctl)
 	ss := nodeService{
 		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
 		indexService: mockIndexService,
 		shadow:       mockObject.modelStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockObject.dbStorage.EXPECT().CreateNodeDeployHistory(gomock.Any()).Return(nil, nil).AnyTimes()
 	app := &specV1.Application{
 		Name:    "appTest",
 		Version: "1234",
@@ -542,9 +570,12 @@ func TestUpdateReport(t *testing.T) {
 	defer mockObject.Close()
 
 	ss := nodeService{
-		storage: mockObject.modelStorage,
-		shadow:  mockObject.dbStorage,
+		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
+		shadow:       mockObject.dbStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	node := &specV1.Node{
 		Name:      "node01",
@@ -581,8 +612,10 @@ func TestUpdateReport(t *testing.T) {
 	//mockObject.dbStorage.EXPECT().Create(gomock.Any()).Return(shadow, nil)
 	//mockObject.modelStorage.EXPECT().GetNode(node.Namespace, node.Name).Return(node, nil)
 	mockObject.dbStorage.EXPECT().UpdateReport(gomock.Any()).Return(shadow, nil)
+	count := shadowUpdateCount(t, models.NodeEventReport)
 	shad, err := ss.UpdateReport(node.Namespace, node.Name, report)
 	assert.NoError(t, err)
+	assert.Equal(t, count+1, shadowUpdateCount(t, models.NodeEventReport))
 	assert.Equal(t, node.Name, shad.Name)
 	assert.Equal(t, "appTest-1", shad.Report["apps"].([]specV1.AppInfo)[0].Name)
 }
@@ -737,9 +770,12 @@ func TestUpdateDesired(t *testing.T) {
 	defer mockObject.Close()
 
 	ns := nodeService{
-		storage: mockObject.modelStorage,
-		shadow:  mockObject.modelStorage,
+		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
+		shadow:       mockObject.modelStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	namespace := "test"
 	name := "node01"
@@ -755,8 +791,15 @@ func TestUpdateDesired(t *testing.T) {
 	shadow := genShadowTestCase()
 	mockObject.modelStorage.EXPECT().Get(gomock.Any(), gomock.Any()).Return(shadow, nil)
 	mockObject.modelStorage.EXPECT().UpdateDesire(gomock.Any()).Return(shadow, nil)
-
-	shd, err := ns.UpdateDesire(namespace, name, desire)
+	mockObject.dbStorage.EXPECT().CreateNodeDeployHistory(gomock.Any()).DoAndReturn(func(histories []models.NodeDeployHistory) (sql.Result, error) {
+		assert.Len(t, histories, 1)
+		assert.Equal(t, "app01", histories[0].AppName)
+		assert.Equal(t, "1245", histories[0].NewVersion)
+		assert.Equal(t, models.DeployTriggerApp, histories[0].Trigger)
+		return nil, nil
+	}).Times(2)
+
+	shd, err := ns.UpdateDesire(namespace, name, desire, models.DeployTriggerApp)
 	assert.NoError(t, err)
 	apps := shd.Desire[common.DesiredApplications].([]specV1.AppInfo)
 	assert.Equal(t, 1, len(apps))
@@ -766,7 +809,7 @@ func TestUpdateDesired(t *testing.T) {
 	mockObject.modelStorage.EXPECT().Create(gomock.Any()).Return(shadow, nil)
 	//mockObject.modelStorage.EXPECT().UpdateDesire(gomock.Any()).Return(shadow, nil)
 
-	shd, err = ns.UpdateDesire(namespace, name, desire)
+	shd, err = ns.UpdateDesire(namespace, name, desire, models.DeployTriggerApp)
 	assert.NoError(t, err)
 	apps = shd.Desire[common.DesiredApplications].([]specV1.AppInfo)
 	assert.Equal(t, 1, len(apps))
@@ -778,9 +821,12 @@ func TestRematchApplicationForNode(t *testing.T) {
 	defer mockObject.Close()
 
 	ns := nodeService{
-		storage: mockObject.modelStorage,
-		shadow:  mockObject.dbStorage,
+		storage:      mockObject.modelStorage,
+		dbStorage:    mockObject.dbStorage,
+		shadow:       mockObject.dbStorage,
+		eventService: &eventService{pubsub: mockObject.pubsub},
 	}
+	mockObject.pubsub.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	apps := &models.ApplicationList{
 		Items: []models.AppItem{
@@ -830,3 +876,101 @@ func TestRematchApplicationForNode(t *testing.T) {
 	assert.Equal(t, names, appNames)
 
 }
+
+func TestKeepRolloutVersions(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := nodeService{
+		dbStorage: mockObject.dbStorage,
+		shadow:    mockObject.dbStorage,
+	}
+	mockObject.dbStorage.EXPECT().Get("default", "node01").Return(&models.Shadow{Desire: specV1.Desire{
+		common.DesiredApplications: []specV1.AppInfo{{Name: "app01", Version: "1"}, {Name: "app02", Version: "1"}},
+	}}, nil)
+	mockObject.dbStorage.EXPECT().ListNamespaceRolloutByStatus("default", []string{models.RolloutRunning, models.RolloutPaused}).Return([]models.Rollout{
+		{Namespace: "default", AppName: "app01", Progress: models.RolloutProgress{Deployed: 1, Nodes: []string{"node02", "node01"}}},
+		{Namespace: "default", AppName: "app03", Progress: models.RolloutProgress{Nodes: []string{"node01"}}},
+		// the deployed count exceeding the nodes is clamped
+		{Namespace: "default", AppName: "app02", Status: models.RolloutPaused, Progress: models.RolloutProgress{Deployed: 3, Nodes: []string{"node01"}}},
+	}, nil)
+	desire := specV1.Desire{
+		common.DesiredApplications: []specV1.AppInfo{{Name: "app01", Version: "2"}, {Name: "app02", Version: "2"}, {Name: "app03", Version: "2"}},
+	}
+	assert.NoError(t, ns.keepRolloutVersions("default", "node01", desire))
+	// the node not deployed by the rollout keeps the old version, the node newly matched gets the current version
+	assert.Equal(t, []specV1.AppInfo{{Name: "app01", Version: "1"}, {Name: "app02", Version: "2"}, {Name: "app03", Version: "2"}}, desire.AppInfos(false))
+
+	mockObject.dbStorage.EXPECT().Get("default", "node01").Return(&models.Shadow{Desire: specV1.Desire{}}, nil)
+	mockObject.dbStorage.EXPECT().ListNamespaceRolloutByStatus("default", gomock.Any()).Return(nil, fmt.Errorf("error"))
+	assert.Error(t, ns.keepRolloutVersions("default", "node01", desire))
+}
+
+func TestRecordDeployHistory(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := nodeService{
+		dbStorage: mockObject.dbStorage,
+	}
+
+	old := map[string]string{"app01": "1", "app02": "1", "app03": "1"}
+	desire := specV1.Desire{
+		common.DesiredSysApplications: []specV1.AppInfo{{Name: "core", Version: "1"}},
+		common.DesiredApplications: []specV1.AppInfo{
+			{Name: "app01", Version: "1"},
+			{Name: "app02", Version: "2"},
+		},
+	}
+	expect := []models.NodeDeployHistory{
+		{Namespace: "default", NodeName: "node01", AppName: "app02", OldVersion: "1", NewVersion: "2", Trigger: models.DeployTriggerConfig},
+		{Namespace: "default", NodeName: "node01", AppName: "app03", OldVersion: "1", Trigger: models.DeployTriggerConfig},
+		{Namespace: "default", NodeName: "node01", AppName: "core", NewVersion: "1", Trigger: models.DeployTriggerConfig},
+	}
+	mockObject.dbStorage.EXPECT().CreateNodeDeployHistory(gomock.Any()).DoAndReturn(func(histories []models.NodeDeployHistory) (sql.Result, error) {
+		for i := range histories {
+			assert.False(t, histories[i].CreateTime.IsZero())
+			histories[i].CreateTime = time.Time{}
+		}
+		assert.Equal(t, expect, histories)
+		return nil, fmt.Errorf("error")
+	})
+	ns.recordDeployHistory("default", "node01", old, desire, models.DeployTriggerConfig)
+
+	// nothing changed
+	ns.recordDeployHistory("default", "node01", appVersions(desire), desire, models.DeployTriggerConfig)
+}
+
+func TestListDeployHistory(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := nodeService{
+		dbStorage: mockObject.dbStorage,
+	}
+
+	filter := &models.NodeDeployFilter{Filter: models.Filter{PageNo: 1, PageSize: 10}}
+	histories := []models.NodeDeployHistory{{Id: 1, AppName: "app01"}}
+	mockObject.dbStorage.EXPECT().ListNodeDeployHistory("default", "node01", filter).Return(histories, nil)
+	mockObject.dbStorage.EXPECT().CountNodeDeployHistory("default", "node01", filter).Return(1, nil)
+	res, err := ns.ListDeployHistory("default", "node01", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, histories, res.Items)
+
+	mockObject.dbStorage.EXPECT().ListNodeDeployHistory("default", "node01", filter).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CountNodeDeployHistory("default", "node01", filter).Return(0, nil)
+	res, err = ns.ListDeployHistory("default", "node01", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.NodeDeployHistory{}, res.Items)
+
+	mockObject.dbStorage.EXPECT().ListNodeDeployHistory("default", "node01", filter).Return(nil, fmt.Errorf("error"))
+	_, err = ns.ListDeployHistory("default", "node01", filter)
+	assert.Error(t, err)
+}
+
+func shadowUpdateCount(t *testing.T, typ string) uint64 {
+	var m dto.Metric
+	assert.NoError(t, shadowUpdateDuration.WithLabelValues(typ).(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,501 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/ota.go -package=service github.com/baetyl/baetyl-cloud/v2/service OTAService
+
+// the limits of ota tasks
+const (
+	DefaultOTATimeout = 600
+	MaxOTATimeout     = 3600
+)
+
+// the service of baetyl-core in the core app of node
+const coreServiceName = "baetyl-core"
+
+// otaIdleDuration the duration the nodes without running tasks are not queried again by their reports,
+// the tasks started meanwhile are advanced by the reports after it
+const otaIdleDuration = 30 * time.Second
+
+// OTAService upgrades baetyl-core of nodes by updating the image of the core apps,
+// the tasks are advanced by the reports of nodes
+type OTAService interface {
+	Create(namespace string, req *models.OTARequest) (*models.OTAView, error)
+	Get(namespace, node, id string) (*models.OTATask, error)
+	List(namespace, node string, filter *models.TaskFilter) (*models.ListView, error)
+	Cancel(namespace, node, id string) (*models.OTATask, error)
+	Retry(namespace, node, id string) (*models.OTATask, error)
+	// Advance finishes the running tasks of the node once the new version of the core app is reported running or failed
+	Advance(namespace, node string, report specV1.Report) error
+}
+
+type otaService struct {
+	storage plugin.DBStorage
+	app     ApplicationService
+	node    NodeService
+	// the expiry of the nodes known without running tasks
+	idle map[string]time.Time
+	mu   sync.Mutex
+}
+
+// NewOTAService NewOTAService
+func NewOTAService(config *config.CloudConfig) (OTAService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	as, err := NewApplicationService(config)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := NewNodeService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &otaService{
+		storage: ds.(plugin.DBStorage),
+		app:     as,
+		node:    ns,
+		idle:    map[string]time.Time{},
+	}, nil
+}
+
+// Create starts the upgrades of the nodes of the request, the nodes failed to start are returned with the errors
+func (s *otaService) Create(namespace string, req *models.OTARequest) (*models.OTAView, error) {
+	if req.Version == "" && req.Image == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "version or image is required"))
+	}
+	if req.Timeout == 0 {
+		req.Timeout = DefaultOTATimeout
+	}
+	if req.Timeout < 0 || req.Timeout > MaxOTATimeout {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid ota timeout"))
+	}
+	nodes, err := s.targets(namespace, req)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.OTAView{Tasks: []models.OTATask{}}
+	for _, n := range nodes {
+		task, err := s.start(namespace, n, req)
+		if err != nil {
+			view.Failures = append(view.Failures, models.OTAFailure{Node: n, Error: err.Error()})
+			continue
+		}
+		view.Tasks = append(view.Tasks, *task)
+	}
+	return view, nil
+}
+
+// Get get the ota task of the node
+func (s *otaService) Get(namespace, node, id string) (*models.OTATask, error) {
+	task, err := s.storage.GetTask(id)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if task == nil || task.Type != models.TaskOTA || task.Namespace != namespace || task.Node != node {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "ota"),
+			common.Field("name", id), common.Field("namespace", namespace))
+	}
+	return s.decode(task)
+}
+
+// List list the ota tasks of the node with pagination, the latest first
+func (s *otaService) List(namespace, node string, filter *models.TaskFilter) (*models.ListView, error) {
+	filter.Type = models.TaskOTA
+	tasks, err := s.storage.ListNodeTask(namespace, node, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountNodeTask(namespace, node, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	otas := make([]models.OTATask, 0, len(tasks))
+	for i := range tasks {
+		ota, err := s.decode(&tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		otas = append(otas, *ota)
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    otas,
+	}, nil
+}
+
+// Cancel cancels the running task, the old image is restored if the core app is not changed by others
+func (s *otaService) Cancel(namespace, node, id string) (*models.OTATask, error) {
+	ota, err := s.Get(namespace, node, id)
+	if err != nil {
+		return nil, err
+	}
+	if ota.Finished() {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the ota task is already "+ota.State))
+	}
+	if err = s.restore(ota); err != nil {
+		return nil, err
+	}
+	ota.State = models.OTACanceled
+	ota.Message = "canceled by user"
+	if err = s.update(ota); err != nil {
+		return nil, err
+	}
+	return ota, nil
+}
+
+// Retry deploys the new image again for the finished task which is not succeeded, the timeout restarts
+func (s *otaService) Retry(namespace, node, id string) (*models.OTATask, error) {
+	ota, err := s.Get(namespace, node, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ota.Finished() || ota.State == models.OTASucceeded {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the ota task is "+ota.State))
+	}
+	running, err := s.running(namespace, node)
+	if err != nil {
+		return nil, err
+	}
+	if len(running) > 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the node is upgrading by task "+running[0].ID))
+	}
+	app, err := getCoreApp(s.node, s.app, namespace, node)
+	if err != nil {
+		return nil, err
+	}
+	svc := coreService(app)
+	if svc == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "service"),
+			common.Field("name", coreServiceName), common.Field("namespace", namespace))
+	}
+	svc.Image = ota.NewImage
+	if app, err = s.deploy(namespace, app); err != nil {
+		return nil, err
+	}
+	s.setIdle(namespace, node, false)
+	ota.AppVersion = app.Version
+	ota.State = models.OTARunning
+	ota.Message = ""
+	ota.StartTime = time.Now()
+	if err = s.update(ota); err != nil {
+		return nil, err
+	}
+	return ota, nil
+}
+
+func (s *otaService) Advance(namespace, node string, report specV1.Report) error {
+	if s.isIdle(namespace, node) {
+		return nil
+	}
+	running, err := s.running(namespace, node)
+	if err != nil {
+		return err
+	}
+	s.setIdle(namespace, node, len(running) == 0)
+	for i := range running {
+		ota := &running[i]
+		for _, stats := range report.AppStats(true) {
+			if stats.Name != ota.App || stats.Version != ota.AppVersion {
+				continue
+			}
+			switch stats.Status {
+			case specV1.Running:
+				ota.State = models.OTASucceeded
+			case specV1.Failed:
+				ota.State = models.OTAFailed
+				ota.Message = stats.Cause
+				s.restoreOnFailure(ota)
+			}
+		}
+		if !ota.Finished() {
+			continue
+		}
+		if err = s.update(ota); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// start deploys the new image of baetyl-core to the node and records the task,
+// the node is not allowed to be upgraded by more than one task at the same time
+func (s *otaService) start(namespace, node string, req *models.OTARequest) (*models.OTATask, error) {
+	running, err := s.running(namespace, node)
+	if err != nil {
+		return nil, err
+	}
+	if len(running) > 0 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the node is upgrading by task "+running[0].ID))
+	}
+	app, err := getCoreApp(s.node, s.app, namespace, node)
+	if err != nil {
+		return nil, err
+	}
+	svc := coreService(app)
+	if svc == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "service"),
+			common.Field("name", coreServiceName), common.Field("namespace", namespace))
+	}
+	image := req.Image
+	if image == "" {
+		image = replaceImageTag(svc.Image, req.Version)
+	}
+	if image == svc.Image {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "baetyl-core is already "+image))
+	}
+	oldImage := svc.Image
+	svc.Image = image
+	if app, err = s.deploy(namespace, app); err != nil {
+		return nil, err
+	}
+	s.setIdle(namespace, node, false)
+
+	now := time.Now()
+	ota := &models.OTATask{
+		ID:         common.UUIDPrune(),
+		Namespace:  namespace,
+		Node:       node,
+		App:        app.Name,
+		AppVersion: app.Version,
+		OldVersion: imageTag(oldImage),
+		NewVersion: imageTag(image),
+		OldImage:   oldImage,
+		NewImage:   image,
+		Timeout:    req.Timeout,
+		State:      models.OTARunning,
+		StartTime:  now,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	task, err := ota.ToTask()
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err = s.storage.CreateTask(task); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return ota, nil
+}
+
+// targets returns the names of the nodes specified by the request
+func (s *otaService) targets(namespace string, req *models.OTARequest) ([]string, error) {
+	specified := 0
+	for _, ok := range []bool{len(req.Nodes) > 0, req.Selector != "", req.Batch != ""} {
+		if ok {
+			specified++
+		}
+	}
+	if specified != 1 {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "one of nodes, selector and batch is required"))
+	}
+
+	var nodes []string
+	switch {
+	case len(req.Nodes) > 0:
+		nodes = req.Nodes
+	case req.Selector != "":
+		list, err := s.node.List(namespace, &models.ListOptions{LabelSelector: req.Selector})
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range list.Items {
+			nodes = append(nodes, n.Name)
+		}
+	default:
+		batch, err := s.storage.GetBatch(req.Batch, namespace)
+		if err != nil {
+			return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+		}
+		if batch == nil {
+			return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "batch"),
+				common.Field("name", req.Batch), common.Field("namespace", namespace))
+		}
+		records, err := s.storage.ListRecord(req.Batch, namespace, &models.Filter{})
+		if err != nil {
+			return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+		}
+		for _, r := range records {
+			if r.NodeName != "" {
+				nodes = append(nodes, r.NodeName)
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	var res []string
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			res = append(res, n)
+		}
+	}
+	return res, nil
+}
+
+// running returns the running tasks of the node
+func (s *otaService) running(namespace, node string) ([]models.OTATask, error) {
+	filter := &models.TaskFilter{Type: models.TaskOTA, State: models.OTARunning}
+	tasks, err := s.storage.ListNodeTask(namespace, node, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	var otas []models.OTATask
+	for i := range tasks {
+		ota, err := s.decode(&tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		if !ota.Finished() {
+			otas = append(otas, *ota)
+		}
+	}
+	return otas, nil
+}
+
+// deploy updates the core app and its version in the desire of the node
+func (s *otaService) deploy(namespace string, app *specV1.Application) (*specV1.Application, error) {
+	app, err := s.app.Update(namespace, app)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.node.UpdateNodeAppVersion(namespace, app, models.DeployTriggerOTA); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+// decode returns the ota task of the task, the running task is timed out once its deadline is passed
+func (s *otaService) decode(task *models.Task) (*models.OTATask, error) {
+	ota, err := models.NewOTATaskFromTask(task)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if ota.State == models.OTARunning && time.Now().After(ota.Deadline()) {
+		ota.State = models.OTATimeout
+		ota.Message = fmt.Sprintf("the node does not report the new version in %d seconds", ota.Timeout)
+		s.restoreOnFailure(ota)
+		if err = s.update(ota); err != nil {
+			return nil, err
+		}
+	}
+	return ota, nil
+}
+
+// restore deploys the old image again if the core app is not changed by others
+func (s *otaService) restore(ota *models.OTATask) error {
+	app, err := getCoreApp(s.node, s.app, ota.Namespace, ota.Node)
+	if err != nil {
+		return err
+	}
+	if svc := coreService(app); svc != nil && svc.Image == ota.NewImage && ota.OldImage != "" {
+		svc.Image = ota.OldImage
+		if _, err = s.deploy(ota.Namespace, app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreOnFailure restores the old image of the failed or timed out task,
+// the task is finished anyway and the failure of the restore is kept in its message
+func (s *otaService) restoreOnFailure(ota *models.OTATask) {
+	if err := s.restore(ota); err != nil {
+		log.L().Error("failed to restore the old image of node",
+			log.Any(common.KeyContextNamespace, ota.Namespace),
+			log.Any("name", ota.Node),
+			log.Any("task", ota.ID),
+			log.Error(err))
+		ota.Message = ota.Message + ", and the old image is not restored: " + err.Error()
+	}
+}
+
+// isIdle returns true if the node is known without running tasks recently
+func (s *otaService) isIdle(namespace, node string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiry, ok := s.idle[namespace+"/"+node]
+	return ok && time.Now().Before(expiry)
+}
+
+func (s *otaService) setIdle(namespace, node string, idle bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.idle == nil {
+		s.idle = map[string]time.Time{}
+	}
+	if idle {
+		s.idle[namespace+"/"+node] = time.Now().Add(otaIdleDuration)
+	} else {
+		delete(s.idle, namespace+"/"+node)
+	}
+}
+
+func (s *otaService) update(ota *models.OTATask) error {
+	ota.UpdateTime = time.Now()
+	task, err := ota.ToTask()
+	if err != nil {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err = s.storage.UpdateTask(task); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+// getCoreApp returns the core app in the desire of the node
+func getCoreApp(nodes NodeService, apps ApplicationService, namespace, node string) (*specV1.Application, error) {
+	desire, err := nodes.GetDesire(namespace, node)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range desire.AppInfos(true) {
+		if strings.Contains(info.Name, coreServiceName) {
+			return apps.Get(namespace, info.Name, "")
+		}
+	}
+	return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "sysapp"),
+		common.Field("name", node), common.Field("namespace", namespace))
+}
+
+func coreService(app *specV1.Application) *specV1.Service {
+	for i := range app.Services {
+		if app.Services[i].Name == coreServiceName {
+			return &app.Services[i]
+		}
+	}
+	return nil
+}
+
+// imageTag returns the tag of the image, latest if not tagged
+func imageTag(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
+
+// replaceImageTag returns the image with the tag replaced
+func replaceImageTag(image, tag string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image + ":" + tag
+}
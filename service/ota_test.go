@@ -0,0 +1,313 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type otaMocks struct {
+	*MockServices
+	app  *ms.MockApplicationService
+	node *ms.MockNodeService
+}
+
+func initOTAService(t *testing.T) (*otaService, *otaMocks) {
+	mockObject := InitMockEnvironment(t)
+	mocks := &otaMocks{
+		MockServices: mockObject,
+		app:          ms.NewMockApplicationService(mockObject.ctl),
+		node:         ms.NewMockNodeService(mockObject.ctl),
+	}
+	return &otaService{
+		storage: mockObject.dbStorage,
+		app:     mocks.app,
+		node:    mocks.node,
+	}, mocks
+}
+
+func genCoreApp(node, version, image string) *specV1.Application {
+	return &specV1.Application{
+		Namespace: "default",
+		Name:      "baetyl-core-" + node,
+		Version:   version,
+		Selector:  "baetyl-node-name=" + node,
+		System:    true,
+		Services:  []specV1.Service{{Name: "baetyl-core", Image: image}},
+	}
+}
+
+func genOTATask(t *testing.T, state string, start time.Time) *models.Task {
+	ota := &models.OTATask{
+		ID:         "t1",
+		Namespace:  "default",
+		Node:       "n1",
+		App:        "baetyl-core-n1",
+		AppVersion: "2",
+		OldVersion: "v2.1.0",
+		NewVersion: "v2.2.0",
+		OldImage:   "baetyl:v2.1.0",
+		NewImage:   "baetyl:v2.2.0",
+		Timeout:    60,
+		State:      state,
+		StartTime:  start,
+	}
+	task, err := ota.ToTask()
+	assert.NoError(t, err)
+	return task
+}
+
+// expectDeploy expects the core app of the node updated to the image
+func (m *otaMocks) expectDeploy(t *testing.T, node, version, image string) {
+	m.node.EXPECT().GetDesire("default", node).Return(&specV1.Desire{
+		common.DesiredSysApplications: []specV1.AppInfo{{Name: "baetyl-core-" + node, Version: version}},
+	}, nil)
+	m.app.EXPECT().Get("default", "baetyl-core-"+node, "").Return(genCoreApp(node, version, "hub.baidubce.com/baetyl/baetyl:v2.1.0"), nil)
+	m.app.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, image, app.Services[0].Image)
+		res := *app
+		res.Version = version + "0"
+		return &res, nil
+	})
+	m.node.EXPECT().UpdateNodeAppVersion("default", gomock.Any(), models.DeployTriggerOTA).Return([]string{node}, nil)
+}
+
+func TestOTAImageTag(t *testing.T) {
+	assert.Equal(t, "v2.1.0", imageTag("hub.baidubce.com/baetyl/baetyl:v2.1.0"))
+	assert.Equal(t, "latest", imageTag("localhost:5000/baetyl"))
+	assert.Equal(t, "localhost:5000/baetyl:v2", replaceImageTag("localhost:5000/baetyl", "v2"))
+	assert.Equal(t, "baetyl:v2", replaceImageTag("baetyl:v1", "v2"))
+}
+
+func TestOTAServiceCreate(t *testing.T) {
+	s, mocks := initOTAService(t)
+	defer mocks.Close()
+
+	runningFilter := &models.TaskFilter{Type: models.TaskOTA, State: models.OTARunning}
+
+	// invalid requests
+	_, err := s.Create("default", &models.OTARequest{Nodes: []string{"n1"}})
+	assert.Error(t, err)
+	_, err = s.Create("default", &models.OTARequest{Version: "v2.2.0", Nodes: []string{"n1"}, Timeout: MaxOTATimeout + 1})
+	assert.Error(t, err)
+	_, err = s.Create("default", &models.OTARequest{Version: "v2.2.0", Nodes: []string{"n1"}, Selector: "a=a"})
+	assert.Error(t, err)
+	_, err = s.Create("default", &models.OTARequest{Version: "v2.2.0"})
+	assert.Error(t, err)
+
+	// n1 started, n2 is upgrading
+	var created *models.Task
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return(nil, nil)
+	mocks.expectDeploy(t, "n1", "1", "hub.baidubce.com/baetyl/baetyl:v2.2.0")
+	mocks.dbStorage.EXPECT().CreateTask(gomock.Any()).DoAndReturn(func(task *models.Task) (interface{}, error) {
+		created = task
+		return nil, nil
+	})
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n2", runningFilter).Return([]models.Task{*genOTATask(t, models.OTARunning, time.Now())}, nil)
+	view, err := s.Create("default", &models.OTARequest{Version: "v2.2.0", Nodes: []string{"n1", "n2", "n1"}})
+	assert.NoError(t, err)
+	assert.Len(t, view.Tasks, 1)
+	assert.Equal(t, "n1", view.Tasks[0].Node)
+	assert.Equal(t, "10", view.Tasks[0].AppVersion)
+	assert.Equal(t, models.OTARunning, view.Tasks[0].State)
+	assert.Equal(t, DefaultOTATimeout, view.Tasks[0].Timeout)
+	assert.Equal(t, models.TaskOTA, created.Type)
+	assert.Equal(t, "v2.1.0", created.OldVersion)
+	assert.Equal(t, "v2.2.0", created.NewVersion)
+	assert.Len(t, view.Failures, 1)
+	assert.Equal(t, "n2", view.Failures[0].Node)
+
+	// the same image
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return(nil, nil)
+	mocks.node.EXPECT().List("default", &models.ListOptions{LabelSelector: "a=a"}).Return(&models.NodeList{
+		Items: []specV1.Node{{Name: "n1"}},
+	}, nil)
+	mocks.node.EXPECT().GetDesire("default", "n1").Return(&specV1.Desire{
+		common.DesiredSysApplications: []specV1.AppInfo{{Name: "baetyl-core-n1", Version: "1"}},
+	}, nil)
+	mocks.app.EXPECT().Get("default", "baetyl-core-n1", "").Return(genCoreApp("n1", "1", "hub.baidubce.com/baetyl/baetyl:v2.1.0"), nil)
+	view, err = s.Create("default", &models.OTARequest{Version: "v2.1.0", Selector: "a=a"})
+	assert.NoError(t, err)
+	assert.Len(t, view.Tasks, 0)
+	assert.Len(t, view.Failures, 1)
+
+	// batch
+	mocks.dbStorage.EXPECT().GetBatch("b1", "default").Return(nil, nil)
+	_, err = s.Create("default", &models.OTARequest{Version: "v2.2.0", Batch: "b1"})
+	assert.Error(t, err)
+	mocks.dbStorage.EXPECT().GetBatch("b1", "default").Return(&models.Batch{Name: "b1"}, nil)
+	mocks.dbStorage.EXPECT().ListRecord("b1", "default", &models.Filter{}).Return([]models.Record{
+		{Name: "r1"}, {Name: "r2", NodeName: "n3"},
+	}, nil)
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n3", runningFilter).Return(nil, fmt.Errorf("error"))
+	view, err = s.Create("default", &models.OTARequest{Version: "v2.2.0", Batch: "b1"})
+	assert.NoError(t, err)
+	assert.Len(t, view.Failures, 1)
+	assert.Equal(t, "n3", view.Failures[0].Node)
+}
+
+func TestOTAServiceAdvance(t *testing.T) {
+	s, mocks := initOTAService(t)
+	defer mocks.Close()
+
+	runningFilter := &models.TaskFilter{Type: models.TaskOTA, State: models.OTARunning}
+	report := func(version string, status specV1.Status) specV1.Report {
+		return specV1.Report{
+			"sysappstats": []specV1.AppStats{{
+				AppInfo: specV1.AppInfo{Name: "baetyl-core-n1", Version: version},
+				Status:  status,
+				Cause:   "pull image failed",
+			}},
+		}
+	}
+
+	// not reported yet
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return([]models.Task{*genOTATask(t, models.OTARunning, time.Now())}, nil)
+	assert.NoError(t, s.Advance("default", "n1", report("1", specV1.Running)))
+
+	// succeeded
+	var updated *models.Task
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return([]models.Task{*genOTATask(t, models.OTARunning, time.Now())}, nil)
+	mocks.dbStorage.EXPECT().UpdateTask(gomock.Any()).DoAndReturn(func(task *models.Task) (interface{}, error) {
+		updated = task
+		return nil, nil
+	})
+	assert.NoError(t, s.Advance("default", "n1", report("2", specV1.Running)))
+	assert.Equal(t, models.OTASucceeded, updated.State)
+
+	// failed, the old image is restored
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return([]models.Task{*genOTATask(t, models.OTARunning, time.Now())}, nil)
+	mocks.expectRestore(t)
+	mocks.dbStorage.EXPECT().UpdateTask(gomock.Any()).DoAndReturn(func(task *models.Task) (interface{}, error) {
+		updated = task
+		return nil, nil
+	})
+	assert.NoError(t, s.Advance("default", "n1", report("2", specV1.Failed)))
+	assert.Equal(t, models.OTAFailed, updated.State)
+	ota, err := models.NewOTATaskFromTask(updated)
+	assert.NoError(t, err)
+	assert.Equal(t, "pull image failed", ota.Message)
+
+	// timed out, the old image is restored
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return([]models.Task{*genOTATask(t, models.OTARunning, time.Now().Add(-time.Hour))}, nil)
+	mocks.expectRestore(t)
+	mocks.dbStorage.EXPECT().UpdateTask(gomock.Any()).DoAndReturn(func(task *models.Task) (interface{}, error) {
+		updated = task
+		return nil, nil
+	})
+	assert.NoError(t, s.Advance("default", "n1", report("2", specV1.Running)))
+	assert.Equal(t, models.OTATimeout, updated.State)
+	// no running task is left after timed out
+	assert.True(t, s.isIdle("default", "n1"))
+	delete(s.idle, "default/n1")
+
+	// the task is failed even if the old image fails to restore
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return([]models.Task{*genOTATask(t, models.OTARunning, time.Now())}, nil)
+	mocks.node.EXPECT().GetDesire("default", "n1").Return(nil, fmt.Errorf("error"))
+	mocks.dbStorage.EXPECT().UpdateTask(gomock.Any()).DoAndReturn(func(task *models.Task) (interface{}, error) {
+		updated = task
+		return nil, nil
+	})
+	assert.NoError(t, s.Advance("default", "n1", report("2", specV1.Failed)))
+	assert.Equal(t, models.OTAFailed, updated.State)
+	ota, err = models.NewOTATaskFromTask(updated)
+	assert.NoError(t, err)
+	assert.Contains(t, ota.Message, "the old image is not restored")
+
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return(nil, fmt.Errorf("error"))
+	assert.Error(t, s.Advance("default", "n1", report("2", specV1.Running)))
+
+	// the node without running tasks is not queried again until the idle duration expires
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return(nil, nil)
+	assert.NoError(t, s.Advance("default", "n1", report("2", specV1.Running)))
+	assert.NoError(t, s.Advance("default", "n1", report("2", specV1.Running)))
+	s.idle["default/n1"] = time.Now().Add(-time.Second)
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return(nil, nil)
+	assert.NoError(t, s.Advance("default", "n1", report("2", specV1.Running)))
+}
+
+// expectRestore expects the core app of n1 restored to the old image
+func (m *otaMocks) expectRestore(t *testing.T) {
+	m.node.EXPECT().GetDesire("default", "n1").Return(&specV1.Desire{
+		common.DesiredSysApplications: []specV1.AppInfo{{Name: "baetyl-core-n1", Version: "2"}},
+	}, nil)
+	m.app.EXPECT().Get("default", "baetyl-core-n1", "").Return(genCoreApp("n1", "2", "baetyl:v2.2.0"), nil)
+	m.app.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "baetyl:v2.1.0", app.Services[0].Image)
+		return app, nil
+	})
+	m.node.EXPECT().UpdateNodeAppVersion("default", gomock.Any(), models.DeployTriggerOTA).Return([]string{"n1"}, nil)
+}
+
+func TestOTAServiceCancelRetry(t *testing.T) {
+	s, mocks := initOTAService(t)
+	defer mocks.Close()
+
+	runningFilter := &models.TaskFilter{Type: models.TaskOTA, State: models.OTARunning}
+
+	// not found
+	mocks.dbStorage.EXPECT().GetTask("t1").Return(genOTATask(t, models.OTARunning, time.Now()), nil)
+	_, err := s.Cancel("default", "n2", "t1")
+	assert.Error(t, err)
+
+	// cancel restores the old image
+	mocks.dbStorage.EXPECT().GetTask("t1").Return(genOTATask(t, models.OTARunning, time.Now()), nil)
+	mocks.node.EXPECT().GetDesire("default", "n1").Return(&specV1.Desire{
+		common.DesiredSysApplications: []specV1.AppInfo{{Name: "baetyl-core-n1", Version: "2"}},
+	}, nil)
+	mocks.app.EXPECT().Get("default", "baetyl-core-n1", "").Return(genCoreApp("n1", "2", "baetyl:v2.2.0"), nil)
+	mocks.app.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "baetyl:v2.1.0", app.Services[0].Image)
+		return app, nil
+	})
+	mocks.node.EXPECT().UpdateNodeAppVersion("default", gomock.Any(), models.DeployTriggerOTA).Return([]string{"n1"}, nil)
+	mocks.dbStorage.EXPECT().UpdateTask(gomock.Any()).Return(nil, nil)
+	ota, err := s.Cancel("default", "n1", "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.OTACanceled, ota.State)
+
+	// canceled task is not canceled again, succeeded task is not retried
+	mocks.dbStorage.EXPECT().GetTask("t1").Return(genOTATask(t, models.OTACanceled, time.Now()), nil)
+	_, err = s.Cancel("default", "n1", "t1")
+	assert.Error(t, err)
+	mocks.dbStorage.EXPECT().GetTask("t1").Return(genOTATask(t, models.OTASucceeded, time.Now()), nil)
+	_, err = s.Retry("default", "n1", "t1")
+	assert.Error(t, err)
+
+	// retry redeploys the new image
+	mocks.dbStorage.EXPECT().GetTask("t1").Return(genOTATask(t, models.OTAFailed, time.Now().Add(-time.Hour)), nil)
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", runningFilter).Return(nil, nil)
+	mocks.node.EXPECT().GetDesire("default", "n1").Return(&specV1.Desire{
+		common.DesiredSysApplications: []specV1.AppInfo{{Name: "baetyl-core-n1", Version: "2"}},
+	}, nil)
+	mocks.app.EXPECT().Get("default", "baetyl-core-n1", "").Return(genCoreApp("n1", "2", "baetyl:v2.2.0"), nil)
+	mocks.app.EXPECT().Update("default", gomock.Any()).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "baetyl:v2.2.0", app.Services[0].Image)
+		res := *app
+		res.Version = "3"
+		return &res, nil
+	})
+	mocks.node.EXPECT().UpdateNodeAppVersion("default", gomock.Any(), models.DeployTriggerOTA).Return([]string{"n1"}, nil)
+	mocks.dbStorage.EXPECT().UpdateTask(gomock.Any()).Return(nil, nil)
+	ota, err = s.Retry("default", "n1", "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.OTARunning, ota.State)
+	assert.Equal(t, "3", ota.AppVersion)
+	assert.True(t, ota.Deadline().After(time.Now()))
+
+	// list
+	mocks.dbStorage.EXPECT().ListNodeTask("default", "n1", gomock.Any()).Return([]models.Task{*genOTATask(t, models.OTASucceeded, time.Now())}, nil)
+	mocks.dbStorage.EXPECT().CountNodeTask("default", "n1", gomock.Any()).Return(1, nil)
+	list, err := s.List("default", "n1", &models.TaskFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Len(t, list.Items, 1)
+}
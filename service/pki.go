@@ -25,6 +25,8 @@ type PKIService interface {
 	DeleteServerCertificate(certId string) error
 	// DeleteClientCertificate delete a server certificate by certId
 	DeleteClientCertificate(certId string) error
+	// RotatePrivateKeys encrypts the private keys of the certificates again by the current key-encryption key
+	RotatePrivateKeys() (int, error)
 }
 
 const (
@@ -69,6 +71,10 @@ func (p *pkiService) DeleteClientCertificate(certId string) error {
 	return p.pki.DeleteClientCert(certId)
 }
 
+func (p *pkiService) RotatePrivateKeys() (int, error) {
+	return p.pki.RotatePrivateKeys()
+}
+
 func (p *pkiService) SignServerCertificate(cn string, altNames models.AltNames) (*models.PEMCredential, error) {
 	return p.signCertificate(cn, altNames, p.pki.CreateServerCert, p.pki.GetServerCert)
 }
@@ -103,3 +103,16 @@ func TestPkiService_DeleteCertificate(t *testing.T) {
 	err = ps.DeleteServerCertificate(certId)
 	assert.NoError(t, err)
 }
+
+func TestPkiService_RotatePrivateKeys(t *testing.T) {
+	mc := InitMockEnvironment(t)
+	defer mc.Close()
+
+	ps, err := NewPKIService(mc.conf)
+	assert.NoError(t, err)
+
+	mc.pki.EXPECT().RotatePrivateKeys().Return(2, nil).Times(1)
+	count, err := ps.RotatePrivateKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/rbac.go -package=service github.com/baetyl/baetyl-cloud/v2/service RBACService
+
+// RBACService authorizes users by the roles bound to them in namespaces
+type RBACService interface {
+	Authorize(namespace, user, resource, verb string) error
+	GetRoleBinding(namespace, user string) (*models.RoleBinding, error)
+	ListRoleBinding(namespace string, filter *models.Filter) (*models.ListView, error)
+	CreateRoleBinding(binding *models.RoleBinding) (*models.RoleBinding, error)
+	UpdateRoleBinding(binding *models.RoleBinding) (*models.RoleBinding, error)
+	DeleteRoleBinding(namespace, user string) error
+}
+
+type rbacService struct {
+	storage    plugin.DBStorage
+	enabled    bool
+	superUsers map[string]struct{}
+}
+
+// NewRBACService NewRBACService
+func NewRBACService(config *config.CloudConfig) (RBACService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	superUsers := map[string]struct{}{}
+	for _, u := range config.RBAC.SuperUsers {
+		superUsers[u] = struct{}{}
+	}
+	return &rbacService{
+		storage:    ds.(plugin.DBStorage),
+		enabled:    config.RBAC.Enabled,
+		superUsers: superUsers,
+	}, nil
+}
+
+// Authorize returns ErrRequestForbidden if the role of the user in the namespace is not granted the verb on the resource,
+// all requests are allowed if rbac is disabled, and super users are allowed in all namespaces
+func (s *rbacService) Authorize(namespace, user, resource, verb string) error {
+	if !s.enabled {
+		return nil
+	}
+	if _, ok := s.superUsers[user]; ok && user != "" {
+		return nil
+	}
+	forbidden := common.Error(common.ErrRequestForbidden,
+		common.Field("user", user),
+		common.Field("verb", verb),
+		common.Field("resource", resource),
+		common.Field("namespace", namespace))
+	if user == "" {
+		return forbidden
+	}
+	binding, err := s.storage.GetRoleBinding(namespace, user)
+	if err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if binding == nil || !models.RoleAllows(binding.Role, resource, verb) {
+		return forbidden
+	}
+	return nil
+}
+
+// GetRoleBinding get the role binding of the user in the namespace
+func (s *rbacService) GetRoleBinding(namespace, user string) (*models.RoleBinding, error) {
+	binding, err := s.storage.GetRoleBinding(namespace, user)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if binding == nil {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "rolebinding"),
+			common.Field("name", user),
+			common.Field("namespace", namespace))
+	}
+	return binding, nil
+}
+
+// ListRoleBinding list the role bindings of the namespace with pagination
+func (s *rbacService) ListRoleBinding(namespace string, filter *models.Filter) (*models.ListView, error) {
+	bindings, err := s.storage.ListRoleBinding(namespace, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := s.storage.CountRoleBinding(namespace, filter.Name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if bindings == nil {
+		bindings = []models.RoleBinding{}
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    bindings,
+	}, nil
+}
+
+// CreateRoleBinding bind a role to the user, a user has only one role in a namespace
+func (s *rbacService) CreateRoleBinding(binding *models.RoleBinding) (*models.RoleBinding, error) {
+	old, err := s.storage.GetRoleBinding(binding.Namespace, binding.User)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if old != nil {
+		return nil, common.Error(common.ErrResourceConflict,
+			common.Field("type", "rolebinding"),
+			common.Field("name", binding.User))
+	}
+	if _, err = s.storage.CreateRoleBinding(binding); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return s.GetRoleBinding(binding.Namespace, binding.User)
+}
+
+// UpdateRoleBinding change the role of the user
+func (s *rbacService) UpdateRoleBinding(binding *models.RoleBinding) (*models.RoleBinding, error) {
+	if _, err := s.storage.UpdateRoleBinding(binding); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return s.GetRoleBinding(binding.Namespace, binding.User)
+}
+
+// DeleteRoleBinding delete the role binding of the user
+func (s *rbacService) DeleteRoleBinding(namespace, user string) error {
+	if _, err := s.storage.DeleteRoleBinding(namespace, user); err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestRBACService_Authorize(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	// disabled
+	rs, err := NewRBACService(mockObject.conf)
+	assert.NoError(t, err)
+	assert.NoError(t, rs.Authorize("default", "", models.ResourceSecret, models.VerbWrite))
+
+	mockObject.conf.RBAC.Enabled = true
+	mockObject.conf.RBAC.SuperUsers = []string{"root"}
+	rs, err = NewRBACService(mockObject.conf)
+	assert.NoError(t, err)
+
+	assert.NoError(t, rs.Authorize("default", "root", models.ResourceRoleBinding, models.VerbWrite))
+
+	err = rs.Authorize("default", "", models.ResourceApp, models.VerbRead)
+	assert.Equal(t, common.ErrRequestForbidden, err.(errors.Coder).Code())
+
+	mockObject.dbStorage.EXPECT().GetRoleBinding("default", "user01").Return(nil, nil)
+	err = rs.Authorize("default", "user01", models.ResourceApp, models.VerbRead)
+	assert.Equal(t, common.ErrRequestForbidden, err.(errors.Coder).Code())
+	assert.Equal(t, "The user (user01) is not allowed to read apps in namespace(default).", err.Error())
+
+	mockObject.dbStorage.EXPECT().GetRoleBinding("default", "user01").Return(nil, fmt.Errorf("error"))
+	err = rs.Authorize("default", "user01", models.ResourceApp, models.VerbRead)
+	assert.Equal(t, common.ErrDatabase, err.(errors.Coder).Code())
+
+	cases := []struct {
+		role     string
+		resource string
+		verb     string
+		allowed  bool
+	}{
+		{models.RoleViewer, models.ResourceApp, models.VerbRead, true},
+		{models.RoleViewer, models.ResourceApp, models.VerbWrite, false},
+		{models.RoleViewer, models.ResourceSecret, models.VerbRead, false},
+		{models.RoleOperator, models.ResourceApp, models.VerbWrite, true},
+		{models.RoleOperator, models.ResourceNode, models.VerbWrite, true},
+		{models.RoleOperator, models.ResourceConfig, models.VerbWrite, true},
+		{models.RoleOperator, models.ResourceSecret, models.VerbRead, false},
+		{models.RoleOperator, models.ResourceRegistry, models.VerbWrite, false},
+		{models.RoleOperator, models.ResourceCertificate, models.VerbWrite, false},
+		{models.RoleOperator, models.ResourceRoleBinding, models.VerbRead, false},
+		{models.RoleAdmin, models.ResourceSecret, models.VerbWrite, true},
+		{models.RoleAdmin, models.ResourceRoleBinding, models.VerbWrite, true},
+		{"unknown", models.ResourceApp, models.VerbRead, false},
+	}
+	for _, c := range cases {
+		mockObject.dbStorage.EXPECT().GetRoleBinding("default", "user01").Return(&models.RoleBinding{
+			Namespace: "default",
+			User:      "user01",
+			Role:      c.role,
+		}, nil)
+		err = rs.Authorize("default", "user01", c.resource, c.verb)
+		if c.allowed {
+			assert.NoError(t, err, c)
+		} else {
+			assert.Error(t, err, c)
+		}
+	}
+}
+
+func TestRBACService_RoleBinding(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	rs, err := NewRBACService(mockObject.conf)
+	assert.NoError(t, err)
+
+	binding := &models.RoleBinding{Namespace: "default", User: "user01", Role: models.RoleViewer}
+
+	mockObject.dbStorage.EXPECT().GetRoleBinding("default", "user01").Return(binding, nil)
+	_, err = rs.CreateRoleBinding(binding)
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().GetRoleBinding("default", "user01").Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CreateRoleBinding(binding).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetRoleBinding("default", "user01").Return(binding, nil)
+	res, err := rs.CreateRoleBinding(binding)
+	assert.NoError(t, err)
+	assert.Equal(t, binding, res)
+
+	mockObject.dbStorage.EXPECT().UpdateRoleBinding(binding).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().GetRoleBinding("default", "user01").Return(binding, nil)
+	_, err = rs.UpdateRoleBinding(binding)
+	assert.NoError(t, err)
+
+	mockObject.dbStorage.EXPECT().UpdateRoleBinding(binding).Return(nil, fmt.Errorf("error"))
+	_, err = rs.UpdateRoleBinding(binding)
+	assert.Error(t, err)
+
+	mockObject.dbStorage.EXPECT().GetRoleBinding("default", "user02").Return(nil, nil)
+	_, err = rs.GetRoleBinding("default", "user02")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	filter := &models.Filter{Name: "%"}
+	mockObject.dbStorage.EXPECT().ListRoleBinding("default", filter).Return(nil, nil)
+	mockObject.dbStorage.EXPECT().CountRoleBinding("default", "%").Return(0, nil)
+	list, err := rs.ListRoleBinding("default", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.RoleBinding{}, list.Items)
+
+	mockObject.dbStorage.EXPECT().DeleteRoleBinding("default", "user01").Return(nil, nil)
+	assert.NoError(t, rs.DeleteRoleBinding("default", "user01"))
+	mockObject.dbStorage.EXPECT().DeleteRoleBinding("default", "user01").Return(nil, fmt.Errorf("error"))
+	assert.Error(t, rs.DeleteRoleBinding("default", "user01"))
+}
@@ -0,0 +1,479 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/rollout.go -package=service github.com/baetyl/baetyl-cloud/v2/service RolloutService
+
+const (
+	defaultRolloutPercent = 20
+	defaultRolloutTimeout = 600
+)
+
+// RolloutService rolls the new version of application out to the matched nodes in waves
+type RolloutService interface {
+	Get(namespace, name string) (*models.Rollout, error)
+	List(namespace string, filter *models.Filter) (*models.ListView, error)
+	Create(namespace string, app *specV1.Application, strategy *models.RolloutStrategy) (*models.Rollout, error)
+	Pause(namespace, name string) (*models.Rollout, error)
+	Resume(namespace, name string) (*models.Rollout, error)
+	Abort(namespace, name string) (*models.Rollout, error)
+	// UpdateNodeAppVersion updates the app version in the desires of the matched nodes,
+	// in waves by the strategy of the unfinished rollout of the app if any
+	UpdateNodeAppVersion(namespace string, app *specV1.Application, trigger string) ([]string, error)
+	// Reconcile checks the reports of the nodes in the current wave of all running rollouts,
+	// starts the next wave when the current one is finished
+	Reconcile() error
+}
+
+type rolloutService struct {
+	dbStorage    plugin.DBStorage
+	app          ApplicationService
+	node         NodeService
+	indexService IndexService
+}
+
+// NewRolloutService NewRolloutService
+func NewRolloutService(config *config.CloudConfig) (RolloutService, error) {
+	ds, err := plugin.GetPlugin(config.Plugin.DatabaseStorage)
+	if err != nil {
+		return nil, err
+	}
+	as, err := NewApplicationService(config)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := NewNodeService(config)
+	if err != nil {
+		return nil, err
+	}
+	is, err := NewIndexService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &rolloutService{
+		dbStorage:    ds.(plugin.DBStorage),
+		app:          as,
+		node:         ns,
+		indexService: is,
+	}, nil
+}
+
+func (r *rolloutService) Get(namespace, name string) (*models.Rollout, error) {
+	rollout, err := r.dbStorage.GetRollout(name, namespace)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if rollout == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "rollout"),
+			common.Field("name", name), common.Field("namespace", namespace))
+	}
+	return rollout, nil
+}
+
+func (r *rolloutService) List(namespace string, filter *models.Filter) (*models.ListView, error) {
+	rollouts, err := r.dbStorage.ListRollout(namespace, filter)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	count, err := r.dbStorage.CountRollout(namespace, filter.Name)
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	if rollouts == nil {
+		rollouts = []models.Rollout{}
+	}
+	return &models.ListView{
+		Total:    count,
+		PageNo:   filter.PageNo,
+		PageSize: filter.PageSize,
+		Items:    rollouts,
+	}, nil
+}
+
+// Create starts to roll the current version of app out, the rollouts of the older versions are aborted
+func (r *rolloutService) Create(namespace string, app *specV1.Application, strategy *models.RolloutStrategy) (*models.Rollout, error) {
+	if err := CheckRolloutStrategy(strategy); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if app.Selector != "" {
+		nodeList, err := r.node.List(namespace, &models.ListOptions{LabelSelector: app.Selector})
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range nodeList.Items {
+			names = append(names, n.Name)
+		}
+	}
+	sort.Strings(names)
+	nodes, err := orderRolloutNodes(names, strategy.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.abortRollouts(namespace, app.Name); err != nil {
+		return nil, err
+	}
+	if err = r.indexService.RefreshNodesIndexByApp(namespace, app.Name, names); err != nil {
+		return nil, err
+	}
+
+	rollout := &models.Rollout{
+		Name:       fmt.Sprintf("%s-%s", app.Name, app.Version),
+		Namespace:  namespace,
+		AppName:    app.Name,
+		AppVersion: app.Version,
+		Status:     models.RolloutRunning,
+		Strategy:   *strategy,
+		Progress: models.RolloutProgress{
+			Total: len(nodes),
+			Nodes: nodes,
+		},
+	}
+	if _, err = r.dbStorage.CreateRollout(rollout); err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+
+	if err = r.nextWave(rollout, app); err != nil {
+		return nil, err
+	}
+	// the rollout aborted by a newer update meanwhile is returned as is
+	if _, err = r.updateRollout(rollout, models.RolloutRunning); err != nil {
+		return nil, err
+	}
+	return r.Get(namespace, rollout.Name)
+}
+
+func (r *rolloutService) Pause(namespace, name string) (*models.Rollout, error) {
+	return r.changeStatus(namespace, name, models.RolloutPaused, "paused by user", models.RolloutRunning)
+}
+
+func (r *rolloutService) Resume(namespace, name string) (*models.Rollout, error) {
+	return r.changeStatus(namespace, name, models.RolloutRunning, "", models.RolloutPaused)
+}
+
+func (r *rolloutService) Abort(namespace, name string) (*models.Rollout, error) {
+	return r.changeStatus(namespace, name, models.RolloutAborted, "aborted by user", models.RolloutRunning, models.RolloutPaused)
+}
+
+// UpdateNodeAppVersion rolls the new version of the app out again by the strategy of the unfinished rollout,
+// so that the versions changed by the configs or secrets referenced do not bypass the waves
+func (r *rolloutService) UpdateNodeAppVersion(namespace string, app *specV1.Application, trigger string) ([]string, error) {
+	rollouts, err := r.dbStorage.ListRollout(namespace, &models.Filter{Name: app.Name + "-%"})
+	if err != nil {
+		return nil, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	for i := range rollouts {
+		rollout := &rollouts[i]
+		if rollout.AppName != app.Name || (rollout.Status != models.RolloutRunning && rollout.Status != models.RolloutPaused) {
+			continue
+		}
+		res, err := r.Create(namespace, app, &rollout.Strategy)
+		if err != nil {
+			return nil, err
+		}
+		return res.Progress.Nodes, nil
+	}
+	return r.node.UpdateNodeAppVersion(namespace, app, trigger)
+}
+
+func (r *rolloutService) Reconcile() error {
+	rollouts, err := r.dbStorage.ListRolloutByStatus(models.RolloutRunning)
+	if err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	for i := range rollouts {
+		rollout := &rollouts[i]
+		if err = r.reconcile(rollout); err != nil {
+			log.L().Error("failed to reconcile rollout",
+				log.Any("namespace", rollout.Namespace),
+				log.Any("name", rollout.Name),
+				log.Error(err))
+		}
+	}
+	return nil
+}
+
+func (r *rolloutService) reconcile(rollout *models.Rollout) error {
+	app, err := r.app.Get(rollout.Namespace, rollout.AppName, "")
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return r.finish(rollout, models.RolloutAborted, "the application is deleted")
+		}
+		return err
+	}
+	if app.Version != rollout.AppVersion {
+		return r.finish(rollout, models.RolloutAborted, "the application is updated")
+	}
+
+	progress := &rollout.Progress
+	finished := map[string]bool{}
+	for _, list := range [][]string{progress.Succeeded, progress.Failed, progress.Skipped} {
+		for _, n := range list {
+			finished[n] = true
+		}
+	}
+	timeout := time.Now().UTC().Sub(progress.WaveTime) > time.Duration(rollout.Strategy.Timeout)*time.Second
+	pending, failed := 0, 0
+	for _, name := range progress.WaveNodes {
+		if finished[name] {
+			continue
+		}
+		node, err := r.node.Get(rollout.Namespace, name)
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				progress.Skipped = append(progress.Skipped, name)
+				continue
+			}
+			return err
+		}
+		switch rolloutNodeStatus(node, app) {
+		case specV1.Running:
+			progress.Succeeded = append(progress.Succeeded, name)
+		case specV1.Failed:
+			progress.Failed = append(progress.Failed, name)
+			failed++
+		default:
+			if timeout {
+				progress.Failed = append(progress.Failed, name)
+				failed++
+			} else {
+				pending++
+			}
+		}
+	}
+
+	if failed > 0 && len(progress.Failed) > rollout.Strategy.FailureThreshold {
+		msg := fmt.Sprintf("%d nodes failed, exceeds the threshold %d", len(progress.Failed), rollout.Strategy.FailureThreshold)
+		if rollout.Strategy.FailurePolicy == models.RolloutPolicyAbort {
+			return r.finish(rollout, models.RolloutAborted, msg)
+		}
+		return r.finish(rollout, models.RolloutPaused, msg)
+	}
+	if pending == 0 {
+		if progress.Deployed >= progress.Total {
+			msg := ""
+			if len(progress.Failed) > 0 {
+				msg = fmt.Sprintf("%d nodes failed", len(progress.Failed))
+			}
+			return r.finish(rollout, models.RolloutSucceeded, msg)
+		}
+		// the progress is saved before the next wave, which is not started if the rollout is paused or aborted meanwhile
+		if ok, err := r.updateRollout(rollout, models.RolloutRunning); err != nil || !ok {
+			return err
+		}
+		if err = r.nextWave(rollout, app); err != nil {
+			return err
+		}
+	}
+	_, err = r.updateRollout(rollout, models.RolloutRunning)
+	return err
+}
+
+// nextWave updates the app version in the desire of the nodes of the next wave
+func (r *rolloutService) nextWave(rollout *models.Rollout, app *specV1.Application) error {
+	progress := &rollout.Progress
+	end := progress.Deployed + rolloutWaveSize(&rollout.Strategy, progress.Total, progress.Wave)
+	if end > progress.Total {
+		end = progress.Total
+	}
+
+	progress.Wave++
+	progress.WaveNodes = progress.Nodes[progress.Deployed:end]
+	progress.WaveTime = time.Now().UTC()
+	for _, name := range progress.WaveNodes {
+		node, err := r.node.Get(rollout.Namespace, name)
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				progress.Skipped = append(progress.Skipped, name)
+				continue
+			}
+			return err
+		}
+		refreshNodeDesireByApp(node, app)
+		if _, err = r.node.UpdateDesire(rollout.Namespace, name, node.Desire, models.DeployTriggerRollout); err != nil {
+			return err
+		}
+	}
+	progress.Deployed = end
+	return nil
+}
+
+func (r *rolloutService) finish(rollout *models.Rollout, status, message string) error {
+	rollout.Status = status
+	rollout.Message = message
+	_, err := r.updateRollout(rollout, models.RolloutRunning)
+	return err
+}
+
+// updateRollout saves the rollout only if its status is still the old status,
+// false is returned if the status is changed by others meanwhile, e.g. paused by user or aborted by a newer update
+func (r *rolloutService) updateRollout(rollout *models.Rollout, oldStatus string) (bool, error) {
+	res, err := r.dbStorage.UpdateRollout(rollout, oldStatus)
+	if err != nil {
+		return false, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	return n > 0, nil
+}
+
+func (r *rolloutService) changeStatus(namespace, name, status, message string, from ...string) (*models.Rollout, error) {
+	rollout, err := r.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	old := rollout.Status
+	allowed := false
+	for _, s := range from {
+		if s == old {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the rollout is %s", old)))
+	}
+	rollout.Status = status
+	rollout.Message = message
+	ok, err := r.updateRollout(rollout, old)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, common.Error(common.ErrResourceVersionConflict, common.Field("type", "rollout"), common.Field("name", name))
+	}
+	return r.Get(namespace, name)
+}
+
+// abortRollouts aborts the unfinished rollouts of the app
+func (r *rolloutService) abortRollouts(namespace, appName string) error {
+	rollouts, err := r.dbStorage.ListRollout(namespace, &models.Filter{Name: appName + "-%"})
+	if err != nil {
+		return common.Error(common.ErrDatabase, common.Field("error", err.Error()))
+	}
+	for i := range rollouts {
+		rollout := &rollouts[i]
+		if rollout.AppName != appName || (rollout.Status != models.RolloutRunning && rollout.Status != models.RolloutPaused) {
+			continue
+		}
+		old := rollout.Status
+		rollout.Status = models.RolloutAborted
+		rollout.Message = "the application is updated"
+		// the rollout finished meanwhile is left as is
+		if _, err = r.updateRollout(rollout, old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckRolloutStrategy checks the strategy and sets the defaults
+func CheckRolloutStrategy(strategy *models.RolloutStrategy) error {
+	if strategy.Percent == 0 {
+		strategy.Percent = defaultRolloutPercent
+	}
+	if strategy.Timeout == 0 {
+		strategy.Timeout = defaultRolloutTimeout
+	}
+	if strategy.FailurePolicy == "" {
+		strategy.FailurePolicy = models.RolloutPolicyPause
+	}
+	if strategy.Percent < 0 || strategy.Percent > 100 {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "percent should be between 1 and 100"))
+	}
+	if strategy.Timeout < 0 || strategy.FailureThreshold < 0 {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "timeout and failureThreshold should not be negative"))
+	}
+	if strategy.FailurePolicy != models.RolloutPolicyPause && strategy.FailurePolicy != models.RolloutPolicyAbort {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "failurePolicy should be pause or abort"))
+	}
+	return nil
+}
+
+// PlanRolloutWaves returns the nodes updated in each wave if the nodes matched are rolled out by the strategy
+func PlanRolloutWaves(names []string, strategy *models.RolloutStrategy) ([][]string, error) {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	nodes, err := orderRolloutNodes(sorted, strategy.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	var waves [][]string
+	for deployed := 0; deployed < len(nodes); {
+		end := deployed + rolloutWaveSize(strategy, len(nodes), len(waves))
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+		waves = append(waves, nodes[deployed:end])
+		deployed = end
+	}
+	return waves, nil
+}
+
+// rolloutWaveSize returns the number of the nodes of the wave, the specified nodes make up the first wave
+func rolloutWaveSize(strategy *models.RolloutStrategy, total, wave int) int {
+	size := (total*strategy.Percent + 99) / 100
+	if wave == 0 && len(strategy.Nodes) > 0 {
+		size = len(strategy.Nodes)
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// orderRolloutNodes puts the specified nodes of the first wave ahead of the others
+func orderRolloutNodes(names, first []string) ([]string, error) {
+	matched := map[string]bool{}
+	for _, n := range names {
+		matched[n] = true
+	}
+	ordered := map[string]bool{}
+	var nodes []string
+	for _, n := range first {
+		if !matched[n] {
+			return nil, common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("node (%s) is not matched by the application", n)))
+		}
+		if !ordered[n] {
+			ordered[n] = true
+			nodes = append(nodes, n)
+		}
+	}
+	for _, n := range names {
+		if !ordered[n] {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
+
+// rolloutNodeStatus returns the status of the app reported by the node, pending if the new version is not reported
+func rolloutNodeStatus(node *specV1.Node, app *specV1.Application) specV1.Status {
+	if node.Report == nil {
+		return specV1.Pending
+	}
+	for _, stats := range node.Report.AppStats(app.System) {
+		if stats.Name == app.Name && stats.Version == app.Version {
+			return stats.Status
+		}
+	}
+	return specV1.Pending
+}
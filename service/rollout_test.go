@@ -0,0 +1,370 @@
+package service
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type rolloutMocks struct {
+	*MockServices
+	app   *ms.MockApplicationService
+	node  *ms.MockNodeService
+	index *ms.MockIndexService
+}
+
+func initRolloutService(t *testing.T) (*rolloutService, *rolloutMocks) {
+	mockObject := InitMockEnvironment(t)
+	mocks := &rolloutMocks{
+		MockServices: mockObject,
+		app:          ms.NewMockApplicationService(mockObject.ctl),
+		node:         ms.NewMockNodeService(mockObject.ctl),
+		index:        ms.NewMockIndexService(mockObject.ctl),
+	}
+	return &rolloutService{
+		dbStorage:    mockObject.dbStorage,
+		app:          mocks.app,
+		node:         mocks.node,
+		indexService: mocks.index,
+	}, mocks
+}
+
+func genRolloutApp() *specV1.Application {
+	return &specV1.Application{
+		Namespace: "default",
+		Name:      "app",
+		Version:   "2",
+		Selector:  "a=a",
+	}
+}
+
+func genRolloutNode(name, version string, status specV1.Status) *specV1.Node {
+	node := &specV1.Node{Namespace: "default", Name: name}
+	if version != "" {
+		node.Report = specV1.Report{
+			"appstats": []specV1.AppStats{{AppInfo: specV1.AppInfo{Name: "app", Version: version}, Status: status}},
+		}
+	}
+	return node
+}
+
+func TestCheckRolloutStrategy(t *testing.T) {
+	strategy := &models.RolloutStrategy{}
+	assert.NoError(t, CheckRolloutStrategy(strategy))
+	assert.Equal(t, models.RolloutStrategy{
+		Percent:       defaultRolloutPercent,
+		FailurePolicy: models.RolloutPolicyPause,
+		Timeout:       defaultRolloutTimeout,
+	}, *strategy)
+
+	assert.Error(t, CheckRolloutStrategy(&models.RolloutStrategy{Percent: 101}))
+	assert.Error(t, CheckRolloutStrategy(&models.RolloutStrategy{FailureThreshold: -1}))
+	assert.Error(t, CheckRolloutStrategy(&models.RolloutStrategy{FailurePolicy: "retry"}))
+}
+
+func TestOrderRolloutNodes(t *testing.T) {
+	nodes, err := orderRolloutNodes([]string{"n1", "n2", "n3"}, []string{"n3", "n3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n3", "n1", "n2"}, nodes)
+
+	_, err = orderRolloutNodes([]string{"n1"}, []string{"n4"})
+	assert.Error(t, err)
+}
+
+func TestPlanRolloutWaves(t *testing.T) {
+	waves, err := PlanRolloutWaves([]string{"n5", "n4", "n3", "n2", "n1"}, &models.RolloutStrategy{Percent: 40, Nodes: []string{"n5"}})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"n5"}, {"n1", "n2"}, {"n3", "n4"}}, waves)
+
+	waves, err = PlanRolloutWaves(nil, &models.RolloutStrategy{Percent: 40})
+	assert.NoError(t, err)
+	assert.Empty(t, waves)
+
+	_, err = PlanRolloutWaves([]string{"n1"}, &models.RolloutStrategy{Percent: 40, Nodes: []string{"n4"}})
+	assert.Error(t, err)
+}
+
+func TestRolloutService_Create(t *testing.T) {
+	rs, mocks := initRolloutService(t)
+	defer mocks.Close()
+	app := genRolloutApp()
+
+	nodeList := &models.NodeList{Items: []specV1.Node{{Name: "n3"}, {Name: "n1"}, {Name: "n2"}}}
+	mocks.node.EXPECT().List("default", &models.ListOptions{LabelSelector: app.Selector}).Return(nodeList, nil).Times(2)
+
+	// the node of the first wave is not matched
+	_, err := rs.Create("default", app, &models.RolloutStrategy{Nodes: []string{"n4"}})
+	assert.Error(t, err)
+
+	old := models.Rollout{Name: "app-1", Namespace: "default", AppName: "app", Status: models.RolloutPaused}
+	mocks.dbStorage.EXPECT().ListRollout("default", &models.Filter{Name: "app-%"}).Return([]models.Rollout{old}, nil)
+	mocks.dbStorage.EXPECT().UpdateRollout(gomock.Any(), models.RolloutPaused).DoAndReturn(func(r *models.Rollout, _ string) (sql.Result, error) {
+		assert.Equal(t, "app-1", r.Name)
+		assert.Equal(t, models.RolloutAborted, r.Status)
+		return driver.RowsAffected(1), nil
+	})
+	mocks.index.EXPECT().RefreshNodesIndexByApp("default", "app", []string{"n1", "n2", "n3"}).Return(nil)
+	mocks.dbStorage.EXPECT().CreateRollout(gomock.Any()).DoAndReturn(func(r *models.Rollout) (sql.Result, error) {
+		assert.Equal(t, "app-2", r.Name)
+		assert.Equal(t, []string{"n2", "n1", "n3"}, r.Progress.Nodes)
+		assert.Equal(t, 3, r.Progress.Total)
+		return nil, nil
+	})
+	mocks.node.EXPECT().Get("default", "n2").Return(genRolloutNode("n2", "", ""), nil)
+	mocks.node.EXPECT().UpdateDesire("default", "n2", gomock.Any(), models.DeployTriggerRollout).DoAndReturn(
+		func(_, _ string, desire specV1.Desire, _ string) (*models.Shadow, error) {
+			assert.Equal(t, []specV1.AppInfo{{Name: "app", Version: "2"}}, desire.AppInfos(false))
+			return nil, nil
+		})
+	var saved *models.Rollout
+	mocks.dbStorage.EXPECT().UpdateRollout(gomock.Any(), models.RolloutRunning).DoAndReturn(func(r *models.Rollout, _ string) (sql.Result, error) {
+		saved = r
+		assert.Equal(t, 1, r.Progress.Wave)
+		assert.Equal(t, 1, r.Progress.Deployed)
+		assert.Equal(t, []string{"n2"}, r.Progress.WaveNodes)
+		return driver.RowsAffected(1), nil
+	})
+	mocks.dbStorage.EXPECT().GetRollout("app-2", "default").DoAndReturn(func(_, _ string) (*models.Rollout, error) {
+		return saved, nil
+	})
+	res, err := rs.Create("default", app, &models.RolloutStrategy{Nodes: []string{"n2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, models.RolloutRunning, res.Status)
+}
+
+func TestRolloutService_Reconcile(t *testing.T) {
+	rs, mocks := initRolloutService(t)
+	defer mocks.Close()
+	app := genRolloutApp()
+
+	genRollout := func() models.Rollout {
+		return models.Rollout{
+			Name:       "app-2",
+			Namespace:  "default",
+			AppName:    "app",
+			AppVersion: "2",
+			Status:     models.RolloutRunning,
+			Strategy: models.RolloutStrategy{
+				Percent:       50,
+				FailurePolicy: models.RolloutPolicyPause,
+				Timeout:       60,
+			},
+			Progress: models.RolloutProgress{
+				Total:     4,
+				Deployed:  2,
+				Wave:      1,
+				WaveNodes: []string{"n1", "n2"},
+				WaveTime:  time.Now().UTC(),
+				Nodes:     []string{"n1", "n2", "n3", "n4"},
+			},
+		}
+	}
+	expectSaved := func(check func(r *models.Rollout)) {
+		mocks.dbStorage.EXPECT().UpdateRollout(gomock.Any(), models.RolloutRunning).DoAndReturn(func(r *models.Rollout, _ string) (sql.Result, error) {
+			check(r)
+			return driver.RowsAffected(1), nil
+		})
+	}
+	mocks.app.EXPECT().Get("default", "app", "").Return(app, nil).AnyTimes()
+
+	// a node of the wave is still pending
+	mocks.dbStorage.EXPECT().ListRolloutByStatus(models.RolloutRunning).Return([]models.Rollout{genRollout()}, nil)
+	mocks.node.EXPECT().Get("default", "n1").Return(genRolloutNode("n1", "2", specV1.Running), nil)
+	mocks.node.EXPECT().Get("default", "n2").Return(genRolloutNode("n2", "1", specV1.Running), nil)
+	expectSaved(func(r *models.Rollout) {
+		assert.Equal(t, models.RolloutRunning, r.Status)
+		assert.Equal(t, 1, r.Progress.Wave)
+		assert.Equal(t, []string{"n1"}, r.Progress.Succeeded)
+	})
+	assert.NoError(t, rs.Reconcile())
+
+	// the wave is finished, start the next wave
+	rollout := genRollout()
+	rollout.Progress.Succeeded = []string{"n1"}
+	mocks.dbStorage.EXPECT().ListRolloutByStatus(models.RolloutRunning).Return([]models.Rollout{rollout}, nil)
+	mocks.node.EXPECT().Get("default", "n2").Return(genRolloutNode("n2", "2", specV1.Running), nil)
+	mocks.node.EXPECT().Get("default", "n3").Return(genRolloutNode("n3", "", ""), nil)
+	mocks.node.EXPECT().Get("default", "n4").Return(nil, common.Error(common.ErrResourceNotFound))
+	mocks.node.EXPECT().UpdateDesire("default", "n3", gomock.Any(), models.DeployTriggerRollout).Return(nil, nil)
+	expectSaved(func(r *models.Rollout) {
+		assert.Equal(t, 1, r.Progress.Wave)
+		assert.Equal(t, []string{"n1", "n2"}, r.Progress.Succeeded)
+	})
+	expectSaved(func(r *models.Rollout) {
+		assert.Equal(t, 2, r.Progress.Wave)
+		assert.Equal(t, 4, r.Progress.Deployed)
+		assert.Equal(t, []string{"n3", "n4"}, r.Progress.WaveNodes)
+		assert.Equal(t, []string{"n1", "n2"}, r.Progress.Succeeded)
+		assert.Equal(t, []string{"n4"}, r.Progress.Skipped)
+	})
+	assert.NoError(t, rs.Reconcile())
+
+	// the rollout paused meanwhile does not start the next wave
+	rollout = genRollout()
+	rollout.Progress.Succeeded = []string{"n1"}
+	mocks.dbStorage.EXPECT().ListRolloutByStatus(models.RolloutRunning).Return([]models.Rollout{rollout}, nil)
+	mocks.node.EXPECT().Get("default", "n2").Return(genRolloutNode("n2", "2", specV1.Running), nil)
+	mocks.dbStorage.EXPECT().UpdateRollout(gomock.Any(), models.RolloutRunning).Return(driver.RowsAffected(0), nil)
+	assert.NoError(t, rs.Reconcile())
+
+	// all waves are finished
+	rollout = genRollout()
+	rollout.Progress.Deployed = 4
+	rollout.Progress.Wave = 2
+	rollout.Progress.WaveNodes = []string{"n3", "n4"}
+	rollout.Progress.Succeeded = []string{"n1", "n2"}
+	rollout.Progress.Skipped = []string{"n4"}
+	mocks.dbStorage.EXPECT().ListRolloutByStatus(models.RolloutRunning).Return([]models.Rollout{rollout}, nil)
+	mocks.node.EXPECT().Get("default", "n3").Return(genRolloutNode("n3", "2", specV1.Running), nil)
+	expectSaved(func(r *models.Rollout) {
+		assert.Equal(t, models.RolloutSucceeded, r.Status)
+	})
+	assert.NoError(t, rs.Reconcile())
+
+	// failed nodes exceed the threshold
+	mocks.dbStorage.EXPECT().ListRolloutByStatus(models.RolloutRunning).Return([]models.Rollout{genRollout()}, nil)
+	mocks.node.EXPECT().Get("default", "n1").Return(genRolloutNode("n1", "2", specV1.Failed), nil)
+	mocks.node.EXPECT().Get("default", "n2").Return(genRolloutNode("n2", "2", specV1.Pending), nil)
+	expectSaved(func(r *models.Rollout) {
+		assert.Equal(t, models.RolloutPaused, r.Status)
+		assert.Equal(t, []string{"n1"}, r.Progress.Failed)
+		assert.Contains(t, r.Message, "exceeds the threshold")
+	})
+	assert.NoError(t, rs.Reconcile())
+
+	// the pending nodes fail after the timeout, the rollout is aborted by policy
+	rollout = genRollout()
+	rollout.Strategy.FailurePolicy = models.RolloutPolicyAbort
+	rollout.Strategy.FailureThreshold = 1
+	rollout.Progress.WaveTime = time.Now().UTC().Add(-time.Hour)
+	mocks.dbStorage.EXPECT().ListRolloutByStatus(models.RolloutRunning).Return([]models.Rollout{rollout}, nil)
+	mocks.node.EXPECT().Get("default", "n1").Return(genRolloutNode("n1", "", ""), nil)
+	mocks.node.EXPECT().Get("default", "n2").Return(genRolloutNode("n2", "1", specV1.Running), nil)
+	expectSaved(func(r *models.Rollout) {
+		assert.Equal(t, models.RolloutAborted, r.Status)
+		assert.Equal(t, []string{"n1", "n2"}, r.Progress.Failed)
+	})
+	assert.NoError(t, rs.Reconcile())
+
+	// the application is updated by others, the failure of a rollout is only logged
+	rollout = genRollout()
+	rollout.AppVersion = "1"
+	broken := genRollout()
+	broken.Name = "broken-1"
+	broken.AppName = "broken"
+	mocks.dbStorage.EXPECT().ListRolloutByStatus(models.RolloutRunning).Return([]models.Rollout{rollout, broken}, nil)
+	mocks.app.EXPECT().Get("default", "broken", "").Return(nil, fmt.Errorf("error"))
+	expectSaved(func(r *models.Rollout) {
+		assert.Equal(t, models.RolloutAborted, r.Status)
+		assert.Equal(t, "the application is updated", r.Message)
+	})
+	assert.NoError(t, rs.Reconcile())
+
+	mocks.dbStorage.EXPECT().ListRolloutByStatus(models.RolloutRunning).Return(nil, fmt.Errorf("error"))
+	assert.Error(t, rs.Reconcile())
+}
+
+func TestRolloutService_ChangeStatus(t *testing.T) {
+	rs, mocks := initRolloutService(t)
+	defer mocks.Close()
+
+	rollout := &models.Rollout{Name: "app-2", Namespace: "default", Status: models.RolloutRunning}
+	mocks.dbStorage.EXPECT().GetRollout("app-2", "default").Return(rollout, nil).Times(2)
+	mocks.dbStorage.EXPECT().UpdateRollout(rollout, models.RolloutRunning).Return(driver.RowsAffected(1), nil)
+	res, err := rs.Pause("default", "app-2")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RolloutPaused, res.Status)
+
+	mocks.dbStorage.EXPECT().GetRollout("app-2", "default").Return(rollout, nil)
+	_, err = rs.Pause("default", "app-2")
+	assert.Error(t, err)
+
+	mocks.dbStorage.EXPECT().GetRollout("app-2", "default").Return(rollout, nil).Times(2)
+	mocks.dbStorage.EXPECT().UpdateRollout(rollout, models.RolloutPaused).Return(driver.RowsAffected(1), nil)
+	res, err = rs.Resume("default", "app-2")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RolloutRunning, res.Status)
+
+	mocks.dbStorage.EXPECT().GetRollout("app-2", "default").Return(rollout, nil).Times(2)
+	mocks.dbStorage.EXPECT().UpdateRollout(rollout, models.RolloutRunning).Return(driver.RowsAffected(1), nil)
+	res, err = rs.Abort("default", "app-2")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RolloutAborted, res.Status)
+
+	mocks.dbStorage.EXPECT().GetRollout("app-3", "default").Return(nil, nil)
+	_, err = rs.Abort("default", "app-3")
+	assert.Error(t, err)
+
+	// the status is changed by others meanwhile
+	rollout.Status = models.RolloutRunning
+	mocks.dbStorage.EXPECT().GetRollout("app-2", "default").Return(rollout, nil)
+	mocks.dbStorage.EXPECT().UpdateRollout(rollout, models.RolloutRunning).Return(driver.RowsAffected(0), nil)
+	_, err = rs.Pause("default", "app-2")
+	assert.Error(t, err)
+}
+
+func TestRolloutService_UpdateNodeAppVersion(t *testing.T) {
+	rs, mocks := initRolloutService(t)
+	defer mocks.Close()
+	app := genRolloutApp()
+
+	// updated at once without any unfinished rollout
+	finished := models.Rollout{Name: "app-1", Namespace: "default", AppName: "app", Status: models.RolloutSucceeded}
+	mocks.dbStorage.EXPECT().ListRollout("default", &models.Filter{Name: "app-%"}).Return([]models.Rollout{finished}, nil)
+	mocks.node.EXPECT().UpdateNodeAppVersion("default", app, models.DeployTriggerConfig).Return([]string{"n1"}, nil)
+	nodes, err := rs.UpdateNodeAppVersion("default", app, models.DeployTriggerConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, nodes)
+
+	// rolled out again by the strategy of the unfinished rollout
+	running := models.Rollout{Name: "app-1", Namespace: "default", AppName: "app", Status: models.RolloutRunning,
+		Strategy: models.RolloutStrategy{Percent: 50, FailurePolicy: models.RolloutPolicyPause, Timeout: 60}}
+	mocks.dbStorage.EXPECT().ListRollout("default", &models.Filter{Name: "app-%"}).Return([]models.Rollout{running}, nil).Times(2)
+	mocks.node.EXPECT().List("default", &models.ListOptions{LabelSelector: app.Selector}).Return(&models.NodeList{Items: []specV1.Node{{Name: "n1"}, {Name: "n2"}}}, nil)
+	mocks.dbStorage.EXPECT().UpdateRollout(gomock.Any(), models.RolloutRunning).DoAndReturn(func(r *models.Rollout, _ string) (sql.Result, error) {
+		assert.Equal(t, models.RolloutAborted, r.Status)
+		return driver.RowsAffected(1), nil
+	})
+	mocks.index.EXPECT().RefreshNodesIndexByApp("default", "app", []string{"n1", "n2"}).Return(nil)
+	mocks.dbStorage.EXPECT().CreateRollout(gomock.Any()).DoAndReturn(func(r *models.Rollout) (sql.Result, error) {
+		assert.Equal(t, "app-2", r.Name)
+		assert.Equal(t, 50, r.Strategy.Percent)
+		return nil, nil
+	})
+	mocks.node.EXPECT().Get("default", "n1").Return(genRolloutNode("n1", "", ""), nil)
+	mocks.node.EXPECT().UpdateDesire("default", "n1", gomock.Any(), models.DeployTriggerRollout).Return(nil, nil)
+	var saved *models.Rollout
+	mocks.dbStorage.EXPECT().UpdateRollout(gomock.Any(), models.RolloutRunning).DoAndReturn(func(r *models.Rollout, _ string) (sql.Result, error) {
+		saved = r
+		assert.Equal(t, []string{"n1"}, r.Progress.WaveNodes)
+		return driver.RowsAffected(1), nil
+	})
+	mocks.dbStorage.EXPECT().GetRollout("app-2", "default").DoAndReturn(func(_, _ string) (*models.Rollout, error) {
+		return saved, nil
+	})
+	nodes, err = rs.UpdateNodeAppVersion("default", app, models.DeployTriggerConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2"}, nodes)
+}
+
+func TestRolloutService_List(t *testing.T) {
+	rs, mocks := initRolloutService(t)
+	defer mocks.Close()
+
+	filter := &models.Filter{Name: "%"}
+	mocks.dbStorage.EXPECT().ListRollout("default", filter).Return(nil, nil)
+	mocks.dbStorage.EXPECT().CountRollout("default", "%").Return(0, nil)
+	res, err := rs.List("default", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Rollout{}, res.Items)
+}
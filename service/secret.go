@@ -7,6 +7,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-go/v2/errors"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 )
 
@@ -19,10 +20,16 @@ type SecretService interface {
 	Create(namespace string, secret *specV1.Secret) (*specV1.Secret, error)
 	Update(namespace string, secret *specV1.Secret) (*specV1.Secret, error)
 	Delete(namespace, name string) error
+	Rotate(namespace string) (int, error)
 }
 
 type secretService struct {
-	storage plugin.ModelStorage
+	storage  plugin.ModelStorage
+	envelope *plugin.Envelope
+	// the services to redeploy the applications referencing the rotated secrets
+	index   IndexService
+	app     ApplicationService
+	rollout RolloutService
 }
 
 // NewSecretService NewSecretService
@@ -31,8 +38,28 @@ func NewSecretService(config *config.CloudConfig) (SecretService, error) {
 	if err != nil {
 		return nil, err
 	}
+	envelope, err := plugin.NewEnvelope(config.Plugin.KMS)
+	if err != nil {
+		return nil, err
+	}
+	is, err := NewIndexService(config)
+	if err != nil {
+		return nil, err
+	}
+	as, err := NewApplicationService(config)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := NewRolloutService(config)
+	if err != nil {
+		return nil, err
+	}
 	return &secretService{
-		storage: ms.(plugin.ModelStorage),
+		storage:  ms.(plugin.ModelStorage),
+		envelope: envelope,
+		index:    is,
+		app:      as,
+		rollout:  rs,
 	}, nil
 }
 
@@ -43,26 +70,161 @@ func (s *secretService) Get(namespace, name, version string) (*specV1.Secret, er
 		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "secret"),
 			common.Field("name", name))
 	}
-	return res, err
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(res)
 }
 
 // List get list Secret
 func (s *secretService) List(namespace string, listOptions *models.ListOptions) (*models.SecretList, error) {
-	return s.storage.ListSecret(namespace, listOptions)
+	res, err := s.storage.ListSecret(namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res.Items {
+		item, err := s.decrypt(&res.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Items[i] = *item
+	}
+	return res, nil
 }
 
 // Create Create a Secret
 func (s *secretService) Create(namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
-	return s.storage.CreateSecret(namespace, secret)
-
+	encrypted, err := s.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.storage.CreateSecret(namespace, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(res)
 }
 
 // Update update a Secret
 func (s *secretService) Update(namespace string, secret *specV1.Secret) (*specV1.Secret, error) {
-	return s.storage.UpdateSecret(namespace, secret)
+	encrypted, err := s.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.storage.UpdateSecret(namespace, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(res)
 }
 
 // Delete Delete a Secret
 func (s *secretService) Delete(namespace, name string) error {
 	return s.storage.DeleteSecret(namespace, name)
 }
+
+// Rotate encrypts the secrets of the namespace again if they are not encrypted by the current key-encryption key,
+// and points the applications referencing them to the new versions, the number of the secrets encrypted is returned
+func (s *secretService) Rotate(namespace string) (int, error) {
+	if !s.envelope.Enabled() {
+		return 0, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the kms is not configured"))
+	}
+	list, err := s.storage.ListSecret(namespace, &models.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	for i := range list.Items {
+		item := &list.Items[i]
+		outdated := false
+		for _, v := range item.Data {
+			if s.envelope.Outdated(v) {
+				outdated = true
+				break
+			}
+		}
+		if !outdated {
+			continue
+		}
+		secret, err := s.decrypt(item)
+		if err != nil {
+			return count, err
+		}
+		res, err := s.Update(namespace, secret)
+		if err != nil {
+			return count, err
+		}
+		count++
+		if err = s.updateAppSecret(namespace, res); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// updateAppSecret updates the secret version of the applications referencing the secret and their nodes
+func (s *secretService) updateAppSecret(namespace string, secret *specV1.Secret) error {
+	appNames, err := s.index.ListAppIndexBySecret(namespace, secret.Name)
+	if err != nil {
+		return err
+	}
+	for _, appName := range appNames {
+		app, err := s.app.Get(namespace, appName, "")
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				continue
+			}
+			return err
+		}
+		updated := false
+		for _, v := range app.Volumes {
+			if v.Secret != nil && v.Secret.Name == secret.Name &&
+				common.CompareNumericalString(secret.Version, v.Secret.Version) > 0 {
+				v.Secret.Version = secret.Version
+				updated = true
+			}
+		}
+		if !updated {
+			continue
+		}
+		if app, err = s.app.Update(namespace, app); err != nil {
+			return err
+		}
+		if _, err = s.rollout.UpdateNodeAppVersion(namespace, app, models.DeployTriggerSecret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encrypt returns the copy of the secret with the data encrypted
+func (s *secretService) encrypt(secret *specV1.Secret) (*specV1.Secret, error) {
+	if !s.envelope.Enabled() || secret == nil {
+		return secret, nil
+	}
+	res := *secret
+	res.Data = make(map[string][]byte, len(secret.Data))
+	for k, v := range secret.Data {
+		data, err := s.envelope.Encrypt(v)
+		if err != nil {
+			return nil, err
+		}
+		res.Data[k] = data
+	}
+	return &res, nil
+}
+
+// decrypt decrypts the data of the secret in place, the data not encrypted is kept
+func (s *secretService) decrypt(secret *specV1.Secret) (*specV1.Secret, error) {
+	if secret == nil {
+		return nil, nil
+	}
+	for k, v := range secret.Data {
+		data, err := s.envelope.Decrypt(v)
+		if err != nil {
+			return nil, err
+		}
+		secret.Data[k] = data
+	}
+	return secret, nil
+}
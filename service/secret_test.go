@@ -1,9 +1,14 @@
 package service
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -80,3 +85,101 @@ func TestDefaultRegistryService_Update(t *testing.T) {
 	_, err = cs.Update(registry.Namespace, registry)
 	assert.NoError(t, err)
 }
+
+func TestSecretService_Encryption(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	mockKMS := mockPlugin.NewMockKMS(mockObject.ctl)
+	mockObject.conf.Plugin.KMS = common.RandString(9)
+	plugin.RegisterFactory(mockObject.conf.Plugin.KMS, func() (plugin.Plugin, error) {
+		return mockKMS, nil
+	})
+	current := "k2"
+	mockKMS.EXPECT().CurrentKeyID().DoAndReturn(func() string { return current }).AnyTimes()
+	mockKMS.EXPECT().Encrypt(gomock.Any()).DoAndReturn(func(key []byte) (string, []byte, error) {
+		return current, key, nil
+	}).AnyTimes()
+	mockKMS.EXPECT().Decrypt(gomock.Any(), gomock.Any()).DoAndReturn(func(_ string, key []byte) ([]byte, error) {
+		return key, nil
+	}).AnyTimes()
+	cs, err := NewSecretService(mockObject.conf)
+	assert.NoError(t, err)
+
+	// the data is encrypted in the storage and decrypted for the callers
+	var stored *specV1.Secret
+	mockObject.modelStorage.EXPECT().CreateSecret("default", gomock.Any()).DoAndReturn(func(_ string, secret *specV1.Secret) (*specV1.Secret, error) {
+		stored = secret
+		res := *secret
+		res.Data = map[string][]byte{}
+		for k, v := range secret.Data {
+			res.Data[k] = v
+		}
+		return &res, nil
+	})
+	secret := &specV1.Secret{Namespace: "default", Name: "abc", Data: map[string][]byte{"password": []byte("pwd")}}
+	res, err := cs.Create("default", secret)
+	assert.NoError(t, err)
+	assert.Equal(t, "pwd", string(res.Data["password"]))
+	assert.Equal(t, "pwd", string(secret.Data["password"]))
+	assert.True(t, plugin.IsEnveloped(stored.Data["password"]))
+	enveloped := string(stored.Data["password"])
+
+	mockObject.modelStorage.EXPECT().GetSecret("default", "abc", "").Return(stored, nil)
+	res, err = cs.Get("default", "abc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "pwd", string(res.Data["password"]))
+
+	// the secrets stored before the rotation or the encryption are re-encrypted
+	list := func() *models.SecretList {
+		return &models.SecretList{Items: []specV1.Secret{
+			{Name: "plain", Data: map[string][]byte{"password": []byte("pwd")}},
+			{Name: "k1", Data: map[string][]byte{"password": []byte(strings.Replace(enveloped, "$k2$", "$k1$", 1))}},
+			{Name: "k2", Data: map[string][]byte{"password": []byte(enveloped)}},
+		}}
+	}
+	mockIndex := ms.NewMockIndexService(mockObject.ctl)
+	mockApp := ms.NewMockApplicationService(mockObject.ctl)
+	mockRollout := ms.NewMockRolloutService(mockObject.ctl)
+	ss := cs.(*secretService)
+	ss.index, ss.app, ss.rollout = mockIndex, mockApp, mockRollout
+	mockObject.modelStorage.EXPECT().ListSecret("default", &models.ListOptions{}).Return(list(), nil)
+	var rotated []string
+	mockObject.modelStorage.EXPECT().UpdateSecret("default", gomock.Any()).DoAndReturn(func(_ string, secret *specV1.Secret) (*specV1.Secret, error) {
+		assert.True(t, plugin.IsEnveloped(secret.Data["password"]))
+		rotated = append(rotated, secret.Name)
+		res := *secret
+		res.Version = "2"
+		return &res, nil
+	}).Times(2)
+	// the applications referencing the rotated secrets are redeployed with the new versions
+	app := &specV1.Application{Namespace: "default", Name: "a0", Volumes: []specV1.Volume{
+		{Name: "v0", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "plain", Version: "1"}}},
+	}}
+	mockIndex.EXPECT().ListAppIndexBySecret("default", "plain").Return([]string{"a0", "a1"}, nil)
+	mockIndex.EXPECT().ListAppIndexBySecret("default", "k1").Return(nil, nil)
+	mockApp.EXPECT().Get("default", "a0", "").Return(app, nil)
+	mockApp.EXPECT().Get("default", "a1", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	mockApp.EXPECT().Update("default", app).DoAndReturn(func(_ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "2", app.Volumes[0].Secret.Version)
+		return app, nil
+	})
+	mockRollout.EXPECT().UpdateNodeAppVersion("default", app, models.DeployTriggerSecret).Return([]string{"n0"}, nil)
+	count, err := cs.Rotate("default")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"plain", "k1"}, rotated)
+
+	mockObject.modelStorage.EXPECT().ListSecret("default", &models.ListOptions{}).Return(list(), nil)
+	sl, err := cs.List("default", &models.ListOptions{})
+	assert.NoError(t, err)
+	for _, item := range sl.Items {
+		assert.Equal(t, "pwd", string(item.Data["password"]))
+	}
+
+	// the rotation requires the kms
+	mockObject.conf.Plugin.KMS = ""
+	cs, err = NewSecretService(mockObject.conf)
+	assert.NoError(t, err)
+	_, err = cs.Rotate("default")
+	assert.Error(t, err)
+}
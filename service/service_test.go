@@ -24,6 +24,7 @@ type MockServices struct {
 	shadowStorage  *mockPlugin.MockShadow
 	license        *mockPlugin.MockLicense
 	property       *mockPlugin.MockProperty
+	pubsub         *mockPlugin.MockPubsub
 }
 
 func (m *MockServices) Close() {
@@ -99,6 +100,7 @@ func mockTestConfig() *config.CloudConfig {
 	conf.Plugin.Shadow = conf.Plugin.DatabaseStorage
 	conf.Plugin.License = common.RandString(9)
 	conf.Plugin.Property = common.RandString(9)
+	conf.Plugin.Pubsub = common.RandString(9)
 	conf.Template.Path = "../scripts/native/templates"
 	return conf
 }
@@ -132,6 +134,8 @@ func InitMockEnvironment(t *testing.T) *MockServices {
 
 	mLicense := mockPlugin.NewMockLicense(mockCtl)
 	plugin.RegisterFactory(conf.Plugin.License, mockLicense(mLicense))
+	mPubsub := mockPlugin.NewMockPubsub(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Pubsub, mockPubsub(mPubsub))
 	_, err := NewSyncService(conf)
 	assert.Nil(t, err)
 
@@ -148,6 +152,7 @@ func InitMockEnvironment(t *testing.T) *MockServices {
 		auth:           mAuth,
 		license:        mLicense,
 		property:       mProperty,
+		pubsub:         mPubsub,
 	}
 }
 
@@ -179,3 +184,10 @@ func mockProperty(mock plugin.Property) plugin.Factory {
 	}
 	return factory
 }
+
+func mockPubsub(mock plugin.Pubsub) plugin.Factory {
+	factory := func() (plugin.Plugin, error) {
+		return mock, nil
+	}
+	return factory
+}
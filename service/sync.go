@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
@@ -20,6 +21,7 @@ import (
 type SyncService interface {
 	Report(namespace, name string, report specV1.Report) (specV1.Desire, error)
 	Desire(namespace string, infos []specV1.ResourceInfo, metadata map[string]string) ([]specV1.ResourceValue, error)
+	Delta(namespace, name string) (specV1.Desire, error)
 }
 
 type HandlerPopulateConfig func(cfg *specV1.Configuration, metadata map[string]string) error
@@ -37,6 +39,8 @@ type SyncServiceImpl struct {
 	AppService    ApplicationService
 	SecretService SecretService
 	ObjectService ObjectService
+	EventService  EventService
+	OTAService    OTAService
 	Hooks         map[string]interface{}
 }
 
@@ -75,6 +79,14 @@ func NewSyncService(config *config.CloudConfig) (SyncService, error) {
 	if err != nil {
 		return nil, err
 	}
+	es.EventService, err = NewEventService(config)
+	if err != nil {
+		return nil, err
+	}
+	es.OTAService, err = NewOTAService(config)
+	if err != nil {
+		return nil, err
+	}
 	es.Hooks[HookNamePopulateConfig] = HandlerPopulateConfig(es.PopulateConfig)
 	return es, nil
 }
@@ -89,6 +101,28 @@ func (t *SyncServiceImpl) Report(namespace, name string, report specV1.Report) (
 		return nil, err
 	}
 
+	event := &models.NodeEvent{
+		Type:      models.NodeEventReport,
+		Namespace: namespace,
+		Name:      name,
+		Report:    shadow.Report,
+		Time:      time.Now().UTC(),
+	}
+	if err = t.EventService.PublishNodeEvent(event); err != nil {
+		log.L().Warn("failed to publish node report event",
+			log.Any(common.KeyContextNamespace, namespace),
+			log.Any("name", name),
+			log.Error(err))
+	}
+
+	// the ota tasks are advanced again by the next report if failed
+	if err = t.OTAService.Advance(namespace, name, shadow.Report); err != nil {
+		log.L().Warn("failed to advance node ota tasks",
+			log.Any(common.KeyContextNamespace, namespace),
+			log.Any("name", name),
+			log.Error(err))
+	}
+
 	err = checkSysapp(name, &shadow.Desire)
 
 	if err != nil {
@@ -111,6 +145,19 @@ func (t *SyncServiceImpl) Report(namespace, name string, report specV1.Report) (
 	return delta, nil
 }
 
+// Delta returns the difference between the desire and the report of the node without updating the report,
+// which is pushed to the node once its desire is changed
+func (t *SyncServiceImpl) Delta(namespace, name string) (specV1.Desire, error) {
+	node, err := t.NodeService.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkSysapp(name, &node.Desire); err != nil {
+		return nil, err
+	}
+	return node.Desire.Diff(node.Report)
+}
+
 func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInfo, metadata map[string]string) ([]specV1.ResourceValue, error) {
 	var crdDatas []specV1.ResourceValue
 	for _, info := range crdInfos {
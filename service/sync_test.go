@@ -23,10 +23,15 @@ func TestReport(t *testing.T) {
 	namespace := "ns01"
 	name := "node01"
 
+	es := ms.NewMockEventService(mockObject.ctl)
+	otas := ms.NewMockOTAService(mockObject.ctl)
+
 	ns.EXPECT().UpdateReport(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
 
 	sync := SyncServiceImpl{
-		NodeService: ns,
+		NodeService:  ns,
+		EventService: es,
+		OTAService:   otas,
 	}
 	info := specV1.Report{}
 	response, err := sync.Report(namespace, name, info)
@@ -42,6 +47,13 @@ func TestReport(t *testing.T) {
 	}
 
 	ns.EXPECT().UpdateReport(gomock.Any(), gomock.Any(), gomock.Any()).Return(shadow, nil)
+	es.EXPECT().PublishNodeEvent(gomock.Any()).DoAndReturn(func(event *models.NodeEvent) error {
+		assert.Equal(t, models.NodeEventReport, event.Type)
+		assert.Equal(t, namespace, event.Namespace)
+		assert.Equal(t, name, event.Name)
+		return nil
+	})
+	otas.EXPECT().Advance(namespace, name, gomock.Any()).Return(nil)
 	response, err = sync.Report(namespace, name, info)
 	assert.Error(t, err)
 
@@ -60,6 +72,9 @@ func TestReport(t *testing.T) {
 		},
 	}
 	ns.EXPECT().UpdateReport(gomock.Any(), gomock.Any(), gomock.Any()).Return(shadow, nil)
+	// the failures to publish the event and advance the ota tasks do not fail the report
+	es.EXPECT().PublishNodeEvent(gomock.Any()).Return(fmt.Errorf("error"))
+	otas.EXPECT().Advance(namespace, name, gomock.Any()).Return(fmt.Errorf("error"))
 	response, err = sync.Report(namespace, name, info)
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
@@ -163,8 +178,14 @@ func TestSyncService_Report(t *testing.T) {
 	defer mockObject.Close()
 
 	mockNs := ms.NewMockNodeService(mockObject.ctl)
+	mockEs := ms.NewMockEventService(mockObject.ctl)
+	mockEs.EXPECT().PublishNodeEvent(gomock.Any()).Return(nil).AnyTimes()
+	mockOs := ms.NewMockOTAService(mockObject.ctl)
+	mockOs.EXPECT().Advance(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	ss := &SyncServiceImpl{
-		NodeService: mockNs,
+		NodeService:  mockNs,
+		EventService: mockEs,
+		OTAService:   mockOs,
 	}
 	namespace := "namespace01"
 	name := "name"
@@ -269,3 +290,40 @@ func TestDesireDiff(t *testing.T) {
 	delta, _ := desire.Diff(report)
 	assert.Equal(t, desire.AppInfos(isSysApp), delta.AppInfos(isSysApp))
 }
+
+func TestSyncService_Delta(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	mockNs := ms.NewMockNodeService(mockObject.ctl)
+	ss := &SyncServiceImpl{
+		NodeService: mockNs,
+	}
+	node := &specV1.Node{
+		Namespace: "default",
+		Name:      "node01",
+		Desire: specV1.Desire{
+			common.DesiredSysApplications: []interface{}{
+				map[string]interface{}{"name": "sysapp01", "version": "v2"},
+			},
+		},
+		Report: specV1.Report{
+			common.DesiredSysApplications: []interface{}{
+				map[string]interface{}{"name": "sysapp01", "version": "v1"},
+			},
+		},
+	}
+	mockNs.EXPECT().Get("default", "node01").Return(node, nil)
+	delta, err := ss.Delta("default", "node01")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", delta.AppInfos(true)[0].Version)
+
+	// the node is not ready
+	mockNs.EXPECT().Get("default", "node02").Return(&specV1.Node{Name: "node02"}, nil)
+	_, err = ss.Delta("default", "node02")
+	assert.Error(t, err)
+
+	mockNs.EXPECT().Get("default", "node03").Return(nil, fmt.Errorf("error"))
+	_, err = ss.Delta("default", "node03")
+	assert.Error(t, err)
+}